@@ -0,0 +1,76 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	apb "github.com/grafeas/grafeas/proto/v1beta1/attestation_go_proto"
+	cpb "github.com/grafeas/grafeas/proto/v1beta1/common_go_proto"
+)
+
+func attestationNoteToV1(a *apb.Authority) *v1pb.AttestationNote {
+	if a == nil {
+		return nil
+	}
+	out := &v1pb.AttestationNote{}
+	if a.Hint != nil {
+		out.Hint = &v1pb.AttestationNote_Hint{HumanReadableName: a.Hint.HumanReadableName}
+	}
+	return out
+}
+
+func attestationNoteToV1Beta1(a *v1pb.AttestationNote) *apb.Authority {
+	if a == nil {
+		return nil
+	}
+	out := &apb.Authority{}
+	if a.Hint != nil {
+		out.Hint = &apb.Authority_Hint{HumanReadableName: a.Hint.HumanReadableName}
+	}
+	return out
+}
+
+func attestationOccurrenceToV1(d *apb.Details) *v1pb.AttestationOccurrence {
+	if d == nil {
+		return nil
+	}
+	out := &v1pb.AttestationOccurrence{}
+	switch s := d.GetAttestation().GetSignature().(type) {
+	case *apb.Attestation_PgpSignedAttestation:
+		out.Signatures = []*v1pb.Signature{{
+			Signature:   []byte(s.PgpSignedAttestation.GetSignature()),
+			PublicKeyId: s.PgpSignedAttestation.GetPgpKeyId(),
+		}}
+	case *apb.Attestation_GenericSignedAttestation:
+		out.SerializedPayload = s.GenericSignedAttestation.GetSerializedPayload()
+		for _, sig := range s.GenericSignedAttestation.GetSignatures() {
+			out.Signatures = append(out.Signatures, &v1pb.Signature{Signature: sig.Signature, PublicKeyId: sig.PublicKeyId})
+		}
+	}
+	return out
+}
+
+func attestationOccurrenceToV1Beta1(a *v1pb.AttestationOccurrence) *apb.Details {
+	if a == nil {
+		return nil
+	}
+	generic := &apb.GenericSignedAttestation{SerializedPayload: a.SerializedPayload}
+	for _, sig := range a.Signatures {
+		generic.Signatures = append(generic.Signatures, &cpb.Signature{Signature: sig.Signature, PublicKeyId: sig.PublicKeyId})
+	}
+	return &apb.Details{Attestation: &apb.Attestation{
+		Signature: &apb.Attestation_GenericSignedAttestation{GenericSignedAttestation: generic},
+	}}
+}
@@ -0,0 +1,284 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	bpb "github.com/grafeas/grafeas/proto/v1beta1/build_go_proto"
+	provpb "github.com/grafeas/grafeas/proto/v1beta1/provenance_go_proto"
+	srcpb "github.com/grafeas/grafeas/proto/v1beta1/source_go_proto"
+)
+
+func buildNoteToV1(b *bpb.Build) *v1pb.BuildNote {
+	if b == nil {
+		return nil
+	}
+	out := &v1pb.BuildNote{BuilderVersion: b.BuilderVersion}
+	if s := b.Signature; s != nil {
+		out.Signature = &v1pb.BuildSignature{
+			PublicKey: s.PublicKey,
+			Signature: s.Signature,
+			KeyId:     s.KeyId,
+			KeyType:   v1pb.BuildSignature_KeyType(s.KeyType),
+		}
+	}
+	return out
+}
+
+func buildNoteToV1Beta1(b *v1pb.BuildNote) *bpb.Build {
+	if b == nil {
+		return nil
+	}
+	out := &bpb.Build{BuilderVersion: b.BuilderVersion}
+	if s := b.Signature; s != nil {
+		out.Signature = &bpb.BuildSignature{
+			PublicKey: s.PublicKey,
+			Signature: s.Signature,
+			KeyId:     s.KeyId,
+			KeyType:   bpb.BuildSignature_KeyType(s.KeyType),
+		}
+	}
+	return out
+}
+
+func buildOccurrenceToV1(d *bpb.Details) *v1pb.BuildOccurrence {
+	if d == nil {
+		return nil
+	}
+	return &v1pb.BuildOccurrence{
+		Provenance:      provenanceToV1(d.Provenance),
+		ProvenanceBytes: d.ProvenanceBytes,
+	}
+}
+
+func buildOccurrenceToV1Beta1(b *v1pb.BuildOccurrence) *bpb.Details {
+	if b == nil {
+		return nil
+	}
+	return &bpb.Details{
+		Provenance:      provenanceToV1Beta1(b.Provenance),
+		ProvenanceBytes: b.ProvenanceBytes,
+	}
+}
+
+func provenanceToV1(p *provpb.BuildProvenance) *v1pb.BuildProvenance {
+	if p == nil {
+		return nil
+	}
+	out := &v1pb.BuildProvenance{
+		Id:             p.Id,
+		ProjectId:      p.ProjectId,
+		CreateTime:     p.CreateTime,
+		StartTime:      p.StartTime,
+		EndTime:        p.EndTime,
+		Creator:        p.Creator,
+		LogsUri:        p.LogsUri,
+		TriggerId:      p.TriggerId,
+		BuildOptions:   p.BuildOptions,
+		BuilderVersion: p.BuilderVersion,
+	}
+	for _, c := range p.Commands {
+		out.Commands = append(out.Commands, &v1pb.Command{
+			Name:    c.Name,
+			Env:     c.Env,
+			Args:    c.Args,
+			Dir:     c.Dir,
+			Id:      c.Id,
+			WaitFor: c.WaitFor,
+		})
+	}
+	for _, a := range p.BuiltArtifacts {
+		out.BuiltArtifacts = append(out.BuiltArtifacts, &v1pb.Artifact{Checksum: a.Checksum, Id: a.Id, Names: a.Names})
+	}
+	if s := p.SourceProvenance; s != nil {
+		out.SourceProvenance = &v1pb.Source{
+			ArtifactStorageSourceUri: s.ArtifactStorageSourceUri,
+			Context:                  sourceContextToV1(s.Context),
+		}
+		for _, c := range s.AdditionalContexts {
+			out.SourceProvenance.AdditionalContexts = append(out.SourceProvenance.AdditionalContexts, sourceContextToV1(c))
+		}
+		if s.FileHashes != nil {
+			out.SourceProvenance.FileHashes = map[string]*v1pb.FileHashes{}
+			for f, hashes := range s.FileHashes {
+				fh := &v1pb.FileHashes{}
+				for _, h := range hashes.GetFileHash() {
+					fh.FileHash = append(fh.FileHash, &v1pb.Hash{Type: v1pb.Hash_HashType(h.Type), Value: h.Value})
+				}
+				out.SourceProvenance.FileHashes[f] = fh
+			}
+		}
+	}
+	return out
+}
+
+func provenanceToV1Beta1(p *v1pb.BuildProvenance) *provpb.BuildProvenance {
+	if p == nil {
+		return nil
+	}
+	out := &provpb.BuildProvenance{
+		Id:             p.Id,
+		ProjectId:      p.ProjectId,
+		CreateTime:     p.CreateTime,
+		StartTime:      p.StartTime,
+		EndTime:        p.EndTime,
+		Creator:        p.Creator,
+		LogsUri:        p.LogsUri,
+		TriggerId:      p.TriggerId,
+		BuildOptions:   p.BuildOptions,
+		BuilderVersion: p.BuilderVersion,
+	}
+	for _, c := range p.Commands {
+		out.Commands = append(out.Commands, &provpb.Command{
+			Name:    c.Name,
+			Env:     c.Env,
+			Args:    c.Args,
+			Dir:     c.Dir,
+			Id:      c.Id,
+			WaitFor: c.WaitFor,
+		})
+	}
+	for _, a := range p.BuiltArtifacts {
+		out.BuiltArtifacts = append(out.BuiltArtifacts, &provpb.Artifact{Checksum: a.Checksum, Id: a.Id, Names: a.Names})
+	}
+	if s := p.SourceProvenance; s != nil {
+		out.SourceProvenance = &provpb.Source{
+			ArtifactStorageSourceUri: s.ArtifactStorageSourceUri,
+			Context:                  sourceContextToV1Beta1(s.Context),
+		}
+		for _, c := range s.AdditionalContexts {
+			out.SourceProvenance.AdditionalContexts = append(out.SourceProvenance.AdditionalContexts, sourceContextToV1Beta1(c))
+		}
+		if s.FileHashes != nil {
+			out.SourceProvenance.FileHashes = map[string]*provpb.FileHashes{}
+			for f, hashes := range s.FileHashes {
+				fh := &provpb.FileHashes{}
+				for _, h := range hashes.GetFileHash() {
+					fh.FileHash = append(fh.FileHash, &provpb.Hash{Type: provpb.Hash_HashType(h.Type), Value: h.Value})
+				}
+				out.SourceProvenance.FileHashes[f] = fh
+			}
+		}
+	}
+	return out
+}
+
+func sourceContextToV1(c *srcpb.SourceContext) *v1pb.SourceContext {
+	if c == nil {
+		return nil
+	}
+	out := &v1pb.SourceContext{Labels: c.Labels}
+	switch t := c.Context.(type) {
+	case *srcpb.SourceContext_CloudRepo:
+		repo := &v1pb.CloudRepoSourceContext{RepoId: repoIDToV1(t.CloudRepo.RepoId)}
+		switch r := t.CloudRepo.Revision.(type) {
+		case *srcpb.CloudRepoSourceContext_RevisionId:
+			repo.Revision = &v1pb.CloudRepoSourceContext_RevisionId{RevisionId: r.RevisionId}
+		case *srcpb.CloudRepoSourceContext_AliasContext:
+			repo.Revision = &v1pb.CloudRepoSourceContext_AliasContext{AliasContext: aliasContextToV1(r.AliasContext)}
+		}
+		out.Context = &v1pb.SourceContext_CloudRepo{CloudRepo: repo}
+	case *srcpb.SourceContext_Gerrit:
+		gerrit := &v1pb.GerritSourceContext{HostUri: t.Gerrit.HostUri, GerritProject: t.Gerrit.GerritProject}
+		switch r := t.Gerrit.Revision.(type) {
+		case *srcpb.GerritSourceContext_RevisionId:
+			gerrit.Revision = &v1pb.GerritSourceContext_RevisionId{RevisionId: r.RevisionId}
+		case *srcpb.GerritSourceContext_AliasContext:
+			gerrit.Revision = &v1pb.GerritSourceContext_AliasContext{AliasContext: aliasContextToV1(r.AliasContext)}
+		}
+		out.Context = &v1pb.SourceContext_Gerrit{Gerrit: gerrit}
+	case *srcpb.SourceContext_Git:
+		out.Context = &v1pb.SourceContext_Git{Git: &v1pb.GitSourceContext{Url: t.Git.Url, RevisionId: t.Git.RevisionId}}
+	}
+	return out
+}
+
+func sourceContextToV1Beta1(c *v1pb.SourceContext) *srcpb.SourceContext {
+	if c == nil {
+		return nil
+	}
+	out := &srcpb.SourceContext{Labels: c.Labels}
+	switch t := c.Context.(type) {
+	case *v1pb.SourceContext_CloudRepo:
+		repo := &srcpb.CloudRepoSourceContext{RepoId: repoIDToV1Beta1(t.CloudRepo.RepoId)}
+		switch r := t.CloudRepo.Revision.(type) {
+		case *v1pb.CloudRepoSourceContext_RevisionId:
+			repo.Revision = &srcpb.CloudRepoSourceContext_RevisionId{RevisionId: r.RevisionId}
+		case *v1pb.CloudRepoSourceContext_AliasContext:
+			repo.Revision = &srcpb.CloudRepoSourceContext_AliasContext{AliasContext: aliasContextToV1Beta1(r.AliasContext)}
+		}
+		out.Context = &srcpb.SourceContext_CloudRepo{CloudRepo: repo}
+	case *v1pb.SourceContext_Gerrit:
+		gerrit := &srcpb.GerritSourceContext{HostUri: t.Gerrit.HostUri, GerritProject: t.Gerrit.GerritProject}
+		switch r := t.Gerrit.Revision.(type) {
+		case *v1pb.GerritSourceContext_RevisionId:
+			gerrit.Revision = &srcpb.GerritSourceContext_RevisionId{RevisionId: r.RevisionId}
+		case *v1pb.GerritSourceContext_AliasContext:
+			gerrit.Revision = &srcpb.GerritSourceContext_AliasContext{AliasContext: aliasContextToV1Beta1(r.AliasContext)}
+		}
+		out.Context = &srcpb.SourceContext_Gerrit{Gerrit: gerrit}
+	case *v1pb.SourceContext_Git:
+		out.Context = &srcpb.SourceContext_Git{Git: &srcpb.GitSourceContext{Url: t.Git.Url, RevisionId: t.Git.RevisionId}}
+	}
+	return out
+}
+
+func repoIDToV1(r *srcpb.RepoId) *v1pb.RepoId {
+	if r == nil {
+		return nil
+	}
+	out := &v1pb.RepoId{}
+	switch t := r.Id.(type) {
+	case *srcpb.RepoId_ProjectRepoId:
+		out.Id = &v1pb.RepoId_ProjectRepoId{ProjectRepoId: &v1pb.ProjectRepoId{
+			ProjectId: t.ProjectRepoId.GetProjectId(),
+			RepoName:  t.ProjectRepoId.GetRepoName(),
+		}}
+	case *srcpb.RepoId_Uid:
+		out.Id = &v1pb.RepoId_Uid{Uid: t.Uid}
+	}
+	return out
+}
+
+func repoIDToV1Beta1(r *v1pb.RepoId) *srcpb.RepoId {
+	if r == nil {
+		return nil
+	}
+	out := &srcpb.RepoId{}
+	switch t := r.Id.(type) {
+	case *v1pb.RepoId_ProjectRepoId:
+		out.Id = &srcpb.RepoId_ProjectRepoId{ProjectRepoId: &srcpb.ProjectRepoId{
+			ProjectId: t.ProjectRepoId.GetProjectId(),
+			RepoName:  t.ProjectRepoId.GetRepoName(),
+		}}
+	case *v1pb.RepoId_Uid:
+		out.Id = &srcpb.RepoId_Uid{Uid: t.Uid}
+	}
+	return out
+}
+
+func aliasContextToV1(a *srcpb.AliasContext) *v1pb.AliasContext {
+	if a == nil {
+		return nil
+	}
+	return &v1pb.AliasContext{Kind: v1pb.AliasContext_Kind(a.Kind), Name: a.Name}
+}
+
+func aliasContextToV1Beta1(a *v1pb.AliasContext) *srcpb.AliasContext {
+	if a == nil {
+		return nil
+	}
+	return &srcpb.AliasContext{Kind: srcpb.AliasContext_Kind(a.Kind), Name: a.Name}
+}
@@ -0,0 +1,230 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package convert translates notes and occurrences between the v1 and v1beta1 Grafeas APIs.
+//
+// The conversions are total: every message of one version has a counterpart in the other. A few
+// fields exist in only one of the versions and are dropped when converting to the other:
+//   - v1beta1 Resource name and content hash; only its URI is kept, as v1 resource_uri.
+//   - v1beta1 vulnerability max_affected_version and package issue severity_name.
+//   - v1beta1 discovered last_analysis_time.
+//   - v1beta1 attestation content types. PGP signed attestations become a v1 signature whose
+//     public key ID is the PGP key ID, and convert back as generic signed attestations.
+//   - v1 Version full_name.
+//   - v1 fix_available, which is derived from the fixed version when converting to v1.
+//
+// Converted messages may share timestamps, statuses and repeated scalar fields with their source.
+package convert
+
+import (
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	cpb "github.com/grafeas/grafeas/proto/v1beta1/common_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	pkgpb "github.com/grafeas/grafeas/proto/v1beta1/package_go_proto"
+)
+
+// NoteToV1 converts a v1beta1 note to v1.
+func NoteToV1(n *gpb.Note) *v1pb.Note {
+	if n == nil {
+		return nil
+	}
+	out := &v1pb.Note{
+		Name:             n.Name,
+		ShortDescription: n.ShortDescription,
+		LongDescription:  n.LongDescription,
+		Kind:             v1pb.NoteKind(n.Kind),
+		RelatedUrl:       relatedURLsToV1(n.RelatedUrl),
+		ExpirationTime:   n.ExpirationTime,
+		CreateTime:       n.CreateTime,
+		UpdateTime:       n.UpdateTime,
+		RelatedNoteNames: n.RelatedNoteNames,
+	}
+	switch t := n.Type.(type) {
+	case *gpb.Note_Vulnerability:
+		out.Type = &v1pb.Note_Vulnerability{Vulnerability: vulnerabilityNoteToV1(t.Vulnerability)}
+	case *gpb.Note_Build:
+		out.Type = &v1pb.Note_Build{Build: buildNoteToV1(t.Build)}
+	case *gpb.Note_BaseImage:
+		out.Type = &v1pb.Note_Image{Image: imageNoteToV1(t.BaseImage)}
+	case *gpb.Note_Package:
+		out.Type = &v1pb.Note_Package{Package: packageNoteToV1(t.Package)}
+	case *gpb.Note_Deployable:
+		out.Type = &v1pb.Note_Deployment{Deployment: deploymentNoteToV1(t.Deployable)}
+	case *gpb.Note_Discovery:
+		out.Type = &v1pb.Note_Discovery{Discovery: discoveryNoteToV1(t.Discovery)}
+	case *gpb.Note_AttestationAuthority:
+		out.Type = &v1pb.Note_Attestation{Attestation: attestationNoteToV1(t.AttestationAuthority)}
+	}
+	return out
+}
+
+// NoteToV1Beta1 converts a v1 note to v1beta1.
+func NoteToV1Beta1(n *v1pb.Note) *gpb.Note {
+	if n == nil {
+		return nil
+	}
+	out := &gpb.Note{
+		Name:             n.Name,
+		ShortDescription: n.ShortDescription,
+		LongDescription:  n.LongDescription,
+		Kind:             cpb.NoteKind(n.Kind),
+		RelatedUrl:       relatedURLsToV1Beta1(n.RelatedUrl),
+		ExpirationTime:   n.ExpirationTime,
+		CreateTime:       n.CreateTime,
+		UpdateTime:       n.UpdateTime,
+		RelatedNoteNames: n.RelatedNoteNames,
+	}
+	switch t := n.Type.(type) {
+	case *v1pb.Note_Vulnerability:
+		out.Type = &gpb.Note_Vulnerability{Vulnerability: vulnerabilityNoteToV1Beta1(t.Vulnerability)}
+	case *v1pb.Note_Build:
+		out.Type = &gpb.Note_Build{Build: buildNoteToV1Beta1(t.Build)}
+	case *v1pb.Note_Image:
+		out.Type = &gpb.Note_BaseImage{BaseImage: imageNoteToV1Beta1(t.Image)}
+	case *v1pb.Note_Package:
+		out.Type = &gpb.Note_Package{Package: packageNoteToV1Beta1(t.Package)}
+	case *v1pb.Note_Deployment:
+		out.Type = &gpb.Note_Deployable{Deployable: deploymentNoteToV1Beta1(t.Deployment)}
+	case *v1pb.Note_Discovery:
+		out.Type = &gpb.Note_Discovery{Discovery: discoveryNoteToV1Beta1(t.Discovery)}
+	case *v1pb.Note_Attestation:
+		out.Type = &gpb.Note_AttestationAuthority{AttestationAuthority: attestationNoteToV1Beta1(t.Attestation)}
+	}
+	return out
+}
+
+// OccurrenceToV1 converts a v1beta1 occurrence to v1.
+func OccurrenceToV1(o *gpb.Occurrence) *v1pb.Occurrence {
+	if o == nil {
+		return nil
+	}
+	out := &v1pb.Occurrence{
+		Name:        o.Name,
+		ResourceUri: o.GetResource().GetUri(),
+		NoteName:    o.NoteName,
+		Kind:        v1pb.NoteKind(o.Kind),
+		Remediation: o.Remediation,
+		CreateTime:  o.CreateTime,
+		UpdateTime:  o.UpdateTime,
+	}
+	switch d := o.Details.(type) {
+	case *gpb.Occurrence_Vulnerability:
+		out.Details = &v1pb.Occurrence_Vulnerability{Vulnerability: vulnerabilityOccurrenceToV1(d.Vulnerability)}
+	case *gpb.Occurrence_Build:
+		out.Details = &v1pb.Occurrence_Build{Build: buildOccurrenceToV1(d.Build)}
+	case *gpb.Occurrence_DerivedImage:
+		out.Details = &v1pb.Occurrence_Image{Image: imageOccurrenceToV1(d.DerivedImage)}
+	case *gpb.Occurrence_Installation:
+		out.Details = &v1pb.Occurrence_Package{Package: packageOccurrenceToV1(d.Installation)}
+	case *gpb.Occurrence_Deployment:
+		out.Details = &v1pb.Occurrence_Deployment{Deployment: deploymentOccurrenceToV1(d.Deployment)}
+	case *gpb.Occurrence_Discovered:
+		out.Details = &v1pb.Occurrence_Discovery{Discovery: discoveryOccurrenceToV1(d.Discovered)}
+	case *gpb.Occurrence_Attestation:
+		out.Details = &v1pb.Occurrence_Attestation{Attestation: attestationOccurrenceToV1(d.Attestation)}
+	}
+	return out
+}
+
+// OccurrenceToV1Beta1 converts a v1 occurrence to v1beta1.
+func OccurrenceToV1Beta1(o *v1pb.Occurrence) *gpb.Occurrence {
+	if o == nil {
+		return nil
+	}
+	out := &gpb.Occurrence{
+		Name:        o.Name,
+		NoteName:    o.NoteName,
+		Kind:        cpb.NoteKind(o.Kind),
+		Remediation: o.Remediation,
+		CreateTime:  o.CreateTime,
+		UpdateTime:  o.UpdateTime,
+	}
+	if o.ResourceUri != "" {
+		out.Resource = &gpb.Resource{Uri: o.ResourceUri}
+	}
+	switch d := o.Details.(type) {
+	case *v1pb.Occurrence_Vulnerability:
+		out.Details = &gpb.Occurrence_Vulnerability{Vulnerability: vulnerabilityOccurrenceToV1Beta1(d.Vulnerability)}
+	case *v1pb.Occurrence_Build:
+		out.Details = &gpb.Occurrence_Build{Build: buildOccurrenceToV1Beta1(d.Build)}
+	case *v1pb.Occurrence_Image:
+		out.Details = &gpb.Occurrence_DerivedImage{DerivedImage: imageOccurrenceToV1Beta1(d.Image)}
+	case *v1pb.Occurrence_Package:
+		out.Details = &gpb.Occurrence_Installation{Installation: packageOccurrenceToV1Beta1(d.Package)}
+	case *v1pb.Occurrence_Deployment:
+		out.Details = &gpb.Occurrence_Deployment{Deployment: deploymentOccurrenceToV1Beta1(d.Deployment)}
+	case *v1pb.Occurrence_Discovery:
+		out.Details = &gpb.Occurrence_Discovered{Discovered: discoveryOccurrenceToV1Beta1(d.Discovery)}
+	case *v1pb.Occurrence_Attestation:
+		out.Details = &gpb.Occurrence_Attestation{Attestation: attestationOccurrenceToV1Beta1(d.Attestation)}
+	}
+	return out
+}
+
+// VulnerabilityOccurrencesSummaryToV1 converts a v1beta1 vulnerability occurrences summary to v1.
+func VulnerabilityOccurrencesSummaryToV1(s *gpb.VulnerabilityOccurrencesSummary) *v1pb.VulnerabilityOccurrencesSummary {
+	if s == nil {
+		return nil
+	}
+	out := &v1pb.VulnerabilityOccurrencesSummary{}
+	for _, c := range s.Counts {
+		out.Counts = append(out.Counts, &v1pb.VulnerabilityOccurrencesSummary_FixableTotalByDigest{
+			ResourceUri:  c.GetResource().GetUri(),
+			Severity:     v1pb.Severity(c.Severity),
+			FixableCount: c.FixableCount,
+			TotalCount:   c.TotalCount,
+		})
+	}
+	return out
+}
+
+func relatedURLsToV1(urls []*cpb.RelatedUrl) []*v1pb.RelatedUrl {
+	var out []*v1pb.RelatedUrl
+	for _, u := range urls {
+		out = append(out, &v1pb.RelatedUrl{Url: u.Url, Label: u.Label})
+	}
+	return out
+}
+
+func relatedURLsToV1Beta1(urls []*v1pb.RelatedUrl) []*cpb.RelatedUrl {
+	var out []*cpb.RelatedUrl
+	for _, u := range urls {
+		out = append(out, &cpb.RelatedUrl{Url: u.Url, Label: u.Label})
+	}
+	return out
+}
+
+func versionToV1(v *pkgpb.Version) *v1pb.Version {
+	if v == nil {
+		return nil
+	}
+	return &v1pb.Version{
+		Epoch:    v.Epoch,
+		Name:     v.Name,
+		Revision: v.Revision,
+		Kind:     v1pb.Version_VersionKind(v.Kind),
+	}
+}
+
+func versionToV1Beta1(v *v1pb.Version) *pkgpb.Version {
+	if v == nil {
+		return nil
+	}
+	return &pkgpb.Version{
+		Epoch:    v.Epoch,
+		Name:     v.Name,
+		Revision: v.Revision,
+		Kind:     pkgpb.Version_VersionKind(v.Kind),
+	}
+}
@@ -0,0 +1,719 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	tpb "github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/google/go-cmp/cmp"
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	apb "github.com/grafeas/grafeas/proto/v1beta1/attestation_go_proto"
+	bpb "github.com/grafeas/grafeas/proto/v1beta1/build_go_proto"
+	cpb "github.com/grafeas/grafeas/proto/v1beta1/common_go_proto"
+	cvsspb "github.com/grafeas/grafeas/proto/v1beta1/cvss_go_proto"
+	deploymentpb "github.com/grafeas/grafeas/proto/v1beta1/deployment_go_proto"
+	discoverypb "github.com/grafeas/grafeas/proto/v1beta1/discovery_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	ipb "github.com/grafeas/grafeas/proto/v1beta1/image_go_proto"
+	pkgpb "github.com/grafeas/grafeas/proto/v1beta1/package_go_proto"
+	provpb "github.com/grafeas/grafeas/proto/v1beta1/provenance_go_proto"
+	srcpb "github.com/grafeas/grafeas/proto/v1beta1/source_go_proto"
+	vpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+)
+
+const (
+	imageURI  = "https://gcr.io/consumer1/debian@sha256:dbc96ed51bc598faeec0901bad307ebb5d1d7259b33e2d7d7296c28f439dc777"
+	debianCPE = "cpe:/o:debian:debian_linux:9"
+)
+
+var (
+	createTime = &tpb.Timestamp{Seconds: 1560000000}
+	updateTime = &tpb.Timestamp{Seconds: 1560000100}
+)
+
+func v1beta1Version(name string, kind pkgpb.Version_VersionKind) *pkgpb.Version {
+	return &pkgpb.Version{Epoch: 1, Name: name, Revision: "1", Kind: kind}
+}
+
+func v1Version(name string, kind v1pb.Version_VersionKind) *v1pb.Version {
+	return &v1pb.Version{Epoch: 1, Name: name, Revision: "1", Kind: kind}
+}
+
+// v1beta1Note returns a note of the specified kind with all the common fields set.
+func v1beta1Note(kind cpb.NoteKind) *gpb.Note {
+	return &gpb.Note{
+		Name:             "projects/goog-vulnz/notes/" + kind.String(),
+		ShortDescription: "short",
+		LongDescription:  "long",
+		Kind:             kind,
+		RelatedUrl:       []*cpb.RelatedUrl{{Url: "https://grafeas.io", Label: "home"}},
+		ExpirationTime:   updateTime,
+		CreateTime:       createTime,
+		UpdateTime:       updateTime,
+		RelatedNoteNames: []string{"projects/goog-vulnz/notes/other"},
+	}
+}
+
+// v1Note returns the v1 counterpart of v1beta1Note(kind).
+func v1Note(kind v1pb.NoteKind) *v1pb.Note {
+	return &v1pb.Note{
+		Name:             "projects/goog-vulnz/notes/" + kind.String(),
+		ShortDescription: "short",
+		LongDescription:  "long",
+		Kind:             kind,
+		RelatedUrl:       []*v1pb.RelatedUrl{{Url: "https://grafeas.io", Label: "home"}},
+		ExpirationTime:   updateTime,
+		CreateTime:       createTime,
+		UpdateTime:       updateTime,
+		RelatedNoteNames: []string{"projects/goog-vulnz/notes/other"},
+	}
+}
+
+// v1beta1Occurrence returns an occurrence of the specified kind with all the common fields set.
+func v1beta1Occurrence(kind cpb.NoteKind) *gpb.Occurrence {
+	return &gpb.Occurrence{
+		Name:        "projects/consumer1/occurrences/" + kind.String(),
+		Resource:    &gpb.Resource{Uri: imageURI},
+		NoteName:    "projects/goog-vulnz/notes/" + kind.String(),
+		Kind:        kind,
+		Remediation: "upgrade",
+		CreateTime:  createTime,
+		UpdateTime:  updateTime,
+	}
+}
+
+// v1Occurrence returns the v1 counterpart of v1beta1Occurrence(kind).
+func v1Occurrence(kind v1pb.NoteKind) *v1pb.Occurrence {
+	return &v1pb.Occurrence{
+		Name:        "projects/consumer1/occurrences/" + kind.String(),
+		ResourceUri: imageURI,
+		NoteName:    "projects/goog-vulnz/notes/" + kind.String(),
+		Kind:        kind,
+		Remediation: "upgrade",
+		CreateTime:  createTime,
+		UpdateTime:  updateTime,
+	}
+}
+
+func TestNotes(t *testing.T) {
+	tests := []struct {
+		desc    string
+		v1beta1 func(n *gpb.Note)
+		v1      func(n *v1pb.Note)
+		kind    cpb.NoteKind
+	}{
+		{
+			desc: "vulnerability",
+			kind: cpb.NoteKind_VULNERABILITY,
+			v1beta1: func(n *gpb.Note) {
+				n.Type = &gpb.Note_Vulnerability{Vulnerability: &vpb.Vulnerability{
+					CvssScore: 7.5,
+					Severity:  vpb.Severity_HIGH,
+					Details: []*vpb.Vulnerability_Detail{
+						{
+							CpeUri:             debianCPE,
+							Package:            "icu",
+							MinAffectedVersion: v1beta1Version("52.1", pkgpb.Version_NORMAL),
+							SeverityName:       "HIGH",
+							Description:        "desc",
+							FixedLocation: &vpb.VulnerabilityLocation{
+								CpeUri:  debianCPE,
+								Package: "icu",
+								Version: v1beta1Version("55.1", pkgpb.Version_NORMAL),
+							},
+							PackageType: "dpkg",
+							IsObsolete:  true,
+						},
+						// Details without a fix have no fixed location.
+						{CpeUri: debianCPE, Package: "libc"},
+					},
+					CvssV3: &cvsspb.CVSSv3{
+						BaseScore:             7.5,
+						ExploitabilityScore:   3.9,
+						ImpactScore:           3.6,
+						AttackVector:          cvsspb.CVSSv3_ATTACK_VECTOR_NETWORK,
+						AttackComplexity:      cvsspb.CVSSv3_ATTACK_COMPLEXITY_LOW,
+						PrivilegesRequired:    cvsspb.CVSSv3_PRIVILEGES_REQUIRED_NONE,
+						UserInteraction:       cvsspb.CVSSv3_USER_INTERACTION_REQUIRED,
+						Scope:                 cvsspb.CVSSv3_SCOPE_CHANGED,
+						ConfidentialityImpact: cvsspb.CVSSv3_IMPACT_HIGH,
+						IntegrityImpact:       cvsspb.CVSSv3_IMPACT_LOW,
+						AvailabilityImpact:    cvsspb.CVSSv3_IMPACT_NONE,
+					},
+					WindowsDetails: []*vpb.Vulnerability_WindowsDetail{{
+						CpeUri:      "cpe:/o:microsoft:windows_10",
+						Name:        "KB",
+						Description: "windows",
+						FixingKbs:   []*vpb.Vulnerability_WindowsDetail_KnowledgeBase{{Name: "KB1", Url: "https://support.microsoft.com"}},
+					}},
+				}}
+			},
+			v1: func(n *v1pb.Note) {
+				n.Type = &v1pb.Note_Vulnerability{Vulnerability: &v1pb.VulnerabilityNote{
+					CvssScore: 7.5,
+					Severity:  v1pb.Severity_HIGH,
+					Details: []*v1pb.VulnerabilityNote_Detail{
+						{
+							SeverityName:       "HIGH",
+							Description:        "desc",
+							PackageType:        "dpkg",
+							AffectedCpeUri:     debianCPE,
+							AffectedPackage:    "icu",
+							MinAffectedVersion: v1Version("52.1", v1pb.Version_NORMAL),
+							FixedCpeUri:        debianCPE,
+							FixedPackage:       "icu",
+							FixedVersion:       v1Version("55.1", v1pb.Version_NORMAL),
+							IsObsolete:         true,
+						},
+						{AffectedCpeUri: debianCPE, AffectedPackage: "libc"},
+					},
+					CvssV3: &v1pb.CVSSv3{
+						BaseScore:             7.5,
+						ExploitabilityScore:   3.9,
+						ImpactScore:           3.6,
+						AttackVector:          v1pb.CVSSv3_ATTACK_VECTOR_NETWORK,
+						AttackComplexity:      v1pb.CVSSv3_ATTACK_COMPLEXITY_LOW,
+						PrivilegesRequired:    v1pb.CVSSv3_PRIVILEGES_REQUIRED_NONE,
+						UserInteraction:       v1pb.CVSSv3_USER_INTERACTION_REQUIRED,
+						Scope:                 v1pb.CVSSv3_SCOPE_CHANGED,
+						ConfidentialityImpact: v1pb.CVSSv3_IMPACT_HIGH,
+						IntegrityImpact:       v1pb.CVSSv3_IMPACT_LOW,
+						AvailabilityImpact:    v1pb.CVSSv3_IMPACT_NONE,
+					},
+					WindowsDetails: []*v1pb.VulnerabilityNote_WindowsDetail{{
+						CpeUri:      "cpe:/o:microsoft:windows_10",
+						Name:        "KB",
+						Description: "windows",
+						FixingKbs:   []*v1pb.VulnerabilityNote_WindowsDetail_KnowledgeBase{{Name: "KB1", Url: "https://support.microsoft.com"}},
+					}},
+				}}
+			},
+		},
+		{
+			desc: "build",
+			kind: cpb.NoteKind_BUILD,
+			v1beta1: func(n *gpb.Note) {
+				n.Type = &gpb.Note_Build{Build: &bpb.Build{
+					BuilderVersion: "v1",
+					Signature: &bpb.BuildSignature{
+						PublicKey: "key",
+						Signature: []byte("sig"),
+						KeyId:     "id",
+						KeyType:   bpb.BuildSignature_PGP_ASCII_ARMORED,
+					},
+				}}
+			},
+			v1: func(n *v1pb.Note) {
+				n.Type = &v1pb.Note_Build{Build: &v1pb.BuildNote{
+					BuilderVersion: "v1",
+					Signature: &v1pb.BuildSignature{
+						PublicKey: "key",
+						Signature: []byte("sig"),
+						KeyId:     "id",
+						KeyType:   v1pb.BuildSignature_PGP_ASCII_ARMORED,
+					},
+				}}
+			},
+		},
+		{
+			desc: "image",
+			kind: cpb.NoteKind_IMAGE,
+			v1beta1: func(n *gpb.Note) {
+				n.Type = &gpb.Note_BaseImage{BaseImage: &ipb.Basis{
+					ResourceUrl: imageURI,
+					Fingerprint: &ipb.Fingerprint{V1Name: "v1", V2Blob: []string{"blob"}, V2Name: "v2"},
+				}}
+			},
+			v1: func(n *v1pb.Note) {
+				n.Type = &v1pb.Note_Image{Image: &v1pb.ImageNote{
+					ResourceUrl: imageURI,
+					Fingerprint: &v1pb.Fingerprint{V1Name: "v1", V2Blob: []string{"blob"}, V2Name: "v2"},
+				}}
+			},
+		},
+		{
+			desc: "package",
+			kind: cpb.NoteKind_PACKAGE,
+			v1beta1: func(n *gpb.Note) {
+				n.Type = &gpb.Note_Package{Package: &pkgpb.Package{
+					Name: "icu",
+					Distribution: []*pkgpb.Distribution{{
+						CpeUri:        debianCPE,
+						Architecture:  pkgpb.Architecture_X64,
+						LatestVersion: v1beta1Version("55.1", pkgpb.Version_NORMAL),
+						Maintainer:    "maintainer",
+						Url:           "https://debian.org",
+						Description:   "icu",
+					}},
+				}}
+			},
+			v1: func(n *v1pb.Note) {
+				n.Type = &v1pb.Note_Package{Package: &v1pb.PackageNote{
+					Name: "icu",
+					Distribution: []*v1pb.Distribution{{
+						CpeUri:        debianCPE,
+						Architecture:  v1pb.Architecture_X64,
+						LatestVersion: v1Version("55.1", v1pb.Version_NORMAL),
+						Maintainer:    "maintainer",
+						Url:           "https://debian.org",
+						Description:   "icu",
+					}},
+				}}
+			},
+		},
+		{
+			desc: "deployment",
+			kind: cpb.NoteKind_DEPLOYMENT,
+			v1beta1: func(n *gpb.Note) {
+				n.Type = &gpb.Note_Deployable{Deployable: &deploymentpb.Deployable{ResourceUri: []string{imageURI}}}
+			},
+			v1: func(n *v1pb.Note) {
+				n.Type = &v1pb.Note_Deployment{Deployment: &v1pb.DeploymentNote{ResourceUri: []string{imageURI}}}
+			},
+		},
+		{
+			desc: "discovery",
+			kind: cpb.NoteKind_DISCOVERY,
+			v1beta1: func(n *gpb.Note) {
+				n.Type = &gpb.Note_Discovery{Discovery: &discoverypb.Discovery{AnalysisKind: cpb.NoteKind_VULNERABILITY}}
+			},
+			v1: func(n *v1pb.Note) {
+				n.Type = &v1pb.Note_Discovery{Discovery: &v1pb.DiscoveryNote{AnalysisKind: v1pb.NoteKind_VULNERABILITY}}
+			},
+		},
+		{
+			desc: "attestation",
+			kind: cpb.NoteKind_ATTESTATION,
+			v1beta1: func(n *gpb.Note) {
+				n.Type = &gpb.Note_AttestationAuthority{AttestationAuthority: &apb.Authority{
+					Hint: &apb.Authority_Hint{HumanReadableName: "qa"},
+				}}
+			},
+			v1: func(n *v1pb.Note) {
+				n.Type = &v1pb.Note_Attestation{Attestation: &v1pb.AttestationNote{
+					Hint: &v1pb.AttestationNote_Hint{HumanReadableName: "qa"},
+				}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			beta := v1beta1Note(tt.kind)
+			tt.v1beta1(beta)
+			v1 := v1Note(v1pb.NoteKind(tt.kind))
+			tt.v1(v1)
+
+			if diff := cmp.Diff(v1, NoteToV1(beta), cmp.Comparer(proto.Equal)); diff != "" {
+				t.Errorf("NoteToV1 returned diff (want -> got):\n%s", diff)
+			}
+			if diff := cmp.Diff(beta, NoteToV1Beta1(v1), cmp.Comparer(proto.Equal)); diff != "" {
+				t.Errorf("NoteToV1Beta1 returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		desc    string
+		v1beta1 func(o *gpb.Occurrence)
+		v1      func(o *v1pb.Occurrence)
+		kind    cpb.NoteKind
+	}{
+		{
+			desc: "vulnerability",
+			kind: cpb.NoteKind_VULNERABILITY,
+			v1beta1: func(o *gpb.Occurrence) {
+				o.Details = &gpb.Occurrence_Vulnerability{Vulnerability: &vpb.Details{
+					Type:      "dpkg",
+					Severity:  vpb.Severity_HIGH,
+					CvssScore: 7.5,
+					PackageIssue: []*vpb.PackageIssue{
+						{
+							AffectedLocation: &vpb.VulnerabilityLocation{
+								CpeUri:  debianCPE,
+								Package: "icu",
+								Version: v1beta1Version("52.1", pkgpb.Version_NORMAL),
+							},
+							FixedLocation: &vpb.VulnerabilityLocation{
+								CpeUri:  debianCPE,
+								Package: "icu",
+								Version: v1beta1Version("55.1", pkgpb.Version_NORMAL),
+							},
+						},
+						// A maximum fixed version means there is no fix yet.
+						{
+							AffectedLocation: &vpb.VulnerabilityLocation{CpeUri: debianCPE, Package: "libc"},
+							FixedLocation: &vpb.VulnerabilityLocation{
+								CpeUri:  debianCPE,
+								Package: "libc",
+								Version: &pkgpb.Version{Kind: pkgpb.Version_MAXIMUM},
+							},
+						},
+					},
+					ShortDescription:  "short",
+					LongDescription:   "long",
+					RelatedUrls:       []*cpb.RelatedUrl{{Url: "https://security-tracker.debian.org", Label: "tracker"}},
+					EffectiveSeverity: vpb.Severity_LOW,
+				}}
+			},
+			v1: func(o *v1pb.Occurrence) {
+				o.Details = &v1pb.Occurrence_Vulnerability{Vulnerability: &v1pb.VulnerabilityOccurrence{
+					Type:      "dpkg",
+					Severity:  v1pb.Severity_HIGH,
+					CvssScore: 7.5,
+					PackageIssue: []*v1pb.VulnerabilityOccurrence_PackageIssue{
+						{
+							AffectedCpeUri:     debianCPE,
+							AffectedPackage:    "icu",
+							MinAffectedVersion: v1Version("52.1", v1pb.Version_NORMAL),
+							FixedCpeUri:        debianCPE,
+							FixedPackage:       "icu",
+							FixedVersion:       v1Version("55.1", v1pb.Version_NORMAL),
+							FixAvailable:       true,
+						},
+						{
+							AffectedCpeUri:  debianCPE,
+							AffectedPackage: "libc",
+							FixedCpeUri:     debianCPE,
+							FixedPackage:    "libc",
+							FixedVersion:    &v1pb.Version{Kind: v1pb.Version_MAXIMUM},
+						},
+					},
+					ShortDescription:  "short",
+					LongDescription:   "long",
+					RelatedUrls:       []*v1pb.RelatedUrl{{Url: "https://security-tracker.debian.org", Label: "tracker"}},
+					EffectiveSeverity: v1pb.Severity_LOW,
+					FixAvailable:      true,
+				}}
+			},
+		},
+		{
+			desc: "build",
+			kind: cpb.NoteKind_BUILD,
+			v1beta1: func(o *gpb.Occurrence) {
+				o.Details = &gpb.Occurrence_Build{Build: &bpb.Details{
+					Provenance: &provpb.BuildProvenance{
+						Id:        "build1",
+						ProjectId: "consumer1",
+						Commands: []*provpb.Command{{
+							Name:    "docker",
+							Env:     []string{"A=B"},
+							Args:    []string{"build", "."},
+							Dir:     "/src",
+							Id:      "step1",
+							WaitFor: []string{"step0"},
+						}},
+						BuiltArtifacts: []*provpb.Artifact{{Checksum: "sha256:123", Id: "artifact1", Names: []string{imageURI}}},
+						CreateTime:     createTime,
+						StartTime:      createTime,
+						EndTime:        updateTime,
+						Creator:        "me@example.com",
+						LogsUri:        "gs://logs",
+						SourceProvenance: &provpb.Source{
+							ArtifactStorageSourceUri: "gs://source",
+							FileHashes: map[string]*provpb.FileHashes{
+								"main.go": {FileHash: []*provpb.Hash{{Type: provpb.Hash_SHA256, Value: []byte("hash")}}},
+							},
+							Context: &srcpb.SourceContext{
+								Context: &srcpb.SourceContext_CloudRepo{CloudRepo: &srcpb.CloudRepoSourceContext{
+									RepoId: &srcpb.RepoId{Id: &srcpb.RepoId_ProjectRepoId{ProjectRepoId: &srcpb.ProjectRepoId{ProjectId: "consumer1", RepoName: "repo"}}},
+									Revision: &srcpb.CloudRepoSourceContext_AliasContext{AliasContext: &srcpb.AliasContext{
+										Kind: srcpb.AliasContext_MOVABLE,
+										Name: "master",
+									}},
+								}},
+								Labels: map[string]string{"a": "b"},
+							},
+							AdditionalContexts: []*srcpb.SourceContext{
+								{Context: &srcpb.SourceContext_CloudRepo{CloudRepo: &srcpb.CloudRepoSourceContext{
+									RepoId:   &srcpb.RepoId{Id: &srcpb.RepoId_Uid{Uid: "uid"}},
+									Revision: &srcpb.CloudRepoSourceContext_RevisionId{RevisionId: "abc"},
+								}}},
+								{Context: &srcpb.SourceContext_Gerrit{Gerrit: &srcpb.GerritSourceContext{
+									HostUri:       "https://gerrit",
+									GerritProject: "project",
+									Revision:      &srcpb.GerritSourceContext_RevisionId{RevisionId: "abc"},
+								}}},
+								{Context: &srcpb.SourceContext_Gerrit{Gerrit: &srcpb.GerritSourceContext{
+									Revision: &srcpb.GerritSourceContext_AliasContext{AliasContext: &srcpb.AliasContext{Kind: srcpb.AliasContext_FIXED}},
+								}}},
+								{Context: &srcpb.SourceContext_Git{Git: &srcpb.GitSourceContext{Url: "https://github.com/grafeas/grafeas", RevisionId: "abc"}}},
+							},
+						},
+						TriggerId:      "trigger",
+						BuildOptions:   map[string]string{"machine": "large"},
+						BuilderVersion: "v1",
+					},
+					ProvenanceBytes: "bytes",
+				}}
+			},
+			v1: func(o *v1pb.Occurrence) {
+				o.Details = &v1pb.Occurrence_Build{Build: &v1pb.BuildOccurrence{
+					Provenance: &v1pb.BuildProvenance{
+						Id:        "build1",
+						ProjectId: "consumer1",
+						Commands: []*v1pb.Command{{
+							Name:    "docker",
+							Env:     []string{"A=B"},
+							Args:    []string{"build", "."},
+							Dir:     "/src",
+							Id:      "step1",
+							WaitFor: []string{"step0"},
+						}},
+						BuiltArtifacts: []*v1pb.Artifact{{Checksum: "sha256:123", Id: "artifact1", Names: []string{imageURI}}},
+						CreateTime:     createTime,
+						StartTime:      createTime,
+						EndTime:        updateTime,
+						Creator:        "me@example.com",
+						LogsUri:        "gs://logs",
+						SourceProvenance: &v1pb.Source{
+							ArtifactStorageSourceUri: "gs://source",
+							FileHashes: map[string]*v1pb.FileHashes{
+								"main.go": {FileHash: []*v1pb.Hash{{Type: v1pb.Hash_SHA256, Value: []byte("hash")}}},
+							},
+							Context: &v1pb.SourceContext{
+								Context: &v1pb.SourceContext_CloudRepo{CloudRepo: &v1pb.CloudRepoSourceContext{
+									RepoId: &v1pb.RepoId{Id: &v1pb.RepoId_ProjectRepoId{ProjectRepoId: &v1pb.ProjectRepoId{ProjectId: "consumer1", RepoName: "repo"}}},
+									Revision: &v1pb.CloudRepoSourceContext_AliasContext{AliasContext: &v1pb.AliasContext{
+										Kind: v1pb.AliasContext_MOVABLE,
+										Name: "master",
+									}},
+								}},
+								Labels: map[string]string{"a": "b"},
+							},
+							AdditionalContexts: []*v1pb.SourceContext{
+								{Context: &v1pb.SourceContext_CloudRepo{CloudRepo: &v1pb.CloudRepoSourceContext{
+									RepoId:   &v1pb.RepoId{Id: &v1pb.RepoId_Uid{Uid: "uid"}},
+									Revision: &v1pb.CloudRepoSourceContext_RevisionId{RevisionId: "abc"},
+								}}},
+								{Context: &v1pb.SourceContext_Gerrit{Gerrit: &v1pb.GerritSourceContext{
+									HostUri:       "https://gerrit",
+									GerritProject: "project",
+									Revision:      &v1pb.GerritSourceContext_RevisionId{RevisionId: "abc"},
+								}}},
+								{Context: &v1pb.SourceContext_Gerrit{Gerrit: &v1pb.GerritSourceContext{
+									Revision: &v1pb.GerritSourceContext_AliasContext{AliasContext: &v1pb.AliasContext{Kind: v1pb.AliasContext_FIXED}},
+								}}},
+								{Context: &v1pb.SourceContext_Git{Git: &v1pb.GitSourceContext{Url: "https://github.com/grafeas/grafeas", RevisionId: "abc"}}},
+							},
+						},
+						TriggerId:      "trigger",
+						BuildOptions:   map[string]string{"machine": "large"},
+						BuilderVersion: "v1",
+					},
+					ProvenanceBytes: "bytes",
+				}}
+			},
+		},
+		{
+			desc: "image",
+			kind: cpb.NoteKind_IMAGE,
+			v1beta1: func(o *gpb.Occurrence) {
+				o.Details = &gpb.Occurrence_DerivedImage{DerivedImage: &ipb.Details{DerivedImage: &ipb.Derived{
+					Fingerprint: &ipb.Fingerprint{V1Name: "v1", V2Blob: []string{"blob"}, V2Name: "v2"},
+					Distance:    2,
+					LayerInfo: []*ipb.Layer{
+						{Directive: ipb.Layer_RUN, Arguments: "apt-get update"},
+						{Arguments: "unknown"},
+					},
+					BaseResourceUrl: imageURI,
+				}}}
+			},
+			v1: func(o *v1pb.Occurrence) {
+				o.Details = &v1pb.Occurrence_Image{Image: &v1pb.ImageOccurrence{
+					Fingerprint: &v1pb.Fingerprint{V1Name: "v1", V2Blob: []string{"blob"}, V2Name: "v2"},
+					Distance:    2,
+					LayerInfo: []*v1pb.Layer{
+						{Directive: "RUN", Arguments: "apt-get update"},
+						{Arguments: "unknown"},
+					},
+					BaseResourceUrl: imageURI,
+				}}
+			},
+		},
+		{
+			desc: "package",
+			kind: cpb.NoteKind_PACKAGE,
+			v1beta1: func(o *gpb.Occurrence) {
+				o.Details = &gpb.Occurrence_Installation{Installation: &pkgpb.Details{Installation: &pkgpb.Installation{
+					Name: "icu",
+					Location: []*pkgpb.Location{{
+						CpeUri:  debianCPE,
+						Version: v1beta1Version("52.1", pkgpb.Version_NORMAL),
+						Path:    "/usr/lib",
+					}},
+				}}}
+			},
+			v1: func(o *v1pb.Occurrence) {
+				o.Details = &v1pb.Occurrence_Package{Package: &v1pb.PackageOccurrence{
+					Name: "icu",
+					Location: []*v1pb.Location{{
+						CpeUri:  debianCPE,
+						Version: v1Version("52.1", v1pb.Version_NORMAL),
+						Path:    "/usr/lib",
+					}},
+				}}
+			},
+		},
+		{
+			desc: "deployment",
+			kind: cpb.NoteKind_DEPLOYMENT,
+			v1beta1: func(o *gpb.Occurrence) {
+				o.Details = &gpb.Occurrence_Deployment{Deployment: &deploymentpb.Details{Deployment: &deploymentpb.Deployment{
+					UserEmail:    "me@example.com",
+					DeployTime:   createTime,
+					UndeployTime: updateTime,
+					Config:       "config",
+					Address:      "10.0.0.1",
+					ResourceUri:  []string{imageURI},
+					Platform:     deploymentpb.Deployment_GKE,
+				}}}
+			},
+			v1: func(o *v1pb.Occurrence) {
+				o.Details = &v1pb.Occurrence_Deployment{Deployment: &v1pb.DeploymentOccurrence{
+					UserEmail:    "me@example.com",
+					DeployTime:   createTime,
+					UndeployTime: updateTime,
+					Config:       "config",
+					Address:      "10.0.0.1",
+					ResourceUri:  []string{imageURI},
+					Platform:     v1pb.DeploymentOccurrence_GKE,
+				}}
+			},
+		},
+		{
+			desc: "discovery",
+			kind: cpb.NoteKind_DISCOVERY,
+			v1beta1: func(o *gpb.Occurrence) {
+				o.Details = &gpb.Occurrence_Discovered{Discovered: &discoverypb.Details{Discovered: &discoverypb.Discovered{
+					ContinuousAnalysis:  discoverypb.Discovered_ACTIVE,
+					AnalysisStatus:      discoverypb.Discovered_FINISHED_FAILED,
+					AnalysisStatusError: &spb.Status{Code: 13, Message: "failed"},
+				}}}
+			},
+			v1: func(o *v1pb.Occurrence) {
+				o.Details = &v1pb.Occurrence_Discovery{Discovery: &v1pb.DiscoveryOccurrence{
+					ContinuousAnalysis:  v1pb.DiscoveryOccurrence_ACTIVE,
+					AnalysisStatus:      v1pb.DiscoveryOccurrence_FINISHED_FAILED,
+					AnalysisStatusError: &spb.Status{Code: 13, Message: "failed"},
+				}}
+			},
+		},
+		{
+			desc: "attestation",
+			kind: cpb.NoteKind_ATTESTATION,
+			v1beta1: func(o *gpb.Occurrence) {
+				o.Details = &gpb.Occurrence_Attestation{Attestation: &apb.Details{Attestation: &apb.Attestation{
+					Signature: &apb.Attestation_GenericSignedAttestation{GenericSignedAttestation: &apb.GenericSignedAttestation{
+						SerializedPayload: []byte("payload"),
+						Signatures:        []*cpb.Signature{{Signature: []byte("sig"), PublicKeyId: "key"}},
+					}},
+				}}}
+			},
+			v1: func(o *v1pb.Occurrence) {
+				o.Details = &v1pb.Occurrence_Attestation{Attestation: &v1pb.AttestationOccurrence{
+					SerializedPayload: []byte("payload"),
+					Signatures:        []*v1pb.Signature{{Signature: []byte("sig"), PublicKeyId: "key"}},
+				}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			beta := v1beta1Occurrence(tt.kind)
+			tt.v1beta1(beta)
+			v1 := v1Occurrence(v1pb.NoteKind(tt.kind))
+			tt.v1(v1)
+
+			if diff := cmp.Diff(v1, OccurrenceToV1(beta), cmp.Comparer(proto.Equal)); diff != "" {
+				t.Errorf("OccurrenceToV1 returned diff (want -> got):\n%s", diff)
+			}
+			if diff := cmp.Diff(beta, OccurrenceToV1Beta1(v1), cmp.Comparer(proto.Equal)); diff != "" {
+				t.Errorf("OccurrenceToV1Beta1 returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVulnerabilityOccurrencesSummaryToV1(t *testing.T) {
+	s := &gpb.VulnerabilityOccurrencesSummary{
+		Counts: []*gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest{
+			{Resource: &gpb.Resource{Uri: imageURI}, Severity: vpb.Severity_HIGH, FixableCount: 1, TotalCount: 2},
+		},
+	}
+	want := &v1pb.VulnerabilityOccurrencesSummary{
+		Counts: []*v1pb.VulnerabilityOccurrencesSummary_FixableTotalByDigest{
+			{ResourceUri: imageURI, Severity: v1pb.Severity_HIGH, FixableCount: 1, TotalCount: 2},
+		},
+	}
+	if diff := cmp.Diff(want, VulnerabilityOccurrencesSummaryToV1(s), cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("VulnerabilityOccurrencesSummaryToV1 returned diff (want -> got):\n%s", diff)
+	}
+}
+
+func TestDroppedFields(t *testing.T) {
+	// Fields without a counterpart in v1 are dropped.
+	beta := v1beta1Occurrence(cpb.NoteKind_ATTESTATION)
+	beta.Resource.Name = "debian"
+	beta.Resource.ContentHash = &provpb.Hash{Type: provpb.Hash_SHA256, Value: []byte("hash")}
+	beta.Details = &gpb.Occurrence_Attestation{Attestation: &apb.Details{Attestation: &apb.Attestation{
+		Signature: &apb.Attestation_PgpSignedAttestation{PgpSignedAttestation: &apb.PgpSignedAttestation{
+			Signature:   "sig",
+			ContentType: apb.PgpSignedAttestation_SIMPLE_SIGNING_JSON,
+			KeyId:       &apb.PgpSignedAttestation_PgpKeyId{PgpKeyId: "key"},
+		}},
+	}}}
+	want := v1Occurrence(v1pb.NoteKind_ATTESTATION)
+	want.Details = &v1pb.Occurrence_Attestation{Attestation: &v1pb.AttestationOccurrence{
+		Signatures: []*v1pb.Signature{{Signature: []byte("sig"), PublicKeyId: "key"}},
+	}}
+	if diff := cmp.Diff(want, OccurrenceToV1(beta), cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("OccurrenceToV1 returned diff (want -> got):\n%s", diff)
+	}
+
+	// Fields without a counterpart in v1beta1 are dropped, and unknown layer directives are
+	// unspecified.
+	v1 := v1Occurrence(v1pb.NoteKind_IMAGE)
+	v1.ResourceUri = ""
+	v1.Details = &v1pb.Occurrence_Image{Image: &v1pb.ImageOccurrence{
+		LayerInfo: []*v1pb.Layer{{Directive: "UNKNOWN", Arguments: "bash"}},
+	}}
+	wantBeta := v1beta1Occurrence(cpb.NoteKind_IMAGE)
+	wantBeta.Resource = nil
+	wantBeta.Details = &gpb.Occurrence_DerivedImage{DerivedImage: &ipb.Details{DerivedImage: &ipb.Derived{
+		LayerInfo: []*ipb.Layer{{Arguments: "bash"}},
+	}}}
+	if diff := cmp.Diff(wantBeta, OccurrenceToV1Beta1(v1), cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("OccurrenceToV1Beta1 returned diff (want -> got):\n%s", diff)
+	}
+
+	n := v1Note(v1pb.NoteKind_PACKAGE)
+	n.Type = &v1pb.Note_Package{Package: &v1pb.PackageNote{
+		Distribution: []*v1pb.Distribution{{LatestVersion: &v1pb.Version{Name: "55.1", FullName: "1:55.1-1"}}},
+	}}
+	if got := NoteToV1Beta1(n).GetPackage().Distribution[0].LatestVersion; got.Name != "55.1" {
+		t.Errorf("NoteToV1Beta1 got latest version %v, want name 55.1", got)
+	}
+
+	if NoteToV1(nil) != nil || NoteToV1Beta1(nil) != nil || OccurrenceToV1(nil) != nil || OccurrenceToV1Beta1(nil) != nil {
+		t.Errorf("converting nil got non-nil, want nil")
+	}
+}
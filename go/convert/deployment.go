@@ -0,0 +1,68 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	deploymentpb "github.com/grafeas/grafeas/proto/v1beta1/deployment_go_proto"
+)
+
+func deploymentNoteToV1(d *deploymentpb.Deployable) *v1pb.DeploymentNote {
+	if d == nil {
+		return nil
+	}
+	return &v1pb.DeploymentNote{ResourceUri: d.ResourceUri}
+}
+
+func deploymentNoteToV1Beta1(d *v1pb.DeploymentNote) *deploymentpb.Deployable {
+	if d == nil {
+		return nil
+	}
+	return &deploymentpb.Deployable{ResourceUri: d.ResourceUri}
+}
+
+func deploymentOccurrenceToV1(d *deploymentpb.Details) *v1pb.DeploymentOccurrence {
+	if d == nil {
+		return nil
+	}
+	dep := d.Deployment
+	if dep == nil {
+		return &v1pb.DeploymentOccurrence{}
+	}
+	return &v1pb.DeploymentOccurrence{
+		UserEmail:    dep.UserEmail,
+		DeployTime:   dep.DeployTime,
+		UndeployTime: dep.UndeployTime,
+		Config:       dep.Config,
+		Address:      dep.Address,
+		ResourceUri:  dep.ResourceUri,
+		Platform:     v1pb.DeploymentOccurrence_Platform(dep.Platform),
+	}
+}
+
+func deploymentOccurrenceToV1Beta1(d *v1pb.DeploymentOccurrence) *deploymentpb.Details {
+	if d == nil {
+		return nil
+	}
+	return &deploymentpb.Details{Deployment: &deploymentpb.Deployment{
+		UserEmail:    d.UserEmail,
+		DeployTime:   d.DeployTime,
+		UndeployTime: d.UndeployTime,
+		Config:       d.Config,
+		Address:      d.Address,
+		ResourceUri:  d.ResourceUri,
+		Platform:     deploymentpb.Deployment_Platform(d.Platform),
+	}}
+}
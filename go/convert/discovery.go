@@ -0,0 +1,61 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	cpb "github.com/grafeas/grafeas/proto/v1beta1/common_go_proto"
+	discoverypb "github.com/grafeas/grafeas/proto/v1beta1/discovery_go_proto"
+)
+
+func discoveryNoteToV1(d *discoverypb.Discovery) *v1pb.DiscoveryNote {
+	if d == nil {
+		return nil
+	}
+	return &v1pb.DiscoveryNote{AnalysisKind: v1pb.NoteKind(d.AnalysisKind)}
+}
+
+func discoveryNoteToV1Beta1(d *v1pb.DiscoveryNote) *discoverypb.Discovery {
+	if d == nil {
+		return nil
+	}
+	return &discoverypb.Discovery{AnalysisKind: cpb.NoteKind(d.AnalysisKind)}
+}
+
+func discoveryOccurrenceToV1(d *discoverypb.Details) *v1pb.DiscoveryOccurrence {
+	if d == nil {
+		return nil
+	}
+	discovered := d.Discovered
+	if discovered == nil {
+		return &v1pb.DiscoveryOccurrence{}
+	}
+	return &v1pb.DiscoveryOccurrence{
+		ContinuousAnalysis:  v1pb.DiscoveryOccurrence_ContinuousAnalysis(discovered.ContinuousAnalysis),
+		AnalysisStatus:      v1pb.DiscoveryOccurrence_AnalysisStatus(discovered.AnalysisStatus),
+		AnalysisStatusError: discovered.AnalysisStatusError,
+	}
+}
+
+func discoveryOccurrenceToV1Beta1(d *v1pb.DiscoveryOccurrence) *discoverypb.Details {
+	if d == nil {
+		return nil
+	}
+	return &discoverypb.Details{Discovered: &discoverypb.Discovered{
+		ContinuousAnalysis:  discoverypb.Discovered_ContinuousAnalysis(d.ContinuousAnalysis),
+		AnalysisStatus:      discoverypb.Discovered_AnalysisStatus(d.AnalysisStatus),
+		AnalysisStatusError: d.AnalysisStatusError,
+	}}
+}
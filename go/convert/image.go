@@ -0,0 +1,97 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	ipb "github.com/grafeas/grafeas/proto/v1beta1/image_go_proto"
+)
+
+func imageNoteToV1(b *ipb.Basis) *v1pb.ImageNote {
+	if b == nil {
+		return nil
+	}
+	return &v1pb.ImageNote{ResourceUrl: b.ResourceUrl, Fingerprint: fingerprintToV1(b.Fingerprint)}
+}
+
+func imageNoteToV1Beta1(i *v1pb.ImageNote) *ipb.Basis {
+	if i == nil {
+		return nil
+	}
+	return &ipb.Basis{ResourceUrl: i.ResourceUrl, Fingerprint: fingerprintToV1Beta1(i.Fingerprint)}
+}
+
+func imageOccurrenceToV1(d *ipb.Details) *v1pb.ImageOccurrence {
+	if d == nil {
+		return nil
+	}
+	derived := d.DerivedImage
+	if derived == nil {
+		return &v1pb.ImageOccurrence{}
+	}
+	out := &v1pb.ImageOccurrence{
+		Fingerprint:     fingerprintToV1(derived.Fingerprint),
+		Distance:        derived.Distance,
+		BaseResourceUrl: derived.BaseResourceUrl,
+	}
+	for _, l := range derived.LayerInfo {
+		out.LayerInfo = append(out.LayerInfo, &v1pb.Layer{Directive: directiveToV1(l.Directive), Arguments: l.Arguments})
+	}
+	return out
+}
+
+func imageOccurrenceToV1Beta1(i *v1pb.ImageOccurrence) *ipb.Details {
+	if i == nil {
+		return nil
+	}
+	derived := &ipb.Derived{
+		Fingerprint:     fingerprintToV1Beta1(i.Fingerprint),
+		Distance:        i.Distance,
+		BaseResourceUrl: i.BaseResourceUrl,
+	}
+	for _, l := range i.LayerInfo {
+		derived.LayerInfo = append(derived.LayerInfo, &ipb.Layer{Directive: directiveToV1Beta1(l.Directive), Arguments: l.Arguments})
+	}
+	return &ipb.Details{DerivedImage: derived}
+}
+
+func fingerprintToV1(f *ipb.Fingerprint) *v1pb.Fingerprint {
+	if f == nil {
+		return nil
+	}
+	return &v1pb.Fingerprint{V1Name: f.V1Name, V2Blob: f.V2Blob, V2Name: f.V2Name}
+}
+
+func fingerprintToV1Beta1(f *v1pb.Fingerprint) *ipb.Fingerprint {
+	if f == nil {
+		return nil
+	}
+	return &ipb.Fingerprint{V1Name: f.V1Name, V2Blob: f.V2Blob, V2Name: f.V2Name}
+}
+
+// directiveToV1 returns the Dockerfile directive of a v1beta1 layer as the string used by v1, which
+// is empty for unspecified directives.
+func directiveToV1(d ipb.Layer_Directive) string {
+	if d == ipb.Layer_DIRECTIVE_UNSPECIFIED {
+		return ""
+	}
+	return d.String()
+}
+
+// directiveToV1Beta1 returns the v1beta1 directive of a v1 layer, or DIRECTIVE_UNSPECIFIED if the
+// directive is unknown.
+func directiveToV1Beta1(d string) ipb.Layer_Directive {
+	return ipb.Layer_Directive(ipb.Layer_Directive_value[d])
+}
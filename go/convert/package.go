@@ -0,0 +1,78 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	pkgpb "github.com/grafeas/grafeas/proto/v1beta1/package_go_proto"
+)
+
+func packageNoteToV1(p *pkgpb.Package) *v1pb.PackageNote {
+	if p == nil {
+		return nil
+	}
+	out := &v1pb.PackageNote{Name: p.Name}
+	for _, d := range p.Distribution {
+		out.Distribution = append(out.Distribution, &v1pb.Distribution{
+			CpeUri:        d.CpeUri,
+			Architecture:  v1pb.Architecture(d.Architecture),
+			LatestVersion: versionToV1(d.LatestVersion),
+			Maintainer:    d.Maintainer,
+			Url:           d.Url,
+			Description:   d.Description,
+		})
+	}
+	return out
+}
+
+func packageNoteToV1Beta1(p *v1pb.PackageNote) *pkgpb.Package {
+	if p == nil {
+		return nil
+	}
+	out := &pkgpb.Package{Name: p.Name}
+	for _, d := range p.Distribution {
+		out.Distribution = append(out.Distribution, &pkgpb.Distribution{
+			CpeUri:        d.CpeUri,
+			Architecture:  pkgpb.Architecture(d.Architecture),
+			LatestVersion: versionToV1Beta1(d.LatestVersion),
+			Maintainer:    d.Maintainer,
+			Url:           d.Url,
+			Description:   d.Description,
+		})
+	}
+	return out
+}
+
+func packageOccurrenceToV1(d *pkgpb.Details) *v1pb.PackageOccurrence {
+	if d == nil {
+		return nil
+	}
+	out := &v1pb.PackageOccurrence{Name: d.GetInstallation().GetName()}
+	for _, l := range d.GetInstallation().GetLocation() {
+		out.Location = append(out.Location, &v1pb.Location{CpeUri: l.CpeUri, Version: versionToV1(l.Version), Path: l.Path})
+	}
+	return out
+}
+
+func packageOccurrenceToV1Beta1(p *v1pb.PackageOccurrence) *pkgpb.Details {
+	if p == nil {
+		return nil
+	}
+	installation := &pkgpb.Installation{Name: p.Name}
+	for _, l := range p.Location {
+		installation.Location = append(installation.Location, &pkgpb.Location{CpeUri: l.CpeUri, Version: versionToV1Beta1(l.Version), Path: l.Path})
+	}
+	return &pkgpb.Details{Installation: installation}
+}
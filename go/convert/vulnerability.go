@@ -0,0 +1,198 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	cvsspb "github.com/grafeas/grafeas/proto/v1beta1/cvss_go_proto"
+	pkgpb "github.com/grafeas/grafeas/proto/v1beta1/package_go_proto"
+	vpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
+)
+
+func vulnerabilityNoteToV1(v *vpb.Vulnerability) *v1pb.VulnerabilityNote {
+	if v == nil {
+		return nil
+	}
+	out := &v1pb.VulnerabilityNote{
+		CvssScore: v.CvssScore,
+		Severity:  v1pb.Severity(v.Severity),
+		CvssV3:    cvssToV1(v.CvssV3),
+	}
+	for _, d := range v.Details {
+		out.Details = append(out.Details, &v1pb.VulnerabilityNote_Detail{
+			SeverityName:       d.SeverityName,
+			Description:        d.Description,
+			PackageType:        d.PackageType,
+			AffectedCpeUri:     d.CpeUri,
+			AffectedPackage:    d.Package,
+			MinAffectedVersion: versionToV1(d.MinAffectedVersion),
+			FixedCpeUri:        d.GetFixedLocation().GetCpeUri(),
+			FixedPackage:       d.GetFixedLocation().GetPackage(),
+			FixedVersion:       versionToV1(d.GetFixedLocation().GetVersion()),
+			IsObsolete:         d.IsObsolete,
+		})
+	}
+	for _, w := range v.WindowsDetails {
+		wd := &v1pb.VulnerabilityNote_WindowsDetail{
+			CpeUri:      w.CpeUri,
+			Name:        w.Name,
+			Description: w.Description,
+		}
+		for _, kb := range w.FixingKbs {
+			wd.FixingKbs = append(wd.FixingKbs, &v1pb.VulnerabilityNote_WindowsDetail_KnowledgeBase{Name: kb.Name, Url: kb.Url})
+		}
+		out.WindowsDetails = append(out.WindowsDetails, wd)
+	}
+	return out
+}
+
+func vulnerabilityNoteToV1Beta1(v *v1pb.VulnerabilityNote) *vpb.Vulnerability {
+	if v == nil {
+		return nil
+	}
+	out := &vpb.Vulnerability{
+		CvssScore: v.CvssScore,
+		Severity:  vpb.Severity(v.Severity),
+		CvssV3:    cvssToV1Beta1(v.CvssV3),
+	}
+	for _, d := range v.Details {
+		out.Details = append(out.Details, &vpb.Vulnerability_Detail{
+			CpeUri:             d.AffectedCpeUri,
+			Package:            d.AffectedPackage,
+			MinAffectedVersion: versionToV1Beta1(d.MinAffectedVersion),
+			SeverityName:       d.SeverityName,
+			Description:        d.Description,
+			FixedLocation:      locationToV1Beta1(d.FixedCpeUri, d.FixedPackage, d.FixedVersion),
+			PackageType:        d.PackageType,
+			IsObsolete:         d.IsObsolete,
+		})
+	}
+	for _, w := range v.WindowsDetails {
+		wd := &vpb.Vulnerability_WindowsDetail{
+			CpeUri:      w.CpeUri,
+			Name:        w.Name,
+			Description: w.Description,
+		}
+		for _, kb := range w.FixingKbs {
+			wd.FixingKbs = append(wd.FixingKbs, &vpb.Vulnerability_WindowsDetail_KnowledgeBase{Name: kb.Name, Url: kb.Url})
+		}
+		out.WindowsDetails = append(out.WindowsDetails, wd)
+	}
+	return out
+}
+
+func cvssToV1(c *cvsspb.CVSSv3) *v1pb.CVSSv3 {
+	if c == nil {
+		return nil
+	}
+	return &v1pb.CVSSv3{
+		BaseScore:             c.BaseScore,
+		ExploitabilityScore:   c.ExploitabilityScore,
+		ImpactScore:           c.ImpactScore,
+		AttackVector:          v1pb.CVSSv3_AttackVector(c.AttackVector),
+		AttackComplexity:      v1pb.CVSSv3_AttackComplexity(c.AttackComplexity),
+		PrivilegesRequired:    v1pb.CVSSv3_PrivilegesRequired(c.PrivilegesRequired),
+		UserInteraction:       v1pb.CVSSv3_UserInteraction(c.UserInteraction),
+		Scope:                 v1pb.CVSSv3_Scope(c.Scope),
+		ConfidentialityImpact: v1pb.CVSSv3_Impact(c.ConfidentialityImpact),
+		IntegrityImpact:       v1pb.CVSSv3_Impact(c.IntegrityImpact),
+		AvailabilityImpact:    v1pb.CVSSv3_Impact(c.AvailabilityImpact),
+	}
+}
+
+func cvssToV1Beta1(c *v1pb.CVSSv3) *cvsspb.CVSSv3 {
+	if c == nil {
+		return nil
+	}
+	return &cvsspb.CVSSv3{
+		BaseScore:             c.BaseScore,
+		ExploitabilityScore:   c.ExploitabilityScore,
+		ImpactScore:           c.ImpactScore,
+		AttackVector:          cvsspb.CVSSv3_AttackVector(c.AttackVector),
+		AttackComplexity:      cvsspb.CVSSv3_AttackComplexity(c.AttackComplexity),
+		PrivilegesRequired:    cvsspb.CVSSv3_PrivilegesRequired(c.PrivilegesRequired),
+		UserInteraction:       cvsspb.CVSSv3_UserInteraction(c.UserInteraction),
+		Scope:                 cvsspb.CVSSv3_Scope(c.Scope),
+		ConfidentialityImpact: cvsspb.CVSSv3_Impact(c.ConfidentialityImpact),
+		IntegrityImpact:       cvsspb.CVSSv3_Impact(c.IntegrityImpact),
+		AvailabilityImpact:    cvsspb.CVSSv3_Impact(c.AvailabilityImpact),
+	}
+}
+
+func vulnerabilityOccurrenceToV1(d *vpb.Details) *v1pb.VulnerabilityOccurrence {
+	if d == nil {
+		return nil
+	}
+	out := &v1pb.VulnerabilityOccurrence{
+		Type:              d.Type,
+		Severity:          v1pb.Severity(d.Severity),
+		CvssScore:         d.CvssScore,
+		ShortDescription:  d.ShortDescription,
+		LongDescription:   d.LongDescription,
+		RelatedUrls:       relatedURLsToV1(d.RelatedUrls),
+		EffectiveSeverity: v1pb.Severity(d.EffectiveSeverity),
+	}
+	for _, pi := range d.PackageIssue {
+		issue := &v1pb.VulnerabilityOccurrence_PackageIssue{
+			AffectedCpeUri:     pi.GetAffectedLocation().GetCpeUri(),
+			AffectedPackage:    pi.GetAffectedLocation().GetPackage(),
+			MinAffectedVersion: versionToV1(pi.GetAffectedLocation().GetVersion()),
+			FixedCpeUri:        pi.GetFixedLocation().GetCpeUri(),
+			FixedPackage:       pi.GetFixedLocation().GetPackage(),
+			FixedVersion:       versionToV1(pi.GetFixedLocation().GetVersion()),
+			FixAvailable:       fixAvailable(pi.GetFixedLocation().GetVersion()),
+		}
+		out.FixAvailable = out.FixAvailable || issue.FixAvailable
+		out.PackageIssue = append(out.PackageIssue, issue)
+	}
+	return out
+}
+
+func vulnerabilityOccurrenceToV1Beta1(v *v1pb.VulnerabilityOccurrence) *vpb.Details {
+	if v == nil {
+		return nil
+	}
+	out := &vpb.Details{
+		Type:              v.Type,
+		Severity:          vpb.Severity(v.Severity),
+		CvssScore:         v.CvssScore,
+		ShortDescription:  v.ShortDescription,
+		LongDescription:   v.LongDescription,
+		RelatedUrls:       relatedURLsToV1Beta1(v.RelatedUrls),
+		EffectiveSeverity: vpb.Severity(v.EffectiveSeverity),
+	}
+	for _, pi := range v.PackageIssue {
+		out.PackageIssue = append(out.PackageIssue, &vpb.PackageIssue{
+			AffectedLocation: locationToV1Beta1(pi.AffectedCpeUri, pi.AffectedPackage, pi.MinAffectedVersion),
+			FixedLocation:    locationToV1Beta1(pi.FixedCpeUri, pi.FixedPackage, pi.FixedVersion),
+		})
+	}
+	return out
+}
+
+// locationToV1Beta1 returns the vulnerability location of the specified fields, or nil if none of
+// them is set.
+func locationToV1Beta1(cpeURI, pkg string, v *v1pb.Version) *vpb.VulnerabilityLocation {
+	if cpeURI == "" && pkg == "" && v == nil {
+		return nil
+	}
+	return &vpb.VulnerabilityLocation{CpeUri: cpeURI, Package: pkg, Version: versionToV1Beta1(v)}
+}
+
+// fixAvailable returns whether the specified fixed version denotes an actual fix, as v1beta1 uses
+// the maximum version to mean that the vulnerability isn't fixed yet.
+func fixAvailable(fixed *pkgpb.Version) bool {
+	return fixed != nil && fixed.Kind != pkgpb.Version_MAXIMUM
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package etag computes and checks the entity tags used for optimistic concurrency control of
+// notes and occurrences.
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/grafeas/grafeas/go/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// IfMatchKey is the request metadata key the caller sets to the etag it expects the resource to
+	// have.
+	IfMatchKey = "if-match"
+	// gatewayIfMatchKey is the request metadata key grpc-gateway forwards the If-Match HTTP header
+	// as.
+	gatewayIfMatchKey = "grpcgateway-if-match"
+	// HeaderKey is the response metadata key the current etag of a resource is returned in.
+	HeaderKey = "etag"
+)
+
+// Compute returns the etag of the specified resource. The etag is derived from the deterministic
+// serialization of the resource, so any change to a stored note or occurrence changes its etag.
+func Compute(m proto.Message) (string, error) {
+	b := proto.NewBuffer(nil)
+	b.SetDeterministic(true)
+	// Marshal a copy, marshaling caches sizes in the message which would otherwise race with other
+	// readers of a stored resource.
+	if err := b.Marshal(proto.Clone(m)); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b.Bytes())
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// Check verifies that the specified resource has the wanted etag. An empty want always matches. On
+// mismatch an Aborted error is returned so that callers know to re-read the resource and retry.
+func Check(m proto.Message, want string) error {
+	if want == "" {
+		return nil
+	}
+	got, err := Compute(m)
+	if err != nil {
+		return errors.Newf(codes.Internal, "failed to compute etag: %v", err)
+	}
+	if got != want {
+		return errors.Newf(codes.Aborted, "etag %q does not match the current etag %q, the resource was modified concurrently", want, got)
+	}
+	return nil
+}
+
+// FromIncomingContext returns the etag the caller expects the resource to have, as set in the
+// if-match request metadata (or the If-Match HTTP header when called through the gateway). An
+// empty string is returned if the caller didn't set one.
+func FromIncomingContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, k := range []string{IfMatchKey, gatewayIfMatchKey} {
+		if v := md.Get(k); len(v) > 0 {
+			return normalize(v[0])
+		}
+	}
+	return ""
+}
+
+// SetHeader returns the specified etag to the caller in the etag response header. It is a no-op
+// if the context isn't from a gRPC call, e.g. when the API is called directly.
+func SetHeader(ctx context.Context, tag string) {
+	if tag == "" {
+		return
+	}
+	// Errors only occur if ctx doesn't belong to a server stream, in which case there's nobody to
+	// send the header to.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(HeaderKey, tag))
+}
+
+// SetHeaderFor computes the etag of the specified resource and returns it to the caller in the etag
+// response header.
+func SetHeaderFor(ctx context.Context, m proto.Message) {
+	if tag, err := Compute(m); err == nil {
+		SetHeader(ctx, tag)
+	}
+}
+
+// normalize strips the weak validator prefix and quotes HTTP clients put around etags.
+func normalize(tag string) string {
+	tag = strings.TrimSpace(tag)
+	tag = strings.TrimPrefix(tag, "W/")
+	return strings.Trim(tag, `"`)
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etag
+
+import (
+	"testing"
+
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestCompute(t *testing.T) {
+	n := &gpb.Note{Name: "projects/goog-vulnz/notes/CVE-UH-OH", ShortDescription: "bad"}
+	tag, err := Compute(n)
+	if err != nil {
+		t.Fatalf("Compute(%v) got error %v, want success", n, err)
+	}
+	if tag == "" {
+		t.Fatalf("Compute(%v) got empty etag", n)
+	}
+
+	same := &gpb.Note{Name: "projects/goog-vulnz/notes/CVE-UH-OH", ShortDescription: "bad"}
+	if got, _ := Compute(same); got != tag {
+		t.Errorf("Compute of identical note got %q, want %q", got, tag)
+	}
+
+	changed := &gpb.Note{Name: "projects/goog-vulnz/notes/CVE-UH-OH", ShortDescription: "worse"}
+	if got, _ := Compute(changed); got == tag {
+		t.Errorf("Compute of changed note got %q, want a different etag", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	n := &gpb.Note{Name: "projects/goog-vulnz/notes/CVE-UH-OH"}
+	tag, err := Compute(n)
+	if err != nil {
+		t.Fatalf("Compute(%v) got error %v, want success", n, err)
+	}
+
+	tests := []struct {
+		desc     string
+		want     string
+		wantCode codes.Code
+	}{
+		{
+			desc:     "no etag, always matches",
+			want:     "",
+			wantCode: codes.OK,
+		},
+		{
+			desc:     "matching etag",
+			want:     tag,
+			wantCode: codes.OK,
+		},
+		{
+			desc:     "stale etag, want aborted",
+			want:     "d41d8cd98f00b204e9800998ecf8427e",
+			wantCode: codes.Aborted,
+		},
+	}
+
+	for _, tt := range tests {
+		err := Check(n, tt.want)
+		if status.Code(err) != tt.wantCode {
+			t.Errorf("%q: Check(%v, %q) got code %v, want %v", tt.desc, n, tt.want, status.Code(err), tt.wantCode)
+		}
+	}
+}
+
+func TestFromIncomingContext(t *testing.T) {
+	tests := []struct {
+		desc string
+		md   metadata.MD
+		want string
+	}{
+		{
+			desc: "no metadata",
+			want: "",
+		},
+		{
+			desc: "grpc if-match",
+			md:   metadata.Pairs("if-match", "abc"),
+			want: "abc",
+		},
+		{
+			desc: "quoted weak etag from the gateway",
+			md:   metadata.Pairs("grpcgateway-if-match", `W/"abc"`),
+			want: "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.md != nil {
+			ctx = metadata.NewIncomingContext(ctx, tt.md)
+		}
+		if got := FromIncomingContext(ctx); got != tt.want {
+			t.Errorf("%q: FromIncomingContext got %q, want %q", tt.desc, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eval evaluates filter expressions against protocol buffer messages.
+//
+// Fields are addressed by their proto field names, e.g. `resource.uri`, and enums by their value
+// names, e.g. `kind = VULNERABILITY`. A restriction on a path through a repeated field matches if
+// it matches any of its elements.
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	expr "github.com/google/cel-spec/proto/v1"
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/filtering/common"
+	"github.com/grafeas/grafeas/go/filtering/operators"
+	"github.com/grafeas/grafeas/go/filtering/parser"
+	"google.golang.org/grpc/codes"
+)
+
+// Filter is a parsed filter expression.
+type Filter struct {
+	// expr is nil for the empty filter, which matches everything.
+	expr *expr.Expr
+}
+
+// Compile parses the specified filter expression. It returns an InvalidArgument error if the
+// filter is malformed or uses functions that aren't supported.
+func Compile(filter string) (*Filter, error) {
+	if strings.TrimSpace(filter) == "" {
+		return &Filter{}, nil
+	}
+	parsed, errs := parser.Parse(common.NewStringSource(filter, "filter"))
+	if errs != nil {
+		return nil, errors.Newf(codes.InvalidArgument, "invalid filter %q: %v", filter, errs)
+	}
+	if err := check(parsed.Expr); err != nil {
+		return nil, errors.Newf(codes.InvalidArgument, "invalid filter %q: %v", filter, err)
+	}
+	return &Filter{expr: parsed.Expr}, nil
+}
+
+// Matches returns whether the specified message satisfies the filter.
+func (f *Filter) Matches(m proto.Message) (bool, error) {
+	if f.expr == nil {
+		return true, nil
+	}
+	s, err := (&jsonpb.Marshaler{OrigName: true}).MarshalToString(m)
+	if err != nil {
+		return false, errors.Newf(codes.Internal, "failed to evaluate filter: %v", err)
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return false, errors.Newf(codes.Internal, "failed to evaluate filter: %v", err)
+	}
+	return eval(f.expr, v), nil
+}
+
+// check returns an error if e can't be evaluated.
+func check(e *expr.Expr) error {
+	call := e.GetCallExpr()
+	if call == nil {
+		return fmt.Errorf("unsupported expression %v", e)
+	}
+	switch call.Function {
+	case operators.Sequence, operators.LogicalAnd, operators.LogicalOr, operators.LogicalNot, operators.Negate:
+		for _, arg := range call.Args {
+			if err := check(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	case operators.Global:
+		if _, ok := literal(call.Args[0]); !ok {
+			return fmt.Errorf("unsupported expression %v", call.Args[0])
+		}
+		return nil
+	case operators.Has, operators.Equals, operators.NotEquals, operators.Less, operators.LessEquals, operators.Greater, operators.GreaterEquals:
+		if _, ok := path(call.Args[0]); !ok {
+			return fmt.Errorf("left-hand side of %q must be a field", call.Function)
+		}
+		if _, ok := literal(call.Args[1]); !ok {
+			return fmt.Errorf("right-hand side of %q must be a value", call.Function)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported function %q", call.Function)
+}
+
+// eval evaluates e, which has passed check, against the JSON value v.
+func eval(e *expr.Expr, v interface{}) bool {
+	call := e.GetCallExpr()
+	switch call.Function {
+	case operators.Sequence, operators.LogicalAnd:
+		for _, arg := range call.Args {
+			if !eval(arg, v) {
+				return false
+			}
+		}
+		return true
+	case operators.LogicalOr:
+		for _, arg := range call.Args {
+			if eval(arg, v) {
+				return true
+			}
+		}
+		return false
+	case operators.LogicalNot, operators.Negate:
+		return !eval(call.Args[0], v)
+	case operators.Global:
+		text, _ := literal(call.Args[0])
+		return containsText(v, text)
+	case operators.NotEquals:
+		// a != b is the negation of a = b, so it also matches fields that aren't set.
+		return !restrict(operators.Equals, call.Args, v)
+	}
+	return restrict(call.Function, call.Args, v)
+}
+
+// restrict evaluates the restriction op with the specified field and value arguments against v.
+func restrict(op string, args []*expr.Expr, v interface{}) bool {
+	p, _ := path(args[0])
+	want, _ := literal(args[1])
+	for _, got := range lookup(v, p) {
+		if op == operators.Has {
+			if has(got, want) {
+				return true
+			}
+			continue
+		}
+		c, ok := compare(got, want)
+		if !ok {
+			continue
+		}
+		switch {
+		case op == operators.Equals && c == 0,
+			op == operators.Less && c < 0,
+			op == operators.LessEquals && c <= 0,
+			op == operators.Greater && c > 0,
+			op == operators.GreaterEquals && c >= 0:
+			return true
+		}
+	}
+	return false
+}
+
+// has implements the `:` operator: `*` matches any set value, maps match on their keys and
+// scalars on substrings.
+func has(got interface{}, want string) bool {
+	if want == "*" {
+		return true
+	}
+	switch got := got.(type) {
+	case map[string]interface{}:
+		_, ok := got[want]
+		return ok
+	case string:
+		return strings.Contains(got, want)
+	}
+	c, ok := compare(got, want)
+	return ok && c == 0
+}
+
+// compare compares the scalar JSON value got to want, numerically if both are numbers. It returns
+// false if got isn't a scalar.
+func compare(got interface{}, want string) (int, bool) {
+	var s string
+	switch got := got.(type) {
+	case string:
+		s = got
+	case float64:
+		s = strconv.FormatFloat(got, 'g', -1, 64)
+	case bool:
+		s = strconv.FormatBool(got)
+	default:
+		return 0, false
+	}
+	if a, err := strconv.ParseFloat(s, 64); err == nil {
+		if b, err := strconv.ParseFloat(want, 64); err == nil {
+			switch {
+			case a < b:
+				return -1, true
+			case a > b:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return strings.Compare(s, want), true
+}
+
+// containsText returns whether any scalar within v contains text.
+func containsText(v interface{}, text string) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, e := range v {
+			if containsText(e, text) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, e := range v {
+			if containsText(e, text) {
+				return true
+			}
+		}
+	case nil:
+	default:
+		return strings.Contains(fmt.Sprint(v), text)
+	}
+	return false
+}
+
+// lookup returns the values at the field path p within v, fanning out over repeated fields.
+func lookup(v interface{}, p []string) []interface{} {
+	if list, ok := v.([]interface{}); ok {
+		var vs []interface{}
+		for _, e := range list {
+			vs = append(vs, lookup(e, p)...)
+		}
+		return vs
+	}
+	if len(p) == 0 {
+		if v == nil {
+			return nil
+		}
+		return []interface{}{v}
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return lookup(m[p[0]], p[1:])
+}
+
+// path returns the field path e refers to, e.g. ["resource", "uri"] for `resource.uri`.
+func path(e *expr.Expr) ([]string, bool) {
+	switch {
+	case e.GetIdentExpr() != nil:
+		return []string{e.GetIdentExpr().Name}, true
+	case e.GetSelectExpr() != nil:
+		p, ok := path(e.GetSelectExpr().Operand)
+		return append(p, e.GetSelectExpr().Field), ok
+	}
+	return nil, false
+}
+
+// literal returns the text of the value e represents. Unquoted text is parsed as identifiers, so
+// `kind = VULNERABILITY` and `kind = "VULNERABILITY"` are equivalent.
+func literal(e *expr.Expr) (string, bool) {
+	if p, ok := path(e); ok {
+		return strings.Join(p, "."), true
+	}
+	c := e.GetConstExpr()
+	if c == nil {
+		return "", false
+	}
+	switch k := c.ConstantKind.(type) {
+	case *expr.Constant_StringValue:
+		return k.StringValue, true
+	case *expr.Constant_Int64Value:
+		return strconv.FormatInt(k.Int64Value, 10), true
+	case *expr.Constant_Uint64Value:
+		return strconv.FormatUint(k.Uint64Value, 10), true
+	case *expr.Constant_DoubleValue:
+		return strconv.FormatFloat(k.DoubleValue, 'g', -1, 64), true
+	case *expr.Constant_BoolValue:
+		return strconv.FormatBool(k.BoolValue), true
+	}
+	return "", false
+}
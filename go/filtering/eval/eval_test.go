@@ -0,0 +1,95 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	cpb "github.com/grafeas/grafeas/proto/v1beta1/common_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	vpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMatches(t *testing.T) {
+	o := &gpb.Occurrence{
+		Name:     "projects/consumer1/occurrences/1234",
+		Resource: &gpb.Resource{Uri: "https://gcr.io/consumer1/debian@sha256:abc"},
+		NoteName: "projects/goog-vulnz/notes/CVE-UH-OH",
+		Kind:     cpb.NoteKind_VULNERABILITY,
+		Details: &gpb.Occurrence_Vulnerability{
+			Vulnerability: &vpb.Details{
+				Severity:  vpb.Severity_HIGH,
+				CvssScore: 7.5,
+				PackageIssue: []*vpb.PackageIssue{
+					{AffectedLocation: &vpb.VulnerabilityLocation{Package: "icu"}},
+					{AffectedLocation: &vpb.VulnerabilityLocation{Package: "openssl"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{filter: "", want: true},
+		{filter: `kind = "VULNERABILITY"`, want: true},
+		{filter: `kind = VULNERABILITY`, want: true},
+		{filter: `kind = BUILD`, want: false},
+		{filter: `kind != BUILD`, want: true},
+		{filter: `resource.uri:"gcr.io/consumer1"`, want: true},
+		{filter: `note_name = "projects/goog-vulnz/notes/CVE-UH-OH" AND kind = VULNERABILITY`, want: true},
+		{filter: `kind = BUILD OR vulnerability.severity = HIGH`, want: true},
+		{filter: `vulnerability.cvss_score > 7`, want: true},
+		{filter: `vulnerability.cvss_score >= 8`, want: false},
+		{filter: `vulnerability.package_issue.affected_location.package = openssl`, want: true},
+		{filter: `vulnerability.package_issue.affected_location.package = zlib`, want: false},
+		{filter: `NOT vulnerability.package_issue.affected_location.package = zlib`, want: true},
+		{filter: `remediation:*`, want: false},
+		{filter: `vulnerability:*`, want: true},
+		{filter: `openssl`, want: true},
+		{filter: `-openssl`, want: false},
+	}
+
+	for _, tt := range tests {
+		f, err := Compile(tt.filter)
+		if err != nil {
+			t.Errorf("Compile(%q) got error %v, want success", tt.filter, err)
+			continue
+		}
+		got, err := f.Matches(o)
+		if err != nil {
+			t.Errorf("Matches(%q) got error %v, want success", tt.filter, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Matches(%q) got %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, filter := range []string{
+		`kind = `,
+		`has_fix(kind)`,
+		`kind = lower(BUILD)`,
+	} {
+		if _, err := Compile(filter); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Compile(%q) got %v, want %v", filter, err, codes.InvalidArgument)
+		}
+	}
+}
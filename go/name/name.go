@@ -77,6 +77,25 @@ func ParseOccurrence(name string) (string, string, error) {
 	return params[1], params[3], nil
 }
 
+// ParseOperation parses the project ID and operation ID from an operation resource name.
+func ParseOperation(name string) (string, string, error) {
+	params := strings.Split(name, "/")
+	if len(params) != 4 {
+		return "", "", errors.Newf(codes.InvalidArgument, "name must be in the form 'projects/[PROJECT_ID]/operations/[OPERATION_ID]', got %q", name)
+	}
+	if params[0] != "projects" {
+		return "", "", errors.Newf(codes.InvalidArgument, "name must be in the form 'projects/[PROJECT_ID]/operations/[OPERATION_ID]', got %q", name)
+	}
+	if params[2] != "operations" {
+		return "", "", errors.Newf(codes.InvalidArgument, "name must be in the form 'projects/[PROJECT_ID]/operations/[OPERATION_ID]', got %q", name)
+	}
+	if params[1] == "" || params[3] == "" {
+		return "", "", errors.Newf(codes.InvalidArgument, "name must be in the form 'projects/[PROJECT_ID]/operations/[OPERATION_ID]', got %q", name)
+	}
+
+	return params[1], params[3], nil
+}
+
 // FormatProject formats the specified project ID into a project resource name.
 func FormatProject(pID string) string {
 	return fmt.Sprintf("projects/%s", pID)
@@ -92,3 +111,14 @@ func FormatNote(pID, nID string) string {
 func FormatOccurrence(pID, oID string) string {
 	return fmt.Sprintf("projects/%s/occurrences/%s", pID, oID)
 }
+
+// FormatOperation formats the specified project ID and operation ID into an operation resource
+// name.
+func FormatOperation(pID, opID string) string {
+	return fmt.Sprintf("projects/%s/operations/%s", pID, opID)
+}
+
+// FormatAuditEvent formats the specified project ID and event ID into an audit event resource name.
+func FormatAuditEvent(pID, eID string) string {
+	return fmt.Sprintf("projects/%s/auditEvents/%s", pID, eID)
+}
@@ -166,6 +166,61 @@ func TestParseOccurrence(t *testing.T) {
 	}
 }
 
+func TestParseOperation(t *testing.T) {
+	tests := []struct {
+		name string
+		pID  string
+		opID string
+		err  bool
+	}{{
+		name: "",
+		err:  true,
+	}, {
+		name: "asdf/bear-sheep",
+		err:  true,
+	}, {
+		name: "projects/bear-sheep/operations/1234-asdf-5678/foo/bar",
+		err:  true,
+	}, {
+		name: "projects/",
+		err:  true,
+	}, {
+		name: "asdf/bear-sheep/operations/1234-asdf-5678",
+		err:  true,
+	}, {
+		name: "projects/bear-sheep/occurrences/1234-asdf-5678",
+		err:  true,
+	}, {
+		name: "projects//operations/1234-asdf-5678",
+		err:  true,
+	}, {
+		name: "projects/bear-sheep/operations",
+		err:  true,
+	}, {
+		name: "projects/bear-sheep/operations/1234-asdf-5678",
+		pID:  "bear-sheep",
+		opID: "1234-asdf-5678",
+	}}
+
+	for _, tt := range tests {
+		pID, opID, err := ParseOperation(tt.name)
+		if err != nil {
+			if !tt.err {
+				t.Errorf("Got err when parsing operation name %q: %v, want success", tt.name, err)
+			}
+		} else if tt.err {
+			t.Errorf("Got success when parsing operation name %q, want error", tt.name)
+		}
+
+		if pID != tt.pID {
+			t.Errorf("Got project ID %q, want %q", pID, tt.pID)
+		}
+		if opID != tt.opID {
+			t.Errorf("Got operation ID %q, want %q", opID, tt.opID)
+		}
+	}
+}
+
 func TestFormatProject(t *testing.T) {
 	tests := []struct {
 		pID  string
@@ -247,3 +302,49 @@ func TestFormatOccurrence(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatOperation(t *testing.T) {
+	tests := []struct {
+		pID  string
+		opID string
+		name string
+	}{{
+		pID:  "bear-sheep",
+		opID: "",
+		name: "projects/bear-sheep/operations/",
+	}, {
+		pID:  "bear-sheep",
+		opID: "1234-asdf-5678",
+		name: "projects/bear-sheep/operations/1234-asdf-5678",
+	}}
+
+	for _, tt := range tests {
+		name := FormatOperation(tt.pID, tt.opID)
+		if name != tt.name {
+			t.Errorf("Got operation name %q, want %q", name, tt.name)
+		}
+	}
+}
+
+func TestFormatAuditEvent(t *testing.T) {
+	tests := []struct {
+		pID  string
+		eID  string
+		name string
+	}{{
+		pID:  "bear-sheep",
+		eID:  "",
+		name: "projects/bear-sheep/auditEvents/",
+	}, {
+		pID:  "bear-sheep",
+		eID:  "1234-asdf-5678",
+		name: "projects/bear-sheep/auditEvents/1234-asdf-5678",
+	}}
+
+	for _, tt := range tests {
+		name := FormatAuditEvent(tt.pID, tt.eID)
+		if name != tt.name {
+			t.Errorf("Got audit event name %q, want %q", name, tt.name)
+		}
+	}
+}
@@ -94,6 +94,8 @@ type Storage interface {
 	GetOccurrenceNote(ctx context.Context, projectID, oID string) (*gpb.Note, error)
 	// ListNoteOccurrences lists occurrences for the specified note from storage.
 	ListNoteOccurrences(ctx context.Context, projectID, nID, filter, pageToken string, pageSize int32) ([]*gpb.Occurrence, string, error)
+	// GetVulnerabilityOccurrencesSummary gets a summary of vulnerability occurrences from storage.
+	GetVulnerabilityOccurrencesSummary(ctx context.Context, projectID, filter string) (*gpb.VulnerabilityOccurrencesSummary, error)
 }
 
 // Auth provides authorization functions for this API.
@@ -136,6 +138,9 @@ type API struct {
 	Filter            Filter
 	Logger            Logger
 	EnforceValidation bool
+	// KeepDeletedNotePolicies keeps the IAM policies of deleted notes, for storage that keeps deleted
+	// notes so that they can be undeleted and has their policies purged along with them.
+	KeepDeletedNotePolicies bool
 }
 
 // validatePageSize returns the default page size if the specified page size is 0, otherwise it
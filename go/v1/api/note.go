@@ -178,7 +178,10 @@ func (g *API) DeleteNote(ctx context.Context, req *gpb.DeleteNoteRequest, _ *emp
 		return err
 	}
 
-	// Purge any IAM policies set on this entity.
+	// Purge any IAM policies set on this entity, unless the note can still be undeleted.
+	if g.KeepDeletedNotePolicies {
+		return nil
+	}
 	if err := g.Auth.PurgePolicy(ctx, pID, nID, Notes); err != nil {
 		// This fails open, should not block on policy deletion failure.
 		g.Logger.Warningf(ctx, "Error deleting policies for note %q in project %q: %v", nID, pID, err)
@@ -783,6 +783,103 @@ func TestListNoteOccurrencesErrors(t *testing.T) {
 	}
 }
 
+func TestGetVulnerabilityOccurrencesSummary(t *testing.T) {
+	ctx := context.Background()
+	g := &API{
+		Storage:           newFakeStorage(),
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+	}
+	wantSummary := &gpb.VulnerabilityOccurrencesSummary{
+		Counts: []*gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest{
+			{
+				ResourceUri:  "https://eu.gcr.io/consumer1/debian9@sha256:dbc96ed51bc598faeec0901bad307ebb5d1d7259b33e2d7d7296c28f439dc777",
+				Severity:     gpb.Severity_CRITICAL,
+				FixableCount: 1,
+				TotalCount:   3,
+			},
+			{
+				ResourceUri:  "https://eu.gcr.io/consumer1/debian9@sha256:dbc96ed51bc598faeec0901bad307ebb5d1d7259b33e2d7d7296c28f439dc777",
+				Severity:     gpb.Severity_LOW,
+				FixableCount: 4,
+				TotalCount:   10,
+			},
+		},
+	}
+
+	req := &gpb.GetVulnerabilityOccurrencesSummaryRequest{
+		Parent: "projects/consumer1",
+	}
+	resp := &gpb.VulnerabilityOccurrencesSummary{}
+	if err := g.GetVulnerabilityOccurrencesSummary(ctx, req, resp); err != nil {
+		t.Errorf("GetVulnerabilityOccurrencesSummaryRequest(%v) got err %v, want success", req, err)
+	}
+
+	if diff := cmp.Diff(wantSummary, resp); diff != "" {
+		t.Errorf("GetVulnerabilityOccurrencesSummaryRequest(%v) returned diff (want -> got):\n%s", req, diff)
+	}
+}
+
+func TestGetVulnerabilityOccurrencesSummaryErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		desc string
+		// Test inputs.
+		req                                    *gpb.GetVulnerabilityOccurrencesSummaryRequest
+		internalStorageErr, authErr, filterErr bool
+		wantErrStatus                          codes.Code
+	}{
+		{
+			desc: "invalid project name",
+			req: &gpb.GetVulnerabilityOccurrencesSummaryRequest{
+				Parent: "projects//",
+			},
+			wantErrStatus: codes.InvalidArgument,
+		}, {
+			desc: "auth error",
+			req: &gpb.GetVulnerabilityOccurrencesSummaryRequest{
+				Parent: "projects/consumer1",
+			},
+			authErr:       true,
+			wantErrStatus: codes.PermissionDenied,
+		}, {
+			desc: "storage error",
+			req: &gpb.GetVulnerabilityOccurrencesSummaryRequest{
+				Parent: "projects/consumer1",
+			},
+			internalStorageErr: true,
+			wantErrStatus:      codes.Internal,
+		}, {
+			desc: "filter parse error",
+			req: &gpb.GetVulnerabilityOccurrencesSummaryRequest{
+				Parent: "projects/consumer1",
+			},
+			filterErr:     true,
+			wantErrStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		s := newFakeStorage()
+		s.getVulnSummaryErr = tt.internalStorageErr
+		g := &API{
+			Storage:           s,
+			Auth:              &fakeAuth{authErr: tt.authErr},
+			Filter:            &fakeFilter{err: tt.filterErr},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+		}
+		resp := &gpb.VulnerabilityOccurrencesSummary{}
+		err := g.GetVulnerabilityOccurrencesSummary(ctx, tt.req, resp)
+		if s, _ := status.FromError(err); s.Code() != tt.wantErrStatus {
+			t.Errorf("%q: GetVulnerabilityOccurrencesSummary(%v) got error status %v, want %v", tt.desc, tt.req, s.Code(), tt.wantErrStatus)
+		}
+	}
+}
+
 // vulnzOcc returns a fake v1 valid vulnerability occurrence for testing.
 func vulnzOcc(t *testing.T, pID, noteName, imageName string) *gpb.Occurrence {
 	t.Helper()
@@ -0,0 +1,163 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	emptypb "github.com/golang/protobuf/ptypes/empty"
+	gpb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	"golang.org/x/net/context"
+)
+
+// Server exposes an API as a gRPC service, so that it can be registered with RegisterGrafeasServer.
+type Server struct {
+	API *API
+}
+
+var _ gpb.GrafeasServer = (*Server)(nil)
+
+// GetOccurrence gets the specified occurrence.
+func (s *Server) GetOccurrence(ctx context.Context, req *gpb.GetOccurrenceRequest) (*gpb.Occurrence, error) {
+	resp := &gpb.Occurrence{}
+	if err := s.API.GetOccurrence(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListOccurrences lists occurrences for the specified project.
+func (s *Server) ListOccurrences(ctx context.Context, req *gpb.ListOccurrencesRequest) (*gpb.ListOccurrencesResponse, error) {
+	resp := &gpb.ListOccurrencesResponse{}
+	if err := s.API.ListOccurrences(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteOccurrence deletes the specified occurrence.
+func (s *Server) DeleteOccurrence(ctx context.Context, req *gpb.DeleteOccurrenceRequest) (*emptypb.Empty, error) {
+	resp := &emptypb.Empty{}
+	if err := s.API.DeleteOccurrence(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CreateOccurrence creates the specified occurrence.
+func (s *Server) CreateOccurrence(ctx context.Context, req *gpb.CreateOccurrenceRequest) (*gpb.Occurrence, error) {
+	resp := &gpb.Occurrence{}
+	if err := s.API.CreateOccurrence(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BatchCreateOccurrences batch creates the specified occurrences.
+func (s *Server) BatchCreateOccurrences(ctx context.Context, req *gpb.BatchCreateOccurrencesRequest) (*gpb.BatchCreateOccurrencesResponse, error) {
+	resp := &gpb.BatchCreateOccurrencesResponse{}
+	if err := s.API.BatchCreateOccurrences(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateOccurrence updates the specified occurrence.
+func (s *Server) UpdateOccurrence(ctx context.Context, req *gpb.UpdateOccurrenceRequest) (*gpb.Occurrence, error) {
+	resp := &gpb.Occurrence{}
+	if err := s.API.UpdateOccurrence(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetOccurrenceNote gets the note for the specified occurrence.
+func (s *Server) GetOccurrenceNote(ctx context.Context, req *gpb.GetOccurrenceNoteRequest) (*gpb.Note, error) {
+	resp := &gpb.Note{}
+	if err := s.API.GetOccurrenceNote(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetNote gets the specified note.
+func (s *Server) GetNote(ctx context.Context, req *gpb.GetNoteRequest) (*gpb.Note, error) {
+	resp := &gpb.Note{}
+	if err := s.API.GetNote(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListNotes lists notes for the specified project.
+func (s *Server) ListNotes(ctx context.Context, req *gpb.ListNotesRequest) (*gpb.ListNotesResponse, error) {
+	resp := &gpb.ListNotesResponse{}
+	if err := s.API.ListNotes(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteNote deletes the specified note.
+func (s *Server) DeleteNote(ctx context.Context, req *gpb.DeleteNoteRequest) (*emptypb.Empty, error) {
+	resp := &emptypb.Empty{}
+	if err := s.API.DeleteNote(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CreateNote creates the specified note.
+func (s *Server) CreateNote(ctx context.Context, req *gpb.CreateNoteRequest) (*gpb.Note, error) {
+	resp := &gpb.Note{}
+	if err := s.API.CreateNote(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BatchCreateNotes batch creates the specified notes.
+func (s *Server) BatchCreateNotes(ctx context.Context, req *gpb.BatchCreateNotesRequest) (*gpb.BatchCreateNotesResponse, error) {
+	resp := &gpb.BatchCreateNotesResponse{}
+	if err := s.API.BatchCreateNotes(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateNote updates the specified note.
+func (s *Server) UpdateNote(ctx context.Context, req *gpb.UpdateNoteRequest) (*gpb.Note, error) {
+	resp := &gpb.Note{}
+	if err := s.API.UpdateNote(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListNoteOccurrences lists occurrences for the specified note.
+func (s *Server) ListNoteOccurrences(ctx context.Context, req *gpb.ListNoteOccurrencesRequest) (*gpb.ListNoteOccurrencesResponse, error) {
+	resp := &gpb.ListNoteOccurrencesResponse{}
+	if err := s.API.ListNoteOccurrences(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetVulnerabilityOccurrencesSummary gets a summary of vulnerability occurrences.
+func (s *Server) GetVulnerabilityOccurrencesSummary(ctx context.Context, req *gpb.GetVulnerabilityOccurrencesSummaryRequest) (*gpb.VulnerabilityOccurrencesSummary, error) {
+	resp := &gpb.VulnerabilityOccurrencesSummary{}
+	if err := s.API.GetVulnerabilityOccurrencesSummary(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	gpb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{
+		API: &API{
+			Storage:           newFakeStorage(),
+			Auth:              &fakeAuth{},
+			Filter:            &fakeFilter{},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+		},
+	}
+
+	n := vulnzNote(t)
+	createdN, err := s.CreateNote(ctx, &gpb.CreateNoteRequest{Parent: "projects/goog-vulnz", NoteId: "CVE-UH-OH", Note: n})
+	if err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	if createdN.Name != "projects/goog-vulnz/notes/CVE-UH-OH" {
+		t.Errorf("CreateNote got name %q, want %q", createdN.Name, "projects/goog-vulnz/notes/CVE-UH-OH")
+	}
+
+	gotN, err := s.GetNote(ctx, &gpb.GetNoteRequest{Name: createdN.Name})
+	if err != nil {
+		t.Fatalf("GetNote got err %v, want success", err)
+	}
+	if diff := cmp.Diff(createdN, gotN); diff != "" {
+		t.Errorf("GetNote returned diff (want -> got):\n%s", diff)
+	}
+
+	if _, err := s.DeleteNote(ctx, &gpb.DeleteNoteRequest{Name: createdN.Name}); err != nil {
+		t.Fatalf("DeleteNote got err %v, want success", err)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{
+		API: &API{
+			Storage:           newFakeStorage(),
+			Auth:              &fakeAuth{},
+			Filter:            &fakeFilter{},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+		},
+	}
+
+	got, err := s.GetNote(ctx, &gpb.GetNoteRequest{Name: "projects/goog-vulnz/notes/CVE-UH-OH"})
+	if got != nil {
+		t.Errorf("GetNote of a missing note got %v, want nil", got)
+	}
+	if c := status.Code(err); c != codes.NotFound {
+		t.Errorf("GetNote of a missing note got error code %v, want %v", c, codes.NotFound)
+	}
+	if _, err := s.ListOccurrences(ctx, &gpb.ListOccurrencesRequest{Parent: "projects"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListOccurrences with an invalid parent got err %v, want %v", err, codes.InvalidArgument)
+	}
+}
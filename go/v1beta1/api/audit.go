@@ -0,0 +1,161 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	tpb "github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/google/uuid"
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/etag"
+	"github.com/grafeas/grafeas/go/name"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"golang.org/x/net/context"
+	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+)
+
+// ListAuditEvents lists the audit events of the specified project.
+func (g *API) ListAuditEvents(ctx context.Context, req *auditpb.ListAuditEventsRequest, resp *auditpb.ListAuditEventsResponse) error {
+	pID, err := name.ParseProject(req.Parent)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, "", AuditEventsList); err != nil {
+		return err
+	}
+
+	if g.Audit == nil {
+		return errors.Newf(codes.Unimplemented, "audit events are not supported")
+	}
+	ps, err := validatePageSize(req.PageSize)
+	if err != nil {
+		return err
+	}
+	start, err := auditTime(req.StartTime)
+	if err != nil {
+		return errors.Newf(codes.InvalidArgument, "invalid start time: %v", err)
+	}
+	end, err := auditTime(req.EndTime)
+	if err != nil {
+		return errors.Newf(codes.InvalidArgument, "invalid end time: %v", err)
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return errors.Newf(codes.InvalidArgument, "the start time must be before the end time")
+	}
+
+	events, npt, err := g.Audit.ListAuditEvents(ctx, pID, req.Resource, start, end, req.PageToken, ps)
+	if err != nil {
+		return err
+	}
+	resp.Events = events
+	resp.NextPageToken = npt
+
+	return nil
+}
+
+// audit records, if Audit is set, that actor called method on the resource of the specified project
+// with the specified name, changing it from before to after, either of which is nil if the resource
+// didn't exist. If actor is empty, the caller is looked up in ctx. The call already took effect, so
+// failures to record it are logged instead of failing it.
+func (g *API) audit(ctx context.Context, pID, actor, method, resource string, mask *fieldmaskpb.FieldMask, before, after proto.Message) {
+	if g.Audit == nil {
+		return
+	}
+	if actor == "" {
+		actor = g.auditActor(ctx)
+	}
+	e := &auditpb.AuditEvent{
+		Name:       name.FormatAuditEvent(pID, uuid.New().String()),
+		Actor:      actor,
+		Method:     method,
+		Resource:   resource,
+		UpdateMask: mask,
+		EventTime:  ptypes.TimestampNow(),
+	}
+	var err error
+	if e.BeforeDigest, err = digest(before); err == nil {
+		e.AfterDigest, err = digest(after)
+	}
+	if err != nil {
+		g.Logger.Errorf(ctx, "Error computing the digests of %q for the audit log: %v", resource, err)
+	}
+	if err := g.Audit.RecordAuditEvent(ctx, pID, e); err != nil {
+		g.Logger.Errorf(ctx, "Error recording %s of %q in the audit log: %v", method, resource, err)
+	}
+}
+
+// auditActor returns the caller to record in the audit log, empty if Audit isn't set or the caller
+// can't be identified.
+func (g *API) auditActor(ctx context.Context) string {
+	if g.Audit == nil {
+		return ""
+	}
+	uID, err := g.Auth.EndUserID(ctx)
+	if err != nil {
+		g.Logger.Warningf(ctx, "Error getting the caller for the audit log: %v", err)
+	}
+	return uID
+}
+
+// auditedNote returns the specified note before a call mutates it, if Audit is set and the note
+// can be read.
+func (g *API) auditedNote(ctx context.Context, pID, nID string) *gpb.Note {
+	if g.Audit == nil {
+		return nil
+	}
+	n, err := g.Storage.GetNote(ctx, pID, nID)
+	if err != nil {
+		return nil
+	}
+	return n
+}
+
+// auditedOccurrence returns the specified occurrence before a call mutates it, if Audit is set and
+// the occurrence can be read.
+func (g *API) auditedOccurrence(ctx context.Context, pID, oID string) *gpb.Occurrence {
+	if g.Audit == nil {
+		return nil
+	}
+	o, err := g.Storage.GetOccurrence(ctx, pID, oID)
+	if err != nil {
+		return nil
+	}
+	return o
+}
+
+// digest returns the digest of the specified resource in the audit log, empty if m is nil.
+func digest(m proto.Message) (string, error) {
+	if m == nil || reflect.ValueOf(m).IsNil() {
+		return "", nil
+	}
+	return etag.Compute(m)
+}
+
+// auditTime returns the time of the specified timestamp, the zero time if ts is nil.
+func auditTime(ts *tpb.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+	return ptypes.Timestamp(ts)
+}
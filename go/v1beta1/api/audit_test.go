@@ -0,0 +1,236 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	emptypb "github.com/golang/protobuf/ptypes/empty"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"golang.org/x/net/context"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
+	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuditMutations(t *testing.T) {
+	ctx := context.Background()
+	a := &fakeAudit{}
+	g := &API{
+		Storage:           newFakeStorage(),
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+		IAM:               newFakeIAM(),
+		Audit:             a,
+	}
+	nName := "projects/goog-vulnz/notes/CVE-UH-OH"
+
+	if err := g.CreateNote(ctx, &gpb.CreateNoteRequest{Parent: "projects/goog-vulnz", NoteId: "CVE-UH-OH", Note: vulnzNote(t)}, &gpb.Note{}); err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	n := vulnzNote(t)
+	n.ShortDescription = "updated"
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"short_description"}}
+	if err := g.UpdateNote(ctx, &gpb.UpdateNoteRequest{Name: nName, Note: n, UpdateMask: mask}, &gpb.Note{}); err != nil {
+		t.Fatalf("UpdateNote got err %v, want success", err)
+	}
+	o := &gpb.Occurrence{}
+	if err := g.CreateOccurrence(ctx, &gpb.CreateOccurrenceRequest{Parent: "projects/consumer1", Occurrence: vulnzOcc(t, "consumer1", nName, "debian")}, o); err != nil {
+		t.Fatalf("CreateOccurrence got err %v, want success", err)
+	}
+	if err := g.DeleteOccurrence(ctx, &gpb.DeleteOccurrenceRequest{Name: o.Name}, &emptypb.Empty{}); err != nil {
+		t.Fatalf("DeleteOccurrence got err %v, want success", err)
+	}
+	p := &iampb.Policy{Bindings: []*iampb.Binding{{Role: "roles/viewer", Members: []string{"alice"}}}}
+	if err := g.SetIamPolicy(ctx, &iampb.SetIamPolicyRequest{Resource: nName, Policy: p}, &iampb.Policy{}); err != nil {
+		t.Fatalf("SetIamPolicy got err %v, want success", err)
+	}
+	if err := g.DeleteNote(ctx, &gpb.DeleteNoteRequest{Name: nName}, &emptypb.Empty{}); err != nil {
+		t.Fatalf("DeleteNote got err %v, want success", err)
+	}
+
+	events := append(a.events["goog-vulnz"], a.events["consumer1"]...)
+	want := []struct {
+		method, resource string
+		before, after    bool
+	}{
+		{"CreateNote", nName, false, true},
+		{"UpdateNote", nName, true, true},
+		{"SetIamPolicy", nName, true, true},
+		{"DeleteNote", nName, true, false},
+		{"CreateOccurrence", o.Name, false, true},
+		{"DeleteOccurrence", o.Name, true, false},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d audit events, want %d: %v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Method != w.method || e.Resource != w.resource || e.Actor != "23" {
+			t.Errorf("event %d got %s of %q by %q, want %s of %q by %q", i, e.Method, e.Resource, e.Actor, w.method, w.resource, "23")
+		}
+		if (e.BeforeDigest != "") != w.before || (e.AfterDigest != "") != w.after {
+			t.Errorf("%s got digests %q, %q, want before %v, after %v", w.method, e.BeforeDigest, e.AfterDigest, w.before, w.after)
+		}
+		if !strings.HasPrefix(e.Name, "projects/") || e.EventTime == nil {
+			t.Errorf("%s got name %q and time %v, want them set", w.method, e.Name, e.EventTime)
+		}
+	}
+	if events[1].BeforeDigest != events[0].AfterDigest || events[1].BeforeDigest == events[1].AfterDigest {
+		t.Errorf("UpdateNote got digests %q, %q, want the created digest %q before a new one", events[1].BeforeDigest, events[1].AfterDigest, events[0].AfterDigest)
+	}
+	if events[3].BeforeDigest != events[1].AfterDigest {
+		t.Errorf("DeleteNote got before digest %q, want the updated digest %q", events[3].BeforeDigest, events[1].AfterDigest)
+	}
+	if !proto.Equal(events[1].UpdateMask, mask) {
+		t.Errorf("UpdateNote got mask %v, want %v", events[1].UpdateMask, mask)
+	}
+}
+
+func TestAuditRecordErrorFailsOpen(t *testing.T) {
+	ctx := context.Background()
+	g := &API{
+		Storage:           newFakeStorage(),
+		Auth:              &fakeAuth{endUserIDErr: true},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+		Audit:             &fakeAudit{recordErr: true},
+	}
+	if _, err := g.Storage.CreateNote(ctx, "goog-vulnz", "CVE-UH-OH", "", vulnzNote(t)); err != nil {
+		t.Fatalf("Failed to create note: %v", err)
+	}
+	if err := g.DeleteNote(ctx, &gpb.DeleteNoteRequest{Name: "projects/goog-vulnz/notes/CVE-UH-OH"}, &emptypb.Empty{}); err != nil {
+		t.Errorf("DeleteNote got err %v, want success", err)
+	}
+}
+
+func TestListAuditEvents(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	a := &fakeAudit{}
+	for i, r := range []string{"projects/goog-vulnz/notes/1", "projects/goog-vulnz/notes/2", "projects/goog-vulnz/notes/1"} {
+		ts, err := ptypes.TimestampProto(now.Add(time.Duration(i) * time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := a.RecordAuditEvent(ctx, "goog-vulnz", &auditpb.AuditEvent{Resource: r, EventTime: ts}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g := &API{
+		Storage: newFakeStorage(),
+		Auth:    &fakeAuth{},
+		Filter:  &fakeFilter{},
+		Logger:  &fakeLogger{},
+		Audit:   a,
+	}
+	start, err := ptypes.TimestampProto(now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc string
+		req  *auditpb.ListAuditEventsRequest
+		want int
+	}{
+		{"all", &auditpb.ListAuditEventsRequest{Parent: "projects/goog-vulnz"}, 3},
+		{"by resource", &auditpb.ListAuditEventsRequest{Parent: "projects/goog-vulnz", Resource: "projects/goog-vulnz/notes/1"}, 2},
+		{"by resource and time", &auditpb.ListAuditEventsRequest{Parent: "projects/goog-vulnz", Resource: "projects/goog-vulnz/notes/1", StartTime: start}, 1},
+		{"other project", &auditpb.ListAuditEventsRequest{Parent: "projects/consumer1"}, 0},
+	}
+	for _, tt := range tests {
+		resp := &auditpb.ListAuditEventsResponse{}
+		if err := g.ListAuditEvents(ctx, tt.req, resp); err != nil {
+			t.Errorf("%q: ListAuditEvents got err %v, want success", tt.desc, err)
+			continue
+		}
+		if len(resp.Events) != tt.want {
+			t.Errorf("%q: ListAuditEvents got %d events, want %d", tt.desc, len(resp.Events), tt.want)
+		}
+	}
+}
+
+func TestListAuditEventsErrors(t *testing.T) {
+	ctx := context.Background()
+	now := ptypes.TimestampNow()
+	tests := []struct {
+		desc          string
+		req           *auditpb.ListAuditEventsRequest
+		authErr       bool
+		noAudit       bool
+		listErr       bool
+		wantErrStatus codes.Code
+	}{
+		{
+			desc:          "invalid parent",
+			req:           &auditpb.ListAuditEventsRequest{Parent: "projects"},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "auth error",
+			req:           &auditpb.ListAuditEventsRequest{Parent: "projects/goog-vulnz"},
+			authErr:       true,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc:          "audit not supported",
+			req:           &auditpb.ListAuditEventsRequest{Parent: "projects/goog-vulnz"},
+			noAudit:       true,
+			wantErrStatus: codes.Unimplemented,
+		},
+		{
+			desc:          "negative page size",
+			req:           &auditpb.ListAuditEventsRequest{Parent: "projects/goog-vulnz", PageSize: -1},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "start time not before end time",
+			req:           &auditpb.ListAuditEventsRequest{Parent: "projects/goog-vulnz", StartTime: now, EndTime: now},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "storage error",
+			req:           &auditpb.ListAuditEventsRequest{Parent: "projects/goog-vulnz"},
+			listErr:       true,
+			wantErrStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		g := &API{
+			Storage: newFakeStorage(),
+			Auth:    &fakeAuth{authErr: tt.authErr},
+			Filter:  &fakeFilter{},
+			Logger:  &fakeLogger{},
+		}
+		if !tt.noAudit {
+			g.Audit = &fakeAudit{listErr: tt.listErr}
+		}
+		err := g.ListAuditEvents(ctx, tt.req, &auditpb.ListAuditEventsResponse{})
+		if c := status.Code(err); c != tt.wantErrStatus {
+			t.Errorf("%q: ListAuditEvents(%v) got error code %v, want %v", tt.desc, tt.req, c, tt.wantErrStatus)
+		}
+	}
+}
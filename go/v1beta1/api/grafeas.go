@@ -16,10 +16,18 @@
 package grafeas
 
 import (
+	"time"
+
 	"github.com/grafeas/grafeas/go/errors"
 	"github.com/grafeas/grafeas/go/iam"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
 	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
 	"golang.org/x/net/context"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
 	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
 	"google.golang.org/grpc/codes"
 )
@@ -29,6 +37,9 @@ const (
 	maxPageSize     = 1000
 	maxBatchSize    = 1000
 
+	// maxFailureSamples is the number of failures bulk methods report individually.
+	maxFailureSamples = 100
+
 	// NotesGet is the permission to get a note.
 	NotesGet = iam.Permission("notes.get")
 	// NotesList is the permission to list notes.
@@ -39,6 +50,8 @@ const (
 	NotesUpdate = iam.Permission("notes.update")
 	// NotesDelete is the permission to delete a note.
 	NotesDelete = iam.Permission("notes.delete")
+	// NotesUndelete is the permission to undelete a note.
+	NotesUndelete = iam.Permission("notes.undelete")
 
 	// OccurrencesGet is the permission to get an occurrence.
 	OccurrencesGet = iam.Permission("occurrences.get")
@@ -56,13 +69,53 @@ const (
 	// NotesAttachOccurrence is the permission to attach occurrences for a note you own.
 	NotesAttachOccurrence = iam.Permission("notes.attachOccurrence")
 
+	// NotesGetIamPolicy is the permission to get the IAM policy of a note.
+	NotesGetIamPolicy = iam.Permission("notes.getIamPolicy")
+	// NotesSetIamPolicy is the permission to set the IAM policy of a note.
+	NotesSetIamPolicy = iam.Permission("notes.setIamPolicy")
+	// ProjectsGetIamPolicy is the permission to get the IAM policy of a project.
+	ProjectsGetIamPolicy = iam.Permission("projects.getIamPolicy")
+	// ProjectsSetIamPolicy is the permission to set the IAM policy of a project.
+	ProjectsSetIamPolicy = iam.Permission("projects.setIamPolicy")
+
+	// ProjectsGet is the permission to get a project.
+	ProjectsGet = iam.Permission("projects.get")
+	// ProjectsList is the permission to list projects.
+	ProjectsList = iam.Permission("projects.list")
+	// ProjectsCreate is the permission to create a project.
+	ProjectsCreate = iam.Permission("projects.create")
+	// ProjectsDelete is the permission to delete a project.
+	ProjectsDelete = iam.Permission("projects.delete")
+	// ProjectsUndelete is the permission to undelete a project.
+	ProjectsUndelete = iam.Permission("projects.undelete")
+
+	// AuditEventsList is the permission to list the audit events of a project.
+	AuditEventsList = iam.Permission("auditEvents.list")
+
+	// OperationsGet is the permission to get or wait for an operation.
+	OperationsGet = iam.Permission("operations.get")
+	// OperationsList is the permission to list operations.
+	OperationsList = iam.Permission("operations.list")
+	// OperationsDelete is the permission to delete an operation.
+	OperationsDelete = iam.Permission("operations.delete")
+	// OperationsCancel is the permission to cancel an operation.
+	OperationsCancel = iam.Permission("operations.cancel")
+
 	// Notes is the resource type for notes.
 	Notes = iam.Resource("notes")
 	// Occurrences is the resource type for occurrences.
 	Occurrences = iam.Resource("occurrences")
+	// Projects is the resource type for projects.
+	Projects = iam.Resource("projects")
+	// AuditEvents is the resource type for audit events.
+	AuditEvents = iam.Resource("auditEvents")
 )
 
 // Storage provides storage functions for this API.
+//
+// Updates and deletes take the etag the caller expects the stored entity to have. If it is
+// non-empty and doesn't match the etag.Compute of the stored entity, the call must fail with an
+// Aborted error without modifying anything.
 type Storage interface {
 	// GetOccurrence gets the specified occurrence from storage.
 	GetOccurrence(ctx context.Context, projectID, oID string) (*gpb.Occurrence, error)
@@ -73,9 +126,9 @@ type Storage interface {
 	// CreateOccurrence batch creates the specified occurrences in storage.
 	BatchCreateOccurrences(ctx context.Context, projectID string, userID string, occs []*gpb.Occurrence) ([]*gpb.Occurrence, []error)
 	// UpdateOccurrence updates the specified occurrence in storage.
-	UpdateOccurrence(ctx context.Context, projectID, oID string, o *gpb.Occurrence, mask *fieldmaskpb.FieldMask) (*gpb.Occurrence, error)
+	UpdateOccurrence(ctx context.Context, projectID, oID string, o *gpb.Occurrence, mask *fieldmaskpb.FieldMask, etag string) (*gpb.Occurrence, error)
 	// DeleteOccurrence deletes the specified occurrence in storage.
-	DeleteOccurrence(ctx context.Context, projectID, oID string) error
+	DeleteOccurrence(ctx context.Context, projectID, oID, etag string) error
 
 	// GetNote gets the specified note from storage.
 	GetNote(ctx context.Context, projectID, nID string) (*gpb.Note, error)
@@ -86,9 +139,9 @@ type Storage interface {
 	// BatchCreateNotes batch creates the specified notes in storage.
 	BatchCreateNotes(ctx context.Context, projectID string, userID string, notes map[string]*gpb.Note) ([]*gpb.Note, []error)
 	// UpdateNote updates the specified note in storage.
-	UpdateNote(ctx context.Context, projectID, nID string, n *gpb.Note, mask *fieldmaskpb.FieldMask) (*gpb.Note, error)
+	UpdateNote(ctx context.Context, projectID, nID string, n *gpb.Note, mask *fieldmaskpb.FieldMask, etag string) (*gpb.Note, error)
 	// DeleteNote deletes the specified note in storage.
-	DeleteNote(ctx context.Context, projectID, nID string) error
+	DeleteNote(ctx context.Context, projectID, nID, etag string) error
 
 	// GetOccurrenceNote gets the note for the specified occurrence from storage.
 	GetOccurrenceNote(ctx context.Context, projectID, oID string) (*gpb.Note, error)
@@ -98,6 +151,95 @@ type Storage interface {
 	GetVulnerabilityOccurrencesSummary(ctx context.Context, projectID, filter string) (*gpb.VulnerabilityOccurrencesSummary, error)
 }
 
+// Upserts provides storage functions for upserting occurrences.
+type Upserts interface {
+	// UpsertOccurrence updates the occurrence in storage for the same project, resource URI and note
+	// as the specified occurrence in place, keeping its name and create time and bumping its update
+	// time, or creates the specified occurrence if there is none.
+	UpsertOccurrence(ctx context.Context, projectID string, userID string, o *gpb.Occurrence) (*gpb.Occurrence, error)
+	// BatchUpsertOccurrences batch upserts the specified occurrences in storage.
+	BatchUpsertOccurrences(ctx context.Context, projectID string, userID string, occs []*gpb.Occurrence) ([]*gpb.Occurrence, []error)
+}
+
+// Watch provides the changes storage makes to occurrences.
+type Watch interface {
+	// WatchOccurrences calls fn, in order, with the changes to occurrences matching the filter in
+	// the specified project made after cursor, or after the call if cursor is empty, until ctx is
+	// done or fn returns an error. It fails with an OutOfRange error if the cursor has expired.
+	WatchOccurrences(ctx context.Context, projectID, filter, cursor string, fn func(*watchpb.OccurrenceEvent) error) error
+}
+
+// ProjectStorage provides storage functions for projects.
+type ProjectStorage interface {
+	// CreateProject creates the specified project in storage.
+	CreateProject(ctx context.Context, projectID string, p *prpb.Project) (*prpb.Project, error)
+	// GetProject gets the specified project from storage.
+	GetProject(ctx context.Context, projectID string) (*prpb.Project, error)
+	// ListProjects lists projects from storage.
+	ListProjects(ctx context.Context, filter, pageToken string, pageSize int32) ([]*prpb.Project, string, error)
+	// DeleteProject deletes the specified project in storage.
+	DeleteProject(ctx context.Context, projectID string) error
+}
+
+// Operations provides storage functions for the long-running operations of this API.
+type Operations interface {
+	// CreateOperation creates the specified operation in storage.
+	CreateOperation(ctx context.Context, projectID string, op *lrpb.Operation) error
+	// UpdateOperation updates the operation with the same name as the specified one in storage. An
+	// operation that is done, e.g. because it was cancelled, is final: updating it must fail with a
+	// FailedPrecondition error without modifying it.
+	UpdateOperation(ctx context.Context, projectID string, op *lrpb.Operation) error
+}
+
+// IAM stores the IAM policies of projects and notes, which are identified by the resource names of
+// the projects and notes.
+type IAM interface {
+	// GetIamPolicy gets the policy of the specified resource, or an empty policy if it has none.
+	GetIamPolicy(ctx context.Context, resource string) (*iampb.Policy, error)
+	// SetIamPolicy replaces the policy of the specified resource and returns the new policy. If the
+	// etag of the specified policy is set and doesn't match the etag of the current policy, the call
+	// must fail with an Aborted error without modifying anything.
+	SetIamPolicy(ctx context.Context, resource string, p *iampb.Policy) (*iampb.Policy, error)
+}
+
+// Audit stores the audit log of the calls of this API that mutated notes, occurrences and IAM
+// policies. The log of a project is append-only.
+type Audit interface {
+	// RecordAuditEvent appends the specified event to the audit log of the specified project.
+	RecordAuditEvent(ctx context.Context, projectID string, e *auditpb.AuditEvent) error
+	// ListAuditEvents lists the events of the audit log of the specified project in the order they
+	// were recorded. Only the events about resource are listed if it is set, and only those
+	// recorded in [start, end) if the times are set.
+	ListAuditEvents(ctx context.Context, projectID, resource string, start, end time.Time, pageToken string, pageSize int32) ([]*auditpb.AuditEvent, string, error)
+}
+
+// Revisions stores the revisions notes and occurrences had before each of their updates. Storage
+// records them as it updates notes and occurrences.
+type Revisions interface {
+	// ListNoteRevisions lists the previous revisions of the specified note, newest first.
+	ListNoteRevisions(ctx context.Context, projectID, noteID, pageToken string, pageSize int32) ([]*revpb.NoteRevision, string, error)
+	// ListOccurrenceRevisions lists the previous revisions of the specified occurrence, newest
+	// first.
+	ListOccurrenceRevisions(ctx context.Context, projectID, occID, pageToken string, pageSize int32) ([]*revpb.OccurrenceRevision, string, error)
+	// GetNoteAt gets the specified note as it was at time t, or a NotFound error if it didn't exist
+	// then.
+	GetNoteAt(ctx context.Context, projectID, noteID string, t time.Time) (*gpb.Note, error)
+}
+
+// Tombstones keeps the projects and notes Storage deletes, hidden, until they are purged so that they
+// can be undeleted in the meantime.
+type Tombstones interface {
+	// UndeleteProject restores the specified project if it was deleted at or after deletedAfter, or
+	// fails with a NotFound error.
+	UndeleteProject(ctx context.Context, projectID string, deletedAfter time.Time) error
+	// UndeleteNote restores the specified note if it was deleted at or after deletedAfter and
+	// returns it, or fails with a NotFound error.
+	UndeleteNote(ctx context.Context, projectID, noteID string, deletedAfter time.Time) (*gpb.Note, error)
+	// PurgeDeleted permanently deletes the projects and notes deleted before the specified time and
+	// returns their resource names.
+	PurgeDeleted(ctx context.Context, before time.Time) ([]string, error)
+}
+
 // Auth provides authorization functions for this API.
 type Auth interface {
 	// CheckAccessAndProject checks to see whether an API call is allowed. It can check things like
@@ -138,6 +280,36 @@ type API struct {
 	Filter            Filter
 	Logger            Logger
 	EnforceValidation bool
+	// Projects stores projects. If nil, projects can't be created, read or deleted through this API.
+	Projects ProjectStorage
+	// Upserts upserts occurrences. If nil, occurrences can't be upserted.
+	Upserts Upserts
+	// Watch provides the changes to occurrences. If nil, occurrences can't be watched.
+	Watch Watch
+	// Operations stores the long-running operations of bulk methods. If nil, bulk methods always
+	// complete within the call.
+	Operations Operations
+	// ImportDir is the directory holding the files ImportNotes and ImportOccurrences may import. If
+	// empty, only uploaded data can be imported.
+	ImportDir string
+	// MaxUploadSize is the size in bytes of the largest upload ImportNotes and ImportOccurrences
+	// accept. If 0, uploads of up to 1 GiB are accepted.
+	MaxUploadSize int64
+	// IAM stores the IAM policies of projects and notes. If nil, their policies can't be read or
+	// set.
+	IAM IAM
+	// Audit stores the audit log of the calls that mutate notes, occurrences and IAM policies. If
+	// nil, calls aren't audited.
+	Audit Audit
+	// Revisions lists the previous revisions of notes and occurrences. If nil, they can't be listed
+	// and notes can't be read as of a past time.
+	Revisions Revisions
+	// Tombstones undeletes projects and notes deleted within DeleteRetention and purges older ones.
+	// If nil, deletes are final and the IAM policies of notes are purged as they are deleted;
+	// otherwise policies are purged along with their projects and notes by PurgeDeleted.
+	Tombstones Tombstones
+	// DeleteRetention is how long deleted projects and notes can be undeleted if Tombstones is set.
+	DeleteRetention time.Duration
 }
 
 // validatePageSize returns the default page size if the specified page size is 0, otherwise it
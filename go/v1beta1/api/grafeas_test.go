@@ -16,16 +16,28 @@ package grafeas
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/uuid"
+	"github.com/grafeas/grafeas/go/etag"
 	"github.com/grafeas/grafeas/go/iam"
 	"github.com/grafeas/grafeas/go/name"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
 	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
 	provpb "github.com/grafeas/grafeas/proto/v1beta1/provenance_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
 	vulnpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
 	"golang.org/x/net/context"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
 	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -39,17 +51,20 @@ type fakeStorage struct {
 	notes map[string]map[string]*gpb.Note
 	// Map of project IDs to a map of occurrence IDs to their occurrence.
 	occurrences map[string]map[string]*gpb.Occurrence
+	// Map of project IDs to the occurrence events a watch replays.
+	events map[string][]*watchpb.OccurrenceEvent
 
 	// The following errors are for simulating an internal database error.
 	getOccErr, listOccsErr, createOccErr, batchCreateOccsErr, updateOccErr, deleteOccErr       bool
 	getNoteErr, listNotesErr, createNoteErr, batchCreateNotesErr, updateNoteErr, deleteNoteErr bool
-	getOccNoteErr, listNoteOccsErr, getVulnSummaryErr                                          bool
+	getOccNoteErr, listNoteOccsErr, getVulnSummaryErr, watchOccsErr                            bool
 }
 
 func newFakeStorage() *fakeStorage {
 	return &fakeStorage{
 		notes:       map[string]map[string]*gpb.Note{},
 		occurrences: map[string]map[string]*gpb.Occurrence{},
+		events:      map[string][]*watchpb.OccurrenceEvent{},
 	}
 }
 
@@ -142,7 +157,45 @@ func (s *fakeStorage) BatchCreateOccurrences(ctx context.Context, pID string, us
 	return created, errs
 }
 
-func (s *fakeStorage) UpdateOccurrence(ctx context.Context, pID, oID string, o *gpb.Occurrence, mask *fieldmaskpb.FieldMask) (*gpb.Occurrence, error) {
+func (s *fakeStorage) UpsertOccurrence(ctx context.Context, pID string, userID string, o *gpb.Occurrence) (*gpb.Occurrence, error) {
+	if s.createOccErr {
+		return nil, status.Errorf(codes.Internal, "failed to upsert occurrence %+v", o)
+	}
+
+	for oID, existing := range s.occurrences[pID] {
+		if existing.GetResource().GetUri() == o.GetResource().GetUri() && existing.NoteName == o.NoteName {
+			o = proto.Clone(o).(*gpb.Occurrence)
+			o.Name = name.FormatOccurrence(pID, oID)
+			o.CreateTime = existing.CreateTime
+			o.UpdateTime = ptypes.TimestampNow()
+			s.occurrences[pID][oID] = o
+			return o, nil
+		}
+	}
+
+	return s.CreateOccurrence(ctx, pID, userID, o)
+}
+
+func (s *fakeStorage) BatchUpsertOccurrences(ctx context.Context, pID string, userID string, occs []*gpb.Occurrence) ([]*gpb.Occurrence, []error) {
+	errs := []error{}
+	upserted := []*gpb.Occurrence{}
+	for _, o := range occs {
+		if s.batchCreateOccsErr {
+			errs = append(errs, status.Errorf(codes.Internal, "failed to upsert occurrence %+v", o))
+			continue
+		}
+		o, err := s.UpsertOccurrence(ctx, pID, userID, o)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		upserted = append(upserted, o)
+	}
+
+	return upserted, errs
+}
+
+func (s *fakeStorage) UpdateOccurrence(ctx context.Context, pID, oID string, o *gpb.Occurrence, mask *fieldmaskpb.FieldMask, etag string) (*gpb.Occurrence, error) {
 	o = proto.Clone(o).(*gpb.Occurrence)
 
 	if s.updateOccErr {
@@ -154,9 +207,13 @@ func (s *fakeStorage) UpdateOccurrence(ctx context.Context, pID, oID string, o *
 		s.occurrences[pID] = map[string]*gpb.Occurrence{}
 	}
 
-	if _, ok := s.occurrences[pID][oID]; !ok {
+	existing, ok := s.occurrences[pID][oID]
+	if !ok {
 		return nil, status.Errorf(codes.NotFound, "occurrence %q not found", oID)
 	}
+	if err := checkETag(existing, etag); err != nil {
+		return nil, err
+	}
 
 	s.occurrences[pID][oID] = o
 	o.Name = name.FormatOccurrence(pID, oID)
@@ -164,7 +221,7 @@ func (s *fakeStorage) UpdateOccurrence(ctx context.Context, pID, oID string, o *
 	return o, nil
 }
 
-func (s *fakeStorage) DeleteOccurrence(ctx context.Context, pID, oID string) error {
+func (s *fakeStorage) DeleteOccurrence(ctx context.Context, pID, oID, etag string) error {
 	if s.deleteOccErr {
 		return status.Errorf(codes.Internal, "failed to delete occurrence %q", oID)
 	}
@@ -174,15 +231,33 @@ func (s *fakeStorage) DeleteOccurrence(ctx context.Context, pID, oID string) err
 		s.occurrences[pID] = map[string]*gpb.Occurrence{}
 	}
 
-	if _, ok := s.occurrences[pID][oID]; !ok {
+	existing, ok := s.occurrences[pID][oID]
+	if !ok {
 		return status.Errorf(codes.NotFound, "occurrence %q not found", oID)
 	}
+	if err := checkETag(existing, etag); err != nil {
+		return err
+	}
 
 	delete(s.occurrences[pID], oID)
 
 	return nil
 }
 
+func (s *fakeStorage) WatchOccurrences(ctx context.Context, pID, filter, cursor string, fn func(*watchpb.OccurrenceEvent) error) error {
+	if s.watchOccsErr {
+		return status.Errorf(codes.Internal, "failed to watch occurrences for project %q", pID)
+	}
+
+	for _, e := range s.events[pID] {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *fakeStorage) GetNote(ctx context.Context, pID, nID string) (*gpb.Note, error) {
 	if s.getNoteErr {
 		return nil, status.Errorf(codes.Internal, "failed to get note %q", nID)
@@ -279,7 +354,7 @@ func (s *fakeStorage) BatchCreateNotes(ctx context.Context, pID string, uID stri
 	return created, errs
 }
 
-func (s *fakeStorage) UpdateNote(ctx context.Context, pID, nID string, n *gpb.Note, mask *fieldmaskpb.FieldMask) (*gpb.Note, error) {
+func (s *fakeStorage) UpdateNote(ctx context.Context, pID, nID string, n *gpb.Note, mask *fieldmaskpb.FieldMask, etag string) (*gpb.Note, error) {
 	n = proto.Clone(n).(*gpb.Note)
 
 	if s.updateNoteErr {
@@ -291,9 +366,13 @@ func (s *fakeStorage) UpdateNote(ctx context.Context, pID, nID string, n *gpb.No
 		s.notes[pID] = map[string]*gpb.Note{}
 	}
 
-	if _, ok := s.notes[pID][nID]; !ok {
+	existing, ok := s.notes[pID][nID]
+	if !ok {
 		return nil, status.Errorf(codes.NotFound, "note %q not found", nID)
 	}
+	if err := checkETag(existing, etag); err != nil {
+		return nil, err
+	}
 
 	s.notes[pID][nID] = n
 	n.Name = name.FormatNote(pID, nID)
@@ -301,7 +380,7 @@ func (s *fakeStorage) UpdateNote(ctx context.Context, pID, nID string, n *gpb.No
 	return n, nil
 }
 
-func (s *fakeStorage) DeleteNote(ctx context.Context, pID, nID string) error {
+func (s *fakeStorage) DeleteNote(ctx context.Context, pID, nID, etag string) error {
 	if s.deleteNoteErr {
 		return status.Errorf(codes.Internal, "failed to delete note %q", nID)
 	}
@@ -311,9 +390,13 @@ func (s *fakeStorage) DeleteNote(ctx context.Context, pID, nID string) error {
 		s.notes[pID] = map[string]*gpb.Note{}
 	}
 
-	if _, ok := s.notes[pID][nID]; !ok {
+	existing, ok := s.notes[pID][nID]
+	if !ok {
 		return status.Errorf(codes.NotFound, "note %q not found", nID)
 	}
+	if err := checkETag(existing, etag); err != nil {
+		return err
+	}
 
 	delete(s.notes[pID], nID)
 
@@ -415,13 +498,27 @@ func (s *fakeStorage) GetVulnerabilityOccurrencesSummary(ctx context.Context, pr
 	}, nil
 }
 
+// checkETag verifies the stored entity against the etag expected by the caller, the same way a real
+// storage implementation would.
+func checkETag(stored proto.Message, want string) error {
+	return etag.Check(stored, want)
+}
+
 type fakeAuth struct {
+	mu sync.Mutex
 	// Whether auth calls return an error to exercise err code paths.
 	authErr, endUserIDErr, purgeErr bool
+	// Resource types and names of the entities whose policies were purged, in the form of
+	// "notes projects/[PROJECT_ID]/[ENTITY_ID]".
+	purged []string
+	// IDs of the entities access checks are denied for.
+	deniedEntities map[string]bool
+	// Permissions access checks are denied for.
+	deniedPermissions map[iam.Permission]bool
 }
 
 func (a *fakeAuth) CheckAccessAndProject(ctx context.Context, projectID string, entityID string, p iam.Permission) error {
-	if a.authErr {
+	if a.authErr || a.deniedEntities[entityID] || a.deniedPermissions[p] {
 		return status.Errorf(codes.PermissionDenied, "permission %q denied for %q or %q", p, projectID, entityID)
 	}
 	return nil
@@ -438,6 +535,277 @@ func (a *fakeAuth) PurgePolicy(ctx context.Context, projectID string, entityID s
 	if a.purgeErr {
 		return status.Errorf(codes.Internal, "failed to purge policy for entity ID %q of resource type %q", entityID, r)
 	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.purged = append(a.purged, fmt.Sprintf("%s %s/%s", r, name.FormatProject(projectID), entityID))
+	return nil
+}
+
+// fakeIAM implements the Grafeas IAM interface using an in-memory map for tests.
+type fakeIAM struct {
+	// Map of resource names to their policy.
+	policies map[string]*iampb.Policy
+	// Number of policies set so far, policies are tagged with it.
+	sets int
+}
+
+func newFakeIAM() *fakeIAM {
+	return &fakeIAM{policies: map[string]*iampb.Policy{}}
+}
+
+func (f *fakeIAM) GetIamPolicy(ctx context.Context, resource string) (*iampb.Policy, error) {
+	if p, ok := f.policies[resource]; ok {
+		return p, nil
+	}
+	return &iampb.Policy{}, nil
+}
+
+func (f *fakeIAM) SetIamPolicy(ctx context.Context, resource string, p *iampb.Policy) (*iampb.Policy, error) {
+	existing, _ := f.GetIamPolicy(ctx, resource)
+	if len(p.Etag) > 0 && string(p.Etag) != string(existing.Etag) {
+		return nil, status.Errorf(codes.Aborted, "etag %q does not match %q", p.Etag, existing.Etag)
+	}
+	f.sets++
+	p = proto.Clone(p).(*iampb.Policy)
+	p.Etag = []byte(fmt.Sprint(f.sets))
+	f.policies[resource] = p
+	return p, nil
+}
+
+// fakeOperations implements the Grafeas operations storage interface using an in-memory map for
+// tests.
+type fakeOperations struct {
+	mu  sync.Mutex
+	ops map[string]*lrpb.Operation
+	// Closed when an operation is updated to done.
+	done chan struct{}
+	// Whether operations are cancelled as soon as they are created.
+	cancel bool
+	// Closed when an update of an operation that is done is rejected.
+	rejected chan struct{}
+}
+
+func newFakeOperations() *fakeOperations {
+	return &fakeOperations{
+		ops:      map[string]*lrpb.Operation{},
+		done:     make(chan struct{}),
+		rejected: make(chan struct{}),
+	}
+}
+
+func (o *fakeOperations) CreateOperation(ctx context.Context, pID string, op *lrpb.Operation) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.ops[op.Name]; ok {
+		return status.Errorf(codes.AlreadyExists, "operation %q already exists", op.Name)
+	}
+	op = proto.Clone(op).(*lrpb.Operation)
+	if o.cancel {
+		op.Done = true
+		op.Result = &lrpb.Operation_Error{Error: &spb.Status{Code: int32(codes.Canceled)}}
+	}
+	o.ops[op.Name] = op
+	return nil
+}
+
+func (o *fakeOperations) UpdateOperation(ctx context.Context, pID string, op *lrpb.Operation) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	old, ok := o.ops[op.Name]
+	if !ok {
+		return status.Errorf(codes.NotFound, "operation %q not found", op.Name)
+	}
+	if old.Done {
+		close(o.rejected)
+		return status.Errorf(codes.FailedPrecondition, "operation %q is done", op.Name)
+	}
+	o.ops[op.Name] = proto.Clone(op).(*lrpb.Operation)
+	if op.Done {
+		close(o.done)
+	}
+	return nil
+}
+
+func (o *fakeOperations) get(name string) *lrpb.Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.ops[name]
+}
+
+// fakeAudit implements the Grafeas audit interface using an in-memory map for tests. Page tokens
+// aren't supported.
+type fakeAudit struct {
+	mu sync.Mutex
+	// Map of project IDs to their events.
+	events map[string][]*auditpb.AuditEvent
+	// Whether audit calls return an error to exercise err code paths.
+	recordErr, listErr bool
+}
+
+func (a *fakeAudit) RecordAuditEvent(ctx context.Context, pID string, e *auditpb.AuditEvent) error {
+	if a.recordErr {
+		return status.Errorf(codes.Internal, "failed to record audit event %q", e.Name)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.events == nil {
+		a.events = map[string][]*auditpb.AuditEvent{}
+	}
+	a.events[pID] = append(a.events[pID], proto.Clone(e).(*auditpb.AuditEvent))
+	return nil
+}
+
+func (a *fakeAudit) ListAuditEvents(ctx context.Context, pID, resource string, start, end time.Time, pageToken string, pageSize int32) ([]*auditpb.AuditEvent, string, error) {
+	if a.listErr {
+		return nil, "", status.Errorf(codes.Internal, "failed to list audit events")
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var events []*auditpb.AuditEvent
+	for _, e := range a.events[pID] {
+		if resource != "" && e.Resource != resource {
+			continue
+		}
+		t, _ := ptypes.Timestamp(e.EventTime)
+		if (!start.IsZero() && t.Before(start)) || (!end.IsZero() && !t.Before(end)) {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, "", nil
+}
+
+// fakeRevisions implements the revisions interface with fixed revisions for tests. Page tokens
+// aren't supported.
+type fakeRevisions struct {
+	// Map of note names to their revisions, newest first.
+	notes map[string][]*revpb.NoteRevision
+	// Map of occurrence names to their revisions, newest first.
+	occurrences map[string][]*revpb.OccurrenceRevision
+	// Whether revisions calls return an error to exercise err code paths.
+	err bool
+}
+
+func (r *fakeRevisions) ListNoteRevisions(ctx context.Context, pID, nID, pageToken string, pageSize int32) ([]*revpb.NoteRevision, string, error) {
+	if r.err {
+		return nil, "", status.Errorf(codes.Internal, "failed to list note revisions")
+	}
+	return r.notes[name.FormatNote(pID, nID)], "", nil
+}
+
+func (r *fakeRevisions) ListOccurrenceRevisions(ctx context.Context, pID, oID, pageToken string, pageSize int32) ([]*revpb.OccurrenceRevision, string, error) {
+	if r.err {
+		return nil, "", status.Errorf(codes.Internal, "failed to list occurrence revisions")
+	}
+	return r.occurrences[name.FormatOccurrence(pID, oID)], "", nil
+}
+
+// GetNoteAt returns the oldest revision of the note replaced after t. The current revision isn't
+// known, so it returns a NotFound error if there is none.
+func (r *fakeRevisions) GetNoteAt(ctx context.Context, pID, nID string, t time.Time) (*gpb.Note, error) {
+	if r.err {
+		return nil, status.Errorf(codes.Internal, "failed to get note revision")
+	}
+	var at *gpb.Note
+	for _, rev := range r.notes[name.FormatNote(pID, nID)] {
+		rt, err := ptypes.Timestamp(rev.ReplaceTime)
+		if err != nil || !rt.After(t) {
+			break
+		}
+		at = rev.Note
+	}
+	if at == nil {
+		return nil, status.Errorf(codes.NotFound, "note %q has no revision at %v", nID, t)
+	}
+	return at, nil
+}
+
+// fakeTombstones implements the Grafeas tombstones interface with fixed deleted projects and notes
+// for tests.
+type fakeTombstones struct {
+	// Map of the names of deleted projects and notes to their delete times.
+	deleted map[string]time.Time
+	// Map of the names of deleted notes to the notes.
+	notes map[string]*gpb.Note
+	// Whether tombstones calls return an error to exercise err code paths.
+	err bool
+}
+
+func (d *fakeTombstones) UndeleteProject(ctx context.Context, pID string, deletedAfter time.Time) error {
+	return d.undelete(name.FormatProject(pID), deletedAfter)
+}
+
+func (d *fakeTombstones) UndeleteNote(ctx context.Context, pID, nID string, deletedAfter time.Time) (*gpb.Note, error) {
+	nName := name.FormatNote(pID, nID)
+	if err := d.undelete(nName, deletedAfter); err != nil {
+		return nil, err
+	}
+	return d.notes[nName], nil
+}
+
+func (d *fakeTombstones) undelete(name string, deletedAfter time.Time) error {
+	if d.err {
+		return status.Errorf(codes.Internal, "failed to undelete %q", name)
+	}
+	t, ok := d.deleted[name]
+	if !ok || t.Before(deletedAfter) {
+		return status.Errorf(codes.NotFound, "%q is not deleted", name)
+	}
+	delete(d.deleted, name)
+	return nil
+}
+
+func (d *fakeTombstones) PurgeDeleted(ctx context.Context, before time.Time) ([]string, error) {
+	if d.err {
+		return nil, status.Errorf(codes.Internal, "failed to purge deleted projects and notes")
+	}
+	var purged []string
+	for name, t := range d.deleted {
+		if t.Before(before) {
+			purged = append(purged, name)
+			delete(d.deleted, name)
+		}
+	}
+	sort.Strings(purged)
+	return purged, nil
+}
+
+// fakeProjects implements the Grafeas project storage interface using an in-memory map for tests.
+type fakeProjects struct {
+	// Map of project IDs to projects.
+	projects map[string]*prpb.Project
+}
+
+func (s *fakeProjects) CreateProject(ctx context.Context, pID string, p *prpb.Project) (*prpb.Project, error) {
+	if _, ok := s.projects[pID]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "project %q already exists", pID)
+	}
+	s.projects[pID] = p
+	return p, nil
+}
+
+func (s *fakeProjects) GetProject(ctx context.Context, pID string) (*prpb.Project, error) {
+	p, ok := s.projects[pID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "project %q not found", pID)
+	}
+	return p, nil
+}
+
+func (s *fakeProjects) ListProjects(ctx context.Context, filter, pageToken string, pageSize int32) ([]*prpb.Project, string, error) {
+	var projects []*prpb.Project
+	for _, p := range s.projects {
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects, "", nil
+}
+
+func (s *fakeProjects) DeleteProject(ctx context.Context, pID string) error {
+	if _, ok := s.projects[pID]; !ok {
+		return status.Errorf(codes.NotFound, "project %q not found", pID)
+	}
+	delete(s.projects, pID)
 	return nil
 }
 
@@ -0,0 +1,176 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"strings"
+
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/iam"
+	"github.com/grafeas/grafeas/go/name"
+	"golang.org/x/net/context"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxTestPermissions is the number of permissions TestIamPermissions tests at most.
+const maxTestPermissions = 100
+
+// GetIamPolicy gets the IAM policy of the specified project or note.
+func (g *API) GetIamPolicy(ctx context.Context, req *iampb.GetIamPolicyRequest, resp *iampb.Policy) error {
+	pID, nID, err := parseIamResource(req.Resource)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	perm := ProjectsGetIamPolicy
+	if nID != "" {
+		perm = NotesGetIamPolicy
+	}
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, nID, perm); err != nil {
+		return err
+	}
+
+	resource, err := g.iamResource(ctx, pID, nID)
+	if err != nil {
+		return err
+	}
+	p, err := g.IAM.GetIamPolicy(ctx, resource)
+	if err != nil {
+		return err
+	}
+	*resp = *p
+
+	return nil
+}
+
+// SetIamPolicy replaces the IAM policy of the specified project or note.
+func (g *API) SetIamPolicy(ctx context.Context, req *iampb.SetIamPolicyRequest, resp *iampb.Policy) error {
+	pID, nID, err := parseIamResource(req.Resource)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	perm := ProjectsSetIamPolicy
+	if nID != "" {
+		perm = NotesSetIamPolicy
+	}
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, nID, perm); err != nil {
+		return err
+	}
+
+	if req.Policy == nil {
+		return errors.Newf(codes.InvalidArgument, "a policy must be specified")
+	}
+	for i, b := range req.Policy.Bindings {
+		if b.Role == "" {
+			return errors.Newf(codes.InvalidArgument, "binding %d: a role must be specified", i)
+		}
+		if len(b.Members) == 0 {
+			return errors.Newf(codes.InvalidArgument, "binding %d: members must be specified", i)
+		}
+	}
+
+	resource, err := g.iamResource(ctx, pID, nID)
+	if err != nil {
+		return err
+	}
+	var before *iampb.Policy
+	if g.Audit != nil {
+		if before, err = g.IAM.GetIamPolicy(ctx, resource); err != nil {
+			return err
+		}
+	}
+	p, err := g.IAM.SetIamPolicy(ctx, resource, req.Policy)
+	if err != nil {
+		return err
+	}
+	g.audit(ctx, pID, "", "SetIamPolicy", resource, nil, before, p)
+	*resp = *p
+
+	return nil
+}
+
+// TestIamPermissions returns the subset of the specified permissions the caller has on the
+// specified project or note. Permissions on notes are those on the notes resource type.
+func (g *API) TestIamPermissions(ctx context.Context, req *iampb.TestIamPermissionsRequest, resp *iampb.TestIamPermissionsResponse) error {
+	pID, nID, err := parseIamResource(req.Resource)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if len(req.Permissions) > maxTestPermissions {
+		return errors.Newf(codes.InvalidArgument, "%d permissions cannot be tested at once, the max is %d", len(req.Permissions), maxTestPermissions)
+	}
+	for _, p := range req.Permissions {
+		if strings.Contains(p, "*") || !strings.Contains(p, ".") {
+			return errors.Newf(codes.InvalidArgument, "invalid permission %q", p)
+		}
+		if nID != "" && !strings.HasPrefix(p, string(Notes)+".") {
+			return errors.Newf(codes.InvalidArgument, "permission %q doesn't apply to notes", p)
+		}
+	}
+
+	var granted []string
+	for _, p := range req.Permissions {
+		err := g.Auth.CheckAccessAndProject(ctx, pID, nID, iam.Permission(p))
+		switch status.Code(err) {
+		case codes.OK:
+			granted = append(granted, p)
+		case codes.PermissionDenied:
+		default:
+			return err
+		}
+	}
+	resp.Permissions = granted
+
+	return nil
+}
+
+// iamResource returns the resource name of the policy of the specified project, or note if nID is
+// set, after checking that policies can be stored and that the note exists.
+func (g *API) iamResource(ctx context.Context, pID, nID string) (string, error) {
+	if g.IAM == nil {
+		return "", errors.Newf(codes.Unimplemented, "IAM policies are not supported")
+	}
+	if nID == "" {
+		return name.FormatProject(pID), nil
+	}
+	if _, err := g.Storage.GetNote(ctx, pID, nID); err != nil {
+		return "", err
+	}
+	return name.FormatNote(pID, nID), nil
+}
+
+// parseIamResource parses the project ID and, for a note, the note ID from the resource name of an
+// IAM policy.
+func parseIamResource(resource string) (string, string, error) {
+	if strings.Count(resource, "/") == 1 {
+		pID, err := name.ParseProject(resource)
+		return pID, "", err
+	}
+	pID, nID, err := name.ParseNote(resource)
+	if err != nil {
+		return "", "", errors.Newf(codes.InvalidArgument, "resource must be in the form 'projects/[PROJECT_ID]' or 'projects/[PROJECT_ID]/notes/[NOTE_ID]', got %q", resource)
+	}
+	return pID, nID, nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafeas/grafeas/go/iam"
+	"golang.org/x/net/context"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetGetIamPolicy(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+		IAM:               newFakeIAM(),
+	}
+	if _, err := s.CreateNote(ctx, "goog-vulnz", "CVE-UH-OH", "", vulnzNote(t)); err != nil {
+		t.Fatalf("Failed to create note: %v", err)
+	}
+
+	for _, resource := range []string{"projects/goog-vulnz", "projects/goog-vulnz/notes/CVE-UH-OH"} {
+		p := &iampb.Policy{
+			Bindings: []*iampb.Binding{{Role: "roles/attestor", Members: []string{"allAuthenticatedUsers"}}},
+		}
+		set := &iampb.Policy{}
+		if err := g.SetIamPolicy(ctx, &iampb.SetIamPolicyRequest{Resource: resource, Policy: p}, set); err != nil {
+			t.Fatalf("SetIamPolicy(%q) got err %v, want success", resource, err)
+		}
+		if len(set.Etag) == 0 {
+			t.Errorf("SetIamPolicy(%q) got policy without etag", resource)
+		}
+
+		got := &iampb.Policy{}
+		if err := g.GetIamPolicy(ctx, &iampb.GetIamPolicyRequest{Resource: resource}, got); err != nil {
+			t.Fatalf("GetIamPolicy(%q) got err %v, want success", resource, err)
+		}
+		if !proto.Equal(got, set) {
+			t.Errorf("GetIamPolicy(%q) got %v, want %v", resource, got, set)
+		}
+
+		// Setting a policy with a stale etag fails.
+		p.Etag = []byte("stale")
+		err := g.SetIamPolicy(ctx, &iampb.SetIamPolicyRequest{Resource: resource, Policy: p}, &iampb.Policy{})
+		if c := status.Code(err); c != codes.Aborted {
+			t.Errorf("SetIamPolicy(%q) with stale etag got err %v, want %v", resource, err, codes.Aborted)
+		}
+	}
+}
+
+func TestIamPolicyErrors(t *testing.T) {
+	ctx := context.Background()
+	valid := &iampb.Policy{
+		Bindings: []*iampb.Binding{{Role: "roles/viewer", Members: []string{"alice"}}},
+	}
+
+	tests := []struct {
+		desc          string
+		resource      string
+		policy        *iampb.Policy
+		authErr       bool
+		noIAM         bool
+		wantErrStatus codes.Code
+	}{
+		{
+			desc:          "invalid resource",
+			resource:      "projects/goog-vulnz/occurrences/1234",
+			policy:        valid,
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "auth error",
+			resource:      "projects/goog-vulnz",
+			policy:        valid,
+			authErr:       true,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc:          "IAM not supported",
+			resource:      "projects/goog-vulnz",
+			policy:        valid,
+			noIAM:         true,
+			wantErrStatus: codes.Unimplemented,
+		},
+		{
+			desc:          "note doesn't exist, not found error",
+			resource:      "projects/goog-vulnz/notes/CVE-UH-HUH",
+			policy:        valid,
+			wantErrStatus: codes.NotFound,
+		},
+		{
+			desc:          "nil policy",
+			resource:      "projects/goog-vulnz",
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "binding without members",
+			resource:      "projects/goog-vulnz",
+			policy:        &iampb.Policy{Bindings: []*iampb.Binding{{Role: "roles/viewer"}}},
+			wantErrStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		g := &API{
+			Storage:           newFakeStorage(),
+			Auth:              &fakeAuth{authErr: tt.authErr},
+			Filter:            &fakeFilter{},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+		}
+		if !tt.noIAM {
+			g.IAM = newFakeIAM()
+		}
+
+		err := g.SetIamPolicy(ctx, &iampb.SetIamPolicyRequest{Resource: tt.resource, Policy: tt.policy}, &iampb.Policy{})
+		t.Logf("%q: error: %v", tt.desc, err)
+		if status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: SetIamPolicy got error status %v, want %v", tt.desc, status.Code(err), tt.wantErrStatus)
+		}
+		// GetIamPolicy fails the same way, unless the error is about the policy.
+		if tt.policy != valid {
+			continue
+		}
+		err = g.GetIamPolicy(ctx, &iampb.GetIamPolicyRequest{Resource: tt.resource}, &iampb.Policy{})
+		if status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: GetIamPolicy got error status %v, want %v", tt.desc, status.Code(err), tt.wantErrStatus)
+		}
+	}
+}
+
+func TestTestIamPermissions(t *testing.T) {
+	ctx := context.Background()
+	g := &API{
+		Storage:           newFakeStorage(),
+		Auth:              &fakeAuth{deniedPermissions: map[iam.Permission]bool{NotesAttachOccurrence: true}},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+	}
+
+	tests := []struct {
+		desc          string
+		req           *iampb.TestIamPermissionsRequest
+		want          []string
+		wantErrStatus codes.Code
+	}{
+		{
+			desc: "project",
+			req: &iampb.TestIamPermissionsRequest{
+				Resource:    "projects/goog-vulnz",
+				Permissions: []string{"notes.get", "notes.attachOccurrence", "occurrences.list"},
+			},
+			want: []string{"notes.get", "occurrences.list"},
+		},
+		{
+			desc: "note",
+			req: &iampb.TestIamPermissionsRequest{
+				Resource:    "projects/goog-vulnz/notes/CVE-UH-OH",
+				Permissions: []string{"notes.attachOccurrence", "notes.listOccurrences"},
+			},
+			want: []string{"notes.listOccurrences"},
+		},
+		{
+			desc: "wildcard permission",
+			req: &iampb.TestIamPermissionsRequest{
+				Resource:    "projects/goog-vulnz",
+				Permissions: []string{"notes.*"},
+			},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc: "occurrence permission on note",
+			req: &iampb.TestIamPermissionsRequest{
+				Resource:    "projects/goog-vulnz/notes/CVE-UH-OH",
+				Permissions: []string{"occurrences.get"},
+			},
+			wantErrStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		resp := &iampb.TestIamPermissionsResponse{}
+		err := g.TestIamPermissions(ctx, tt.req, resp)
+		if status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: got error status %v, want %v", tt.desc, status.Code(err), tt.wantErrStatus)
+		}
+		if err == nil && !cmp.Equal(resp.Permissions, tt.want) {
+			t.Errorf("%q: got permissions %v, want %v", tt.desc, resp.Permissions, tt.want)
+		}
+	}
+}
@@ -0,0 +1,421 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/name"
+	"github.com/grafeas/grafeas/go/v1beta1/api/validators/grafeas"
+	bulkpb "github.com/grafeas/grafeas/proto/v1beta1/bulk_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"golang.org/x/net/context"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxRecordSize is the size in bytes of the largest note or occurrence an import accepts.
+	maxRecordSize = 4 << 20
+	// defaultMaxUploadSize is the size in bytes of the largest upload an import accepts if
+	// MaxUploadSize isn't set.
+	defaultMaxUploadSize = 1 << 30
+)
+
+// ImportNotes creates the notes of a file in ImportDir or of an upload in the specified project.
+// The upload is the data of req followed by that of upload, which may be nil. Each note must be
+// named in the project, and those that can't be created are reported as failures instead of
+// failing the call. If Operations is set, the notes are created in the background.
+func (g *API) ImportNotes(ctx context.Context, req *bulkpb.ImportNotesRequest, upload io.Reader, resp *lrpb.Operation) error {
+	pID, err := name.ParseProject(req.Parent)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, "", NotesCreate); err != nil {
+		return err
+	}
+
+	uID, err := g.Auth.EndUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	src, err := g.openImport(req.Format, req.GetPath(), req.GetData(), upload)
+	if err != nil {
+		return err
+	}
+	return g.runImport(ctx, pID, src, resp, func(ctx context.Context, rec []byte) (string, error) {
+		n := &gpb.Note{}
+		if err := unmarshalRecord(req.Format, rec, n); err != nil {
+			return "", err
+		}
+		notePID, nID, err := name.ParseNote(n.Name)
+		if err != nil {
+			return n.Name, err
+		}
+		if notePID != pID {
+			return n.Name, errors.Newf(codes.InvalidArgument, "note %q isn't in project %q", n.Name, pID)
+		}
+		if err := grafeas.ValidateNote(n); err != nil {
+			if g.EnforceValidation {
+				return n.Name, err
+			}
+			g.Logger.Warningf(ctx, "ImportNotes %+v for project %q: invalid note, fail open, would have failed with: %v", n, pID, err)
+		}
+		created, err := g.Storage.CreateNote(ctx, pID, nID, uID, n)
+		if err != nil {
+			return n.Name, err
+		}
+		g.audit(ctx, pID, uID, "ImportNotes", created.Name, nil, nil, created)
+		return n.Name, nil
+	})
+}
+
+// ImportOccurrences creates the occurrences of a file in ImportDir or of an upload in the specified
+// project, like ImportNotes creates notes. Each occurrence needs the same permissions as
+// CreateOccurrence, or as UpsertOccurrence if the request asks for occurrences to be upserted.
+func (g *API) ImportOccurrences(ctx context.Context, req *bulkpb.ImportOccurrencesRequest, upload io.Reader, resp *lrpb.Operation) error {
+	pID, err := name.ParseProject(req.Parent)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, "", OccurrencesCreate); err != nil {
+		return err
+	}
+	if req.Upsert {
+		if err := g.Auth.CheckAccessAndProject(ctx, pID, "", OccurrencesUpdate); err != nil {
+			return err
+		}
+		if g.Upserts == nil {
+			return errors.Newf(codes.Unimplemented, "upserting occurrences is not supported")
+		}
+	}
+
+	uID, err := g.Auth.EndUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	src, err := g.openImport(req.Format, req.GetPath(), req.GetData(), upload)
+	if err != nil {
+		return err
+	}
+	// Occurrences of the same note share the outcome of its permission check.
+	noteErrs := map[string]error{}
+	return g.runImport(ctx, pID, src, resp, func(ctx context.Context, rec []byte) (string, error) {
+		o := &gpb.Occurrence{}
+		if err := unmarshalRecord(req.Format, rec, o); err != nil {
+			return "", err
+		}
+		if _, _, err := name.ParseNote(o.NoteName); err != nil {
+			return o.Name, err
+		}
+		if err := g.checkAttachOccurrence(ctx, o.NoteName, noteErrs); err != nil {
+			return o.Name, err
+		}
+		if err := grafeas.ValidateOccurrence(o); err != nil {
+			if g.EnforceValidation {
+				return o.Name, err
+			}
+			g.Logger.Warningf(ctx, "ImportOccurrences %+v for project %q: invalid occurrence, fail open, would have failed with: %v", o, pID, err)
+		}
+		var created *gpb.Occurrence
+		if req.Upsert {
+			created, err = g.Upserts.UpsertOccurrence(ctx, pID, uID, o)
+		} else {
+			created, err = g.Storage.CreateOccurrence(ctx, pID, uID, o)
+		}
+		if err != nil {
+			return o.Name, err
+		}
+		g.audit(ctx, pID, uID, "ImportOccurrences", created.Name, nil, nil, created)
+		return o.Name, nil
+	})
+}
+
+// importSource is the data of an import.
+type importSource struct {
+	format bulkpb.ImportFormat
+	f      *os.File
+	size   int64
+	// temp is whether f is a spooled upload to remove once imported.
+	temp bool
+}
+
+// close closes the source, removing it if it is a spooled upload.
+func (s *importSource) close() {
+	s.f.Close()
+	if s.temp {
+		os.Remove(s.f.Name())
+	}
+}
+
+// openImport opens the file at path in ImportDir if path is set, or otherwise spools data followed
+// by upload to a temporary file, so that the import can outlive the call.
+func (g *API) openImport(format bulkpb.ImportFormat, path string, data []byte, upload io.Reader) (*importSource, error) {
+	switch format {
+	case bulkpb.ImportFormat_JSON_LINES, bulkpb.ImportFormat_LENGTH_DELIMITED:
+	case bulkpb.ImportFormat_IMPORT_FORMAT_UNSPECIFIED:
+		return nil, errors.Newf(codes.InvalidArgument, "an import format must be specified")
+	default:
+		return nil, errors.Newf(codes.InvalidArgument, "unknown import format %v", format)
+	}
+
+	if path != "" {
+		if g.ImportDir == "" {
+			return nil, errors.Newf(codes.FailedPrecondition, "importing files on the server isn't enabled")
+		}
+		if upload != nil {
+			if n, _ := upload.Read(make([]byte, 1)); n > 0 {
+				return nil, errors.Newf(codes.InvalidArgument, "data can't be uploaded along with a path")
+			}
+		}
+		// Cleaning the path as an absolute one keeps it within ImportDir.
+		f, err := os.Open(filepath.Join(g.ImportDir, filepath.Clean("/"+path)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, errors.Newf(codes.NotFound, "import file %q not found", path)
+			}
+			return nil, errors.Newf(codes.Internal, "failed to open import file %q: %v", path, err)
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, errors.Newf(codes.Internal, "failed to stat import file %q: %v", path, err)
+		}
+		return &importSource{format: format, f: f, size: fi.Size()}, nil
+	}
+
+	r := io.Reader(bytes.NewReader(data))
+	if upload != nil {
+		r = io.MultiReader(r, upload)
+	}
+	maxSize := g.MaxUploadSize
+	if maxSize == 0 {
+		maxSize = defaultMaxUploadSize
+	}
+	f, err := ioutil.TempFile("", "grafeas-import")
+	if err != nil {
+		return nil, errors.Newf(codes.Internal, "failed to create import file: %v", err)
+	}
+	src := &importSource{format: format, f: f, temp: true}
+	// Reading a byte past the max tells a larger upload from one of exactly the max size.
+	if src.size, err = io.Copy(f, io.LimitReader(r, maxSize+1)); err != nil {
+		src.close()
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, errors.Newf(codes.Internal, "failed to spool upload: %v", err)
+	}
+	if src.size > maxSize {
+		src.close()
+		return nil, errors.Newf(codes.InvalidArgument, "upload is larger than the max upload size of %d bytes", maxSize)
+	}
+	if src.size == 0 {
+		src.close()
+		return nil, errors.Newf(codes.InvalidArgument, "a path or data must be specified")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		src.close()
+		return nil, errors.Newf(codes.Internal, "failed to rewind upload: %v", err)
+	}
+	return src, nil
+}
+
+// runImport imports the records of src, in the background if Operations is set, and closes it.
+// importRecord imports a single record, returning the name of its note or occurrence if known.
+func (g *API) runImport(ctx context.Context, pID string, src *importSource, resp *lrpb.Operation, importRecord func(context.Context, []byte) (string, error)) error {
+	metadata := &bulkpb.ImportMetadata{
+		CreateTime: ptypes.TimestampNow(),
+		TotalBytes: src.size,
+	}
+	result := &bulkpb.ImportResponse{}
+	resp.Name = name.FormatOperation(pID, uuid.New().String())
+	if g.Operations == nil {
+		defer src.close()
+		importRecords(ctx, src, metadata, result, importRecord, nil)
+		metadata.EndTime = ptypes.TimestampNow()
+		return finishOperation(resp, metadata, result)
+	}
+
+	if err := setOperationMetadata(resp, metadata); err != nil {
+		src.close()
+		return err
+	}
+	if err := g.Operations.CreateOperation(ctx, pID, resp); err != nil {
+		src.close()
+		return err
+	}
+	// The import outlives the call, but has already been authorized.
+	bgCtx := g.Logger.PrepareCtx(context.Background(), pID)
+	op := proto.Clone(resp).(*lrpb.Operation)
+	go func() {
+		defer src.close()
+		if !importRecords(bgCtx, src, metadata, result, importRecord, func() bool {
+			return g.updateProgress(bgCtx, pID, op, metadata)
+		}) {
+			return
+		}
+		metadata.EndTime = ptypes.TimestampNow()
+		g.storeFinishedOperation(bgCtx, pID, op, metadata, result)
+	}()
+	return nil
+}
+
+// importRecords imports the records of src, recording failures in result and progress in metadata,
+// and calling progress, if set, after every maxBatchSize records. A record that can't be read ends
+// the import, as the records following it can't be found. It stops before the next batch if
+// progress returns false, and reports whether the import ran to its end.
+func importRecords(ctx context.Context, src *importSource, metadata *bulkpb.ImportMetadata, result *bulkpb.ImportResponse, importRecord func(context.Context, []byte) (string, error), progress func() bool) bool {
+	cr := &countingReader{r: src.f}
+	br := bufio.NewReader(cr)
+	for {
+		rec, err := nextRecord(br, src.format)
+		if err == io.EOF {
+			break
+		}
+		metadata.ProcessedCount++
+		readErr := err
+		if readErr != nil {
+			addImportFailure(result, metadata.ProcessedCount, "", readErr)
+		} else if n, err := importRecord(ctx, rec); err != nil {
+			addImportFailure(result, metadata.ProcessedCount, n, err)
+		} else {
+			result.ImportedCount++
+		}
+		metadata.ProcessedBytes = cr.n - int64(br.Buffered())
+		metadata.ImportedCount, metadata.FailedCount = result.ImportedCount, result.FailedCount
+		if readErr != nil {
+			return true
+		}
+		if progress != nil && metadata.ProcessedCount%maxBatchSize == 0 && !progress() {
+			return false
+		}
+	}
+	return true
+}
+
+// nextRecord returns the next record of r in the specified format, or io.EOF if there are no more.
+func nextRecord(r *bufio.Reader, format bulkpb.ImportFormat) ([]byte, error) {
+	if format == bulkpb.ImportFormat_JSON_LINES {
+		for {
+			line, err := readLine(r)
+			if err != nil && err != io.EOF {
+				if _, ok := status.FromError(err); ok {
+					return nil, err
+				}
+				return nil, errors.Newf(codes.Internal, "failed to read record: %v", err)
+			}
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				return line, nil
+			}
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+		}
+	}
+
+	size, err := binary.ReadUvarint(r)
+	switch {
+	case err == io.EOF:
+		return nil, io.EOF
+	case err == io.ErrUnexpectedEOF:
+		return nil, errors.Newf(codes.InvalidArgument, "truncated record length")
+	case err != nil:
+		return nil, errors.Newf(codes.InvalidArgument, "failed to read record length: %v", err)
+	case size > maxRecordSize:
+		return nil, errors.Newf(codes.InvalidArgument, "record of %d bytes is larger than the max record size of %d", size, maxRecordSize)
+	}
+	rec := make([]byte, size)
+	if _, err := io.ReadFull(r, rec); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, errors.Newf(codes.InvalidArgument, "truncated record of %d bytes", size)
+		}
+		return nil, errors.Newf(codes.Internal, "failed to read record: %v", err)
+	}
+	return rec, nil
+}
+
+// readLine reads the next line of r, including its newline, like r.ReadBytes('\n') but failing
+// with InvalidArgument once the line is larger than maxRecordSize.
+func readLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line)+len(bytes.TrimSuffix(chunk, []byte("\n"))) > maxRecordSize {
+			return nil, errors.Newf(codes.InvalidArgument, "record is larger than the max record size of %d", maxRecordSize)
+		}
+		line = append(line, chunk...)
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+	}
+}
+
+// unmarshalRecord unmarshals a record in the specified format into m.
+func unmarshalRecord(format bulkpb.ImportFormat, rec []byte, m proto.Message) error {
+	var err error
+	if format == bulkpb.ImportFormat_JSON_LINES {
+		err = jsonpb.Unmarshal(bytes.NewReader(rec), m)
+	} else {
+		err = proto.Unmarshal(rec, m)
+	}
+	if err != nil {
+		return errors.Newf(codes.InvalidArgument, "failed to parse record: %v", err)
+	}
+	return nil
+}
+
+// addImportFailure records that the specified record, named n if known, couldn't be imported.
+func addImportFailure(result *bulkpb.ImportResponse, record int32, n string, err error) {
+	result.FailedCount++
+	if len(result.Failures) < maxFailureSamples {
+		result.Failures = append(result.Failures, &bulkpb.ImportResponse_Failure{
+			Record: record,
+			Name:   n,
+			Status: status.Convert(err).Proto(),
+		})
+	}
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,423 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	bulkpb "github.com/grafeas/grafeas/proto/v1beta1/bulk_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"golang.org/x/net/context"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestImportNotes(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+	}
+
+	named := func(n *gpb.Note, name string) *gpb.Note {
+		n.Name = name
+		return n
+	}
+	lines := []string{
+		jsonLine(t, named(vulnzNote(t), "projects/consumer1/notes/CVE-1")),
+		"",
+		jsonLine(t, named(vulnzNote(t), "projects/consumer2/notes/CVE-2")),
+		jsonLine(t, named(invalidVulnzNote(t), "projects/consumer1/notes/CVE-3")),
+		"{not json",
+		jsonLine(t, named(vulnzNote(t), "projects/consumer1/notes/CVE-1")),
+		jsonLine(t, named(vulnzNote(t), "projects/consumer1/notes/CVE-4")),
+	}
+	data := strings.Join(lines, "\n")
+
+	// The upload spans the request and the reader.
+	req := &bulkpb.ImportNotesRequest{
+		Parent: "projects/consumer1",
+		Format: bulkpb.ImportFormat_JSON_LINES,
+		Source: &bulkpb.ImportNotesRequest_Data{Data: []byte(data[:10])},
+	}
+	op := &lrpb.Operation{}
+	if err := g.ImportNotes(ctx, req, strings.NewReader(data[10:]), op); err != nil {
+		t.Fatalf("Got err %v, want success", err)
+	}
+	if !op.Done {
+		t.Fatalf("Got operation %+v, want it done", op)
+	}
+	result := &bulkpb.ImportResponse{}
+	if err := ptypes.UnmarshalAny(op.GetResponse(), result); err != nil {
+		t.Fatalf("Failed to unmarshal operation response: %v", err)
+	}
+	if result.ImportedCount != 2 || result.FailedCount != 4 {
+		t.Errorf("Got imported/failed %d/%d, want 2/4", result.ImportedCount, result.FailedCount)
+	}
+	wantFailures := []struct {
+		record int32
+		code   codes.Code
+	}{
+		{2, codes.InvalidArgument},
+		{3, codes.InvalidArgument},
+		{4, codes.InvalidArgument},
+		{5, codes.AlreadyExists},
+	}
+	if len(result.Failures) != len(wantFailures) {
+		t.Fatalf("Got failures %+v, want %d", result.Failures, len(wantFailures))
+	}
+	for i, want := range wantFailures {
+		f := result.Failures[i]
+		if f.Record != want.record || codes.Code(f.Status.Code) != want.code {
+			t.Errorf("Got failure %+v, want record %d failing with %v", f, want.record, want.code)
+		}
+	}
+	metadata := &bulkpb.ImportMetadata{}
+	if err := ptypes.UnmarshalAny(op.Metadata, metadata); err != nil {
+		t.Fatalf("Failed to unmarshal operation metadata: %v", err)
+	}
+	if metadata.TotalBytes != int64(len(data)) || metadata.ProcessedBytes != metadata.TotalBytes || metadata.ProcessedCount != 6 || metadata.EndTime == nil {
+		t.Errorf("Got metadata %+v, want %d bytes and 6 records processed and an end time", metadata, len(data))
+	}
+	if got := len(s.notes["consumer1"]); got != 2 {
+		t.Errorf("Got %d notes, want 2", got)
+	}
+}
+
+func TestImportOccurrencesLongRunning(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	ops := newFakeOperations()
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{deniedEntities: map[string]bool{"CVE-DENIED": true}},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+		Operations:        ops,
+	}
+
+	num := maxBatchSize + 1
+	var buf bytes.Buffer
+	for _, o := range vulnzOccs(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "foobar", num) {
+		writeDelimited(t, &buf, o)
+	}
+	writeDelimited(t, &buf, vulnzOcc(t, "consumer1", "projects/goog-vulnz/notes/CVE-DENIED", "foobar"))
+	writeDelimited(t, &buf, vulnzOcc(t, "consumer1", "", "foobar"))
+	// A record that is cut short ends the import.
+	writeDelimited(t, &buf, vulnzOcc(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "foobar"))
+	data := buf.Bytes()[:buf.Len()-1]
+
+	req := &bulkpb.ImportOccurrencesRequest{Parent: "projects/consumer1", Format: bulkpb.ImportFormat_LENGTH_DELIMITED}
+	op := &lrpb.Operation{}
+	if err := g.ImportOccurrences(ctx, req, bytes.NewReader(data), op); err != nil {
+		t.Fatalf("Got err %v, want success", err)
+	}
+	metadata := &bulkpb.ImportMetadata{}
+	if err := ptypes.UnmarshalAny(op.Metadata, metadata); err != nil {
+		t.Fatalf("Failed to unmarshal operation metadata: %v", err)
+	}
+	if metadata.TotalBytes != int64(len(data)) {
+		t.Errorf("Got total bytes %d, want %d", metadata.TotalBytes, len(data))
+	}
+
+	<-ops.done
+	op = ops.get(op.Name)
+	result := &bulkpb.ImportResponse{}
+	if err := ptypes.UnmarshalAny(op.GetResponse(), result); err != nil {
+		t.Fatalf("Failed to unmarshal operation response: %v", err)
+	}
+	if result.ImportedCount != int32(num) || result.FailedCount != 3 {
+		t.Errorf("Got imported/failed %d/%d, want %d/3", result.ImportedCount, result.FailedCount, num)
+	}
+	wantCodes := []codes.Code{codes.PermissionDenied, codes.InvalidArgument, codes.InvalidArgument}
+	if len(result.Failures) != len(wantCodes) {
+		t.Fatalf("Got failures %+v, want %d", result.Failures, len(wantCodes))
+	}
+	for i, f := range result.Failures {
+		if want := int32(num + i + 1); f.Record != want || codes.Code(f.Status.Code) != wantCodes[i] {
+			t.Errorf("Got failure %+v, want record %d failing with %v", f, want, wantCodes[i])
+		}
+	}
+	if err := ptypes.UnmarshalAny(op.Metadata, metadata); err != nil {
+		t.Fatalf("Failed to unmarshal operation metadata: %v", err)
+	}
+	if metadata.ProcessedCount != int32(num+3) || metadata.ImportedCount != int32(num) || metadata.FailedCount != 3 || metadata.EndTime == nil {
+		t.Errorf("Got metadata %+v, want %d processed, %d imported, 3 failed and an end time", metadata, num+3, num)
+	}
+	if got := len(s.occurrences["consumer1"]); got != num {
+		t.Errorf("Got %d occurrences, want %d", got, num)
+	}
+}
+
+func TestImportOccurrencesCancelled(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	ops := newFakeOperations()
+	ops.cancel = true
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+		Operations:        ops,
+	}
+
+	num := maxBatchSize + 1
+	var buf bytes.Buffer
+	for _, o := range vulnzOccs(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "foobar", num) {
+		writeDelimited(t, &buf, o)
+	}
+
+	req := &bulkpb.ImportOccurrencesRequest{Parent: "projects/consumer1", Format: bulkpb.ImportFormat_LENGTH_DELIMITED}
+	op := &lrpb.Operation{}
+	if err := g.ImportOccurrences(ctx, req, bytes.NewReader(buf.Bytes()), op); err != nil {
+		t.Fatalf("Got err %v, want success", err)
+	}
+
+	// The import stops after the first batch, once it finds the operation cancelled.
+	<-ops.rejected
+	if got := len(s.occurrences["consumer1"]); got != maxBatchSize {
+		t.Errorf("Got %d occurrences, want %d", got, maxBatchSize)
+	}
+	if op := ops.get(op.Name); op.GetError().GetCode() != int32(codes.Canceled) {
+		t.Errorf("Got operation %+v, want it cancelled", op)
+	}
+}
+
+func TestImportNotesFile(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "grafeas-import-test")
+	if err != nil {
+		t.Fatalf("Failed to create import dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	n := vulnzNote(t)
+	n.Name = "projects/consumer1/notes/CVE-1"
+	if err := ioutil.WriteFile(filepath.Join(dir, "notes.json"), []byte(jsonLine(t, n)), 0644); err != nil {
+		t.Fatalf("Failed to write import file: %v", err)
+	}
+
+	tests := []struct {
+		desc, importDir, path string
+		wantErrStatus         codes.Code
+	}{
+		{
+			desc:      "file in import dir",
+			importDir: dir,
+			path:      "notes.json",
+		},
+		{
+			desc:      "path escaping import dir",
+			importDir: dir,
+			path:      "../../notes.json",
+		},
+		{
+			desc:          "missing file",
+			importDir:     dir,
+			path:          "missing.json",
+			wantErrStatus: codes.NotFound,
+		},
+		{
+			desc:          "no import dir",
+			path:          "notes.json",
+			wantErrStatus: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		s := newFakeStorage()
+		g := &API{
+			Storage:           s,
+			Auth:              &fakeAuth{},
+			Filter:            &fakeFilter{},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+			ImportDir:         tt.importDir,
+		}
+
+		req := &bulkpb.ImportNotesRequest{
+			Parent: "projects/consumer1",
+			Format: bulkpb.ImportFormat_JSON_LINES,
+			Source: &bulkpb.ImportNotesRequest_Path{Path: tt.path},
+		}
+		err := g.ImportNotes(ctx, req, nil, &lrpb.Operation{})
+		if status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: got error status %v, want %v", tt.desc, status.Code(err), tt.wantErrStatus)
+		}
+		if err == nil && len(s.notes["consumer1"]) != 1 {
+			t.Errorf("%q: got notes %+v, want the imported one", tt.desc, s.notes["consumer1"])
+		}
+	}
+}
+
+func TestImportRecordTooLarge(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+	}
+
+	// The import ends at the first record that's too large, as it can't tell where it ends.
+	n := vulnzNote(t)
+	n.Name = "projects/consumer1/notes/CVE-1"
+	data := strings.Repeat(" ", maxRecordSize) + "{}\n" + jsonLine(t, n) + "\n"
+	req := &bulkpb.ImportNotesRequest{
+		Parent: "projects/consumer1",
+		Format: bulkpb.ImportFormat_JSON_LINES,
+		Source: &bulkpb.ImportNotesRequest_Data{Data: []byte(data)},
+	}
+	op := &lrpb.Operation{}
+	if err := g.ImportNotes(ctx, req, nil, op); err != nil {
+		t.Fatalf("Got err %v, want success", err)
+	}
+	result := &bulkpb.ImportResponse{}
+	if err := ptypes.UnmarshalAny(op.GetResponse(), result); err != nil {
+		t.Fatalf("Failed to unmarshal operation response: %v", err)
+	}
+	if result.ImportedCount != 0 || result.FailedCount != 1 || codes.Code(result.Failures[0].Status.Code) != codes.InvalidArgument {
+		t.Errorf("Got result %+v, want the first record failing with %v", result, codes.InvalidArgument)
+	}
+	if got := len(s.notes["consumer1"]); got != 0 {
+		t.Errorf("Got %d notes, want 0", got)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		desc                  string
+		req                   *bulkpb.ImportOccurrencesRequest
+		upload                io.Reader
+		maxUploadSize         int64
+		authErr, endUserIDErr bool
+		wantErrStatus         codes.Code
+	}{
+		{
+			desc:          "invalid project name",
+			req:           &bulkpb.ImportOccurrencesRequest{Parent: "consumer1", Format: bulkpb.ImportFormat_JSON_LINES},
+			upload:        strings.NewReader("{}"),
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "no format",
+			req:           &bulkpb.ImportOccurrencesRequest{Parent: "projects/consumer1"},
+			upload:        strings.NewReader("{}"),
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "no data",
+			req:           &bulkpb.ImportOccurrencesRequest{Parent: "projects/consumer1", Format: bulkpb.ImportFormat_JSON_LINES},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc: "path and upload",
+			req: &bulkpb.ImportOccurrencesRequest{
+				Parent: "projects/consumer1",
+				Format: bulkpb.ImportFormat_JSON_LINES,
+				Source: &bulkpb.ImportOccurrencesRequest_Path{Path: "occurrences.json"},
+			},
+			upload:        strings.NewReader("{}"),
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "auth error",
+			req:           &bulkpb.ImportOccurrencesRequest{Parent: "projects/consumer1", Format: bulkpb.ImportFormat_JSON_LINES},
+			upload:        strings.NewReader("{}"),
+			authErr:       true,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc:          "end user ID error",
+			req:           &bulkpb.ImportOccurrencesRequest{Parent: "projects/consumer1", Format: bulkpb.ImportFormat_JSON_LINES},
+			upload:        strings.NewReader("{}"),
+			endUserIDErr:  true,
+			wantErrStatus: codes.Internal,
+		},
+		{
+			desc:          "upload too large",
+			req:           &bulkpb.ImportOccurrencesRequest{Parent: "projects/consumer1", Format: bulkpb.ImportFormat_JSON_LINES},
+			upload:        strings.NewReader("{}\n{}\n"),
+			maxUploadSize: 4,
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "upserting not supported",
+			req:           &bulkpb.ImportOccurrencesRequest{Parent: "projects/consumer1", Format: bulkpb.ImportFormat_JSON_LINES, Upsert: true},
+			upload:        strings.NewReader("{}"),
+			wantErrStatus: codes.Unimplemented,
+		},
+	}
+
+	for _, tt := range tests {
+		g := &API{
+			Storage:           newFakeStorage(),
+			Auth:              &fakeAuth{authErr: tt.authErr, endUserIDErr: tt.endUserIDErr},
+			Filter:            &fakeFilter{},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+			ImportDir:         os.TempDir(),
+			MaxUploadSize:     tt.maxUploadSize,
+		}
+
+		err := g.ImportOccurrences(ctx, tt.req, tt.upload, &lrpb.Operation{})
+		if status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: got error status %v, want %v", tt.desc, status.Code(err), tt.wantErrStatus)
+		}
+	}
+}
+
+// jsonLine returns m encoded as a line of a JSON lines import.
+func jsonLine(t *testing.T, m proto.Message) string {
+	t.Helper()
+	s, err := (&jsonpb.Marshaler{}).MarshalToString(m)
+	if err != nil {
+		t.Fatalf("Failed to marshal %+v: %v", m, err)
+	}
+	return s
+}
+
+// writeDelimited writes m to buf as a record of a length-delimited import.
+func writeDelimited(t *testing.T, buf *bytes.Buffer, m proto.Message) {
+	t.Helper()
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("Failed to marshal %+v: %v", m, err)
+	}
+	var size [binary.MaxVarintLen64]byte
+	buf.Write(size[:binary.PutUvarint(size[:], uint64(len(b)))])
+	buf.Write(b)
+}
@@ -19,6 +19,7 @@ import (
 
 	emptypb "github.com/golang/protobuf/ptypes/empty"
 	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/etag"
 	"github.com/grafeas/grafeas/go/name"
 	"github.com/grafeas/grafeas/go/v1beta1/api/validators/grafeas"
 	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
@@ -61,6 +62,8 @@ func (g *API) CreateNote(ctx context.Context, req *gpb.CreateNoteRequest, resp *
 	if err != nil {
 		return err
 	}
+	g.audit(ctx, pID, uID, "CreateNote", n.Name, nil, nil, n)
+	etag.SetHeaderFor(ctx, n)
 
 	*resp = *n
 	return nil
@@ -104,6 +107,9 @@ func (g *API) BatchCreateNotes(ctx context.Context, req *gpb.BatchCreateNotesReq
 	}
 
 	created, errs := g.Storage.BatchCreateNotes(ctx, pID, uID, req.Notes)
+	for _, n := range created {
+		g.audit(ctx, pID, uID, "BatchCreateNotes", n.Name, nil, nil, n)
+	}
 	resp.Notes = created
 	if len(errs) > 0 {
 		// Report any storage layer errors as invalid argument for now, find a better way to do this.
@@ -113,7 +119,7 @@ func (g *API) BatchCreateNotes(ctx context.Context, req *gpb.BatchCreateNotesReq
 	return nil
 }
 
-// GetNote gets the specified note.
+// GetNote gets the specified note, as it was at the read time if the request has one.
 func (g *API) GetNote(ctx context.Context, req *gpb.GetNoteRequest, resp *gpb.Note) error {
 	pID, nID, err := name.ParseNote(req.Name)
 	if err != nil {
@@ -126,10 +132,15 @@ func (g *API) GetNote(ctx context.Context, req *gpb.GetNoteRequest, resp *gpb.No
 		return err
 	}
 
+	if req.ReadTime != nil {
+		return g.getNoteAt(ctx, pID, nID, req.ReadTime, resp)
+	}
+
 	n, err := g.Storage.GetNote(ctx, pID, nID)
 	if err != nil {
 		return err
 	}
+	etag.SetHeaderFor(ctx, n)
 	*resp = *n
 
 	return nil
@@ -152,10 +163,13 @@ func (g *API) UpdateNote(ctx context.Context, req *gpb.UpdateNoteRequest, resp *
 		return errors.Newf(codes.InvalidArgument, "an note must be specified")
 	}
 
-	n, err := g.Storage.UpdateNote(ctx, pID, nID, req.Note, req.UpdateMask)
+	before := g.auditedNote(ctx, pID, nID)
+	n, err := g.Storage.UpdateNote(ctx, pID, nID, req.Note, req.UpdateMask, etag.FromIncomingContext(ctx))
 	if err != nil {
 		return err
 	}
+	g.audit(ctx, pID, "", "UpdateNote", req.Name, req.UpdateMask, before, n)
+	etag.SetHeaderFor(ctx, n)
 	*resp = *n
 
 	return nil
@@ -174,11 +188,16 @@ func (g *API) DeleteNote(ctx context.Context, req *gpb.DeleteNoteRequest, _ *emp
 		return err
 	}
 
-	if err := g.Storage.DeleteNote(ctx, pID, nID); err != nil {
+	before := g.auditedNote(ctx, pID, nID)
+	if err := g.Storage.DeleteNote(ctx, pID, nID, etag.FromIncomingContext(ctx)); err != nil {
 		return err
 	}
+	g.audit(ctx, pID, "", "DeleteNote", req.Name, nil, before, nil)
 
-	// Purge any IAM policies set on this entity.
+	// Purge any IAM policies set on this entity, unless the note can still be undeleted.
+	if g.Tombstones != nil {
+		return nil
+	}
 	if err := g.Auth.PurgePolicy(ctx, pID, nID, Notes); err != nil {
 		// This fails open, should not block on policy deletion failure.
 		g.Logger.Warningf(ctx, "Error deleting policies for note %q in project %q: %v", nID, pID, err)
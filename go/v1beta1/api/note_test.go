@@ -19,10 +19,12 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/grafeas/grafeas/go/etag"
 	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
 	vpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -644,6 +646,50 @@ func TestUpdateNoteErrors(t *testing.T) {
 	}
 }
 
+func TestUpdateNoteETag(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+	}
+
+	n, err := s.CreateNote(ctx, "goog-vulnz", "CVE-UH-OH", "", vulnzNote(t))
+	if err != nil {
+		t.Fatalf("Failed to create note %+v", n)
+	}
+	tag, err := etag.Compute(n)
+	if err != nil {
+		t.Fatalf("Failed to compute etag of note %+v: %v", n, err)
+	}
+
+	// The first writer holds the current etag and wins, the second one read the note before the
+	// first update and must be rejected.
+	for _, want := range []codes.Code{codes.OK, codes.Aborted} {
+		update := vulnzNote(t)
+		update.ShortDescription = fmt.Sprintf("updated, expecting %v", want)
+		req := &gpb.UpdateNoteRequest{
+			Name: "projects/goog-vulnz/notes/CVE-UH-OH",
+			Note: update,
+		}
+		ctx := metadata.NewIncomingContext(ctx, metadata.Pairs(etag.IfMatchKey, tag))
+		err := g.UpdateNote(ctx, req, &gpb.Note{})
+		if status.Code(err) != want {
+			t.Errorf("UpdateNote with etag %q got error status %v, want %v", tag, status.Code(err), want)
+		}
+	}
+
+	// Deleting with a stale etag must fail too.
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(etag.IfMatchKey, tag))
+	req := &gpb.DeleteNoteRequest{Name: "projects/goog-vulnz/notes/CVE-UH-OH"}
+	if err := g.DeleteNote(ctx, req, nil); status.Code(err) != codes.Aborted {
+		t.Errorf("DeleteNote with stale etag got error status %v, want %v", status.Code(err), codes.Aborted)
+	}
+}
+
 func TestDeleteNote(t *testing.T) {
 	ctx := context.Background()
 	s := newFakeStorage()
@@ -17,13 +17,22 @@ package grafeas
 import (
 	"fmt"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	emptypb "github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
 	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/etag"
 	"github.com/grafeas/grafeas/go/name"
 	"github.com/grafeas/grafeas/go/v1beta1/api/validators/grafeas"
+	bulkpb "github.com/grafeas/grafeas/proto/v1beta1/bulk_go_proto"
 	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	upsertpb "github.com/grafeas/grafeas/proto/v1beta1/upsert_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
 	"golang.org/x/net/context"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // GetOccurrence gets the specified occurrence.
@@ -43,6 +52,7 @@ func (g *API) GetOccurrence(ctx context.Context, req *gpb.GetOccurrenceRequest,
 	if err != nil {
 		return err
 	}
+	etag.SetHeaderFor(ctx, o)
 	*resp = *o
 
 	return nil
@@ -79,26 +89,71 @@ func (g *API) ListOccurrences(ctx context.Context, req *gpb.ListOccurrencesReque
 	return nil
 }
 
+// WatchOccurrences streams changes to occurrences in the specified project to send until ctx is
+// done or send returns an error.
+func (g *API) WatchOccurrences(ctx context.Context, req *watchpb.WatchOccurrencesRequest, send func(*watchpb.OccurrenceEvent) error) error {
+	pID, err := name.ParseProject(req.Parent)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, "", OccurrencesList); err != nil {
+		return err
+	}
+
+	if g.Watch == nil {
+		return errors.Newf(codes.Unimplemented, "watching occurrences is not supported")
+	}
+	if err := g.Filter.Validate(req.Filter); err != nil {
+		return err
+	}
+
+	return g.Watch.WatchOccurrences(ctx, pID, req.Filter, req.Cursor, send)
+}
+
 // CreateOccurrence creates the specified occurrence.
 func (g *API) CreateOccurrence(ctx context.Context, req *gpb.CreateOccurrenceRequest, resp *gpb.Occurrence) error {
-	pID, err := name.ParseProject(req.Parent)
+	return g.createOccurrence(ctx, "CreateOccurrence", req.Parent, req.Occurrence, false, resp)
+}
+
+// UpsertOccurrence updates the occurrence for the same resource URI and note as the specified one
+// in place, or creates the specified occurrence if there is none. The caller needs permission to
+// both create and update occurrences.
+func (g *API) UpsertOccurrence(ctx context.Context, req *upsertpb.UpsertOccurrenceRequest, resp *gpb.Occurrence) error {
+	return g.createOccurrence(ctx, "UpsertOccurrence", req.Parent, req.Occurrence, true, resp)
+}
+
+// createOccurrence creates occ in project parent, or upserts it if upsert is set, on behalf of the
+// specified method.
+func (g *API) createOccurrence(ctx context.Context, method, parent string, occ *gpb.Occurrence, upsert bool, resp *gpb.Occurrence) error {
+	pID, err := name.ParseProject(parent)
 	if err != nil {
 		return err
 	}
 
 	ctx = g.Logger.PrepareCtx(ctx, pID)
 
-	if req.Occurrence == nil {
+	if occ == nil {
 		return errors.Newf(codes.InvalidArgument, "an occurrence must be specified")
 	}
 
 	if err := g.Auth.CheckAccessAndProject(ctx, pID, "", OccurrencesCreate); err != nil {
 		return err
 	}
+	if upsert {
+		if err := g.Auth.CheckAccessAndProject(ctx, pID, "", OccurrencesUpdate); err != nil {
+			return err
+		}
+		if g.Upserts == nil {
+			return errors.Newf(codes.Unimplemented, "upserting occurrences is not supported")
+		}
+	}
 
 	// Creating occurrences requires an additional notes attacher permissions check before we can
 	// continue validation.
-	notePID, nID, err := name.ParseNote(req.Occurrence.NoteName)
+	notePID, nID, err := name.ParseNote(occ.NoteName)
 	if err != nil {
 		return err
 	}
@@ -106,11 +161,11 @@ func (g *API) CreateOccurrence(ctx context.Context, req *gpb.CreateOccurrenceReq
 		return err
 	}
 
-	if err := grafeas.ValidateOccurrence(req.Occurrence); err != nil {
+	if err := grafeas.ValidateOccurrence(occ); err != nil {
 		if g.EnforceValidation {
 			return err
 		}
-		g.Logger.Warningf(ctx, "CreateOccurrence %+v for project %q: invalid occurrence, fail open, would have failed with: %v", req.Occurrence, pID, err)
+		g.Logger.Warningf(ctx, "%s %+v for project %q: invalid occurrence, fail open, would have failed with: %v", method, occ, pID, err)
 	}
 
 	uID, err := g.Auth.EndUserID(ctx)
@@ -118,10 +173,17 @@ func (g *API) CreateOccurrence(ctx context.Context, req *gpb.CreateOccurrenceReq
 		return err
 	}
 
-	o, err := g.Storage.CreateOccurrence(ctx, pID, uID, req.Occurrence)
+	var o *gpb.Occurrence
+	if upsert {
+		o, err = g.Upserts.UpsertOccurrence(ctx, pID, uID, occ)
+	} else {
+		o, err = g.Storage.CreateOccurrence(ctx, pID, uID, occ)
+	}
 	if err != nil {
 		return err
 	}
+	g.audit(ctx, pID, uID, method, o.Name, nil, nil, o)
+	etag.SetHeaderFor(ctx, o)
 	*resp = *o
 
 	return nil
@@ -129,66 +191,106 @@ func (g *API) CreateOccurrence(ctx context.Context, req *gpb.CreateOccurrenceReq
 
 // BatchCreateOccurrences batch creates the specified occurrences.
 func (g *API) BatchCreateOccurrences(ctx context.Context, req *gpb.BatchCreateOccurrencesRequest, resp *gpb.BatchCreateOccurrencesResponse) error {
-	pID, err := name.ParseProject(req.Parent)
+	created, err := g.batchCreateOccurrences(ctx, "BatchCreateOccurrences", req.Parent, req.Occurrences, false)
+	resp.Occurrences = created
+	return err
+}
+
+// BatchUpsertOccurrences batch upserts the specified occurrences, like UpsertOccurrence upserts one.
+func (g *API) BatchUpsertOccurrences(ctx context.Context, req *upsertpb.BatchUpsertOccurrencesRequest, resp *upsertpb.BatchUpsertOccurrencesResponse) error {
+	upserted, err := g.batchCreateOccurrences(ctx, "BatchUpsertOccurrences", req.Parent, req.Occurrences, true)
+	resp.Occurrences = upserted
+	return err
+}
+
+// batchCreateOccurrences creates occs in project parent, or upserts them if upsert is set, on
+// behalf of the specified method. It returns the occurrences that were stored even if others
+// failed.
+func (g *API) batchCreateOccurrences(ctx context.Context, method, parent string, occs []*gpb.Occurrence, upsert bool) ([]*gpb.Occurrence, error) {
+	pID, err := name.ParseProject(parent)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	ctx = g.Logger.PrepareCtx(ctx, pID)
 
 	if err := g.Auth.CheckAccessAndProject(ctx, pID, "", OccurrencesCreate); err != nil {
-		return err
+		return nil, err
+	}
+	if upsert {
+		if err := g.Auth.CheckAccessAndProject(ctx, pID, "", OccurrencesUpdate); err != nil {
+			return nil, err
+		}
+		if g.Upserts == nil {
+			return nil, errors.Newf(codes.Unimplemented, "upserting occurrences is not supported")
+		}
+	}
+
+	// The verb of the error messages.
+	verb, verbPast, verbGerund := "create", "created", "creating"
+	if upsert {
+		verb, verbPast, verbGerund = "upsert", "upserted", "upserting"
 	}
 
-	if len(req.Occurrences) == 0 {
-		return errors.Newf(codes.InvalidArgument, "at least one occurrence must be specified")
+	if len(occs) == 0 {
+		return nil, errors.Newf(codes.InvalidArgument, "at least one occurrence must be specified")
 	}
-	if len(req.Occurrences) > maxBatchSize {
-		return errors.Newf(codes.InvalidArgument, "%d is too many occurrence to batch create, a maximum of %d occurrence is allowed per batch create", len(req.Occurrences), maxBatchSize)
+	if len(occs) > maxBatchSize {
+		return nil, errors.Newf(codes.InvalidArgument, "%d is too many occurrence to batch %s, a maximum of %d occurrence is allowed per batch %s", len(occs), verb, maxBatchSize, verb)
 	}
 
 	// Creating occurrences requires an additional notes attacher permissions check before we can
 	// continue validation.
 	authErrs := []error{}
-	for i, o := range req.Occurrences {
+	for i, o := range occs {
 		notePID, nID, err := name.ParseNote(o.NoteName)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if err := g.Auth.CheckAccessAndProject(ctx, notePID, nID, NotesAttachOccurrence); err != nil {
 			authErrs = append(authErrs, fmt.Errorf("occurrences[%d]: %s", i, err))
 		}
 	}
 	if len(authErrs) > 0 {
-		return errors.Newf(codes.PermissionDenied, "one or more occurrences had auth errors, no occurrences were created: %v", authErrs)
+		return nil, errors.Newf(codes.PermissionDenied, "one or more occurrences had auth errors, no occurrences were %s: %v", verbPast, authErrs)
 	}
 
 	validationErrs := []error{}
-	for i, o := range req.Occurrences {
+	for i, o := range occs {
 		if err := grafeas.ValidateOccurrence(o); err != nil {
 			validationErrs = append(validationErrs, fmt.Errorf("occurrences[%d]: %v", i, err))
 		}
 	}
 	if len(validationErrs) > 0 {
 		if g.EnforceValidation {
-			return errors.Newf(codes.InvalidArgument, "one or more occurrences are invalid, no occurrences were created: %v", validationErrs)
+			return nil, errors.Newf(codes.InvalidArgument, "one or more occurrences are invalid, no occurrences were %s: %v", verbPast, validationErrs)
 		}
-		g.Logger.Warningf(ctx, "BatchCreateOccurrences %+v for project %q: invalid occurrences(s), fail open, would have failed with: %v", req.Occurrences, pID, validationErrs)
+		g.Logger.Warningf(ctx, "%s %+v for project %q: invalid occurrences(s), fail open, would have failed with: %v", method, occs, pID, validationErrs)
 	}
 
 	uID, err := g.Auth.EndUserID(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	created, errs := g.Storage.BatchCreateOccurrences(ctx, pID, uID, req.Occurrences)
-	resp.Occurrences = created
+	var (
+		created []*gpb.Occurrence
+		errs    []error
+	)
+	if upsert {
+		created, errs = g.Upserts.BatchUpsertOccurrences(ctx, pID, uID, occs)
+	} else {
+		created, errs = g.Storage.BatchCreateOccurrences(ctx, pID, uID, occs)
+	}
+	for _, o := range created {
+		g.audit(ctx, pID, uID, method, o.Name, nil, nil, o)
+	}
 	if len(errs) != 0 {
 		// Report any storage layer errors as invalid argument for now, find a better way to do this.
-		return errors.Newf(codes.InvalidArgument, "errors encountered when batch creating occurrences: %d of %d occurrences failed: %v", len(errs), len(req.Occurrences), errs)
+		return created, errors.Newf(codes.InvalidArgument, "errors encountered when batch %s occurrences: %d of %d occurrences failed: %v", verbGerund, len(errs), len(occs), errs)
 	}
 
-	return nil
+	return created, nil
 }
 
 // UpdateOccurrence updates the specified occurrence.
@@ -215,10 +317,13 @@ func (g *API) UpdateOccurrence(ctx context.Context, req *gpb.UpdateOccurrenceReq
 		return err
 	}
 
-	o, err := g.Storage.UpdateOccurrence(ctx, pID, oID, req.Occurrence, req.UpdateMask)
+	before := g.auditedOccurrence(ctx, pID, oID)
+	o, err := g.Storage.UpdateOccurrence(ctx, pID, oID, req.Occurrence, req.UpdateMask, etag.FromIncomingContext(ctx))
 	if err != nil {
 		return err
 	}
+	g.audit(ctx, pID, "", "UpdateOccurrence", req.Name, req.UpdateMask, before, o)
+	etag.SetHeaderFor(ctx, o)
 	*resp = *o
 
 	return nil
@@ -251,9 +356,10 @@ func (g *API) DeleteOccurrence(ctx context.Context, req *gpb.DeleteOccurrenceReq
 		}
 	}
 
-	if err := g.Storage.DeleteOccurrence(ctx, pID, oID); err != nil {
+	if err := g.Storage.DeleteOccurrence(ctx, pID, oID, etag.FromIncomingContext(ctx)); err != nil {
 		return err
 	}
+	g.audit(ctx, pID, "", "DeleteOccurrence", req.Name, nil, o, nil)
 
 	// Purge any IAM policies set on this entity.
 	if err := g.Auth.PurgePolicy(ctx, pID, oID, Occurrences); err != nil {
@@ -264,6 +370,285 @@ func (g *API) DeleteOccurrence(ctx context.Context, req *gpb.DeleteOccurrenceReq
 	return nil
 }
 
+// expireActor is the actor recorded in the audit log for the deletions of expired occurrences, which
+// no caller makes.
+const expireActor = "system:retention"
+
+// ExpireOccurrence deletes the specified occurrence because it expired, provided it still has the
+// specified etag. No caller makes the deletion, so it isn't authorized, but it is audited and the
+// IAM policies of the occurrence are purged like those of deleted occurrences.
+func (g *API) ExpireOccurrence(ctx context.Context, pID, oID, etag string) error {
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	before := g.auditedOccurrence(ctx, pID, oID)
+	if err := g.Storage.DeleteOccurrence(ctx, pID, oID, etag); err != nil {
+		return err
+	}
+	g.audit(ctx, pID, expireActor, "ExpireOccurrence", name.FormatOccurrence(pID, oID), nil, before, nil)
+
+	if err := g.Auth.PurgePolicy(ctx, pID, oID, Occurrences); err != nil {
+		// This fails open, the occurrence is gone already.
+		g.Logger.Warningf(ctx, "Error deleting policies for occurrence %q in project %q: %v", oID, pID, err)
+	}
+
+	return nil
+}
+
+// BatchDeleteOccurrences deletes the occurrences in the specified project that match the filter or
+// are listed by name. Each occurrence needs the same permissions as DeleteOccurrence; those that
+// lack them are reported as failures instead of failing the call. If more than maxBatchSize
+// occurrences are to be deleted and Operations is set, they are deleted in the background.
+func (g *API) BatchDeleteOccurrences(ctx context.Context, req *bulkpb.BatchDeleteOccurrencesRequest, resp *lrpb.Operation) error {
+	pID, err := name.ParseProject(req.Parent)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	switch {
+	case req.Filter != "" && len(req.Names) > 0:
+		return errors.Newf(codes.InvalidArgument, "only one of filter and names can be specified")
+	case req.Filter == "" && len(req.Names) == 0:
+		return errors.Newf(codes.InvalidArgument, "a filter or names must be specified")
+	case len(req.Names) > maxBatchSize:
+		return errors.Newf(codes.InvalidArgument, "%d occurrences is larger than the max batch size of %d", len(req.Names), maxBatchSize)
+	}
+
+	result := &bulkpb.BatchDeleteOccurrencesResponse{}
+	var oIDs []string
+	// Occurrences of the same note share the outcome of its permission check.
+	noteErrs := map[string]error{}
+	if req.Filter != "" {
+		if err := g.Auth.CheckAccessAndProject(ctx, pID, "", OccurrencesList); err != nil {
+			return err
+		}
+		if err := g.Filter.Validate(req.Filter); err != nil {
+			return err
+		}
+		occs, err := g.listAllOccurrences(ctx, pID, req.Filter)
+		if err != nil {
+			return err
+		}
+		for _, o := range occs {
+			_, oID, err := name.ParseOccurrence(o.Name)
+			if err != nil {
+				return err
+			}
+			if err := g.Auth.CheckAccessAndProject(ctx, pID, oID, OccurrencesDelete); err != nil {
+				addFailure(result, o.Name, err)
+				continue
+			}
+			if err := g.checkAttachOccurrence(ctx, o.NoteName, noteErrs); err != nil {
+				addFailure(result, o.Name, err)
+				continue
+			}
+			oIDs = append(oIDs, oID)
+		}
+		result.MatchedCount = int32(len(occs))
+	} else {
+		seen := map[string]bool{}
+		for _, n := range req.Names {
+			occPID, oID, err := name.ParseOccurrence(n)
+			if err != nil {
+				return err
+			}
+			if occPID != pID {
+				return errors.Newf(codes.InvalidArgument, "occurrence %q isn't in project %q", n, pID)
+			}
+			if seen[oID] {
+				continue
+			}
+			seen[oID] = true
+			if err := g.Auth.CheckAccessAndProject(ctx, pID, oID, OccurrencesDelete); err != nil {
+				addFailure(result, n, err)
+				continue
+			}
+			o, err := g.Storage.GetOccurrence(ctx, pID, oID)
+			if err != nil {
+				if status.Code(err) != codes.NotFound {
+					return err
+				}
+				addFailure(result, n, err)
+				continue
+			}
+			if err := g.checkAttachOccurrence(ctx, o.NoteName, noteErrs); err != nil {
+				addFailure(result, n, err)
+				continue
+			}
+			oIDs = append(oIDs, oID)
+		}
+		result.MatchedCount = int32(len(seen))
+	}
+
+	metadata := &bulkpb.BatchDeleteOccurrencesMetadata{
+		CreateTime: ptypes.TimestampNow(),
+		TotalCount: int32(len(oIDs)),
+	}
+	resp.Name = name.FormatOperation(pID, uuid.New().String())
+	// Deletions in the background have no caller in their context.
+	actor := g.auditActor(ctx)
+	if req.DryRun {
+		result.DeletedCount = int32(len(oIDs))
+		metadata.EndTime = ptypes.TimestampNow()
+		return finishOperation(resp, metadata, result)
+	}
+	if len(oIDs) <= maxBatchSize || g.Operations == nil {
+		g.deleteOccurrences(ctx, pID, actor, oIDs, metadata, result, nil)
+		metadata.EndTime = ptypes.TimestampNow()
+		return finishOperation(resp, metadata, result)
+	}
+
+	if err := setOperationMetadata(resp, metadata); err != nil {
+		return err
+	}
+	if err := g.Operations.CreateOperation(ctx, pID, resp); err != nil {
+		return err
+	}
+	// The deletions outlive the call, but have already been authorized.
+	bgCtx := g.Logger.PrepareCtx(context.Background(), pID)
+	op := proto.Clone(resp).(*lrpb.Operation)
+	go func() {
+		if !g.deleteOccurrences(bgCtx, pID, actor, oIDs, metadata, result, func() bool {
+			return g.updateProgress(bgCtx, pID, op, metadata)
+		}) {
+			return
+		}
+		metadata.EndTime = ptypes.TimestampNow()
+		g.storeFinishedOperation(bgCtx, pID, op, metadata, result)
+	}()
+	return nil
+}
+
+// listAllOccurrences lists all occurrences matching the filter in the specified project.
+func (g *API) listAllOccurrences(ctx context.Context, pID, filter string) ([]*gpb.Occurrence, error) {
+	var (
+		all       []*gpb.Occurrence
+		pageToken string
+	)
+	for {
+		occs, npt, err := g.Storage.ListOccurrences(ctx, pID, filter, pageToken, maxPageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, occs...)
+		if npt == "" {
+			return all, nil
+		}
+		pageToken = npt
+	}
+}
+
+// checkAttachOccurrence checks that the caller may attach occurrences to the specified note, which
+// deleting an occurrence of it requires. Checked notes are cached in checked.
+func (g *API) checkAttachOccurrence(ctx context.Context, noteName string, checked map[string]error) error {
+	if noteName == "" {
+		return nil
+	}
+	if err, ok := checked[noteName]; ok {
+		return err
+	}
+	pID, nID, err := name.ParseNote(noteName)
+	if err == nil {
+		err = g.Auth.CheckAccessAndProject(ctx, pID, nID, NotesAttachOccurrence)
+	}
+	checked[noteName] = err
+	return err
+}
+
+// deleteOccurrences deletes the specified occurrences on behalf of actor, recording failures in
+// result and progress in metadata, and calling progress, if set, after every maxBatchSize
+// occurrences. It stops before the next batch if progress returns false, and reports whether all
+// occurrences were processed.
+func (g *API) deleteOccurrences(ctx context.Context, pID, actor string, oIDs []string, metadata *bulkpb.BatchDeleteOccurrencesMetadata, result *bulkpb.BatchDeleteOccurrencesResponse, progress func() bool) bool {
+	for i, oID := range oIDs {
+		before := g.auditedOccurrence(ctx, pID, oID)
+		if err := g.Storage.DeleteOccurrence(ctx, pID, oID, ""); err != nil {
+			addFailure(result, name.FormatOccurrence(pID, oID), err)
+		} else {
+			result.DeletedCount++
+			g.audit(ctx, pID, actor, "BatchDeleteOccurrences", name.FormatOccurrence(pID, oID), nil, before, nil)
+			if err := g.Auth.PurgePolicy(ctx, pID, oID, Occurrences); err != nil {
+				// This fails open, should not block on policy deletion failure.
+				g.Logger.Warningf(ctx, "Error deleting policies for occurrence %q in project %q: %v", oID, pID, err)
+			}
+		}
+		metadata.ProcessedCount = int32(i + 1)
+		if progress != nil && (i+1)%maxBatchSize == 0 && !progress() {
+			return false
+		}
+	}
+	return true
+}
+
+// addFailure records that the occurrence with the specified name couldn't be deleted.
+func addFailure(result *bulkpb.BatchDeleteOccurrencesResponse, oName string, err error) {
+	result.FailedCount++
+	if len(result.Failures) < maxFailureSamples {
+		result.Failures = append(result.Failures, &bulkpb.BatchDeleteOccurrencesResponse_Failure{
+			Name:   oName,
+			Status: status.Convert(err).Proto(),
+		})
+	}
+}
+
+// setOperationMetadata sets the metadata of op.
+func setOperationMetadata(op *lrpb.Operation, metadata proto.Message) error {
+	m, err := ptypes.MarshalAny(metadata)
+	if err != nil {
+		return errors.Newf(codes.Internal, "failed to marshal operation metadata: %v", err)
+	}
+	op.Metadata = m
+	return nil
+}
+
+// finishOperation marks op as done with the specified response and final metadata.
+func finishOperation(op *lrpb.Operation, metadata, result proto.Message) error {
+	if err := setOperationMetadata(op, metadata); err != nil {
+		return err
+	}
+	r, err := ptypes.MarshalAny(result)
+	if err != nil {
+		return errors.Newf(codes.Internal, "failed to marshal operation response: %v", err)
+	}
+	op.Done = true
+	op.Result = &lrpb.Operation_Response{Response: r}
+	return nil
+}
+
+// updateProgress stores op with the specified metadata while it runs in the background, and reports
+// whether it should go on, i.e. whether the stored operation isn't done yet, e.g. cancelled.
+func (g *API) updateProgress(ctx context.Context, pID string, op *lrpb.Operation, metadata proto.Message) bool {
+	err := setOperationMetadata(op, metadata)
+	if err == nil {
+		err = g.Operations.UpdateOperation(ctx, pID, op)
+	}
+	if status.Code(err) == codes.FailedPrecondition {
+		g.Logger.Infof(ctx, "Stopping operation %q, it is already done: %v", op.Name, err)
+		return false
+	}
+	if err != nil {
+		g.Logger.Warningf(ctx, "Error updating progress of operation %q: %v", op.Name, err)
+	}
+	return true
+}
+
+// storeFinishedOperation marks op as done with the specified response and final metadata, and
+// stores it unless the stored operation is already done, e.g. cancelled.
+func (g *API) storeFinishedOperation(ctx context.Context, pID string, op *lrpb.Operation, metadata, result proto.Message) {
+	err := finishOperation(op, metadata, result)
+	if err == nil {
+		err = g.Operations.UpdateOperation(ctx, pID, op)
+	}
+	if status.Code(err) == codes.FailedPrecondition {
+		g.Logger.Infof(ctx, "Operation %q finished after it was done: %v", op.Name, err)
+		return
+	}
+	if err != nil {
+		g.Logger.Errorf(ctx, "Error finishing operation %q: %v", op.Name, err)
+	}
+}
+
 // ListNoteOccurrences lists occurrences for the specified note.
 func (g *API) ListNoteOccurrences(ctx context.Context, req *gpb.ListNoteOccurrencesRequest, resp *gpb.ListNoteOccurrencesResponse) error {
 	pID, nID, err := name.ParseNote(req.Name)
@@ -16,15 +16,25 @@ package grafeas
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
+	"github.com/grafeas/grafeas/go/etag"
+	"github.com/grafeas/grafeas/go/name"
+	bulkpb "github.com/grafeas/grafeas/proto/v1beta1/bulk_go_proto"
 	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
 	pkgpb "github.com/grafeas/grafeas/proto/v1beta1/package_go_proto"
 	provpb "github.com/grafeas/grafeas/proto/v1beta1/provenance_go_proto"
+	upsertpb "github.com/grafeas/grafeas/proto/v1beta1/upsert_go_proto"
 	vpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
 	"golang.org/x/net/context"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -211,6 +221,105 @@ func TestListOccurrencesErrors(t *testing.T) {
 	}
 }
 
+func TestWatchOccurrences(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+		Watch:             s,
+	}
+
+	o := vulnzOcc(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "debian")
+	s.events["consumer1"] = []*watchpb.OccurrenceEvent{
+		{Type: watchpb.OccurrenceEvent_CREATED, Occurrence: o, Cursor: "1"},
+		{Type: watchpb.OccurrenceEvent_DELETED, Occurrence: o, Cursor: "2"},
+	}
+
+	req := &watchpb.WatchOccurrencesRequest{
+		Parent: "projects/consumer1",
+	}
+	var got []*watchpb.OccurrenceEvent
+	if err := g.WatchOccurrences(ctx, req, func(e *watchpb.OccurrenceEvent) error {
+		got = append(got, e)
+		return nil
+	}); err != nil {
+		t.Errorf("Got err %v, want success", err)
+	}
+
+	if diff := cmp.Diff(s.events["consumer1"], got); diff != "" {
+		t.Errorf("WatchOccurrences(%v) returned diff (want -> got):\n%s", req, diff)
+	}
+}
+
+func TestWatchOccurrencesErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		desc                                   string
+		parent                                 string
+		internalStorageErr, authErr, filterErr bool
+		noWatch                                bool
+		wantErrStatus                          codes.Code
+	}{
+		{
+			desc:          "invalid parent name",
+			parent:        "projects",
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "watching not supported",
+			parent:        "projects/consumer1",
+			noWatch:       true,
+			wantErrStatus: codes.Unimplemented,
+		},
+		{
+			desc:          "auth error",
+			parent:        "projects/consumer1",
+			authErr:       true,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc:               "internal storage error",
+			parent:             "projects/consumer1",
+			internalStorageErr: true,
+			wantErrStatus:      codes.Internal,
+		},
+		{
+			desc:          "filter parse error",
+			parent:        "projects/consumer1",
+			filterErr:     true,
+			wantErrStatus: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		s := newFakeStorage()
+		s.watchOccsErr = tt.internalStorageErr
+		g := &API{
+			Storage:           s,
+			Auth:              &fakeAuth{authErr: tt.authErr},
+			Filter:            &fakeFilter{err: tt.filterErr},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+		}
+		if !tt.noWatch {
+			g.Watch = s
+		}
+
+		req := &watchpb.WatchOccurrencesRequest{
+			Parent: tt.parent,
+		}
+		err := g.WatchOccurrences(ctx, req, func(*watchpb.OccurrenceEvent) error { return nil })
+		t.Logf("%q: error: %v", tt.desc, err)
+		if status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: got error status %v, want %v", tt.desc, status.Code(err), tt.wantErrStatus)
+		}
+	}
+}
+
 func TestCreateOccurrence(t *testing.T) {
 	ctx := context.Background()
 	g := &API{
@@ -236,6 +345,95 @@ func TestCreateOccurrence(t *testing.T) {
 	}
 }
 
+func TestUpsertOccurrence(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+		Upserts:           s,
+	}
+
+	req := &upsertpb.UpsertOccurrenceRequest{
+		Parent:     "projects/consumer1",
+		Occurrence: vulnzOcc(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "debian"),
+	}
+	first := &gpb.Occurrence{}
+	if err := g.UpsertOccurrence(ctx, req, first); err != nil {
+		t.Fatalf("Got err %v, want success", err)
+	}
+
+	req.Occurrence.Remediation = "upgrade"
+	second := &gpb.Occurrence{}
+	if err := g.UpsertOccurrence(ctx, req, second); err != nil {
+		t.Fatalf("Got err %v, want success", err)
+	}
+	if second.Name != first.Name {
+		t.Errorf("UpsertOccurrence upserted into %q, want %q", second.Name, first.Name)
+	}
+	if second.Remediation != "upgrade" {
+		t.Errorf("UpsertOccurrence got remediation %q, want %q", second.Remediation, "upgrade")
+	}
+	if second.UpdateTime == nil {
+		t.Error("UpsertOccurrence got no update time, want it bumped")
+	}
+	if got := len(s.occurrences["consumer1"]); got != 1 {
+		t.Errorf("Got %d stored occurrences, want 1", got)
+	}
+
+	// A different resource gets its own occurrence.
+	batchReq := &upsertpb.BatchUpsertOccurrencesRequest{
+		Parent: "projects/consumer1",
+		Occurrences: []*gpb.Occurrence{
+			vulnzOcc(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "debian"),
+			vulnzOcc(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "alpine"),
+		},
+	}
+	batchResp := &upsertpb.BatchUpsertOccurrencesResponse{}
+	if err := g.BatchUpsertOccurrences(ctx, batchReq, batchResp); err != nil {
+		t.Fatalf("Got err %v, want success", err)
+	}
+	if len(batchResp.Occurrences) != 2 || batchResp.Occurrences[0].Name != first.Name {
+		t.Errorf("BatchUpsertOccurrences got %v, want %q upserted and another occurrence created", batchResp.Occurrences, first.Name)
+	}
+	if got := len(s.occurrences["consumer1"]); got != 2 {
+		t.Errorf("Got %d stored occurrences, want 2", got)
+	}
+
+	// CreateOccurrence always creates another occurrence.
+	createReq := &gpb.CreateOccurrenceRequest{
+		Parent:     "projects/consumer1",
+		Occurrence: vulnzOcc(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "debian"),
+	}
+	if err := g.CreateOccurrence(ctx, createReq, &gpb.Occurrence{}); err != nil {
+		t.Fatalf("Got err %v, want success", err)
+	}
+	if got := len(s.occurrences["consumer1"]); got != 3 {
+		t.Errorf("Got %d stored occurrences, want 3", got)
+	}
+
+	// Errors of batch upserts say that nothing was upserted.
+	invalidReq := &upsertpb.BatchUpsertOccurrencesRequest{
+		Parent:      "projects/consumer1",
+		Occurrences: []*gpb.Occurrence{{NoteName: "projects/goog-vulnz/notes/CVE-UH-OH"}},
+	}
+	if err := g.BatchUpsertOccurrences(ctx, invalidReq, &upsertpb.BatchUpsertOccurrencesResponse{}); status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "no occurrences were upserted") {
+		t.Errorf("BatchUpsertOccurrences of an invalid occurrence got err %v, want %v saying no occurrences were upserted", err, codes.InvalidArgument)
+	}
+
+	// Without upserts in storage, occurrences can't be upserted.
+	g.Upserts = nil
+	if err := g.UpsertOccurrence(ctx, req, &gpb.Occurrence{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("UpsertOccurrence without upserts got err %v, want %v", err, codes.Unimplemented)
+	}
+	if err := g.BatchUpsertOccurrences(ctx, batchReq, &upsertpb.BatchUpsertOccurrencesResponse{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("BatchUpsertOccurrences without upserts got err %v, want %v", err, codes.Unimplemented)
+	}
+}
+
 func TestCreateOccurrenceErrors(t *testing.T) {
 	ctx := context.Background()
 
@@ -614,6 +812,98 @@ func TestDeleteOccurrence(t *testing.T) {
 	}
 }
 
+func TestDeleteOccurrenceETag(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+	}
+
+	o, err := s.CreateOccurrence(ctx, "consumer1", "", vulnzOcc(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "debian"))
+	if err != nil {
+		t.Fatalf("Failed to create occurrence %+v", o)
+	}
+	tag, err := etag.Compute(o)
+	if err != nil {
+		t.Fatalf("Failed to compute etag of occurrence %+v: %v", o, err)
+	}
+
+	tests := []struct {
+		desc     string
+		etag     string
+		wantCode codes.Code
+	}{
+		{
+			desc:     "stale etag, want aborted",
+			etag:     "d41d8cd98f00b204e9800998ecf8427e",
+			wantCode: codes.Aborted,
+		},
+		{
+			desc:     "current etag",
+			etag:     tag,
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		ctx := metadata.NewIncomingContext(ctx, metadata.Pairs(etag.IfMatchKey, tt.etag))
+		req := &gpb.DeleteOccurrenceRequest{Name: o.Name}
+		if err := g.DeleteOccurrence(ctx, req, nil); status.Code(err) != tt.wantCode {
+			t.Errorf("%q: got error status %v, want %v", tt.desc, status.Code(err), tt.wantCode)
+		}
+	}
+}
+
+func TestExpireOccurrence(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	auth := &fakeAuth{authErr: true}
+	a := &fakeAudit{}
+	g := &API{
+		Storage:           s,
+		Auth:              auth,
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+		Audit:             a,
+	}
+
+	o, err := s.CreateOccurrence(ctx, "consumer1", "", vulnzOcc(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "debian"))
+	if err != nil {
+		t.Fatalf("Failed to create occurrence %+v", o)
+	}
+	pID, oID, err := name.ParseOccurrence(o.Name)
+	if err != nil {
+		t.Fatalf("Failed to parse occurrence name %q: %v", o.Name, err)
+	}
+	tag, err := etag.Compute(o)
+	if err != nil {
+		t.Fatalf("Failed to compute etag of occurrence %+v: %v", o, err)
+	}
+
+	// An occurrence that changed since it expired is kept.
+	if err := g.ExpireOccurrence(ctx, pID, oID, "d41d8cd98f00b204e9800998ecf8427e"); status.Code(err) != codes.Aborted {
+		t.Errorf("ExpireOccurrence with a stale etag got err %v, want %v", err, codes.Aborted)
+	}
+	// Expiring isn't authorized, as no caller makes it.
+	if err := g.ExpireOccurrence(ctx, pID, oID, tag); err != nil {
+		t.Fatalf("ExpireOccurrence got err %v, want success", err)
+	}
+	if _, err := s.GetOccurrence(ctx, pID, oID); status.Code(err) != codes.NotFound {
+		t.Errorf("GetOccurrence after ExpireOccurrence got err %v, want %v", err, codes.NotFound)
+	}
+	if events := a.events["consumer1"]; len(events) != 1 || events[0].Actor != expireActor || events[0].Method != "ExpireOccurrence" || events[0].Resource != o.Name {
+		t.Errorf("Got audit events %v, want the expiry of %q by %q", events, o.Name, expireActor)
+	}
+	if want := []string{"occurrences projects/consumer1/" + oID}; !reflect.DeepEqual(auth.purged, want) {
+		t.Errorf("ExpireOccurrence purged policies %v, want %v", auth.purged, want)
+	}
+}
+
 func TestDeleteOccurrenceErrors(t *testing.T) {
 	ctx := context.Background()
 
@@ -696,6 +986,279 @@ func TestDeleteOccurrenceErrors(t *testing.T) {
 	}
 }
 
+func TestBatchDeleteOccurrences(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		desc                                 string
+		useNames, dryRun                     bool
+		wantMatched, wantDeleted, wantFailed int32
+		wantRemaining                        int
+	}{
+		{
+			desc:          "by filter",
+			wantMatched:   4,
+			wantDeleted:   2,
+			wantFailed:    2,
+			wantRemaining: 2,
+		},
+		{
+			desc:          "by filter, dry run",
+			dryRun:        true,
+			wantMatched:   4,
+			wantDeleted:   2,
+			wantFailed:    2,
+			wantRemaining: 4,
+		},
+		{
+			desc:          "by name",
+			useNames:      true,
+			wantMatched:   5,
+			wantDeleted:   2,
+			wantFailed:    3,
+			wantRemaining: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		s := newFakeStorage()
+		a := &fakeAuth{deniedEntities: map[string]bool{"CVE-DENIED": true}}
+		g := &API{
+			Storage:           s,
+			Auth:              a,
+			Filter:            &fakeFilter{},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+		}
+
+		// Create two deletable occurrences, one whose note denies attaching occurrences and one that
+		// can't be deleted itself.
+		req := &bulkpb.BatchDeleteOccurrencesRequest{Parent: "projects/consumer1", DryRun: tt.dryRun}
+		for _, noteName := range []string{"projects/goog-vulnz/notes/CVE-UH-OH", "", "projects/goog-vulnz/notes/CVE-DENIED", ""} {
+			o, err := s.CreateOccurrence(ctx, "consumer1", "", vulnzOcc(t, "consumer1", noteName, "debian"))
+			if err != nil {
+				t.Fatalf("Failed to create occurrence %+v", o)
+			}
+			req.Names = append(req.Names, o.Name)
+		}
+		_, deniedID, _ := name.ParseOccurrence(req.Names[3])
+		a.deniedEntities[deniedID] = true
+		if tt.useNames {
+			// Names are deduplicated, and ones that don't exist are reported.
+			req.Names = append(req.Names, req.Names[0], "projects/consumer1/occurrences/1234-abcd-3456-wxyz")
+		} else {
+			req.Names, req.Filter = nil, `kind = "VULNERABILITY"`
+		}
+
+		op := &lrpb.Operation{}
+		if err := g.BatchDeleteOccurrences(ctx, req, op); err != nil {
+			t.Fatalf("%q: got err %v, want success", tt.desc, err)
+		}
+		if !op.Done {
+			t.Fatalf("%q: got operation %+v, want it done", tt.desc, op)
+		}
+		result := &bulkpb.BatchDeleteOccurrencesResponse{}
+		if err := ptypes.UnmarshalAny(op.GetResponse(), result); err != nil {
+			t.Fatalf("%q: failed to unmarshal operation response: %v", tt.desc, err)
+		}
+		if result.MatchedCount != tt.wantMatched || result.DeletedCount != tt.wantDeleted || result.FailedCount != tt.wantFailed {
+			t.Errorf("%q: got matched/deleted/failed %d/%d/%d, want %d/%d/%d", tt.desc,
+				result.MatchedCount, result.DeletedCount, result.FailedCount, tt.wantMatched, tt.wantDeleted, tt.wantFailed)
+		}
+		if got := len(result.Failures); got != int(tt.wantFailed) {
+			t.Errorf("%q: got %d failure samples, want %d", tt.desc, got, tt.wantFailed)
+		}
+		for _, f := range result.Failures {
+			if c := codes.Code(f.Status.Code); c != codes.PermissionDenied && c != codes.NotFound {
+				t.Errorf("%q: got failure %+v, want permission denied or not found", tt.desc, f)
+			}
+		}
+		if got := len(s.occurrences["consumer1"]); got != tt.wantRemaining {
+			t.Errorf("%q: got %d remaining occurrences, want %d", tt.desc, got, tt.wantRemaining)
+		}
+	}
+}
+
+func TestBatchDeleteOccurrencesLongRunning(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	ops := newFakeOperations()
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+		Operations:        ops,
+	}
+
+	num := maxBatchSize + 1
+	for _, o := range vulnzOccs(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "foobar", num) {
+		if _, err := s.CreateOccurrence(ctx, "consumer1", "", o); err != nil {
+			t.Fatalf("Failed to create occurrence %+v", o)
+		}
+	}
+
+	req := &bulkpb.BatchDeleteOccurrencesRequest{Parent: "projects/consumer1", Filter: `kind = "VULNERABILITY"`}
+	op := &lrpb.Operation{}
+	if err := g.BatchDeleteOccurrences(ctx, req, op); err != nil {
+		t.Fatalf("Got err %v, want success", err)
+	}
+	if op.Done {
+		t.Errorf("Got operation %+v, want it running", op)
+	}
+	metadata := &bulkpb.BatchDeleteOccurrencesMetadata{}
+	if err := ptypes.UnmarshalAny(op.Metadata, metadata); err != nil {
+		t.Fatalf("Failed to unmarshal operation metadata: %v", err)
+	}
+	if metadata.TotalCount != int32(num) {
+		t.Errorf("Got total count %d, want %d", metadata.TotalCount, num)
+	}
+
+	<-ops.done
+	op = ops.get(op.Name)
+	result := &bulkpb.BatchDeleteOccurrencesResponse{}
+	if err := ptypes.UnmarshalAny(op.GetResponse(), result); err != nil {
+		t.Fatalf("Failed to unmarshal operation response: %v", err)
+	}
+	if result.DeletedCount != int32(num) {
+		t.Errorf("Got deleted count %d, want %d", result.DeletedCount, num)
+	}
+	if err := ptypes.UnmarshalAny(op.Metadata, metadata); err != nil {
+		t.Fatalf("Failed to unmarshal operation metadata: %v", err)
+	}
+	if metadata.ProcessedCount != int32(num) || metadata.EndTime == nil {
+		t.Errorf("Got metadata %+v, want %d processed and an end time", metadata, num)
+	}
+	if got := len(s.occurrences["consumer1"]); got != 0 {
+		t.Errorf("Got %d remaining occurrences, want 0", got)
+	}
+}
+
+func TestBatchDeleteOccurrencesCancelled(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	ops := newFakeOperations()
+	ops.cancel = true
+	g := &API{
+		Storage:           s,
+		Auth:              &fakeAuth{},
+		Filter:            &fakeFilter{},
+		Logger:            &fakeLogger{},
+		EnforceValidation: true,
+		Operations:        ops,
+	}
+
+	num := maxBatchSize + 1
+	for _, o := range vulnzOccs(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "foobar", num) {
+		if _, err := s.CreateOccurrence(ctx, "consumer1", "", o); err != nil {
+			t.Fatalf("Failed to create occurrence %+v", o)
+		}
+	}
+
+	req := &bulkpb.BatchDeleteOccurrencesRequest{Parent: "projects/consumer1", Filter: `kind = "VULNERABILITY"`}
+	op := &lrpb.Operation{}
+	if err := g.BatchDeleteOccurrences(ctx, req, op); err != nil {
+		t.Fatalf("Got err %v, want success", err)
+	}
+
+	// The deletions stop after the first batch, once they find the operation cancelled.
+	<-ops.rejected
+	if got := len(s.occurrences["consumer1"]); got != num-maxBatchSize {
+		t.Errorf("Got %d remaining occurrences, want %d", got, num-maxBatchSize)
+	}
+	if op := ops.get(op.Name); op.GetError().GetCode() != int32(codes.Canceled) {
+		t.Errorf("Got operation %+v, want it cancelled", op)
+	}
+}
+
+func TestBatchDeleteOccurrencesErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		desc                                   string
+		req                                    *bulkpb.BatchDeleteOccurrencesRequest
+		authErr, filterErr, internalStorageErr bool
+		wantErrStatus                          codes.Code
+	}{
+		{
+			desc:          "invalid project name",
+			req:           &bulkpb.BatchDeleteOccurrencesRequest{Parent: "consumer1", Filter: "kind = VULNERABILITY"},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "neither filter nor names",
+			req:           &bulkpb.BatchDeleteOccurrencesRequest{Parent: "projects/consumer1"},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc: "both filter and names",
+			req: &bulkpb.BatchDeleteOccurrencesRequest{
+				Parent: "projects/consumer1",
+				Filter: "kind = VULNERABILITY",
+				Names:  []string{"projects/consumer1/occurrences/1234"},
+			},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc: "occurrence in another project",
+			req: &bulkpb.BatchDeleteOccurrencesRequest{
+				Parent: "projects/consumer1",
+				Names:  []string{"projects/consumer2/occurrences/1234"},
+			},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc: "invalid occurrence name",
+			req: &bulkpb.BatchDeleteOccurrencesRequest{
+				Parent: "projects/consumer1",
+				Names:  []string{"projects/consumer1/notes/1234"},
+			},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "too many names",
+			req:           &bulkpb.BatchDeleteOccurrencesRequest{Parent: "projects/consumer1", Names: make([]string, maxBatchSize+1)},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "auth error listing occurrences",
+			req:           &bulkpb.BatchDeleteOccurrencesRequest{Parent: "projects/consumer1", Filter: "kind = VULNERABILITY"},
+			authErr:       true,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc:          "invalid filter",
+			req:           &bulkpb.BatchDeleteOccurrencesRequest{Parent: "projects/consumer1", Filter: "kind ="},
+			filterErr:     true,
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:               "internal storage error",
+			req:                &bulkpb.BatchDeleteOccurrencesRequest{Parent: "projects/consumer1", Filter: "kind = VULNERABILITY"},
+			internalStorageErr: true,
+			wantErrStatus:      codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		s := newFakeStorage()
+		s.listOccsErr = tt.internalStorageErr
+		g := &API{
+			Storage:           s,
+			Auth:              &fakeAuth{authErr: tt.authErr},
+			Filter:            &fakeFilter{err: tt.filterErr},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+		}
+
+		err := g.BatchDeleteOccurrences(ctx, tt.req, &lrpb.Operation{})
+		if s, _ := status.FromError(err); s.Code() != tt.wantErrStatus {
+			t.Errorf("%q: got error status %v, want %v", tt.desc, s.Code(), tt.wantErrStatus)
+		}
+	}
+}
+
 func TestListNoteOccurrences(t *testing.T) {
 	ctx := context.Background()
 	s := newFakeStorage()
@@ -0,0 +1,139 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	emptypb "github.com/golang/protobuf/ptypes/empty"
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/name"
+	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// CreateProject creates the specified project.
+func (g *API) CreateProject(ctx context.Context, req *prpb.CreateProjectRequest, resp *prpb.Project) error {
+	if req.Project == nil {
+		return errors.Newf(codes.InvalidArgument, "a project must be specified")
+	}
+	pID, err := name.ParseProject(req.Project.Name)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, "", ProjectsCreate); err != nil {
+		return err
+	}
+
+	if g.Projects == nil {
+		return errors.Newf(codes.Unimplemented, "managing projects is not supported")
+	}
+	p, err := g.Projects.CreateProject(ctx, pID, req.Project)
+	if err != nil {
+		return err
+	}
+	g.audit(ctx, pID, "", "CreateProject", p.Name, nil, nil, p)
+	*resp = *p
+
+	return nil
+}
+
+// GetProject gets the specified project.
+func (g *API) GetProject(ctx context.Context, req *prpb.GetProjectRequest, resp *prpb.Project) error {
+	pID, err := name.ParseProject(req.Name)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, "", ProjectsGet); err != nil {
+		return err
+	}
+
+	if g.Projects == nil {
+		return errors.Newf(codes.Unimplemented, "managing projects is not supported")
+	}
+	p, err := g.Projects.GetProject(ctx, pID)
+	if err != nil {
+		return err
+	}
+	*resp = *p
+
+	return nil
+}
+
+// ListProjects lists projects. Listing them isn't scoped to a project, so the caller needs
+// permission to list projects in every project.
+func (g *API) ListProjects(ctx context.Context, req *prpb.ListProjectsRequest, resp *prpb.ListProjectsResponse) error {
+	if err := g.Auth.CheckAccessAndProject(ctx, "", "", ProjectsList); err != nil {
+		return err
+	}
+
+	if g.Projects == nil {
+		return errors.Newf(codes.Unimplemented, "managing projects is not supported")
+	}
+	ps, err := validatePageSize(req.PageSize)
+	if err != nil {
+		return err
+	}
+	if err := g.Filter.Validate(req.Filter); err != nil {
+		return err
+	}
+
+	projects, npt, err := g.Projects.ListProjects(ctx, req.Filter, req.PageToken, ps)
+	if err != nil {
+		return err
+	}
+	resp.Projects = projects
+	resp.NextPageToken = npt
+
+	return nil
+}
+
+// DeleteProject deletes the specified project.
+func (g *API) DeleteProject(ctx context.Context, req *prpb.DeleteProjectRequest, _ *emptypb.Empty) error {
+	pID, err := name.ParseProject(req.Name)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, "", ProjectsDelete); err != nil {
+		return err
+	}
+
+	if g.Projects == nil {
+		return errors.Newf(codes.Unimplemented, "managing projects is not supported")
+	}
+	if err := g.Projects.DeleteProject(ctx, pID); err != nil {
+		return err
+	}
+	g.audit(ctx, pID, "", "DeleteProject", req.Name, nil, nil, nil)
+
+	// Purge any IAM policies set on this project, unless the project can still be undeleted.
+	if g.Tombstones != nil {
+		return nil
+	}
+	if err := g.Auth.PurgePolicy(ctx, pID, "", Projects); err != nil {
+		// This fails open, should not block on policy deletion failure.
+		g.Logger.Warningf(ctx, "Error deleting policies for project %q: %v", pID, err)
+	}
+
+	return nil
+}
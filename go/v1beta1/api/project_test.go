@@ -0,0 +1,154 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"reflect"
+	"testing"
+
+	emptypb "github.com/golang/protobuf/ptypes/empty"
+	"github.com/grafeas/grafeas/go/iam"
+	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestProjects(t *testing.T) {
+	ctx := context.Background()
+	s := &fakeProjects{projects: map[string]*prpb.Project{}}
+	a := &fakeAudit{}
+	auth := &fakeAuth{}
+	g := &API{
+		Storage:  newFakeStorage(),
+		Auth:     auth,
+		Filter:   &fakeFilter{},
+		Logger:   &fakeLogger{},
+		Projects: s,
+		Audit:    a,
+	}
+
+	p := &prpb.Project{}
+	if err := g.CreateProject(ctx, &prpb.CreateProjectRequest{Project: &prpb.Project{Name: "projects/consumer1"}}, p); err != nil {
+		t.Fatalf("CreateProject got err %v, want success", err)
+	}
+	if err := g.GetProject(ctx, &prpb.GetProjectRequest{Name: "projects/consumer1"}, p); err != nil || p.Name != "projects/consumer1" {
+		t.Errorf("GetProject got %v, %v, want projects/consumer1", p, err)
+	}
+	resp := &prpb.ListProjectsResponse{}
+	if err := g.ListProjects(ctx, &prpb.ListProjectsRequest{}, resp); err != nil || len(resp.Projects) != 1 {
+		t.Errorf("ListProjects got %v, %v, want projects/consumer1", resp.Projects, err)
+	}
+	if err := g.DeleteProject(ctx, &prpb.DeleteProjectRequest{Name: "projects/consumer1"}, &emptypb.Empty{}); err != nil {
+		t.Fatalf("DeleteProject got err %v, want success", err)
+	}
+	if _, ok := s.projects["consumer1"]; ok {
+		t.Error("DeleteProject left the project")
+	}
+
+	var methods []string
+	for _, e := range a.events["consumer1"] {
+		methods = append(methods, e.Method+" "+e.Resource)
+	}
+	want := []string{"CreateProject projects/consumer1", "DeleteProject projects/consumer1"}
+	if !reflect.DeepEqual(methods, want) {
+		t.Errorf("got audit events %v, want %v", methods, want)
+	}
+	if want := []string{"projects projects/consumer1/"}; !reflect.DeepEqual(auth.purged, want) {
+		t.Errorf("DeleteProject purged policies %v, want %v", auth.purged, want)
+	}
+}
+
+func TestProjectsErrors(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		desc          string
+		call          func(g *API) error
+		denied        iam.Permission
+		noProjects    bool
+		wantErrStatus codes.Code
+	}{
+		{
+			desc: "create denied",
+			call: func(g *API) error {
+				return g.CreateProject(ctx, &prpb.CreateProjectRequest{Project: &prpb.Project{Name: "projects/consumer1"}}, &prpb.Project{})
+			},
+			denied:        ProjectsCreate,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc: "nil project",
+			call: func(g *API) error {
+				return g.CreateProject(ctx, &prpb.CreateProjectRequest{}, &prpb.Project{})
+			},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc: "get denied",
+			call: func(g *API) error {
+				return g.GetProject(ctx, &prpb.GetProjectRequest{Name: "projects/consumer1"}, &prpb.Project{})
+			},
+			denied:        ProjectsGet,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc: "list denied",
+			call: func(g *API) error {
+				return g.ListProjects(ctx, &prpb.ListProjectsRequest{}, &prpb.ListProjectsResponse{})
+			},
+			denied:        ProjectsList,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc: "delete denied",
+			call: func(g *API) error {
+				return g.DeleteProject(ctx, &prpb.DeleteProjectRequest{Name: "projects/consumer1"}, &emptypb.Empty{})
+			},
+			denied:        ProjectsDelete,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc: "invalid name",
+			call: func(g *API) error {
+				return g.DeleteProject(ctx, &prpb.DeleteProjectRequest{Name: "consumer1"}, &emptypb.Empty{})
+			},
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc: "no project storage",
+			call: func(g *API) error {
+				return g.GetProject(ctx, &prpb.GetProjectRequest{Name: "projects/consumer1"}, &prpb.Project{})
+			},
+			noProjects:    true,
+			wantErrStatus: codes.Unimplemented,
+		},
+	}
+
+	for _, tt := range tests {
+		g := &API{
+			Storage:  newFakeStorage(),
+			Auth:     &fakeAuth{deniedPermissions: map[iam.Permission]bool{tt.denied: true}},
+			Filter:   &fakeFilter{},
+			Logger:   &fakeLogger{},
+			Projects: &fakeProjects{projects: map[string]*prpb.Project{"consumer1": {Name: "projects/consumer1"}}},
+		}
+		if tt.noProjects {
+			g.Projects = nil
+		}
+		if err := tt.call(g); status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: got error status %v, want %v", tt.desc, status.Code(err), tt.wantErrStatus)
+		}
+	}
+}
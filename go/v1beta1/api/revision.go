@@ -0,0 +1,108 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"github.com/golang/protobuf/ptypes"
+	tpb "github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/name"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// ListNoteRevisions lists the previous revisions of the specified note, newest first.
+func (g *API) ListNoteRevisions(ctx context.Context, req *revpb.ListNoteRevisionsRequest, resp *revpb.ListNoteRevisionsResponse) error {
+	pID, nID, err := name.ParseNote(req.Name)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, nID, NotesGet); err != nil {
+		return err
+	}
+
+	if g.Revisions == nil {
+		return errors.Newf(codes.Unimplemented, "revisions are not supported")
+	}
+	ps, err := validatePageSize(req.PageSize)
+	if err != nil {
+		return err
+	}
+
+	revs, npt, err := g.Revisions.ListNoteRevisions(ctx, pID, nID, req.PageToken, ps)
+	if err != nil {
+		return err
+	}
+	resp.Revisions = revs
+	resp.NextPageToken = npt
+
+	return nil
+}
+
+// ListOccurrenceRevisions lists the previous revisions of the specified occurrence, newest first.
+func (g *API) ListOccurrenceRevisions(ctx context.Context, req *revpb.ListOccurrenceRevisionsRequest, resp *revpb.ListOccurrenceRevisionsResponse) error {
+	pID, oID, err := name.ParseOccurrence(req.Name)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, oID, OccurrencesGet); err != nil {
+		return err
+	}
+
+	if g.Revisions == nil {
+		return errors.Newf(codes.Unimplemented, "revisions are not supported")
+	}
+	ps, err := validatePageSize(req.PageSize)
+	if err != nil {
+		return err
+	}
+
+	revs, npt, err := g.Revisions.ListOccurrenceRevisions(ctx, pID, oID, req.PageToken, ps)
+	if err != nil {
+		return err
+	}
+	resp.Revisions = revs
+	resp.NextPageToken = npt
+
+	return nil
+}
+
+// getNoteAt gets the specified note as it was at the specified time. No etag is returned, as the
+// note may have been updated since.
+func (g *API) getNoteAt(ctx context.Context, pID, nID string, ts *tpb.Timestamp, resp *gpb.Note) error {
+	if g.Revisions == nil {
+		return errors.Newf(codes.Unimplemented, "reading notes as of a past time is not supported")
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return errors.Newf(codes.InvalidArgument, "invalid read time: %v", err)
+	}
+
+	n, err := g.Revisions.GetNoteAt(ctx, pID, nID, t)
+	if err != nil {
+		return err
+	}
+	*resp = *n
+
+	return nil
+}
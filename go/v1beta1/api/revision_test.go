@@ -0,0 +1,187 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	tpb "github.com/golang/protobuf/ptypes/timestamp"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListRevisions(t *testing.T) {
+	ctx := context.Background()
+	nName := "projects/goog-vulnz/notes/CVE-UH-OH"
+	oName := "projects/consumer1/occurrences/1234"
+	r := &fakeRevisions{
+		notes: map[string][]*revpb.NoteRevision{
+			nName: {
+				{Note: &gpb.Note{Name: nName, ShortDescription: "2"}, ReplaceTime: ptypes.TimestampNow()},
+				{Note: &gpb.Note{Name: nName, ShortDescription: "1"}, ReplaceTime: ptypes.TimestampNow()},
+			},
+		},
+		occurrences: map[string][]*revpb.OccurrenceRevision{
+			oName: {{Occurrence: &gpb.Occurrence{Name: oName, Remediation: "1"}, ReplaceTime: ptypes.TimestampNow()}},
+		},
+	}
+	g := &API{
+		Storage:   newFakeStorage(),
+		Auth:      &fakeAuth{},
+		Filter:    &fakeFilter{},
+		Logger:    &fakeLogger{},
+		Revisions: r,
+	}
+
+	nResp := &revpb.ListNoteRevisionsResponse{}
+	if err := g.ListNoteRevisions(ctx, &revpb.ListNoteRevisionsRequest{Name: nName}, nResp); err != nil {
+		t.Fatalf("ListNoteRevisions got err %v, want success", err)
+	}
+	if len(nResp.Revisions) != 2 || !proto.Equal(nResp.Revisions[0], r.notes[nName][0]) {
+		t.Errorf("ListNoteRevisions got %v, want %v", nResp.Revisions, r.notes[nName])
+	}
+	oResp := &revpb.ListOccurrenceRevisionsResponse{}
+	if err := g.ListOccurrenceRevisions(ctx, &revpb.ListOccurrenceRevisionsRequest{Name: oName}, oResp); err != nil {
+		t.Fatalf("ListOccurrenceRevisions got err %v, want success", err)
+	}
+	if len(oResp.Revisions) != 1 || !proto.Equal(oResp.Revisions[0], r.occurrences[oName][0]) {
+		t.Errorf("ListOccurrenceRevisions got %v, want %v", oResp.Revisions, r.occurrences[oName])
+	}
+}
+
+func TestListRevisionsErrors(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		desc          string
+		name          string
+		pageSize      int32
+		authErr       bool
+		noRevisions   bool
+		revisionsErr  bool
+		wantErrStatus codes.Code
+	}{
+		{
+			desc:          "invalid name",
+			name:          "projects/goog-vulnz",
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "auth error",
+			authErr:       true,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc:          "revisions not supported",
+			noRevisions:   true,
+			wantErrStatus: codes.Unimplemented,
+		},
+		{
+			desc:          "negative page size",
+			pageSize:      -1,
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "storage error",
+			revisionsErr:  true,
+			wantErrStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		g := &API{
+			Storage: newFakeStorage(),
+			Auth:    &fakeAuth{authErr: tt.authErr},
+			Filter:  &fakeFilter{},
+			Logger:  &fakeLogger{},
+		}
+		if !tt.noRevisions {
+			g.Revisions = &fakeRevisions{err: tt.revisionsErr}
+		}
+		nName, oName := "projects/goog-vulnz/notes/CVE-UH-OH", "projects/consumer1/occurrences/1234"
+		if tt.name != "" {
+			nName, oName = tt.name, tt.name
+		}
+		nReq := &revpb.ListNoteRevisionsRequest{Name: nName, PageSize: tt.pageSize}
+		if err := g.ListNoteRevisions(ctx, nReq, &revpb.ListNoteRevisionsResponse{}); status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: ListNoteRevisions(%v) got error code %v, want %v", tt.desc, nReq, status.Code(err), tt.wantErrStatus)
+		}
+		oReq := &revpb.ListOccurrenceRevisionsRequest{Name: oName, PageSize: tt.pageSize}
+		if err := g.ListOccurrenceRevisions(ctx, oReq, &revpb.ListOccurrenceRevisionsResponse{}); status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: ListOccurrenceRevisions(%v) got error code %v, want %v", tt.desc, oReq, status.Code(err), tt.wantErrStatus)
+		}
+	}
+}
+
+func TestGetNoteReadTime(t *testing.T) {
+	ctx := context.Background()
+	nName := "projects/goog-vulnz/notes/CVE-UH-OH"
+	now := time.Now()
+	ts := func(d time.Duration) *tpb.Timestamp {
+		ts, err := ptypes.TimestampProto(now.Add(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+	// The note was updated an hour ago and a minute ago.
+	r := &fakeRevisions{
+		notes: map[string][]*revpb.NoteRevision{
+			nName: {
+				{Note: &gpb.Note{Name: nName, ShortDescription: "2"}, ReplaceTime: ts(-time.Minute)},
+				{Note: &gpb.Note{Name: nName, ShortDescription: "1"}, ReplaceTime: ts(-time.Hour)},
+			},
+		},
+	}
+	g := &API{
+		Storage:   newFakeStorage(),
+		Auth:      &fakeAuth{},
+		Filter:    &fakeFilter{},
+		Logger:    &fakeLogger{},
+		Revisions: r,
+	}
+
+	tests := []struct {
+		readTime      *tpb.Timestamp
+		wantDesc      string
+		wantErrStatus codes.Code
+	}{
+		{readTime: ts(-2 * time.Hour), wantDesc: "1"},
+		{readTime: ts(-30 * time.Minute), wantDesc: "2"},
+		{readTime: ts(time.Second), wantErrStatus: codes.NotFound},
+		{readTime: &tpb.Timestamp{Nanos: -1}, wantErrStatus: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		got := &gpb.Note{}
+		err := g.GetNote(ctx, &gpb.GetNoteRequest{Name: nName, ReadTime: tt.readTime}, got)
+		if status.Code(err) != tt.wantErrStatus {
+			t.Errorf("GetNote as of %v got err %v, want code %v", tt.readTime, err, tt.wantErrStatus)
+			continue
+		}
+		if err == nil && got.ShortDescription != tt.wantDesc {
+			t.Errorf("GetNote as of %v got revision %q, want %q", tt.readTime, got.ShortDescription, tt.wantDesc)
+		}
+	}
+
+	g.Revisions = nil
+	if err := g.GetNote(ctx, &gpb.GetNoteRequest{Name: nName, ReadTime: ts(0)}, &gpb.Note{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("GetNote as of a time without revisions got err %v, want %v", err, codes.Unimplemented)
+	}
+}
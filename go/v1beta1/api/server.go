@@ -0,0 +1,401 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"io"
+
+	emptypb "github.com/golang/protobuf/ptypes/empty"
+	"github.com/grafeas/grafeas/go/errors"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
+	bulkpb "github.com/grafeas/grafeas/proto/v1beta1/bulk_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	iamsvcpb "github.com/grafeas/grafeas/proto/v1beta1/iam_go_proto"
+	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
+	udpb "github.com/grafeas/grafeas/proto/v1beta1/undelete_go_proto"
+	upsertpb "github.com/grafeas/grafeas/proto/v1beta1/upsert_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
+	"golang.org/x/net/context"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc/codes"
+)
+
+// Server exposes an API as gRPC services, so that it can be registered with
+// RegisterGrafeasV1Beta1Server, RegisterProjectsServer, RegisterGrafeasWatchV1Beta1Server,
+// RegisterGrafeasBulkV1Beta1Server, RegisterGrafeasIamV1Beta1Server,
+// RegisterGrafeasAuditV1Beta1Server, RegisterGrafeasRevisionsV1Beta1Server,
+// RegisterGrafeasUndeleteV1Beta1Server and RegisterGrafeasUpsertV1Beta1Server.
+type Server struct {
+	API *API
+}
+
+var (
+	_ gpb.GrafeasV1Beta1Server            = (*Server)(nil)
+	_ prpb.ProjectsServer                 = (*Server)(nil)
+	_ watchpb.GrafeasWatchV1Beta1Server   = (*Server)(nil)
+	_ bulkpb.GrafeasBulkV1Beta1Server     = (*Server)(nil)
+	_ iamsvcpb.GrafeasIamV1Beta1Server    = (*Server)(nil)
+	_ auditpb.GrafeasAuditV1Beta1Server   = (*Server)(nil)
+	_ revpb.GrafeasRevisionsV1Beta1Server = (*Server)(nil)
+	_ udpb.GrafeasUndeleteV1Beta1Server   = (*Server)(nil)
+	_ upsertpb.GrafeasUpsertV1Beta1Server = (*Server)(nil)
+)
+
+// CreateProject creates the specified project.
+func (s *Server) CreateProject(ctx context.Context, req *prpb.CreateProjectRequest) (*prpb.Project, error) {
+	resp := &prpb.Project{}
+	if err := s.API.CreateProject(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetProject gets the specified project.
+func (s *Server) GetProject(ctx context.Context, req *prpb.GetProjectRequest) (*prpb.Project, error) {
+	resp := &prpb.Project{}
+	if err := s.API.GetProject(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListProjects lists projects.
+func (s *Server) ListProjects(ctx context.Context, req *prpb.ListProjectsRequest) (*prpb.ListProjectsResponse, error) {
+	resp := &prpb.ListProjectsResponse{}
+	if err := s.API.ListProjects(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteProject deletes the specified project.
+func (s *Server) DeleteProject(ctx context.Context, req *prpb.DeleteProjectRequest) (*emptypb.Empty, error) {
+	resp := &emptypb.Empty{}
+	if err := s.API.DeleteProject(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetOccurrence gets the specified occurrence.
+func (s *Server) GetOccurrence(ctx context.Context, req *gpb.GetOccurrenceRequest) (*gpb.Occurrence, error) {
+	resp := &gpb.Occurrence{}
+	if err := s.API.GetOccurrence(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListOccurrences lists occurrences for the specified project.
+func (s *Server) ListOccurrences(ctx context.Context, req *gpb.ListOccurrencesRequest) (*gpb.ListOccurrencesResponse, error) {
+	resp := &gpb.ListOccurrencesResponse{}
+	if err := s.API.ListOccurrences(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteOccurrence deletes the specified occurrence.
+func (s *Server) DeleteOccurrence(ctx context.Context, req *gpb.DeleteOccurrenceRequest) (*emptypb.Empty, error) {
+	resp := &emptypb.Empty{}
+	if err := s.API.DeleteOccurrence(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CreateOccurrence creates the specified occurrence.
+func (s *Server) CreateOccurrence(ctx context.Context, req *gpb.CreateOccurrenceRequest) (*gpb.Occurrence, error) {
+	resp := &gpb.Occurrence{}
+	if err := s.API.CreateOccurrence(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BatchCreateOccurrences batch creates the specified occurrences.
+func (s *Server) BatchCreateOccurrences(ctx context.Context, req *gpb.BatchCreateOccurrencesRequest) (*gpb.BatchCreateOccurrencesResponse, error) {
+	resp := &gpb.BatchCreateOccurrencesResponse{}
+	if err := s.API.BatchCreateOccurrences(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateOccurrence updates the specified occurrence.
+func (s *Server) UpdateOccurrence(ctx context.Context, req *gpb.UpdateOccurrenceRequest) (*gpb.Occurrence, error) {
+	resp := &gpb.Occurrence{}
+	if err := s.API.UpdateOccurrence(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetOccurrenceNote gets the note for the specified occurrence.
+func (s *Server) GetOccurrenceNote(ctx context.Context, req *gpb.GetOccurrenceNoteRequest) (*gpb.Note, error) {
+	resp := &gpb.Note{}
+	if err := s.API.GetOccurrenceNote(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetNote gets the specified note.
+func (s *Server) GetNote(ctx context.Context, req *gpb.GetNoteRequest) (*gpb.Note, error) {
+	resp := &gpb.Note{}
+	if err := s.API.GetNote(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListNotes lists notes for the specified project.
+func (s *Server) ListNotes(ctx context.Context, req *gpb.ListNotesRequest) (*gpb.ListNotesResponse, error) {
+	resp := &gpb.ListNotesResponse{}
+	if err := s.API.ListNotes(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteNote deletes the specified note.
+func (s *Server) DeleteNote(ctx context.Context, req *gpb.DeleteNoteRequest) (*emptypb.Empty, error) {
+	resp := &emptypb.Empty{}
+	if err := s.API.DeleteNote(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CreateNote creates the specified note.
+func (s *Server) CreateNote(ctx context.Context, req *gpb.CreateNoteRequest) (*gpb.Note, error) {
+	resp := &gpb.Note{}
+	if err := s.API.CreateNote(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BatchCreateNotes batch creates the specified notes.
+func (s *Server) BatchCreateNotes(ctx context.Context, req *gpb.BatchCreateNotesRequest) (*gpb.BatchCreateNotesResponse, error) {
+	resp := &gpb.BatchCreateNotesResponse{}
+	if err := s.API.BatchCreateNotes(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateNote updates the specified note.
+func (s *Server) UpdateNote(ctx context.Context, req *gpb.UpdateNoteRequest) (*gpb.Note, error) {
+	resp := &gpb.Note{}
+	if err := s.API.UpdateNote(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListNoteOccurrences lists occurrences for the specified note.
+func (s *Server) ListNoteOccurrences(ctx context.Context, req *gpb.ListNoteOccurrencesRequest) (*gpb.ListNoteOccurrencesResponse, error) {
+	resp := &gpb.ListNoteOccurrencesResponse{}
+	if err := s.API.ListNoteOccurrences(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetVulnerabilityOccurrencesSummary gets a summary of vulnerability occurrences.
+func (s *Server) GetVulnerabilityOccurrencesSummary(ctx context.Context, req *gpb.GetVulnerabilityOccurrencesSummaryRequest) (*gpb.VulnerabilityOccurrencesSummary, error) {
+	resp := &gpb.VulnerabilityOccurrencesSummary{}
+	if err := s.API.GetVulnerabilityOccurrencesSummary(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// WatchOccurrences streams the changes to the occurrences matching the request until the client
+// cancels the call.
+func (s *Server) WatchOccurrences(req *watchpb.WatchOccurrencesRequest, stream watchpb.GrafeasWatchV1Beta1_WatchOccurrencesServer) error {
+	return s.API.WatchOccurrences(stream.Context(), req, stream.Send)
+}
+
+// BatchDeleteOccurrences deletes the occurrences matching the request.
+func (s *Server) BatchDeleteOccurrences(ctx context.Context, req *bulkpb.BatchDeleteOccurrencesRequest) (*lrpb.Operation, error) {
+	resp := &lrpb.Operation{}
+	if err := s.API.BatchDeleteOccurrences(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetIamPolicy gets the IAM policy of the specified project or note.
+func (s *Server) GetIamPolicy(ctx context.Context, req *iampb.GetIamPolicyRequest) (*iampb.Policy, error) {
+	resp := &iampb.Policy{}
+	if err := s.API.GetIamPolicy(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SetIamPolicy replaces the IAM policy of the specified project or note.
+func (s *Server) SetIamPolicy(ctx context.Context, req *iampb.SetIamPolicyRequest) (*iampb.Policy, error) {
+	resp := &iampb.Policy{}
+	if err := s.API.SetIamPolicy(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// TestIamPermissions returns the permissions the caller has on the specified project or note.
+func (s *Server) TestIamPermissions(ctx context.Context, req *iampb.TestIamPermissionsRequest) (*iampb.TestIamPermissionsResponse, error) {
+	resp := &iampb.TestIamPermissionsResponse{}
+	if err := s.API.TestIamPermissions(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListAuditEvents lists the audit events of the specified project.
+func (s *Server) ListAuditEvents(ctx context.Context, req *auditpb.ListAuditEventsRequest) (*auditpb.ListAuditEventsResponse, error) {
+	resp := &auditpb.ListAuditEventsResponse{}
+	if err := s.API.ListAuditEvents(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListNoteRevisions lists the previous revisions of the specified note.
+func (s *Server) ListNoteRevisions(ctx context.Context, req *revpb.ListNoteRevisionsRequest) (*revpb.ListNoteRevisionsResponse, error) {
+	resp := &revpb.ListNoteRevisionsResponse{}
+	if err := s.API.ListNoteRevisions(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListOccurrenceRevisions lists the previous revisions of the specified occurrence.
+func (s *Server) ListOccurrenceRevisions(ctx context.Context, req *revpb.ListOccurrenceRevisionsRequest) (*revpb.ListOccurrenceRevisionsResponse, error) {
+	resp := &revpb.ListOccurrenceRevisionsResponse{}
+	if err := s.API.ListOccurrenceRevisions(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Undelete restores the specified deleted project or note.
+func (s *Server) Undelete(ctx context.Context, req *udpb.UndeleteRequest) (*emptypb.Empty, error) {
+	resp := &emptypb.Empty{}
+	if err := s.API.Undelete(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpsertOccurrence updates the occurrence for the same resource and note as the specified one, or
+// creates it.
+func (s *Server) UpsertOccurrence(ctx context.Context, req *upsertpb.UpsertOccurrenceRequest) (*gpb.Occurrence, error) {
+	resp := &gpb.Occurrence{}
+	if err := s.API.UpsertOccurrence(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BatchUpsertOccurrences batch upserts the specified occurrences.
+func (s *Server) BatchUpsertOccurrences(ctx context.Context, req *upsertpb.BatchUpsertOccurrencesRequest) (*upsertpb.BatchUpsertOccurrencesResponse, error) {
+	resp := &upsertpb.BatchUpsertOccurrencesResponse{}
+	if err := s.API.BatchUpsertOccurrences(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ImportNotes imports the notes of the file named by the first message of the stream or uploaded
+// with the stream.
+func (s *Server) ImportNotes(stream bulkpb.GrafeasBulkV1Beta1_ImportNotesServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return firstRecvError(err)
+	}
+	upload := &chunkReader{next: func() ([]byte, error) {
+		r, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if r.Parent != "" || r.Format != bulkpb.ImportFormat_IMPORT_FORMAT_UNSPECIFIED || r.GetPath() != "" {
+			return nil, errors.Newf(codes.InvalidArgument, "only the first message can set parent, format or path")
+		}
+		return r.GetData(), nil
+	}}
+	resp := &lrpb.Operation{}
+	if err := s.API.ImportNotes(stream.Context(), req, upload, resp); err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
+
+// ImportOccurrences imports the occurrences of the file named by the first message of the stream or
+// uploaded with the stream.
+func (s *Server) ImportOccurrences(stream bulkpb.GrafeasBulkV1Beta1_ImportOccurrencesServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return firstRecvError(err)
+	}
+	upload := &chunkReader{next: func() ([]byte, error) {
+		r, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if r.Parent != "" || r.Format != bulkpb.ImportFormat_IMPORT_FORMAT_UNSPECIFIED || r.GetPath() != "" {
+			return nil, errors.Newf(codes.InvalidArgument, "only the first message can set parent, format or path")
+		}
+		return r.GetData(), nil
+	}}
+	resp := &lrpb.Operation{}
+	if err := s.API.ImportOccurrences(stream.Context(), req, upload, resp); err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
+
+// firstRecvError returns the error to fail a client streaming call with if receiving its first
+// message failed with err.
+func firstRecvError(err error) error {
+	if err == io.EOF {
+		return errors.Newf(codes.InvalidArgument, "a request must be specified")
+	}
+	return err
+}
+
+// chunkReader reads the data of the chunks returned by next until it returns an error, io.EOF at
+// the end of the chunks.
+type chunkReader struct {
+	next  func() ([]byte, error)
+	chunk []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		c, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = c
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	bulkpb "github.com/grafeas/grafeas/proto/v1beta1/bulk_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
+	"golang.org/x/net/context"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{
+		API: &API{
+			Storage:           newFakeStorage(),
+			Auth:              &fakeAuth{},
+			Filter:            &fakeFilter{},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+		},
+	}
+
+	n := vulnzNote(t)
+	createdN, err := s.CreateNote(ctx, &gpb.CreateNoteRequest{Parent: "projects/goog-vulnz", NoteId: "CVE-UH-OH", Note: n})
+	if err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	if createdN.Name != "projects/goog-vulnz/notes/CVE-UH-OH" {
+		t.Errorf("CreateNote got name %q, want %q", createdN.Name, "projects/goog-vulnz/notes/CVE-UH-OH")
+	}
+
+	gotN, err := s.GetNote(ctx, &gpb.GetNoteRequest{Name: createdN.Name})
+	if err != nil {
+		t.Fatalf("GetNote got err %v, want success", err)
+	}
+	if diff := cmp.Diff(createdN, gotN); diff != "" {
+		t.Errorf("GetNote returned diff (want -> got):\n%s", diff)
+	}
+
+	if _, err := s.DeleteNote(ctx, &gpb.DeleteNoteRequest{Name: createdN.Name}); err != nil {
+		t.Fatalf("DeleteNote got err %v, want success", err)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{
+		API: &API{
+			Storage:           newFakeStorage(),
+			Auth:              &fakeAuth{},
+			Filter:            &fakeFilter{},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+		},
+	}
+
+	got, err := s.GetNote(ctx, &gpb.GetNoteRequest{Name: "projects/goog-vulnz/notes/CVE-UH-OH"})
+	if got != nil {
+		t.Errorf("GetNote of a missing note got %v, want nil", got)
+	}
+	if c := status.Code(err); c != codes.NotFound {
+		t.Errorf("GetNote of a missing note got error code %v, want %v", c, codes.NotFound)
+	}
+	if _, err := s.ListOccurrences(ctx, &gpb.ListOccurrencesRequest{Parent: "projects"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListOccurrences with an invalid parent got err %v, want %v", err, codes.InvalidArgument)
+	}
+}
+
+// fakeWatchStream collects the events sent on a WatchOccurrences stream.
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*watchpb.OccurrenceEvent
+}
+
+func (s *fakeWatchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeWatchStream) Send(e *watchpb.OccurrenceEvent) error {
+	s.events = append(s.events, e)
+	return nil
+}
+
+func TestServerWatchOccurrences(t *testing.T) {
+	fs := newFakeStorage()
+	s := &Server{
+		API: &API{
+			Storage:           fs,
+			Auth:              &fakeAuth{},
+			Filter:            &fakeFilter{},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+			Watch:             fs,
+		},
+	}
+
+	o := vulnzOcc(t, "consumer1", "projects/goog-vulnz/notes/CVE-UH-OH", "debian")
+	fs.events["consumer1"] = []*watchpb.OccurrenceEvent{
+		{Type: watchpb.OccurrenceEvent_CREATED, Occurrence: o, Cursor: "1"},
+	}
+
+	stream := &fakeWatchStream{ctx: context.Background()}
+	req := &watchpb.WatchOccurrencesRequest{Parent: "projects/consumer1"}
+	if err := s.WatchOccurrences(req, stream); err != nil {
+		t.Fatalf("WatchOccurrences got err %v, want success", err)
+	}
+	if diff := cmp.Diff(fs.events["consumer1"], stream.events); diff != "" {
+		t.Errorf("WatchOccurrences(%v) returned diff (want -> got):\n%s", req, diff)
+	}
+}
+
+// fakeImportNotesStream receives the specified requests on an ImportNotes stream and collects the
+// operation sent.
+type fakeImportNotesStream struct {
+	grpc.ServerStream
+	reqs []*bulkpb.ImportNotesRequest
+	op   *lrpb.Operation
+}
+
+func (s *fakeImportNotesStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeImportNotesStream) Recv() (*bulkpb.ImportNotesRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *fakeImportNotesStream) SendAndClose(op *lrpb.Operation) error {
+	s.op = op
+	return nil
+}
+
+func TestServerImportNotes(t *testing.T) {
+	fs := newFakeStorage()
+	s := &Server{
+		API: &API{
+			Storage:           fs,
+			Auth:              &fakeAuth{},
+			Filter:            &fakeFilter{},
+			Logger:            &fakeLogger{},
+			EnforceValidation: true,
+		},
+	}
+
+	var data []byte
+	for _, id := range []string{"CVE-1", "CVE-2"} {
+		n := vulnzNote(t)
+		n.Name = "projects/goog-vulnz/notes/" + id
+		data = append(data, jsonLine(t, n)+"\n"...)
+	}
+	chunk := func(b []byte) *bulkpb.ImportNotesRequest {
+		return &bulkpb.ImportNotesRequest{Source: &bulkpb.ImportNotesRequest_Data{Data: b}}
+	}
+	first := chunk(data[:5])
+	first.Parent, first.Format = "projects/goog-vulnz", bulkpb.ImportFormat_JSON_LINES
+
+	stream := &fakeImportNotesStream{reqs: []*bulkpb.ImportNotesRequest{first, chunk(data[5:50]), chunk(nil), chunk(data[50:])}}
+	if err := s.ImportNotes(stream); err != nil {
+		t.Fatalf("ImportNotes got err %v, want success", err)
+	}
+	if stream.op == nil || !stream.op.Done {
+		t.Errorf("ImportNotes sent operation %+v, want a done one", stream.op)
+	}
+	if got := len(fs.notes["goog-vulnz"]); got != 2 {
+		t.Errorf("ImportNotes created %d notes, want 2", got)
+	}
+
+	// Only the first message sets the parent.
+	again := chunk(data)
+	again.Parent = "projects/goog-vulnz"
+	stream = &fakeImportNotesStream{reqs: []*bulkpb.ImportNotesRequest{first, again}}
+	if err := s.ImportNotes(stream); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ImportNotes with two parents got err %v, want %v", err, codes.InvalidArgument)
+	}
+	if err := s.ImportNotes(&fakeImportNotesStream{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ImportNotes without requests got err %v, want %v", err, codes.InvalidArgument)
+	}
+}
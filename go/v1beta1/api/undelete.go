@@ -0,0 +1,118 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"strings"
+	"time"
+
+	emptypb "github.com/golang/protobuf/ptypes/empty"
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/name"
+	udpb "github.com/grafeas/grafeas/proto/v1beta1/undelete_go_proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// purgeActor is the actor recorded in the audit log for the purges of deleted projects and notes,
+// which no caller makes.
+const purgeActor = "system:purge"
+
+// Undelete restores the specified project or note if it was deleted within DeleteRetention.
+func (g *API) Undelete(ctx context.Context, req *udpb.UndeleteRequest, _ *emptypb.Empty) error {
+	if strings.Count(req.Name, "/") == 1 {
+		return g.undeleteProject(ctx, req.Name)
+	}
+	return g.undeleteNote(ctx, req.Name)
+}
+
+func (g *API) undeleteProject(ctx context.Context, pName string) error {
+	pID, err := name.ParseProject(pName)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, "", ProjectsUndelete); err != nil {
+		return err
+	}
+
+	if g.Tombstones == nil {
+		return errors.Newf(codes.Unimplemented, "undeleting is not supported")
+	}
+	if err := g.Tombstones.UndeleteProject(ctx, pID, time.Now().Add(-g.DeleteRetention)); err != nil {
+		return err
+	}
+	g.audit(ctx, pID, "", "Undelete", pName, nil, nil, nil)
+
+	return nil
+}
+
+func (g *API) undeleteNote(ctx context.Context, nName string) error {
+	pID, nID, err := name.ParseNote(nName)
+	if err != nil {
+		return err
+	}
+
+	ctx = g.Logger.PrepareCtx(ctx, pID)
+
+	if err := g.Auth.CheckAccessAndProject(ctx, pID, nID, NotesUndelete); err != nil {
+		return err
+	}
+
+	if g.Tombstones == nil {
+		return errors.Newf(codes.Unimplemented, "undeleting is not supported")
+	}
+	n, err := g.Tombstones.UndeleteNote(ctx, pID, nID, time.Now().Add(-g.DeleteRetention))
+	if err != nil {
+		return err
+	}
+	g.audit(ctx, pID, "", "Undelete", nName, nil, nil, n)
+
+	return nil
+}
+
+// PurgeDeleted permanently deletes the projects and notes deleted more than DeleteRetention ago and
+// purges their IAM policies. It does nothing if Tombstones isn't set. Servers call it periodically.
+func (g *API) PurgeDeleted(ctx context.Context) error {
+	if g.Tombstones == nil {
+		return nil
+	}
+	purged, err := g.Tombstones.PurgeDeleted(ctx, time.Now().Add(-g.DeleteRetention))
+	if err != nil {
+		return err
+	}
+	for _, r := range purged {
+		var (
+			pID, entityID string
+			resource      = Notes
+		)
+		if pID, entityID, err = name.ParseNote(r); err != nil {
+			if pID, err = name.ParseProject(r); err != nil {
+				g.Logger.Warningf(ctx, "Error purging %q: %v", r, err)
+				continue
+			}
+			resource = Projects
+		}
+		g.audit(ctx, pID, purgeActor, "PurgeDeleted", r, nil, nil, nil)
+		// This fails open, the entity is gone already.
+		if err := g.Auth.PurgePolicy(ctx, pID, entityID, resource); err != nil {
+			g.Logger.Warningf(ctx, "Error deleting policies for %q: %v", r, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafeas
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	emptypb "github.com/golang/protobuf/ptypes/empty"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	udpb "github.com/grafeas/grafeas/proto/v1beta1/undelete_go_proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUndelete(t *testing.T) {
+	ctx := context.Background()
+	pName := "projects/consumer1"
+	nName := "projects/goog-vulnz/notes/CVE-UH-OH"
+	expired := "projects/goog-vulnz/notes/CVE-OLD"
+	d := &fakeTombstones{
+		deleted: map[string]time.Time{
+			pName:   time.Now().Add(-time.Minute),
+			nName:   time.Now().Add(-time.Minute),
+			expired: time.Now().Add(-48 * time.Hour),
+		},
+		notes: map[string]*gpb.Note{nName: {Name: nName}, expired: {Name: expired}},
+	}
+	a := &fakeAudit{}
+	g := &API{
+		Storage:         newFakeStorage(),
+		Auth:            &fakeAuth{},
+		Filter:          &fakeFilter{},
+		Logger:          &fakeLogger{},
+		Audit:           a,
+		Tombstones:      d,
+		DeleteRetention: 24 * time.Hour,
+	}
+
+	for _, n := range []string{pName, nName} {
+		if err := g.Undelete(ctx, &udpb.UndeleteRequest{Name: n}, &emptypb.Empty{}); err != nil {
+			t.Fatalf("Undelete(%q) got err %v, want success", n, err)
+		}
+		if _, ok := d.deleted[n]; ok {
+			t.Errorf("Undelete(%q) left it deleted", n)
+		}
+	}
+	if err := g.Undelete(ctx, &udpb.UndeleteRequest{Name: expired}, &emptypb.Empty{}); status.Code(err) != codes.NotFound {
+		t.Errorf("Undelete(%q) deleted before the retention window got err %v, want %v", expired, err, codes.NotFound)
+	}
+
+	if len(a.events["consumer1"]) != 1 || len(a.events["goog-vulnz"]) != 1 {
+		t.Fatalf("Undelete recorded audit events %v, want one per undeleted entity", a.events)
+	}
+	if e := a.events["goog-vulnz"][0]; e.Method != "Undelete" || e.Resource != nName || e.BeforeDigest != "" || e.AfterDigest == "" {
+		t.Errorf("Undelete recorded %v, want the note undeleted", e)
+	}
+}
+
+func TestUndeleteErrors(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		desc          string
+		name          string
+		authErr       bool
+		noTombstones  bool
+		tombstonesErr bool
+		wantErrStatus codes.Code
+	}{
+		{
+			desc:          "invalid name",
+			name:          "projects/consumer1/occurrences/1234",
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "invalid project name",
+			name:          "consumer1/",
+			wantErrStatus: codes.InvalidArgument,
+		},
+		{
+			desc:          "auth error",
+			name:          "projects/consumer1",
+			authErr:       true,
+			wantErrStatus: codes.PermissionDenied,
+		},
+		{
+			desc:          "undelete not supported",
+			name:          "projects/goog-vulnz/notes/CVE-UH-OH",
+			noTombstones:  true,
+			wantErrStatus: codes.Unimplemented,
+		},
+		{
+			desc:          "storage error",
+			name:          "projects/goog-vulnz/notes/CVE-UH-OH",
+			tombstonesErr: true,
+			wantErrStatus: codes.Internal,
+		},
+		{
+			desc:          "not deleted",
+			name:          "projects/goog-vulnz/notes/CVE-UH-OH",
+			wantErrStatus: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		g := &API{
+			Storage: newFakeStorage(),
+			Auth:    &fakeAuth{authErr: tt.authErr},
+			Filter:  &fakeFilter{},
+			Logger:  &fakeLogger{},
+		}
+		if !tt.noTombstones {
+			g.Tombstones = &fakeTombstones{err: tt.tombstonesErr}
+		}
+		req := &udpb.UndeleteRequest{Name: tt.name}
+		if err := g.Undelete(ctx, req, &emptypb.Empty{}); status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: Undelete(%v) got error code %v, want %v", tt.desc, req, status.Code(err), tt.wantErrStatus)
+		}
+	}
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeStorage()
+	if _, err := s.CreateNote(ctx, "goog-vulnz", "CVE-UH-OH", "", &gpb.Note{}); err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	d := &fakeTombstones{
+		deleted: map[string]time.Time{
+			"projects/consumer1":                   time.Now().Add(-48 * time.Hour),
+			"projects/goog-vulnz/notes/CVE-OLD":    time.Now().Add(-48 * time.Hour),
+			"projects/goog-vulnz/notes/CVE-RECENT": time.Now().Add(-time.Minute),
+		},
+	}
+	auth := &fakeAuth{}
+	a := &fakeAudit{}
+	g := &API{
+		Storage:         s,
+		Auth:            auth,
+		Filter:          &fakeFilter{},
+		Logger:          &fakeLogger{},
+		Audit:           a,
+		Tombstones:      d,
+		DeleteRetention: 24 * time.Hour,
+	}
+
+	// Policies of notes that can still be undeleted are kept.
+	req := &gpb.DeleteNoteRequest{Name: "projects/goog-vulnz/notes/CVE-UH-OH"}
+	if err := g.DeleteNote(ctx, req, &emptypb.Empty{}); err != nil {
+		t.Fatalf("DeleteNote got err %v, want success", err)
+	}
+	if len(auth.purged) != 0 {
+		t.Errorf("DeleteNote purged policies %v, want none", auth.purged)
+	}
+
+	if err := g.PurgeDeleted(ctx); err != nil {
+		t.Fatalf("PurgeDeleted got err %v, want success", err)
+	}
+	want := []string{"projects projects/consumer1/", "notes projects/goog-vulnz/CVE-OLD"}
+	if !reflect.DeepEqual(auth.purged, want) {
+		t.Errorf("PurgeDeleted purged policies %v, want %v", auth.purged, want)
+	}
+	if _, ok := d.deleted["projects/goog-vulnz/notes/CVE-RECENT"]; !ok {
+		t.Error("PurgeDeleted purged a note deleted within the retention window")
+	}
+	for pID, events := range map[string]int{"consumer1": 1, "goog-vulnz": 2} {
+		if len(a.events[pID]) != events {
+			t.Errorf("PurgeDeleted recorded audit events %v in project %q, want %d", a.events[pID], pID, events)
+		}
+	}
+	if e := a.events["consumer1"][0]; e.Actor != purgeActor || e.Method != "PurgeDeleted" {
+		t.Errorf("PurgeDeleted recorded %v, want a purge by %q", e, purgeActor)
+	}
+
+	// Failing to purge policies fails open.
+	d.deleted["projects/goog-vulnz/notes/CVE-RECENT"] = time.Now().Add(-48 * time.Hour)
+	auth.purgeErr = true
+	if err := g.PurgeDeleted(ctx); err != nil {
+		t.Errorf("PurgeDeleted with a purge policy error got err %v, want success", err)
+	}
+	d.err = true
+	if err := g.PurgeDeleted(ctx); status.Code(err) != codes.Internal {
+		t.Errorf("PurgeDeleted with a storage error got err %v, want %v", err, codes.Internal)
+	}
+	g.Tombstones = nil
+	if err := g.PurgeDeleted(ctx); err != nil {
+		t.Errorf("PurgeDeleted without tombstones got err %v, want success", err)
+	}
+}
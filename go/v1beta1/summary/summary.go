@@ -0,0 +1,136 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package summary builds vulnerability occurrence summaries, so that storage implementations of
+// GetVulnerabilityOccurrencesSummary only need to list the matching occurrences.
+package summary
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	pkgpb "github.com/grafeas/grafeas/proto/v1beta1/package_go_proto"
+	vpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
+)
+
+// Aggregator counts vulnerability occurrences by resource and severity.
+type Aggregator struct {
+	// Map of resource URIs to the first resource seen with that URI.
+	resources map[string]*gpb.Resource
+	// Map of resource URIs to a map of severities to their counts. SEVERITY_UNSPECIFIED holds the
+	// total across all severities.
+	counts map[string]map[vpb.Severity]*gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest
+}
+
+// NewAggregator returns an empty aggregator.
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		resources: map[string]*gpb.Resource{},
+		counts:    map[string]map[vpb.Severity]*gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest{},
+	}
+}
+
+// Add counts the specified occurrence if it is a vulnerability occurrence, and ignores it otherwise.
+// Its severity is the effective severity if set, the note provider assigned severity otherwise.
+func (a *Aggregator) Add(o *gpb.Occurrence) {
+	v := o.GetVulnerability()
+	if v == nil {
+		return
+	}
+	uri := o.GetResource().GetUri()
+	if _, ok := a.resources[uri]; !ok {
+		a.resources[uri] = o.GetResource()
+		a.counts[uri] = map[vpb.Severity]*gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest{}
+	}
+	severity := v.EffectiveSeverity
+	if severity == vpb.Severity_SEVERITY_UNSPECIFIED {
+		severity = v.Severity
+	}
+	fixable := IsFixable(v)
+	a.count(uri, vpb.Severity_SEVERITY_UNSPECIFIED, fixable)
+	if severity != vpb.Severity_SEVERITY_UNSPECIFIED {
+		a.count(uri, severity, fixable)
+	}
+}
+
+func (a *Aggregator) count(uri string, severity vpb.Severity, fixable bool) {
+	c, ok := a.counts[uri][severity]
+	if !ok {
+		c = &gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest{Severity: severity}
+		if r := a.resources[uri]; r != nil {
+			c.Resource = proto.Clone(r).(*gpb.Resource)
+		}
+		a.counts[uri][severity] = c
+	}
+	c.TotalCount++
+	if fixable {
+		c.FixableCount++
+	}
+}
+
+// Summary returns the counts of the occurrences added so far, ordered by resource URI and
+// severity, where the total for each resource comes first.
+func (a *Aggregator) Summary() *gpb.VulnerabilityOccurrencesSummary {
+	uris := make([]string, 0, len(a.counts))
+	for uri := range a.counts {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	summary := &gpb.VulnerabilityOccurrencesSummary{}
+	for _, uri := range uris {
+		severities := make([]vpb.Severity, 0, len(a.counts[uri]))
+		for s := range a.counts[uri] {
+			severities = append(severities, s)
+		}
+		sort.Slice(severities, func(i, j int) bool { return severities[i] < severities[j] })
+		for _, s := range severities {
+			summary.Counts = append(summary.Counts, proto.Clone(a.counts[uri][s]).(*gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest))
+		}
+	}
+	return summary
+}
+
+// IsFixable returns whether a fix is available for any of the package issues of the specified
+// vulnerability, that is whether any issue has a fixed version other than the MAXIMUM sentinel.
+func IsFixable(v *vpb.Details) bool {
+	for _, pi := range v.GetPackageIssue() {
+		version := pi.GetFixedLocation().GetVersion()
+		if version != nil && version.Kind != pkgpb.Version_MAXIMUM {
+			return true
+		}
+	}
+	return false
+}
+
+// FromPages summarizes the occurrences returned by list, which is called with the next page token
+// until it returns an empty one, e.g. wrapping a storage's ListOccurrences with a filter.
+func FromPages(list func(pageToken string) ([]*gpb.Occurrence, string, error)) (*gpb.VulnerabilityOccurrencesSummary, error) {
+	a := NewAggregator()
+	pageToken := ""
+	for {
+		occs, npt, err := list(pageToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range occs {
+			a.Add(o)
+		}
+		if npt == "" {
+			return a.Summary(), nil
+		}
+		pageToken = npt
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package summary
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	pkgpb "github.com/grafeas/grafeas/proto/v1beta1/package_go_proto"
+	vpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
+)
+
+const (
+	debian = "https://eu.gcr.io/consumer1/debian9@sha256:dbc96ed51bc598faeec0901bad307ebb5d1d7259b33e2d7d7296c28f439dc777"
+	alpine = "https://eu.gcr.io/consumer1/alpine@sha256:0baa7a935c0cba530xxx03af85770cb52b26bfe570a9ff09e17c1a02c6b0bd9a"
+)
+
+// vulnzOcc returns a vulnerability occurrence on the specified resource, with a fix of the
+// specified version kind or none if kind is unspecified.
+func vulnzOcc(uri string, severity, effectiveSeverity vpb.Severity, fixKind pkgpb.Version_VersionKind) *gpb.Occurrence {
+	pi := &vpb.PackageIssue{
+		AffectedLocation: &vpb.VulnerabilityLocation{
+			CpeUri:  "cpe:/o:debian:debian_linux:9",
+			Package: "icu",
+			Version: &pkgpb.Version{Name: "52.1", Kind: pkgpb.Version_NORMAL},
+		},
+	}
+	if fixKind != pkgpb.Version_VERSION_KIND_UNSPECIFIED {
+		pi.FixedLocation = &vpb.VulnerabilityLocation{
+			CpeUri:  "cpe:/o:debian:debian_linux:9",
+			Package: "icu",
+			Version: &pkgpb.Version{Name: "55.1", Kind: fixKind},
+		}
+	}
+	return &gpb.Occurrence{
+		Resource: &gpb.Resource{Uri: uri},
+		NoteName: "projects/goog-vulnz/notes/CVE-2014-9911",
+		Details: &gpb.Occurrence_Vulnerability{
+			Vulnerability: &vpb.Details{
+				Severity:          severity,
+				EffectiveSeverity: effectiveSeverity,
+				PackageIssue:      []*vpb.PackageIssue{pi},
+			},
+		},
+	}
+}
+
+func count(uri string, severity vpb.Severity, fixable, total int64) *gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest {
+	return &gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest{
+		Resource:     &gpb.Resource{Uri: uri},
+		Severity:     severity,
+		FixableCount: fixable,
+		TotalCount:   total,
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	a := NewAggregator()
+	for _, o := range []*gpb.Occurrence{
+		vulnzOcc(debian, vpb.Severity_HIGH, vpb.Severity_SEVERITY_UNSPECIFIED, pkgpb.Version_NORMAL),
+		vulnzOcc(debian, vpb.Severity_HIGH, vpb.Severity_SEVERITY_UNSPECIFIED, pkgpb.Version_MAXIMUM),
+		// The effective severity takes precedence.
+		vulnzOcc(debian, vpb.Severity_HIGH, vpb.Severity_LOW, pkgpb.Version_NORMAL),
+		vulnzOcc(alpine, vpb.Severity_CRITICAL, vpb.Severity_SEVERITY_UNSPECIFIED, pkgpb.Version_VERSION_KIND_UNSPECIFIED),
+		// Occurrences without a severity only count towards the total.
+		vulnzOcc(alpine, vpb.Severity_SEVERITY_UNSPECIFIED, vpb.Severity_SEVERITY_UNSPECIFIED, pkgpb.Version_NORMAL),
+		// Other kinds of occurrences are ignored.
+		{Resource: &gpb.Resource{Uri: debian}, NoteName: "projects/goog-vulnz/notes/build"},
+	} {
+		a.Add(o)
+	}
+
+	want := &gpb.VulnerabilityOccurrencesSummary{
+		Counts: []*gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest{
+			count(alpine, vpb.Severity_SEVERITY_UNSPECIFIED, 1, 2),
+			count(alpine, vpb.Severity_CRITICAL, 0, 1),
+			count(debian, vpb.Severity_SEVERITY_UNSPECIFIED, 2, 3),
+			count(debian, vpb.Severity_LOW, 1, 1),
+			count(debian, vpb.Severity_HIGH, 1, 2),
+		},
+	}
+	if diff := cmp.Diff(want, a.Summary(), cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("Summary() returned diff (want -> got):\n%s", diff)
+	}
+}
+
+func TestFromPages(t *testing.T) {
+	pages := map[string][]*gpb.Occurrence{
+		"":  {vulnzOcc(debian, vpb.Severity_HIGH, vpb.Severity_SEVERITY_UNSPECIFIED, pkgpb.Version_NORMAL)},
+		"2": {vulnzOcc(debian, vpb.Severity_HIGH, vpb.Severity_SEVERITY_UNSPECIFIED, pkgpb.Version_MAXIMUM)},
+	}
+	next := map[string]string{"": "2", "2": ""}
+	got, err := FromPages(func(pageToken string) ([]*gpb.Occurrence, string, error) {
+		return pages[pageToken], next[pageToken], nil
+	})
+	if err != nil {
+		t.Fatalf("FromPages got err %v, want success", err)
+	}
+	want := &gpb.VulnerabilityOccurrencesSummary{
+		Counts: []*gpb.VulnerabilityOccurrencesSummary_FixableTotalByDigest{
+			count(debian, vpb.Severity_SEVERITY_UNSPECIFIED, 1, 2),
+			count(debian, vpb.Severity_HIGH, 1, 2),
+		},
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("FromPages returned diff (want -> got):\n%s", diff)
+	}
+
+	if _, err := FromPages(func(string) ([]*gpb.Occurrence, string, error) {
+		return nil, "", errors.New("storage error")
+	}); err == nil {
+		t.Errorf("FromPages with a failing list got success, want error")
+	}
+}
@@ -0,0 +1,396 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proto/v1beta1/audit.proto
+
+package audit_go_proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	field_mask "google.golang.org/genproto/protobuf/field_mask"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// An append-only record of a call that mutated a resource.
+type AuditEvent struct {
+	// Output only. The name of the event, in the form of
+	// `projects/[PROJECT_ID]/auditEvents/[EVENT_ID]`.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The ID of the caller, empty if the caller is anonymous.
+	Actor string `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
+	// The name of the method called, e.g. `UpdateNote`.
+	Method string `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	// The name of the mutated resource, e.g.
+	// `projects/goog-vulnz/notes/CVE-2019-0001`.
+	Resource string `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`
+	// The fields the call updated, for updates with a field mask.
+	UpdateMask *field_mask.FieldMask `protobuf:"bytes,5,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// The digest of the resource before the call, empty if it didn't exist or,
+	// for occurrences created by upserting, isn't known.
+	BeforeDigest string `protobuf:"bytes,6,opt,name=before_digest,json=beforeDigest,proto3" json:"before_digest,omitempty"`
+	// The digest of the resource after the call, empty if it was deleted.
+	AfterDigest string `protobuf:"bytes,7,opt,name=after_digest,json=afterDigest,proto3" json:"after_digest,omitempty"`
+	// Output only. The time the event was recorded.
+	EventTime            *timestamp.Timestamp `protobuf:"bytes,8,opt,name=event_time,json=eventTime,proto3" json:"event_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *AuditEvent) Reset()         { *m = AuditEvent{} }
+func (m *AuditEvent) String() string { return proto.CompactTextString(m) }
+func (*AuditEvent) ProtoMessage()    {}
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0aeca102d256463, []int{0}
+}
+
+func (m *AuditEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEvent.Unmarshal(m, b)
+}
+func (m *AuditEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEvent.Marshal(b, m, deterministic)
+}
+func (m *AuditEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEvent.Merge(m, src)
+}
+func (m *AuditEvent) XXX_Size() int {
+	return xxx_messageInfo_AuditEvent.Size(m)
+}
+func (m *AuditEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEvent proto.InternalMessageInfo
+
+func (m *AuditEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *AuditEvent) GetBeforeDigest() string {
+	if m != nil {
+		return m.BeforeDigest
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetAfterDigest() string {
+	if m != nil {
+		return m.AfterDigest
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetEventTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EventTime
+	}
+	return nil
+}
+
+// Request to list audit events.
+type ListAuditEventsRequest struct {
+	// The name of the project to list audit events of, in the form of
+	// `projects/[PROJECT_ID]`.
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The name of the resource to list the events of. If empty, the events of
+	// every resource of the project are listed.
+	Resource string `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
+	// Only events recorded at or after this time are listed, if set.
+	StartTime *timestamp.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// Only events recorded before this time are listed, if set.
+	EndTime *timestamp.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// Number of events to return in the list.
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Token to provide to skip to a particular spot in the list.
+	PageToken            string   `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAuditEventsRequest) Reset()         { *m = ListAuditEventsRequest{} }
+func (m *ListAuditEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAuditEventsRequest) ProtoMessage()    {}
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0aeca102d256463, []int{1}
+}
+
+func (m *ListAuditEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditEventsRequest.Unmarshal(m, b)
+}
+func (m *ListAuditEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListAuditEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditEventsRequest.Merge(m, src)
+}
+func (m *ListAuditEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAuditEventsRequest.Size(m)
+}
+func (m *ListAuditEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditEventsRequest proto.InternalMessageInfo
+
+func (m *ListAuditEventsRequest) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *ListAuditEventsRequest) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+func (m *ListAuditEventsRequest) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *ListAuditEventsRequest) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *ListAuditEventsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListAuditEventsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// Response for listing audit events.
+type ListAuditEventsResponse struct {
+	// The audit events requested.
+	Events []*AuditEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// The next pagination token in the list response. It should be used as
+	// `page_token` for the following request. An empty value means no more
+	// results.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAuditEventsResponse) Reset()         { *m = ListAuditEventsResponse{} }
+func (m *ListAuditEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAuditEventsResponse) ProtoMessage()    {}
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0aeca102d256463, []int{2}
+}
+
+func (m *ListAuditEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditEventsResponse.Unmarshal(m, b)
+}
+func (m *ListAuditEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListAuditEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditEventsResponse.Merge(m, src)
+}
+func (m *ListAuditEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAuditEventsResponse.Size(m)
+}
+func (m *ListAuditEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditEventsResponse proto.InternalMessageInfo
+
+func (m *ListAuditEventsResponse) GetEvents() []*AuditEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *ListAuditEventsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*AuditEvent)(nil), "grafeas.v1beta1.audit.AuditEvent")
+	proto.RegisterType((*ListAuditEventsRequest)(nil), "grafeas.v1beta1.audit.ListAuditEventsRequest")
+	proto.RegisterType((*ListAuditEventsResponse)(nil), "grafeas.v1beta1.audit.ListAuditEventsResponse")
+}
+
+func init() { proto.RegisterFile("proto/v1beta1/audit.proto", fileDescriptor_c0aeca102d256463) }
+
+var fileDescriptor_c0aeca102d256463 = []byte{
+	// 497 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0x95, 0x93, 0xc6, 0x4d, 0x26, 0xad, 0x2a, 0x2d, 0x10, 0x8c, 0x11, 0x22, 0x0d, 0x12, 0xca,
+	0x85, 0xb5, 0x12, 0x84, 0x50, 0xc5, 0xa9, 0x15, 0xd0, 0x0b, 0x48, 0x95, 0xa9, 0x38, 0xc0, 0xc1,
+	0x5a, 0xc7, 0x63, 0xd7, 0x4a, 0xed, 0x35, 0xde, 0x75, 0x85, 0x2a, 0xee, 0xfc, 0x07, 0x47, 0x3e,
+	0x91, 0x13, 0xda, 0x59, 0xa7, 0x85, 0x90, 0xaa, 0x9c, 0xbc, 0xf3, 0xe6, 0xbd, 0xd9, 0x37, 0xcf,
+	0x36, 0x3c, 0xa8, 0x6a, 0xa9, 0x65, 0x70, 0x31, 0x8b, 0x51, 0x8b, 0x59, 0x20, 0x9a, 0x24, 0xd7,
+	0x9c, 0x30, 0x76, 0x2f, 0xab, 0x45, 0x8a, 0x42, 0xf1, 0xb6, 0xc9, 0xa9, 0xe9, 0x8f, 0x33, 0x29,
+	0xb3, 0x73, 0x0c, 0x88, 0x14, 0x37, 0x69, 0x90, 0xe6, 0x78, 0x9e, 0x44, 0x85, 0x50, 0x4b, 0x2b,
+	0xf4, 0x1f, 0xaf, 0x33, 0x74, 0x5e, 0xa0, 0xd2, 0xa2, 0xa8, 0x2c, 0x61, 0xf2, 0xb3, 0x03, 0x70,
+	0x68, 0x86, 0xbd, 0xb9, 0xc0, 0x52, 0x33, 0x06, 0x5b, 0xa5, 0x28, 0xd0, 0x73, 0xc6, 0xce, 0x74,
+	0x10, 0xd2, 0x99, 0xdd, 0x85, 0x9e, 0x58, 0x68, 0x59, 0x7b, 0x1d, 0x02, 0x6d, 0xc1, 0x46, 0xe0,
+	0x16, 0xa8, 0xcf, 0x64, 0xe2, 0x75, 0x09, 0x6e, 0x2b, 0xe6, 0x43, 0xbf, 0x46, 0x25, 0x9b, 0x7a,
+	0x81, 0xde, 0x16, 0x75, 0xae, 0x6a, 0xf6, 0x0a, 0x86, 0x4d, 0x95, 0x08, 0x8d, 0x64, 0xd1, 0xeb,
+	0x8d, 0x9d, 0xe9, 0x70, 0xee, 0x73, 0xeb, 0x91, 0xaf, 0x3c, 0xf2, 0xb7, 0x66, 0x8b, 0xf7, 0x42,
+	0x2d, 0x43, 0xb0, 0x74, 0x73, 0x66, 0x4f, 0x60, 0x37, 0xc6, 0x54, 0xd6, 0x18, 0x25, 0x79, 0x86,
+	0x4a, 0x7b, 0x2e, 0x4d, 0xdf, 0xb1, 0xe0, 0x6b, 0xc2, 0xd8, 0x3e, 0xec, 0x88, 0x54, 0x63, 0xbd,
+	0xe2, 0x6c, 0x13, 0x67, 0x48, 0x58, 0x4b, 0x39, 0x00, 0x40, 0xb3, 0x6b, 0x64, 0xa2, 0xf0, 0xfa,
+	0x37, 0x78, 0x38, 0x5d, 0xe5, 0x14, 0x0e, 0x88, 0x6d, 0xea, 0xc9, 0x2f, 0x07, 0x46, 0xef, 0x72,
+	0xa5, 0xaf, 0x03, 0x53, 0x21, 0x7e, 0x69, 0xcc, 0xd4, 0x11, 0xb8, 0x95, 0xa8, 0xb1, 0xd4, 0x6d,
+	0x74, 0x6d, 0xf5, 0x57, 0x1c, 0x9d, 0xb5, 0x38, 0x0e, 0x00, 0x94, 0x16, 0x75, 0xeb, 0xa4, 0x7b,
+	0xbb, 0x13, 0x62, 0x9b, 0x9a, 0xbd, 0x80, 0x3e, 0x96, 0x89, 0x15, 0x6e, 0xdd, 0x2a, 0xdc, 0xc6,
+	0x32, 0x21, 0xd9, 0x43, 0x18, 0x54, 0x22, 0xc3, 0x48, 0xe5, 0x97, 0x48, 0xf1, 0xf7, 0xc2, 0xbe,
+	0x01, 0x3e, 0xe4, 0x97, 0xc8, 0x1e, 0x01, 0x50, 0x53, 0xcb, 0x25, 0x96, 0x6d, 0xba, 0x44, 0x3f,
+	0x35, 0xc0, 0xe4, 0x1b, 0xdc, 0xff, 0x67, 0x77, 0x55, 0xc9, 0x52, 0x99, 0x45, 0x5c, 0x0a, 0x49,
+	0x79, 0xce, 0xb8, 0x3b, 0x1d, 0xce, 0xf7, 0xf9, 0xc6, 0xef, 0x95, 0x5f, 0x6b, 0xc3, 0x56, 0xc0,
+	0x9e, 0xc2, 0x5e, 0x89, 0x5f, 0x75, 0xf4, 0xc7, 0xcd, 0x36, 0xa6, 0x5d, 0x03, 0x9f, 0xac, 0x6e,
+	0x9f, 0x7f, 0x77, 0xe0, 0xce, 0xb1, 0x1d, 0x4a, 0x53, 0x3e, 0xce, 0x8e, 0xcc, 0x60, 0x56, 0xc1,
+	0xde, 0x9a, 0x2b, 0xf6, 0xec, 0x86, 0xdb, 0x37, 0xbf, 0x39, 0x9f, 0xff, 0x2f, 0xdd, 0x2e, 0x7b,
+	0xf4, 0x19, 0xbc, 0x5c, 0x6e, 0xd6, 0x9c, 0x38, 0x9f, 0x5e, 0x66, 0xb9, 0x3e, 0x6b, 0x62, 0xbe,
+	0x90, 0x45, 0xd0, 0x72, 0xae, 0x9e, 0x1b, 0xfe, 0xef, 0x28, 0x93, 0x11, 0xc1, 0x3f, 0x3a, 0xdd,
+	0xe3, 0xf0, 0x30, 0x76, 0xa9, 0x78, 0xfe, 0x7b, 0x00, 0x6d, 0x3a, 0xb3, 0x9e, 0x0c, 0x04, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GrafeasAuditV1Beta1Client is the client API for GrafeasAuditV1Beta1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GrafeasAuditV1Beta1Client interface {
+	// Lists the audit events of the specified project, in the order they were
+	// recorded.
+	//
+	// The caller needs the `auditEvents.list` permission in the project.
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+}
+
+type grafeasAuditV1Beta1Client struct {
+	cc *grpc.ClientConn
+}
+
+func NewGrafeasAuditV1Beta1Client(cc *grpc.ClientConn) GrafeasAuditV1Beta1Client {
+	return &grafeasAuditV1Beta1Client{cc}
+}
+
+func (c *grafeasAuditV1Beta1Client) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, "/grafeas.v1beta1.audit.GrafeasAuditV1Beta1/ListAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GrafeasAuditV1Beta1Server is the server API for GrafeasAuditV1Beta1 service.
+type GrafeasAuditV1Beta1Server interface {
+	// Lists the audit events of the specified project, in the order they were
+	// recorded.
+	//
+	// The caller needs the `auditEvents.list` permission in the project.
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+}
+
+func RegisterGrafeasAuditV1Beta1Server(s *grpc.Server, srv GrafeasAuditV1Beta1Server) {
+	s.RegisterService(&_GrafeasAuditV1Beta1_serviceDesc, srv)
+}
+
+func _GrafeasAuditV1Beta1_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrafeasAuditV1Beta1Server).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grafeas.v1beta1.audit.GrafeasAuditV1Beta1/ListAuditEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrafeasAuditV1Beta1Server).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GrafeasAuditV1Beta1_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grafeas.v1beta1.audit.GrafeasAuditV1Beta1",
+	HandlerType: (*GrafeasAuditV1Beta1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _GrafeasAuditV1Beta1_ListAuditEvents_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/v1beta1/audit.proto",
+}
@@ -0,0 +1,1054 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proto/v1beta1/bulk.proto
+
+package bulk_go_proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	longrunning "google.golang.org/genproto/googleapis/longrunning"
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// The encoding of the notes or occurrences of an import.
+type ImportFormat int32
+
+const (
+	// Unspecified, imports must specify a format.
+	ImportFormat_IMPORT_FORMAT_UNSPECIFIED ImportFormat = 0
+	// One JSON encoded note or occurrence per line.
+	ImportFormat_JSON_LINES ImportFormat = 1
+	// Binary encoded notes or occurrences, each preceded by its length as a
+	// varint.
+	ImportFormat_LENGTH_DELIMITED ImportFormat = 2
+)
+
+var ImportFormat_name = map[int32]string{
+	0: "IMPORT_FORMAT_UNSPECIFIED",
+	1: "JSON_LINES",
+	2: "LENGTH_DELIMITED",
+}
+
+var ImportFormat_value = map[string]int32{
+	"IMPORT_FORMAT_UNSPECIFIED": 0,
+	"JSON_LINES":                1,
+	"LENGTH_DELIMITED":          2,
+}
+
+func (x ImportFormat) String() string {
+	return proto.EnumName(ImportFormat_name, int32(x))
+}
+
+func (ImportFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_fa8600042c629d5a, []int{0}
+}
+
+// Request to delete occurrences in bulk.
+type BatchDeleteOccurrencesRequest struct {
+	// The name of the project to delete occurrences in, in the form of
+	// `projects/[PROJECT_ID]`.
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The filter expression the occurrences to delete match. Exactly one of
+	// `filter` and `names` must be set.
+	Filter string `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	// The names of the occurrences to delete, in the form of
+	// `projects/[PROJECT_ID]/occurrences/[OCCURRENCE_ID]`, all in the parent
+	// project.
+	Names []string `protobuf:"bytes,3,rep,name=names,proto3" json:"names,omitempty"`
+	// If true, nothing is deleted, the response only reports what would be.
+	DryRun               bool     `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchDeleteOccurrencesRequest) Reset()         { *m = BatchDeleteOccurrencesRequest{} }
+func (m *BatchDeleteOccurrencesRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteOccurrencesRequest) ProtoMessage()    {}
+func (*BatchDeleteOccurrencesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa8600042c629d5a, []int{0}
+}
+
+func (m *BatchDeleteOccurrencesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteOccurrencesRequest.Unmarshal(m, b)
+}
+func (m *BatchDeleteOccurrencesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteOccurrencesRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteOccurrencesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteOccurrencesRequest.Merge(m, src)
+}
+func (m *BatchDeleteOccurrencesRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteOccurrencesRequest.Size(m)
+}
+func (m *BatchDeleteOccurrencesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteOccurrencesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteOccurrencesRequest proto.InternalMessageInfo
+
+func (m *BatchDeleteOccurrencesRequest) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *BatchDeleteOccurrencesRequest) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+func (m *BatchDeleteOccurrencesRequest) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+func (m *BatchDeleteOccurrencesRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+// Response for deleting occurrences in bulk.
+type BatchDeleteOccurrencesResponse struct {
+	// The number of occurrences the request matched.
+	MatchedCount int32 `protobuf:"varint,1,opt,name=matched_count,json=matchedCount,proto3" json:"matched_count,omitempty"`
+	// The number of occurrences deleted, or that would be deleted for a dry run.
+	DeletedCount int32 `protobuf:"varint,2,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`
+	// The number of occurrences that couldn't be deleted, e.g. for lack of
+	// permission.
+	FailedCount int32 `protobuf:"varint,3,opt,name=failed_count,json=failedCount,proto3" json:"failed_count,omitempty"`
+	// A sample of the occurrences that couldn't be deleted.
+	Failures             []*BatchDeleteOccurrencesResponse_Failure `protobuf:"bytes,4,rep,name=failures,proto3" json:"failures,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                  `json:"-"`
+	XXX_unrecognized     []byte                                    `json:"-"`
+	XXX_sizecache        int32                                     `json:"-"`
+}
+
+func (m *BatchDeleteOccurrencesResponse) Reset()         { *m = BatchDeleteOccurrencesResponse{} }
+func (m *BatchDeleteOccurrencesResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteOccurrencesResponse) ProtoMessage()    {}
+func (*BatchDeleteOccurrencesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa8600042c629d5a, []int{1}
+}
+
+func (m *BatchDeleteOccurrencesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteOccurrencesResponse.Unmarshal(m, b)
+}
+func (m *BatchDeleteOccurrencesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteOccurrencesResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteOccurrencesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteOccurrencesResponse.Merge(m, src)
+}
+func (m *BatchDeleteOccurrencesResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteOccurrencesResponse.Size(m)
+}
+func (m *BatchDeleteOccurrencesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteOccurrencesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteOccurrencesResponse proto.InternalMessageInfo
+
+func (m *BatchDeleteOccurrencesResponse) GetMatchedCount() int32 {
+	if m != nil {
+		return m.MatchedCount
+	}
+	return 0
+}
+
+func (m *BatchDeleteOccurrencesResponse) GetDeletedCount() int32 {
+	if m != nil {
+		return m.DeletedCount
+	}
+	return 0
+}
+
+func (m *BatchDeleteOccurrencesResponse) GetFailedCount() int32 {
+	if m != nil {
+		return m.FailedCount
+	}
+	return 0
+}
+
+func (m *BatchDeleteOccurrencesResponse) GetFailures() []*BatchDeleteOccurrencesResponse_Failure {
+	if m != nil {
+		return m.Failures
+	}
+	return nil
+}
+
+// An occurrence that couldn't be deleted.
+type BatchDeleteOccurrencesResponse_Failure struct {
+	// The name of the occurrence.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Why the occurrence couldn't be deleted.
+	Status               *status.Status `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *BatchDeleteOccurrencesResponse_Failure) Reset() {
+	*m = BatchDeleteOccurrencesResponse_Failure{}
+}
+func (m *BatchDeleteOccurrencesResponse_Failure) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteOccurrencesResponse_Failure) ProtoMessage()    {}
+func (*BatchDeleteOccurrencesResponse_Failure) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa8600042c629d5a, []int{1, 0}
+}
+
+func (m *BatchDeleteOccurrencesResponse_Failure) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteOccurrencesResponse_Failure.Unmarshal(m, b)
+}
+func (m *BatchDeleteOccurrencesResponse_Failure) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteOccurrencesResponse_Failure.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteOccurrencesResponse_Failure) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteOccurrencesResponse_Failure.Merge(m, src)
+}
+func (m *BatchDeleteOccurrencesResponse_Failure) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteOccurrencesResponse_Failure.Size(m)
+}
+func (m *BatchDeleteOccurrencesResponse_Failure) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteOccurrencesResponse_Failure.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteOccurrencesResponse_Failure proto.InternalMessageInfo
+
+func (m *BatchDeleteOccurrencesResponse_Failure) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BatchDeleteOccurrencesResponse_Failure) GetStatus() *status.Status {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// Metadata for deleting occurrences in bulk.
+type BatchDeleteOccurrencesMetadata struct {
+	// Output only. The time the operation was created.
+	CreateTime *timestamp.Timestamp `protobuf:"bytes,1,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Output only. The time the operation finished.
+	EndTime *timestamp.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// The number of occurrences being deleted.
+	TotalCount int32 `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	// The number of occurrences processed so far.
+	ProcessedCount       int32    `protobuf:"varint,4,opt,name=processed_count,json=processedCount,proto3" json:"processed_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchDeleteOccurrencesMetadata) Reset()         { *m = BatchDeleteOccurrencesMetadata{} }
+func (m *BatchDeleteOccurrencesMetadata) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteOccurrencesMetadata) ProtoMessage()    {}
+func (*BatchDeleteOccurrencesMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa8600042c629d5a, []int{2}
+}
+
+func (m *BatchDeleteOccurrencesMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteOccurrencesMetadata.Unmarshal(m, b)
+}
+func (m *BatchDeleteOccurrencesMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteOccurrencesMetadata.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteOccurrencesMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteOccurrencesMetadata.Merge(m, src)
+}
+func (m *BatchDeleteOccurrencesMetadata) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteOccurrencesMetadata.Size(m)
+}
+func (m *BatchDeleteOccurrencesMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteOccurrencesMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteOccurrencesMetadata proto.InternalMessageInfo
+
+func (m *BatchDeleteOccurrencesMetadata) GetCreateTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreateTime
+	}
+	return nil
+}
+
+func (m *BatchDeleteOccurrencesMetadata) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *BatchDeleteOccurrencesMetadata) GetTotalCount() int32 {
+	if m != nil {
+		return m.TotalCount
+	}
+	return 0
+}
+
+func (m *BatchDeleteOccurrencesMetadata) GetProcessedCount() int32 {
+	if m != nil {
+		return m.ProcessedCount
+	}
+	return 0
+}
+
+// Request to import notes.
+type ImportNotesRequest struct {
+	// The name of the project to import notes into, in the form of
+	// `projects/[PROJECT_ID]`. Only set in the first message.
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The encoding of the notes. Only set in the first message.
+	Format ImportFormat `protobuf:"varint,2,opt,name=format,proto3,enum=grafeas.v1beta1.bulk.ImportFormat" json:"format,omitempty"`
+	// Where the notes come from. Every note must be named
+	// `projects/[PROJECT_ID]/notes/[NOTE_ID]`, in the parent project.
+	//
+	// Types that are valid to be assigned to Source:
+	//	*ImportNotesRequest_Path
+	//	*ImportNotesRequest_Data
+	Source               isImportNotesRequest_Source `protobuf_oneof:"source"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *ImportNotesRequest) Reset()         { *m = ImportNotesRequest{} }
+func (m *ImportNotesRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportNotesRequest) ProtoMessage()    {}
+func (*ImportNotesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa8600042c629d5a, []int{3}
+}
+
+func (m *ImportNotesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportNotesRequest.Unmarshal(m, b)
+}
+func (m *ImportNotesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportNotesRequest.Marshal(b, m, deterministic)
+}
+func (m *ImportNotesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportNotesRequest.Merge(m, src)
+}
+func (m *ImportNotesRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportNotesRequest.Size(m)
+}
+func (m *ImportNotesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportNotesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportNotesRequest proto.InternalMessageInfo
+
+func (m *ImportNotesRequest) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *ImportNotesRequest) GetFormat() ImportFormat {
+	if m != nil {
+		return m.Format
+	}
+	return ImportFormat_IMPORT_FORMAT_UNSPECIFIED
+}
+
+type isImportNotesRequest_Source interface {
+	isImportNotesRequest_Source()
+}
+
+type ImportNotesRequest_Path struct {
+	Path string `protobuf:"bytes,3,opt,name=path,proto3,oneof"`
+}
+
+type ImportNotesRequest_Data struct {
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3,oneof"`
+}
+
+func (*ImportNotesRequest_Path) isImportNotesRequest_Source() {}
+
+func (*ImportNotesRequest_Data) isImportNotesRequest_Source() {}
+
+func (m *ImportNotesRequest) GetSource() isImportNotesRequest_Source {
+	if m != nil {
+		return m.Source
+	}
+	return nil
+}
+
+func (m *ImportNotesRequest) GetPath() string {
+	if x, ok := m.GetSource().(*ImportNotesRequest_Path); ok {
+		return x.Path
+	}
+	return ""
+}
+
+func (m *ImportNotesRequest) GetData() []byte {
+	if x, ok := m.GetSource().(*ImportNotesRequest_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ImportNotesRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ImportNotesRequest_Path)(nil),
+		(*ImportNotesRequest_Data)(nil),
+	}
+}
+
+// Request to import occurrences.
+type ImportOccurrencesRequest struct {
+	// The name of the project to import occurrences into, in the form of
+	// `projects/[PROJECT_ID]`. Only set in the first message.
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The encoding of the occurrences. Only set in the first message.
+	Format ImportFormat `protobuf:"varint,2,opt,name=format,proto3,enum=grafeas.v1beta1.bulk.ImportFormat" json:"format,omitempty"`
+	// Where the occurrences come from. Occurrences are named by the server, like
+	// those created with `CreateOccurrence`.
+	//
+	// Types that are valid to be assigned to Source:
+	//	*ImportOccurrencesRequest_Path
+	//	*ImportOccurrencesRequest_Data
+	Source isImportOccurrencesRequest_Source `protobuf_oneof:"source"`
+	// Whether an occurrence for the same resource URI and note as an imported
+	// one is updated in place, like `UpsertOccurrence` does, instead of another
+	// occurrence being created. Only set in the first message. The caller then
+	// also needs the `occurrences.update` permission in the project.
+	Upsert               bool     `protobuf:"varint,5,opt,name=upsert,proto3" json:"upsert,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportOccurrencesRequest) Reset()         { *m = ImportOccurrencesRequest{} }
+func (m *ImportOccurrencesRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportOccurrencesRequest) ProtoMessage()    {}
+func (*ImportOccurrencesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa8600042c629d5a, []int{4}
+}
+
+func (m *ImportOccurrencesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportOccurrencesRequest.Unmarshal(m, b)
+}
+func (m *ImportOccurrencesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportOccurrencesRequest.Marshal(b, m, deterministic)
+}
+func (m *ImportOccurrencesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportOccurrencesRequest.Merge(m, src)
+}
+func (m *ImportOccurrencesRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportOccurrencesRequest.Size(m)
+}
+func (m *ImportOccurrencesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportOccurrencesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportOccurrencesRequest proto.InternalMessageInfo
+
+func (m *ImportOccurrencesRequest) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *ImportOccurrencesRequest) GetFormat() ImportFormat {
+	if m != nil {
+		return m.Format
+	}
+	return ImportFormat_IMPORT_FORMAT_UNSPECIFIED
+}
+
+type isImportOccurrencesRequest_Source interface {
+	isImportOccurrencesRequest_Source()
+}
+
+type ImportOccurrencesRequest_Path struct {
+	Path string `protobuf:"bytes,3,opt,name=path,proto3,oneof"`
+}
+
+type ImportOccurrencesRequest_Data struct {
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3,oneof"`
+}
+
+func (*ImportOccurrencesRequest_Path) isImportOccurrencesRequest_Source() {}
+
+func (*ImportOccurrencesRequest_Data) isImportOccurrencesRequest_Source() {}
+
+func (m *ImportOccurrencesRequest) GetSource() isImportOccurrencesRequest_Source {
+	if m != nil {
+		return m.Source
+	}
+	return nil
+}
+
+func (m *ImportOccurrencesRequest) GetPath() string {
+	if x, ok := m.GetSource().(*ImportOccurrencesRequest_Path); ok {
+		return x.Path
+	}
+	return ""
+}
+
+func (m *ImportOccurrencesRequest) GetData() []byte {
+	if x, ok := m.GetSource().(*ImportOccurrencesRequest_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+func (m *ImportOccurrencesRequest) GetUpsert() bool {
+	if m != nil {
+		return m.Upsert
+	}
+	return false
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ImportOccurrencesRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ImportOccurrencesRequest_Path)(nil),
+		(*ImportOccurrencesRequest_Data)(nil),
+	}
+}
+
+// Response for importing notes or occurrences.
+type ImportResponse struct {
+	// The number of notes or occurrences imported.
+	ImportedCount int32 `protobuf:"varint,1,opt,name=imported_count,json=importedCount,proto3" json:"imported_count,omitempty"`
+	// The number of notes or occurrences that couldn't be imported, e.g.
+	// because they are invalid or already exist.
+	FailedCount int32 `protobuf:"varint,2,opt,name=failed_count,json=failedCount,proto3" json:"failed_count,omitempty"`
+	// A sample of the notes or occurrences that couldn't be imported.
+	Failures             []*ImportResponse_Failure `protobuf:"bytes,3,rep,name=failures,proto3" json:"failures,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *ImportResponse) Reset()         { *m = ImportResponse{} }
+func (m *ImportResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportResponse) ProtoMessage()    {}
+func (*ImportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa8600042c629d5a, []int{5}
+}
+
+func (m *ImportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportResponse.Unmarshal(m, b)
+}
+func (m *ImportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportResponse.Marshal(b, m, deterministic)
+}
+func (m *ImportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportResponse.Merge(m, src)
+}
+func (m *ImportResponse) XXX_Size() int {
+	return xxx_messageInfo_ImportResponse.Size(m)
+}
+func (m *ImportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportResponse proto.InternalMessageInfo
+
+func (m *ImportResponse) GetImportedCount() int32 {
+	if m != nil {
+		return m.ImportedCount
+	}
+	return 0
+}
+
+func (m *ImportResponse) GetFailedCount() int32 {
+	if m != nil {
+		return m.FailedCount
+	}
+	return 0
+}
+
+func (m *ImportResponse) GetFailures() []*ImportResponse_Failure {
+	if m != nil {
+		return m.Failures
+	}
+	return nil
+}
+
+// A note or occurrence that couldn't be imported.
+type ImportResponse_Failure struct {
+	// The position of the note or occurrence in the import, starting at 1.
+	Record int32 `protobuf:"varint,1,opt,name=record,proto3" json:"record,omitempty"`
+	// The name of the note or occurrence, if it has one.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Why the note or occurrence couldn't be imported.
+	Status               *status.Status `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ImportResponse_Failure) Reset()         { *m = ImportResponse_Failure{} }
+func (m *ImportResponse_Failure) String() string { return proto.CompactTextString(m) }
+func (*ImportResponse_Failure) ProtoMessage()    {}
+func (*ImportResponse_Failure) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa8600042c629d5a, []int{5, 0}
+}
+
+func (m *ImportResponse_Failure) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportResponse_Failure.Unmarshal(m, b)
+}
+func (m *ImportResponse_Failure) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportResponse_Failure.Marshal(b, m, deterministic)
+}
+func (m *ImportResponse_Failure) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportResponse_Failure.Merge(m, src)
+}
+func (m *ImportResponse_Failure) XXX_Size() int {
+	return xxx_messageInfo_ImportResponse_Failure.Size(m)
+}
+func (m *ImportResponse_Failure) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportResponse_Failure.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportResponse_Failure proto.InternalMessageInfo
+
+func (m *ImportResponse_Failure) GetRecord() int32 {
+	if m != nil {
+		return m.Record
+	}
+	return 0
+}
+
+func (m *ImportResponse_Failure) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ImportResponse_Failure) GetStatus() *status.Status {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// Metadata for importing notes or occurrences.
+type ImportMetadata struct {
+	// Output only. The time the operation was created.
+	CreateTime *timestamp.Timestamp `protobuf:"bytes,1,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Output only. The time the operation finished.
+	EndTime *timestamp.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// The size of the imported data in bytes.
+	TotalBytes int64 `protobuf:"varint,3,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	// The number of bytes processed so far.
+	ProcessedBytes int64 `protobuf:"varint,4,opt,name=processed_bytes,json=processedBytes,proto3" json:"processed_bytes,omitempty"`
+	// The number of notes or occurrences processed so far.
+	ProcessedCount int32 `protobuf:"varint,5,opt,name=processed_count,json=processedCount,proto3" json:"processed_count,omitempty"`
+	// The number of notes or occurrences imported so far.
+	ImportedCount int32 `protobuf:"varint,6,opt,name=imported_count,json=importedCount,proto3" json:"imported_count,omitempty"`
+	// The number of notes or occurrences that couldn't be imported so far.
+	FailedCount          int32    `protobuf:"varint,7,opt,name=failed_count,json=failedCount,proto3" json:"failed_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportMetadata) Reset()         { *m = ImportMetadata{} }
+func (m *ImportMetadata) String() string { return proto.CompactTextString(m) }
+func (*ImportMetadata) ProtoMessage()    {}
+func (*ImportMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fa8600042c629d5a, []int{6}
+}
+
+func (m *ImportMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportMetadata.Unmarshal(m, b)
+}
+func (m *ImportMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportMetadata.Marshal(b, m, deterministic)
+}
+func (m *ImportMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportMetadata.Merge(m, src)
+}
+func (m *ImportMetadata) XXX_Size() int {
+	return xxx_messageInfo_ImportMetadata.Size(m)
+}
+func (m *ImportMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportMetadata proto.InternalMessageInfo
+
+func (m *ImportMetadata) GetCreateTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreateTime
+	}
+	return nil
+}
+
+func (m *ImportMetadata) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *ImportMetadata) GetTotalBytes() int64 {
+	if m != nil {
+		return m.TotalBytes
+	}
+	return 0
+}
+
+func (m *ImportMetadata) GetProcessedBytes() int64 {
+	if m != nil {
+		return m.ProcessedBytes
+	}
+	return 0
+}
+
+func (m *ImportMetadata) GetProcessedCount() int32 {
+	if m != nil {
+		return m.ProcessedCount
+	}
+	return 0
+}
+
+func (m *ImportMetadata) GetImportedCount() int32 {
+	if m != nil {
+		return m.ImportedCount
+	}
+	return 0
+}
+
+func (m *ImportMetadata) GetFailedCount() int32 {
+	if m != nil {
+		return m.FailedCount
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("grafeas.v1beta1.bulk.ImportFormat", ImportFormat_name, ImportFormat_value)
+	proto.RegisterType((*BatchDeleteOccurrencesRequest)(nil), "grafeas.v1beta1.bulk.BatchDeleteOccurrencesRequest")
+	proto.RegisterType((*BatchDeleteOccurrencesResponse)(nil), "grafeas.v1beta1.bulk.BatchDeleteOccurrencesResponse")
+	proto.RegisterType((*BatchDeleteOccurrencesResponse_Failure)(nil), "grafeas.v1beta1.bulk.BatchDeleteOccurrencesResponse.Failure")
+	proto.RegisterType((*BatchDeleteOccurrencesMetadata)(nil), "grafeas.v1beta1.bulk.BatchDeleteOccurrencesMetadata")
+	proto.RegisterType((*ImportNotesRequest)(nil), "grafeas.v1beta1.bulk.ImportNotesRequest")
+	proto.RegisterType((*ImportOccurrencesRequest)(nil), "grafeas.v1beta1.bulk.ImportOccurrencesRequest")
+	proto.RegisterType((*ImportResponse)(nil), "grafeas.v1beta1.bulk.ImportResponse")
+	proto.RegisterType((*ImportResponse_Failure)(nil), "grafeas.v1beta1.bulk.ImportResponse.Failure")
+	proto.RegisterType((*ImportMetadata)(nil), "grafeas.v1beta1.bulk.ImportMetadata")
+}
+
+func init() { proto.RegisterFile("proto/v1beta1/bulk.proto", fileDescriptor_fa8600042c629d5a) }
+
+var fileDescriptor_fa8600042c629d5a = []byte{
+	// 826 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x56, 0x41, 0x6f, 0xe3, 0x44,
+	0x14, 0x5e, 0xdb, 0xa9, 0xdb, 0xbe, 0x74, 0x43, 0x19, 0x45, 0xad, 0x89, 0x54, 0x36, 0x64, 0x85,
+	0x88, 0x56, 0xc8, 0x51, 0xb3, 0x82, 0x03, 0x70, 0xd9, 0x6c, 0x92, 0xd6, 0xa8, 0x49, 0x2a, 0x27,
+	0xa0, 0x85, 0x8b, 0x35, 0xb1, 0x27, 0xa9, 0xb5, 0x8e, 0xc7, 0x8c, 0xc7, 0x48, 0xbd, 0x70, 0xe5,
+	0x5f, 0x70, 0xe0, 0x27, 0x70, 0xe6, 0x02, 0x3f, 0x84, 0xdf, 0x82, 0x3c, 0x33, 0xf1, 0x66, 0x89,
+	0x37, 0xa1, 0x17, 0xc4, 0x29, 0x79, 0x9f, 0xbf, 0xf7, 0xe6, 0xe5, 0x7d, 0xef, 0x1b, 0x07, 0xac,
+	0x84, 0x51, 0x4e, 0x3b, 0x3f, 0x5e, 0xce, 0x09, 0xc7, 0x97, 0x9d, 0x79, 0x16, 0xbd, 0xb6, 0x05,
+	0x84, 0xea, 0x4b, 0x86, 0x17, 0x04, 0xa7, 0xb6, 0x7a, 0x66, 0xe7, 0xcf, 0x1a, 0x4f, 0x97, 0x94,
+	0x2e, 0x23, 0xd2, 0x89, 0x68, 0xbc, 0x64, 0x59, 0x1c, 0x87, 0xf1, 0xb2, 0x43, 0x13, 0xc2, 0x30,
+	0x0f, 0x69, 0x9c, 0xca, 0xd4, 0xc6, 0x13, 0x45, 0x12, 0xd1, 0x3c, 0x5b, 0x74, 0x78, 0xb8, 0x22,
+	0x29, 0xc7, 0xab, 0x44, 0x11, 0xce, 0x15, 0x81, 0x25, 0x7e, 0x27, 0xe5, 0x98, 0x67, 0x2a, 0xb3,
+	0xf5, 0x13, 0x5c, 0xf4, 0x30, 0xf7, 0xef, 0xfa, 0x24, 0x22, 0x9c, 0x4c, 0x7c, 0x3f, 0x63, 0x8c,
+	0xc4, 0x3e, 0x49, 0x5d, 0xf2, 0x43, 0x46, 0x52, 0x8e, 0xce, 0xc0, 0x4c, 0x30, 0x23, 0x31, 0xb7,
+	0xb4, 0xa6, 0xd6, 0x3e, 0x76, 0x55, 0x94, 0xe3, 0x8b, 0x30, 0xe2, 0x84, 0x59, 0xba, 0xc4, 0x65,
+	0x84, 0xea, 0x70, 0x10, 0xe3, 0x15, 0x49, 0x2d, 0xa3, 0x69, 0xb4, 0x8f, 0x5d, 0x19, 0xa0, 0x73,
+	0x38, 0x0c, 0xd8, 0xbd, 0xc7, 0xb2, 0xd8, 0xaa, 0x34, 0xb5, 0xf6, 0x91, 0x6b, 0x06, 0xec, 0xde,
+	0xcd, 0xe2, 0xd6, 0x6f, 0x3a, 0x7c, 0xf8, 0xae, 0x06, 0xd2, 0x84, 0xc6, 0x29, 0x41, 0x4f, 0xe1,
+	0xf1, 0x2a, 0x67, 0x90, 0xc0, 0xf3, 0x69, 0xa6, 0x1a, 0x39, 0x70, 0x4f, 0x14, 0xf8, 0x32, 0xc7,
+	0x72, 0x52, 0x20, 0x2a, 0xac, 0x49, 0xba, 0x24, 0x29, 0x50, 0x92, 0x3e, 0x82, 0x93, 0x05, 0x0e,
+	0xa3, 0x82, 0x63, 0x08, 0x4e, 0x55, 0x62, 0x92, 0xf2, 0x0a, 0x8e, 0xf2, 0x30, 0x63, 0x24, 0xb5,
+	0x2a, 0x4d, 0xa3, 0x5d, 0xed, 0x7e, 0x65, 0x97, 0xe9, 0x62, 0xef, 0x6e, 0xda, 0x1e, 0xca, 0x22,
+	0x6e, 0x51, 0xad, 0xe1, 0xc0, 0xa1, 0x02, 0x11, 0x82, 0x4a, 0x3e, 0x16, 0x35, 0x51, 0xf1, 0x1d,
+	0x3d, 0x03, 0x53, 0x0a, 0x23, 0x3a, 0xaf, 0x76, 0x91, 0x2d, 0x25, 0xb3, 0x59, 0xe2, 0xdb, 0x53,
+	0xf1, 0xc4, 0x55, 0x8c, 0xd6, 0x5f, 0xda, 0xbb, 0x86, 0x36, 0x22, 0x1c, 0x07, 0x98, 0x63, 0xf4,
+	0x25, 0x54, 0x7d, 0x46, 0x30, 0x27, 0x1e, 0x0f, 0xd5, 0x49, 0xd5, 0x6e, 0x63, 0x5d, 0x73, 0xbd,
+	0x27, 0xf6, 0x6c, 0xbd, 0x27, 0x2e, 0x48, 0x7a, 0x0e, 0xa0, 0xcf, 0xe0, 0x88, 0xc4, 0x81, 0xcc,
+	0xd4, 0xf7, 0x66, 0x1e, 0x92, 0x38, 0x10, 0x69, 0x4f, 0xa0, 0xca, 0x29, 0xc7, 0xd1, 0x5b, 0xd3,
+	0x05, 0x01, 0xc9, 0xe1, 0x7e, 0x02, 0xef, 0x25, 0x8c, 0xfa, 0x24, 0x4d, 0x0b, 0x09, 0x2a, 0x82,
+	0x54, 0x2b, 0x60, 0x41, 0x6c, 0xfd, 0xa2, 0x01, 0x72, 0x56, 0x09, 0x65, 0x7c, 0x4c, 0xf9, 0xfe,
+	0x5d, 0xfc, 0x02, 0xcc, 0x05, 0x65, 0x2b, 0x2c, 0x55, 0xaf, 0x75, 0x5b, 0xe5, 0x92, 0xc9, 0x8a,
+	0x43, 0xc1, 0x74, 0x55, 0x06, 0xaa, 0x43, 0x25, 0xc1, 0xfc, 0x4e, 0x74, 0x7b, 0x7c, 0xfd, 0xc8,
+	0x15, 0x51, 0x8e, 0xe6, 0x63, 0x14, 0xed, 0x9d, 0xe4, 0x68, 0x1e, 0xf5, 0x8e, 0xc0, 0x4c, 0x69,
+	0xc6, 0x7c, 0xd2, 0xfa, 0x5d, 0x03, 0x4b, 0x96, 0x7b, 0x80, 0x65, 0xfe, 0xa3, 0x36, 0xf3, 0xf3,
+	0xb3, 0x24, 0x25, 0x8c, 0x5b, 0x07, 0xd2, 0x6b, 0x32, 0xda, 0x68, 0xff, 0x67, 0x1d, 0x6a, 0xf2,
+	0x98, 0xc2, 0x65, 0x1f, 0x43, 0x2d, 0x14, 0xc8, 0x3f, 0x6c, 0xf6, 0x78, 0x8d, 0x96, 0x5b, 0x48,
+	0xdf, 0xb6, 0xd0, 0xf5, 0x86, 0x85, 0x0c, 0x61, 0xa1, 0x4f, 0x77, 0xfd, 0xd0, 0x1d, 0x96, 0xc1,
+	0x6f, 0x2c, 0x73, 0x06, 0x26, 0x23, 0x3e, 0x65, 0x81, 0x6a, 0x4b, 0x45, 0x85, 0x95, 0xf4, 0x52,
+	0x2b, 0x19, 0x7b, 0xad, 0xf4, 0x47, 0x31, 0x89, 0xff, 0x87, 0x75, 0xe6, 0xf7, 0x9c, 0xc8, 0xbe,
+	0x0d, 0x65, 0x9d, 0x5e, 0x8e, 0xbc, 0x6d, 0x1d, 0x49, 0xaa, 0x08, 0xd2, 0x1b, 0xeb, 0x94, 0x10,
+	0xa5, 0x46, 0x07, 0x65, 0x1e, 0x2b, 0x11, 0xdc, 0xfc, 0x37, 0x82, 0x1f, 0x6e, 0x09, 0xfe, 0x6c,
+	0x0a, 0x27, 0x9b, 0x3b, 0x8b, 0x2e, 0xe0, 0x03, 0x67, 0x74, 0x3b, 0x71, 0x67, 0xde, 0x70, 0xe2,
+	0x8e, 0x5e, 0xcc, 0xbc, 0x6f, 0xc6, 0xd3, 0xdb, 0xc1, 0x4b, 0x67, 0xe8, 0x0c, 0xfa, 0xa7, 0x8f,
+	0x50, 0x0d, 0xe0, 0xeb, 0xe9, 0x64, 0xec, 0xdd, 0x38, 0xe3, 0xc1, 0xf4, 0x54, 0x43, 0x75, 0x38,
+	0xbd, 0x19, 0x8c, 0xaf, 0x66, 0xd7, 0x5e, 0x7f, 0x70, 0xe3, 0x8c, 0x9c, 0xd9, 0xa0, 0x7f, 0xaa,
+	0x77, 0xff, 0xd4, 0x01, 0x5d, 0xc9, 0xad, 0xe9, 0x65, 0xd1, 0xeb, 0x6f, 0x2f, 0x7b, 0xf9, 0xe2,
+	0xa0, 0x08, 0xce, 0xca, 0x6f, 0x3e, 0xf4, 0xfc, 0x61, 0xf7, 0xb4, 0xb0, 0x6a, 0xe3, 0x62, 0x2d,
+	0xce, 0xc6, 0xeb, 0xd5, 0x9e, 0xac, 0x5f, 0xaf, 0xe8, 0x15, 0x54, 0x37, 0xae, 0x21, 0xd4, 0xde,
+	0xb5, 0xc7, 0x9b, 0x37, 0xd5, 0x9e, 0xba, 0x6d, 0x0d, 0x05, 0xf0, 0xfe, 0xd6, 0xfd, 0x81, 0xec,
+	0x5d, 0xf5, 0x1f, 0xdc, 0x7d, 0x5b, 0xeb, 0x7d, 0x07, 0xe7, 0x21, 0x2d, 0x2d, 0x7a, 0xab, 0x7d,
+	0xff, 0xf9, 0x32, 0xe4, 0x77, 0xd9, 0xdc, 0xf6, 0xe9, 0xaa, 0xa3, 0x28, 0xc5, 0xe7, 0xf6, 0x9f,
+	0x14, 0x6f, 0x49, 0x3d, 0x81, 0xfe, 0xaa, 0x1b, 0x57, 0xee, 0x8b, 0xb9, 0x29, 0x82, 0xe7, 0x7f,
+	0x0f, 0x00, 0x7a, 0xbd, 0x08, 0x8b, 0xd0, 0x08, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GrafeasBulkV1Beta1Client is the client API for GrafeasBulkV1Beta1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GrafeasBulkV1Beta1Client interface {
+	// Deletes the occurrences in a project that match a filter or are listed by
+	// name. If few occurrences match, the returned operation is already done.
+	// Otherwise they are deleted in the background and the operation's
+	// `BatchDeleteOccurrencesMetadata` reports the progress. The operation's
+	// response is a `BatchDeleteOccurrencesResponse`.
+	BatchDeleteOccurrences(ctx context.Context, in *BatchDeleteOccurrencesRequest, opts ...grpc.CallOption) (*longrunning.Operation, error)
+	// Imports notes into a project, from a file on the server or from data
+	// uploaded with the call. The first message of the stream names the project,
+	// the format of the notes, and either the file to import or the first chunk
+	// of the upload; the following messages carry the rest of the upload. The
+	// notes are created in the background and the operation's `ImportMetadata`
+	// reports the progress. The operation's response is an `ImportResponse`.
+	ImportNotes(ctx context.Context, opts ...grpc.CallOption) (GrafeasBulkV1Beta1_ImportNotesClient, error)
+	// Imports occurrences into a project, like `ImportNotes` imports notes.
+	ImportOccurrences(ctx context.Context, opts ...grpc.CallOption) (GrafeasBulkV1Beta1_ImportOccurrencesClient, error)
+}
+
+type grafeasBulkV1Beta1Client struct {
+	cc *grpc.ClientConn
+}
+
+func NewGrafeasBulkV1Beta1Client(cc *grpc.ClientConn) GrafeasBulkV1Beta1Client {
+	return &grafeasBulkV1Beta1Client{cc}
+}
+
+func (c *grafeasBulkV1Beta1Client) BatchDeleteOccurrences(ctx context.Context, in *BatchDeleteOccurrencesRequest, opts ...grpc.CallOption) (*longrunning.Operation, error) {
+	out := new(longrunning.Operation)
+	err := c.cc.Invoke(ctx, "/grafeas.v1beta1.bulk.GrafeasBulkV1Beta1/BatchDeleteOccurrences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grafeasBulkV1Beta1Client) ImportNotes(ctx context.Context, opts ...grpc.CallOption) (GrafeasBulkV1Beta1_ImportNotesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GrafeasBulkV1Beta1_serviceDesc.Streams[0], "/grafeas.v1beta1.bulk.GrafeasBulkV1Beta1/ImportNotes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grafeasBulkV1Beta1ImportNotesClient{stream}
+	return x, nil
+}
+
+type GrafeasBulkV1Beta1_ImportNotesClient interface {
+	Send(*ImportNotesRequest) error
+	CloseAndRecv() (*longrunning.Operation, error)
+	grpc.ClientStream
+}
+
+type grafeasBulkV1Beta1ImportNotesClient struct {
+	grpc.ClientStream
+}
+
+func (x *grafeasBulkV1Beta1ImportNotesClient) Send(m *ImportNotesRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *grafeasBulkV1Beta1ImportNotesClient) CloseAndRecv() (*longrunning.Operation, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(longrunning.Operation)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *grafeasBulkV1Beta1Client) ImportOccurrences(ctx context.Context, opts ...grpc.CallOption) (GrafeasBulkV1Beta1_ImportOccurrencesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GrafeasBulkV1Beta1_serviceDesc.Streams[1], "/grafeas.v1beta1.bulk.GrafeasBulkV1Beta1/ImportOccurrences", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grafeasBulkV1Beta1ImportOccurrencesClient{stream}
+	return x, nil
+}
+
+type GrafeasBulkV1Beta1_ImportOccurrencesClient interface {
+	Send(*ImportOccurrencesRequest) error
+	CloseAndRecv() (*longrunning.Operation, error)
+	grpc.ClientStream
+}
+
+type grafeasBulkV1Beta1ImportOccurrencesClient struct {
+	grpc.ClientStream
+}
+
+func (x *grafeasBulkV1Beta1ImportOccurrencesClient) Send(m *ImportOccurrencesRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *grafeasBulkV1Beta1ImportOccurrencesClient) CloseAndRecv() (*longrunning.Operation, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(longrunning.Operation)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GrafeasBulkV1Beta1Server is the server API for GrafeasBulkV1Beta1 service.
+type GrafeasBulkV1Beta1Server interface {
+	// Deletes the occurrences in a project that match a filter or are listed by
+	// name. If few occurrences match, the returned operation is already done.
+	// Otherwise they are deleted in the background and the operation's
+	// `BatchDeleteOccurrencesMetadata` reports the progress. The operation's
+	// response is a `BatchDeleteOccurrencesResponse`.
+	BatchDeleteOccurrences(context.Context, *BatchDeleteOccurrencesRequest) (*longrunning.Operation, error)
+	// Imports notes into a project, from a file on the server or from data
+	// uploaded with the call. The first message of the stream names the project,
+	// the format of the notes, and either the file to import or the first chunk
+	// of the upload; the following messages carry the rest of the upload. The
+	// notes are created in the background and the operation's `ImportMetadata`
+	// reports the progress. The operation's response is an `ImportResponse`.
+	ImportNotes(GrafeasBulkV1Beta1_ImportNotesServer) error
+	// Imports occurrences into a project, like `ImportNotes` imports notes.
+	ImportOccurrences(GrafeasBulkV1Beta1_ImportOccurrencesServer) error
+}
+
+func RegisterGrafeasBulkV1Beta1Server(s *grpc.Server, srv GrafeasBulkV1Beta1Server) {
+	s.RegisterService(&_GrafeasBulkV1Beta1_serviceDesc, srv)
+}
+
+func _GrafeasBulkV1Beta1_BatchDeleteOccurrences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteOccurrencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrafeasBulkV1Beta1Server).BatchDeleteOccurrences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grafeas.v1beta1.bulk.GrafeasBulkV1Beta1/BatchDeleteOccurrences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrafeasBulkV1Beta1Server).BatchDeleteOccurrences(ctx, req.(*BatchDeleteOccurrencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrafeasBulkV1Beta1_ImportNotes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GrafeasBulkV1Beta1Server).ImportNotes(&grafeasBulkV1Beta1ImportNotesServer{stream})
+}
+
+type GrafeasBulkV1Beta1_ImportNotesServer interface {
+	SendAndClose(*longrunning.Operation) error
+	Recv() (*ImportNotesRequest, error)
+	grpc.ServerStream
+}
+
+type grafeasBulkV1Beta1ImportNotesServer struct {
+	grpc.ServerStream
+}
+
+func (x *grafeasBulkV1Beta1ImportNotesServer) SendAndClose(m *longrunning.Operation) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *grafeasBulkV1Beta1ImportNotesServer) Recv() (*ImportNotesRequest, error) {
+	m := new(ImportNotesRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _GrafeasBulkV1Beta1_ImportOccurrences_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GrafeasBulkV1Beta1Server).ImportOccurrences(&grafeasBulkV1Beta1ImportOccurrencesServer{stream})
+}
+
+type GrafeasBulkV1Beta1_ImportOccurrencesServer interface {
+	SendAndClose(*longrunning.Operation) error
+	Recv() (*ImportOccurrencesRequest, error)
+	grpc.ServerStream
+}
+
+type grafeasBulkV1Beta1ImportOccurrencesServer struct {
+	grpc.ServerStream
+}
+
+func (x *grafeasBulkV1Beta1ImportOccurrencesServer) SendAndClose(m *longrunning.Operation) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *grafeasBulkV1Beta1ImportOccurrencesServer) Recv() (*ImportOccurrencesRequest, error) {
+	m := new(ImportOccurrencesRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _GrafeasBulkV1Beta1_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grafeas.v1beta1.bulk.GrafeasBulkV1Beta1",
+	HandlerType: (*GrafeasBulkV1Beta1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BatchDeleteOccurrences",
+			Handler:    _GrafeasBulkV1Beta1_BatchDeleteOccurrences_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportNotes",
+			Handler:       _GrafeasBulkV1Beta1_ImportNotes_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ImportOccurrences",
+			Handler:       _GrafeasBulkV1Beta1_ImportOccurrences_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proto/v1beta1/bulk.proto",
+}
@@ -892,10 +892,12 @@ func (m *UpdateOccurrenceRequest) GetUpdateMask() *field_mask.FieldMask {
 type GetNoteRequest struct {
 	// The name of the note in the form of
 	// `projects/[PROVIDER_ID]/notes/[NOTE_ID]`.
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// If set, gets the note as it was at this time instead of as it is now.
+	ReadTime             *timestamp.Timestamp `protobuf:"bytes,2,opt,name=read_time,json=readTime,proto3" json:"read_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *GetNoteRequest) Reset()         { *m = GetNoteRequest{} }
@@ -930,6 +932,13 @@ func (m *GetNoteRequest) GetName() string {
 	return ""
 }
 
+func (m *GetNoteRequest) GetReadTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReadTime
+	}
+	return nil
+}
+
 // Request to get the note to which the specified occurrence is attached.
 type GetOccurrenceNoteRequest struct {
 	// The name of the occurrence in the form of
@@ -1574,8 +1583,10 @@ type GetVulnerabilityOccurrencesSummaryRequest struct {
 func (m *GetVulnerabilityOccurrencesSummaryRequest) Reset() {
 	*m = GetVulnerabilityOccurrencesSummaryRequest{}
 }
-func (m *GetVulnerabilityOccurrencesSummaryRequest) String() string { return proto.CompactTextString(m) }
-func (*GetVulnerabilityOccurrencesSummaryRequest) ProtoMessage()    {}
+func (m *GetVulnerabilityOccurrencesSummaryRequest) String() string {
+	return proto.CompactTextString(m)
+}
+func (*GetVulnerabilityOccurrencesSummaryRequest) ProtoMessage() {}
 func (*GetVulnerabilityOccurrencesSummaryRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_a2686dc759bc3b97, []int{22}
 }
@@ -1759,125 +1770,126 @@ func init() {
 func init() { proto.RegisterFile("proto/v1beta1/grafeas.proto", fileDescriptor_a2686dc759bc3b97) }
 
 var fileDescriptor_a2686dc759bc3b97 = []byte{
-	// 1881 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0xdd, 0x73, 0xdb, 0x4a,
-	0x15, 0xaf, 0x9c, 0x4f, 0x1f, 0xe7, 0xc3, 0x59, 0x7a, 0x13, 0x5d, 0xe7, 0xb6, 0xf1, 0xd5, 0x2d,
-	0xbd, 0x89, 0xdb, 0xda, 0x6d, 0x5a, 0x3a, 0x34, 0x6d, 0xa6, 0x53, 0x27, 0x69, 0xd2, 0x01, 0xd2,
-	0x8e, 0x9a, 0x96, 0x19, 0x98, 0x8e, 0x47, 0xb6, 0x37, 0xb6, 0x88, 0x2c, 0x09, 0x69, 0xed, 0xa9,
-	0xcb, 0xb4, 0xc3, 0x30, 0x85, 0x37, 0x86, 0x07, 0x66, 0xe0, 0xbd, 0x2f, 0xf0, 0x27, 0x30, 0x3c,
-	0xf2, 0x0c, 0x4f, 0xbc, 0xc0, 0xf0, 0xca, 0x1f, 0xc2, 0xec, 0x6a, 0x25, 0xad, 0x2c, 0xc9, 0x56,
-	0x5a, 0xe0, 0xbe, 0x24, 0xd2, 0x9e, 0xcf, 0x3d, 0x7b, 0x7e, 0xbf, 0x3d, 0xb6, 0x61, 0xdd, 0x76,
-	0x2c, 0x62, 0xd5, 0x06, 0xb7, 0x9a, 0x98, 0x68, 0xb7, 0x6a, 0x1d, 0x47, 0x3b, 0xc5, 0x9a, 0x5b,
-	0x65, 0xab, 0x68, 0xd9, 0x7f, 0xe5, 0xe2, 0xd2, 0x17, 0x1d, 0xcb, 0xea, 0x18, 0xb8, 0xa6, 0xd9,
-	0x7a, 0x4d, 0x33, 0x4d, 0x8b, 0x68, 0x44, 0xb7, 0x4c, 0xae, 0x5e, 0x5a, 0xe7, 0x52, 0xf6, 0xd6,
-	0xec, 0x9f, 0xd6, 0x70, 0xcf, 0x26, 0x43, 0x2e, 0x2c, 0x8f, 0x0a, 0x4f, 0x75, 0x6c, 0xb4, 0x1b,
-	0x3d, 0xcd, 0x3d, 0xe3, 0x1a, 0x1b, 0xa3, 0x1a, 0x44, 0xef, 0x61, 0x97, 0x68, 0x3d, 0xdb, 0x57,
-	0x88, 0xe6, 0xaa, 0x11, 0x42, 0xc5, 0x34, 0x03, 0xae, 0xf0, 0x79, 0x54, 0xa1, 0xd9, 0xd7, 0x8d,
-	0x36, 0x17, 0x95, 0xa2, 0xa2, 0x96, 0xd5, 0xeb, 0x05, 0x66, 0x97, 0xa3, 0xb2, 0x36, 0xb6, 0x0d,
-	0x6b, 0xd8, 0xc3, 0x26, 0xe1, 0xf2, 0x4b, 0x23, 0x72, 0xdd, 0x6d, 0x59, 0x03, 0xec, 0x0c, 0x93,
-	0xa3, 0xea, 0x3d, 0xad, 0x83, 0xfd, 0x8a, 0x44, 0x45, 0xb6, 0xd6, 0x3a, 0x0b, 0x85, 0x23, 0x61,
-	0x6d, 0xc7, 0x1a, 0x60, 0x53, 0x33, 0x5b, 0xbe, 0xfc, 0xcb, 0xa8, 0x7c, 0xd0, 0x37, 0x4c, 0xec,
-	0x68, 0x4d, 0xdd, 0xd0, 0xfd, 0xa2, 0x2a, 0x7f, 0x9a, 0x05, 0x78, 0xda, 0x6a, 0xf5, 0x1d, 0x07,
-	0x9b, 0x2d, 0x8c, 0x10, 0x4c, 0x9b, 0x5a, 0x0f, 0xcb, 0x52, 0x59, 0xda, 0xcc, 0xab, 0xec, 0x19,
-	0x7d, 0x07, 0xe6, 0x1d, 0xec, 0x5a, 0x7d, 0xa7, 0x85, 0xe5, 0x5c, 0x59, 0xda, 0x2c, 0x6c, 0x7f,
-	0x5e, 0x1d, 0x39, 0xd6, 0xaa, 0xca, 0x15, 0xd4, 0x40, 0x15, 0xad, 0x43, 0xde, 0xb4, 0x08, 0x6e,
-	0x30, 0x7f, 0x53, 0xcc, 0xdf, 0x3c, 0x5d, 0x38, 0xa6, 0x3e, 0x6f, 0xc0, 0xf4, 0x99, 0x6e, 0xb6,
-	0xe5, 0xe9, 0xb2, 0xb4, 0xb9, 0x94, 0xe0, 0xef, 0xd8, 0x22, 0xf8, 0x7b, 0xba, 0xd9, 0x56, 0x99,
-	0x1a, 0x2a, 0x43, 0xc1, 0xc1, 0x3d, 0xdc, 0xd6, 0xd9, 0x59, 0xc9, 0x33, 0xcc, 0x9b, 0xb8, 0x84,
-	0xee, 0x43, 0xa1, 0xe5, 0x60, 0x8d, 0xe0, 0x06, 0x3d, 0x73, 0x79, 0x96, 0xe5, 0x59, 0xaa, 0x7a,
-	0x0d, 0x51, 0xf5, 0x1b, 0xa2, 0x7a, 0xe2, 0x37, 0x84, 0x0a, 0x9e, 0x3a, 0x5d, 0xa0, 0xc6, 0x7d,
-	0xbb, 0x1d, 0x18, 0xcf, 0x4d, 0x36, 0xf6, 0xd4, 0x99, 0xf1, 0x31, 0x2c, 0x46, 0x0a, 0x2b, 0xcf,
-	0x33, 0xf3, 0xab, 0xb1, 0x3d, 0x45, 0xcb, 0xbf, 0x8f, 0x89, 0xa6, 0x1b, 0xee, 0xd1, 0x05, 0x35,
-	0x6a, 0x8e, 0xee, 0xc2, 0x0c, 0x6b, 0x3b, 0x39, 0xcf, 0xfc, 0x5c, 0x8e, 0xf9, 0xf1, 0x9a, 0x32,
-	0xb4, 0xf7, 0xd4, 0xd1, 0x01, 0x2c, 0xb6, 0xb1, 0xa3, 0x0f, 0x70, 0xbb, 0xc1, 0x1a, 0x48, 0x86,
-	0x14, 0x7b, 0xaf, 0xbd, 0x42, 0xfb, 0x05, 0x6e, 0xf6, 0x84, 0xae, 0xa3, 0xc7, 0xb0, 0xa0, 0x9b,
-	0x2e, 0xd1, 0x0c, 0xc3, 0xab, 0x75, 0x81, 0x79, 0x29, 0xc7, 0xbc, 0xf8, 0x9d, 0x28, 0xf8, 0x11,
-	0xed, 0xd0, 0x01, 0x40, 0x08, 0x03, 0x79, 0x81, 0x79, 0xf9, 0x2a, 0xe6, 0x45, 0x40, 0x4a, 0xe8,
-	0x48, 0x30, 0x44, 0xfb, 0x00, 0x3e, 0x5a, 0x70, 0x5b, 0x5e, 0x64, 0x6e, 0x94, 0xb8, 0x9b, 0x00,
-	0x50, 0xa2, 0x97, 0xc0, 0x0e, 0x1d, 0x41, 0x41, 0xc0, 0xba, 0xbc, 0xc4, 0xdc, 0x5c, 0x89, 0xb9,
-	0x11, 0xf9, 0x20, 0x74, 0x24, 0x9a, 0xd6, 0xf3, 0x30, 0xd7, 0xf6, 0x24, 0xca, 0x5b, 0x98, 0xf7,
-	0xdb, 0x1e, 0xad, 0x8a, 0xb8, 0xa9, 0xe7, 0x64, 0x89, 0x63, 0xa7, 0x08, 0x53, 0x7d, 0x47, 0x67,
-	0xb0, 0xc9, 0xab, 0xf4, 0x11, 0x1d, 0xc2, 0x42, 0xcb, 0x32, 0x09, 0x36, 0x49, 0xa3, 0xab, 0xb9,
-	0x5d, 0x86, 0x8c, 0xc4, 0xfa, 0x86, 0x60, 0x3e, 0xd2, 0xdc, 0x2e, 0xf3, 0x59, 0xe0, 0x96, 0x74,
-	0x41, 0xf9, 0xeb, 0x1c, 0x4c, 0x53, 0x98, 0x24, 0x62, 0xf6, 0x1a, 0xac, 0xb8, 0x5d, 0xcb, 0x21,
-	0x8d, 0x36, 0x76, 0x5b, 0x8e, 0x6e, 0xb3, 0x6d, 0x7b, 0x59, 0x14, 0x99, 0x60, 0x3f, 0x5c, 0x47,
-	0x5b, 0x50, 0x34, 0x2c, 0xb3, 0x13, 0xd1, 0xf5, 0x00, 0xbb, 0x4c, 0xd7, 0x45, 0xd5, 0x73, 0xe2,
-	0xf6, 0x01, 0xc5, 0xad, 0xa1, 0x11, 0xdc, 0x6e, 0xf4, 0x1d, 0x43, 0x9e, 0x29, 0x4f, 0x6d, 0x16,
-	0xb6, 0xd7, 0x13, 0xd8, 0x83, 0xe9, 0xbc, 0x70, 0x0c, 0x15, 0x9c, 0xe0, 0x19, 0xed, 0xc1, 0x32,
-	0x7e, 0x6d, 0xeb, 0x0e, 0xab, 0x7c, 0x56, 0x5c, 0x2f, 0x85, 0x26, 0x3e, 0xb6, 0x45, 0x62, 0x98,
-	0xfb, 0x14, 0x62, 0x98, 0x3f, 0x17, 0x31, 0x5c, 0x07, 0xe4, 0x6f, 0x3e, 0x20, 0x42, 0x57, 0xce,
-	0x97, 0xa7, 0xe8, 0x21, 0x70, 0xc9, 0x31, 0x27, 0x44, 0x17, 0x9d, 0x8c, 0xd2, 0x88, 0x07, 0xdf,
-	0xeb, 0x13, 0x68, 0xe4, 0xa5, 0xf8, 0x16, 0x27, 0x93, 0x3b, 0x3e, 0x99, 0x78, 0x30, 0xfe, 0x22,
-	0x85, 0x4c, 0xea, 0xf4, 0x6f, 0x48, 0x25, 0xbb, 0x00, 0x4d, 0xcd, 0xc5, 0x9c, 0x47, 0x16, 0x52,
-	0x4c, 0x3d, 0x1e, 0xa9, 0x6b, 0xae, 0x4e, 0x51, 0x92, 0xa7, 0x16, 0x1e, 0x85, 0x3c, 0x80, 0x39,
-	0xce, 0x0e, 0x1c, 0xb0, 0xe9, 0xec, 0xf1, 0xcc, 0xfb, 0x7f, 0x74, 0x41, 0xf5, 0x4d, 0xd0, 0x91,
-	0x4f, 0x1c, 0x5a, 0xd3, 0xc0, 0x1c, 0xaa, 0x57, 0xc7, 0x13, 0x87, 0xaf, 0x1d, 0x72, 0x07, 0x7d,
-	0x43, 0xfb, 0x90, 0x0f, 0x88, 0x41, 0x5e, 0x4e, 0xc1, 0xbc, 0x40, 0x1d, 0xfe, 0x13, 0xdd, 0x4d,
-	0xb0, 0x8c, 0x5e, 0xc1, 0x67, 0x02, 0x01, 0x34, 0xb4, 0x3e, 0xe9, 0x5a, 0x0e, 0x3d, 0xa0, 0x62,
-	0x4a, 0x6a, 0x22, 0x8b, 0x3c, 0xf2, 0xb5, 0x8f, 0x2e, 0xa8, 0x17, 0x05, 0x41, 0xb0, 0x5e, 0x9f,
-	0x85, 0x69, 0x32, 0xb4, 0xb1, 0x52, 0x81, 0x8b, 0x87, 0x98, 0x84, 0x57, 0xb1, 0x8a, 0x7f, 0xda,
-	0xc7, 0x2e, 0x49, 0x42, 0xb7, 0xf2, 0x5e, 0x82, 0xd5, 0xef, 0xeb, 0xae, 0xa0, 0xed, 0xfa, 0xea,
-	0xab, 0x30, 0x6b, 0x6b, 0x0e, 0xa5, 0x5c, 0xcf, 0x80, 0xbf, 0xd1, 0xf5, 0x53, 0xdd, 0x20, 0xd8,
-	0xe1, 0x2c, 0xc0, 0xdf, 0xe8, 0x2d, 0x6d, 0x6b, 0x1d, 0xdc, 0x70, 0xf5, 0x37, 0xde, 0x2d, 0x3d,
-	0xa3, 0xce, 0xd3, 0x85, 0xe7, 0xfa, 0x1b, 0x8c, 0x2e, 0x01, 0x30, 0x21, 0xb1, 0xce, 0xb0, 0xc9,
-	0x30, 0x9f, 0x57, 0x99, 0xfa, 0x09, 0x5d, 0x50, 0x7e, 0x2e, 0xc1, 0x5a, 0x2c, 0x0d, 0xd7, 0xb6,
-	0x4c, 0x17, 0xa3, 0x5d, 0x28, 0x58, 0xe1, 0xb2, 0x2c, 0xa5, 0x20, 0x5f, 0xd8, 0xaf, 0xa8, 0x8f,
-	0xae, 0xc2, 0xb2, 0x89, 0x5f, 0x93, 0x86, 0x10, 0xde, 0xcb, 0x7b, 0x91, 0x2e, 0x3f, 0x0b, 0x52,
-	0xb8, 0x01, 0x6b, 0xfb, 0xd8, 0xc0, 0x04, 0x67, 0x2b, 0x9c, 0x09, 0x6b, 0x7b, 0x0c, 0xdd, 0x71,
-	0xf5, 0xb4, 0xc2, 0xdd, 0x07, 0x08, 0x13, 0xe3, 0xf3, 0xcf, 0xd8, 0x7d, 0x08, 0xea, 0xca, 0x1f,
-	0x24, 0x58, 0x7b, 0xc1, 0x18, 0x21, 0x53, 0x7e, 0x9f, 0x14, 0x4c, 0x20, 0x2b, 0x3a, 0x12, 0xf3,
-	0x8b, 0x25, 0x4e, 0x56, 0x8f, 0xe9, 0xd4, 0xfc, 0x03, 0xcd, 0x3d, 0xf3, 0xc9, 0x8a, 0x3e, 0x2b,
-	0x57, 0x60, 0xe9, 0x10, 0x13, 0x4a, 0x47, 0xe3, 0xea, 0x57, 0x05, 0x39, 0xd2, 0xa4, 0x93, 0xf4,
-	0xdf, 0x41, 0x91, 0x36, 0x08, 0x55, 0xfb, 0x46, 0x3a, 0xb4, 0x0b, 0x2b, 0x42, 0x7c, 0xde, 0x9a,
-	0xd7, 0x60, 0x86, 0xf2, 0xb1, 0xdf, 0x94, 0x9f, 0x25, 0x5e, 0x62, 0xaa, 0xa7, 0x93, 0xb9, 0x11,
-	0xbf, 0x86, 0x15, 0xaf, 0x11, 0x27, 0x95, 0xc4, 0x82, 0x15, 0xaf, 0x05, 0x45, 0xc5, 0xb4, 0x9a,
-	0xac, 0xc1, 0x1c, 0xbb, 0x3a, 0xf4, 0xb6, 0x5f, 0x14, 0xfa, 0xfa, 0xa4, 0x8d, 0xb6, 0x60, 0x9a,
-	0x3e, 0xf1, 0x43, 0x4e, 0xd9, 0x02, 0x53, 0x51, 0x7e, 0x23, 0xc1, 0x8a, 0xd7, 0x83, 0x13, 0x52,
-	0x0b, 0x9c, 0xe6, 0x26, 0x3a, 0xfd, 0xb4, 0x5e, 0x7b, 0x2f, 0x41, 0xc9, 0x3f, 0x96, 0x04, 0x0a,
-	0x4b, 0x4a, 0xed, 0x7f, 0xd1, 0x1c, 0xef, 0x25, 0x58, 0x4f, 0x4c, 0xe3, 0xff, 0x4b, 0x61, 0x7f,
-	0x93, 0x60, 0xad, 0xae, 0x91, 0x56, 0x37, 0x6c, 0x8b, 0x89, 0x58, 0x79, 0xe2, 0xb7, 0x70, 0x8e,
-	0x25, 0x75, 0x3b, 0x96, 0x54, 0x8a, 0x43, 0x76, 0x84, 0xee, 0x81, 0x49, 0x9c, 0x21, 0x6f, 0xf0,
-	0xd2, 0x53, 0x80, 0x70, 0x91, 0xce, 0xab, 0x67, 0x78, 0xc8, 0xa3, 0xd1, 0x47, 0x8a, 0x96, 0x81,
-	0x66, 0xf4, 0x27, 0x74, 0x85, 0xa7, 0xb3, 0x93, 0xfb, 0xae, 0xa4, 0x1c, 0x82, 0x1c, 0x8f, 0xfe,
-	0x11, 0xd0, 0x53, 0x06, 0x70, 0x49, 0x70, 0x74, 0x8e, 0xbb, 0x6e, 0xe4, 0xe0, 0x72, 0xe7, 0x3b,
-	0x38, 0xa5, 0x01, 0x97, 0xd3, 0xe2, 0xfe, 0x57, 0x3a, 0x43, 0xf9, 0x31, 0x6c, 0x1d, 0x62, 0x12,
-	0x99, 0xdc, 0x84, 0x28, 0xcf, 0xfb, 0xbd, 0x9e, 0xe6, 0x0c, 0x3f, 0x92, 0x2e, 0x95, 0x7f, 0xe5,
-	0x60, 0x63, 0x82, 0x6b, 0xf4, 0x0a, 0x66, 0x5b, 0x56, 0xdf, 0x24, 0x7e, 0xea, 0x07, 0xb1, 0xd4,
-	0x27, 0x78, 0xa8, 0x3e, 0xd6, 0x5f, 0xd3, 0x11, 0xeb, 0xc4, 0x22, 0x9a, 0x51, 0x1f, 0xee, 0xeb,
-	0x1d, 0xec, 0x12, 0x95, 0x3b, 0x2d, 0xfd, 0x43, 0x82, 0x8b, 0x49, 0x0a, 0x91, 0x6f, 0x12, 0xa4,
-	0xec, 0xdf, 0x24, 0xec, 0xc1, 0xbc, 0x8b, 0x07, 0x98, 0x0d, 0x5d, 0x39, 0xf6, 0xc1, 0xe3, 0xeb,
-	0x09, 0x53, 0xf1, 0x73, 0xae, 0xae, 0x06, 0x86, 0xe8, 0x2b, 0x58, 0x3c, 0xf5, 0x72, 0x6a, 0xb0,
-	0x34, 0x19, 0x5b, 0x4c, 0xa9, 0x0b, 0x7c, 0x71, 0x8f, 0xae, 0xa1, 0x0d, 0x28, 0x10, 0x9a, 0x31,
-	0x57, 0x99, 0x66, 0x2a, 0xc0, 0x96, 0x98, 0xc2, 0xf6, 0x5f, 0x56, 0x60, 0xe9, 0xd0, 0x0b, 0xfd,
-	0xf2, 0x56, 0x9d, 0x46, 0x46, 0xbf, 0x94, 0x60, 0x31, 0x72, 0x29, 0xa2, 0x6f, 0xc7, 0xb2, 0x4b,
-	0x9a, 0xec, 0x4a, 0xe3, 0x1a, 0x46, 0xb9, 0xf9, 0x8b, 0xbf, 0xff, 0xfb, 0xb7, 0xb9, 0x0a, 0xda,
-	0x0c, 0xbe, 0xbd, 0xf9, 0x19, 0x25, 0xc2, 0x5d, 0xdb, 0xb1, 0x7e, 0x82, 0x5b, 0xc4, 0xad, 0x55,
-	0x6a, 0x42, 0x4b, 0xd5, 0x2a, 0x6f, 0xd1, 0xef, 0x24, 0x58, 0x1e, 0x99, 0xc6, 0x50, 0xbc, 0x4e,
-	0xc9, 0x63, 0x63, 0x69, 0x73, 0xb2, 0xa2, 0xd7, 0xfb, 0x49, 0x89, 0x79, 0x1d, 0x29, 0xa4, 0xf6,
-	0x56, 0xcc, 0x0d, 0xfd, 0x4a, 0x82, 0xe2, 0xe8, 0x90, 0x86, 0xe2, 0x01, 0x53, 0xe6, 0xb8, 0xd2,
-	0x6a, 0xec, 0x56, 0x39, 0xe8, 0xd9, 0x64, 0xe8, 0x27, 0x52, 0xc9, 0x5e, 0xa1, 0xdf, 0x4b, 0x50,
-	0x1c, 0x05, 0x75, 0x42, 0x22, 0x29, 0x13, 0xe2, 0xf8, 0xf3, 0x7a, 0xc0, 0xb2, 0xb9, 0xab, 0x64,
-	0x2e, 0xcb, 0x8e, 0x38, 0xba, 0xfd, 0x59, 0x82, 0xd5, 0x64, 0xce, 0x41, 0xd5, 0x71, 0xdc, 0x9e,
-	0x70, 0x92, 0xb5, 0xcc, 0xfa, 0xfc, 0x40, 0x1f, 0xb2, 0xcc, 0xef, 0x29, 0x77, 0x32, 0x67, 0xde,
-	0x0c, 0x1d, 0xee, 0x48, 0x15, 0x56, 0xd6, 0xd1, 0x21, 0x37, 0xa1, 0xac, 0x29, 0x73, 0x70, 0xa6,
-	0xb2, 0x6e, 0x67, 0x3e, 0xe4, 0x48, 0x59, 0x7f, 0x2d, 0xc1, 0x4a, 0x6c, 0x5e, 0x45, 0x5b, 0xe3,
-	0xe1, 0x29, 0x4c, 0x49, 0xa5, 0xe4, 0x0b, 0x4a, 0xb9, 0xcb, 0xb2, 0xba, 0x89, 0xaa, 0x59, 0xb3,
-	0xaa, 0x79, 0xc3, 0x64, 0x0f, 0xe6, 0xf8, 0x90, 0x8d, 0x36, 0x92, 0x92, 0xc8, 0x10, 0xba, 0xc2,
-	0x42, 0x5f, 0x41, 0x4a, 0x7a, 0x68, 0x16, 0x8b, 0xf6, 0xfb, 0x3b, 0xc8, 0x07, 0xd3, 0x2f, 0xfa,
-	0x32, 0x11, 0xe1, 0xe2, 0x70, 0x50, 0x52, 0xc6, 0xa9, 0xf0, 0x6e, 0x49, 0x88, 0x9f, 0xd0, 0x2d,
-	0xde, 0x76, 0x09, 0x40, 0x38, 0x13, 0x23, 0x25, 0x05, 0xf1, 0xe2, 0xa6, 0xd3, 0xb0, 0xce, 0xa3,
-	0x56, 0xb2, 0xec, 0x7a, 0x08, 0x10, 0x8e, 0x1e, 0x09, 0x51, 0x63, 0xd3, 0x77, 0x5a, 0xa9, 0x39,
-	0xc1, 0x28, 0x19, 0xb6, 0xba, 0xe3, 0x4d, 0xc5, 0x1f, 0x24, 0x28, 0x8e, 0xce, 0x3e, 0x09, 0x48,
-	0x48, 0x19, 0xce, 0x4a, 0x5b, 0x19, 0x34, 0xf9, 0x31, 0xdc, 0x63, 0xb9, 0xdd, 0x56, 0xaa, 0x19,
-	0x72, 0x1b, 0x81, 0xeb, 0x10, 0x20, 0xfc, 0x38, 0x90, 0x50, 0x9f, 0xd8, 0x67, 0x85, 0x09, 0xf5,
-	0xd9, 0xce, 0x70, 0x28, 0xbc, 0x3e, 0x7f, 0x94, 0xe0, 0x5b, 0x09, 0x13, 0x37, 0xba, 0x96, 0xda,
-	0x78, 0x09, 0x04, 0x77, 0x3d, 0x9b, 0x32, 0x2f, 0x54, 0x06, 0xa8, 0xfa, 0x49, 0x46, 0x2e, 0xad,
-	0x7f, 0x4a, 0xa0, 0x4c, 0x1e, 0xd2, 0xd0, 0x4e, 0x12, 0x8c, 0xb3, 0x4d, 0x76, 0xa5, 0x9b, 0xe7,
-	0x9d, 0xba, 0x94, 0x03, 0xb6, 0x99, 0x87, 0x68, 0x37, 0x33, 0x55, 0x47, 0xc6, 0x22, 0xee, 0xa6,
-	0xfe, 0x43, 0x40, 0xba, 0x35, 0x1a, 0xfc, 0x99, 0xf4, 0xa3, 0x7b, 0x1d, 0x9d, 0x74, 0xfb, 0xcd,
-	0x6a, 0xcb, 0xea, 0xf9, 0x3f, 0xe3, 0x05, 0xff, 0x13, 0x7f, 0xe4, 0x6b, 0x74, 0xac, 0x06, 0x13,
-	0x7c, 0xc8, 0x4d, 0x1d, 0xaa, 0x8f, 0x9a, 0xb3, 0xec, 0xe5, 0xf6, 0x7f, 0x02, 0x00, 0x00, 0xff,
-	0xff, 0x85, 0x38, 0x1f, 0x20, 0x13, 0x1c, 0x00, 0x00,
+	// 1898 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0xcd, 0x6f, 0xdb, 0xc8,
+	0x15, 0x0f, 0x25, 0x7f, 0x48, 0x4f, 0xfe, 0x90, 0xa7, 0x59, 0x9b, 0x2b, 0x6f, 0x62, 0x2d, 0x77,
+	0xbb, 0x6b, 0x2b, 0x59, 0x29, 0x71, 0xb6, 0x69, 0xe3, 0x8d, 0xb1, 0x58, 0xd9, 0x8e, 0x1d, 0xb4,
+	0xf5, 0x06, 0x8c, 0x77, 0x0b, 0xb4, 0x08, 0x84, 0x91, 0x38, 0x96, 0x58, 0x53, 0xa4, 0x4a, 0x8e,
+	0x84, 0x68, 0x8b, 0x2c, 0x8a, 0x22, 0xed, 0xad, 0xe8, 0xa1, 0x40, 0x7b, 0xcf, 0xa5, 0xfd, 0x13,
+	0x8a, 0x1e, 0x7b, 0x6e, 0x4f, 0xbd, 0xb4, 0xe8, 0xb5, 0x7f, 0x48, 0x31, 0xc3, 0xa1, 0x38, 0x14,
+	0x49, 0x8b, 0x4e, 0xfa, 0x71, 0xb1, 0xc9, 0x79, 0xbf, 0xf7, 0x31, 0x6f, 0xde, 0xfb, 0xcd, 0x93,
+	0x04, 0x9b, 0x03, 0xd7, 0xa1, 0x4e, 0x63, 0x74, 0xb7, 0x4d, 0x28, 0xbe, 0xdb, 0xe8, 0xba, 0xf8,
+	0x9c, 0x60, 0xaf, 0xce, 0x57, 0xd1, 0x6a, 0xf0, 0x2a, 0xc4, 0x95, 0x77, 0xba, 0x8e, 0xd3, 0xb5,
+	0x48, 0x03, 0x0f, 0xcc, 0x06, 0xb6, 0x6d, 0x87, 0x62, 0x6a, 0x3a, 0xb6, 0x80, 0x57, 0x36, 0x85,
+	0x94, 0xbf, 0xb5, 0x87, 0xe7, 0x0d, 0xd2, 0x1f, 0xd0, 0xb1, 0x10, 0x56, 0xa7, 0x85, 0xe7, 0x26,
+	0xb1, 0x8c, 0x56, 0x1f, 0x7b, 0x17, 0x02, 0xb1, 0x35, 0x8d, 0xa0, 0x66, 0x9f, 0x78, 0x14, 0xf7,
+	0x07, 0x01, 0x20, 0x1a, 0x2b, 0xa6, 0x94, 0x78, 0x7e, 0x04, 0x02, 0xf0, 0x76, 0x14, 0xd0, 0x1e,
+	0x9a, 0x96, 0x21, 0x44, 0x95, 0xa8, 0xa8, 0xe3, 0xf4, 0xfb, 0x13, 0xb5, 0x9b, 0x51, 0x99, 0x41,
+	0x06, 0x96, 0x33, 0xee, 0x13, 0x9b, 0x0a, 0xf9, 0x8d, 0x29, 0xb9, 0xe9, 0x75, 0x9c, 0x11, 0x71,
+	0xc7, 0xc9, 0x5e, 0xcd, 0x3e, 0xee, 0x92, 0x20, 0x23, 0x51, 0xd1, 0x00, 0x77, 0x2e, 0x42, 0xe1,
+	0x94, 0xdb, 0x81, 0xeb, 0x8c, 0x88, 0x8d, 0xed, 0x4e, 0x20, 0x7f, 0x37, 0x2a, 0x1f, 0x0d, 0x2d,
+	0x9b, 0xb8, 0xb8, 0x6d, 0x5a, 0x66, 0x90, 0x54, 0xed, 0x8f, 0x0b, 0x00, 0x9f, 0x77, 0x3a, 0x43,
+	0xd7, 0x25, 0x76, 0x87, 0x20, 0x04, 0x73, 0x36, 0xee, 0x13, 0x55, 0xa9, 0x2a, 0xdb, 0x45, 0x9d,
+	0x3f, 0xa3, 0x6f, 0x41, 0xc1, 0x25, 0x9e, 0x33, 0x74, 0x3b, 0x44, 0xcd, 0x55, 0x95, 0xed, 0xd2,
+	0xee, 0xdb, 0xf5, 0xa9, 0x63, 0xad, 0xeb, 0x02, 0xa0, 0x4f, 0xa0, 0x68, 0x13, 0x8a, 0xb6, 0x43,
+	0x49, 0x8b, 0xdb, 0xcb, 0x73, 0x7b, 0x05, 0xb6, 0x70, 0xca, 0x6c, 0x7e, 0x04, 0x73, 0x17, 0xa6,
+	0x6d, 0xa8, 0x73, 0x55, 0x65, 0x7b, 0x25, 0xc1, 0xde, 0xa9, 0x43, 0xc9, 0x77, 0x4d, 0xdb, 0xd0,
+	0x39, 0x0c, 0x55, 0xa1, 0xe4, 0x92, 0x3e, 0x31, 0x4c, 0x7e, 0x56, 0xea, 0x3c, 0xb7, 0x26, 0x2f,
+	0xa1, 0x4f, 0xa0, 0xd4, 0x71, 0x09, 0xa6, 0xa4, 0xc5, 0xce, 0x5c, 0x5d, 0xe0, 0x71, 0x56, 0xea,
+	0x7e, 0x41, 0xd4, 0x83, 0x82, 0xa8, 0x9f, 0x05, 0x05, 0xa1, 0x83, 0x0f, 0x67, 0x0b, 0x4c, 0x79,
+	0x38, 0x30, 0x26, 0xca, 0x8b, 0xb3, 0x95, 0x7d, 0x38, 0x57, 0x3e, 0x85, 0xe5, 0x48, 0x62, 0xd5,
+	0x02, 0x57, 0xff, 0x20, 0xb6, 0xa7, 0x68, 0xfa, 0x0f, 0x09, 0xc5, 0xa6, 0xe5, 0x9d, 0x5c, 0xd3,
+	0xa3, 0xea, 0xe8, 0x3e, 0xcc, 0xf3, 0xb2, 0x53, 0x8b, 0xdc, 0xce, 0xcd, 0x98, 0x1d, 0x2e, 0x95,
+	0xf4, 0x7d, 0x38, 0x3a, 0x82, 0x65, 0x83, 0xb8, 0xe6, 0x88, 0x18, 0x2d, 0x5e, 0x40, 0x2a, 0xa4,
+	0xe8, 0x73, 0xa9, 0xa4, 0xbf, 0x24, 0xd4, 0x1e, 0xb3, 0x75, 0xf4, 0x08, 0x96, 0x4c, 0xdb, 0xa3,
+	0xd8, 0xb2, 0xfc, 0x5c, 0x97, 0xb8, 0x95, 0x6a, 0xcc, 0x4a, 0x50, 0x89, 0x92, 0x1d, 0x59, 0x0f,
+	0x1d, 0x01, 0x84, 0x6d, 0xa0, 0x2e, 0x71, 0x2b, 0xef, 0xc5, 0xac, 0x84, 0x10, 0xc9, 0x90, 0xa4,
+	0x88, 0x0e, 0x01, 0x82, 0x6e, 0x21, 0x86, 0xba, 0xcc, 0xcd, 0x68, 0x71, 0x33, 0x93, 0x86, 0x92,
+	0xad, 0x4c, 0xf4, 0xd0, 0x09, 0x94, 0xa4, 0x5e, 0x57, 0x57, 0xb8, 0x99, 0xf7, 0x63, 0x66, 0x24,
+	0x8c, 0x64, 0x48, 0x56, 0x6d, 0x16, 0x61, 0xd1, 0xf0, 0x25, 0xda, 0x0b, 0x28, 0x04, 0x65, 0x8f,
+	0xd6, 0xe5, 0xbe, 0x69, 0xe6, 0x54, 0x45, 0xf4, 0x4e, 0x19, 0xf2, 0x43, 0xd7, 0xe4, 0x6d, 0x53,
+	0xd4, 0xd9, 0x23, 0x3a, 0x86, 0xa5, 0x8e, 0x63, 0x53, 0x62, 0xd3, 0x56, 0x0f, 0x7b, 0x3d, 0x35,
+	0x9f, 0x96, 0xdf, 0xb0, 0x99, 0x4f, 0xb0, 0xd7, 0xe3, 0x36, 0x4b, 0x42, 0x93, 0x2d, 0x68, 0x7f,
+	0x59, 0x84, 0x39, 0xd6, 0x26, 0x89, 0x3d, 0x7b, 0x0b, 0xd6, 0xbc, 0x9e, 0xe3, 0xd2, 0x96, 0x41,
+	0xbc, 0x8e, 0x6b, 0x0e, 0xf8, 0xb6, 0xfd, 0x28, 0xca, 0x5c, 0x70, 0x18, 0xae, 0xa3, 0x1d, 0x28,
+	0x5b, 0x8e, 0xdd, 0x8d, 0x60, 0xfd, 0x86, 0x5d, 0x65, 0xeb, 0x32, 0xf4, 0x8a, 0x7d, 0xfb, 0x90,
+	0xf5, 0xad, 0x85, 0x29, 0x31, 0x5a, 0x43, 0xd7, 0x52, 0xe7, 0xab, 0xf9, 0xed, 0xd2, 0xee, 0x66,
+	0x02, 0x7b, 0x70, 0xcc, 0x17, 0xae, 0xa5, 0x83, 0x3b, 0x79, 0x46, 0x07, 0xb0, 0x4a, 0x9e, 0x0f,
+	0x4c, 0x97, 0x67, 0x3e, 0x6b, 0x5f, 0xaf, 0x84, 0x2a, 0x41, 0x6f, 0xcb, 0xc4, 0xb0, 0xf8, 0x26,
+	0xc4, 0x50, 0xb8, 0x12, 0x31, 0xdc, 0x06, 0x14, 0x6c, 0x7e, 0x42, 0x84, 0x9e, 0x5a, 0xac, 0xe6,
+	0xd9, 0x21, 0x08, 0xc9, 0xa9, 0x20, 0x44, 0x0f, 0x9d, 0x4d, 0xd3, 0x88, 0xdf, 0xbe, 0xb7, 0x67,
+	0xd0, 0xc8, 0x97, 0xf2, 0x5b, 0x9c, 0x4c, 0x3e, 0x0e, 0xc8, 0xc4, 0x6f, 0xe3, 0x77, 0x52, 0xc8,
+	0xa4, 0xc9, 0xfe, 0x86, 0x54, 0xb2, 0x0f, 0xd0, 0xc6, 0x1e, 0x11, 0x3c, 0xb2, 0x94, 0xa2, 0xca,
+	0xa5, 0xf5, 0x26, 0xf6, 0x4c, 0xd6, 0x25, 0x45, 0xa6, 0xe1, 0x53, 0xc8, 0x43, 0x58, 0x14, 0xec,
+	0xa0, 0x2e, 0xa7, 0x55, 0xb7, 0x2f, 0xaf, 0x3f, 0xf1, 0xff, 0x9f, 0x5c, 0xd3, 0x03, 0x15, 0x74,
+	0x12, 0x10, 0x07, 0x6e, 0x5b, 0x44, 0x5d, 0x49, 0x21, 0xd3, 0x08, 0x71, 0x04, 0xe8, 0x90, 0x3b,
+	0xd8, 0x1b, 0x3a, 0x84, 0xe2, 0x84, 0x18, 0xd4, 0xd5, 0x94, 0x9e, 0x97, 0xa8, 0x23, 0x78, 0x62,
+	0xbb, 0x99, 0x2c, 0xa3, 0x67, 0xf0, 0x96, 0x44, 0x00, 0x2d, 0x3c, 0xa4, 0x3d, 0xc7, 0x65, 0x07,
+	0x54, 0x4e, 0x09, 0x4d, 0x42, 0xd7, 0x3f, 0x0b, 0xd0, 0x27, 0xd7, 0xf4, 0xeb, 0x92, 0x60, 0xb2,
+	0xde, 0x5c, 0x80, 0x39, 0x3a, 0x1e, 0x10, 0xad, 0x06, 0xd7, 0x8f, 0x09, 0x0d, 0xaf, 0x62, 0x9d,
+	0xfc, 0x64, 0x48, 0x3c, 0x9a, 0xd4, 0xdd, 0xda, 0x4b, 0x05, 0xd6, 0xbf, 0x67, 0x7a, 0x12, 0xda,
+	0x0b, 0xe0, 0xeb, 0xb0, 0x30, 0xc0, 0x2e, 0xa3, 0x5c, 0x5f, 0x41, 0xbc, 0xb1, 0xf5, 0x73, 0xd3,
+	0xa2, 0xc4, 0x15, 0x2c, 0x20, 0xde, 0xd8, 0x2d, 0x3d, 0xc0, 0x5d, 0xd2, 0xf2, 0xcc, 0xaf, 0xfc,
+	0x5b, 0x7a, 0x5e, 0x2f, 0xb0, 0x85, 0xa7, 0xe6, 0x57, 0x04, 0xdd, 0x00, 0xe0, 0x42, 0xea, 0x5c,
+	0x10, 0x9b, 0xf7, 0x7c, 0x51, 0xe7, 0xf0, 0x33, 0xb6, 0xa0, 0xfd, 0x4c, 0x81, 0x8d, 0x58, 0x18,
+	0xde, 0xc0, 0xb1, 0x3d, 0x82, 0xf6, 0xa1, 0xe4, 0x84, 0xcb, 0xaa, 0x92, 0xd2, 0xf9, 0xd2, 0x7e,
+	0x65, 0x3c, 0xfa, 0x00, 0x56, 0x6d, 0xf2, 0x9c, 0xb6, 0x24, 0xf7, 0x7e, 0xdc, 0xcb, 0x6c, 0xf9,
+	0xc9, 0x24, 0x84, 0x8f, 0x60, 0xe3, 0x90, 0x58, 0x84, 0x92, 0x6c, 0x89, 0xb3, 0x61, 0xe3, 0x80,
+	0x77, 0x77, 0x1c, 0x9e, 0x96, 0xb8, 0x4f, 0x00, 0xc2, 0xc0, 0xc4, 0xfc, 0x73, 0xe9, 0x3e, 0x24,
+	0xb8, 0xf6, 0x7b, 0x05, 0x36, 0xbe, 0xe0, 0x8c, 0x90, 0x29, 0xbe, 0x37, 0x72, 0x26, 0x91, 0x15,
+	0x1b, 0x89, 0xd5, 0x7c, 0x0a, 0x59, 0x3d, 0x62, 0x53, 0xf3, 0xf7, 0xb1, 0x77, 0x11, 0x90, 0x15,
+	0x7b, 0xd6, 0x9e, 0xc1, 0xca, 0x31, 0xa1, 0x8c, 0x8e, 0x2e, 0x8b, 0xef, 0xdb, 0x50, 0x74, 0x09,
+	0x36, 0x7c, 0x36, 0xcc, 0xcd, 0x64, 0xc3, 0x02, 0x03, 0xb3, 0x57, 0xad, 0x0e, 0x6a, 0xa4, 0xba,
+	0x67, 0x38, 0xd2, 0xbe, 0x86, 0x32, 0xab, 0x2c, 0x06, 0xfb, 0xbf, 0x94, 0x76, 0x0f, 0xd6, 0x24,
+	0xff, 0xa2, 0xa6, 0x6f, 0xc1, 0x3c, 0x23, 0xf2, 0xa0, 0x9a, 0xdf, 0x4a, 0xbc, 0xfd, 0x74, 0x1f,
+	0x93, 0xb9, 0x82, 0x3f, 0x84, 0x35, 0xbf, 0x82, 0x67, 0xa5, 0xc4, 0x81, 0x35, 0xbf, 0x76, 0x65,
+	0x60, 0x5a, 0x4e, 0x36, 0x60, 0x91, 0xdf, 0x39, 0xa6, 0x11, 0x24, 0x85, 0xbd, 0x3e, 0x36, 0xd0,
+	0x0e, 0xcc, 0xb1, 0x27, 0x51, 0x1d, 0x29, 0x5b, 0xe0, 0x10, 0xed, 0xd7, 0x0a, 0xac, 0xf9, 0xc5,
+	0x3b, 0xab, 0x2c, 0x02, 0xa3, 0xb9, 0x99, 0x46, 0xdf, 0xac, 0x48, 0x5f, 0x2a, 0x50, 0x09, 0x8e,
+	0x25, 0x81, 0xfb, 0x92, 0x42, 0xfb, 0x6f, 0x14, 0xc7, 0x4b, 0x05, 0x36, 0x13, 0xc3, 0xf8, 0xdf,
+	0x72, 0xdf, 0x5f, 0x15, 0xd8, 0x68, 0x62, 0xda, 0xe9, 0x85, 0x65, 0x31, 0xb3, 0x57, 0x1e, 0x07,
+	0x25, 0x9c, 0xe3, 0x41, 0xdd, 0x8b, 0x05, 0x95, 0x62, 0x90, 0x1f, 0xa1, 0x77, 0x64, 0x53, 0x77,
+	0x2c, 0x0a, 0xbc, 0xf2, 0x39, 0x40, 0xb8, 0xc8, 0x06, 0xdd, 0x0b, 0x32, 0x16, 0xde, 0xd8, 0x23,
+	0xeb, 0x96, 0x11, 0xb6, 0x86, 0x33, 0xaa, 0xc2, 0xc7, 0xec, 0xe5, 0xbe, 0xa3, 0x68, 0xc7, 0xa0,
+	0xc6, 0xbd, 0xbf, 0x46, 0xeb, 0x69, 0x23, 0xb8, 0x21, 0x19, 0xba, 0xc2, 0x25, 0x39, 0x75, 0x70,
+	0xb9, 0xab, 0x1d, 0x9c, 0xd6, 0x82, 0x9b, 0x69, 0x7e, 0xff, 0x23, 0x95, 0xa1, 0xfd, 0x08, 0x76,
+	0x8e, 0x09, 0x8d, 0x8c, 0x7c, 0x92, 0x97, 0xa7, 0xc3, 0x7e, 0x1f, 0xbb, 0xe3, 0xd7, 0xa4, 0x4b,
+	0xed, 0x9f, 0x39, 0xd8, 0x9a, 0x61, 0x1a, 0x3d, 0x83, 0x85, 0x8e, 0x33, 0xb4, 0x69, 0x10, 0xfa,
+	0x51, 0x2c, 0xf4, 0x19, 0x16, 0xea, 0x8f, 0xcc, 0xe7, 0x6c, 0x36, 0x3b, 0x73, 0x28, 0xb6, 0x9a,
+	0xe3, 0x43, 0xb3, 0x4b, 0x3c, 0xaa, 0x0b, 0xa3, 0x95, 0xbf, 0x2b, 0x70, 0x3d, 0x09, 0x10, 0xf9,
+	0x0a, 0x42, 0xc9, 0xfe, 0x15, 0xc4, 0x01, 0x14, 0x3c, 0x32, 0x22, 0x7c, 0x5a, 0xcb, 0xf1, 0x4f,
+	0x2c, 0x1f, 0xce, 0x18, 0xa7, 0x9f, 0x0a, 0xb8, 0x3e, 0x51, 0x44, 0xef, 0xc1, 0xf2, 0xb9, 0x1f,
+	0x53, 0x8b, 0x87, 0xc9, 0xd9, 0x22, 0xaf, 0x2f, 0x89, 0xc5, 0x03, 0xb6, 0x86, 0xb6, 0xa0, 0x44,
+	0x59, 0xc4, 0x02, 0x32, 0xc7, 0x21, 0xc0, 0x97, 0x38, 0x60, 0xf7, 0xcf, 0x6b, 0xb0, 0x72, 0xec,
+	0xbb, 0xfe, 0xf2, 0x6e, 0x93, 0x79, 0x46, 0xbf, 0x50, 0x60, 0x39, 0x72, 0x29, 0xa2, 0x6f, 0xc6,
+	0xa2, 0x4b, 0x1a, 0x09, 0x2b, 0x97, 0x15, 0x8c, 0x76, 0xe7, 0xe7, 0x7f, 0xfb, 0xd7, 0x6f, 0x72,
+	0x35, 0xb4, 0x3d, 0xf9, 0xda, 0xe7, 0xa7, 0x8c, 0x08, 0xf7, 0x07, 0xae, 0xf3, 0x63, 0xd2, 0xa1,
+	0x5e, 0xa3, 0xd6, 0x90, 0x4a, 0xaa, 0x51, 0x7b, 0x81, 0x7e, 0xab, 0xc0, 0xea, 0xd4, 0x18, 0x87,
+	0xe2, 0x79, 0x4a, 0x9e, 0x37, 0x2b, 0xdb, 0xb3, 0x81, 0x7e, 0xed, 0x27, 0x05, 0xe6, 0x57, 0xa4,
+	0x14, 0xda, 0x0b, 0x39, 0x36, 0xf4, 0x4b, 0x05, 0xca, 0xd3, 0xd3, 0x1d, 0x8a, 0x3b, 0x4c, 0x19,
+	0x00, 0x2b, 0xeb, 0xb1, 0x5b, 0xe5, 0x88, 0x7d, 0x9b, 0x18, 0x04, 0x52, 0xcb, 0x9e, 0xa1, 0xdf,
+	0x29, 0x50, 0x9e, 0x6e, 0xea, 0x84, 0x40, 0x52, 0x46, 0xcb, 0xcb, 0xcf, 0xeb, 0x21, 0x8f, 0xe6,
+	0xbe, 0x96, 0x39, 0x2d, 0x7b, 0xf2, 0xcc, 0xf7, 0x27, 0x05, 0xd6, 0x93, 0x39, 0x07, 0xd5, 0x2f,
+	0xe3, 0xf6, 0x84, 0x93, 0x6c, 0x64, 0xc6, 0x8b, 0x03, 0xfd, 0x94, 0x47, 0xfe, 0x40, 0xfb, 0x38,
+	0x73, 0xe4, 0xed, 0xd0, 0xe0, 0x9e, 0x52, 0xe3, 0x69, 0x9d, 0x9e, 0x8e, 0x13, 0xd2, 0x9a, 0x32,
+	0x40, 0x67, 0x4a, 0xeb, 0x6e, 0xe6, 0x43, 0x8e, 0xa4, 0xf5, 0x57, 0x0a, 0xac, 0xc5, 0xe6, 0x55,
+	0xb4, 0x73, 0x79, 0x7b, 0x4a, 0x53, 0x52, 0x25, 0xf9, 0x82, 0xd2, 0xee, 0xf3, 0xa8, 0xee, 0xa0,
+	0x7a, 0xd6, 0xa8, 0x1a, 0xfe, 0x30, 0xd9, 0x87, 0x45, 0x31, 0x9d, 0xa3, 0xad, 0xa4, 0x20, 0x32,
+	0xb8, 0xae, 0x71, 0xd7, 0xef, 0x23, 0x2d, 0xdd, 0x35, 0xf7, 0xc5, 0xea, 0xfd, 0x6b, 0x28, 0x4e,
+	0xa6, 0x5f, 0xf4, 0x6e, 0x62, 0x87, 0xcb, 0xc3, 0x41, 0x45, 0xbb, 0x0c, 0x22, 0xaa, 0x25, 0xc1,
+	0x7f, 0x42, 0xb5, 0xf8, 0xdb, 0xa5, 0x00, 0xe1, 0x4c, 0x8c, 0xb4, 0x94, 0x8e, 0x97, 0x37, 0x9d,
+	0xd6, 0xeb, 0xc2, 0x6b, 0x2d, 0xcb, 0xae, 0xc7, 0x00, 0xe1, 0xe8, 0x91, 0xe0, 0x35, 0x36, 0x7d,
+	0xa7, 0xa5, 0x5a, 0x10, 0x8c, 0x96, 0x61, 0xab, 0x7b, 0xfe, 0x54, 0xfc, 0x4a, 0x81, 0xf2, 0xf4,
+	0xec, 0x93, 0xd0, 0x09, 0x29, 0xc3, 0x59, 0x65, 0x27, 0x03, 0x52, 0x1c, 0xc3, 0x03, 0x1e, 0xdb,
+	0x3d, 0xad, 0x9e, 0x21, 0xb6, 0xa9, 0x76, 0x1d, 0x03, 0x84, 0x1f, 0x07, 0x12, 0xf2, 0x13, 0xfb,
+	0xac, 0x30, 0x23, 0x3f, 0xbb, 0x19, 0x0e, 0x45, 0xe4, 0xe7, 0x0f, 0x0a, 0x7c, 0x23, 0x61, 0xe2,
+	0x46, 0xb7, 0x52, 0x0b, 0x2f, 0x81, 0xe0, 0x6e, 0x67, 0x03, 0x8b, 0x44, 0x65, 0x68, 0xd5, 0x20,
+	0xc8, 0xc8, 0xa5, 0xf5, 0x0f, 0x05, 0xb4, 0xd9, 0x43, 0x1a, 0xda, 0x4b, 0x6a, 0xe3, 0x6c, 0x93,
+	0x5d, 0xe5, 0xce, 0x55, 0xa7, 0x2e, 0xed, 0x88, 0x6f, 0xe6, 0x53, 0xb4, 0x9f, 0x99, 0xaa, 0x23,
+	0x63, 0x91, 0x30, 0xd3, 0xfc, 0x01, 0x20, 0xd3, 0x99, 0x76, 0xfe, 0x44, 0xf9, 0xe1, 0x83, 0xae,
+	0x49, 0x7b, 0xc3, 0x76, 0xbd, 0xe3, 0xf4, 0x83, 0xdf, 0xff, 0x26, 0xff, 0x13, 0x7f, 0x1d, 0x6c,
+	0x75, 0x9d, 0x16, 0x17, 0xbc, 0xca, 0xe5, 0x8f, 0xf5, 0xcf, 0xda, 0x0b, 0xfc, 0xe5, 0xde, 0xbf,
+	0x07, 0x00, 0xaa, 0x9d, 0x3c, 0x92, 0x4c, 0x1c, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -266,6 +266,10 @@ func request_GrafeasV1Beta1_GetOccurrenceNote_0(ctx context.Context, marshaler r
 
 }
 
+var (
+	filter_GrafeasV1Beta1_GetNote_0 = &utilities.DoubleArray{Encoding: map[string]int{"name": 0}, Base: []int{1, 1, 0}, Check: []int{0, 1, 2}}
+)
+
 func request_GrafeasV1Beta1_GetNote_0(ctx context.Context, marshaler runtime.Marshaler, client GrafeasV1Beta1Client, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
 	var protoReq GetNoteRequest
 	var metadata runtime.ServerMetadata
@@ -288,6 +292,10 @@ func request_GrafeasV1Beta1_GetNote_0(ctx context.Context, marshaler runtime.Mar
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "name", err)
 	}
 
+	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_GrafeasV1Beta1_GetNote_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
 	msg, err := client.GetNote(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
 	return msg, metadata, err
 
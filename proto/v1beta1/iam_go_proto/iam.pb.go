@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proto/v1beta1/iam.proto
+
+package iam_go_proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	v1 "google.golang.org/genproto/googleapis/iam/v1"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+func init() { proto.RegisterFile("proto/v1beta1/iam.proto", fileDescriptor_a05d16bd83832a25) }
+
+var fileDescriptor_a05d16bd83832a25 = []byte{
+	// 237 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2f, 0x28, 0xca, 0x2f,
+	0xc9, 0xd7, 0x2f, 0x33, 0x4c, 0x4a, 0x2d, 0x49, 0x34, 0xd4, 0xcf, 0x4c, 0xcc, 0xd5, 0x03, 0x8b,
+	0x08, 0x09, 0xa7, 0x17, 0x25, 0xa6, 0xa5, 0x26, 0x16, 0xeb, 0x41, 0xa5, 0xf4, 0x32, 0x13, 0x73,
+	0xa5, 0xe4, 0xd2, 0xf3, 0xf3, 0xd3, 0x73, 0x52, 0x41, 0xca, 0xf4, 0xcb, 0xc0, 0xaa, 0xe3, 0x0b,
+	0xf2, 0x73, 0x32, 0x93, 0x2b, 0x21, 0x9a, 0xa4, 0xa4, 0x50, 0xe5, 0x91, 0xe5, 0x8c, 0xa6, 0x30,
+	0x71, 0x09, 0xba, 0x43, 0xcc, 0xf4, 0x4c, 0xcc, 0x0d, 0x33, 0x74, 0x02, 0x99, 0x2a, 0xe4, 0xc9,
+	0xc5, 0x13, 0x9c, 0x5a, 0xe2, 0x99, 0x98, 0x1b, 0x00, 0x56, 0x2b, 0xa4, 0xa4, 0x07, 0x31, 0x02,
+	0x64, 0x9d, 0x5e, 0x99, 0xa1, 0x1e, 0xb2, 0x64, 0x50, 0x6a, 0x61, 0x69, 0x6a, 0x71, 0x89, 0x94,
+	0x28, 0x9a, 0x1a, 0xa8, 0x56, 0x4f, 0x2e, 0x1e, 0x77, 0x7c, 0x46, 0xb9, 0x13, 0x6f, 0x54, 0x26,
+	0x97, 0x50, 0x48, 0x6a, 0x31, 0x58, 0x79, 0x6a, 0x51, 0x6e, 0x66, 0x71, 0x71, 0x66, 0x7e, 0x5e,
+	0xb1, 0x90, 0x06, 0x9a, 0x62, 0x4c, 0x25, 0x30, 0x63, 0x35, 0x89, 0x50, 0x59, 0x5c, 0x90, 0x9f,
+	0x57, 0x9c, 0xea, 0x14, 0xce, 0x25, 0x96, 0x99, 0xaf, 0x87, 0x25, 0xb0, 0x03, 0x18, 0xa3, 0x4c,
+	0xd3, 0x33, 0x4b, 0x32, 0x4a, 0x93, 0xf4, 0x92, 0xf3, 0x73, 0xf5, 0xa1, 0x2a, 0xe0, 0x34, 0x46,
+	0xbc, 0xc5, 0xa7, 0xe7, 0xc7, 0x83, 0x05, 0x17, 0x31, 0x31, 0xbb, 0x07, 0x39, 0x26, 0xb1, 0x81,
+	0x39, 0xc6, 0x80, 0x01, 0x00, 0x14, 0x5b, 0x0b, 0xcc, 0xe2, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GrafeasIamV1Beta1Client is the client API for GrafeasIamV1Beta1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GrafeasIamV1Beta1Client interface {
+	// Sets the access control policy on the specified project or note, replacing
+	// any existing policy. If the policy's `etag` is set, it has to match the
+	// etag of the current policy.
+	//
+	// The caller needs the `projects.setIamPolicy` permission on projects and
+	// the `notes.setIamPolicy` permission on notes.
+	SetIamPolicy(ctx context.Context, in *v1.SetIamPolicyRequest, opts ...grpc.CallOption) (*v1.Policy, error)
+	// Gets the access control policy of the specified project or note. Resources
+	// without a policy have an empty one.
+	//
+	// The caller needs the `projects.getIamPolicy` permission on projects and
+	// the `notes.getIamPolicy` permission on notes.
+	GetIamPolicy(ctx context.Context, in *v1.GetIamPolicyRequest, opts ...grpc.CallOption) (*v1.Policy, error)
+	// Returns the subset of the specified permissions that the caller has on the
+	// specified project or note. No permission is needed to call it.
+	TestIamPermissions(ctx context.Context, in *v1.TestIamPermissionsRequest, opts ...grpc.CallOption) (*v1.TestIamPermissionsResponse, error)
+}
+
+type grafeasIamV1Beta1Client struct {
+	cc *grpc.ClientConn
+}
+
+func NewGrafeasIamV1Beta1Client(cc *grpc.ClientConn) GrafeasIamV1Beta1Client {
+	return &grafeasIamV1Beta1Client{cc}
+}
+
+func (c *grafeasIamV1Beta1Client) SetIamPolicy(ctx context.Context, in *v1.SetIamPolicyRequest, opts ...grpc.CallOption) (*v1.Policy, error) {
+	out := new(v1.Policy)
+	err := c.cc.Invoke(ctx, "/grafeas.v1beta1.iam.GrafeasIamV1Beta1/SetIamPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grafeasIamV1Beta1Client) GetIamPolicy(ctx context.Context, in *v1.GetIamPolicyRequest, opts ...grpc.CallOption) (*v1.Policy, error) {
+	out := new(v1.Policy)
+	err := c.cc.Invoke(ctx, "/grafeas.v1beta1.iam.GrafeasIamV1Beta1/GetIamPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grafeasIamV1Beta1Client) TestIamPermissions(ctx context.Context, in *v1.TestIamPermissionsRequest, opts ...grpc.CallOption) (*v1.TestIamPermissionsResponse, error) {
+	out := new(v1.TestIamPermissionsResponse)
+	err := c.cc.Invoke(ctx, "/grafeas.v1beta1.iam.GrafeasIamV1Beta1/TestIamPermissions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GrafeasIamV1Beta1Server is the server API for GrafeasIamV1Beta1 service.
+type GrafeasIamV1Beta1Server interface {
+	// Sets the access control policy on the specified project or note, replacing
+	// any existing policy. If the policy's `etag` is set, it has to match the
+	// etag of the current policy.
+	//
+	// The caller needs the `projects.setIamPolicy` permission on projects and
+	// the `notes.setIamPolicy` permission on notes.
+	SetIamPolicy(context.Context, *v1.SetIamPolicyRequest) (*v1.Policy, error)
+	// Gets the access control policy of the specified project or note. Resources
+	// without a policy have an empty one.
+	//
+	// The caller needs the `projects.getIamPolicy` permission on projects and
+	// the `notes.getIamPolicy` permission on notes.
+	GetIamPolicy(context.Context, *v1.GetIamPolicyRequest) (*v1.Policy, error)
+	// Returns the subset of the specified permissions that the caller has on the
+	// specified project or note. No permission is needed to call it.
+	TestIamPermissions(context.Context, *v1.TestIamPermissionsRequest) (*v1.TestIamPermissionsResponse, error)
+}
+
+func RegisterGrafeasIamV1Beta1Server(s *grpc.Server, srv GrafeasIamV1Beta1Server) {
+	s.RegisterService(&_GrafeasIamV1Beta1_serviceDesc, srv)
+}
+
+func _GrafeasIamV1Beta1_SetIamPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.SetIamPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrafeasIamV1Beta1Server).SetIamPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grafeas.v1beta1.iam.GrafeasIamV1Beta1/SetIamPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrafeasIamV1Beta1Server).SetIamPolicy(ctx, req.(*v1.SetIamPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrafeasIamV1Beta1_GetIamPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.GetIamPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrafeasIamV1Beta1Server).GetIamPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grafeas.v1beta1.iam.GrafeasIamV1Beta1/GetIamPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrafeasIamV1Beta1Server).GetIamPolicy(ctx, req.(*v1.GetIamPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrafeasIamV1Beta1_TestIamPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.TestIamPermissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrafeasIamV1Beta1Server).TestIamPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grafeas.v1beta1.iam.GrafeasIamV1Beta1/TestIamPermissions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrafeasIamV1Beta1Server).TestIamPermissions(ctx, req.(*v1.TestIamPermissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GrafeasIamV1Beta1_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grafeas.v1beta1.iam.GrafeasIamV1Beta1",
+	HandlerType: (*GrafeasIamV1Beta1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetIamPolicy",
+			Handler:    _GrafeasIamV1Beta1_SetIamPolicy_Handler,
+		},
+		{
+			MethodName: "GetIamPolicy",
+			Handler:    _GrafeasIamV1Beta1_GetIamPolicy_Handler,
+		},
+		{
+			MethodName: "TestIamPermissions",
+			Handler:    _GrafeasIamV1Beta1_TestIamPermissions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/v1beta1/iam.proto",
+}
@@ -0,0 +1,512 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proto/v1beta1/revision.proto
+
+package revision_go_proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grafeas_go_proto "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// A note as it was before an update.
+type NoteRevision struct {
+	// The note as it was.
+	Note *grafeas_go_proto.Note `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	// The time the update replaced this revision.
+	ReplaceTime          *timestamp.Timestamp `protobuf:"bytes,2,opt,name=replace_time,json=replaceTime,proto3" json:"replace_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *NoteRevision) Reset()         { *m = NoteRevision{} }
+func (m *NoteRevision) String() string { return proto.CompactTextString(m) }
+func (*NoteRevision) ProtoMessage()    {}
+func (*NoteRevision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aa7a2c96ee228599, []int{0}
+}
+
+func (m *NoteRevision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NoteRevision.Unmarshal(m, b)
+}
+func (m *NoteRevision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NoteRevision.Marshal(b, m, deterministic)
+}
+func (m *NoteRevision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NoteRevision.Merge(m, src)
+}
+func (m *NoteRevision) XXX_Size() int {
+	return xxx_messageInfo_NoteRevision.Size(m)
+}
+func (m *NoteRevision) XXX_DiscardUnknown() {
+	xxx_messageInfo_NoteRevision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NoteRevision proto.InternalMessageInfo
+
+func (m *NoteRevision) GetNote() *grafeas_go_proto.Note {
+	if m != nil {
+		return m.Note
+	}
+	return nil
+}
+
+func (m *NoteRevision) GetReplaceTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReplaceTime
+	}
+	return nil
+}
+
+// An occurrence as it was before an update.
+type OccurrenceRevision struct {
+	// The occurrence as it was.
+	Occurrence *grafeas_go_proto.Occurrence `protobuf:"bytes,1,opt,name=occurrence,proto3" json:"occurrence,omitempty"`
+	// The time the update replaced this revision.
+	ReplaceTime          *timestamp.Timestamp `protobuf:"bytes,2,opt,name=replace_time,json=replaceTime,proto3" json:"replace_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *OccurrenceRevision) Reset()         { *m = OccurrenceRevision{} }
+func (m *OccurrenceRevision) String() string { return proto.CompactTextString(m) }
+func (*OccurrenceRevision) ProtoMessage()    {}
+func (*OccurrenceRevision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aa7a2c96ee228599, []int{1}
+}
+
+func (m *OccurrenceRevision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OccurrenceRevision.Unmarshal(m, b)
+}
+func (m *OccurrenceRevision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OccurrenceRevision.Marshal(b, m, deterministic)
+}
+func (m *OccurrenceRevision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OccurrenceRevision.Merge(m, src)
+}
+func (m *OccurrenceRevision) XXX_Size() int {
+	return xxx_messageInfo_OccurrenceRevision.Size(m)
+}
+func (m *OccurrenceRevision) XXX_DiscardUnknown() {
+	xxx_messageInfo_OccurrenceRevision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OccurrenceRevision proto.InternalMessageInfo
+
+func (m *OccurrenceRevision) GetOccurrence() *grafeas_go_proto.Occurrence {
+	if m != nil {
+		return m.Occurrence
+	}
+	return nil
+}
+
+func (m *OccurrenceRevision) GetReplaceTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReplaceTime
+	}
+	return nil
+}
+
+// Request to list the revisions of a note.
+type ListNoteRevisionsRequest struct {
+	// The name of the note in the form of
+	// `projects/[PROVIDER_ID]/notes/[NOTE_ID]`.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Number of revisions to return in the list.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Token to provide to skip to a particular spot in the list.
+	PageToken            string   `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListNoteRevisionsRequest) Reset()         { *m = ListNoteRevisionsRequest{} }
+func (m *ListNoteRevisionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListNoteRevisionsRequest) ProtoMessage()    {}
+func (*ListNoteRevisionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aa7a2c96ee228599, []int{2}
+}
+
+func (m *ListNoteRevisionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListNoteRevisionsRequest.Unmarshal(m, b)
+}
+func (m *ListNoteRevisionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListNoteRevisionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListNoteRevisionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListNoteRevisionsRequest.Merge(m, src)
+}
+func (m *ListNoteRevisionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListNoteRevisionsRequest.Size(m)
+}
+func (m *ListNoteRevisionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListNoteRevisionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListNoteRevisionsRequest proto.InternalMessageInfo
+
+func (m *ListNoteRevisionsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ListNoteRevisionsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListNoteRevisionsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// Response for listing the revisions of a note.
+type ListNoteRevisionsResponse struct {
+	// The revisions requested.
+	Revisions []*NoteRevision `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	// The next pagination token in the list response. It should be used as
+	// `page_token` for the following request. An empty value means no more
+	// results.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListNoteRevisionsResponse) Reset()         { *m = ListNoteRevisionsResponse{} }
+func (m *ListNoteRevisionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListNoteRevisionsResponse) ProtoMessage()    {}
+func (*ListNoteRevisionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aa7a2c96ee228599, []int{3}
+}
+
+func (m *ListNoteRevisionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListNoteRevisionsResponse.Unmarshal(m, b)
+}
+func (m *ListNoteRevisionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListNoteRevisionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListNoteRevisionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListNoteRevisionsResponse.Merge(m, src)
+}
+func (m *ListNoteRevisionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListNoteRevisionsResponse.Size(m)
+}
+func (m *ListNoteRevisionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListNoteRevisionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListNoteRevisionsResponse proto.InternalMessageInfo
+
+func (m *ListNoteRevisionsResponse) GetRevisions() []*NoteRevision {
+	if m != nil {
+		return m.Revisions
+	}
+	return nil
+}
+
+func (m *ListNoteRevisionsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// Request to list the revisions of an occurrence.
+type ListOccurrenceRevisionsRequest struct {
+	// The name of the occurrence in the form of
+	// `projects/[PROJECT_ID]/occurrences/[OCCURRENCE_ID]`.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Number of revisions to return in the list.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Token to provide to skip to a particular spot in the list.
+	PageToken            string   `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListOccurrenceRevisionsRequest) Reset()         { *m = ListOccurrenceRevisionsRequest{} }
+func (m *ListOccurrenceRevisionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListOccurrenceRevisionsRequest) ProtoMessage()    {}
+func (*ListOccurrenceRevisionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aa7a2c96ee228599, []int{4}
+}
+
+func (m *ListOccurrenceRevisionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOccurrenceRevisionsRequest.Unmarshal(m, b)
+}
+func (m *ListOccurrenceRevisionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOccurrenceRevisionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListOccurrenceRevisionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOccurrenceRevisionsRequest.Merge(m, src)
+}
+func (m *ListOccurrenceRevisionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListOccurrenceRevisionsRequest.Size(m)
+}
+func (m *ListOccurrenceRevisionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOccurrenceRevisionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOccurrenceRevisionsRequest proto.InternalMessageInfo
+
+func (m *ListOccurrenceRevisionsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ListOccurrenceRevisionsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListOccurrenceRevisionsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// Response for listing the revisions of an occurrence.
+type ListOccurrenceRevisionsResponse struct {
+	// The revisions requested.
+	Revisions []*OccurrenceRevision `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	// The next pagination token in the list response. It should be used as
+	// `page_token` for the following request. An empty value means no more
+	// results.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListOccurrenceRevisionsResponse) Reset()         { *m = ListOccurrenceRevisionsResponse{} }
+func (m *ListOccurrenceRevisionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListOccurrenceRevisionsResponse) ProtoMessage()    {}
+func (*ListOccurrenceRevisionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aa7a2c96ee228599, []int{5}
+}
+
+func (m *ListOccurrenceRevisionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOccurrenceRevisionsResponse.Unmarshal(m, b)
+}
+func (m *ListOccurrenceRevisionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOccurrenceRevisionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListOccurrenceRevisionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOccurrenceRevisionsResponse.Merge(m, src)
+}
+func (m *ListOccurrenceRevisionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListOccurrenceRevisionsResponse.Size(m)
+}
+func (m *ListOccurrenceRevisionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOccurrenceRevisionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOccurrenceRevisionsResponse proto.InternalMessageInfo
+
+func (m *ListOccurrenceRevisionsResponse) GetRevisions() []*OccurrenceRevision {
+	if m != nil {
+		return m.Revisions
+	}
+	return nil
+}
+
+func (m *ListOccurrenceRevisionsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*NoteRevision)(nil), "grafeas.v1beta1.revision.NoteRevision")
+	proto.RegisterType((*OccurrenceRevision)(nil), "grafeas.v1beta1.revision.OccurrenceRevision")
+	proto.RegisterType((*ListNoteRevisionsRequest)(nil), "grafeas.v1beta1.revision.ListNoteRevisionsRequest")
+	proto.RegisterType((*ListNoteRevisionsResponse)(nil), "grafeas.v1beta1.revision.ListNoteRevisionsResponse")
+	proto.RegisterType((*ListOccurrenceRevisionsRequest)(nil), "grafeas.v1beta1.revision.ListOccurrenceRevisionsRequest")
+	proto.RegisterType((*ListOccurrenceRevisionsResponse)(nil), "grafeas.v1beta1.revision.ListOccurrenceRevisionsResponse")
+}
+
+func init() { proto.RegisterFile("proto/v1beta1/revision.proto", fileDescriptor_aa7a2c96ee228599) }
+
+var fileDescriptor_aa7a2c96ee228599 = []byte{
+	// 456 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x94, 0xcf, 0x6b, 0xd5, 0x40,
+	0x10, 0xc7, 0xd9, 0xf7, 0xaa, 0xf8, 0xe6, 0x55, 0xc4, 0x05, 0x69, 0x4c, 0xd4, 0x96, 0x77, 0x28,
+	0x15, 0x64, 0xc3, 0x4b, 0x2f, 0xfe, 0xc0, 0x83, 0x45, 0x28, 0x88, 0x68, 0x89, 0xc5, 0x83, 0x97,
+	0xb0, 0x09, 0xd3, 0xb8, 0xda, 0x64, 0x63, 0x76, 0x53, 0x4a, 0xf1, 0x1f, 0xf0, 0x22, 0x5e, 0xfc,
+	0x07, 0x3c, 0xf9, 0x67, 0x4a, 0x36, 0x3f, 0x1a, 0x4d, 0x57, 0xf0, 0x49, 0x4f, 0x49, 0x66, 0xe6,
+	0x3b, 0xf3, 0xc9, 0x77, 0x96, 0x85, 0x3b, 0x45, 0x29, 0xb5, 0xf4, 0x4f, 0x96, 0x31, 0x6a, 0xbe,
+	0xf4, 0x4b, 0x3c, 0x11, 0x4a, 0xc8, 0x9c, 0x99, 0x30, 0x75, 0xd2, 0x92, 0x1f, 0x21, 0x57, 0xac,
+	0xcd, 0xb3, 0x2e, 0xef, 0x6e, 0xa6, 0x52, 0xa6, 0xc7, 0xe8, 0x9b, 0xba, 0xb8, 0x3a, 0xf2, 0xb5,
+	0xc8, 0x50, 0x69, 0x9e, 0x15, 0x8d, 0xd4, 0xf5, 0x7e, 0x6f, 0xdc, 0x35, 0x32, 0xd1, 0xc5, 0x29,
+	0xac, 0xbf, 0x92, 0x1a, 0xc3, 0xb6, 0x1b, 0xbd, 0x0f, 0x6b, 0xb9, 0xd4, 0xe8, 0x90, 0x2d, 0xb2,
+	0x33, 0x0f, 0x6e, 0xb1, 0x3f, 0xc7, 0x9a, 0x62, 0x53, 0x42, 0x9f, 0xc2, 0x7a, 0x89, 0xc5, 0x31,
+	0x4f, 0x30, 0xaa, 0x47, 0x3a, 0x13, 0x23, 0x71, 0x59, 0xc3, 0xc3, 0x3a, 0x1e, 0x76, 0xd8, 0xf1,
+	0x84, 0xf3, 0xb6, 0xbe, 0x8e, 0x2c, 0xbe, 0x11, 0xa0, 0xaf, 0x93, 0xa4, 0x2a, 0x4b, 0xcc, 0x93,
+	0x73, 0x80, 0x27, 0x00, 0xb2, 0x8f, 0xb6, 0x18, 0xde, 0x08, 0x63, 0x20, 0x1c, 0x94, 0xff, 0x2f,
+	0xd2, 0x07, 0x70, 0x5e, 0x0a, 0xa5, 0x87, 0x86, 0xa8, 0x10, 0x3f, 0x55, 0xa8, 0x34, 0xa5, 0xb0,
+	0x96, 0xf3, 0xac, 0x21, 0x9a, 0x85, 0xe6, 0x9d, 0x7a, 0x30, 0x2b, 0x78, 0x8a, 0x91, 0x12, 0x67,
+	0xcd, 0xac, 0x2b, 0xe1, 0xb5, 0x3a, 0xf0, 0x46, 0x9c, 0x21, 0xbd, 0x0b, 0x60, 0x92, 0x5a, 0x7e,
+	0xc4, 0xdc, 0x99, 0x1a, 0x99, 0x29, 0x3f, 0xac, 0x03, 0x8b, 0x2f, 0x04, 0x6e, 0x5f, 0x30, 0x4c,
+	0x15, 0x32, 0x57, 0x48, 0x9f, 0xc3, 0xac, 0x5b, 0xb0, 0x72, 0xc8, 0xd6, 0x74, 0x67, 0x1e, 0x6c,
+	0x33, 0xdb, 0x11, 0x60, 0xc3, 0x1e, 0xe1, 0xb9, 0x90, 0x6e, 0xc3, 0x8d, 0x1c, 0x4f, 0x75, 0x34,
+	0xe0, 0x98, 0x18, 0x8e, 0xeb, 0x75, 0xf8, 0xa0, 0x67, 0x29, 0xe0, 0x5e, 0x8d, 0x32, 0xde, 0xc6,
+	0xa5, 0xfd, 0xfd, 0x77, 0x02, 0x9b, 0xd6, 0x91, 0xad, 0x07, 0x2f, 0xc6, 0x1e, 0x3c, 0xb0, 0x7b,
+	0x30, 0xee, 0xb4, 0x82, 0x13, 0xc1, 0xcf, 0x09, 0x6c, 0xec, 0x37, 0x23, 0x7a, 0xa0, 0xb7, 0xcb,
+	0xbd, 0x7a, 0x14, 0xfd, 0x0c, 0x37, 0x47, 0x0b, 0xa3, 0x81, 0x9d, 0xc8, 0x76, 0x94, 0xdc, 0xdd,
+	0x7f, 0xd2, 0xb4, 0x6e, 0x7c, 0x25, 0xb0, 0x61, 0x71, 0x8c, 0x3e, 0xfc, 0x7b, 0x43, 0xfb, 0x5e,
+	0xdd, 0x47, 0x2b, 0x28, 0x1b, 0xa0, 0x3d, 0x0e, 0x9e, 0x90, 0x56, 0xf9, 0x01, 0x79, 0xf7, 0x38,
+	0x15, 0xfa, 0x7d, 0x15, 0xb3, 0x44, 0x66, 0xdd, 0xa5, 0xd3, 0x3f, 0x2f, 0xbe, 0xeb, 0xa2, 0x54,
+	0x46, 0x26, 0xf3, 0x63, 0x32, 0xdd, 0x0f, 0x9f, 0xc5, 0x57, 0xcd, 0xc7, 0xee, 0xaf, 0x01, 0x00,
+	0x83, 0x5b, 0x7e, 0xc9, 0x1b, 0x05, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GrafeasRevisionsV1Beta1Client is the client API for GrafeasRevisionsV1Beta1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GrafeasRevisionsV1Beta1Client interface {
+	// Lists the previous revisions of the specified note, newest first.
+	//
+	// The caller needs the `notes.get` permission on the note.
+	ListNoteRevisions(ctx context.Context, in *ListNoteRevisionsRequest, opts ...grpc.CallOption) (*ListNoteRevisionsResponse, error)
+	// Lists the previous revisions of the specified occurrence, newest first.
+	//
+	// The caller needs the `occurrences.get` permission in the occurrence's
+	// project.
+	ListOccurrenceRevisions(ctx context.Context, in *ListOccurrenceRevisionsRequest, opts ...grpc.CallOption) (*ListOccurrenceRevisionsResponse, error)
+}
+
+type grafeasRevisionsV1Beta1Client struct {
+	cc *grpc.ClientConn
+}
+
+func NewGrafeasRevisionsV1Beta1Client(cc *grpc.ClientConn) GrafeasRevisionsV1Beta1Client {
+	return &grafeasRevisionsV1Beta1Client{cc}
+}
+
+func (c *grafeasRevisionsV1Beta1Client) ListNoteRevisions(ctx context.Context, in *ListNoteRevisionsRequest, opts ...grpc.CallOption) (*ListNoteRevisionsResponse, error) {
+	out := new(ListNoteRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/grafeas.v1beta1.revision.GrafeasRevisionsV1Beta1/ListNoteRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grafeasRevisionsV1Beta1Client) ListOccurrenceRevisions(ctx context.Context, in *ListOccurrenceRevisionsRequest, opts ...grpc.CallOption) (*ListOccurrenceRevisionsResponse, error) {
+	out := new(ListOccurrenceRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/grafeas.v1beta1.revision.GrafeasRevisionsV1Beta1/ListOccurrenceRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GrafeasRevisionsV1Beta1Server is the server API for GrafeasRevisionsV1Beta1 service.
+type GrafeasRevisionsV1Beta1Server interface {
+	// Lists the previous revisions of the specified note, newest first.
+	//
+	// The caller needs the `notes.get` permission on the note.
+	ListNoteRevisions(context.Context, *ListNoteRevisionsRequest) (*ListNoteRevisionsResponse, error)
+	// Lists the previous revisions of the specified occurrence, newest first.
+	//
+	// The caller needs the `occurrences.get` permission in the occurrence's
+	// project.
+	ListOccurrenceRevisions(context.Context, *ListOccurrenceRevisionsRequest) (*ListOccurrenceRevisionsResponse, error)
+}
+
+func RegisterGrafeasRevisionsV1Beta1Server(s *grpc.Server, srv GrafeasRevisionsV1Beta1Server) {
+	s.RegisterService(&_GrafeasRevisionsV1Beta1_serviceDesc, srv)
+}
+
+func _GrafeasRevisionsV1Beta1_ListNoteRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNoteRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrafeasRevisionsV1Beta1Server).ListNoteRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grafeas.v1beta1.revision.GrafeasRevisionsV1Beta1/ListNoteRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrafeasRevisionsV1Beta1Server).ListNoteRevisions(ctx, req.(*ListNoteRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrafeasRevisionsV1Beta1_ListOccurrenceRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOccurrenceRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrafeasRevisionsV1Beta1Server).ListOccurrenceRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grafeas.v1beta1.revision.GrafeasRevisionsV1Beta1/ListOccurrenceRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrafeasRevisionsV1Beta1Server).ListOccurrenceRevisions(ctx, req.(*ListOccurrenceRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GrafeasRevisionsV1Beta1_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grafeas.v1beta1.revision.GrafeasRevisionsV1Beta1",
+	HandlerType: (*GrafeasRevisionsV1Beta1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListNoteRevisions",
+			Handler:    _GrafeasRevisionsV1Beta1_ListNoteRevisions_Handler,
+		},
+		{
+			MethodName: "ListOccurrenceRevisions",
+			Handler:    _GrafeasRevisionsV1Beta1_ListOccurrenceRevisions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/v1beta1/revision.proto",
+}
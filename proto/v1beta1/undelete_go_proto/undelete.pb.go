@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proto/v1beta1/undelete.proto
+
+package undelete_go_proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Request to restore a deleted project or note.
+type UndeleteRequest struct {
+	// The name of the project in the form of `projects/[PROJECT_ID]` or of the
+	// note in the form of `projects/[PROVIDER_ID]/notes/[NOTE_ID]`.
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UndeleteRequest) Reset()         { *m = UndeleteRequest{} }
+func (m *UndeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*UndeleteRequest) ProtoMessage()    {}
+func (*UndeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8dc6bd4257bf3ba0, []int{0}
+}
+
+func (m *UndeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndeleteRequest.Unmarshal(m, b)
+}
+func (m *UndeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *UndeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndeleteRequest.Merge(m, src)
+}
+func (m *UndeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_UndeleteRequest.Size(m)
+}
+func (m *UndeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UndeleteRequest proto.InternalMessageInfo
+
+func (m *UndeleteRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*UndeleteRequest)(nil), "grafeas.v1beta1.undelete.UndeleteRequest")
+}
+
+func init() { proto.RegisterFile("proto/v1beta1/undelete.proto", fileDescriptor_8dc6bd4257bf3ba0) }
+
+var fileDescriptor_8dc6bd4257bf3ba0 = []byte{
+	// 203 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x29, 0x28, 0xca, 0x2f,
+	0xc9, 0xd7, 0x2f, 0x33, 0x4c, 0x4a, 0x2d, 0x49, 0x34, 0xd4, 0x2f, 0xcd, 0x4b, 0x49, 0xcd, 0x49,
+	0x2d, 0x49, 0xd5, 0x03, 0x0b, 0x0b, 0x49, 0xa4, 0x17, 0x25, 0xa6, 0xa5, 0x26, 0x16, 0xeb, 0x41,
+	0xe5, 0xf5, 0x60, 0xf2, 0x52, 0xd2, 0xe9, 0xf9, 0xf9, 0xe9, 0x39, 0xa9, 0xfa, 0x60, 0x75, 0x49,
+	0xa5, 0x69, 0xfa, 0xa9, 0xb9, 0x05, 0x25, 0x95, 0x10, 0x6d, 0x4a, 0xaa, 0x5c, 0xfc, 0xa1, 0x50,
+	0x85, 0x41, 0xa9, 0x85, 0xa5, 0xa9, 0xc5, 0x25, 0x42, 0x42, 0x5c, 0x2c, 0x79, 0x89, 0xb9, 0xa9,
+	0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x60, 0xb6, 0x51, 0x3a, 0x97, 0x98, 0x3b, 0xc4, 0x7c,
+	0x98, 0xea, 0x30, 0x43, 0x27, 0x90, 0x35, 0x42, 0xbe, 0x5c, 0x1c, 0x30, 0x21, 0x21, 0x4d, 0x3d,
+	0x5c, 0x8e, 0xd0, 0x43, 0xb3, 0x44, 0x4a, 0x4c, 0x0f, 0xe2, 0x2a, 0x3d, 0x98, 0xab, 0xf4, 0x5c,
+	0x41, 0xae, 0x72, 0x4a, 0xe4, 0x92, 0xce, 0xcc, 0xc7, 0x69, 0x4c, 0x00, 0x63, 0x94, 0x55, 0x7a,
+	0x66, 0x49, 0x46, 0x69, 0x92, 0x5e, 0x72, 0x7e, 0xae, 0x3e, 0x54, 0x19, 0x9c, 0xc6, 0x1e, 0x40,
+	0xf1, 0xe9, 0xf9, 0xf1, 0x60, 0x99, 0x45, 0x4c, 0xcc, 0xee, 0x41, 0x8e, 0x49, 0x6c, 0x60, 0x8e,
+	0x31, 0x60, 0x00, 0x78, 0x85, 0x0f, 0x3b, 0x50, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GrafeasUndeleteV1Beta1Client is the client API for GrafeasUndeleteV1Beta1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GrafeasUndeleteV1Beta1Client interface {
+	// Restores the specified project or note if it was deleted within the
+	// retention window.
+	//
+	// The caller needs the `projects.undelete` permission on the project or the
+	// `notes.undelete` permission on the note.
+	Undelete(ctx context.Context, in *UndeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+}
+
+type grafeasUndeleteV1Beta1Client struct {
+	cc *grpc.ClientConn
+}
+
+func NewGrafeasUndeleteV1Beta1Client(cc *grpc.ClientConn) GrafeasUndeleteV1Beta1Client {
+	return &grafeasUndeleteV1Beta1Client{cc}
+}
+
+func (c *grafeasUndeleteV1Beta1Client) Undelete(ctx context.Context, in *UndeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/grafeas.v1beta1.undelete.GrafeasUndeleteV1Beta1/Undelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GrafeasUndeleteV1Beta1Server is the server API for GrafeasUndeleteV1Beta1 service.
+type GrafeasUndeleteV1Beta1Server interface {
+	// Restores the specified project or note if it was deleted within the
+	// retention window.
+	//
+	// The caller needs the `projects.undelete` permission on the project or the
+	// `notes.undelete` permission on the note.
+	Undelete(context.Context, *UndeleteRequest) (*empty.Empty, error)
+}
+
+func RegisterGrafeasUndeleteV1Beta1Server(s *grpc.Server, srv GrafeasUndeleteV1Beta1Server) {
+	s.RegisterService(&_GrafeasUndeleteV1Beta1_serviceDesc, srv)
+}
+
+func _GrafeasUndeleteV1Beta1_Undelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrafeasUndeleteV1Beta1Server).Undelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grafeas.v1beta1.undelete.GrafeasUndeleteV1Beta1/Undelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrafeasUndeleteV1Beta1Server).Undelete(ctx, req.(*UndeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GrafeasUndeleteV1Beta1_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grafeas.v1beta1.undelete.GrafeasUndeleteV1Beta1",
+	HandlerType: (*GrafeasUndeleteV1Beta1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Undelete",
+			Handler:    _GrafeasUndeleteV1Beta1_Undelete_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/v1beta1/undelete.proto",
+}
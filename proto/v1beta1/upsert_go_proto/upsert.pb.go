@@ -0,0 +1,316 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proto/v1beta1/upsert.proto
+
+package upsert_go_proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grafeas_go_proto "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Request to upsert an occurrence.
+type UpsertOccurrenceRequest struct {
+	// The name of the project in the form of `projects/[PROJECT_ID]`, under
+	// which the occurrence is to be upserted.
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The occurrence to upsert.
+	Occurrence           *grafeas_go_proto.Occurrence `protobuf:"bytes,2,opt,name=occurrence,proto3" json:"occurrence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *UpsertOccurrenceRequest) Reset()         { *m = UpsertOccurrenceRequest{} }
+func (m *UpsertOccurrenceRequest) String() string { return proto.CompactTextString(m) }
+func (*UpsertOccurrenceRequest) ProtoMessage()    {}
+func (*UpsertOccurrenceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26c2056a70a564de, []int{0}
+}
+
+func (m *UpsertOccurrenceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpsertOccurrenceRequest.Unmarshal(m, b)
+}
+func (m *UpsertOccurrenceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpsertOccurrenceRequest.Marshal(b, m, deterministic)
+}
+func (m *UpsertOccurrenceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpsertOccurrenceRequest.Merge(m, src)
+}
+func (m *UpsertOccurrenceRequest) XXX_Size() int {
+	return xxx_messageInfo_UpsertOccurrenceRequest.Size(m)
+}
+func (m *UpsertOccurrenceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpsertOccurrenceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpsertOccurrenceRequest proto.InternalMessageInfo
+
+func (m *UpsertOccurrenceRequest) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *UpsertOccurrenceRequest) GetOccurrence() *grafeas_go_proto.Occurrence {
+	if m != nil {
+		return m.Occurrence
+	}
+	return nil
+}
+
+// Request to upsert occurrences in batch.
+type BatchUpsertOccurrencesRequest struct {
+	// The name of the project in the form of `projects/[PROJECT_ID]`, under
+	// which the occurrences are to be upserted.
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The occurrences to upsert.
+	Occurrences          []*grafeas_go_proto.Occurrence `protobuf:"bytes,2,rep,name=occurrences,proto3" json:"occurrences,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
+}
+
+func (m *BatchUpsertOccurrencesRequest) Reset()         { *m = BatchUpsertOccurrencesRequest{} }
+func (m *BatchUpsertOccurrencesRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchUpsertOccurrencesRequest) ProtoMessage()    {}
+func (*BatchUpsertOccurrencesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26c2056a70a564de, []int{1}
+}
+
+func (m *BatchUpsertOccurrencesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchUpsertOccurrencesRequest.Unmarshal(m, b)
+}
+func (m *BatchUpsertOccurrencesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchUpsertOccurrencesRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchUpsertOccurrencesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchUpsertOccurrencesRequest.Merge(m, src)
+}
+func (m *BatchUpsertOccurrencesRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchUpsertOccurrencesRequest.Size(m)
+}
+func (m *BatchUpsertOccurrencesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchUpsertOccurrencesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchUpsertOccurrencesRequest proto.InternalMessageInfo
+
+func (m *BatchUpsertOccurrencesRequest) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *BatchUpsertOccurrencesRequest) GetOccurrences() []*grafeas_go_proto.Occurrence {
+	if m != nil {
+		return m.Occurrences
+	}
+	return nil
+}
+
+// Response for upserting occurrences in batch.
+type BatchUpsertOccurrencesResponse struct {
+	// The occurrences that were created or updated.
+	Occurrences          []*grafeas_go_proto.Occurrence `protobuf:"bytes,1,rep,name=occurrences,proto3" json:"occurrences,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
+}
+
+func (m *BatchUpsertOccurrencesResponse) Reset()         { *m = BatchUpsertOccurrencesResponse{} }
+func (m *BatchUpsertOccurrencesResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchUpsertOccurrencesResponse) ProtoMessage()    {}
+func (*BatchUpsertOccurrencesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26c2056a70a564de, []int{2}
+}
+
+func (m *BatchUpsertOccurrencesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchUpsertOccurrencesResponse.Unmarshal(m, b)
+}
+func (m *BatchUpsertOccurrencesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchUpsertOccurrencesResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchUpsertOccurrencesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchUpsertOccurrencesResponse.Merge(m, src)
+}
+func (m *BatchUpsertOccurrencesResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchUpsertOccurrencesResponse.Size(m)
+}
+func (m *BatchUpsertOccurrencesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchUpsertOccurrencesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchUpsertOccurrencesResponse proto.InternalMessageInfo
+
+func (m *BatchUpsertOccurrencesResponse) GetOccurrences() []*grafeas_go_proto.Occurrence {
+	if m != nil {
+		return m.Occurrences
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*UpsertOccurrenceRequest)(nil), "grafeas.v1beta1.upsert.UpsertOccurrenceRequest")
+	proto.RegisterType((*BatchUpsertOccurrencesRequest)(nil), "grafeas.v1beta1.upsert.BatchUpsertOccurrencesRequest")
+	proto.RegisterType((*BatchUpsertOccurrencesResponse)(nil), "grafeas.v1beta1.upsert.BatchUpsertOccurrencesResponse")
+}
+
+func init() { proto.RegisterFile("proto/v1beta1/upsert.proto", fileDescriptor_26c2056a70a564de) }
+
+var fileDescriptor_26c2056a70a564de = []byte{
+	// 283 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x2a, 0x28, 0xca, 0x2f,
+	0xc9, 0xd7, 0x2f, 0x33, 0x4c, 0x4a, 0x2d, 0x49, 0x34, 0xd4, 0x2f, 0x2d, 0x28, 0x4e, 0x2d, 0x2a,
+	0xd1, 0x03, 0x0b, 0x0a, 0x89, 0xa5, 0x17, 0x25, 0xa6, 0xa5, 0x26, 0x16, 0xeb, 0x41, 0x65, 0xf5,
+	0x20, 0xb2, 0x52, 0xd2, 0xa8, 0x7a, 0x60, 0xaa, 0xc0, 0xa2, 0x4a, 0x79, 0x5c, 0xe2, 0xa1, 0x60,
+	0x65, 0xfe, 0xc9, 0xc9, 0xa5, 0x45, 0x45, 0xa9, 0x79, 0xc9, 0xa9, 0x41, 0xa9, 0x85, 0xa5, 0xa9,
+	0xc5, 0x25, 0x42, 0x62, 0x5c, 0x6c, 0x05, 0x89, 0x45, 0xa9, 0x79, 0x25, 0x12, 0x8c, 0x0a, 0x8c,
+	0x1a, 0x9c, 0x41, 0x50, 0x9e, 0x90, 0x35, 0x17, 0x57, 0x3e, 0x5c, 0xb1, 0x04, 0x93, 0x02, 0xa3,
+	0x06, 0xb7, 0x91, 0xb4, 0x1e, 0xba, 0xe5, 0x48, 0xe6, 0x21, 0x29, 0x57, 0x2a, 0xe3, 0x92, 0x75,
+	0x4a, 0x2c, 0x49, 0xce, 0x40, 0xb7, 0xb4, 0x98, 0x90, 0xad, 0xb6, 0x5c, 0xdc, 0x08, 0x63, 0x8a,
+	0x25, 0x98, 0x14, 0x98, 0x09, 0x59, 0x8b, 0xac, 0x5e, 0x29, 0x9e, 0x4b, 0x0e, 0x97, 0xbd, 0xc5,
+	0x05, 0xf9, 0x79, 0xc5, 0xa9, 0xe8, 0x16, 0x30, 0x92, 0x66, 0x81, 0x51, 0x13, 0x13, 0x97, 0x88,
+	0x3b, 0x44, 0x2d, 0xc4, 0x8e, 0x30, 0x43, 0x27, 0x90, 0x06, 0xa1, 0x04, 0x2e, 0x01, 0x74, 0x4b,
+	0x85, 0xf4, 0xf5, 0xb0, 0xc7, 0x95, 0x1e, 0x8e, 0xb8, 0x90, 0xc2, 0xe7, 0x0e, 0xa1, 0x76, 0x46,
+	0x2e, 0x31, 0xec, 0x9e, 0x13, 0x32, 0xc5, 0x65, 0x11, 0xde, 0x48, 0x90, 0x32, 0x23, 0x55, 0x1b,
+	0x24, 0x0c, 0x9d, 0x62, 0xb9, 0x24, 0x33, 0xf3, 0x71, 0xe8, 0x0d, 0x60, 0x8c, 0xb2, 0x48, 0xcf,
+	0x2c, 0xc9, 0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0x85, 0x25, 0x43, 0x38, 0x8d, 0x2d, 0x61, 0xc7,
+	0xa7, 0xe7, 0xc7, 0x83, 0xc5, 0x17, 0x31, 0x31, 0xbb, 0x07, 0x39, 0x26, 0xb1, 0x81, 0x39, 0xc6,
+	0x80, 0x01, 0x00, 0xbb, 0x43, 0x16, 0x02, 0x06, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GrafeasUpsertV1Beta1Client is the client API for GrafeasUpsertV1Beta1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GrafeasUpsertV1Beta1Client interface {
+	// Updates the occurrence in the project for the same resource URI and note
+	// as the specified one in place, or creates the occurrence if there is none.
+	//
+	// The caller needs the `occurrences.create` and `occurrences.update`
+	// permissions in the project and the `notes.attachOccurrence` permission on
+	// the note.
+	UpsertOccurrence(ctx context.Context, in *UpsertOccurrenceRequest, opts ...grpc.CallOption) (*grafeas_go_proto.Occurrence, error)
+	// Upserts the specified occurrences, like `UpsertOccurrence` does one.
+	BatchUpsertOccurrences(ctx context.Context, in *BatchUpsertOccurrencesRequest, opts ...grpc.CallOption) (*BatchUpsertOccurrencesResponse, error)
+}
+
+type grafeasUpsertV1Beta1Client struct {
+	cc *grpc.ClientConn
+}
+
+func NewGrafeasUpsertV1Beta1Client(cc *grpc.ClientConn) GrafeasUpsertV1Beta1Client {
+	return &grafeasUpsertV1Beta1Client{cc}
+}
+
+func (c *grafeasUpsertV1Beta1Client) UpsertOccurrence(ctx context.Context, in *UpsertOccurrenceRequest, opts ...grpc.CallOption) (*grafeas_go_proto.Occurrence, error) {
+	out := new(grafeas_go_proto.Occurrence)
+	err := c.cc.Invoke(ctx, "/grafeas.v1beta1.upsert.GrafeasUpsertV1Beta1/UpsertOccurrence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grafeasUpsertV1Beta1Client) BatchUpsertOccurrences(ctx context.Context, in *BatchUpsertOccurrencesRequest, opts ...grpc.CallOption) (*BatchUpsertOccurrencesResponse, error) {
+	out := new(BatchUpsertOccurrencesResponse)
+	err := c.cc.Invoke(ctx, "/grafeas.v1beta1.upsert.GrafeasUpsertV1Beta1/BatchUpsertOccurrences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GrafeasUpsertV1Beta1Server is the server API for GrafeasUpsertV1Beta1 service.
+type GrafeasUpsertV1Beta1Server interface {
+	// Updates the occurrence in the project for the same resource URI and note
+	// as the specified one in place, or creates the occurrence if there is none.
+	//
+	// The caller needs the `occurrences.create` and `occurrences.update`
+	// permissions in the project and the `notes.attachOccurrence` permission on
+	// the note.
+	UpsertOccurrence(context.Context, *UpsertOccurrenceRequest) (*grafeas_go_proto.Occurrence, error)
+	// Upserts the specified occurrences, like `UpsertOccurrence` does one.
+	BatchUpsertOccurrences(context.Context, *BatchUpsertOccurrencesRequest) (*BatchUpsertOccurrencesResponse, error)
+}
+
+func RegisterGrafeasUpsertV1Beta1Server(s *grpc.Server, srv GrafeasUpsertV1Beta1Server) {
+	s.RegisterService(&_GrafeasUpsertV1Beta1_serviceDesc, srv)
+}
+
+func _GrafeasUpsertV1Beta1_UpsertOccurrence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertOccurrenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrafeasUpsertV1Beta1Server).UpsertOccurrence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grafeas.v1beta1.upsert.GrafeasUpsertV1Beta1/UpsertOccurrence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrafeasUpsertV1Beta1Server).UpsertOccurrence(ctx, req.(*UpsertOccurrenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrafeasUpsertV1Beta1_BatchUpsertOccurrences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpsertOccurrencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrafeasUpsertV1Beta1Server).BatchUpsertOccurrences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grafeas.v1beta1.upsert.GrafeasUpsertV1Beta1/BatchUpsertOccurrences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrafeasUpsertV1Beta1Server).BatchUpsertOccurrences(ctx, req.(*BatchUpsertOccurrencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GrafeasUpsertV1Beta1_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grafeas.v1beta1.upsert.GrafeasUpsertV1Beta1",
+	HandlerType: (*GrafeasUpsertV1Beta1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UpsertOccurrence",
+			Handler:    _GrafeasUpsertV1Beta1_UpsertOccurrence_Handler,
+		},
+		{
+			MethodName: "BatchUpsertOccurrences",
+			Handler:    _GrafeasUpsertV1Beta1_BatchUpsertOccurrences_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/v1beta1/upsert.proto",
+}
@@ -0,0 +1,332 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proto/v1beta1/watch.proto
+
+package watch_go_proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grafeas_go_proto "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// The kind of change.
+type OccurrenceEvent_Type int32
+
+const (
+	// Unknown.
+	OccurrenceEvent_TYPE_UNSPECIFIED OccurrenceEvent_Type = 0
+	// The occurrence was created.
+	OccurrenceEvent_CREATED OccurrenceEvent_Type = 1
+	// The occurrence was updated.
+	OccurrenceEvent_UPDATED OccurrenceEvent_Type = 2
+	// The occurrence was deleted.
+	OccurrenceEvent_DELETED OccurrenceEvent_Type = 3
+)
+
+var OccurrenceEvent_Type_name = map[int32]string{
+	0: "TYPE_UNSPECIFIED",
+	1: "CREATED",
+	2: "UPDATED",
+	3: "DELETED",
+}
+
+var OccurrenceEvent_Type_value = map[string]int32{
+	"TYPE_UNSPECIFIED": 0,
+	"CREATED":          1,
+	"UPDATED":          2,
+	"DELETED":          3,
+}
+
+func (x OccurrenceEvent_Type) String() string {
+	return proto.EnumName(OccurrenceEvent_Type_name, int32(x))
+}
+
+func (OccurrenceEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_cf3e09d0be00ff23, []int{1, 0}
+}
+
+// Request to watch occurrences.
+type WatchOccurrencesRequest struct {
+	// The name of the project to watch occurrences in, in the form of
+	// `projects/[PROJECT_ID]`.
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The filter expression. Only events for occurrences matching it are
+	// streamed, deletions are matched against the deleted occurrence.
+	Filter string `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	// The cursor of the last event the caller received, to resume watching
+	// after it. If empty, only changes made after the call are streamed.
+	Cursor               string   `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchOccurrencesRequest) Reset()         { *m = WatchOccurrencesRequest{} }
+func (m *WatchOccurrencesRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchOccurrencesRequest) ProtoMessage()    {}
+func (*WatchOccurrencesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cf3e09d0be00ff23, []int{0}
+}
+
+func (m *WatchOccurrencesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchOccurrencesRequest.Unmarshal(m, b)
+}
+func (m *WatchOccurrencesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchOccurrencesRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchOccurrencesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchOccurrencesRequest.Merge(m, src)
+}
+func (m *WatchOccurrencesRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchOccurrencesRequest.Size(m)
+}
+func (m *WatchOccurrencesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchOccurrencesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchOccurrencesRequest proto.InternalMessageInfo
+
+func (m *WatchOccurrencesRequest) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *WatchOccurrencesRequest) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+func (m *WatchOccurrencesRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+// A change to an occurrence.
+type OccurrenceEvent struct {
+	// The kind of change.
+	Type OccurrenceEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=grafeas.v1beta1.watch.OccurrenceEvent_Type" json:"type,omitempty"`
+	// The occurrence after the change, or as it was when it was deleted.
+	Occurrence *grafeas_go_proto.Occurrence `protobuf:"bytes,2,opt,name=occurrence,proto3" json:"occurrence,omitempty"`
+	// Output only. The time the change was made.
+	EventTime *timestamp.Timestamp `protobuf:"bytes,3,opt,name=event_time,json=eventTime,proto3" json:"event_time,omitempty"`
+	// Output only. Opaque cursor identifying this event, pass it in
+	// `WatchOccurrencesRequest.cursor` to resume watching after it.
+	Cursor               string   `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OccurrenceEvent) Reset()         { *m = OccurrenceEvent{} }
+func (m *OccurrenceEvent) String() string { return proto.CompactTextString(m) }
+func (*OccurrenceEvent) ProtoMessage()    {}
+func (*OccurrenceEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cf3e09d0be00ff23, []int{1}
+}
+
+func (m *OccurrenceEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OccurrenceEvent.Unmarshal(m, b)
+}
+func (m *OccurrenceEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OccurrenceEvent.Marshal(b, m, deterministic)
+}
+func (m *OccurrenceEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OccurrenceEvent.Merge(m, src)
+}
+func (m *OccurrenceEvent) XXX_Size() int {
+	return xxx_messageInfo_OccurrenceEvent.Size(m)
+}
+func (m *OccurrenceEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_OccurrenceEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OccurrenceEvent proto.InternalMessageInfo
+
+func (m *OccurrenceEvent) GetType() OccurrenceEvent_Type {
+	if m != nil {
+		return m.Type
+	}
+	return OccurrenceEvent_TYPE_UNSPECIFIED
+}
+
+func (m *OccurrenceEvent) GetOccurrence() *grafeas_go_proto.Occurrence {
+	if m != nil {
+		return m.Occurrence
+	}
+	return nil
+}
+
+func (m *OccurrenceEvent) GetEventTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EventTime
+	}
+	return nil
+}
+
+func (m *OccurrenceEvent) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("grafeas.v1beta1.watch.OccurrenceEvent_Type", OccurrenceEvent_Type_name, OccurrenceEvent_Type_value)
+	proto.RegisterType((*WatchOccurrencesRequest)(nil), "grafeas.v1beta1.watch.WatchOccurrencesRequest")
+	proto.RegisterType((*OccurrenceEvent)(nil), "grafeas.v1beta1.watch.OccurrenceEvent")
+}
+
+func init() { proto.RegisterFile("proto/v1beta1/watch.proto", fileDescriptor_cf3e09d0be00ff23) }
+
+var fileDescriptor_cf3e09d0be00ff23 = []byte{
+	// 392 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x4d, 0x6b, 0xdb, 0x40,
+	0x10, 0xad, 0x64, 0xe3, 0xe2, 0x35, 0xb4, 0x62, 0xfb, 0xa5, 0xca, 0x87, 0x16, 0x1f, 0x4a, 0xa1,
+	0xb0, 0xaa, 0xd5, 0x43, 0x09, 0x39, 0x04, 0xdb, 0xda, 0x18, 0x43, 0x48, 0x84, 0x22, 0x27, 0x24,
+	0x39, 0x08, 0x49, 0xac, 0x65, 0x81, 0xed, 0x55, 0x56, 0x2b, 0x07, 0x9f, 0xf3, 0x4f, 0x72, 0xc9,
+	0xdf, 0x0c, 0xbb, 0xb2, 0xfc, 0x15, 0x1b, 0x72, 0x5a, 0xde, 0x9b, 0x99, 0xc7, 0xbc, 0x37, 0x0b,
+	0xbe, 0xa7, 0x8c, 0x72, 0x6a, 0xce, 0xdb, 0x21, 0xe1, 0x41, 0xdb, 0x7c, 0x08, 0x78, 0x34, 0x46,
+	0x92, 0x83, 0x5f, 0x62, 0x16, 0x8c, 0x48, 0x90, 0xa1, 0x65, 0x11, 0xc9, 0xa2, 0xf1, 0x23, 0xa6,
+	0x34, 0x9e, 0x10, 0x53, 0x36, 0x85, 0xf9, 0xc8, 0xe4, 0xc9, 0x94, 0x64, 0x3c, 0x98, 0xa6, 0xc5,
+	0x9c, 0xd1, 0xdc, 0x96, 0x2c, 0x55, 0x24, 0xdb, 0x0a, 0xc0, 0xb7, 0x6b, 0x21, 0x73, 0x11, 0x45,
+	0x39, 0x63, 0x64, 0x16, 0x91, 0xcc, 0x25, 0xf7, 0x39, 0xc9, 0x38, 0xfc, 0x0a, 0x6a, 0x69, 0xc0,
+	0xc8, 0x8c, 0xeb, 0xca, 0x4f, 0xe5, 0x77, 0xdd, 0x5d, 0x22, 0xc1, 0x8f, 0x92, 0x09, 0x27, 0x4c,
+	0x57, 0x0b, 0xbe, 0x40, 0x82, 0x8f, 0x72, 0x96, 0x51, 0xa6, 0x57, 0x0a, 0xbe, 0x40, 0xad, 0x67,
+	0x15, 0x7c, 0x5c, 0xcb, 0xe3, 0xb9, 0xd0, 0x38, 0x01, 0x55, 0xbe, 0x48, 0x89, 0x54, 0xfe, 0x60,
+	0xfd, 0x41, 0x7b, 0xad, 0xa1, 0x9d, 0x29, 0xe4, 0x2d, 0x52, 0xe2, 0xca, 0x41, 0x78, 0x0c, 0x00,
+	0x5d, 0x55, 0xe5, 0x22, 0x0d, 0xab, 0xf9, 0x4a, 0x66, 0x2d, 0xe0, 0x6e, 0xb4, 0xc3, 0x23, 0x00,
+	0x88, 0x10, 0xf4, 0x45, 0x54, 0x72, 0xdb, 0x86, 0x65, 0xa0, 0x22, 0x47, 0x54, 0xe6, 0x88, 0xbc,
+	0x32, 0x47, 0xb7, 0x2e, 0xbb, 0x05, 0xde, 0x30, 0x59, 0xdd, 0x32, 0xd9, 0x03, 0x55, 0xb1, 0x1d,
+	0xfc, 0x0c, 0x34, 0xef, 0xc6, 0xc1, 0xfe, 0xf0, 0xfc, 0xd2, 0xc1, 0xbd, 0xc1, 0xe9, 0x00, 0xdb,
+	0xda, 0x3b, 0xd8, 0x00, 0xef, 0x7b, 0x2e, 0xee, 0x78, 0xd8, 0xd6, 0x14, 0x01, 0x86, 0x8e, 0x2d,
+	0x81, 0x2a, 0x80, 0x8d, 0xcf, 0xb0, 0x00, 0x15, 0xeb, 0x51, 0x01, 0x9f, 0xfa, 0x85, 0x05, 0x79,
+	0x94, 0xab, 0x76, 0x57, 0xd8, 0x80, 0x13, 0xa0, 0xed, 0x1e, 0x09, 0xa2, 0x03, 0x99, 0x1d, 0xb8,
+	0xa6, 0xf1, 0xeb, 0x6d, 0x19, 0xff, 0x55, 0xba, 0x77, 0x40, 0x4f, 0xe8, 0xfe, 0x6e, 0x47, 0xb9,
+	0xfd, 0x1f, 0x27, 0x7c, 0x9c, 0x87, 0x28, 0xa2, 0xd3, 0xf2, 0x2b, 0xad, 0xde, 0x3d, 0x7f, 0xd7,
+	0x8f, 0xa9, 0x2f, 0xe9, 0x27, 0xb5, 0xd2, 0x77, 0x3b, 0x61, 0x4d, 0x82, 0x7f, 0x2f, 0x03, 0x00,
+	0xe5, 0x6c, 0xfa, 0xd1, 0xe8, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GrafeasWatchV1Beta1Client is the client API for GrafeasWatchV1Beta1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GrafeasWatchV1Beta1Client interface {
+	// Streams an event for every occurrence that is created, updated or deleted
+	// in the specified project, in the order the changes were made.
+	WatchOccurrences(ctx context.Context, in *WatchOccurrencesRequest, opts ...grpc.CallOption) (GrafeasWatchV1Beta1_WatchOccurrencesClient, error)
+}
+
+type grafeasWatchV1Beta1Client struct {
+	cc *grpc.ClientConn
+}
+
+func NewGrafeasWatchV1Beta1Client(cc *grpc.ClientConn) GrafeasWatchV1Beta1Client {
+	return &grafeasWatchV1Beta1Client{cc}
+}
+
+func (c *grafeasWatchV1Beta1Client) WatchOccurrences(ctx context.Context, in *WatchOccurrencesRequest, opts ...grpc.CallOption) (GrafeasWatchV1Beta1_WatchOccurrencesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GrafeasWatchV1Beta1_serviceDesc.Streams[0], "/grafeas.v1beta1.watch.GrafeasWatchV1Beta1/WatchOccurrences", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grafeasWatchV1Beta1WatchOccurrencesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GrafeasWatchV1Beta1_WatchOccurrencesClient interface {
+	Recv() (*OccurrenceEvent, error)
+	grpc.ClientStream
+}
+
+type grafeasWatchV1Beta1WatchOccurrencesClient struct {
+	grpc.ClientStream
+}
+
+func (x *grafeasWatchV1Beta1WatchOccurrencesClient) Recv() (*OccurrenceEvent, error) {
+	m := new(OccurrenceEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GrafeasWatchV1Beta1Server is the server API for GrafeasWatchV1Beta1 service.
+type GrafeasWatchV1Beta1Server interface {
+	// Streams an event for every occurrence that is created, updated or deleted
+	// in the specified project, in the order the changes were made.
+	WatchOccurrences(*WatchOccurrencesRequest, GrafeasWatchV1Beta1_WatchOccurrencesServer) error
+}
+
+func RegisterGrafeasWatchV1Beta1Server(s *grpc.Server, srv GrafeasWatchV1Beta1Server) {
+	s.RegisterService(&_GrafeasWatchV1Beta1_serviceDesc, srv)
+}
+
+func _GrafeasWatchV1Beta1_WatchOccurrences_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchOccurrencesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GrafeasWatchV1Beta1Server).WatchOccurrences(m, &grafeasWatchV1Beta1WatchOccurrencesServer{stream})
+}
+
+type GrafeasWatchV1Beta1_WatchOccurrencesServer interface {
+	Send(*OccurrenceEvent) error
+	grpc.ServerStream
+}
+
+type grafeasWatchV1Beta1WatchOccurrencesServer struct {
+	grpc.ServerStream
+}
+
+func (x *grafeasWatchV1Beta1WatchOccurrencesServer) Send(m *OccurrenceEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _GrafeasWatchV1Beta1_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grafeas.v1beta1.watch.GrafeasWatchV1Beta1",
+	HandlerType: (*GrafeasWatchV1Beta1Server)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchOccurrences",
+			Handler:       _GrafeasWatchV1Beta1_WatchOccurrences_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/v1beta1/watch.proto",
+}
@@ -24,27 +24,53 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
+	grafeasv1 "github.com/grafeas/grafeas/go/v1/api"
+	grafeas "github.com/grafeas/grafeas/go/v1beta1/api"
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
+	bulkpb "github.com/grafeas/grafeas/proto/v1beta1/bulk_go_proto"
 	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	iamsvcpb "github.com/grafeas/grafeas/proto/v1beta1/iam_go_proto"
 	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
+	udpb "github.com/grafeas/grafeas/proto/v1beta1/undelete_go_proto"
+	upsertpb "github.com/grafeas/grafeas/proto/v1beta1/upsert_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/auth"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/bridge"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/legacy"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/operations"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/v1alpha1"
 	server "github.com/grafeas/grafeas/server-go"
+	alphapb "github.com/grafeas/grafeas/v1alpha1/proto"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/rs/cors"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 )
 
 type Config struct {
-	Address            string   `yaml:"address"`              // Endpoint address, e.g. localhost:8080 or unix:///var/run/grafeas.sock
-	CertFile           string   `yaml:"certfile"`             // A PEM eoncoded certificate file
-	KeyFile            string   `yaml:"keyfile"`              // A PEM encoded private key file
-	CAFile             string   `yaml:"cafile"`               // A PEM eoncoded CA's certificate file
-	CORSAllowedOrigins []string `yaml:"cors_allowed_origins"` // Permitted CORS origins.
-	ServerName         string   `yaml:"server_name"`          // Server name to use in tls.Config
+	Address            string         `yaml:"address"`              // Endpoint address, e.g. localhost:8080 or unix:///var/run/grafeas.sock
+	CertFile           string         `yaml:"certfile"`             // A PEM eoncoded certificate file
+	KeyFile            string         `yaml:"keyfile"`              // A PEM encoded private key file
+	CAFile             string         `yaml:"cafile"`               // A PEM eoncoded CA's certificate file
+	CORSAllowedOrigins []string       `yaml:"cors_allowed_origins"` // Permitted CORS origins.
+	ServerName         string         `yaml:"server_name"`          // Server name to use in tls.Config
+	ValidatingAPI      bool           `yaml:"validating_api"`       // Serve the validating, auth-checking go/v1beta1/api implementation
+	V1Alpha1API        bool           `yaml:"v1alpha1_api"`         // Serve the deprecated v1alpha1 API on top of v1beta1
+	ImportDir          string         `yaml:"import_dir"`           // Directory of the files bulk imports may read
+	PolicyFile         string         `yaml:"policy_file"`          // Policy file authorizing the calls of the validating and v1 APIs
+	CertIdentity       string         `yaml:"cert_identity"`        // Client certificate field identifying callers: common_name, uri or email
+	JWT                auth.JWTConfig `yaml:"jwt"`                  // Validation of the bearer tokens identifying callers, if its JWKS is set
+	APIKeyFile         string         `yaml:"api_key_file"`         // File of the hashed API keys identifying callers, managed by cmd/apikeys
+	AuditEvents        string         `yaml:"audit_events"`         // Where the validating API records its mutations: storage, or a file path
+	DeleteRetention    time.Duration  `yaml:"delete_retention"`     // How long deleted projects and notes can be undeleted before they are purged
 }
 
 func networkAddresFromString(addr string) (string, string) {
@@ -59,8 +85,92 @@ func networkAddresFromString(addr string) (string, string) {
 	return network, address
 }
 
-// Run initializes grpc and grpc gateway api services on the same address
-func Run(config *Config, storage *server.Storager) {
+// Services are the gRPC services a server serves.
+type Services struct {
+	Grafeas  pb.GrafeasV1Beta1Server
+	Projects prpb.ProjectsServer
+	// Watch is optional, it has no REST endpoints.
+	Watch watchpb.GrafeasWatchV1Beta1Server
+	// Bulk is optional, it has no REST endpoints.
+	Bulk bulkpb.GrafeasBulkV1Beta1Server
+	// V1 is optional, it serves the v1 API next to v1beta1.
+	V1 v1pb.GrafeasServer
+	// V1Alpha1 and V1Alpha1Projects are optional, they serve the deprecated v1alpha1 API.
+	V1Alpha1         alphapb.GrafeasServer
+	V1Alpha1Projects alphapb.GrafeasProjectsServer
+	// Operations is optional, it has no REST endpoints.
+	Operations lrpb.OperationsServer
+	// IAM is optional, it has no REST endpoints.
+	IAM iamsvcpb.GrafeasIamV1Beta1Server
+	// Audit is optional, it has no REST endpoints.
+	Audit auditpb.GrafeasAuditV1Beta1Server
+	// Revisions is optional, it has no REST endpoints.
+	Revisions revpb.GrafeasRevisionsV1Beta1Server
+	// Undelete is optional, it has no REST endpoints.
+	Undelete udpb.GrafeasUndeleteV1Beta1Server
+	// Upsert is optional, it has no REST endpoints.
+	Upsert upsertpb.GrafeasUpsertV1Beta1Server
+	// Caller is optional, it identifies the callers of the calls recorded in the audit log. If nil,
+	// calls aren't audited.
+	Caller func(ctx context.Context) (string, error)
+	// JWT is optional, it authenticates the callers of gRPC and REST calls by their bearer tokens.
+	JWT *auth.JWTAuthenticator
+	// APIKeys is optional, it authenticates the callers of gRPC and REST calls by their API key.
+	APIKeys *auth.APIKeyAuthenticator
+}
+
+// Run initializes grpc and grpc gateway api services on the same address. a is the API built by
+// NewAPI over storage. The sample API checks no permissions, so the config must not set a policy
+// file.
+func Run(config *Config, storage *server.Storager, a *grafeas.API) {
+	if config.PolicyFile != "" {
+		log.Fatal("Failed to configure API: policy_file requires validating_api")
+	}
+	g := &v1alpha1.Grafeas{S: *storage}
+	v1 := NewV1API(a)
+	services := &Services{
+		Grafeas:    g,
+		Projects:   g,
+		Watch:      g,
+		V1:         &grafeasv1.Server{API: v1},
+		Operations: &operations.Server{S: *storage, Auth: a.Auth},
+		Undelete:   &grafeas.Server{API: a},
+		Upsert:     &grafeas.Server{API: a},
+	}
+	go purgeDeleted(a)
+	setAuthentication(config, services, v1.Auth.EndUserID)
+	if config.V1Alpha1API {
+		alpha := &legacy.Server{Grafeas: g, Projects: g, Operations: bridge.New(*storage)}
+		services.V1Alpha1, services.V1Alpha1Projects = alpha, alpha
+	}
+	Serve(config, services)
+}
+
+// setAuthentication sets the authentication of callers the config enables on the services, and the
+// audit of their calls by the caller IDs endUserID returns if callers are identified.
+func setAuthentication(config *Config, services *Services, endUserID func(context.Context) (string, error)) {
+	if config.JWT.JWKS != "" {
+		a, err := auth.NewJWTAuthenticator(config.JWT)
+		if err != nil {
+			log.Fatalf("Failed to configure bearer token authentication: %s", err)
+		}
+		services.JWT = a
+	}
+	if config.APIKeyFile != "" {
+		a, err := auth.NewAPIKeyAuthenticator(config.APIKeyFile)
+		if err != nil {
+			log.Fatalf("Failed to configure API key authentication: %s", err)
+		}
+		services.APIKeys = a
+	}
+	if config.CertIdentity != "" || services.JWT != nil || services.APIKeys != nil {
+		services.Caller = endUserID
+	}
+}
+
+// Serve initializes grpc and grpc gateway api services for the specified services on the same
+// address
+func Serve(config *Config, services *Services) {
 	network, address := networkAddresFromString(config.Address)
 
 	conn, err := net.Listen(network, address)
@@ -87,10 +197,14 @@ func Run(config *Config, storage *server.Storager) {
 	dialOptions := getDialOptions(tlsConfig)
 	serverOptions := getServerOptions(tlsConfig)
 
-	grpcServer = newGrpcServer(storage, serverOptions...)
-	restMux, _ = newRestMux(ctx, address, dialOptions...)
+	grpcServer = newGrpcServer(services, serverOptions...)
+	restMux, _ = newRestMux(ctx, services, address, dialOptions...)
 
-	httpMux.Handle("/", restMux)
+	if services.JWT != nil {
+		httpMux.Handle("/", services.JWT.HTTPHandler(restMux))
+	} else {
+		httpMux.Handle("/", restMux)
+	}
 
 	mergeHandler := grpcHandlerFunc(grpcServer, httpMux)
 
@@ -152,14 +266,25 @@ func handleShutdown(err error) {
 	}
 }
 
-func newRestMux(ctx context.Context, serverAddress string, opts ...grpc.DialOption) (*runtime.ServeMux, error) {
+func newRestMux(ctx context.Context, services *Services, serverAddress string, opts ...grpc.DialOption) (*runtime.ServeMux, error) {
 
 	// Because we run our REST endpoint on the same port as the GRPC the address is the same.
 	upstreamGRPCServerAddress := serverAddress
 
 	// Which multiplexer to register on.
 	gwmux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard,
-		&runtime.JSONPb{OrigName: true, EmitDefaults: true}))
+		&runtime.JSONPb{OrigName: true, EmitDefaults: true}),
+		// Forward the client certificates of REST callers, and only those, and their API keys.
+		runtime.WithMetadata(auth.ForwardCert),
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			switch {
+			case strings.EqualFold(key, runtime.MetadataHeaderPrefix+auth.ForwardedCertKey):
+				return "", false
+			case strings.EqualFold(key, auth.APIKeyHeader):
+				return auth.APIKeyHeader, true
+			}
+			return runtime.DefaultHeaderMatcher(key)
+		}))
 
 	err := pb.RegisterGrafeasV1Beta1HandlerFromEndpoint(ctx, gwmux, upstreamGRPCServerAddress, opts)
 	if err != nil {
@@ -173,24 +298,131 @@ func newRestMux(ctx context.Context, serverAddress string, opts ...grpc.DialOpti
 		return nil, err
 	}
 
+	if services.V1 != nil {
+		err = v1pb.RegisterGrafeasHandlerFromEndpoint(ctx, gwmux, upstreamGRPCServerAddress, opts)
+		if err != nil {
+			log.Fatal(err)
+			return nil, err
+		}
+	}
+
+	if services.V1Alpha1 != nil {
+		err = alphapb.RegisterGrafeasHandlerFromEndpoint(ctx, gwmux, upstreamGRPCServerAddress, opts)
+		if err != nil {
+			log.Fatal(err)
+			return nil, err
+		}
+	}
+
+	if services.V1Alpha1Projects != nil {
+		err = alphapb.RegisterGrafeasProjectsHandlerFromEndpoint(ctx, gwmux, upstreamGRPCServerAddress, opts)
+		if err != nil {
+			log.Fatal(err)
+			return nil, err
+		}
+	}
+
 	return gwmux, nil
 }
 
-func newGrpcServer(storage *server.Storager, opts ...grpc.ServerOption) *grpc.Server {
+func newGrpcServer(services *Services, opts ...grpc.ServerOption) *grpc.Server {
 	var grpcOpts []grpc.ServerOption
 
 	grpcOpts = append(grpcOpts, opts...)
 
+	// Callers are authenticated before their calls are audited.
+	var (
+		unary  []grpc.UnaryServerInterceptor
+		stream []grpc.StreamServerInterceptor
+	)
+	if services.JWT != nil {
+		unary = append(unary, services.JWT.UnaryInterceptor)
+		stream = append(stream, services.JWT.StreamInterceptor)
+	}
+	if services.APIKeys != nil {
+		unary = append(unary, services.APIKeys.UnaryInterceptor)
+		stream = append(stream, services.APIKeys.StreamInterceptor)
+	}
+	if services.Caller != nil {
+		unary = append(unary, auditUnary(services.Caller))
+		stream = append(stream, auditStream(services.Caller))
+	}
+	if len(unary) > 0 {
+		grpcOpts = append(grpcOpts,
+			grpc.UnaryInterceptor(chainUnary(unary)),
+			grpc.StreamInterceptor(chainStream(stream)))
+	}
+
 	grpcServer := grpc.NewServer(grpcOpts...)
-	g := v1alpha1.Grafeas{S: *storage}
-	pb.RegisterGrafeasV1Beta1Server(grpcServer, &g)
-	prpb.RegisterProjectsServer(grpcServer, &g)
+	pb.RegisterGrafeasV1Beta1Server(grpcServer, services.Grafeas)
+	prpb.RegisterProjectsServer(grpcServer, services.Projects)
+	if services.Watch != nil {
+		watchpb.RegisterGrafeasWatchV1Beta1Server(grpcServer, services.Watch)
+	}
+	if services.Bulk != nil {
+		bulkpb.RegisterGrafeasBulkV1Beta1Server(grpcServer, services.Bulk)
+	}
+	if services.V1 != nil {
+		v1pb.RegisterGrafeasServer(grpcServer, services.V1)
+	}
+	if services.V1Alpha1 != nil {
+		alphapb.RegisterGrafeasServer(grpcServer, services.V1Alpha1)
+	}
+	if services.V1Alpha1Projects != nil {
+		alphapb.RegisterGrafeasProjectsServer(grpcServer, services.V1Alpha1Projects)
+	}
+	if services.Operations != nil {
+		lrpb.RegisterOperationsServer(grpcServer, services.Operations)
+	}
+	if services.IAM != nil {
+		iamsvcpb.RegisterGrafeasIamV1Beta1Server(grpcServer, services.IAM)
+	}
+	if services.Audit != nil {
+		auditpb.RegisterGrafeasAuditV1Beta1Server(grpcServer, services.Audit)
+	}
+	if services.Revisions != nil {
+		revpb.RegisterGrafeasRevisionsV1Beta1Server(grpcServer, services.Revisions)
+	}
+	if services.Undelete != nil {
+		udpb.RegisterGrafeasUndeleteV1Beta1Server(grpcServer, services.Undelete)
+	}
+	if services.Upsert != nil {
+		upsertpb.RegisterGrafeasUpsertV1Beta1Server(grpcServer, services.Upsert)
+	}
 
 	reflection.Register(grpcServer)
 
 	return grpcServer
 }
 
+// chainUnary returns an interceptor calling the interceptors in order, as a gRPC server only takes
+// one.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			next, interceptor := handler, interceptors[i]
+			handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// chainStream returns an interceptor calling the interceptors in order, as a gRPC server only
+// takes one.
+func chainStream(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			next, interceptor := handler, interceptors[i]
+			handler = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, next)
+			}
+		}
+		return handler(srv, ss)
+	}
+}
+
 // grpcHandlerFunc returns an http.Handler that delegates to grpcServer on incoming gRPC
 // connections or otherHandler otherwise. Copied from cockroachdb.
 func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Handler {
@@ -0,0 +1,53 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// auditUnary returns an interceptor logging the caller, method and outcome of every unary call.
+func auditUnary(caller func(context.Context) (string, error)) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		audit(ctx, caller, info.FullMethod, err)
+		return resp, err
+	}
+}
+
+// auditStream returns an interceptor logging the caller, method and outcome of every streaming
+// call.
+func auditStream(caller func(context.Context) (string, error)) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		audit(ss.Context(), caller, info.FullMethod, err)
+		return err
+	}
+}
+
+func audit(ctx context.Context, caller func(context.Context) (string, error), method string, err error) {
+	id, idErr := caller(ctx)
+	switch {
+	case idErr != nil:
+		id = "unidentified caller (" + idErr.Error() + ")"
+	case id == "":
+		id = "anonymous caller"
+	}
+	log.Printf("AUDIT %s by %s: %s", method, id, status.Code(err))
+}
@@ -0,0 +1,306 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/grafeas/grafeas/go/filtering/eval"
+	"github.com/grafeas/grafeas/go/iam"
+	grafeasv1 "github.com/grafeas/grafeas/go/v1/api"
+	grafeas "github.com/grafeas/grafeas/go/v1beta1/api"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/auditlog"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/auth"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/bridge"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/legacy"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/operations"
+)
+
+const (
+	// defaultDeleteRetention is how long deleted projects and notes can be undeleted if the config
+	// doesn't say.
+	defaultDeleteRetention = 30 * 24 * time.Hour
+	// purgeInterval is how often the projects and notes deleted before their retention are purged.
+	purgeInterval = time.Hour
+)
+
+// NewAPI returns the validating, auth-checking v1beta1 API on top of the specified storage, with
+// filters validated by the eval package and logging to the standard logger. Callers are identified
+// by their bearer token if the config sets a JWKS or by their API key if it sets an API key file,
+// and otherwise by their client certificate if the config sets the certificate field identifying
+// them, and are anonymous otherwise. Calls are authorized by the config's policy file if it is set,
+// and by the IAM policies of projects and notes if s is a bridge to a server.Storager too, and
+// otherwise every call is allowed. Projects are stored in s if it implements
+// grafeas.ProjectStorage, occurrences can be upserted and watched if s implements grafeas.Upserts
+// and grafeas.Watch, and bulk methods store their long-running operations in s if it implements
+// grafeas.Operations. Bulk imports read files from the config's import directory.
+// Mutations are audited in s or in a file if the config's audit events say so. The previous
+// revisions of notes and occurrences are read from s if it is a bridge to a server.Storager, and
+// then deleted projects and notes can be undeleted for the config's delete retention too.
+func NewAPI(config *Config, s grafeas.Storage) (*grafeas.API, error) {
+	identity, err := callerIdentity(config)
+	if err != nil {
+		return nil, err
+	}
+	a := &grafeas.API{
+		Storage:           s,
+		Auth:              allowAll{identity},
+		Filter:            evalFilter{},
+		Logger:            stdLogger{},
+		EnforceValidation: true,
+		ImportDir:         config.ImportDir,
+	}
+	if ps, ok := s.(grafeas.ProjectStorage); ok {
+		a.Projects = ps
+	}
+	if us, ok := s.(grafeas.Upserts); ok {
+		a.Upserts = us
+	}
+	if ws, ok := s.(grafeas.Watch); ok {
+		a.Watch = ws
+	}
+	if ops, ok := s.(grafeas.Operations); ok {
+		a.Operations = ops
+	}
+	if config.PolicyFile != "" {
+		pa, err := auth.NewPolicyAuth(config.PolicyFile)
+		if err != nil {
+			return nil, err
+		}
+		pa.Identity = identity
+		pa.Groups = auth.CallerGroups
+		pa.Roles = auth.CallerRoles
+		a.Auth = pa
+		if b, ok := s.(*bridge.Storage); ok {
+			pa.Store = b.S
+			a.IAM = pa
+		}
+	}
+	if b, ok := s.(*bridge.Storage); ok {
+		a.Revisions = b
+		a.Tombstones = b
+		a.DeleteRetention = config.DeleteRetention
+		if a.DeleteRetention == 0 {
+			a.DeleteRetention = defaultDeleteRetention
+		}
+	}
+	switch config.AuditEvents {
+	case "":
+	case "storage":
+		b, ok := s.(*bridge.Storage)
+		if !ok {
+			return nil, fmt.Errorf("audit_events: storage requires a storage that can record them")
+		}
+		a.Audit = b
+	default:
+		f, err := auditlog.NewFile(config.AuditEvents)
+		if err != nil {
+			return nil, err
+		}
+		a.Audit = f
+	}
+	return a, nil
+}
+
+// NewV1API returns the v1 API serving the same storage as the specified v1beta1 API through version
+// conversion, with the same auth, filters, logging and validation.
+func NewV1API(a *grafeas.API) *grafeasv1.API {
+	return &grafeasv1.API{
+		Storage:           bridge.NewV1(a.Storage),
+		Auth:              a.Auth,
+		Filter:            a.Filter,
+		Logger:            a.Logger,
+		EnforceValidation: a.EnforceValidation,
+		// Policies are purged along with the notes that can be undeleted.
+		KeepDeletedNotePolicies: a.Tombstones != nil,
+	}
+}
+
+// RunAPI initializes grpc and grpc gateway api services serving the specified API, including its
+// projects, and its v1 counterpart on the same address, and the v1alpha1 API on top of them if the
+// config enables it. The operations of the API's storage are served too if it is a bridge to a
+// server.Storager, upserts and watches of occurrences if the API supports them, the IAM policy
+// methods if the API stores IAM policies, its audit events if it records them, the revisions of
+// notes and occurrences if it reads them, and undeletes if it keeps deleted projects and notes,
+// which are then purged periodically. Calls are logged with their caller if callers are identified.
+func RunAPI(config *Config, a *grafeas.API) {
+	s := &grafeas.Server{API: a}
+	v1 := &grafeasv1.Server{API: NewV1API(a)}
+	services := &Services{Grafeas: s, Projects: s, Bulk: s, V1: v1}
+	setAuthentication(config, services, a.Auth.EndUserID)
+	if a.Upserts != nil {
+		services.Upsert = s
+	}
+	if a.Watch != nil {
+		services.Watch = s
+	}
+	if a.IAM != nil {
+		services.IAM = s
+	}
+	if a.Audit != nil {
+		services.Audit = s
+	}
+	if a.Revisions != nil {
+		services.Revisions = s
+	}
+	if a.Tombstones != nil {
+		services.Undelete = s
+		go purgeDeleted(a)
+	}
+	if b, ok := a.Storage.(*bridge.Storage); ok {
+		services.Operations = &operations.Server{S: b.S, Auth: a.Auth}
+	}
+	if config.V1Alpha1API {
+		alpha := &legacy.Server{Grafeas: s, Projects: s, Operations: a.Operations}
+		services.V1Alpha1, services.V1Alpha1Projects = alpha, alpha
+	}
+	Serve(config, services)
+}
+
+// purgeDeleted purges the projects and notes deleted before the API's retention every purgeInterval,
+// starting now.
+func purgeDeleted(a *grafeas.API) {
+	for {
+		if err := a.PurgeDeleted(context.Background()); err != nil {
+			log.Printf("Failed to purge deleted projects and notes: %v", err)
+		}
+		time.Sleep(purgeInterval)
+	}
+}
+
+// callerIdentity returns how the config identifies callers, or nil if they are anonymous.
+func callerIdentity(config *Config) (func(context.Context) (string, error), error) {
+	cert, err := certIdentity(config)
+	if err != nil || (config.JWT.JWKS == "" && config.APIKeyFile == "") {
+		return cert, err
+	}
+	// Bearer tokens and API keys are validated by the interceptors of the server, which set the
+	// caller they identify on the context of the call.
+	return func(ctx context.Context) (string, error) {
+		if c, ok := auth.FromContext(ctx); ok {
+			return c.ID, nil
+		}
+		if cert == nil {
+			return "", nil
+		}
+		return cert(ctx)
+	}, nil
+}
+
+// certIdentity returns how the config identifies callers by their client certificate, or nil if it
+// doesn't.
+func certIdentity(config *Config) (func(context.Context) (string, error), error) {
+	if config.CertIdentity == "" {
+		return nil, nil
+	}
+	if config.CAFile == "" {
+		return nil, fmt.Errorf("cert_identity requires client certificates, which require a cafile")
+	}
+	// The REST gateway calls the gRPC services with the server certificate on behalf of its
+	// callers.
+	pemCert, err := ioutil.ReadFile(config.CertFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(pemCert)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM certificate in %q", config.CertFile)
+	}
+	proxy, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	c, err := auth.NewCertIdentity(config.CertIdentity, proxy)
+	if err != nil {
+		return nil, err
+	}
+	return c.Identity, nil
+}
+
+// allowAll is an auth that allows every call, by callers identified by identity, or anonymous ones
+// if it is nil.
+type allowAll struct {
+	identity func(context.Context) (string, error)
+}
+
+func (allowAll) CheckAccessAndProject(ctx context.Context, projectID string, entityID string, p iam.Permission) error {
+	return nil
+}
+
+func (a allowAll) EndUserID(ctx context.Context) (string, error) {
+	if a.identity == nil {
+		return "", nil
+	}
+	return a.identity(ctx)
+}
+
+func (allowAll) PurgePolicy(ctx context.Context, projectID string, entityID string, r iam.Resource) error {
+	return nil
+}
+
+// evalFilter validates filters by compiling them with the eval package.
+type evalFilter struct{}
+
+func (evalFilter) Validate(filter string) error {
+	_, err := eval.Compile(filter)
+	return err
+}
+
+type projectKey struct{}
+
+// stdLogger logs to the standard logger, prefixing messages with the project of the call.
+type stdLogger struct{}
+
+func (stdLogger) PrepareCtx(ctx context.Context, projectID string) context.Context {
+	return context.WithValue(ctx, projectKey{}, projectID)
+}
+
+func (l stdLogger) Info(ctx context.Context, args ...interface{}) {
+	l.output(ctx, "INFO", fmt.Sprint(args...))
+}
+
+func (l stdLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+	l.output(ctx, "INFO", fmt.Sprintf(format, args...))
+}
+
+func (l stdLogger) Warning(ctx context.Context, args ...interface{}) {
+	l.output(ctx, "WARNING", fmt.Sprint(args...))
+}
+
+func (l stdLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+	l.output(ctx, "WARNING", fmt.Sprintf(format, args...))
+}
+
+func (l stdLogger) Error(ctx context.Context, args ...interface{}) {
+	l.output(ctx, "ERROR", fmt.Sprint(args...))
+}
+
+func (l stdLogger) Errorf(ctx context.Context, format string, args ...interface{}) {
+	l.output(ctx, "ERROR", fmt.Sprintf(format, args...))
+}
+
+func (stdLogger) output(ctx context.Context, level, msg string) {
+	if pID, ok := ctx.Value(projectKey{}).(string); ok {
+		log.Printf("%s [%s] %s", level, pID, msg)
+		return
+	}
+	log.Printf("%s %s", level, msg)
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package archive exports the notes and occurrences of a project to a portable archive and imports
+// them back, into the same or another project.
+//
+// An archive is a gzipped tar file of a manifest.json file describing it, followed by the notes
+// and the occurrences of the project, in notes.jsonl and occurrences.jsonl for the JSON lines
+// format, or in notes.pb and occurrences.pb for the length-delimited format. These are the
+// encodings the bulk import methods of the v1beta1 API accept.
+package archive
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// Version is the version of the archive layout written by Export.
+const Version = 1
+
+// Format is the encoding of the notes and occurrences of an archive.
+type Format string
+
+const (
+	// JSONLines encodes one JSON object per line.
+	JSONLines Format = "json_lines"
+	// LengthDelimited encodes binary protos, each preceded by its length as a varint.
+	LengthDelimited Format = "length_delimited"
+)
+
+// maxRecordSize is the size in bytes of the largest note or occurrence an archive may hold.
+const maxRecordSize = 4 << 20
+
+const manifestFile = "manifest.json"
+
+// Manifest describes an archive.
+type Manifest struct {
+	// Version is the version of the archive layout.
+	Version int `json:"version"`
+	// Project is the ID of the exported project.
+	Project string `json:"project"`
+	// Format is the encoding of the notes and occurrences.
+	Format Format `json:"format"`
+	// CreateTime is when the archive was exported.
+	CreateTime time.Time `json:"create_time"`
+	// Notes and Occurrences count the notes and occurrences in the archive.
+	Notes       int `json:"notes"`
+	Occurrences int `json:"occurrences"`
+}
+
+// files returns the names of the notes and occurrences files of an archive in the format.
+func (f Format) files() (string, string, error) {
+	switch f {
+	case JSONLines:
+		return "notes.jsonl", "occurrences.jsonl", nil
+	case LengthDelimited:
+		return "notes.pb", "occurrences.pb", nil
+	}
+	return "", "", fmt.Errorf("unknown archive format %q", f)
+}
+
+// writeRecord writes m to w as a record in the format.
+func (f Format) writeRecord(w io.Writer, m proto.Message) error {
+	if f == JSONLines {
+		if err := (&jsonpb.Marshaler{}).Marshal(w, m); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	var size [binary.MaxVarintLen64]byte
+	if _, err := w.Write(size[:binary.PutUvarint(size[:], uint64(len(b)))]); err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// readRecord returns the next record of r in the format, or io.EOF if there are no more.
+func (f Format) readRecord(r *bufio.Reader) ([]byte, error) {
+	if f == JSONLines {
+		for {
+			line, err := r.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			if len(line) > 0 && string(line) != "\n" {
+				return line, nil
+			}
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+		}
+	}
+	size, err := binary.ReadUvarint(r)
+	switch {
+	case err == io.EOF:
+		return nil, io.EOF
+	case err != nil:
+		return nil, fmt.Errorf("failed to read record length: %v", err)
+	case size > maxRecordSize:
+		return nil, fmt.Errorf("record of %d bytes is larger than the max record size of %d", size, maxRecordSize)
+	}
+	rec := make([]byte, size)
+	if _, err := io.ReadFull(r, rec); err != nil {
+		return nil, fmt.Errorf("truncated record of %d bytes: %v", size, err)
+	}
+	return rec, nil
+}
+
+// unmarshalRecord unmarshals a record in the format into m.
+func (f Format) unmarshalRecord(rec []byte, m proto.Message) error {
+	if f == JSONLines {
+		return jsonpb.UnmarshalString(string(rec), m)
+	}
+	return proto.Unmarshal(rec, m)
+}
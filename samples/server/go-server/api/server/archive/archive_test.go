@@ -0,0 +1,186 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
+	server "github.com/grafeas/grafeas/server-go"
+)
+
+// newStaging returns a storage with a staging project holding two notes and two occurrences, one
+// of a note in another project, and a project whose ID starts with staging.
+func newStaging(t *testing.T) server.Storager {
+	t.Helper()
+	s := storage.NewMemStore()
+	for _, pID := range []string{"staging", "goog-vulnz", "staging2"} {
+		if err := s.CreateProject(pID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, n := range []*pb.Note{
+		{Name: "projects/staging/notes/build", ShortDescription: "build"},
+		{Name: "projects/staging/notes/attestor", RelatedNoteNames: []string{"projects/staging/notes/build", "projects/goog-vulnz/notes/CVE-1"}},
+		{Name: "projects/staging2/notes/other"},
+	} {
+		if err := s.CreateNote(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, o := range []*pb.Occurrence{
+		{Name: "projects/staging/occurrences/built", NoteName: "projects/staging/notes/build", Resource: &pb.Resource{Uri: "https://gcr.io/staging/app"}},
+		{Name: "projects/staging/occurrences/vuln", NoteName: "projects/goog-vulnz/notes/CVE-1", Resource: &pb.Resource{Uri: "https://gcr.io/staging/app"}},
+		{Name: "projects/staging2/occurrences/other", NoteName: "projects/staging2/notes/other"},
+	} {
+		if err := s.CreateOccurrence(o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestExportImport(t *testing.T) {
+	for _, format := range []Format{JSONLines, LengthDelimited} {
+		var buf bytes.Buffer
+		m, err := Export(newStaging(t), "staging", format, &buf)
+		if err != nil {
+			t.Fatalf("%s: Export got err %v, want success", format, err)
+		}
+		if m.Project != "staging" || m.Notes != 2 || m.Occurrences != 2 {
+			t.Errorf("%s: Export got manifest %+v, want 2 notes and 2 occurrences of staging", format, m)
+		}
+
+		dst := storage.NewMemStore()
+		var results []*Result
+		opts := &ImportOptions{Project: "test", NoteProjects: map[string]string{"goog-vulnz": "test-vulnz"}}
+		archive := buf.Bytes()
+		gotM, stats, err := Import(dst, bytes.NewReader(archive), opts, func(r *Result) { results = append(results, r) })
+		if err != nil {
+			t.Fatalf("%s: Import got err %v, want success", format, err)
+		}
+		if diff := cmp.Diff(m, gotM); diff != "" {
+			t.Errorf("%s: Import returned manifest diff (-want +got):\n%s", format, diff)
+		}
+		if diff := cmp.Diff(&Stats{Notes: 2, Occurrences: 2}, stats); diff != "" {
+			t.Errorf("%s: Import returned diff (-want +got):\n%s", format, diff)
+		}
+		if len(results) != 0 {
+			t.Errorf("%s: Import reported %v, want no failures", format, results)
+		}
+
+		n, err := dst.GetNote("test", "attestor")
+		if err != nil {
+			t.Fatalf("%s: GetNote got err %v, want success", format, err)
+		}
+		if want := []string{"projects/test/notes/build", "projects/test-vulnz/notes/CVE-1"}; !cmp.Equal(n.RelatedNoteNames, want) {
+			t.Errorf("%s: got related notes %v, want %v", format, n.RelatedNoteNames, want)
+		}
+		for oID, want := range map[string]string{"built": "projects/test/notes/build", "vuln": "projects/test-vulnz/notes/CVE-1"} {
+			o, err := dst.GetOccurrence("test", oID)
+			if err != nil {
+				t.Fatalf("%s: GetOccurrence(%q) got err %v, want success", format, oID, err)
+			}
+			if o.NoteName != want || o.Resource.GetUri() != "https://gcr.io/staging/app" {
+				t.Errorf("%s: got occurrence %+v, want note %q", format, o, want)
+			}
+		}
+
+		// Importing again fails every entity, unless existing ones are skipped.
+		_, stats, err = Import(dst, bytes.NewReader(archive), opts, func(r *Result) {})
+		if err != nil || stats.Failed != 4 {
+			t.Errorf("%s: Import again got %+v, %v, want 4 failures", format, stats, err)
+		}
+		opts.SkipExisting = true
+		_, stats, err = Import(dst, bytes.NewReader(archive), opts, func(r *Result) {})
+		if err != nil || !cmp.Equal(stats, &Stats{Existing: 4}) {
+			t.Errorf("%s: Import skipping existing got %+v, %v, want 4 existing", format, stats, err)
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	archive := func(files ...string) []byte {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gw)
+		for i := 0; i < len(files); i += 2 {
+			if err := tw.WriteHeader(&tar.Header{Name: files[i], Mode: 0644, Size: int64(len(files[i+1]))}); err != nil {
+				t.Fatal(err)
+			}
+			tw.Write([]byte(files[i+1]))
+		}
+		tw.Close()
+		gw.Close()
+		return buf.Bytes()
+	}
+	manifest := `{"version": 1, "project": "staging", "format": "json_lines"}`
+
+	tests := []struct {
+		desc       string
+		archive    []byte
+		wantErr    bool
+		wantFailed int
+	}{
+		{
+			desc:    "not gzipped",
+			archive: []byte("manifest"),
+			wantErr: true,
+		},
+		{
+			desc:    "no manifest",
+			archive: archive("notes.jsonl", ""),
+			wantErr: true,
+		},
+		{
+			desc:    "unsupported version",
+			archive: archive(manifestFile, `{"version": 2, "project": "staging", "format": "json_lines"}`),
+			wantErr: true,
+		},
+		{
+			desc:    "unknown format",
+			archive: archive(manifestFile, `{"version": 1, "project": "staging", "format": "xml"}`),
+			wantErr: true,
+		},
+		{
+			desc:    "unexpected file",
+			archive: archive(manifestFile, manifest, "notes.pb", ""),
+			wantErr: true,
+		},
+		{
+			desc: "invalid records",
+			archive: archive(manifestFile, manifest,
+				"notes.jsonl", "{not json\n{\"name\": \"projects/other/notes/n\"}\n",
+				"occurrences.jsonl", "{\"name\": \"projects/staging/occurrences/o\"}\n{\"name\": \"occurrences/o\"}\n"),
+			wantFailed: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		var results []*Result
+		_, stats, err := Import(storage.NewMemStore(), bytes.NewReader(tt.archive), &ImportOptions{}, func(r *Result) { results = append(results, r) })
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: Import got err %v, want error %t", tt.desc, err, tt.wantErr)
+		}
+		if err == nil && (stats.Failed != tt.wantFailed || len(results) != tt.wantFailed) {
+			t.Errorf("%q: Import got %+v and reported %v, want %d failures", tt.desc, stats, results, tt.wantFailed)
+		}
+	}
+}
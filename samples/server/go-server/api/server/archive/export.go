@@ -0,0 +1,167 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
+	server "github.com/grafeas/grafeas/server-go"
+)
+
+// pageSize is the number of notes or occurrences listed at once.
+const pageSize = 1000
+
+// Export writes an archive of the notes and occurrences of project pID in s to w, in the specified
+// format, and returns its manifest.
+func Export(s server.Storager, pID string, format Format, w io.Writer) (*Manifest, error) {
+	notesFile, occsFile, err := format.files()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetProject(pID); err != nil {
+		return nil, err
+	}
+	m := &Manifest{
+		Version:    Version,
+		Project:    pID,
+		Format:     format,
+		CreateTime: time.Now().UTC(),
+	}
+
+	// The size of every file of a tar precedes it, so the notes and occurrences are spooled first.
+	notes, err := spool(func(w io.Writer) error {
+		return server.ListAll(func(token string) (string, error) {
+			page, npt, err := s.ListNotes(pID, "", pageSize, token)
+			for _, n := range page {
+				// Storages may list the entities of projects whose IDs start with pID.
+				if nPID, _, err := name.ParseNote(n.Name); err != nil || nPID != pID {
+					continue
+				}
+				if err := format.writeRecord(w, n); err != nil {
+					return "", err
+				}
+				m.Notes++
+			}
+			return npt, err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export notes: %v", err)
+	}
+	defer remove(notes)
+	occs, err := spool(func(w io.Writer) error {
+		return server.ListAll(func(token string) (string, error) {
+			page, npt, err := s.ListOccurrences(pID, "", pageSize, token)
+			for _, o := range page {
+				if oPID, _, err := name.ParseOccurrence(o.Name); err != nil || oPID != pID {
+					continue
+				}
+				if err := format.writeRecord(w, o); err != nil {
+					return "", err
+				}
+				m.Occurrences++
+			}
+			return npt, err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export occurrences: %v", err)
+	}
+	defer remove(occs)
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	if err := writeFile(tw, manifestFile, int64(len(manifest)), m.CreateTime, func(w io.Writer) error {
+		_, err := w.Write(manifest)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	for _, f := range []struct {
+		name string
+		data *os.File
+	}{{notesFile, notes}, {occsFile, occs}} {
+		fi, err := f.data.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.data.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if err := writeFile(tw, f.name, fi.Size(), m.CreateTime, func(w io.Writer) error {
+			_, err := io.Copy(w, f.data)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// spool returns a temporary file holding what write writes to it.
+func spool(write func(io.Writer) error) (*os.File, error) {
+	f, err := ioutil.TempFile("", "grafeas-archive")
+	if err != nil {
+		return nil, err
+	}
+	bw := bufio.NewWriter(f)
+	if err := write(bw); err != nil {
+		remove(f)
+		return nil, err
+	}
+	if err := bw.Flush(); err != nil {
+		remove(f)
+		return nil, err
+	}
+	return f, nil
+}
+
+// remove closes and removes a temporary file.
+func remove(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+// writeFile writes a file of the specified name and size to tw, with the content write writes.
+func writeFile(tw *tar.Writer, fileName string, size int64, modTime time.Time, write func(io.Writer) error) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    fileName,
+		Mode:    0644,
+		Size:    size,
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	return write(tw)
+}
@@ -0,0 +1,231 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
+	server "github.com/grafeas/grafeas/server-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// Project is the ID of the project to import into. If empty, it is the exported project.
+	Project string
+	// SkipExisting skips the notes and occurrences that already exist instead of failing them.
+	SkipExisting bool
+	// NoteProjects maps project IDs to the IDs of the projects that references to their notes, in
+	// the note_name of occurrences and the related_note_names of notes, are rewritten to. References
+	// to the notes of the exported project are rewritten to Project unless it is mapped too.
+	NoteProjects map[string]string
+}
+
+// Result is the outcome of importing a note or occurrence that couldn't be imported.
+type Result struct {
+	// Name is the name of the entity in the archive, or its position if it can't be parsed.
+	Name string
+	// Err is why the entity couldn't be imported.
+	Err error
+}
+
+// Stats counts the entities of an import.
+type Stats struct {
+	// Notes and Occurrences count the entities written.
+	Notes, Occurrences int
+	// Existing counts the entities skipped because they already exist.
+	Existing int
+	// Failed counts the entities that couldn't be written.
+	Failed int
+}
+
+// Import reads an archive from r and writes its notes and occurrences to s, creating the project
+// they are imported into if it doesn't exist. Import calls report with the result of every entity
+// that couldn't be imported, and only returns an error if the archive can't be read.
+func Import(s server.Storager, r io.Reader, opts *ImportOptions, report func(*Result)) (*Manifest, *Stats, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read archive: %v", err)
+	}
+	tr := tar.NewReader(gr)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read archive: %v", err)
+	}
+	if hdr.Name != manifestFile {
+		return nil, nil, fmt.Errorf("archive starts with %q, want %q", hdr.Name, manifestFile)
+	}
+	m := &Manifest{}
+	if err := json.NewDecoder(tr).Decode(m); err != nil {
+		return nil, nil, fmt.Errorf("failed to read manifest: %v", err)
+	}
+	if m.Version != Version {
+		return m, nil, fmt.Errorf("unsupported archive version %d, want %d", m.Version, Version)
+	}
+	notesFile, occsFile, err := m.Format.files()
+	if err != nil {
+		return m, nil, err
+	}
+
+	imp := &importer{
+		s:       s,
+		report:  report,
+		stats:   &Stats{},
+		format:  m.Format,
+		from:    m.Project,
+		to:      m.Project,
+		skip:    opts.SkipExisting,
+		rewrite: map[string]string{},
+	}
+	if opts.Project != "" {
+		imp.to = opts.Project
+	}
+	imp.rewrite[m.Project] = imp.to
+	for from, to := range opts.NoteProjects {
+		imp.rewrite[from] = to
+	}
+	if err := s.CreateProject(imp.to); err != nil && status.Code(err) != codes.AlreadyExists {
+		return m, imp.stats, fmt.Errorf("failed to create project %q: %v", imp.to, err)
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return m, imp.stats, nil
+		}
+		if err != nil {
+			return m, imp.stats, fmt.Errorf("failed to read archive: %v", err)
+		}
+		var importRecord func(int, []byte)
+		switch hdr.Name {
+		case notesFile:
+			importRecord = imp.note
+		case occsFile:
+			importRecord = imp.occurrence
+		default:
+			return m, imp.stats, fmt.Errorf("unexpected file %q in archive", hdr.Name)
+		}
+		br := bufio.NewReader(tr)
+		for i := 1; ; i++ {
+			rec, err := m.Format.readRecord(br)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return m, imp.stats, fmt.Errorf("failed to read record %d of %q: %v", i, hdr.Name, err)
+			}
+			importRecord(i, rec)
+		}
+	}
+}
+
+type importer struct {
+	s      server.Storager
+	report func(*Result)
+	stats  *Stats
+	format Format
+	// from and to are the IDs of the exported project and the project imported into.
+	from, to string
+	skip     bool
+	// rewrite maps the project IDs of note references to those they are rewritten to.
+	rewrite map[string]string
+}
+
+func (imp *importer) note(i int, rec []byte) {
+	n := &pb.Note{}
+	if err := imp.format.unmarshalRecord(rec, n); err != nil {
+		imp.fail(&Result{Name: fmt.Sprintf("note %d", i), Err: err})
+		return
+	}
+	pID, nID, err := name.ParseNote(n.Name)
+	if err == nil && pID != imp.from {
+		err = fmt.Errorf("note isn't in project %q", imp.from)
+	}
+	if err != nil {
+		imp.fail(&Result{Name: n.Name, Err: err})
+		return
+	}
+	archived := n.Name
+	n.Name = name.FormatNote(imp.to, nID)
+	for j, related := range n.RelatedNoteNames {
+		n.RelatedNoteNames[j] = imp.rewriteNoteName(related)
+	}
+	if imp.write(&Result{Name: archived}, imp.s.CreateNote(n)) {
+		imp.stats.Notes++
+	}
+}
+
+func (imp *importer) occurrence(i int, rec []byte) {
+	o := &pb.Occurrence{}
+	if err := imp.format.unmarshalRecord(rec, o); err != nil {
+		imp.fail(&Result{Name: fmt.Sprintf("occurrence %d", i), Err: err})
+		return
+	}
+	pID, oID, err := name.ParseOccurrence(o.Name)
+	if err == nil && pID != imp.from {
+		err = fmt.Errorf("occurrence isn't in project %q", imp.from)
+	}
+	if err != nil {
+		imp.fail(&Result{Name: o.Name, Err: err})
+		return
+	}
+	archived := o.Name
+	o.Name = name.FormatOccurrence(imp.to, oID)
+	o.NoteName = imp.rewriteNoteName(o.NoteName)
+	if imp.write(&Result{Name: archived}, imp.s.CreateOccurrence(o)) {
+		imp.stats.Occurrences++
+	}
+}
+
+// rewriteNoteName returns the name of the note n refers to after rewriting its project.
+func (imp *importer) rewriteNoteName(n string) string {
+	pID, nID, err := name.ParseNote(n)
+	if err != nil {
+		return n
+	}
+	if to, ok := imp.rewrite[pID]; ok {
+		return name.FormatNote(to, nID)
+	}
+	return n
+}
+
+// write records the result of writing an entity, and returns whether it was written.
+func (imp *importer) write(r *Result, err error) bool {
+	switch status.Code(err) {
+	case codes.OK:
+		return true
+	case codes.AlreadyExists:
+		if imp.skip {
+			imp.stats.Existing++
+			return false
+		}
+	}
+	r.Err = err
+	imp.fail(r)
+	return false
+}
+
+func (imp *importer) fail(r *Result) {
+	imp.stats.Failed++
+	imp.report(r)
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auditlog records the audit events of the v1beta1 API in files.
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/name"
+	grafeas "github.com/grafeas/grafeas/go/v1beta1/api"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// File is an audit log of every project in a file of JSON encoded events, one per line, which is
+// only ever appended to. Page tokens are the offsets in the file of the next event to list.
+type File struct {
+	path string
+	mu   sync.Mutex
+	f    *os.File
+}
+
+var _ grafeas.Audit = (*File)(nil)
+
+// NewFile returns the audit log in the file with the specified path, which is created if it
+// doesn't exist.
+func NewFile(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &File{path: path, f: f}, nil
+}
+
+// Close closes the file.
+func (l *File) Close() error {
+	return l.f.Close()
+}
+
+// RecordAuditEvent appends the specified event to the file. Its name has to be in the specified
+// project.
+func (l *File) RecordAuditEvent(ctx context.Context, pID string, e *auditpb.AuditEvent) error {
+	if !inProject(e, pID) {
+		return errors.Newf(codes.InvalidArgument, "audit event %q isn't in project %q", e.Name, pID)
+	}
+	m := &jsonpb.Marshaler{}
+	line, err := m.MarshalToString(e)
+	if err != nil {
+		return errors.Newf(codes.Internal, "failed to marshal audit event: %v", err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// A single write appends the whole line, even if other processes append to the file too.
+	if _, err := l.f.WriteString(line + "\n"); err != nil {
+		return errors.Newf(codes.Internal, "failed to write audit event: %v", err)
+	}
+	return nil
+}
+
+// ListAuditEvents lists the events of the specified project in the file by reading through it from
+// the page token.
+func (l *File) ListAuditEvents(ctx context.Context, pID, resource string, start, end time.Time, pageToken string, pageSize int32) ([]*auditpb.AuditEvent, string, error) {
+	var offset int64
+	if pageToken != "" {
+		var err error
+		if offset, err = strconv.ParseInt(pageToken, 10, 64); err != nil || offset < 0 {
+			return nil, "", errors.Newf(codes.InvalidArgument, "invalid page token %q", pageToken)
+		}
+	}
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, "", errors.Newf(codes.Internal, "failed to open audit log: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, "", errors.Newf(codes.Internal, "failed to read audit log: %v", err)
+	}
+
+	var events []*auditpb.AuditEvent
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A partial line is an event still being written.
+			return events, "", nil
+		}
+		if err != nil {
+			return nil, "", errors.Newf(codes.Internal, "failed to read audit log: %v", err)
+		}
+		e := &auditpb.AuditEvent{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(line), e); err != nil {
+			return nil, "", errors.Newf(codes.Internal, "invalid audit event at offset %d of the audit log: %v", offset, err)
+		}
+		if inProject(e, pID) && matches(e, resource, start, end) {
+			if len(events) == int(pageSize) {
+				return events, strconv.FormatInt(offset, 10), nil
+			}
+			events = append(events, e)
+		}
+		offset += int64(len(line))
+	}
+}
+
+// inProject returns whether the specified event is named in the specified project.
+func inProject(e *auditpb.AuditEvent, pID string) bool {
+	return strings.HasPrefix(e.Name, name.FormatProject(pID)+"/")
+}
+
+// matches returns whether e is about resource, if it is non-empty, and its event time is in
+// [start, end) for the bounds that are non-zero.
+func matches(e *auditpb.AuditEvent, resource string, start, end time.Time) bool {
+	if resource != "" && e.Resource != resource {
+		return false
+	}
+	t, err := ptypes.Timestamp(e.EventTime)
+	if err != nil {
+		return start.IsZero() && end.IsZero()
+	}
+	return (start.IsZero() || !t.Before(start)) && (end.IsZero() || t.Before(end))
+}
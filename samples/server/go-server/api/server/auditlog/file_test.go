@@ -0,0 +1,139 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grafeas/grafeas/go/name"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFile(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "auditlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	l, err := NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile got err %v, want success", err)
+	}
+	start := time.Now().Truncate(time.Second)
+	var want []*auditpb.AuditEvent
+	for i, r := range []string{"projects/goog-vulnz/notes/CVE-1", "projects/goog-vulnz/notes/CVE-2", "projects/goog-vulnz/notes/CVE-1"} {
+		ts, err := ptypes.TimestampProto(start.Add(time.Duration(i) * time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := &auditpb.AuditEvent{
+			Name:        name.FormatAuditEvent("goog-vulnz", strconv.Itoa(i)),
+			Actor:       "alice",
+			Method:      "UpdateNote",
+			Resource:    r,
+			AfterDigest: strconv.Itoa(i),
+			EventTime:   ts,
+		}
+		if err := l.RecordAuditEvent(ctx, "goog-vulnz", e); err != nil {
+			t.Fatalf("RecordAuditEvent got err %v, want success", err)
+		}
+		want = append(want, e)
+		other := proto.Clone(e).(*auditpb.AuditEvent)
+		other.Name = name.FormatAuditEvent("consumer1", strconv.Itoa(i))
+		if err := l.RecordAuditEvent(ctx, "consumer1", other); err != nil {
+			t.Fatalf("RecordAuditEvent got err %v, want success", err)
+		}
+	}
+	if err := l.RecordAuditEvent(ctx, "consumer1", want[0]); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("RecordAuditEvent of an event of another project got err %v, want %v", err, codes.InvalidArgument)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Events are listed in order a page at a time, from a reopened file too.
+	l, err = NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile got err %v, want success", err)
+	}
+	defer l.Close()
+	var got []*auditpb.AuditEvent
+	pageToken := ""
+	for pages := 0; pages == 0 || pageToken != ""; pages++ {
+		if pages > len(want) {
+			t.Fatalf("ListAuditEvents got more than %d pages", len(want))
+		}
+		var page []*auditpb.AuditEvent
+		page, pageToken, err = l.ListAuditEvents(ctx, "goog-vulnz", "", time.Time{}, time.Time{}, pageToken, 2)
+		if err != nil {
+			t.Fatalf("ListAuditEvents got err %v, want success", err)
+		}
+		got = append(got, page...)
+	}
+	if !eventsEqual(got, want) {
+		t.Errorf("ListAuditEvents got %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		desc       string
+		resource   string
+		start, end time.Time
+		want       []*auditpb.AuditEvent
+	}{
+		{"by resource", "projects/goog-vulnz/notes/CVE-1", time.Time{}, time.Time{}, []*auditpb.AuditEvent{want[0], want[2]}},
+		{"by start", "", start.Add(time.Minute), time.Time{}, want[1:]},
+		{"by end", "", time.Time{}, start.Add(time.Minute), want[:1]},
+		{"by resource and time", "projects/goog-vulnz/notes/CVE-1", start.Add(time.Minute), start.Add(time.Hour), want[2:]},
+	}
+	for _, tt := range tests {
+		got, pageToken, err := l.ListAuditEvents(ctx, "goog-vulnz", tt.resource, tt.start, tt.end, "", 100)
+		if err != nil || pageToken != "" {
+			t.Errorf("%q: ListAuditEvents got page token %q, err %v, want success", tt.desc, pageToken, err)
+			continue
+		}
+		if !eventsEqual(got, tt.want) {
+			t.Errorf("%q: ListAuditEvents got %v, want %v", tt.desc, got, tt.want)
+		}
+	}
+
+	if _, _, err := l.ListAuditEvents(ctx, "goog-vulnz", "", time.Time{}, time.Time{}, "invalid", 100); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListAuditEvents with an invalid page token got err %v, want %v", err, codes.InvalidArgument)
+	}
+}
+
+func eventsEqual(a, b []*auditpb.AuditEvent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,293 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+// APIKeyHeader is the header, and gRPC metadata key, callers pass their API key in.
+const APIKeyHeader = "x-api-key"
+
+const (
+	// apiKeyPrefix prefixes API keys, which makes them recognizable, e.g. by secret scanners.
+	apiKeyPrefix = "grafeas_"
+	// hashPrefix prefixes the hashes of API keys in keys files.
+	hashPrefix = "sha256:"
+)
+
+// APIKey is an API key of a keys file. Only the hash of the key is kept.
+type APIKey struct {
+	// Name identifies the key in the keys file.
+	Name string `yaml:"name"`
+	// Hash is "sha256:" followed by the hex encoded SHA-256 hash of the key.
+	Hash string `yaml:"hash"`
+	// Identity is the ID of the callers passing the key.
+	Identity string `yaml:"identity"`
+	// Role is the role of the policy file granted to the callers passing the key in every
+	// project, if set.
+	Role string `yaml:"role,omitempty"`
+	// Created is when the key was created or last rotated.
+	Created time.Time `yaml:"created"`
+}
+
+// APIKeys is the content of a keys file:
+//
+//	keys:
+//	- name: ci
+//	  hash: sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//	  identity: ci@example.com
+//	  role: scanner
+//	  created: 2019-06-01T12:00:00Z
+type APIKeys struct {
+	Keys []*APIKey `yaml:"keys"`
+}
+
+// ParseAPIKeys parses and validates a keys file.
+func ParseAPIKeys(data []byte) (*APIKeys, error) {
+	k := &APIKeys{}
+	if err := yaml.UnmarshalStrict(data, k); err != nil {
+		return nil, err
+	}
+	names, hashes := map[string]bool{}, map[string]bool{}
+	for i, key := range k.Keys {
+		switch {
+		case key.Name == "":
+			return nil, fmt.Errorf("key %d: a name must be specified", i)
+		case names[key.Name]:
+			return nil, fmt.Errorf("key %d: duplicate name %q", i, key.Name)
+		case key.Identity == "":
+			return nil, fmt.Errorf("key %q: an identity must be specified", key.Name)
+		case !validHash(key.Hash):
+			return nil, fmt.Errorf("key %q: invalid hash %q", key.Name, key.Hash)
+		case hashes[key.Hash]:
+			return nil, fmt.Errorf("key %q: duplicate hash", key.Name)
+		}
+		names[key.Name], hashes[key.Hash] = true, true
+	}
+	return k, nil
+}
+
+// ReadAPIKeys reads a keys file. A missing file has no keys.
+func ReadAPIKeys(path string) (*APIKeys, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &APIKeys{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	k, err := ParseAPIKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keys file %q: %v", path, err)
+	}
+	return k, nil
+}
+
+// WriteAPIKeys replaces the keys file with the keys. Servers never see a partially written file.
+func WriteAPIKeys(path string, k *APIKeys) error {
+	data, err := yaml.Marshal(k)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Create adds a key with the specified name, identity and role, which may be empty, and returns
+// it. The key can't be retrieved later.
+func (k *APIKeys) Create(name, identity, role string) (string, error) {
+	if name == "" || identity == "" {
+		return "", fmt.Errorf("a name and an identity must be specified")
+	}
+	if k.find(name) != nil {
+		return "", fmt.Errorf("key %q already exists", name)
+	}
+	key, hash, err := newAPIKey()
+	if err != nil {
+		return "", err
+	}
+	k.Keys = append(k.Keys, &APIKey{Name: name, Hash: hash, Identity: identity, Role: role, Created: time.Now().UTC()})
+	return key, nil
+}
+
+// Rotate replaces the key with the specified name with a new one, which it returns. The previous
+// key stops working.
+func (k *APIKeys) Rotate(name string) (string, error) {
+	existing := k.find(name)
+	if existing == nil {
+		return "", fmt.Errorf("key %q doesn't exist", name)
+	}
+	key, hash, err := newAPIKey()
+	if err != nil {
+		return "", err
+	}
+	existing.Hash, existing.Created = hash, time.Now().UTC()
+	return key, nil
+}
+
+// Revoke deletes the key with the specified name.
+func (k *APIKeys) Revoke(name string) error {
+	for i, key := range k.Keys {
+		if key.Name == name {
+			k.Keys = append(k.Keys[:i], k.Keys[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("key %q doesn't exist", name)
+}
+
+func (k *APIKeys) find(name string) *APIKey {
+	for _, key := range k.Keys {
+		if key.Name == name {
+			return key
+		}
+	}
+	return nil
+}
+
+// APIKeyAuthenticator authenticates callers by the API key they pass in the APIKeyHeader header,
+// one of those of a keys file, which it reloads when the file changes.
+type APIKeyAuthenticator struct {
+	file *watchedFile
+}
+
+// NewAPIKeyAuthenticator returns an APIKeyAuthenticator with the keys of the specified file.
+func NewAPIKeyAuthenticator(path string) (*APIKeyAuthenticator, error) {
+	f, err := newWatchedFile(path, func(data []byte) (interface{}, error) {
+		k, err := ParseAPIKeys(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid keys file %q: %v", path, err)
+		}
+		byHash := map[string]*APIKey{}
+		for _, key := range k.Keys {
+			byHash[key.Hash] = key
+		}
+		return byHash, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &APIKeyAuthenticator{file: f}, nil
+}
+
+// Authenticate returns the caller the key identifies.
+func (a *APIKeyAuthenticator) Authenticate(key string) (*Caller, error) {
+	k, ok := a.file.current().(map[string]*APIKey)[hashAPIKey(key)]
+	if !ok {
+		return nil, fmt.Errorf("unknown API key")
+	}
+	c := &Caller{ID: k.Identity}
+	if k.Role != "" {
+		c.Roles = []string{k.Role}
+	}
+	return c, nil
+}
+
+// UnaryInterceptor authenticates the callers of unary calls with an API key, see
+// StreamInterceptor.
+func (a *APIKeyAuthenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor authenticates the callers of streaming calls with an API key, whose context
+// then carries them. Calls with an unknown key, or with a key and another credential authenticated
+// before, fail with an Unauthenticated error, and calls without one are left to other means of
+// identification.
+func (a *APIKeyAuthenticator) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate returns ctx carrying the caller the API key of the call identifies, or ctx if the
+// call has no API key.
+func (a *APIKeyAuthenticator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	keys := md.Get(APIKeyHeader)
+	switch {
+	case len(keys) == 0:
+		return ctx, nil
+	case len(keys) > 1:
+		return nil, status.Errorf(codes.Unauthenticated, "more than one API key")
+	}
+	if _, ok := FromContext(ctx); ok {
+		return nil, status.Errorf(codes.Unauthenticated, "calls can't be authenticated by both an API key and another credential")
+	}
+	c, err := a.Authenticate(keys[0])
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid API key: %v", err)
+	}
+	return NewContext(ctx, c), nil
+}
+
+// newAPIKey returns a new random API key and its hash.
+func newAPIKey() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, hashAPIKey(key), nil
+}
+
+// hashAPIKey returns the hash of an API key in a keys file. API keys are random, so they need no
+// salt or slow hash.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hashPrefix + hex.EncodeToString(sum[:])
+}
+
+func validHash(h string) bool {
+	if !strings.HasPrefix(h, hashPrefix) {
+		return false
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(h, hashPrefix))
+	return err == nil && len(b) == sha256.Size
+}
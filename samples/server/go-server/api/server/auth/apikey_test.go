@@ -0,0 +1,194 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.yaml")
+
+	k, err := ReadAPIKeys(path)
+	if err != nil || len(k.Keys) != 0 {
+		t.Fatalf("ReadAPIKeys of a missing file got %v, %v, want no keys", k, err)
+	}
+	ci, err := k.Create("ci", "ci@example.com", "scanner")
+	if err != nil {
+		t.Fatalf("Create got err %v, want success", err)
+	}
+	if !strings.HasPrefix(ci, apiKeyPrefix) {
+		t.Errorf("Create got key %q, want prefix %q", ci, apiKeyPrefix)
+	}
+	if _, err := k.Create("ci", "other@example.com", ""); err == nil {
+		t.Error("Create of an existing key got success, want error")
+	}
+	deploy, err := k.Create("deploy", "deploy@example.com", "")
+	if err != nil {
+		t.Fatalf("Create got err %v, want success", err)
+	}
+	if err := WriteAPIKeys(path, k); err != nil {
+		t.Fatalf("WriteAPIKeys got err %v, want success", err)
+	}
+
+	got, err := ReadAPIKeys(path)
+	if err != nil {
+		t.Fatalf("ReadAPIKeys got err %v, want success", err)
+	}
+	if !cmp.Equal(got, k) {
+		t.Errorf("ReadAPIKeys got %+v, want %+v", got, k)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), ci) || strings.Contains(string(data), deploy) {
+		t.Error("keys file has plain keys, want hashes only")
+	}
+
+	rotated, err := k.Rotate("ci")
+	if err != nil {
+		t.Fatalf("Rotate got err %v, want success", err)
+	}
+	if rotated == ci || k.Keys[0].Hash != hashAPIKey(rotated) {
+		t.Errorf("Rotate got key %q with hash %q, want a new key", rotated, k.Keys[0].Hash)
+	}
+	if err := k.Revoke("deploy"); err != nil {
+		t.Fatalf("Revoke got err %v, want success", err)
+	}
+	if len(k.Keys) != 1 || k.Keys[0].Name != "ci" {
+		t.Errorf("Revoke left keys %+v, want ci only", k.Keys)
+	}
+	if _, err := k.Rotate("deploy"); err == nil {
+		t.Error("Rotate of a revoked key got success, want error")
+	}
+	if err := k.Revoke("deploy"); err == nil {
+		t.Error("Revoke of a revoked key got success, want error")
+	}
+}
+
+func TestParseAPIKeysErrors(t *testing.T) {
+	hash := hashAPIKey("grafeas_key")
+	for desc, k := range map[string]string{
+		"not YAML":       "keys: [",
+		"unknown field":  "key: []",
+		"no name":        "keys: [{hash: " + hash + ", identity: ci}]",
+		"duplicate name": "keys: [{name: ci, hash: " + hash + ", identity: ci}, {name: ci, hash: " + hashAPIKey("other") + ", identity: ci}]",
+		"no identity":    "keys: [{name: ci, hash: " + hash + "}]",
+		"plain key":      "keys: [{name: ci, hash: grafeas_key, identity: ci}]",
+		"duplicate hash": "keys: [{name: ci, hash: " + hash + ", identity: ci}, {name: deploy, hash: " + hash + ", identity: deploy}]",
+	} {
+		if _, err := ParseAPIKeys([]byte(k)); err == nil {
+			t.Errorf("%q: ParseAPIKeys got success, want error", desc)
+		}
+	}
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	defer func(old time.Duration) { reloadInterval = old }(reloadInterval)
+	reloadInterval = 0
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.yaml")
+	k := &APIKeys{}
+	ci, err := k.Create("ci", "ci@example.com", "scanner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAPIKeys(path, k); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewAPIKeyAuthenticator(path)
+	if err != nil {
+		t.Fatalf("NewAPIKeyAuthenticator got err %v, want success", err)
+	}
+	jwtCaller := NewContext(context.Background(), &Caller{ID: "alice"})
+	tests := []struct {
+		desc          string
+		ctx           context.Context
+		keys          []string
+		want          *Caller
+		wantErrStatus codes.Code
+	}{
+		{desc: "valid key", keys: []string{ci}, want: &Caller{ID: "ci@example.com", Roles: []string{"scanner"}}},
+		{desc: "unknown key", keys: []string{"grafeas_unknown"}, wantErrStatus: codes.Unauthenticated},
+		{desc: "several keys", keys: []string{ci, ci}, wantErrStatus: codes.Unauthenticated},
+		{desc: "key and bearer token", ctx: jwtCaller, keys: []string{ci}, wantErrStatus: codes.Unauthenticated},
+		{desc: "no key"},
+		{desc: "bearer token only", ctx: jwtCaller, want: &Caller{ID: "alice"}},
+	}
+	for _, tt := range tests {
+		ctx := tt.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		md := metadata.MD{}
+		for _, key := range tt.keys {
+			md.Append(APIKeyHeader, key)
+		}
+		ctx = metadata.NewIncomingContext(ctx, md)
+		var got *Caller
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			got, _ = FromContext(ctx)
+			return nil, nil
+		}
+		_, err := a.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		if status.Code(err) != tt.wantErrStatus || !cmp.Equal(got, tt.want) {
+			t.Errorf("%q: unary call got caller %+v, err %v, want %+v, %v", tt.desc, got, err, tt.want, tt.wantErrStatus)
+		}
+
+		got = nil
+		stream := func(srv interface{}, ss grpc.ServerStream) error {
+			_, err := handler(ss.Context(), nil)
+			return err
+		}
+		err = a.StreamInterceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, stream)
+		if status.Code(err) != tt.wantErrStatus || !cmp.Equal(got, tt.want) {
+			t.Errorf("%q: streaming call got caller %+v, err %v, want %+v, %v", tt.desc, got, err, tt.want, tt.wantErrStatus)
+		}
+	}
+
+	// Revoked keys stop working once the file is reloaded.
+	if err := k.Revoke("ci"); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAPIKeys(path, k); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := a.Authenticate(ci); err == nil {
+		t.Errorf("Authenticate with revoked key got %+v, want error", c)
+	}
+}
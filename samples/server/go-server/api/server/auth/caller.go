@@ -0,0 +1,71 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// Caller is a caller authenticated by a bearer token or an API key.
+type Caller struct {
+	ID string
+	// Groups are the groups the caller is in.
+	Groups []string
+	// Roles are the roles of the policy file granted to the caller in every project.
+	Roles []string
+}
+
+type callerKey struct{}
+
+// NewContext returns a context carrying the authenticated caller.
+func NewContext(ctx context.Context, c *Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, c)
+}
+
+// FromContext returns the authenticated caller ctx carries, if any.
+func FromContext(ctx context.Context) (*Caller, bool) {
+	c, ok := ctx.Value(callerKey{}).(*Caller)
+	return c, ok
+}
+
+// CallerGroups returns the groups of the authenticated caller ctx carries, if any. It can be used
+// as the Groups of a PolicyAuth.
+func CallerGroups(ctx context.Context) []string {
+	if c, ok := FromContext(ctx); ok {
+		return c.Groups
+	}
+	return nil
+}
+
+// CallerRoles returns the roles granted to the authenticated caller ctx carries, if any. It can be
+// used as the Roles of a PolicyAuth.
+func CallerRoles(ctx context.Context) []string {
+	if c, ok := FromContext(ctx); ok {
+		return c.Roles
+	}
+	return nil
+}
+
+// serverStream is a server stream with the context of an authenticated caller.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
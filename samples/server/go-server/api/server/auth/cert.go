@@ -0,0 +1,133 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Fields of client certificates that identify callers.
+const (
+	// CommonName identifies callers by the common name of the subject of their certificate.
+	CommonName = "common_name"
+	// URI identifies callers by the URI subject alternative name of their certificate, e.g. a SPIFFE
+	// ID.
+	URI = "uri"
+	// Email identifies callers by the email subject alternative name of their certificate.
+	Email = "email"
+)
+
+// ForwardedCertKey is the metadata key a proxy serving REST calls forwards the verified client
+// certificate of the REST caller in, see ForwardCert.
+const ForwardedCertKey = "grafeas-client-cert-bin"
+
+// CertIdentity identifies callers by a field of their verified client certificate.
+type CertIdentity struct {
+	field string
+	proxy []byte
+}
+
+// NewCertIdentity returns a CertIdentity identifying callers by the specified certificate field.
+// Calls from the proxy, the peer with the specified certificate, are made on behalf of the
+// callers whose certificates it forwards; proxy may be nil if there is none.
+func NewCertIdentity(field string, proxy *x509.Certificate) (*CertIdentity, error) {
+	switch field {
+	case CommonName, URI, Email:
+	default:
+		return nil, fmt.Errorf("unknown certificate field %q, want %q, %q or %q", field, CommonName, URI, Email)
+	}
+	c := &CertIdentity{field: field}
+	if proxy != nil {
+		c.proxy = proxy.Raw
+	}
+	return c, nil
+}
+
+// Identity returns the ID of the caller of the call of ctx. Callers without a verified certificate
+// are anonymous, but callers whose certificate lacks the field fail.
+func (c *CertIdentity) Identity(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+		return "", nil
+	}
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	if c.proxy != nil && bytes.Equal(cert.Raw, c.proxy) {
+		forwarded, err := forwardedCert(ctx)
+		if err != nil || forwarded == nil {
+			return "", err
+		}
+		cert = forwarded
+	}
+	return c.id(cert)
+}
+
+// id returns the ID in the field of the certificate.
+func (c *CertIdentity) id(cert *x509.Certificate) (string, error) {
+	var ids []string
+	switch c.field {
+	case CommonName:
+		if cert.Subject.CommonName != "" {
+			ids = append(ids, cert.Subject.CommonName)
+		}
+	case URI:
+		for _, u := range cert.URIs {
+			ids = append(ids, u.String())
+		}
+	case Email:
+		ids = cert.EmailAddresses
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("client certificate of %q has no %s", cert.Subject, c.field)
+	case 1:
+		return ids[0], nil
+	}
+	return "", fmt.Errorf("client certificate of %q has %d %s values, want one", cert.Subject, len(ids), c.field)
+}
+
+// forwardedCert returns the certificate a proxy forwarded with the call of ctx, or nil if the proxy
+// made the call on its own behalf.
+func forwardedCert(ctx context.Context) (*x509.Certificate, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	switch v := md.Get(ForwardedCertKey); len(v) {
+	case 0:
+		return nil, nil
+	case 1:
+		return x509.ParseCertificate([]byte(v[0]))
+	}
+	return nil, fmt.Errorf("more than one forwarded client certificate")
+}
+
+// ForwardCert returns the metadata forwarding the verified client certificate of the HTTP request,
+// if any, to a server whose CertIdentity trusts the certificate of the forwarding proxy. The proxy
+// must not forward the ForwardedCertKey header of the request itself.
+func ForwardCert(ctx context.Context, r *http.Request) metadata.MD {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return nil
+	}
+	return metadata.Pairs(ForwardedCertKey, string(r.TLS.VerifiedChains[0][0].Raw))
+}
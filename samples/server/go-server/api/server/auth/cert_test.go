@@ -0,0 +1,135 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// newCert returns a self-signed certificate with the specified common name and subject alternative
+// names.
+func newCert(t *testing.T, cn string, uris []string, emails []string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:   big.NewInt(1),
+		Subject:        pkix.Name{CommonName: cn},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+		EmailAddresses: emails,
+	}
+	for _, u := range uris {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl.URIs = append(tmpl.URIs, parsed)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// peerContext returns the context of a call by a peer with the specified verified certificate.
+func peerContext(cert *x509.Certificate) context.Context {
+	state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+func TestCertIdentity(t *testing.T) {
+	scanner := newCert(t, "scanner", []string{"spiffe://example.com/scanner"}, []string{"scanner@example.com"})
+	twoURIs := newCert(t, "", []string{"spiffe://example.com/a", "spiffe://example.com/b"}, nil)
+
+	tests := []struct {
+		field   string
+		ctx     context.Context
+		want    string
+		wantErr bool
+	}{
+		{field: CommonName, ctx: peerContext(scanner), want: "scanner"},
+		{field: URI, ctx: peerContext(scanner), want: "spiffe://example.com/scanner"},
+		{field: Email, ctx: peerContext(scanner), want: "scanner@example.com"},
+		{field: CommonName, ctx: context.Background(), want: ""},
+		{field: CommonName, ctx: peerContext(twoURIs), wantErr: true},
+		{field: URI, ctx: peerContext(twoURIs), wantErr: true},
+		{field: Email, ctx: peerContext(twoURIs), wantErr: true},
+	}
+	for _, tt := range tests {
+		c, err := NewCertIdentity(tt.field, nil)
+		if err != nil {
+			t.Fatalf("NewCertIdentity(%q) got err %v, want success", tt.field, err)
+		}
+		got, err := c.Identity(tt.ctx)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: Identity got %q, %v, want %q, error %t", tt.field, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if _, err := NewCertIdentity("subject", nil); err == nil {
+		t.Error("NewCertIdentity of unknown field got success, want error")
+	}
+}
+
+func TestCertIdentityProxy(t *testing.T) {
+	proxy := newCert(t, "grafeas", nil, nil)
+	alice := newCert(t, "alice", nil, nil)
+	c, err := NewCertIdentity(CommonName, proxy)
+	if err != nil {
+		t.Fatalf("NewCertIdentity got err %v, want success", err)
+	}
+
+	// The proxy forwards the certificate of the REST caller.
+	r := httptest.NewRequest("GET", "https://grafeas/v1beta1/projects", nil)
+	r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{alice}}}
+	md := ForwardCert(context.Background(), r)
+	ctx := metadata.NewIncomingContext(peerContext(proxy), md)
+	if got, err := c.Identity(ctx); got != "alice" || err != nil {
+		t.Errorf("Identity of forwarded call got %q, %v, want %q", got, err, "alice")
+	}
+
+	// Other callers can't forward certificates.
+	ctx = metadata.NewIncomingContext(peerContext(newCert(t, "mallory", nil, nil)), md)
+	if got, err := c.Identity(ctx); got != "mallory" || err != nil {
+		t.Errorf("Identity of call forwarding a certificate got %q, %v, want %q", got, err, "mallory")
+	}
+
+	if md := ForwardCert(context.Background(), httptest.NewRequest("GET", "http://grafeas/v1beta1/projects", nil)); md != nil {
+		t.Errorf("ForwardCert without TLS got %v, want nil", md)
+	}
+}
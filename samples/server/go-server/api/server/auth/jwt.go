@@ -0,0 +1,461 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	// Register the hashes of the supported signing algorithms.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Default claims identifying callers and listing their groups.
+const (
+	DefaultSubjectClaim = "sub"
+	DefaultGroupsClaim  = "groups"
+)
+
+var (
+	// jwksRefreshInterval is how often JWTAuthenticator refreshes its keys at most, when tokens are
+	// signed by unknown keys.
+	jwksRefreshInterval = time.Minute
+	// clockSkew is how much the clocks of token issuers may be off.
+	clockSkew = time.Minute
+	// jwksClient fetches the keys of JWKS URLs.
+	jwksClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// maxJWKSSize is the size of JWKS documents at most.
+const maxJWKSSize = 1 << 20
+
+// JWTConfig configures the validation of bearer tokens.
+type JWTConfig struct {
+	// JWKS is the path or http(s) URL of the JSON Web Key Set with the keys signing tokens.
+	JWKS string `yaml:"jwks"`
+	// Issuer is the issuer tokens must have.
+	Issuer string `yaml:"issuer"`
+	// Audience is the audience tokens must have.
+	Audience string `yaml:"audience"`
+	// SubjectClaim is the claim identifying callers, DefaultSubjectClaim if empty.
+	SubjectClaim string `yaml:"subject_claim"`
+	// GroupsClaim is the claim listing the groups of callers, DefaultGroupsClaim if empty.
+	GroupsClaim string `yaml:"groups_claim"`
+}
+
+// JWTAuthenticator authenticates callers by the JWTs they pass as bearer tokens, which must be
+// signed by a key of its JWKS, with RS256, RS384, RS512, ES256, ES384 or ES512. It refreshes the
+// keys when tokens are signed by unknown ones.
+type JWTAuthenticator struct {
+	config JWTConfig
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// NewJWTAuthenticator returns a JWTAuthenticator validating tokens as configured.
+func NewJWTAuthenticator(config JWTConfig) (*JWTAuthenticator, error) {
+	switch {
+	case config.JWKS == "":
+		return nil, fmt.Errorf("a JWKS must be specified")
+	case config.Issuer == "":
+		return nil, fmt.Errorf("an issuer must be specified")
+	case config.Audience == "":
+		return nil, fmt.Errorf("an audience must be specified")
+	}
+	if config.SubjectClaim == "" {
+		config.SubjectClaim = DefaultSubjectClaim
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = DefaultGroupsClaim
+	}
+	a := &JWTAuthenticator{config: config}
+	if err := a.load(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Authenticate validates the token and returns the caller it identifies.
+func (a *JWTAuthenticator) Authenticate(token string) (*Caller, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed header: %v", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature: %v", err)
+	}
+	key, err := a.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(header.Alg, key, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed claims: %v", err)
+	}
+	if iss, _ := claims["iss"].(string); iss != a.config.Issuer {
+		return nil, fmt.Errorf("issuer %q, want %q", iss, a.config.Issuer)
+	}
+	if !hasAudience(claims["aud"], a.config.Audience) {
+		return nil, fmt.Errorf("audience %v, want %q", claims["aud"], a.config.Audience)
+	}
+	now := time.Now()
+	exp, ok := numericDate(claims["exp"])
+	if !ok {
+		return nil, fmt.Errorf("no expiration time")
+	}
+	if now.After(exp.Add(clockSkew)) {
+		return nil, fmt.Errorf("expired at %v", exp)
+	}
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Add(clockSkew).Before(nbf) {
+		return nil, fmt.Errorf("not valid before %v", nbf)
+	}
+
+	c := &Caller{}
+	c.ID, _ = claims[a.config.SubjectClaim].(string)
+	if c.ID == "" {
+		return nil, fmt.Errorf("no %q claim", a.config.SubjectClaim)
+	}
+	if groups, ok := claims[a.config.GroupsClaim]; ok {
+		list, ok := groups.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%q claim isn't a list", a.config.GroupsClaim)
+		}
+		for _, g := range list {
+			name, ok := g.(string)
+			if !ok {
+				return nil, fmt.Errorf("%q claim has non-string group %v", a.config.GroupsClaim, g)
+			}
+			c.Groups = append(c.Groups, name)
+		}
+	}
+	return c, nil
+}
+
+// UnaryInterceptor authenticates the callers of unary calls with a bearer token, see
+// StreamInterceptor.
+func (a *JWTAuthenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor authenticates the callers of streaming calls with a bearer token, whose
+// context then carries them. Calls with an invalid token fail with an Unauthenticated error, and
+// calls without one are left to other means of identification.
+func (a *JWTAuthenticator) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// HTTPHandler returns a handler rejecting the HTTP requests with an invalid bearer token, and
+// passing the others to h.
+func (a *JWTAuthenticator) HTTPHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, ok := bearerToken(r.Header["Authorization"]); ok {
+			if _, err := a.Authenticate(token); err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				http.Error(w, fmt.Sprintf("invalid bearer token: %v", err), http.StatusUnauthorized)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// authenticate returns ctx carrying the caller the bearer token of the call identifies, or ctx if
+// the call has no bearer token.
+func (a *JWTAuthenticator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	token, ok := bearerToken(md.Get("authorization"))
+	if !ok {
+		return ctx, nil
+	}
+	c, err := a.Authenticate(token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid bearer token: %v", err)
+	}
+	return NewContext(ctx, c), nil
+}
+
+// key returns the key with the specified ID, refreshing the keys if it is unknown.
+func (a *JWTAuthenticator) key(kid string) (crypto.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if k, ok := a.keys[kid]; ok {
+		return k, nil
+	}
+	// The keys may have been rotated.
+	if time.Since(a.fetched) >= jwksRefreshInterval {
+		if err := a.load(); err != nil {
+			log.Printf("Failed to refresh JWKS %q, keeping the previous keys: %v", a.config.JWKS, err)
+		} else if k, ok := a.keys[kid]; ok {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// load loads the keys of the JWKS. It must be called with mu held or before a is shared.
+func (a *JWTAuthenticator) load() error {
+	a.fetched = time.Now()
+	data, err := a.fetch()
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("invalid JWKS %q: %v", a.config.JWKS, err)
+	}
+	a.keys = keys
+	return nil
+}
+
+// fetch returns the JWKS document.
+func (a *JWTAuthenticator) fetch() ([]byte, error) {
+	src := a.config.JWKS
+	if !strings.HasPrefix(src, "https://") && !strings.HasPrefix(src, "http://") {
+		return ioutil.ReadFile(src)
+	}
+	resp, err := jwksClient.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS %q: %s", src, resp.Status)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+// jwk is a JSON Web Key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS returns the signing keys of a JSON Web Key Set by ID. Keys of other types than RSA and
+// EC are ignored.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []*jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := map[string]crypto.PublicKey{}
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var key crypto.PublicKey
+		switch k.Kty {
+		case "RSA":
+			n, err := decodeInt(k.N)
+			if err != nil {
+				return nil, fmt.Errorf("key %d: invalid modulus: %v", i, err)
+			}
+			e, err := decodeInt(k.E)
+			if err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+				return nil, fmt.Errorf("key %d: invalid exponent", i)
+			}
+			key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case "EC":
+			curve, ok := curves[k.Crv]
+			if !ok {
+				return nil, fmt.Errorf("key %d: unsupported curve %q", i, k.Crv)
+			}
+			x, errX := decodeInt(k.X)
+			y, errY := decodeInt(k.Y)
+			if errX != nil || errY != nil || !curve.IsOnCurve(x, y) {
+				return nil, fmt.Errorf("key %d: invalid point", i)
+			}
+			key = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		default:
+			continue
+		}
+		if _, ok := keys[k.Kid]; ok {
+			return nil, fmt.Errorf("key %d: duplicate key ID %q", i, k.Kid)
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+var curves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+// verify verifies the signature of the signed part of a token with the key, using the algorithm of
+// the token.
+func verify(alg string, key crypto.PublicKey, signed, sig []byte) error {
+	var (
+		hash  crypto.Hash
+		curve elliptic.Curve
+	)
+	switch alg {
+	case "RS256":
+		hash = crypto.SHA256
+	case "RS384":
+		hash = crypto.SHA384
+	case "RS512":
+		hash = crypto.SHA512
+	case "ES256":
+		hash, curve = crypto.SHA256, elliptic.P256()
+	case "ES384":
+		hash, curve = crypto.SHA384, elliptic.P384()
+	case "ES512":
+		hash, curve = crypto.SHA512, elliptic.P521()
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if curve != nil {
+			break
+		}
+		if err := rsa.VerifyPKCS1v15(k, hash, digest, sig); err != nil {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if k.Curve != curve {
+			break
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return fmt.Errorf("invalid signature")
+		}
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("signing algorithm %q doesn't match the key", alg)
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a token into v.
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+// decodeInt decodes a base64url encoded big-endian integer.
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("empty integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// numericDate returns the time of a NumericDate claim.
+func numericDate(v interface{}) (time.Time, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
+
+// hasAudience returns whether the aud claim, a string or a list of strings, has the audience.
+func hasAudience(aud interface{}, audience string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == audience
+	case []interface{}:
+		for _, a := range v {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bearerToken returns the token of the value of an Authorization header, if it is the only one and
+// has the Bearer scheme.
+func bearerToken(values []string) (string, bool) {
+	const prefix = "bearer "
+	if len(values) != 1 || len(values[0]) <= len(prefix) || !strings.EqualFold(values[0][:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(values[0][len(prefix):]), true
+}
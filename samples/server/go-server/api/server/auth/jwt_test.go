@@ -0,0 +1,416 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "grafeas"
+)
+
+// testKey is a locally generated signing key.
+type testKey struct {
+	kid string
+	key crypto.Signer
+}
+
+func newRSAKey(t *testing.T, kid string) *testKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testKey{kid: kid, key: key}
+}
+
+func newECKey(t *testing.T, kid string) *testKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testKey{kid: kid, key: key}
+}
+
+// jwk returns the public JSON Web Key of k.
+func (k *testKey) jwk() map[string]string {
+	enc := base64.RawURLEncoding.EncodeToString
+	switch pub := k.key.Public().(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "kid": k.kid, "n": enc(pub.N.Bytes()), "e": enc(big.NewInt(int64(pub.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		return map[string]string{"kty": "EC", "kid": k.kid, "crv": "P-256", "x": enc(pub.X.Bytes()), "y": enc(pub.Y.Bytes())}
+	}
+	return nil
+}
+
+// sign returns a token with the claims signed by k with RS256 or ES256.
+func (k *testKey) sign(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+	alg := "RS256"
+	if _, ok := k.key.(*ecdsa.PrivateKey); ok {
+		alg = "ES256"
+	}
+	token := encodeSegment(t, map[string]string{"alg": alg, "typ": "JWT", "kid": k.kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(token))
+
+	var sig []byte
+	switch key := k.key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The signature is r and s, padded to the size of the curve.
+		sig = make([]byte, 64)
+		rb, sb := r.Bytes(), s.Bytes()
+		copy(sig[32-len(rb):32], rb)
+		copy(sig[64-len(sb):], sb)
+	}
+	return token + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// jwks returns the JSON Web Key Set of the keys.
+func jwks(t *testing.T, keys ...*testKey) []byte {
+	t.Helper()
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, k.jwk())
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// writeJWKS writes the JSON Web Key Set of the keys to a file in dir and returns its path.
+func writeJWKS(t *testing.T, dir string, keys ...*testKey) string {
+	t.Helper()
+	path := filepath.Join(dir, "jwks.json")
+	if err := ioutil.WriteFile(path, jwks(t, keys...), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// validClaims returns the claims of a valid token for the subject.
+func validClaims(sub string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss": testIssuer,
+		"aud": testAudience,
+		"sub": sub,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	rsaKey, ecKey := newRSAKey(t, "rsa"), newECKey(t, "ec")
+	a, err := NewJWTAuthenticator(JWTConfig{JWKS: writeJWKS(t, dir, rsaKey, ecKey), Issuer: testIssuer, Audience: testAudience})
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator got err %v, want success", err)
+	}
+
+	with := func(sub string, changes map[string]interface{}) map[string]interface{} {
+		claims := validClaims(sub)
+		for k, v := range changes {
+			if v == nil {
+				delete(claims, k)
+				continue
+			}
+			claims[k] = v
+		}
+		return claims
+	}
+	tests := []struct {
+		desc    string
+		token   string
+		want    *Caller
+		wantErr bool
+	}{
+		{
+			desc:  "RSA key",
+			token: rsaKey.sign(t, validClaims("alice")),
+			want:  &Caller{ID: "alice"},
+		},
+		{
+			desc:  "EC key with groups and several audiences",
+			token: ecKey.sign(t, with("ci", map[string]interface{}{"groups": []string{"release"}, "aud": []string{"other", testAudience}})),
+			want:  &Caller{ID: "ci", Groups: []string{"release"}},
+		},
+		{
+			desc:    "unknown key",
+			token:   newRSAKey(t, "other").sign(t, validClaims("alice")),
+			wantErr: true,
+		},
+		{
+			desc:    "signed by another key",
+			token:   newRSAKey(t, "rsa").sign(t, validClaims("alice")),
+			wantErr: true,
+		},
+		{
+			desc:    "unsigned",
+			token:   encodeSegment(t, map[string]string{"alg": "none", "kid": "rsa"}) + "." + encodeSegment(t, validClaims("alice")) + ".",
+			wantErr: true,
+		},
+		{
+			desc:    "wrong issuer",
+			token:   rsaKey.sign(t, with("alice", map[string]interface{}{"iss": "https://evil.example.com"})),
+			wantErr: true,
+		},
+		{
+			desc:    "wrong audience",
+			token:   rsaKey.sign(t, with("alice", map[string]interface{}{"aud": "other"})),
+			wantErr: true,
+		},
+		{
+			desc:    "expired",
+			token:   rsaKey.sign(t, with("alice", map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()})),
+			wantErr: true,
+		},
+		{
+			desc:    "no expiration",
+			token:   rsaKey.sign(t, with("alice", map[string]interface{}{"exp": nil})),
+			wantErr: true,
+		},
+		{
+			desc:    "not yet valid",
+			token:   rsaKey.sign(t, with("alice", map[string]interface{}{"nbf": time.Now().Add(time.Hour).Unix()})),
+			wantErr: true,
+		},
+		{
+			desc:    "no subject",
+			token:   rsaKey.sign(t, with("", nil)),
+			wantErr: true,
+		},
+		{
+			desc:    "invalid groups",
+			token:   rsaKey.sign(t, with("alice", map[string]interface{}{"groups": "release"})),
+			wantErr: true,
+		},
+		{
+			desc:    "malformed",
+			token:   "alice",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := a.Authenticate(tt.token)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: Authenticate got err %v, want error %t", tt.desc, err, tt.wantErr)
+			continue
+		}
+		if !cmp.Equal(got, tt.want) {
+			t.Errorf("%q: Authenticate got %+v, want %+v", tt.desc, got, tt.want)
+		}
+	}
+
+	// The claims identifying callers and listing their groups are configurable.
+	a, err = NewJWTAuthenticator(JWTConfig{JWKS: writeJWKS(t, dir, rsaKey), Issuer: testIssuer, Audience: testAudience, SubjectClaim: "email", GroupsClaim: "teams"})
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator got err %v, want success", err)
+	}
+	token := rsaKey.sign(t, with("1234", map[string]interface{}{"email": "alice@example.com", "teams": []string{"security"}}))
+	want := &Caller{ID: "alice@example.com", Groups: []string{"security"}}
+	if got, err := a.Authenticate(token); err != nil || !cmp.Equal(got, want) {
+		t.Errorf("Authenticate with custom claims got %+v, %v, want %+v", got, err, want)
+	}
+}
+
+func TestJWTAuthenticatorErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := writeJWKS(t, dir, newRSAKey(t, "rsa"))
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := ioutil.WriteFile(invalid, []byte(`{"keys": [{"kty": "EC", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for desc, config := range map[string]JWTConfig{
+		"no JWKS":      {Issuer: testIssuer, Audience: testAudience},
+		"no issuer":    {JWKS: path, Audience: testAudience},
+		"no audience":  {JWKS: path, Issuer: testIssuer},
+		"missing JWKS": {JWKS: filepath.Join(dir, "missing.json"), Issuer: testIssuer, Audience: testAudience},
+		"invalid JWKS": {JWKS: invalid, Issuer: testIssuer, Audience: testAudience},
+	} {
+		if _, err := NewJWTAuthenticator(config); err == nil {
+			t.Errorf("%q: NewJWTAuthenticator got success, want error", desc)
+		}
+	}
+}
+
+func TestJWTAuthenticatorURL(t *testing.T) {
+	defer func(old time.Duration) { jwksRefreshInterval = old }(jwksRefreshInterval)
+	jwksRefreshInterval = 0
+
+	oldKey, newKey := newRSAKey(t, "2019-01"), newECKey(t, "2019-02")
+	var (
+		mu   sync.Mutex
+		keys = []*testKey{oldKey}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write(jwks(t, keys...))
+	}))
+	defer srv.Close()
+
+	a, err := NewJWTAuthenticator(JWTConfig{JWKS: srv.URL, Issuer: testIssuer, Audience: testAudience})
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator got err %v, want success", err)
+	}
+	if _, err := a.Authenticate(oldKey.sign(t, validClaims("alice"))); err != nil {
+		t.Errorf("Authenticate got err %v, want success", err)
+	}
+	if _, err := a.Authenticate(newKey.sign(t, validClaims("alice"))); err == nil {
+		t.Error("Authenticate with a key not yet published got success, want error")
+	}
+
+	// Keys are refreshed when they are rotated.
+	mu.Lock()
+	keys = []*testKey{newKey}
+	mu.Unlock()
+	if _, err := a.Authenticate(newKey.sign(t, validClaims("alice"))); err != nil {
+		t.Errorf("Authenticate with a rotated key got err %v, want success", err)
+	}
+}
+
+func TestJWTInterceptors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	key := newRSAKey(t, "rsa")
+	a, err := NewJWTAuthenticator(JWTConfig{JWKS: writeJWKS(t, dir, key), Issuer: testIssuer, Audience: testAudience})
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator got err %v, want success", err)
+	}
+	valid := "Bearer " + key.sign(t, validClaims("alice"))
+	invalid := "Bearer " + newRSAKey(t, "rsa").sign(t, validClaims("alice"))
+
+	tests := []struct {
+		desc          string
+		authorization string
+		want          string
+		wantErrStatus codes.Code
+	}{
+		{desc: "valid token", authorization: valid, want: "alice"},
+		{desc: "invalid token", authorization: invalid, wantErrStatus: codes.Unauthenticated},
+		{desc: "no token"},
+		{desc: "other scheme", authorization: "Basic YWxpY2U6"},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+		}
+		var got string
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			if c, ok := FromContext(ctx); ok {
+				got = c.ID
+			}
+			return nil, nil
+		}
+		_, err := a.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		if status.Code(err) != tt.wantErrStatus || got != tt.want {
+			t.Errorf("%q: unary call got caller %q, err %v, want %q, %v", tt.desc, got, err, tt.want, tt.wantErrStatus)
+		}
+
+		got = ""
+		stream := func(srv interface{}, ss grpc.ServerStream) error {
+			_, err := handler(ss.Context(), nil)
+			return err
+		}
+		err = a.StreamInterceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, stream)
+		if status.Code(err) != tt.wantErrStatus || got != tt.want {
+			t.Errorf("%q: streaming call got caller %q, err %v, want %q, %v", tt.desc, got, err, tt.want, tt.wantErrStatus)
+		}
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/v1beta1/projects", nil)
+		if tt.authorization != "" {
+			r.Header.Set("Authorization", tt.authorization)
+		}
+		a.HTTPHandler(http.NotFoundHandler()).ServeHTTP(w, r)
+		wantCode := http.StatusNotFound
+		if tt.wantErrStatus == codes.Unauthenticated {
+			wantCode = http.StatusUnauthorized
+		}
+		if w.Code != wantCode {
+			t.Errorf("%q: HTTP request got status %d, want %d", tt.desc, w.Code, wantCode)
+		}
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
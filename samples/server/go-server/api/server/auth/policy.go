@@ -0,0 +1,385 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth implements the identification and authorization of the callers of the v1beta1 API.
+//
+// A policy file, in YAML or JSON, defines roles as lists of permissions and binds them to members
+// in every project, in a project, or on a single note or occurrence:
+//
+//	roles:
+//	  viewer: [notes.get, notes.list, occurrences.get, occurrences.list]
+//	  scanner: [occurrences.*, notes.get, notes.attachOccurrence]
+//	  attestor: [notes.attachOccurrence]
+//	bindings:
+//	- role: viewer
+//	  members: [allAuthenticatedUsers]
+//	- role: scanner
+//	  members: [scanner@example.com]
+//	  project: staging
+//	- role: attestor
+//	  members: [ci@example.com, group:release]
+//	  resource: projects/goog-vulnz/notes/CVE-2019-0001
+//
+// A permission ending in ".*" grants every permission on the resource type before it, and "*"
+// grants every permission. Members are caller IDs, groups of callers as "group:" followed by the
+// group name, "allUsers" for every caller including anonymous ones, or "allAuthenticatedUsers" for
+// every caller with an ID.
+//
+// Roles can also be granted on projects and notes with IAM policies set through the API, whose
+// bindings refer to the roles of the policy file as "roles/" followed by their name, e.g.
+// "roles/attestor".
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/grafeas/grafeas/go/etag"
+	"github.com/grafeas/grafeas/go/iam"
+	grafeas "github.com/grafeas/grafeas/go/v1beta1/api"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+// Special members.
+const (
+	AllUsers              = "allUsers"
+	AllAuthenticatedUsers = "allAuthenticatedUsers"
+)
+
+// GroupPrefix prefixes the names of groups of callers in members.
+const GroupPrefix = "group:"
+
+// rolePrefix prefixes the names of the roles of the policy file in IAM policies.
+const rolePrefix = "roles/"
+
+// Policy is the content of a policy file.
+type Policy struct {
+	// Roles maps role names to the permissions they grant.
+	Roles map[string][]iam.Permission `yaml:"roles"`
+	// Bindings grant roles to members.
+	Bindings []*Binding `yaml:"bindings"`
+}
+
+// Binding grants a role to members in every project, in a project or on a note or occurrence.
+type Binding struct {
+	Role    string   `yaml:"role"`
+	Members []string `yaml:"members"`
+	// Project is the ID of the project the role is granted in. If both it and Resource are empty,
+	// the role is granted in every project.
+	Project string `yaml:"project"`
+	// Resource is the name of the note or occurrence the role is granted on, e.g.
+	// "projects/goog-vulnz/notes/CVE-2019-0001".
+	Resource string `yaml:"resource"`
+}
+
+// ParsePolicy parses and validates a policy file.
+func ParsePolicy(data []byte) (*Policy, error) {
+	p := &Policy{}
+	if err := yaml.UnmarshalStrict(data, p); err != nil {
+		return nil, err
+	}
+	for role, perms := range p.Roles {
+		for _, perm := range perms {
+			if perm != "*" && !strings.Contains(string(perm), ".") {
+				return nil, fmt.Errorf("role %q: invalid permission %q", role, perm)
+			}
+		}
+	}
+	for i, b := range p.Bindings {
+		if _, ok := p.Roles[b.Role]; !ok {
+			return nil, fmt.Errorf("binding %d: unknown role %q", i, b.Role)
+		}
+		if b.Project != "" && b.Resource != "" {
+			return nil, fmt.Errorf("binding %d: only one of project and resource can be set", i)
+		}
+		if b.Resource != "" {
+			if _, _, err := parseResource(b.Resource); err != nil {
+				return nil, fmt.Errorf("binding %d: %v", i, err)
+			}
+		}
+	}
+	return p, nil
+}
+
+// Allows returns whether the policy grants member the permission in project pID, on the entity
+// entityID if it is set.
+func (p *Policy) Allows(member, pID, entityID string, perm iam.Permission) bool {
+	resource := ""
+	if entityID != "" {
+		resource = entityName(pID, entityID, perm)
+	}
+	for _, b := range p.Bindings {
+		switch {
+		case b.Resource != "":
+			if b.Resource != resource {
+				continue
+			}
+		case b.Project != "" && b.Project != pID:
+			continue
+		}
+		if hasMember(b.Members, member) && grants(p.Roles[b.Role], perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyStore stores the IAM policies of projects and notes. It is implemented by server.Storager.
+type PolicyStore interface {
+	GetIamPolicy(resource string) (*iampb.Policy, error)
+	SetIamPolicy(resource string, p *iampb.Policy, etag string) error
+	DeleteIamPolicy(resource string) error
+}
+
+// PolicyAuth authorizes calls with the policy of a policy file, which it reloads when the file
+// changes, and with the IAM policies of projects and notes. It implements the Auth and IAM
+// interfaces of the v1beta1 API.
+type PolicyAuth struct {
+	// Identity returns the ID of the caller, or "" for an anonymous caller. If nil, every caller is
+	// anonymous.
+	Identity func(ctx context.Context) (string, error)
+	// Groups returns the groups the identified caller is in. If nil, callers are in no groups.
+	Groups func(ctx context.Context) []string
+	// Roles returns the roles of the policy file granted to the identified caller in every
+	// project, e.g. by its API key. If nil, callers are granted roles by bindings only.
+	Roles func(ctx context.Context) []string
+	// Store stores the IAM policies of projects and notes. If nil, only the policy file applies and
+	// IAM policies can't be read or set.
+	Store PolicyStore
+
+	file *watchedFile
+}
+
+// NewPolicyAuth returns a PolicyAuth with the policy of the specified file.
+func NewPolicyAuth(path string) (*PolicyAuth, error) {
+	f, err := newWatchedFile(path, func(data []byte) (interface{}, error) {
+		p, err := ParsePolicy(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy file %q: %v", path, err)
+		}
+		return p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PolicyAuth{file: f}, nil
+}
+
+// CheckAccessAndProject checks that the caller has the permission in the project, on the entity
+// if it is set, and otherwise fails with a PermissionDenied error.
+func (a *PolicyAuth) CheckAccessAndProject(ctx context.Context, projectID string, entityID string, p iam.Permission) error {
+	member, err := a.EndUserID(ctx)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "failed to identify caller: %v", err)
+	}
+	members := []string{member}
+	var roles []string
+	if member != "" && a.Groups != nil {
+		for _, g := range a.Groups(ctx) {
+			members = append(members, GroupPrefix+g)
+		}
+	}
+	if member != "" && a.Roles != nil {
+		roles = a.Roles(ctx)
+	}
+	if !a.allows(members, roles, projectID, entityID, p) {
+		if entityID != "" {
+			return status.Errorf(codes.PermissionDenied, "permission %q denied on %q", p, entityName(projectID, entityID, p))
+		}
+		return status.Errorf(codes.PermissionDenied, "permission %q denied in project %q", p, projectID)
+	}
+	return nil
+}
+
+// EndUserID returns the ID of the caller.
+func (a *PolicyAuth) EndUserID(ctx context.Context) (string, error) {
+	if a.Identity == nil {
+		return "", nil
+	}
+	return a.Identity(ctx)
+}
+
+// PurgePolicy deletes the IAM policy of a deleted project or note. Policy files outlive the
+// entities they refer to.
+func (a *PolicyAuth) PurgePolicy(ctx context.Context, projectID string, entityID string, r iam.Resource) error {
+	if a.Store == nil {
+		return nil
+	}
+	var resource string
+	switch r {
+	case grafeas.Projects:
+		resource = name.FormatProject(projectID)
+	case grafeas.Notes:
+		resource = name.FormatNote(projectID, entityID)
+	default:
+		return nil
+	}
+	err := a.Store.DeleteIamPolicy(resource)
+	if status.Code(err) == codes.NotFound {
+		return nil
+	}
+	return err
+}
+
+// GetIamPolicy returns the IAM policy of the specified project or note, or an empty policy if it
+// has none.
+func (a *PolicyAuth) GetIamPolicy(ctx context.Context, resource string) (*iampb.Policy, error) {
+	if a.Store == nil {
+		return nil, status.Errorf(codes.Unimplemented, "IAM policies are not supported")
+	}
+	p, err := a.Store.GetIamPolicy(resource)
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.NotFound:
+		p = &iampb.Policy{}
+	default:
+		return nil, err
+	}
+	return withETag(p)
+}
+
+// SetIamPolicy replaces the IAM policy of the specified project or note. Its bindings must refer to
+// roles of the policy file.
+func (a *PolicyAuth) SetIamPolicy(ctx context.Context, resource string, p *iampb.Policy) (*iampb.Policy, error) {
+	if a.Store == nil {
+		return nil, status.Errorf(codes.Unimplemented, "IAM policies are not supported")
+	}
+	roles := a.current().Roles
+	for i, b := range p.Bindings {
+		if _, ok := roles[roleName(b.Role)]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "binding %d: unknown role %q", i, b.Role)
+		}
+	}
+	stored := proto.Clone(p).(*iampb.Policy)
+	stored.Etag = nil
+	if err := a.Store.SetIamPolicy(resource, stored, string(p.Etag)); err != nil {
+		return nil, err
+	}
+	return withETag(stored)
+}
+
+// allows returns whether any of the roles, or the policy file or the IAM policies of the project
+// and, for note permissions, of the note grant any of the members the permission.
+func (a *PolicyAuth) allows(members, roles []string, pID, entityID string, perm iam.Permission) bool {
+	file := a.current()
+	for _, r := range roles {
+		if grants(file.Roles[r], perm) {
+			return true
+		}
+	}
+	for _, m := range members {
+		if file.Allows(m, pID, entityID, perm) {
+			return true
+		}
+	}
+	// Calls that aren't scoped to a project, like listing projects, have no IAM policy to check.
+	if a.Store == nil || pID == "" {
+		return false
+	}
+	resources := []string{name.FormatProject(pID)}
+	if entityID != "" && strings.HasPrefix(string(perm), string(grafeas.Notes)+".") {
+		resources = append(resources, name.FormatNote(pID, entityID))
+	}
+	for _, r := range resources {
+		p, err := a.Store.GetIamPolicy(r)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to get the IAM policy of %q, ignoring it: %v", r, err)
+			continue
+		}
+		for _, b := range p.Bindings {
+			if !grants(file.Roles[roleName(b.Role)], perm) {
+				continue
+			}
+			for _, m := range members {
+				if hasMember(b.Members, m) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// current returns the current policy of the policy file.
+func (a *PolicyAuth) current() *Policy {
+	return a.file.current().(*Policy)
+}
+
+// roleName returns the name of the role of the policy file an IAM policy binding refers to, or ""
+// if it doesn't refer to one.
+func roleName(role string) string {
+	if !strings.HasPrefix(role, rolePrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(role, rolePrefix)
+}
+
+// withETag returns a copy of the stored policy p with its etag set.
+func withETag(p *iampb.Policy) (*iampb.Policy, error) {
+	tag, err := etag.Compute(p)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to compute etag: %v", err)
+	}
+	p = proto.Clone(p).(*iampb.Policy)
+	p.Etag = []byte(tag)
+	return p, nil
+}
+
+// entityName returns the name of the entity of the type the permission applies to.
+func entityName(pID, entityID string, p iam.Permission) string {
+	if strings.HasPrefix(string(p), "occurrences.") {
+		return name.FormatOccurrence(pID, entityID)
+	}
+	return name.FormatNote(pID, entityID)
+}
+
+// parseResource parses the name of a note or occurrence.
+func parseResource(r string) (string, string, error) {
+	if strings.Contains(r, "/occurrences/") {
+		return name.ParseOccurrence(r)
+	}
+	return name.ParseNote(r)
+}
+
+func hasMember(members []string, member string) bool {
+	for _, m := range members {
+		if m == member || m == AllUsers || (m == AllAuthenticatedUsers && member != "") {
+			return true
+		}
+	}
+	return false
+}
+
+// grants returns whether the permissions of a role include p.
+func grants(perms []iam.Permission, p iam.Permission) bool {
+	for _, perm := range perms {
+		if perm == p || perm == "*" {
+			return true
+		}
+		if strings.HasSuffix(string(perm), ".*") && strings.HasPrefix(string(p), strings.TrimSuffix(string(perm), "*")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,273 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/grafeas/grafeas/go/iam"
+	grafeas "github.com/grafeas/grafeas/go/v1beta1/api"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	_ grafeas.Auth = (*PolicyAuth)(nil)
+	_ grafeas.IAM  = (*PolicyAuth)(nil)
+	_ PolicyStore  = storage.NewMemStore()
+)
+
+const policy = `
+roles:
+  viewer: [notes.get, notes.list, occurrences.get, occurrences.list]
+  scanner: [occurrences.*, notes.get]
+  attestor: [notes.attachOccurrence]
+  admin: ["*"]
+bindings:
+- role: viewer
+  members: [allAuthenticatedUsers]
+- role: scanner
+  members: [scanner]
+  project: staging
+- role: attestor
+  members: [ci]
+  resource: projects/goog-vulnz/notes/CVE-1
+- role: admin
+  members: [root]
+- role: scanner
+  members: [group:ci]
+  project: prod
+`
+
+func TestPolicyAllows(t *testing.T) {
+	p, err := ParsePolicy([]byte(policy))
+	if err != nil {
+		t.Fatalf("ParsePolicy got err %v, want success", err)
+	}
+
+	tests := []struct {
+		member, pID, entityID string
+		perm                  iam.Permission
+		want                  bool
+	}{
+		{"alice", "staging", "", grafeas.NotesList, true},
+		{"alice", "staging", "CVE-1", grafeas.NotesGet, true},
+		{"", "staging", "", grafeas.NotesList, false},
+		{"alice", "staging", "", grafeas.NotesCreate, false},
+		{"scanner", "staging", "", grafeas.OccurrencesCreate, true},
+		{"scanner", "staging", "1234", grafeas.OccurrencesDelete, true},
+		{"scanner", "prod", "", grafeas.OccurrencesCreate, false},
+		{"scanner", "staging", "", grafeas.NotesCreate, false},
+		{"ci", "goog-vulnz", "CVE-1", grafeas.NotesAttachOccurrence, true},
+		{"ci", "goog-vulnz", "CVE-2", grafeas.NotesAttachOccurrence, false},
+		{"ci", "goog-vulnz", "", grafeas.NotesAttachOccurrence, false},
+		{"root", "prod", "1234", grafeas.OccurrencesDelete, true},
+	}
+	for _, tt := range tests {
+		if got := p.Allows(tt.member, tt.pID, tt.entityID, tt.perm); got != tt.want {
+			t.Errorf("Allows(%q, %q, %q, %q) got %t, want %t", tt.member, tt.pID, tt.entityID, tt.perm, got, tt.want)
+		}
+	}
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	for desc, p := range map[string]string{
+		"not YAML":             "roles: [",
+		"unknown field":        "roles: {}\nbinding: []",
+		"invalid permission":   "roles: {viewer: [get]}",
+		"unknown role":         "bindings: [{role: viewer, members: [alice]}]",
+		"project and resource": "roles: {viewer: [notes.get]}\nbindings: [{role: viewer, members: [alice], project: p, resource: projects/p/notes/n}]",
+		"invalid resource":     "roles: {viewer: [notes.get]}\nbindings: [{role: viewer, members: [alice], resource: notes/n}]",
+	} {
+		if _, err := ParsePolicy([]byte(p)); err == nil {
+			t.Errorf("%q: ParsePolicy got success, want error", desc)
+		}
+	}
+	// JSON policies are YAML too.
+	if _, err := ParsePolicy([]byte(`{"roles": {"viewer": ["notes.get"]}, "bindings": [{"role": "viewer", "members": ["alice"]}]}`)); err != nil {
+		t.Errorf("ParsePolicy of JSON got err %v, want success", err)
+	}
+}
+
+func TestPolicyAuth(t *testing.T) {
+	defer func(old time.Duration) { reloadInterval = old }(reloadInterval)
+	reloadInterval = 0
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policy.yaml")
+	if err := ioutil.WriteFile(path, []byte(policy), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewPolicyAuth(path)
+	if err != nil {
+		t.Fatalf("NewPolicyAuth got err %v, want success", err)
+	}
+	type userKey struct{}
+	a.Identity = func(ctx context.Context) (string, error) {
+		u, _ := ctx.Value(userKey{}).(string)
+		return u, nil
+	}
+	ctx := context.WithValue(context.Background(), userKey{}, "scanner")
+	if err := a.CheckAccessAndProject(ctx, "staging", "", grafeas.OccurrencesCreate); err != nil {
+		t.Errorf("CheckAccessAndProject got err %v, want success", err)
+	}
+	if u, err := a.EndUserID(ctx); u != "scanner" || err != nil {
+		t.Errorf("EndUserID got %q, %v, want %q", u, err, "scanner")
+	}
+
+	// Roles granted to groups are granted to their members.
+	a.Groups = func(ctx context.Context) []string {
+		if u, _ := ctx.Value(userKey{}).(string); u == "bob" {
+			return []string{"ci"}
+		}
+		return nil
+	}
+	if err := a.CheckAccessAndProject(context.WithValue(ctx, userKey{}, "bob"), "prod", "", grafeas.OccurrencesCreate); err != nil {
+		t.Errorf("CheckAccessAndProject by group member got err %v, want success", err)
+	}
+	err = a.CheckAccessAndProject(ctx, "prod", "", grafeas.OccurrencesCreate)
+	if c := status.Code(err); c != codes.PermissionDenied {
+		t.Errorf("CheckAccessAndProject by non group member got err %v, want %v", err, codes.PermissionDenied)
+	}
+
+	// Roles granted to callers, e.g. by their API key, apply in every project.
+	a.Roles = func(ctx context.Context) []string {
+		if u, _ := ctx.Value(userKey{}).(string); u == "ci" {
+			return []string{"attestor"}
+		}
+		return nil
+	}
+	if err := a.CheckAccessAndProject(context.WithValue(ctx, userKey{}, "ci"), "prod", "CVE-2", grafeas.NotesAttachOccurrence); err != nil {
+		t.Errorf("CheckAccessAndProject with granted role got err %v, want success", err)
+	}
+
+	// Invalid changes to the file are ignored, valid ones take effect.
+	if err := ioutil.WriteFile(path, []byte("roles: ["), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.CheckAccessAndProject(ctx, "staging", "", grafeas.OccurrencesCreate); err != nil {
+		t.Errorf("CheckAccessAndProject after invalid change got err %v, want success", err)
+	}
+	if err := ioutil.WriteFile(path, []byte("roles: {}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err = a.CheckAccessAndProject(ctx, "staging", "", grafeas.OccurrencesCreate)
+	if c := status.Code(err); c != codes.PermissionDenied {
+		t.Errorf("CheckAccessAndProject after reload got err %v, want %v", err, codes.PermissionDenied)
+	}
+
+	if _, err := NewPolicyAuth(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("NewPolicyAuth of a missing file got success, want error")
+	}
+}
+
+func TestPolicyAuthIamPolicies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policy.yaml")
+	if err := ioutil.WriteFile(path, []byte(policy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewPolicyAuth(path)
+	if err != nil {
+		t.Fatalf("NewPolicyAuth got err %v, want success", err)
+	}
+	a.Store = storage.NewMemStore()
+	type userKey struct{}
+	a.Identity = func(ctx context.Context) (string, error) {
+		u, _ := ctx.Value(userKey{}).(string)
+		return u, nil
+	}
+	ctx := context.WithValue(context.Background(), userKey{}, "ci")
+	note := "projects/goog-vulnz/notes/CVE-2"
+
+	if err := a.CheckAccessAndProject(ctx, "goog-vulnz", "CVE-2", grafeas.NotesAttachOccurrence); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("CheckAccessAndProject without IAM policy got err %v, want %v", err, codes.PermissionDenied)
+	}
+	empty, err := a.GetIamPolicy(ctx, note)
+	if err != nil || len(empty.Bindings) != 0 || len(empty.Etag) == 0 {
+		t.Fatalf("GetIamPolicy without IAM policy got %v, %v, want an empty policy with an etag", empty, err)
+	}
+
+	for _, role := range []string{"roles/unknown", "attestor"} {
+		p := &iampb.Policy{Bindings: []*iampb.Binding{{Role: role, Members: []string{"ci"}}}}
+		if _, err := a.SetIamPolicy(ctx, note, p); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("SetIamPolicy with role %q got err %v, want %v", role, err, codes.InvalidArgument)
+		}
+	}
+	p := &iampb.Policy{
+		Bindings: []*iampb.Binding{{Role: "roles/attestor", Members: []string{"ci"}}},
+		Etag:     empty.Etag,
+	}
+	set, err := a.SetIamPolicy(ctx, note, p)
+	if err != nil {
+		t.Fatalf("SetIamPolicy got err %v, want success", err)
+	}
+	if got, err := a.GetIamPolicy(ctx, note); err != nil || !proto.Equal(got, set) {
+		t.Errorf("GetIamPolicy got %v, %v, want %v", got, err, set)
+	}
+	if _, err := a.SetIamPolicy(ctx, note, p); status.Code(err) != codes.Aborted {
+		t.Errorf("SetIamPolicy with stale etag got err %v, want %v", err, codes.Aborted)
+	}
+
+	// The note's policy only grants permissions on the note.
+	if err := a.CheckAccessAndProject(ctx, "goog-vulnz", "CVE-2", grafeas.NotesAttachOccurrence); err != nil {
+		t.Errorf("CheckAccessAndProject with IAM policy got err %v, want success", err)
+	}
+	if err := a.CheckAccessAndProject(ctx, "goog-vulnz", "CVE-3", grafeas.NotesAttachOccurrence); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("CheckAccessAndProject of another note got err %v, want %v", err, codes.PermissionDenied)
+	}
+
+	// Project policies grant permissions on every entity of the project.
+	if _, err := a.SetIamPolicy(ctx, "projects/staging", &iampb.Policy{
+		Bindings: []*iampb.Binding{{Role: "roles/scanner", Members: []string{"ci"}}},
+	}); err != nil {
+		t.Fatalf("SetIamPolicy of project got err %v, want success", err)
+	}
+	if err := a.CheckAccessAndProject(ctx, "staging", "1234", grafeas.OccurrencesUpdate); err != nil {
+		t.Errorf("CheckAccessAndProject with project IAM policy got err %v, want success", err)
+	}
+
+	if err := a.PurgePolicy(ctx, "goog-vulnz", "CVE-2", grafeas.Notes); err != nil {
+		t.Fatalf("PurgePolicy got err %v, want success", err)
+	}
+	if err := a.PurgePolicy(ctx, "goog-vulnz", "CVE-2", grafeas.Notes); err != nil {
+		t.Errorf("PurgePolicy without IAM policy got err %v, want success", err)
+	}
+	if err := a.CheckAccessAndProject(ctx, "goog-vulnz", "CVE-2", grafeas.NotesAttachOccurrence); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("CheckAccessAndProject after PurgePolicy got err %v, want %v", err, codes.PermissionDenied)
+	}
+	if err := a.PurgePolicy(ctx, "staging", "", grafeas.Projects); err != nil {
+		t.Fatalf("PurgePolicy of project got err %v, want success", err)
+	}
+	if err := a.CheckAccessAndProject(ctx, "staging", "1234", grafeas.OccurrencesUpdate); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("CheckAccessAndProject after PurgePolicy of project got err %v, want %v", err, codes.PermissionDenied)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// reloadInterval is how often watched files are checked for changes.
+var reloadInterval = time.Second
+
+// watchedFile is a file whose parsed content is reloaded when it changes.
+type watchedFile struct {
+	path  string
+	parse func(data []byte) (interface{}, error)
+
+	mu      sync.Mutex
+	content interface{}
+	modTime time.Time
+	size    int64
+	checked time.Time
+}
+
+// newWatchedFile returns the watched file at path, whose content parse parses.
+func newWatchedFile(path string, parse func(data []byte) (interface{}, error)) (*watchedFile, error) {
+	f := &watchedFile{path: path, parse: parse}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.load(fi); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// current returns the current content, reloading the file if it changed. If the changed file can't
+// be loaded, the previous content stays in effect.
+func (f *watchedFile) current() interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if time.Since(f.checked) < reloadInterval {
+		return f.content
+	}
+	f.checked = time.Now()
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		log.Printf("Failed to check %q, keeping its previous content: %v", f.path, err)
+		return f.content
+	}
+	if fi.ModTime().Equal(f.modTime) && fi.Size() == f.size {
+		return f.content
+	}
+	if err := f.load(fi); err != nil {
+		log.Printf("Failed to reload %q, keeping its previous content: %v", f.path, err)
+		// Don't retry until the file changes again.
+		f.modTime, f.size = fi.ModTime(), fi.Size()
+	}
+	return f.content
+}
+
+// load loads the file, whose info is fi. It must be called with mu held or before f is shared.
+func (f *watchedFile) load(fi os.FileInfo) error {
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	content, err := f.parse(data)
+	if err != nil {
+		return err
+	}
+	f.content, f.modTime, f.size, f.checked = content, fi.ModTime(), fi.Size(), time.Now()
+	return nil
+}
@@ -0,0 +1,529 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bridge lets the sample server's storage implementations back the validating,
+// auth-checking v1beta1 API in go/v1beta1/api.
+package bridge
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/etag"
+	"github.com/grafeas/grafeas/go/filtering/eval"
+	"github.com/grafeas/grafeas/go/name"
+	grafeas "github.com/grafeas/grafeas/go/v1beta1/api"
+	"github.com/grafeas/grafeas/go/v1beta1/summary"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/fieldmask"
+	server "github.com/grafeas/grafeas/server-go"
+	"golang.org/x/net/context"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// listPageSize is the page size used to read through a store when it has to be listed in full.
+const listPageSize = 1000
+
+// maxUpdateAttempts is the number of times an update without an etag is attempted when the entity
+// keeps changing between being read and written.
+const maxUpdateAttempts = 3
+
+// Storage implements the API's storage, project, upserts, watch, operations, audit, revisions and
+// tombstones interfaces on top of a server.Storager. It fills in names and create and update times, applies update masks
+// and filters, and creates batches one entity at a time. The user ID of the caller isn't stored.
+type Storage struct {
+	S server.Storager
+}
+
+// New returns the API storage backed by the specified storager.
+func New(s server.Storager) *Storage {
+	return &Storage{S: s}
+}
+
+var (
+	_ grafeas.Storage        = (*Storage)(nil)
+	_ grafeas.ProjectStorage = (*Storage)(nil)
+	_ grafeas.Upserts        = (*Storage)(nil)
+	_ grafeas.Watch          = (*Storage)(nil)
+	_ grafeas.Operations     = (*Storage)(nil)
+	_ grafeas.Audit          = (*Storage)(nil)
+	_ grafeas.Revisions      = (*Storage)(nil)
+	_ grafeas.Tombstones     = (*Storage)(nil)
+)
+
+// GetOccurrence gets the specified occurrence from storage.
+func (s *Storage) GetOccurrence(ctx context.Context, pID, oID string) (*gpb.Occurrence, error) {
+	return s.S.GetOccurrence(pID, oID)
+}
+
+// ListOccurrences lists the occurrences of the specified project that match the filter.
+func (s *Storage) ListOccurrences(ctx context.Context, pID, filter, pageToken string, pageSize int32) ([]*gpb.Occurrence, string, error) {
+	f, err := eval.Compile(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	var os []*gpb.Occurrence
+	npt, err := listMatching(pageToken, pageSize, func(pageToken string, pageSize int) (string, error) {
+		page, npt, err := s.S.ListOccurrences(pID, filter, pageSize, pageToken)
+		if err != nil {
+			return "", err
+		}
+		for _, o := range page {
+			if ok, err := f.Matches(o); err != nil {
+				return "", err
+			} else if ok {
+				os = append(os, o)
+			}
+		}
+		return npt, nil
+	}, func() int { return len(os) })
+	if err != nil {
+		return nil, "", err
+	}
+	return os, npt, nil
+}
+
+// CreateOccurrence creates the specified occurrence in storage under a new ID.
+func (s *Storage) CreateOccurrence(ctx context.Context, pID, uID string, o *gpb.Occurrence) (*gpb.Occurrence, error) {
+	o, err := s.newOccurrence(pID, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.S.CreateOccurrence(o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// BatchCreateOccurrences creates the specified occurrences in storage, one at a time.
+func (s *Storage) BatchCreateOccurrences(ctx context.Context, pID, uID string, occs []*gpb.Occurrence) ([]*gpb.Occurrence, []error) {
+	return batchOccurrences(occs, func(o *gpb.Occurrence) (*gpb.Occurrence, error) {
+		return s.CreateOccurrence(ctx, pID, uID, o)
+	})
+}
+
+// UpsertOccurrence updates the occurrence for the same resource URI and note as the specified one
+// in place, or creates it under a new ID if there is none.
+func (s *Storage) UpsertOccurrence(ctx context.Context, pID, uID string, o *gpb.Occurrence) (*gpb.Occurrence, error) {
+	o, err := s.newOccurrence(pID, o)
+	if err != nil {
+		return nil, err
+	}
+	return s.S.UpsertOccurrence(o)
+}
+
+// BatchUpsertOccurrences upserts the specified occurrences in storage, one at a time.
+func (s *Storage) BatchUpsertOccurrences(ctx context.Context, pID, uID string, occs []*gpb.Occurrence) ([]*gpb.Occurrence, []error) {
+	return batchOccurrences(occs, func(o *gpb.Occurrence) (*gpb.Occurrence, error) {
+		return s.UpsertOccurrence(ctx, pID, uID, o)
+	})
+}
+
+// UpdateOccurrence updates the fields of the specified occurrence in the mask, or all of them if
+// the mask is empty. Its name and create time can't be changed.
+func (s *Storage) UpdateOccurrence(ctx context.Context, pID, oID string, o *gpb.Occurrence, mask *fieldmaskpb.FieldMask, etag string) (*gpb.Occurrence, error) {
+	var updated *gpb.Occurrence
+	read := func() (proto.Message, error) { return s.S.GetOccurrence(pID, oID) }
+	err := updateRead(etag, read, func(m proto.Message, tag string) error {
+		existing := m.(*gpb.Occurrence)
+		updated = proto.Clone(o).(*gpb.Occurrence)
+		if len(mask.GetPaths()) > 0 {
+			updated = proto.Clone(existing).(*gpb.Occurrence)
+			if err := fieldmask.Apply(updated, o, mask); err != nil {
+				return err
+			}
+		}
+		updated.Name, updated.CreateTime, updated.UpdateTime = existing.Name, existing.CreateTime, ptypes.TimestampNow()
+		return s.S.UpdateOccurrence(pID, oID, updated, tag)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteOccurrence deletes the specified occurrence in storage.
+func (s *Storage) DeleteOccurrence(ctx context.Context, pID, oID, etag string) error {
+	return s.S.DeleteOccurrence(pID, oID, etag)
+}
+
+// WatchOccurrences calls fn with the changes to the occurrences of the specified project that
+// match the filter.
+func (s *Storage) WatchOccurrences(ctx context.Context, pID, filter, cursor string, fn func(*watchpb.OccurrenceEvent) error) error {
+	f, err := eval.Compile(filter)
+	if err != nil {
+		return err
+	}
+	return s.S.WatchOccurrences(ctx, pID, cursor, func(e *watchpb.OccurrenceEvent) error {
+		if ok, err := f.Matches(e.Occurrence); err != nil || !ok {
+			return err
+		}
+		return fn(e)
+	})
+}
+
+// GetNote gets the specified note from storage.
+func (s *Storage) GetNote(ctx context.Context, pID, nID string) (*gpb.Note, error) {
+	return s.S.GetNote(pID, nID)
+}
+
+// ListNotes lists the notes of the specified project that match the filter.
+func (s *Storage) ListNotes(ctx context.Context, pID, filter, pageToken string, pageSize int32) ([]*gpb.Note, string, error) {
+	f, err := eval.Compile(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	var ns []*gpb.Note
+	npt, err := listMatching(pageToken, pageSize, func(pageToken string, pageSize int) (string, error) {
+		page, npt, err := s.S.ListNotes(pID, filter, pageSize, pageToken)
+		if err != nil {
+			return "", err
+		}
+		for _, n := range page {
+			if ok, err := f.Matches(n); err != nil {
+				return "", err
+			} else if ok {
+				ns = append(ns, n)
+			}
+		}
+		return npt, nil
+	}, func() int { return len(ns) })
+	if err != nil {
+		return nil, "", err
+	}
+	return ns, npt, nil
+}
+
+// CreateNote creates the specified note in storage under the specified ID.
+func (s *Storage) CreateNote(ctx context.Context, pID, nID, uID string, n *gpb.Note) (*gpb.Note, error) {
+	if _, err := s.S.GetProject(pID); err != nil {
+		return nil, errors.Newf(codes.NotFound, "project %q not found", pID)
+	}
+	n = proto.Clone(n).(*gpb.Note)
+	n.Name = name.FormatNote(pID, nID)
+	n.CreateTime = ptypes.TimestampNow()
+	n.UpdateTime = n.CreateTime
+	if err := s.S.CreateNote(n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// BatchCreateNotes creates the specified notes in storage, one at a time in order of their IDs.
+func (s *Storage) BatchCreateNotes(ctx context.Context, pID, uID string, notes map[string]*gpb.Note) ([]*gpb.Note, []error) {
+	nIDs := make([]string, 0, len(notes))
+	for nID := range notes {
+		nIDs = append(nIDs, nID)
+	}
+	sort.Strings(nIDs)
+
+	created := []*gpb.Note{}
+	errs := []error{}
+	for _, nID := range nIDs {
+		n, err := s.CreateNote(ctx, pID, nID, uID, notes[nID])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		created = append(created, n)
+	}
+	return created, errs
+}
+
+// UpdateNote updates the fields of the specified note in the mask, or all of them if the mask is
+// empty. Its name and create time can't be changed.
+func (s *Storage) UpdateNote(ctx context.Context, pID, nID string, n *gpb.Note, mask *fieldmaskpb.FieldMask, etag string) (*gpb.Note, error) {
+	var updated *gpb.Note
+	read := func() (proto.Message, error) { return s.S.GetNote(pID, nID) }
+	err := updateRead(etag, read, func(m proto.Message, tag string) error {
+		existing := m.(*gpb.Note)
+		updated = proto.Clone(n).(*gpb.Note)
+		if len(mask.GetPaths()) > 0 {
+			updated = proto.Clone(existing).(*gpb.Note)
+			if err := fieldmask.Apply(updated, n, mask); err != nil {
+				return err
+			}
+		}
+		updated.Name, updated.CreateTime, updated.UpdateTime = existing.Name, existing.CreateTime, ptypes.TimestampNow()
+		return s.S.UpdateNote(pID, nID, updated, tag)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// updateRead reads an entity with read and writes its update with write, conditional on the etag
+// tag. If the caller didn't send an etag, the write is conditional on the etag of the entity read,
+// so that an update made in between isn't silently overwritten, and is retried on a mismatch.
+func updateRead(tag string, read func() (proto.Message, error), write func(existing proto.Message, tag string) error) error {
+	for attempt := 1; ; attempt++ {
+		existing, err := read()
+		if err != nil {
+			return err
+		}
+		want := tag
+		if want == "" {
+			if want, err = etag.Compute(existing); err != nil {
+				return errors.Newf(codes.Internal, "failed to compute etag: %v", err)
+			}
+		}
+		err = write(existing, want)
+		if tag != "" || status.Code(err) != codes.Aborted {
+			return err
+		}
+		if attempt == maxUpdateAttempts {
+			return errors.Newf(codes.Aborted, "the resource kept being modified concurrently, retry the update")
+		}
+	}
+}
+
+// DeleteNote deletes the specified note in storage.
+func (s *Storage) DeleteNote(ctx context.Context, pID, nID, etag string) error {
+	return s.S.DeleteNote(pID, nID, etag)
+}
+
+// GetOccurrenceNote gets the note of the specified occurrence from storage.
+func (s *Storage) GetOccurrenceNote(ctx context.Context, pID, oID string) (*gpb.Note, error) {
+	return s.S.GetNoteByOccurrence(pID, oID)
+}
+
+// ListNoteOccurrences lists the occurrences of the specified note that match the filter.
+func (s *Storage) ListNoteOccurrences(ctx context.Context, pID, nID, filter, pageToken string, pageSize int32) ([]*gpb.Occurrence, string, error) {
+	f, err := eval.Compile(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	var os []*gpb.Occurrence
+	npt, err := listMatching(pageToken, pageSize, func(pageToken string, pageSize int) (string, error) {
+		page, npt, err := s.S.ListNoteOccurrences(pID, nID, filter, pageSize, pageToken)
+		if err != nil {
+			return "", err
+		}
+		for _, o := range page {
+			if ok, err := f.Matches(o); err != nil {
+				return "", err
+			} else if ok {
+				os = append(os, o)
+			}
+		}
+		return npt, nil
+	}, func() int { return len(os) })
+	if err != nil {
+		return nil, "", err
+	}
+	return os, npt, nil
+}
+
+// GetVulnerabilityOccurrencesSummary summarizes the vulnerability occurrences of the specified
+// project that match the filter.
+func (s *Storage) GetVulnerabilityOccurrencesSummary(ctx context.Context, pID, filter string) (*gpb.VulnerabilityOccurrencesSummary, error) {
+	return summary.FromPages(func(pageToken string) ([]*gpb.Occurrence, string, error) {
+		return s.ListOccurrences(ctx, pID, filter, pageToken, listPageSize)
+	})
+}
+
+// CreateOperation creates the specified operation in storage.
+func (s *Storage) CreateOperation(ctx context.Context, pID string, op *lrpb.Operation) error {
+	return s.S.CreateOperation(op)
+}
+
+// UpdateOperation updates the operation with the same name as the specified one in storage, unless
+// it is done.
+func (s *Storage) UpdateOperation(ctx context.Context, pID string, op *lrpb.Operation) error {
+	_, opID, err := name.ParseOperation(op.Name)
+	if err != nil {
+		return err
+	}
+	return s.S.UpdateOperation(pID, opID, op)
+}
+
+// RecordAuditEvent appends the specified event to the audit log of the specified project in
+// storage.
+func (s *Storage) RecordAuditEvent(ctx context.Context, pID string, e *auditpb.AuditEvent) error {
+	return s.S.AppendAuditEvent(pID, e)
+}
+
+// ListAuditEvents lists the events of the audit log of the specified project in storage.
+func (s *Storage) ListAuditEvents(ctx context.Context, pID, resource string, start, end time.Time, pageToken string, pageSize int32) ([]*auditpb.AuditEvent, string, error) {
+	return s.S.ListAuditEvents(pID, resource, start, end, int(pageSize), pageToken)
+}
+
+// ListNoteRevisions lists the previous revisions of the specified note in storage, newest first.
+func (s *Storage) ListNoteRevisions(ctx context.Context, pID, nID, pageToken string, pageSize int32) ([]*revpb.NoteRevision, string, error) {
+	if _, err := s.S.GetNote(pID, nID); err != nil {
+		return nil, "", err
+	}
+	return s.S.ListNoteRevisions(pID, nID, int(pageSize), pageToken)
+}
+
+// ListOccurrenceRevisions lists the previous revisions of the specified occurrence in storage,
+// newest first.
+func (s *Storage) ListOccurrenceRevisions(ctx context.Context, pID, oID, pageToken string, pageSize int32) ([]*revpb.OccurrenceRevision, string, error) {
+	if _, err := s.S.GetOccurrence(pID, oID); err != nil {
+		return nil, "", err
+	}
+	return s.S.ListOccurrenceRevisions(pID, oID, int(pageSize), pageToken)
+}
+
+// GetNoteAt gets the specified note as it was at time t: the oldest of its revisions replaced after
+// t or, if there is none, the note as it is now. The note didn't exist then if it was created after
+// t.
+func (s *Storage) GetNoteAt(ctx context.Context, pID, nID string, t time.Time) (*gpb.Note, error) {
+	at, err := s.S.GetNote(pID, nID)
+	if err != nil {
+		return nil, err
+	}
+	pageToken := ""
+	for {
+		revs, npt, err := s.S.ListNoteRevisions(pID, nID, listPageSize, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range revs {
+			rt, err := ptypes.Timestamp(r.ReplaceTime)
+			if err != nil {
+				return nil, errors.Newf(codes.Internal, "invalid replace time of a revision of note %q: %v", nID, err)
+			}
+			if !rt.After(t) {
+				return noteExistedAt(at, t)
+			}
+			at = r.Note
+		}
+		if npt == "" {
+			return noteExistedAt(at, t)
+		}
+		pageToken = npt
+	}
+}
+
+// noteExistedAt returns n, or a NotFound error if it was created after t.
+func noteExistedAt(n *gpb.Note, t time.Time) (*gpb.Note, error) {
+	if ct, err := ptypes.Timestamp(n.CreateTime); err == nil && ct.After(t) {
+		return nil, errors.Newf(codes.NotFound, "note %q didn't exist at %v", n.Name, t)
+	}
+	return n, nil
+}
+
+// CreateProject creates the specified project in storage.
+func (s *Storage) CreateProject(ctx context.Context, pID string, p *prpb.Project) (*prpb.Project, error) {
+	if err := s.S.CreateProject(pID); err != nil {
+		return nil, err
+	}
+	return &prpb.Project{Name: name.FormatProject(pID)}, nil
+}
+
+// GetProject gets the specified project from storage.
+func (s *Storage) GetProject(ctx context.Context, pID string) (*prpb.Project, error) {
+	return s.S.GetProject(pID)
+}
+
+// ListProjects lists projects from storage.
+func (s *Storage) ListProjects(ctx context.Context, filter, pageToken string, pageSize int32) ([]*prpb.Project, string, error) {
+	return s.S.ListProjects(filter, int(pageSize), pageToken)
+}
+
+// DeleteProject deletes the specified project in storage.
+func (s *Storage) DeleteProject(ctx context.Context, pID string) error {
+	return s.S.DeleteProject(pID)
+}
+
+// UndeleteProject restores the specified project in storage if it was deleted at or after
+// deletedAfter.
+func (s *Storage) UndeleteProject(ctx context.Context, pID string, deletedAfter time.Time) error {
+	return s.S.UndeleteProject(pID, deletedAfter)
+}
+
+// UndeleteNote restores the specified note in storage if it was deleted at or after deletedAfter.
+func (s *Storage) UndeleteNote(ctx context.Context, pID, nID string, deletedAfter time.Time) (*gpb.Note, error) {
+	if err := s.S.UndeleteNote(pID, nID, deletedAfter); err != nil {
+		return nil, err
+	}
+	return s.S.GetNote(pID, nID)
+}
+
+// PurgeDeleted permanently deletes the projects and notes deleted before the specified time from
+// storage.
+func (s *Storage) PurgeDeleted(ctx context.Context, before time.Time) ([]string, error) {
+	return s.S.PurgeDeleted(before)
+}
+
+// newOccurrence returns a copy of the specified occurrence to create in the specified project,
+// with a new name and create and update times. The project and the occurrence's note must exist.
+func (s *Storage) newOccurrence(pID string, o *gpb.Occurrence) (*gpb.Occurrence, error) {
+	if _, err := s.S.GetProject(pID); err != nil {
+		return nil, errors.Newf(codes.NotFound, "project %q not found", pID)
+	}
+	npID, nID, err := name.ParseNote(o.NoteName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.S.GetNote(npID, nID); err != nil {
+		return nil, errors.Newf(codes.NotFound, "note %q not found", o.NoteName)
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Newf(codes.Internal, "failed to generate occurrence ID: %v", err)
+	}
+	o = proto.Clone(o).(*gpb.Occurrence)
+	o.Name = name.FormatOccurrence(pID, id.String())
+	o.CreateTime = ptypes.TimestampNow()
+	o.UpdateTime = o.CreateTime
+	return o, nil
+}
+
+// batchOccurrences calls fn with each of the specified occurrences, and returns the results that
+// succeeded and the errors of those that didn't.
+func batchOccurrences(occs []*gpb.Occurrence, fn func(*gpb.Occurrence) (*gpb.Occurrence, error)) ([]*gpb.Occurrence, []error) {
+	created := []*gpb.Occurrence{}
+	errs := []error{}
+	for _, o := range occs {
+		c, err := fn(o)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		created = append(created, c)
+	}
+	return created, errs
+}
+
+// listMatching reads pages from a store that may ignore filters until pageSize matching items
+// have been collected or the store runs out. list is called with the page token and the number of
+// items still wanted, collects the matching items and returns the next page token, which is empty
+// after the last page; matched returns the number of items collected so far. Since list never reads
+// more items than are wanted, the returned page token continues right after the last one.
+func listMatching(pageToken string, pageSize int32, list func(pageToken string, pageSize int) (string, error), matched func() int) (string, error) {
+	for {
+		npt, err := list(pageToken, int(pageSize)-matched())
+		if err != nil || npt == "" {
+			return "", err
+		}
+		pageToken = npt
+		if matched() >= int(pageSize) {
+			return pageToken, nil
+		}
+	}
+}
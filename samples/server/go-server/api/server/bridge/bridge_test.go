@@ -0,0 +1,342 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bridge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/grafeas/grafeas/go/name"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	pkgpb "github.com/grafeas/grafeas/proto/v1beta1/package_go_proto"
+	vpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
+	server "github.com/grafeas/grafeas/server-go"
+	"golang.org/x/net/context"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newStorage(t *testing.T) *Storage {
+	t.Helper()
+	s := New(storage.NewMemStore())
+	for _, pID := range []string{"goog-vulnz", "consumer1"} {
+		if err := s.S.CreateProject(pID); err != nil {
+			t.Fatalf("CreateProject(%q) got err %v, want success", pID, err)
+		}
+	}
+	return s
+}
+
+func vulnzNote() *gpb.Note {
+	return &gpb.Note{
+		ShortDescription: "CVE-2014-9911",
+		Type: &gpb.Note_Vulnerability{
+			Vulnerability: &vpb.Vulnerability{Severity: vpb.Severity_HIGH},
+		},
+	}
+}
+
+func vulnzOcc(uri string, severity vpb.Severity) *gpb.Occurrence {
+	return &gpb.Occurrence{
+		Resource: &gpb.Resource{Uri: uri},
+		NoteName: "projects/goog-vulnz/notes/CVE-2014-9911",
+		Details: &gpb.Occurrence_Vulnerability{
+			Vulnerability: &vpb.Details{Severity: severity},
+		},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	if _, err := s.CreateNote(ctx, "unknown", "CVE-2014-9911", "", vulnzNote()); status.Code(err) != codes.NotFound {
+		t.Errorf("CreateNote in an unknown project got err %v, want %v", err, codes.NotFound)
+	}
+	n, err := s.CreateNote(ctx, "goog-vulnz", "CVE-2014-9911", "", vulnzNote())
+	if err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	if n.Name != "projects/goog-vulnz/notes/CVE-2014-9911" || n.CreateTime == nil || !proto.Equal(n.CreateTime, n.UpdateTime) {
+		t.Errorf("CreateNote got %v, want a name and equal create and update times", n)
+	}
+
+	o, err := s.CreateOccurrence(ctx, "consumer1", "", vulnzOcc("debian", vpb.Severity_HIGH))
+	if err != nil {
+		t.Fatalf("CreateOccurrence got err %v, want success", err)
+	}
+	if pID, _, err := name.ParseOccurrence(o.Name); err != nil || pID != "consumer1" {
+		t.Errorf("CreateOccurrence got name %q, want an occurrence of consumer1", o.Name)
+	}
+	if o.CreateTime == nil || !proto.Equal(o.CreateTime, o.UpdateTime) {
+		t.Errorf("CreateOccurrence got times %v and %v, want equal times", o.CreateTime, o.UpdateTime)
+	}
+	if got, err := s.GetOccurrence(ctx, "consumer1", o.Name[len("projects/consumer1/occurrences/"):]); err != nil || !proto.Equal(got, o) {
+		t.Errorf("GetOccurrence got %v, %v, want %v", got, err, o)
+	}
+
+	bad := vulnzOcc("alpine", vpb.Severity_LOW)
+	bad.NoteName = "projects/goog-vulnz/notes/unknown"
+	created, errs := s.BatchCreateOccurrences(ctx, "consumer1", "", []*gpb.Occurrence{vulnzOcc("alpine", vpb.Severity_LOW), bad})
+	if len(created) != 1 || len(errs) != 1 || status.Code(errs[0]) != codes.NotFound {
+		t.Errorf("BatchCreateOccurrences got %v, %v, want 1 occurrence and 1 NotFound error", created, errs)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	s := newStorage(t)
+	if _, err := s.CreateNote(ctx, "goog-vulnz", "CVE-2014-9911", "", vulnzNote()); err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	o, err := s.CreateOccurrence(ctx, "consumer1", "", vulnzOcc("debian", vpb.Severity_HIGH))
+	if err != nil {
+		t.Fatalf("CreateOccurrence got err %v, want success", err)
+	}
+	_, oID, _ := name.ParseOccurrence(o.Name)
+
+	// Only the fields in the mask are updated.
+	update := vulnzOcc("alpine", vpb.Severity_LOW)
+	update.Remediation = "upgrade"
+	got, err := s.UpdateOccurrence(ctx, "consumer1", oID, update, &fieldmaskpb.FieldMask{Paths: []string{"remediation", "vulnerability.severity"}}, "")
+	if err != nil {
+		t.Fatalf("UpdateOccurrence got err %v, want success", err)
+	}
+	if got.Name != o.Name || got.Resource.Uri != "debian" || got.Remediation != "upgrade" || got.GetVulnerability().Severity != vpb.Severity_LOW {
+		t.Errorf("UpdateOccurrence with a mask got %v, want the remediation and severity updated", got)
+	}
+	if !proto.Equal(got.CreateTime, o.CreateTime) || got.UpdateTime == nil {
+		t.Errorf("UpdateOccurrence got create time %v and update time %v, want create time %v kept", got.CreateTime, got.UpdateTime, o.CreateTime)
+	}
+
+	// Without a mask the whole occurrence is replaced, except its name and create time.
+	update.Name = "projects/consumer1/occurrences/other"
+	got, err = s.UpdateOccurrence(ctx, "consumer1", oID, update, nil, "")
+	if err != nil {
+		t.Fatalf("UpdateOccurrence got err %v, want success", err)
+	}
+	if got.Name != o.Name || got.Resource.Uri != "alpine" || !proto.Equal(got.CreateTime, o.CreateTime) {
+		t.Errorf("UpdateOccurrence without a mask got %v, want the occurrence replaced", got)
+	}
+
+	if _, err := s.UpdateOccurrence(ctx, "consumer1", oID, update, &fieldmaskpb.FieldMask{Paths: []string{"unknown"}}, ""); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdateOccurrence with an invalid mask got err %v, want %v", err, codes.InvalidArgument)
+	}
+	if _, err := s.UpdateOccurrence(ctx, "consumer1", oID, update, nil, "stale"); status.Code(err) != codes.Aborted {
+		t.Errorf("UpdateOccurrence with a stale etag got err %v, want %v", err, codes.Aborted)
+	}
+
+	// Setting a field of the details oneof replaces the other kind of details.
+	n, err := s.UpdateNote(ctx, "goog-vulnz", "CVE-2014-9911", &gpb.Note{Type: &gpb.Note_Package{Package: &pkgpb.Package{Name: "icu"}}}, &fieldmaskpb.FieldMask{Paths: []string{"package", "long_description"}}, "")
+	if err != nil {
+		t.Fatalf("UpdateNote got err %v, want success", err)
+	}
+	if n.GetPackage().GetName() != "icu" || n.GetVulnerability() != nil || n.ShortDescription != "CVE-2014-9911" {
+		t.Errorf("UpdateNote got %v, want a package note with the same short description", n)
+	}
+}
+
+// racingStorager updates a note's long description after the first races reads of it, as a
+// concurrent caller would.
+type racingStorager struct {
+	server.Storager
+	races int
+}
+
+func (s *racingStorager) GetNote(pID, nID string) (*gpb.Note, error) {
+	n, err := s.Storager.GetNote(pID, nID)
+	if err != nil || s.races == 0 {
+		return n, err
+	}
+	s.races--
+	concurrent := proto.Clone(n).(*gpb.Note)
+	concurrent.LongDescription += "concurrent "
+	return n, s.Storager.UpdateNote(pID, nID, concurrent, "")
+}
+
+func TestMaskedUpdateConcurrentWithOtherUpdates(t *testing.T) {
+	ctx := context.Background()
+	r := &racingStorager{Storager: storage.NewMemStore()}
+	s := New(r)
+	if err := r.CreateProject("goog-vulnz"); err != nil {
+		t.Fatalf("CreateProject got err %v, want success", err)
+	}
+	if _, err := s.CreateNote(ctx, "goog-vulnz", "CVE-2014-9911", "", vulnzNote()); err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"short_description"}}
+
+	// Updates without an etag are retried rather than overwriting the concurrent update.
+	r.races = maxUpdateAttempts - 1
+	if _, err := s.UpdateNote(ctx, "goog-vulnz", "CVE-2014-9911", &gpb.Note{ShortDescription: "updated"}, mask, ""); err != nil {
+		t.Fatalf("UpdateNote got err %v, want success", err)
+	}
+	n, err := s.GetNote(ctx, "goog-vulnz", "CVE-2014-9911")
+	if err != nil {
+		t.Fatalf("GetNote got err %v, want success", err)
+	}
+	if n.ShortDescription != "updated" || n.LongDescription != "concurrent concurrent " {
+		t.Errorf("UpdateNote got %v, want both the update and the concurrent ones", n)
+	}
+
+	r.races = maxUpdateAttempts
+	if _, err := s.UpdateNote(ctx, "goog-vulnz", "CVE-2014-9911", &gpb.Note{ShortDescription: "lost"}, mask, ""); status.Code(err) != codes.Aborted {
+		t.Errorf("UpdateNote racing with every attempt got err %v, want %v", err, codes.Aborted)
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	ctx := context.Background()
+	s := newStorage(t)
+	beforeCreate := time.Now()
+	if _, err := s.CreateNote(ctx, "goog-vulnz", "CVE-2014-9911", "", vulnzNote()); err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	created := time.Now()
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"vulnerability.severity"}}
+	low := &gpb.Note{Type: &gpb.Note_Vulnerability{Vulnerability: &vpb.Vulnerability{Severity: vpb.Severity_LOW}}}
+	if _, err := s.UpdateNote(ctx, "goog-vulnz", "CVE-2014-9911", low, mask, ""); err != nil {
+		t.Fatalf("UpdateNote got err %v, want success", err)
+	}
+
+	revs, _, err := s.ListNoteRevisions(ctx, "goog-vulnz", "CVE-2014-9911", "", 10)
+	if err != nil {
+		t.Fatalf("ListNoteRevisions got err %v, want success", err)
+	}
+	if len(revs) != 1 || revs[0].Note.GetVulnerability().Severity != vpb.Severity_HIGH || revs[0].ReplaceTime == nil {
+		t.Errorf("ListNoteRevisions got %v, want the revision before the update", revs)
+	}
+	if _, _, err := s.ListNoteRevisions(ctx, "goog-vulnz", "CVE-0000-0000", "", 10); status.Code(err) != codes.NotFound {
+		t.Errorf("ListNoteRevisions of a missing note got err %v, want %v", err, codes.NotFound)
+	}
+
+	tests := []struct {
+		desc          string
+		t             time.Time
+		want          vpb.Severity
+		wantErrStatus codes.Code
+	}{
+		{"before the note was created", beforeCreate.Add(-time.Second), 0, codes.NotFound},
+		{"before the update", created, vpb.Severity_HIGH, codes.OK},
+		{"after the update", time.Now(), vpb.Severity_LOW, codes.OK},
+	}
+	for _, tt := range tests {
+		n, err := s.GetNoteAt(ctx, "goog-vulnz", "CVE-2014-9911", tt.t)
+		if status.Code(err) != tt.wantErrStatus {
+			t.Errorf("%q: GetNoteAt got err %v, want %v", tt.desc, err, tt.wantErrStatus)
+			continue
+		}
+		if err == nil && n.GetVulnerability().Severity != tt.want {
+			t.Errorf("%q: GetNoteAt got severity %v, want %v", tt.desc, n.GetVulnerability().Severity, tt.want)
+		}
+	}
+}
+
+func TestUndeleteNote(t *testing.T) {
+	ctx := context.Background()
+	s := newStorage(t)
+	created, err := s.CreateNote(ctx, "goog-vulnz", "CVE-2014-9911", "", vulnzNote())
+	if err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	if err := s.DeleteNote(ctx, "goog-vulnz", "CVE-2014-9911", ""); err != nil {
+		t.Fatalf("DeleteNote got err %v, want success", err)
+	}
+	n, err := s.UndeleteNote(ctx, "goog-vulnz", "CVE-2014-9911", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("UndeleteNote got err %v, want success", err)
+	}
+	if !proto.Equal(n, created) {
+		t.Errorf("UndeleteNote got %v, want %v", n, created)
+	}
+	if purged, err := s.PurgeDeleted(ctx, time.Now()); err != nil || len(purged) != 0 {
+		t.Errorf("PurgeDeleted got %v, %v, want nothing purged", purged, err)
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	ctx := context.Background()
+	s := newStorage(t)
+	if _, err := s.CreateNote(ctx, "goog-vulnz", "CVE-2014-9911", "", vulnzNote()); err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	for _, uri := range []string{"debian", "alpine", "debian", "alpine", "debian"} {
+		if _, err := s.CreateOccurrence(ctx, "consumer1", "", vulnzOcc(uri, vpb.Severity_HIGH)); err != nil {
+			t.Fatalf("CreateOccurrence got err %v, want success", err)
+		}
+	}
+
+	// Pages hold up to the page size of matching occurrences, however they are spread in the store.
+	var got []*gpb.Occurrence
+	pageToken := ""
+	for i := 0; ; i++ {
+		if i > 5 {
+			t.Fatalf("ListOccurrences didn't finish after %d pages", i)
+		}
+		page, npt, err := s.ListOccurrences(ctx, "consumer1", `resource.uri = "debian"`, pageToken, 2)
+		if err != nil {
+			t.Fatalf("ListOccurrences got err %v, want success", err)
+		}
+		if len(page) > 2 {
+			t.Errorf("ListOccurrences got %d occurrences, want at most 2", len(page))
+		}
+		got = append(got, page...)
+		if npt == "" {
+			break
+		}
+		pageToken = npt
+	}
+	if len(got) != 3 {
+		t.Errorf("ListOccurrences got %d occurrences, want 3", len(got))
+	}
+	for _, o := range got {
+		if o.Resource.Uri != "debian" {
+			t.Errorf("ListOccurrences got occurrence on %q, want only debian", o.Resource.Uri)
+		}
+	}
+
+	if _, _, err := s.ListOccurrences(ctx, "consumer1", "resource.uri = (", "", 2); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListOccurrences with an invalid filter got err %v, want %v", err, codes.InvalidArgument)
+	}
+
+	summary, err := s.GetVulnerabilityOccurrencesSummary(ctx, "consumer1", `resource.uri = "alpine"`)
+	if err != nil {
+		t.Fatalf("GetVulnerabilityOccurrencesSummary got err %v, want success", err)
+	}
+	if len(summary.Counts) != 2 || summary.Counts[0].TotalCount != 2 {
+		t.Errorf("GetVulnerabilityOccurrencesSummary got %v, want 2 alpine occurrences", summary)
+	}
+}
+
+func TestOperations(t *testing.T) {
+	ctx := context.Background()
+	s := newStorage(t)
+	op := &lrpb.Operation{Name: name.FormatOperation("consumer1", "op1")}
+	if err := s.CreateOperation(ctx, "consumer1", op); err != nil {
+		t.Fatalf("CreateOperation got err %v, want success", err)
+	}
+	op.Done = true
+	if err := s.UpdateOperation(ctx, "consumer1", op); err != nil {
+		t.Fatalf("UpdateOperation got err %v, want success", err)
+	}
+	got, err := s.S.GetOperation("consumer1", "op1")
+	if err != nil || !got.Done {
+		t.Errorf("GetOperation got %v, %v, want a done operation", got, err)
+	}
+}
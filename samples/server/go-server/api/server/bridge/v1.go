@@ -0,0 +1,314 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bridge
+
+import (
+	"strings"
+
+	"github.com/grafeas/grafeas/go/convert"
+	"github.com/grafeas/grafeas/go/etag"
+	"github.com/grafeas/grafeas/go/filtering/eval"
+	grafeasv1 "github.com/grafeas/grafeas/go/v1/api"
+	grafeas "github.com/grafeas/grafeas/go/v1beta1/api"
+	"github.com/grafeas/grafeas/go/v1beta1/summary"
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/fieldmask"
+	"golang.org/x/net/context"
+	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
+)
+
+// occurrenceFieldsV1Beta1 maps the top level fields of v1 occurrences to those of v1beta1 where
+// their names differ.
+var occurrenceFieldsV1Beta1 = map[string]string{
+	"resource_uri": "resource.uri",
+	"image":        "derived_image",
+	"package":      "installation",
+	"discovery":    "discovered",
+}
+
+// noteFieldsV1Beta1 maps the top level fields of v1 notes to those of v1beta1 where their names
+// differ.
+var noteFieldsV1Beta1 = map[string]string{
+	"image":       "base_image",
+	"deployment":  "deployable",
+	"attestation": "attestation_authority",
+}
+
+// V1Storage implements the v1 API's storage interface on top of a v1beta1 API storage, converting
+// notes and occurrences between the two versions with the convert package. Filters and update
+// masks apply to the v1 form of the entities. Updates only replace the v1beta1 fields touched by
+// the mask, so fields without a v1 counterpart survive updates of other fields.
+type V1Storage struct {
+	S grafeas.Storage
+}
+
+// NewV1 returns the v1 API storage backed by the specified v1beta1 API storage.
+func NewV1(s grafeas.Storage) *V1Storage {
+	return &V1Storage{S: s}
+}
+
+var _ grafeasv1.Storage = (*V1Storage)(nil)
+
+// GetOccurrence gets the specified occurrence from storage.
+func (s *V1Storage) GetOccurrence(ctx context.Context, pID, oID string) (*v1pb.Occurrence, error) {
+	o, err := s.S.GetOccurrence(ctx, pID, oID)
+	if err != nil {
+		return nil, err
+	}
+	return convert.OccurrenceToV1(o), nil
+}
+
+// ListOccurrences lists the occurrences of the specified project that match the filter.
+func (s *V1Storage) ListOccurrences(ctx context.Context, pID, filter, pageToken string, pageSize int32) ([]*v1pb.Occurrence, string, error) {
+	os, npt, err := listV1Occurrences(filter, pageToken, pageSize, func(pageToken string, pageSize int) ([]*gpb.Occurrence, string, error) {
+		return s.S.ListOccurrences(ctx, pID, "", pageToken, int32(pageSize))
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return occurrencesToV1(os), npt, nil
+}
+
+// CreateOccurrence creates the specified occurrence in storage.
+func (s *V1Storage) CreateOccurrence(ctx context.Context, pID, uID string, o *v1pb.Occurrence) (*v1pb.Occurrence, error) {
+	created, err := s.S.CreateOccurrence(ctx, pID, uID, convert.OccurrenceToV1Beta1(o))
+	if err != nil {
+		return nil, err
+	}
+	return convert.OccurrenceToV1(created), nil
+}
+
+// BatchCreateOccurrences creates the specified occurrences in storage.
+func (s *V1Storage) BatchCreateOccurrences(ctx context.Context, pID, uID string, occs []*v1pb.Occurrence) ([]*v1pb.Occurrence, []error) {
+	betaOccs := make([]*gpb.Occurrence, 0, len(occs))
+	for _, o := range occs {
+		betaOccs = append(betaOccs, convert.OccurrenceToV1Beta1(o))
+	}
+	created, errs := s.S.BatchCreateOccurrences(ctx, pID, uID, betaOccs)
+	return occurrencesToV1(created), errs
+}
+
+// UpdateOccurrence updates the fields of the specified occurrence in the mask, or all of them if
+// the mask is empty.
+func (s *V1Storage) UpdateOccurrence(ctx context.Context, pID, oID string, o *v1pb.Occurrence, mask *fieldmaskpb.FieldMask) (*v1pb.Occurrence, error) {
+	existing, err := s.S.GetOccurrence(ctx, pID, oID)
+	if err != nil {
+		return nil, err
+	}
+	var betaMask *fieldmaskpb.FieldMask
+	if len(mask.GetPaths()) > 0 {
+		updated := convert.OccurrenceToV1(existing)
+		if err := fieldmask.Apply(updated, o, mask); err != nil {
+			return nil, err
+		}
+		o, betaMask = updated, v1Beta1Mask(mask, occurrenceFieldsV1Beta1)
+	}
+	// The existing occurrence's etag makes the update fail if it changed since it was read.
+	tag, err := etag.Compute(existing)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.S.UpdateOccurrence(ctx, pID, oID, convert.OccurrenceToV1Beta1(o), betaMask, tag)
+	if err != nil {
+		return nil, err
+	}
+	return convert.OccurrenceToV1(updated), nil
+}
+
+// DeleteOccurrence deletes the specified occurrence in storage.
+func (s *V1Storage) DeleteOccurrence(ctx context.Context, pID, oID string) error {
+	return s.S.DeleteOccurrence(ctx, pID, oID, "")
+}
+
+// GetNote gets the specified note from storage.
+func (s *V1Storage) GetNote(ctx context.Context, pID, nID string) (*v1pb.Note, error) {
+	n, err := s.S.GetNote(ctx, pID, nID)
+	if err != nil {
+		return nil, err
+	}
+	return convert.NoteToV1(n), nil
+}
+
+// ListNotes lists the notes of the specified project that match the filter.
+func (s *V1Storage) ListNotes(ctx context.Context, pID, filter, pageToken string, pageSize int32) ([]*v1pb.Note, string, error) {
+	f, err := eval.Compile(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	var ns []*v1pb.Note
+	npt, err := listMatching(pageToken, pageSize, func(pageToken string, pageSize int) (string, error) {
+		page, npt, err := s.S.ListNotes(ctx, pID, "", pageToken, int32(pageSize))
+		if err != nil {
+			return "", err
+		}
+		for _, n := range page {
+			v1n := convert.NoteToV1(n)
+			if ok, err := f.Matches(v1n); err != nil {
+				return "", err
+			} else if ok {
+				ns = append(ns, v1n)
+			}
+		}
+		return npt, nil
+	}, func() int { return len(ns) })
+	if err != nil {
+		return nil, "", err
+	}
+	return ns, npt, nil
+}
+
+// CreateNote creates the specified note in storage.
+func (s *V1Storage) CreateNote(ctx context.Context, pID, nID, uID string, n *v1pb.Note) (*v1pb.Note, error) {
+	created, err := s.S.CreateNote(ctx, pID, nID, uID, convert.NoteToV1Beta1(n))
+	if err != nil {
+		return nil, err
+	}
+	return convert.NoteToV1(created), nil
+}
+
+// BatchCreateNotes creates the specified notes in storage.
+func (s *V1Storage) BatchCreateNotes(ctx context.Context, pID, uID string, notes map[string]*v1pb.Note) ([]*v1pb.Note, []error) {
+	betaNotes := make(map[string]*gpb.Note, len(notes))
+	for nID, n := range notes {
+		betaNotes[nID] = convert.NoteToV1Beta1(n)
+	}
+	created, errs := s.S.BatchCreateNotes(ctx, pID, uID, betaNotes)
+	ns := make([]*v1pb.Note, 0, len(created))
+	for _, n := range created {
+		ns = append(ns, convert.NoteToV1(n))
+	}
+	return ns, errs
+}
+
+// UpdateNote updates the fields of the specified note in the mask, or all of them if the mask is
+// empty.
+func (s *V1Storage) UpdateNote(ctx context.Context, pID, nID string, n *v1pb.Note, mask *fieldmaskpb.FieldMask) (*v1pb.Note, error) {
+	existing, err := s.S.GetNote(ctx, pID, nID)
+	if err != nil {
+		return nil, err
+	}
+	var betaMask *fieldmaskpb.FieldMask
+	if len(mask.GetPaths()) > 0 {
+		updated := convert.NoteToV1(existing)
+		if err := fieldmask.Apply(updated, n, mask); err != nil {
+			return nil, err
+		}
+		n, betaMask = updated, v1Beta1Mask(mask, noteFieldsV1Beta1)
+	}
+	tag, err := etag.Compute(existing)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.S.UpdateNote(ctx, pID, nID, convert.NoteToV1Beta1(n), betaMask, tag)
+	if err != nil {
+		return nil, err
+	}
+	return convert.NoteToV1(updated), nil
+}
+
+// DeleteNote deletes the specified note in storage.
+func (s *V1Storage) DeleteNote(ctx context.Context, pID, nID string) error {
+	return s.S.DeleteNote(ctx, pID, nID, "")
+}
+
+// GetOccurrenceNote gets the note of the specified occurrence from storage.
+func (s *V1Storage) GetOccurrenceNote(ctx context.Context, pID, oID string) (*v1pb.Note, error) {
+	n, err := s.S.GetOccurrenceNote(ctx, pID, oID)
+	if err != nil {
+		return nil, err
+	}
+	return convert.NoteToV1(n), nil
+}
+
+// ListNoteOccurrences lists the occurrences of the specified note that match the filter.
+func (s *V1Storage) ListNoteOccurrences(ctx context.Context, pID, nID, filter, pageToken string, pageSize int32) ([]*v1pb.Occurrence, string, error) {
+	os, npt, err := listV1Occurrences(filter, pageToken, pageSize, func(pageToken string, pageSize int) ([]*gpb.Occurrence, string, error) {
+		return s.S.ListNoteOccurrences(ctx, pID, nID, "", pageToken, int32(pageSize))
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return occurrencesToV1(os), npt, nil
+}
+
+// GetVulnerabilityOccurrencesSummary summarizes the vulnerability occurrences of the specified
+// project that match the filter.
+func (s *V1Storage) GetVulnerabilityOccurrencesSummary(ctx context.Context, pID, filter string) (*v1pb.VulnerabilityOccurrencesSummary, error) {
+	sum, err := summary.FromPages(func(pageToken string) ([]*gpb.Occurrence, string, error) {
+		return listV1Occurrences(filter, pageToken, listPageSize, func(pageToken string, pageSize int) ([]*gpb.Occurrence, string, error) {
+			return s.S.ListOccurrences(ctx, pID, "", pageToken, int32(pageSize))
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convert.VulnerabilityOccurrencesSummaryToV1(sum), nil
+}
+
+// listV1Occurrences reads pages of v1beta1 occurrences with list, and returns those whose v1 form
+// matches the filter.
+func listV1Occurrences(filter, pageToken string, pageSize int32, list func(pageToken string, pageSize int) ([]*gpb.Occurrence, string, error)) ([]*gpb.Occurrence, string, error) {
+	f, err := eval.Compile(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	var os []*gpb.Occurrence
+	npt, err := listMatching(pageToken, pageSize, func(pageToken string, pageSize int) (string, error) {
+		page, npt, err := list(pageToken, pageSize)
+		if err != nil {
+			return "", err
+		}
+		for _, o := range page {
+			if ok, err := f.Matches(convert.OccurrenceToV1(o)); err != nil {
+				return "", err
+			} else if ok {
+				os = append(os, o)
+			}
+		}
+		return npt, nil
+	}, func() int { return len(os) })
+	if err != nil {
+		return nil, "", err
+	}
+	return os, npt, nil
+}
+
+func occurrencesToV1(occs []*gpb.Occurrence) []*v1pb.Occurrence {
+	out := make([]*v1pb.Occurrence, 0, len(occs))
+	for _, o := range occs {
+		out = append(out, convert.OccurrenceToV1(o))
+	}
+	return out
+}
+
+// v1Beta1Mask returns the v1beta1 mask replacing the top level fields touched by the specified v1
+// mask. Fields are renamed according to fields, and paths into details are widened to the whole
+// details as their structure differs between the versions.
+func v1Beta1Mask(mask *fieldmaskpb.FieldMask, fields map[string]string) *fieldmaskpb.FieldMask {
+	out := &fieldmaskpb.FieldMask{}
+	seen := map[string]bool{}
+	for _, p := range mask.GetPaths() {
+		f := strings.Split(p, ".")[0]
+		if betaF, ok := fields[f]; ok {
+			f = betaF
+		}
+		if !seen[f] {
+			seen[f] = true
+			out.Paths = append(out.Paths, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bridge
+
+import (
+	"testing"
+
+	"github.com/grafeas/grafeas/go/name"
+	v1pb "github.com/grafeas/grafeas/proto/v1/grafeas_go_proto"
+	"golang.org/x/net/context"
+	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func v1VulnzOcc(uri string, severity v1pb.Severity) *v1pb.Occurrence {
+	return &v1pb.Occurrence{
+		ResourceUri: uri,
+		NoteName:    "projects/goog-vulnz/notes/CVE-2014-9911",
+		Details: &v1pb.Occurrence_Vulnerability{
+			Vulnerability: &v1pb.VulnerabilityOccurrence{Severity: severity},
+		},
+	}
+}
+
+func TestV1(t *testing.T) {
+	ctx := context.Background()
+	beta := newStorage(t)
+	s := NewV1(beta)
+
+	n, err := s.CreateNote(ctx, "goog-vulnz", "CVE-2014-9911", "", &v1pb.Note{
+		ShortDescription: "CVE-2014-9911",
+		Type:             &v1pb.Note_Vulnerability{Vulnerability: &v1pb.VulnerabilityNote{Severity: v1pb.Severity_HIGH}},
+	})
+	if err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	if n.Name != "projects/goog-vulnz/notes/CVE-2014-9911" || n.GetVulnerability().GetSeverity() != v1pb.Severity_HIGH {
+		t.Errorf("CreateNote got %v, want a named vulnerability note", n)
+	}
+
+	for _, uri := range []string{"debian", "alpine", "debian"} {
+		if _, err := s.CreateOccurrence(ctx, "consumer1", "", v1VulnzOcc(uri, v1pb.Severity_HIGH)); err != nil {
+			t.Fatalf("CreateOccurrence got err %v, want success", err)
+		}
+	}
+	// Occurrences created through v1beta1 are visible through v1.
+	betaOcc, err := beta.CreateOccurrence(ctx, "consumer1", "", vulnzOcc("alpine", 0))
+	if err != nil {
+		t.Fatalf("CreateOccurrence got err %v, want success", err)
+	}
+
+	// Filters apply to the v1 form of occurrences.
+	os, _, err := s.ListOccurrences(ctx, "consumer1", `resource_uri = "debian"`, "", 10)
+	if err != nil {
+		t.Fatalf("ListOccurrences got err %v, want success", err)
+	}
+	if len(os) != 2 {
+		t.Errorf("ListOccurrences got %d occurrences, want 2", len(os))
+	}
+	os, _, err = s.ListNoteOccurrences(ctx, "goog-vulnz", "CVE-2014-9911", `resource_uri = "alpine"`, "", 10)
+	if err != nil {
+		t.Fatalf("ListNoteOccurrences got err %v, want success", err)
+	}
+	if len(os) != 2 {
+		t.Errorf("ListNoteOccurrences got %d occurrences, want 2", len(os))
+	}
+
+	summary, err := s.GetVulnerabilityOccurrencesSummary(ctx, "consumer1", `resource_uri = "debian"`)
+	if err != nil {
+		t.Fatalf("GetVulnerabilityOccurrencesSummary got err %v, want success", err)
+	}
+	if len(summary.Counts) != 2 || summary.Counts[0].ResourceUri != "debian" || summary.Counts[0].TotalCount != 2 {
+		t.Errorf("GetVulnerabilityOccurrencesSummary got %v, want 2 debian occurrences", summary)
+	}
+
+	// Masked updates keep the v1beta1 fields they don't touch, even those without a v1 counterpart.
+	betaOcc.Resource.Name = "alpine"
+	_, oID, _ := name.ParseOccurrence(betaOcc.Name)
+	if _, err := beta.UpdateOccurrence(ctx, "consumer1", oID, betaOcc, nil, ""); err != nil {
+		t.Fatalf("UpdateOccurrence got err %v, want success", err)
+	}
+	update := v1VulnzOcc("alpine:3.9", v1pb.Severity_LOW)
+	update.Remediation = "upgrade"
+	got, err := s.UpdateOccurrence(ctx, "consumer1", oID, update, &fieldmaskpb.FieldMask{Paths: []string{"resource_uri", "vulnerability.severity", "remediation"}})
+	if err != nil {
+		t.Fatalf("UpdateOccurrence got err %v, want success", err)
+	}
+	if got.ResourceUri != "alpine:3.9" || got.Remediation != "upgrade" || got.GetVulnerability().GetSeverity() != v1pb.Severity_LOW {
+		t.Errorf("UpdateOccurrence got %v, want the resource, remediation and severity updated", got)
+	}
+	stored, err := beta.GetOccurrence(ctx, "consumer1", oID)
+	if err != nil {
+		t.Fatalf("GetOccurrence got err %v, want success", err)
+	}
+	if stored.Resource.Name != "alpine" || stored.Resource.Uri != "alpine:3.9" {
+		t.Errorf("UpdateOccurrence stored resource %v, want the name kept and the URI updated", stored.Resource)
+	}
+	if _, err := s.UpdateOccurrence(ctx, "consumer1", oID, update, &fieldmaskpb.FieldMask{Paths: []string{"resource"}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdateOccurrence with a v1beta1 path got err %v, want %v", err, codes.InvalidArgument)
+	}
+
+	// Renamed details fields are mapped to their v1beta1 names.
+	n, err = s.UpdateNote(ctx, "goog-vulnz", "CVE-2014-9911", &v1pb.Note{Type: &v1pb.Note_Image{Image: &v1pb.ImageNote{ResourceUrl: "debian"}}}, &fieldmaskpb.FieldMask{Paths: []string{"image"}})
+	if err != nil {
+		t.Fatalf("UpdateNote got err %v, want success", err)
+	}
+	if n.GetImage().GetResourceUrl() != "debian" || n.GetVulnerability() != nil || n.ShortDescription != "CVE-2014-9911" {
+		t.Errorf("UpdateNote got %v, want an image note with the same short description", n)
+	}
+
+	if err := s.DeleteOccurrence(ctx, "consumer1", oID); err != nil {
+		t.Errorf("DeleteOccurrence got err %v, want success", err)
+	}
+	if _, err := s.GetOccurrence(ctx, "consumer1", oID); status.Code(err) != codes.NotFound {
+		t.Errorf("GetOccurrence of a deleted occurrence got err %v, want %v", err, codes.NotFound)
+	}
+}
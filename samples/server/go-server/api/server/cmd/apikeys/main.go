@@ -0,0 +1,144 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command apikeys manages the API keys in the API key file of a server, which the server reloads
+// when it changes. The keys created or rotated are printed once, only their hashes are kept.
+//
+// Usage:
+//
+//	apikeys create -config config.yaml -identity ci@example.com [-role scanner] ci
+//	apikeys rotate -config config.yaml ci
+//	apikeys revoke -config config.yaml ci
+//	apikeys list -config config.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/auth"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/config"
+)
+
+const usage = "Usage: apikeys create|rotate|revoke|list [flags] [NAME]"
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+	switch os.Args[1] {
+	case "create":
+		create(os.Args[2:])
+	case "rotate":
+		rotate(os.Args[2:])
+	case "revoke":
+		revoke(os.Args[2:])
+	case "list":
+		list(os.Args[2:])
+	default:
+		log.Fatal(usage)
+	}
+}
+
+func create(args []string) {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	configFile := fs.String("config", "", "Path to the config file of the server")
+	identity := fs.String("identity", "", "ID of the callers passing the key")
+	role := fs.String("role", "", "Role of the policy file granted to the callers passing the key in every project")
+	fs.Parse(args)
+	if *identity == "" || fs.NArg() != 1 {
+		log.Fatal("An -identity and the name of the key are required")
+	}
+
+	path, keys := read(*configFile)
+	key, err := keys.Create(fs.Arg(0), *identity, *role)
+	if err != nil {
+		log.Fatal(err)
+	}
+	write(path, keys)
+	fmt.Println(key)
+}
+
+func rotate(args []string) {
+	fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+	configFile := fs.String("config", "", "Path to the config file of the server")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatal("The name of the key is required")
+	}
+
+	path, keys := read(*configFile)
+	key, err := keys.Rotate(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	write(path, keys)
+	fmt.Println(key)
+}
+
+func revoke(args []string) {
+	fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+	configFile := fs.String("config", "", "Path to the config file of the server")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatal("The name of the key is required")
+	}
+
+	path, keys := read(*configFile)
+	if err := keys.Revoke(fs.Arg(0)); err != nil {
+		log.Fatal(err)
+	}
+	write(path, keys)
+	log.Printf("Revoked key %q", fs.Arg(0))
+}
+
+func list(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	configFile := fs.String("config", "", "Path to the config file of the server")
+	fs.Parse(args)
+
+	_, keys := read(*configFile)
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tIDENTITY\tROLE\tCREATED")
+	for _, k := range keys.Keys {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", k.Name, k.Identity, k.Role, k.Created.Format(time.RFC3339))
+	}
+	w.Flush()
+}
+
+// read returns the path and the keys of the API key file set in the specified server config file.
+func read(configFile string) (string, *auth.APIKeys) {
+	config, err := config.LoadConfig(configFile)
+	if err != nil {
+		log.Fatalf("Failed to load config file: %s", err)
+	}
+	if config.API == nil || config.API.APIKeyFile == "" {
+		log.Fatal("The config file must set the api_key_file of the api")
+	}
+	keys, err := auth.ReadAPIKeys(config.API.APIKeyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return config.API.APIKeyFile, keys
+}
+
+func write(path string, keys *auth.APIKeys) {
+	if err := auth.WriteAPIKeys(path, keys); err != nil {
+		log.Fatalf("Failed to write API key file %q: %s", path, err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command archive exports the notes and occurrences of a project in the storage of a server to a
+// portable archive, and imports such archives, possibly into another project.
+//
+// Usage:
+//
+//	archive export -config config.yaml -project staging [-format length_delimited] staging.tar.gz
+//	archive import -config config.yaml [-project test] [-skip-existing] [-rewrite-notes goog-vulnz=test-vulnz] staging.tar.gz
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/archive"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/config"
+	server "github.com/grafeas/grafeas/server-go"
+)
+
+const usage = "Usage: archive export|import [flags] ARCHIVE"
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+	switch os.Args[1] {
+	case "export":
+		export(os.Args[2:])
+	case "import":
+		imp(os.Args[2:])
+	default:
+		log.Fatal(usage)
+	}
+}
+
+func export(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	configFile := fs.String("config", "", "Path to the config file of the server to export from")
+	project := fs.String("project", "", "ID of the project to export")
+	format := fs.String("format", string(archive.JSONLines), "Encoding of the notes and occurrences, json_lines or length_delimited")
+	fs.Parse(args)
+	if *project == "" || fs.NArg() != 1 {
+		log.Fatal("A -project and the path of the archive to write are required")
+	}
+
+	s := storager(*configFile)
+	f, err := os.Create(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	m, err := archive.Export(s, *project, archive.Format(*format), f)
+	if err == nil {
+		err = f.Close()
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		log.Fatalf("Failed to export project %q: %v", *project, err)
+	}
+	log.Printf("Exported %d notes and %d occurrences of project %q", m.Notes, m.Occurrences, m.Project)
+}
+
+func imp(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	configFile := fs.String("config", "", "Path to the config file of the server to import into")
+	project := fs.String("project", "", "ID of the project to import into, the exported one if empty")
+	skipExisting := fs.Bool("skip-existing", false, "Skip notes and occurrences that already exist instead of failing them")
+	rewriteNotes := fs.String("rewrite-notes", "", "Comma-separated FROM=TO project IDs to rewrite references to notes with")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatal("The path of the archive to read is required")
+	}
+	opts := &archive.ImportOptions{Project: *project, SkipExisting: *skipExisting, NoteProjects: map[string]string{}}
+	if *rewriteNotes != "" {
+		for _, r := range strings.Split(*rewriteNotes, ",") {
+			parts := strings.Split(r, "=")
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				log.Fatalf("Invalid -rewrite-notes %q, want FROM=TO", r)
+			}
+			opts.NoteProjects[parts[0]] = parts[1]
+		}
+	}
+
+	s := storager(*configFile)
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	m, stats, err := archive.Import(s, f, opts, func(r *archive.Result) {
+		log.Printf("%s: failed: %v", r.Name, r.Err)
+	})
+	if stats != nil {
+		log.Printf("Imported %d notes and %d occurrences of project %q: %d already existing, %d failed",
+			stats.Notes, stats.Occurrences, m.Project, stats.Existing, stats.Failed)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if stats.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// storager returns the storage configured in the specified server config file.
+func storager(configFile string) server.Storager {
+	config, err := config.LoadConfig(configFile)
+	if err != nil {
+		log.Fatalf("Failed to load config file: %s", err)
+	}
+	s, err := config.PersistentStorager()
+	if err != nil {
+		log.Fatalf("Failed to configure storage: %s", err)
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command migrate copies v1alpha1 notes and occurrences into the storage of a v1beta1 server,
+// reading them from JSON exports or from a Postgres or bolt database in the layout of the old
+// server. Fields that can't be converted are reported per entity.
+//
+// Usage:
+//
+//	migrate -config config.yaml export1.json export2.json
+//	migrate -config config.yaml -postgres "host=... dbname=... sslmode=disable"
+//	migrate -config config.yaml -bolt /var/lib/grafeas-v1alpha1/grafeas.db
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/config"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/migrate"
+	_ "github.com/lib/pq"
+)
+
+var (
+	configFile = flag.String("config", "", "Path to the config file of the server to migrate to")
+	pgSource   = flag.String("postgres", "", "Connection string of a v1alpha1 Postgres database to migrate from")
+	boltSource = flag.String("bolt", "", "Path to a v1alpha1 bolt database file to migrate from")
+)
+
+func main() {
+	flag.Parse()
+	src, closeSrc := source()
+
+	config, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatalf("Failed to load config file: %s", err)
+	}
+	storager, err := config.PersistentStorager()
+	if err != nil {
+		log.Fatalf("Failed to configure storage: %s", err)
+	}
+
+	stats, err := migrate.Run(src, storager, func(r *migrate.Result) {
+		if r.Err != nil {
+			log.Printf("%s: failed: %v", r.Name, r.Err)
+			return
+		}
+		log.Printf("%s: dropped %s", r.Name, strings.Join(r.Dropped, ", "))
+	})
+	closeSrc()
+	log.Printf("Migrated %d projects, %d notes and %d occurrences: %d with dropped fields, %d already existing, %d failed",
+		stats.Projects, stats.Notes, stats.Occurrences, stats.Lossy, stats.Existing, stats.Failed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if stats.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// source returns the source selected by the flags and a function closing it.
+func source() (migrate.Source, func()) {
+	switch {
+	case *pgSource != "" && *boltSource != "", (*pgSource != "" || *boltSource != "") && flag.NArg() > 0:
+		log.Fatal("Only one of -postgres, -bolt and JSON exports can be migrated from")
+	case *pgSource != "":
+		db, err := sql.Open("postgres", *pgSource)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return &migrate.PgSQLSource{DB: db}, func() { db.Close() }
+	case *boltSource != "":
+		db, err := bolt.Open(*boltSource, 0600, &bolt.Options{ReadOnly: true})
+		if err != nil {
+			log.Fatal(err)
+		}
+		return &migrate.BoltSource{DB: db}, func() { db.Close() }
+	case flag.NArg() == 0:
+		log.Fatal("A -postgres or -bolt database or JSON exports to migrate from are required")
+	}
+	return &migrate.JSONSource{Paths: flag.Args()}, func() {}
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command retention applies the retention rules of a server config file once to the storage it
+// configures and prints the report as JSON. It's a dry run unless -apply is set, whatever the
+// config says, so it can be used to check rules before the server applies them.
+//
+// Usage:
+//
+//	retention -config config.yaml [-apply]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/api"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/bridge"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/config"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/retention"
+)
+
+var (
+	configFile = flag.String("config", "", "Path to the config file of the server whose retention rules to apply")
+	apply      = flag.Bool("apply", false, "Delete the expired occurrences instead of only reporting them")
+)
+
+func main() {
+	flag.Parse()
+	config, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatalf("Failed to load config file: %s", err)
+	}
+	if config.Retention == nil || len(config.Retention.Rules) == 0 {
+		log.Fatal("The config file has no retention rules")
+	}
+	// The report is printed rather than appended to the server's report log.
+	c := *config.Retention
+	c.DryRun = !*apply
+	c.ReportPath = ""
+	s, err := config.PersistentStorager()
+	if err != nil {
+		log.Fatalf("Failed to configure storage: %s", err)
+	}
+	a, err := api.NewAPI(config.API, bridge.New(s))
+	if err != nil {
+		log.Fatalf("Failed to configure API: %s", err)
+	}
+	w, err := retention.NewSweeper(s, a, &c)
+	if err != nil {
+		log.Fatalf("Failed to configure retention: %s", err)
+	}
+	defer w.Close()
+
+	report := w.Sweep(time.Now())
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+}
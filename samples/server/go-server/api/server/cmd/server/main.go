@@ -19,9 +19,8 @@ import (
 	"log"
 
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/api"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/bridge"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/config"
-	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
-	server "github.com/grafeas/grafeas/server-go"
 )
 
 var (
@@ -34,16 +33,13 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config file: %s", err)
 	}
-	var storager server.Storager
-	switch config.StorageType {
-	case "memstore":
-		storager = storage.NewMemStore()
-	case "postgres":
-		storager = storage.NewPgSQLStore(config.PgSQLConfig)
-	case "embedded":
-		storager = storage.NewEmbeddedStore(config.EmbeddedConfig)
-	default:
-		log.Fatalf("Storage type unsupported: %s", config.StorageType)
+	storager, err := config.Storager()
+	if err != nil {
+		log.Fatalf("Failed to configure storage: %s", err)
+	}
+	a, err := api.NewAPI(config.API, bridge.New(storager))
+	if err != nil {
+		log.Fatalf("Failed to configure API: %s", err)
 	}
-	api.Run(config.API, &storager)
+	api.Run(config.API, &storager, a)
 }
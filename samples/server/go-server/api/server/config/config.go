@@ -16,12 +16,16 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 
 	fernet "github.com/fernet/fernet-go"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/api"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/retention"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/webhook"
+	server "github.com/grafeas/grafeas/server-go"
 	"gopkg.in/yaml.v2"
 )
 
@@ -35,7 +39,9 @@ type config struct {
 	API            *api.Config                  `yaml:"api"`
 	StorageType    string                       `yaml:"storage_type"` // Supported storage types are "memstore", "postgres" and "embedded"
 	PgSQLConfig    *storage.PgSQLConfig         `yaml:"postgres"`
-	EmbeddedConfig *storage.EmbeddedStoreConfig `yaml:"embedded"` // EmbeddedConfig is the embedded store config
+	EmbeddedConfig *storage.EmbeddedStoreConfig `yaml:"embedded"`  // EmbeddedConfig is the embedded store config
+	Webhooks       *webhook.Config              `yaml:"webhooks"`  // Endpoints notified of note and occurrence changes
+	Retention      *retention.Config            `yaml:"retention"` // Rules old occurrences are deleted by
 }
 
 // DefaultConfig is a configuration that can be used as a fallback value.
@@ -86,5 +92,33 @@ func LoadConfig(fileName string) (*config, error) {
 			}
 		}
 	}
+	// Without the validating API, projects, notes and occurrences are served by the sample API,
+	// which checks no permissions, so a policy would only be enforced on some services.
+	if config.API != nil && config.API.PolicyFile != "" && !config.API.ValidatingAPI {
+		return nil, errors.New("policy_file requires validating_api")
+	}
 	return config, nil
 }
+
+// Storager returns the storage the config specifies.
+func (c *config) Storager() (server.Storager, error) {
+	switch c.StorageType {
+	case "memstore":
+		return storage.NewMemStore(), nil
+	case "postgres":
+		return storage.NewPgSQLStore(c.PgSQLConfig), nil
+	case "embedded":
+		return storage.NewEmbeddedStore(c.EmbeddedConfig), nil
+	}
+	return nil, fmt.Errorf("storage type unsupported: %s", c.StorageType)
+}
+
+// PersistentStorager returns the storage the config specifies like Storager, for tools that work
+// on the data of a server from outside of it. It fails for the memstore, which would start out
+// empty and keep nothing.
+func (c *config) PersistentStorager() (server.Storager, error) {
+	if c.StorageType == "memstore" {
+		return nil, fmt.Errorf("storage type %s only lives in the server, use postgres or embedded", c.StorageType)
+	}
+	return c.Storager()
+}
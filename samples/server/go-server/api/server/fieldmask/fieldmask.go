@@ -0,0 +1,139 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fieldmask applies update masks to protos.
+package fieldmask
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/grafeas/grafeas/go/errors"
+	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+)
+
+// Apply copies the fields of src in the mask to dst, clearing those that aren't set in src. Paths
+// are dot separated proto field names, whose first element must be a field of dst. Setting a field
+// of a oneof clears its other fields.
+func Apply(dst, src proto.Message, mask *fieldmaskpb.FieldMask) error {
+	fields, oneofs := fieldNames(dst)
+	for _, p := range mask.GetPaths() {
+		if _, ok := fields[strings.Split(p, ".")[0]]; !ok {
+			return errors.Newf(codes.InvalidArgument, "invalid update mask path %q", p)
+		}
+	}
+
+	dstJSON, err := toJSON(dst)
+	if err != nil {
+		return err
+	}
+	srcJSON, err := toJSON(src)
+	if err != nil {
+		return err
+	}
+	for _, p := range mask.GetPaths() {
+		path := strings.Split(p, ".")
+		if oneof, ok := oneofs[path[0]]; ok {
+			for f, o := range oneofs {
+				if o == oneof && f != path[0] {
+					delete(dstJSON, f)
+				}
+			}
+		}
+		if v, ok := lookup(srcJSON, path); ok {
+			set(dstJSON, path, v)
+		} else {
+			unset(dstJSON, path)
+		}
+	}
+
+	b, err := json.Marshal(dstJSON)
+	if err != nil {
+		return errors.Newf(codes.Internal, "failed to apply update mask: %v", err)
+	}
+	dst.Reset()
+	if err := jsonpb.UnmarshalString(string(b), dst); err != nil {
+		return errors.Newf(codes.InvalidArgument, "failed to apply update mask %v: %v", mask.GetPaths(), err)
+	}
+	return nil
+}
+
+// fieldNames returns the proto names of the fields of m, and a map of the names of the fields in
+// oneofs to the index of their oneof.
+func fieldNames(m proto.Message) (map[string]bool, map[string]int) {
+	props := proto.GetProperties(reflect.TypeOf(m).Elem())
+	fields := map[string]bool{}
+	for _, p := range props.Prop {
+		if p.OrigName != "" {
+			fields[p.OrigName] = true
+		}
+	}
+	oneofs := map[string]int{}
+	for n, o := range props.OneofTypes {
+		fields[n] = true
+		oneofs[n] = o.Field
+	}
+	return fields, oneofs
+}
+
+func toJSON(m proto.Message) (map[string]interface{}, error) {
+	s, err := (&jsonpb.Marshaler{OrigName: true}).MarshalToString(m)
+	if err != nil {
+		return nil, errors.Newf(codes.Internal, "failed to apply update mask: %v", err)
+	}
+	v := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, errors.Newf(codes.Internal, "failed to apply update mask: %v", err)
+	}
+	return v, nil
+}
+
+func lookup(m map[string]interface{}, path []string) (interface{}, bool) {
+	v, ok := m[path[0]]
+	if !ok || len(path) == 1 {
+		return v, ok
+	}
+	next, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookup(next, path[1:])
+}
+
+func set(m map[string]interface{}, path []string, v interface{}) {
+	if len(path) == 1 {
+		m[path[0]] = v
+		return
+	}
+	next, ok := m[path[0]].(map[string]interface{})
+	if !ok {
+		next = map[string]interface{}{}
+		m[path[0]] = next
+	}
+	set(next, path[1:], v)
+}
+
+func unset(m map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(m, path[0])
+		return
+	}
+	if next, ok := m[path[0]].(map[string]interface{}); ok {
+		unset(next, path[1:])
+	}
+}
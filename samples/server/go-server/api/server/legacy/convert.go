@@ -0,0 +1,745 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package legacy
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apb "github.com/grafeas/grafeas/proto/v1beta1/attestation_go_proto"
+	bpb "github.com/grafeas/grafeas/proto/v1beta1/build_go_proto"
+	cpb "github.com/grafeas/grafeas/proto/v1beta1/common_go_proto"
+	deploymentpb "github.com/grafeas/grafeas/proto/v1beta1/deployment_go_proto"
+	discoverypb "github.com/grafeas/grafeas/proto/v1beta1/discovery_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	ipb "github.com/grafeas/grafeas/proto/v1beta1/image_go_proto"
+	pkgpb "github.com/grafeas/grafeas/proto/v1beta1/package_go_proto"
+	provpb "github.com/grafeas/grafeas/proto/v1beta1/provenance_go_proto"
+	srcpb "github.com/grafeas/grafeas/proto/v1beta1/source_go_proto"
+	vpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
+	alphapb "github.com/grafeas/grafeas/v1alpha1/proto"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+)
+
+// noteKinds maps v1alpha1 note kinds to v1beta1 ones.
+var noteKinds = map[alphapb.Note_Kind]cpb.NoteKind{
+	alphapb.Note_KIND_UNSPECIFIED:      cpb.NoteKind_NOTE_KIND_UNSPECIFIED,
+	alphapb.Note_PACKAGE_VULNERABILITY: cpb.NoteKind_VULNERABILITY,
+	alphapb.Note_BUILD_DETAILS:         cpb.NoteKind_BUILD,
+	alphapb.Note_IMAGE_BASIS:           cpb.NoteKind_IMAGE,
+	alphapb.Note_PACKAGE_MANAGER:       cpb.NoteKind_PACKAGE,
+	alphapb.Note_DEPLOYABLE:            cpb.NoteKind_DEPLOYMENT,
+	alphapb.Note_DISCOVERY:             cpb.NoteKind_DISCOVERY,
+	alphapb.Note_ATTESTATION_AUTHORITY: cpb.NoteKind_ATTESTATION,
+}
+
+func noteKindToV1Beta1(k alphapb.Note_Kind) cpb.NoteKind {
+	return noteKinds[k]
+}
+
+func noteKindToV1Alpha1(k cpb.NoteKind) alphapb.Note_Kind {
+	for alpha, beta := range noteKinds {
+		if beta == k {
+			return alpha
+		}
+	}
+	return alphapb.Note_KIND_UNSPECIFIED
+}
+
+// noteToV1Beta1 converts a v1alpha1 note to v1beta1. Its operation name is dropped.
+func noteToV1Beta1(n *alphapb.Note) *gpb.Note {
+	if n == nil {
+		return nil
+	}
+	out := &gpb.Note{
+		Name:             n.Name,
+		ShortDescription: n.ShortDescription,
+		LongDescription:  n.LongDescription,
+		Kind:             noteKindToV1Beta1(n.Kind),
+		ExpirationTime:   n.ExpirationTime,
+		CreateTime:       n.CreateTime,
+		UpdateTime:       n.UpdateTime,
+	}
+	for _, u := range n.RelatedUrl {
+		out.RelatedUrl = append(out.RelatedUrl, &cpb.RelatedUrl{Url: u.Url, Label: u.Label})
+	}
+	switch t := n.NoteType.(type) {
+	case *alphapb.Note_VulnerabilityType:
+		v := t.VulnerabilityType
+		vuln := &vpb.Vulnerability{CvssScore: v.GetCvssScore(), Severity: vpb.Severity(v.GetSeverity())}
+		for _, d := range v.GetDetails() {
+			vuln.Details = append(vuln.Details, &vpb.Vulnerability_Detail{
+				CpeUri:             d.CpeUri,
+				Package:            d.Package,
+				MinAffectedVersion: versionToV1Beta1(d.MinAffectedVersion),
+				MaxAffectedVersion: versionToV1Beta1(d.MaxAffectedVersion),
+				SeverityName:       d.SeverityName,
+				Description:        d.Description,
+				FixedLocation:      vulnerabilityLocationToV1Beta1(d.FixedLocation),
+				PackageType:        d.PackageType,
+			})
+		}
+		out.Type = &gpb.Note_Vulnerability{Vulnerability: vuln}
+	case *alphapb.Note_BuildType:
+		b := &bpb.Build{BuilderVersion: t.BuildType.GetBuilderVersion()}
+		if s := t.BuildType.GetSignature(); s != nil {
+			b.Signature = &bpb.BuildSignature{
+				PublicKey: s.PublicKey,
+				Signature: signatureToV1Beta1(s.Signature),
+				KeyId:     s.KeyId,
+				KeyType:   bpb.BuildSignature_KeyType(s.KeyType),
+			}
+		}
+		out.Type = &gpb.Note_Build{Build: b}
+	case *alphapb.Note_BaseImage:
+		out.Type = &gpb.Note_BaseImage{BaseImage: &ipb.Basis{
+			ResourceUrl: t.BaseImage.GetResourceUrl(),
+			Fingerprint: fingerprintToV1Beta1(t.BaseImage.GetFingerprint()),
+		}}
+	case *alphapb.Note_Package:
+		p := &pkgpb.Package{Name: t.Package.GetName()}
+		for _, d := range t.Package.GetDistribution() {
+			p.Distribution = append(p.Distribution, &pkgpb.Distribution{
+				CpeUri:        d.CpeUri,
+				Architecture:  pkgpb.Architecture(d.Architecture),
+				LatestVersion: versionToV1Beta1(d.LatestVersion),
+				Maintainer:    d.Maintainer,
+				Url:           d.Url,
+				Description:   d.Description,
+			})
+		}
+		out.Type = &gpb.Note_Package{Package: p}
+	case *alphapb.Note_Deployable:
+		out.Type = &gpb.Note_Deployable{Deployable: &deploymentpb.Deployable{ResourceUri: t.Deployable.GetResourceUri()}}
+	case *alphapb.Note_Discovery:
+		out.Type = &gpb.Note_Discovery{Discovery: &discoverypb.Discovery{AnalysisKind: noteKindToV1Beta1(t.Discovery.GetAnalysisKind())}}
+	case *alphapb.Note_AttestationAuthority:
+		a := &apb.Authority{}
+		if h := t.AttestationAuthority.GetHint(); h != nil {
+			a.Hint = &apb.Authority_Hint{HumanReadableName: h.HumanReadableName}
+		}
+		out.Type = &gpb.Note_AttestationAuthority{AttestationAuthority: a}
+	}
+	return out
+}
+
+// noteToV1Alpha1 converts a v1beta1 note to v1alpha1. Fields that v1alpha1 lacks, such as related
+// note names and CVSS v3 scores, are dropped.
+func noteToV1Alpha1(n *gpb.Note) *alphapb.Note {
+	if n == nil {
+		return nil
+	}
+	out := &alphapb.Note{
+		Name:             n.Name,
+		ShortDescription: n.ShortDescription,
+		LongDescription:  n.LongDescription,
+		Kind:             noteKindToV1Alpha1(n.Kind),
+		ExpirationTime:   n.ExpirationTime,
+		CreateTime:       n.CreateTime,
+		UpdateTime:       n.UpdateTime,
+	}
+	for _, u := range n.RelatedUrl {
+		out.RelatedUrl = append(out.RelatedUrl, &alphapb.Note_RelatedUrl{Url: u.Url, Label: u.Label})
+	}
+	switch t := n.Type.(type) {
+	case *gpb.Note_Vulnerability:
+		v := t.Vulnerability
+		vuln := &alphapb.VulnerabilityType{CvssScore: v.GetCvssScore(), Severity: alphapb.VulnerabilityType_Severity(v.GetSeverity())}
+		for _, d := range v.GetDetails() {
+			vuln.Details = append(vuln.Details, &alphapb.VulnerabilityType_Detail{
+				CpeUri:             d.CpeUri,
+				Package:            d.Package,
+				MinAffectedVersion: versionToV1Alpha1(d.MinAffectedVersion),
+				MaxAffectedVersion: versionToV1Alpha1(d.MaxAffectedVersion),
+				SeverityName:       d.SeverityName,
+				Description:        d.Description,
+				FixedLocation:      vulnerabilityLocationToV1Alpha1(d.FixedLocation),
+				PackageType:        d.PackageType,
+			})
+		}
+		out.NoteType = &alphapb.Note_VulnerabilityType{VulnerabilityType: vuln}
+	case *gpb.Note_Build:
+		b := &alphapb.BuildType{BuilderVersion: t.Build.GetBuilderVersion()}
+		if s := t.Build.GetSignature(); s != nil {
+			b.Signature = &alphapb.BuildSignature{
+				PublicKey: s.PublicKey,
+				Signature: base64.StdEncoding.EncodeToString(s.Signature),
+				KeyId:     s.KeyId,
+				KeyType:   alphapb.BuildSignature_KeyType(s.KeyType),
+			}
+		}
+		out.NoteType = &alphapb.Note_BuildType{BuildType: b}
+	case *gpb.Note_BaseImage:
+		out.NoteType = &alphapb.Note_BaseImage{BaseImage: &alphapb.DockerImage_Basis{
+			ResourceUrl: t.BaseImage.GetResourceUrl(),
+			Fingerprint: fingerprintToV1Alpha1(t.BaseImage.GetFingerprint()),
+		}}
+	case *gpb.Note_Package:
+		p := &alphapb.PackageManager_Package{Name: t.Package.GetName()}
+		for _, d := range t.Package.GetDistribution() {
+			p.Distribution = append(p.Distribution, &alphapb.PackageManager_Distribution{
+				CpeUri:        d.CpeUri,
+				Architecture:  alphapb.PackageManager_Architecture(d.Architecture),
+				LatestVersion: versionToV1Alpha1(d.LatestVersion),
+				Maintainer:    d.Maintainer,
+				Url:           d.Url,
+				Description:   d.Description,
+			})
+		}
+		out.NoteType = &alphapb.Note_Package{Package: p}
+	case *gpb.Note_Deployable:
+		out.NoteType = &alphapb.Note_Deployable{Deployable: &alphapb.Deployable{ResourceUri: t.Deployable.GetResourceUri()}}
+	case *gpb.Note_Discovery:
+		out.NoteType = &alphapb.Note_Discovery{Discovery: &alphapb.Discovery{AnalysisKind: noteKindToV1Alpha1(t.Discovery.GetAnalysisKind())}}
+	case *gpb.Note_AttestationAuthority:
+		a := &alphapb.AttestationAuthority{}
+		if h := t.AttestationAuthority.GetHint(); h != nil {
+			a.Hint = &alphapb.AttestationAuthority_AttestationAuthorityHint{HumanReadableName: h.HumanReadableName}
+		}
+		out.NoteType = &alphapb.Note_AttestationAuthority{AttestationAuthority: a}
+	}
+	return out
+}
+
+// occurrenceToV1Beta1 converts a v1alpha1 occurrence to v1beta1. Its operation name is dropped.
+func occurrenceToV1Beta1(o *alphapb.Occurrence) *gpb.Occurrence {
+	if o == nil {
+		return nil
+	}
+	out := &gpb.Occurrence{
+		Name:        o.Name,
+		NoteName:    o.NoteName,
+		Kind:        noteKindToV1Beta1(o.Kind),
+		Remediation: o.Remediation,
+		CreateTime:  o.CreateTime,
+		UpdateTime:  o.UpdateTime,
+	}
+	if o.ResourceUrl != "" {
+		out.Resource = &gpb.Resource{Uri: o.ResourceUrl}
+	}
+	switch d := o.Details.(type) {
+	case *alphapb.Occurrence_VulnerabilityDetails:
+		v := d.VulnerabilityDetails
+		details := &vpb.Details{Type: v.GetType(), Severity: vpb.Severity(v.GetSeverity()), CvssScore: v.GetCvssScore()}
+		for _, pi := range v.GetPackageIssue() {
+			details.PackageIssue = append(details.PackageIssue, &vpb.PackageIssue{
+				AffectedLocation: vulnerabilityLocationToV1Beta1(pi.AffectedLocation),
+				FixedLocation:    vulnerabilityLocationToV1Beta1(pi.FixedLocation),
+				SeverityName:     pi.SeverityName,
+			})
+		}
+		out.Details = &gpb.Occurrence_Vulnerability{Vulnerability: details}
+	case *alphapb.Occurrence_BuildDetails:
+		out.Details = &gpb.Occurrence_Build{Build: &bpb.Details{
+			Provenance:      provenanceToV1Beta1(d.BuildDetails.GetProvenance()),
+			ProvenanceBytes: d.BuildDetails.GetProvenanceBytes(),
+		}}
+	case *alphapb.Occurrence_DerivedImageDetails:
+		dd := d.DerivedImageDetails
+		derived := &ipb.Derived{
+			Fingerprint:     fingerprintToV1Beta1(dd.GetFingerprint()),
+			Distance:        int32(dd.GetDistance()),
+			BaseResourceUrl: dd.GetBaseResourceUrl(),
+		}
+		for _, l := range dd.GetLayerInfo() {
+			derived.LayerInfo = append(derived.LayerInfo, &ipb.Layer{Directive: ipb.Layer_Directive(l.Directive), Arguments: l.Arguments})
+		}
+		out.Details = &gpb.Occurrence_DerivedImage{DerivedImage: &ipb.Details{DerivedImage: derived}}
+	case *alphapb.Occurrence_InstallationDetails:
+		installation := &pkgpb.Installation{Name: d.InstallationDetails.GetName()}
+		for _, l := range d.InstallationDetails.GetLocation() {
+			installation.Location = append(installation.Location, &pkgpb.Location{CpeUri: l.CpeUri, Version: versionToV1Beta1(l.Version), Path: l.Path})
+		}
+		out.Details = &gpb.Occurrence_Installation{Installation: &pkgpb.Details{Installation: installation}}
+	case *alphapb.Occurrence_DeploymentDetails:
+		dd := d.DeploymentDetails
+		out.Details = &gpb.Occurrence_Deployment{Deployment: &deploymentpb.Details{Deployment: &deploymentpb.Deployment{
+			UserEmail:    dd.GetUserEmail(),
+			DeployTime:   dd.GetDeployTime(),
+			UndeployTime: dd.GetUndeployTime(),
+			Config:       dd.GetConfig(),
+			Address:      dd.GetAddress(),
+			ResourceUri:  dd.GetResourceUri(),
+			Platform:     deploymentpb.Deployment_Platform(dd.GetPlatform()),
+		}}}
+	case *alphapb.Occurrence_DiscoveredDetails:
+		out.Details = &gpb.Occurrence_Discovered{Discovered: &discoverypb.Details{
+			Discovered: discoveredToV1Beta1(d.DiscoveredDetails.GetOperation()),
+		}}
+	case *alphapb.Occurrence_AttestationDetails:
+		attestation := &apb.Attestation{}
+		if pgp := d.AttestationDetails.GetPgpSignedAttestation(); pgp != nil {
+			beta := &apb.PgpSignedAttestation{
+				Signature:   pgp.Signature,
+				ContentType: apb.PgpSignedAttestation_ContentType(pgp.ContentType),
+			}
+			if pgp.KeyId != nil {
+				beta.KeyId = &apb.PgpSignedAttestation_PgpKeyId{PgpKeyId: pgp.GetPgpKeyId()}
+			}
+			attestation.Signature = &apb.Attestation_PgpSignedAttestation{PgpSignedAttestation: beta}
+		}
+		out.Details = &gpb.Occurrence_Attestation{Attestation: &apb.Details{Attestation: attestation}}
+	}
+	return out
+}
+
+// occurrenceToV1Alpha1 converts a v1beta1 occurrence to v1alpha1. Fields that v1alpha1 lacks, such
+// as the resource name and content hash and generic signed attestations, are dropped.
+func occurrenceToV1Alpha1(o *gpb.Occurrence) *alphapb.Occurrence {
+	if o == nil {
+		return nil
+	}
+	out := &alphapb.Occurrence{
+		Name:        o.Name,
+		ResourceUrl: o.GetResource().GetUri(),
+		NoteName:    o.NoteName,
+		Kind:        noteKindToV1Alpha1(o.Kind),
+		Remediation: o.Remediation,
+		CreateTime:  o.CreateTime,
+		UpdateTime:  o.UpdateTime,
+	}
+	switch d := o.Details.(type) {
+	case *gpb.Occurrence_Vulnerability:
+		v := d.Vulnerability
+		details := &alphapb.VulnerabilityType_VulnerabilityDetails{
+			Type:      v.GetType(),
+			Severity:  alphapb.VulnerabilityType_Severity(v.GetSeverity()),
+			CvssScore: v.GetCvssScore(),
+		}
+		for _, pi := range v.GetPackageIssue() {
+			details.PackageIssue = append(details.PackageIssue, &alphapb.VulnerabilityType_PackageIssue{
+				AffectedLocation: vulnerabilityLocationToV1Alpha1(pi.AffectedLocation),
+				FixedLocation:    vulnerabilityLocationToV1Alpha1(pi.FixedLocation),
+				SeverityName:     pi.SeverityName,
+			})
+		}
+		out.Details = &alphapb.Occurrence_VulnerabilityDetails{VulnerabilityDetails: details}
+	case *gpb.Occurrence_Build:
+		out.Details = &alphapb.Occurrence_BuildDetails{BuildDetails: &alphapb.BuildDetails{
+			Provenance:      provenanceToV1Alpha1(d.Build.GetProvenance()),
+			ProvenanceBytes: d.Build.GetProvenanceBytes(),
+		}}
+	case *gpb.Occurrence_DerivedImage:
+		dd := d.DerivedImage.GetDerivedImage()
+		derived := &alphapb.DockerImage_DerivedDetails{
+			Fingerprint:     fingerprintToV1Alpha1(dd.GetFingerprint()),
+			Distance:        uint32(dd.GetDistance()),
+			BaseResourceUrl: dd.GetBaseResourceUrl(),
+		}
+		for _, l := range dd.GetLayerInfo() {
+			derived.LayerInfo = append(derived.LayerInfo, &alphapb.DockerImage_Layer{Directive: alphapb.DockerImage_Layer_Directive(l.Directive), Arguments: l.Arguments})
+		}
+		out.Details = &alphapb.Occurrence_DerivedImageDetails{DerivedImageDetails: derived}
+	case *gpb.Occurrence_Installation:
+		i := d.Installation.GetInstallation()
+		installation := &alphapb.PackageManager_InstallationDetails{Name: i.GetName()}
+		for _, l := range i.GetLocation() {
+			installation.Location = append(installation.Location, &alphapb.PackageManager_Location{CpeUri: l.CpeUri, Version: versionToV1Alpha1(l.Version), Path: l.Path})
+		}
+		out.Details = &alphapb.Occurrence_InstallationDetails{InstallationDetails: installation}
+	case *gpb.Occurrence_Deployment:
+		dd := d.Deployment.GetDeployment()
+		out.Details = &alphapb.Occurrence_DeploymentDetails{DeploymentDetails: &alphapb.Deployable_DeploymentDetails{
+			UserEmail:    dd.GetUserEmail(),
+			DeployTime:   dd.GetDeployTime(),
+			UndeployTime: dd.GetUndeployTime(),
+			Config:       dd.GetConfig(),
+			Address:      dd.GetAddress(),
+			ResourceUri:  dd.GetResourceUri(),
+			Platform:     alphapb.Deployable_DeploymentDetails_Platform(dd.GetPlatform()),
+		}}
+	case *gpb.Occurrence_Discovered:
+		out.Details = &alphapb.Occurrence_DiscoveredDetails{DiscoveredDetails: &alphapb.Discovery_DiscoveredDetails{
+			Operation: discoveredToV1Alpha1(d.Discovered.GetDiscovered()),
+		}}
+	case *gpb.Occurrence_Attestation:
+		details := &alphapb.AttestationAuthority_AttestationDetails{}
+		if pgp := d.Attestation.GetAttestation().GetPgpSignedAttestation(); pgp != nil {
+			alpha := &alphapb.PgpSignedAttestation{
+				Signature:   pgp.Signature,
+				ContentType: alphapb.PgpSignedAttestation_ContentType(pgp.ContentType),
+			}
+			if pgp.KeyId != nil {
+				alpha.KeyId = &alphapb.PgpSignedAttestation_PgpKeyId{PgpKeyId: pgp.GetPgpKeyId()}
+			}
+			details.Signature = &alphapb.AttestationAuthority_AttestationDetails_PgpSignedAttestation{PgpSignedAttestation: alpha}
+		}
+		out.Details = &alphapb.Occurrence_AttestationDetails{AttestationDetails: details}
+	}
+	return out
+}
+
+// versionToV1Beta1 converts a v1alpha1 version, whose kinds lack the unspecified value of
+// v1beta1.
+func versionToV1Beta1(v *alphapb.VulnerabilityType_Version) *pkgpb.Version {
+	if v == nil {
+		return nil
+	}
+	return &pkgpb.Version{
+		Epoch:    v.Epoch,
+		Name:     v.Name,
+		Revision: v.Revision,
+		Kind:     pkgpb.Version_VersionKind(v.Kind + 1),
+	}
+}
+
+// versionToV1Alpha1 converts a v1beta1 version, turning unspecified kinds into normal ones.
+func versionToV1Alpha1(v *pkgpb.Version) *alphapb.VulnerabilityType_Version {
+	if v == nil {
+		return nil
+	}
+	out := &alphapb.VulnerabilityType_Version{Epoch: v.Epoch, Name: v.Name, Revision: v.Revision}
+	if v.Kind != pkgpb.Version_VERSION_KIND_UNSPECIFIED {
+		out.Kind = alphapb.VulnerabilityType_Version_VersionKind(v.Kind - 1)
+	}
+	return out
+}
+
+func vulnerabilityLocationToV1Beta1(l *alphapb.VulnerabilityType_VulnerabilityLocation) *vpb.VulnerabilityLocation {
+	if l == nil {
+		return nil
+	}
+	return &vpb.VulnerabilityLocation{CpeUri: l.CpeUri, Package: l.Package, Version: versionToV1Beta1(l.Version)}
+}
+
+func vulnerabilityLocationToV1Alpha1(l *vpb.VulnerabilityLocation) *alphapb.VulnerabilityType_VulnerabilityLocation {
+	if l == nil {
+		return nil
+	}
+	return &alphapb.VulnerabilityType_VulnerabilityLocation{CpeUri: l.CpeUri, Package: l.Package, Version: versionToV1Alpha1(l.Version)}
+}
+
+func fingerprintToV1Beta1(f *alphapb.DockerImage_Fingerprint) *ipb.Fingerprint {
+	if f == nil {
+		return nil
+	}
+	return &ipb.Fingerprint{V1Name: f.V1Name, V2Blob: f.V2Blob, V2Name: f.V2Name}
+}
+
+func fingerprintToV1Alpha1(f *ipb.Fingerprint) *alphapb.DockerImage_Fingerprint {
+	if f == nil {
+		return nil
+	}
+	return &alphapb.DockerImage_Fingerprint{V1Name: f.V1Name, V2Blob: f.V2Blob, V2Name: f.V2Name}
+}
+
+// signatureToV1Beta1 decodes a base64 encoded v1alpha1 build signature, keeping signatures that
+// aren't valid base64 as they are.
+func signatureToV1Beta1(s string) []byte {
+	if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return b
+	}
+	return []byte(s)
+}
+
+// discoveredToV1Beta1 derives the v1beta1 analysis status from a v1alpha1 discovery operation.
+func discoveredToV1Beta1(op *lrpb.Operation) *discoverypb.Discovered {
+	d := &discoverypb.Discovered{}
+	switch {
+	case op == nil:
+	case !op.Done:
+		d.AnalysisStatus = discoverypb.Discovered_SCANNING
+	case op.GetError() != nil:
+		d.AnalysisStatus = discoverypb.Discovered_FINISHED_FAILED
+		d.AnalysisStatusError = op.GetError()
+	default:
+		d.AnalysisStatus = discoverypb.Discovered_FINISHED_SUCCESS
+	}
+	return d
+}
+
+// discoveredToV1Alpha1 returns a v1alpha1 discovery operation reflecting a v1beta1 analysis
+// status, or nil if the status is unspecified.
+func discoveredToV1Alpha1(d *discoverypb.Discovered) *lrpb.Operation {
+	switch d.GetAnalysisStatus() {
+	case discoverypb.Discovered_ANALYSIS_STATUS_UNSPECIFIED:
+		return nil
+	case discoverypb.Discovered_PENDING, discoverypb.Discovered_SCANNING:
+		return &lrpb.Operation{}
+	case discoverypb.Discovered_FINISHED_FAILED:
+		if d.AnalysisStatusError != nil {
+			return &lrpb.Operation{Done: true, Result: &lrpb.Operation_Error{Error: d.AnalysisStatusError}}
+		}
+	}
+	return &lrpb.Operation{Done: true}
+}
+
+func provenanceToV1Beta1(p *alphapb.BuildProvenance) *provpb.BuildProvenance {
+	if p == nil {
+		return nil
+	}
+	out := &provpb.BuildProvenance{
+		Id:             p.Id,
+		ProjectId:      p.ProjectId,
+		CreateTime:     p.CreateTime,
+		StartTime:      p.StartTime,
+		EndTime:        p.FinishTime,
+		Creator:        p.Creator,
+		LogsUri:        p.LogsBucket,
+		TriggerId:      p.TriggerId,
+		BuildOptions:   p.BuildOptions,
+		BuilderVersion: p.BuilderVersion,
+	}
+	for _, c := range p.Commands {
+		out.Commands = append(out.Commands, &provpb.Command{Name: c.Name, Env: c.Env, Args: c.Args, Dir: c.Dir, Id: c.Id, WaitFor: c.WaitFor})
+	}
+	for _, a := range p.BuiltArtifacts {
+		names := a.Names
+		if len(names) == 0 && a.Name != "" {
+			names = []string{a.Name}
+		}
+		out.BuiltArtifacts = append(out.BuiltArtifacts, &provpb.Artifact{Checksum: a.Checksum, Id: a.Id, Names: names})
+	}
+	if s := p.SourceProvenance; s != nil {
+		out.SourceProvenance = &provpb.Source{
+			ArtifactStorageSourceUri: storageSourceURI(s.ArtifactStorageSource),
+			Context:                  sourceContextToV1Beta1(s.Context),
+		}
+		for _, c := range s.AdditionalContexts {
+			out.SourceProvenance.AdditionalContexts = append(out.SourceProvenance.AdditionalContexts, sourceContextToV1Beta1(c))
+		}
+		if s.FileHashes != nil {
+			out.SourceProvenance.FileHashes = map[string]*provpb.FileHashes{}
+			for f, hashes := range s.FileHashes {
+				fh := &provpb.FileHashes{}
+				for _, h := range hashes.GetFileHash() {
+					fh.FileHash = append(fh.FileHash, &provpb.Hash{Type: provpb.Hash_HashType(h.Type), Value: h.Value})
+				}
+				out.SourceProvenance.FileHashes[f] = fh
+			}
+		}
+	}
+	return out
+}
+
+func provenanceToV1Alpha1(p *provpb.BuildProvenance) *alphapb.BuildProvenance {
+	if p == nil {
+		return nil
+	}
+	out := &alphapb.BuildProvenance{
+		Id:             p.Id,
+		ProjectId:      p.ProjectId,
+		CreateTime:     p.CreateTime,
+		StartTime:      p.StartTime,
+		FinishTime:     p.EndTime,
+		Creator:        p.Creator,
+		LogsBucket:     p.LogsUri,
+		TriggerId:      p.TriggerId,
+		BuildOptions:   p.BuildOptions,
+		BuilderVersion: p.BuilderVersion,
+	}
+	for _, c := range p.Commands {
+		out.Commands = append(out.Commands, &alphapb.Command{Name: c.Name, Env: c.Env, Args: c.Args, Dir: c.Dir, Id: c.Id, WaitFor: c.WaitFor})
+	}
+	for _, a := range p.BuiltArtifacts {
+		out.BuiltArtifacts = append(out.BuiltArtifacts, &alphapb.Artifact{Checksum: a.Checksum, Id: a.Id, Names: a.Names})
+	}
+	if s := p.SourceProvenance; s != nil {
+		out.SourceProvenance = &alphapb.Source{
+			ArtifactStorageSource: storageSource(s.ArtifactStorageSourceUri),
+			Context:               sourceContextToV1Alpha1(s.Context),
+		}
+		for _, c := range s.AdditionalContexts {
+			out.SourceProvenance.AdditionalContexts = append(out.SourceProvenance.AdditionalContexts, sourceContextToV1Alpha1(c))
+		}
+		if s.FileHashes != nil {
+			out.SourceProvenance.FileHashes = map[string]*alphapb.FileHashes{}
+			for f, hashes := range s.FileHashes {
+				fh := &alphapb.FileHashes{}
+				for _, h := range hashes.GetFileHash() {
+					fh.FileHash = append(fh.FileHash, &alphapb.Hash{Type: alphapb.Hash_HashType(h.Type), Value: h.Value})
+				}
+				out.SourceProvenance.FileHashes[f] = fh
+			}
+		}
+	}
+	return out
+}
+
+// storageSourceURI returns the gs:// URI of a v1alpha1 storage source, with its generation as the
+// fragment if it has one.
+func storageSourceURI(s *alphapb.StorageSource) string {
+	if s == nil {
+		return ""
+	}
+	uri := fmt.Sprintf("gs://%s/%s", s.Bucket, s.Object)
+	if s.Generation != 0 {
+		uri += fmt.Sprintf("#%d", s.Generation)
+	}
+	return uri
+}
+
+// storageSource parses a gs:// URI into a v1alpha1 storage source, or returns nil if it isn't one.
+func storageSource(uri string) *alphapb.StorageSource {
+	if !strings.HasPrefix(uri, "gs://") {
+		return nil
+	}
+	uri = strings.TrimPrefix(uri, "gs://")
+	s := &alphapb.StorageSource{}
+	if i := strings.LastIndex(uri, "#"); i >= 0 {
+		gen, err := strconv.ParseInt(uri[i+1:], 10, 64)
+		if err != nil {
+			return nil
+		}
+		s.Generation, uri = gen, uri[:i]
+	}
+	parts := strings.SplitN(uri, "/", 2)
+	s.Bucket = parts[0]
+	if len(parts) == 2 {
+		s.Object = parts[1]
+	}
+	return s
+}
+
+func sourceContextToV1Beta1(c *alphapb.SourceContext) *srcpb.SourceContext {
+	if c == nil {
+		return nil
+	}
+	out := &srcpb.SourceContext{Labels: c.Labels}
+	switch t := c.Context.(type) {
+	case *alphapb.SourceContext_CloudRepo:
+		repo := &srcpb.CloudRepoSourceContext{}
+		switch id := t.CloudRepo.GetRepoId().GetId().(type) {
+		case *alphapb.RepoId_ProjectRepoId:
+			repo.RepoId = &srcpb.RepoId{Id: &srcpb.RepoId_ProjectRepoId{ProjectRepoId: &srcpb.ProjectRepoId{
+				ProjectId: id.ProjectRepoId.GetProjectId(),
+				RepoName:  id.ProjectRepoId.GetRepoName(),
+			}}}
+		case *alphapb.RepoId_Uid:
+			repo.RepoId = &srcpb.RepoId{Id: &srcpb.RepoId_Uid{Uid: id.Uid}}
+		}
+		switch r := t.CloudRepo.Revision.(type) {
+		case *alphapb.CloudRepoSourceContext_RevisionId:
+			repo.Revision = &srcpb.CloudRepoSourceContext_RevisionId{RevisionId: r.RevisionId}
+		case *alphapb.CloudRepoSourceContext_AliasContext:
+			repo.Revision = &srcpb.CloudRepoSourceContext_AliasContext{AliasContext: aliasContextToV1Beta1(r.AliasContext)}
+		}
+		out.Context = &srcpb.SourceContext_CloudRepo{CloudRepo: repo}
+	case *alphapb.SourceContext_Gerrit:
+		gerrit := &srcpb.GerritSourceContext{HostUri: t.Gerrit.HostUri, GerritProject: t.Gerrit.GerritProject}
+		switch r := t.Gerrit.Revision.(type) {
+		case *alphapb.GerritSourceContext_RevisionId:
+			gerrit.Revision = &srcpb.GerritSourceContext_RevisionId{RevisionId: r.RevisionId}
+		case *alphapb.GerritSourceContext_AliasContext:
+			gerrit.Revision = &srcpb.GerritSourceContext_AliasContext{AliasContext: aliasContextToV1Beta1(r.AliasContext)}
+		}
+		out.Context = &srcpb.SourceContext_Gerrit{Gerrit: gerrit}
+	case *alphapb.SourceContext_Git:
+		out.Context = &srcpb.SourceContext_Git{Git: &srcpb.GitSourceContext{Url: t.Git.Url, RevisionId: t.Git.RevisionId}}
+	}
+	return out
+}
+
+func sourceContextToV1Alpha1(c *srcpb.SourceContext) *alphapb.SourceContext {
+	if c == nil {
+		return nil
+	}
+	out := &alphapb.SourceContext{Labels: c.Labels}
+	switch t := c.Context.(type) {
+	case *srcpb.SourceContext_CloudRepo:
+		repo := &alphapb.CloudRepoSourceContext{}
+		switch id := t.CloudRepo.GetRepoId().GetId().(type) {
+		case *srcpb.RepoId_ProjectRepoId:
+			repo.RepoId = &alphapb.RepoId{Id: &alphapb.RepoId_ProjectRepoId{ProjectRepoId: &alphapb.ProjectRepoId{
+				ProjectId: id.ProjectRepoId.GetProjectId(),
+				RepoName:  id.ProjectRepoId.GetRepoName(),
+			}}}
+		case *srcpb.RepoId_Uid:
+			repo.RepoId = &alphapb.RepoId{Id: &alphapb.RepoId_Uid{Uid: id.Uid}}
+		}
+		switch r := t.CloudRepo.Revision.(type) {
+		case *srcpb.CloudRepoSourceContext_RevisionId:
+			repo.Revision = &alphapb.CloudRepoSourceContext_RevisionId{RevisionId: r.RevisionId}
+		case *srcpb.CloudRepoSourceContext_AliasContext:
+			repo.Revision = &alphapb.CloudRepoSourceContext_AliasContext{AliasContext: aliasContextToV1Alpha1(r.AliasContext)}
+		}
+		out.Context = &alphapb.SourceContext_CloudRepo{CloudRepo: repo}
+	case *srcpb.SourceContext_Gerrit:
+		gerrit := &alphapb.GerritSourceContext{HostUri: t.Gerrit.HostUri, GerritProject: t.Gerrit.GerritProject}
+		switch r := t.Gerrit.Revision.(type) {
+		case *srcpb.GerritSourceContext_RevisionId:
+			gerrit.Revision = &alphapb.GerritSourceContext_RevisionId{RevisionId: r.RevisionId}
+		case *srcpb.GerritSourceContext_AliasContext:
+			gerrit.Revision = &alphapb.GerritSourceContext_AliasContext{AliasContext: aliasContextToV1Alpha1(r.AliasContext)}
+		}
+		out.Context = &alphapb.SourceContext_Gerrit{Gerrit: gerrit}
+	case *srcpb.SourceContext_Git:
+		out.Context = &alphapb.SourceContext_Git{Git: &alphapb.GitSourceContext{Url: t.Git.Url, RevisionId: t.Git.RevisionId}}
+	}
+	return out
+}
+
+func aliasContextToV1Beta1(a *alphapb.AliasContext) *srcpb.AliasContext {
+	if a == nil {
+		return nil
+	}
+	return &srcpb.AliasContext{Kind: srcpb.AliasContext_Kind(a.Kind), Name: a.Name}
+}
+
+func aliasContextToV1Alpha1(a *srcpb.AliasContext) *alphapb.AliasContext {
+	if a == nil {
+		return nil
+	}
+	return &alphapb.AliasContext{Kind: alphapb.AliasContext_Kind(a.Kind), Name: a.Name}
+}
+
+// NoteToV1Beta1 converts a v1alpha1 note to v1beta1, also returning the paths of its set fields
+// that can't be converted.
+func NoteToV1Beta1(n *alphapb.Note) (*gpb.Note, []string) {
+	var dropped []string
+	if _, ok := noteKinds[n.GetKind()]; !ok {
+		dropped = append(dropped, "kind")
+	}
+	if n.GetOperationName() != "" {
+		dropped = append(dropped, "operation_name")
+	}
+	return noteToV1Beta1(n), dropped
+}
+
+// OccurrenceToV1Beta1 converts a v1alpha1 occurrence to v1beta1, also returning the paths of its
+// set fields that can't be converted.
+func OccurrenceToV1Beta1(o *alphapb.Occurrence) (*gpb.Occurrence, []string) {
+	var dropped []string
+	if _, ok := noteKinds[o.GetKind()]; !ok {
+		dropped = append(dropped, "kind")
+	}
+	if o.GetOperationName() != "" {
+		dropped = append(dropped, "operation_name")
+	}
+	if p := o.GetBuildDetails().GetProvenance(); p != nil {
+		for i, a := range p.BuiltArtifacts {
+			if a.Name != "" && len(a.Names) > 0 {
+				dropped = append(dropped, fmt.Sprintf("build_details.provenance.built_artifacts[%d].name", i))
+			}
+		}
+		if p.GetSourceProvenance().GetSource() != nil {
+			dropped = append(dropped, "build_details.provenance.source_provenance.source")
+		}
+	}
+	if op := o.GetDiscoveredDetails().GetOperation(); op != nil {
+		if op.Name != "" {
+			dropped = append(dropped, "discovered_details.operation.name")
+		}
+		if op.Metadata != nil {
+			dropped = append(dropped, "discovered_details.operation.metadata")
+		}
+		if op.GetResponse() != nil {
+			dropped = append(dropped, "discovered_details.operation.response")
+		}
+	}
+	return occurrenceToV1Beta1(o), dropped
+}
@@ -0,0 +1,367 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package legacy serves the v1alpha1 Grafeas API by translating its calls onto a v1beta1 API
+// server, so tools written against v1alpha1 keep working while it is deprecated.
+//
+// Filters are passed through to the v1beta1 server unchanged, so they refer to v1beta1 field
+// names. Fields without a v1beta1 counterpart, such as the operation names of notes and
+// occurrences, are dropped, and v1beta1 fields without a v1alpha1 counterpart survive updates.
+package legacy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/grafeas/grafeas/go/errors"
+	grafeas "github.com/grafeas/grafeas/go/v1beta1/api"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/fieldmask"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
+	alphapb "github.com/grafeas/grafeas/v1alpha1/proto"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+)
+
+// occurrenceFieldsV1Beta1 maps the top level fields of v1alpha1 occurrences to those of v1beta1
+// where their names differ. Fields mapped to "" have no v1beta1 counterpart.
+var occurrenceFieldsV1Beta1 = map[string]string{
+	"resource_url":          "resource.uri",
+	"vulnerability_details": "vulnerability",
+	"build_details":         "build",
+	"derived_image_details": "derived_image",
+	"installation_details":  "installation",
+	"deployment_details":    "deployment",
+	"discovered_details":    "discovered",
+	"attestation_details":   "attestation",
+	"operation_name":        "",
+}
+
+// noteFieldsV1Beta1 maps the top level fields of v1alpha1 notes to those of v1beta1 where their
+// names differ. Fields mapped to "" have no v1beta1 counterpart.
+var noteFieldsV1Beta1 = map[string]string{
+	"vulnerability_type": "vulnerability",
+	"build_type":         "build",
+	"operation_name":     "",
+}
+
+// Server implements the v1alpha1 Grafeas and GrafeasProjects services on top of the v1beta1 API.
+type Server struct {
+	Grafeas  gpb.GrafeasV1Beta1Server
+	Projects prpb.ProjectsServer
+	// Operations is optional, CreateOperation and UpdateOperation are unimplemented without it.
+	Operations grafeas.Operations
+}
+
+var (
+	_ alphapb.GrafeasServer         = (*Server)(nil)
+	_ alphapb.GrafeasProjectsServer = (*Server)(nil)
+)
+
+// GetOccurrence gets the specified occurrence.
+func (s *Server) GetOccurrence(ctx context.Context, req *alphapb.GetOccurrenceRequest) (*alphapb.Occurrence, error) {
+	o, err := s.Grafeas.GetOccurrence(ctx, &gpb.GetOccurrenceRequest{Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+	return occurrenceToV1Alpha1(o), nil
+}
+
+// ListOccurrences lists the occurrences of the specified project.
+func (s *Server) ListOccurrences(ctx context.Context, req *alphapb.ListOccurrencesRequest) (*alphapb.ListOccurrencesResponse, error) {
+	resp, err := s.Grafeas.ListOccurrences(ctx, &gpb.ListOccurrencesRequest{
+		Parent:    req.Parent,
+		Filter:    req.Filter,
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := &alphapb.ListOccurrencesResponse{NextPageToken: resp.NextPageToken}
+	for _, o := range resp.Occurrences {
+		out.Occurrences = append(out.Occurrences, occurrenceToV1Alpha1(o))
+	}
+	return out, nil
+}
+
+// DeleteOccurrence deletes the specified occurrence.
+func (s *Server) DeleteOccurrence(ctx context.Context, req *alphapb.DeleteOccurrenceRequest) (*empty.Empty, error) {
+	return s.Grafeas.DeleteOccurrence(ctx, &gpb.DeleteOccurrenceRequest{Name: req.Name})
+}
+
+// CreateOccurrence creates the specified occurrence.
+func (s *Server) CreateOccurrence(ctx context.Context, req *alphapb.CreateOccurrenceRequest) (*alphapb.Occurrence, error) {
+	o, err := s.Grafeas.CreateOccurrence(ctx, &gpb.CreateOccurrenceRequest{
+		Parent:     req.Parent,
+		Occurrence: occurrenceToV1Beta1(req.Occurrence),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return occurrenceToV1Alpha1(o), nil
+}
+
+// UpdateOccurrence updates the fields of the specified occurrence in the update mask, or replaces
+// it if there is no mask.
+func (s *Server) UpdateOccurrence(ctx context.Context, req *alphapb.UpdateOccurrenceRequest) (*alphapb.Occurrence, error) {
+	if req.Occurrence == nil {
+		return nil, errors.Newf(codes.InvalidArgument, "an occurrence is required")
+	}
+	o, mask := occurrenceToV1Beta1(req.Occurrence), (*fieldmaskpb.FieldMask)(nil)
+	if len(req.UpdateMask.GetPaths()) > 0 {
+		existing, err := s.Grafeas.GetOccurrence(ctx, &gpb.GetOccurrenceRequest{Name: req.Name})
+		if err != nil {
+			return nil, err
+		}
+		merged := occurrenceToV1Alpha1(existing)
+		if err := fieldmask.Apply(merged, req.Occurrence, req.UpdateMask); err != nil {
+			return nil, err
+		}
+		// Only the masked fields of the existing occurrence are replaced, which keeps the fields
+		// v1alpha1 can't represent.
+		mask = v1Beta1Mask(req.UpdateMask, occurrenceFieldsV1Beta1)
+		o = proto.Clone(existing).(*gpb.Occurrence)
+		if err := fieldmask.Apply(o, occurrenceToV1Beta1(merged), mask); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := s.Grafeas.UpdateOccurrence(ctx, &gpb.UpdateOccurrenceRequest{
+		Name:       req.Name,
+		Occurrence: o,
+		UpdateMask: mask,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return occurrenceToV1Alpha1(updated), nil
+}
+
+// GetOccurrenceNote gets the note of the specified occurrence.
+func (s *Server) GetOccurrenceNote(ctx context.Context, req *alphapb.GetOccurrenceNoteRequest) (*alphapb.Note, error) {
+	n, err := s.Grafeas.GetOccurrenceNote(ctx, &gpb.GetOccurrenceNoteRequest{Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+	return noteToV1Alpha1(n), nil
+}
+
+// CreateOperation creates the specified operation in the parent project.
+func (s *Server) CreateOperation(ctx context.Context, req *alphapb.CreateOperationRequest) (*lrpb.Operation, error) {
+	if s.Operations == nil {
+		return nil, errors.Newf(codes.Unimplemented, "operations aren't supported")
+	}
+	pID, err := name.ParseProject(req.Parent)
+	if err != nil {
+		return nil, err
+	}
+	if req.Operation == nil {
+		return nil, errors.Newf(codes.InvalidArgument, "an operation is required")
+	}
+	op := proto.Clone(req.Operation).(*lrpb.Operation)
+	op.Name = name.FormatOperation(pID, req.OperationId)
+	if err := s.Operations.CreateOperation(ctx, pID, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// UpdateOperation replaces the specified operation.
+func (s *Server) UpdateOperation(ctx context.Context, req *alphapb.UpdateOperationRequest) (*lrpb.Operation, error) {
+	if s.Operations == nil {
+		return nil, errors.Newf(codes.Unimplemented, "operations aren't supported")
+	}
+	pID, _, err := name.ParseOperation(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if req.Operation == nil {
+		return nil, errors.Newf(codes.InvalidArgument, "an operation is required")
+	}
+	op := proto.Clone(req.Operation).(*lrpb.Operation)
+	op.Name = req.Name
+	if err := s.Operations.UpdateOperation(ctx, pID, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// GetNote gets the specified note.
+func (s *Server) GetNote(ctx context.Context, req *alphapb.GetNoteRequest) (*alphapb.Note, error) {
+	n, err := s.Grafeas.GetNote(ctx, &gpb.GetNoteRequest{Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+	return noteToV1Alpha1(n), nil
+}
+
+// ListNotes lists the notes of the specified project.
+func (s *Server) ListNotes(ctx context.Context, req *alphapb.ListNotesRequest) (*alphapb.ListNotesResponse, error) {
+	resp, err := s.Grafeas.ListNotes(ctx, &gpb.ListNotesRequest{
+		Parent:    req.Parent,
+		Filter:    req.Filter,
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := &alphapb.ListNotesResponse{NextPageToken: resp.NextPageToken}
+	for _, n := range resp.Notes {
+		out.Notes = append(out.Notes, noteToV1Alpha1(n))
+	}
+	return out, nil
+}
+
+// DeleteNote deletes the specified note.
+func (s *Server) DeleteNote(ctx context.Context, req *alphapb.DeleteNoteRequest) (*empty.Empty, error) {
+	return s.Grafeas.DeleteNote(ctx, &gpb.DeleteNoteRequest{Name: req.Name})
+}
+
+// CreateNote creates the specified note. Notes without a name are named after the parent and note
+// ID of the request.
+func (s *Server) CreateNote(ctx context.Context, req *alphapb.CreateNoteRequest) (*alphapb.Note, error) {
+	n := noteToV1Beta1(req.Note)
+	if n != nil && n.Name == "" && req.NoteId != "" {
+		pID, err := name.ParseProject(req.Parent)
+		if err != nil {
+			return nil, err
+		}
+		n.Name = name.FormatNote(pID, req.NoteId)
+	}
+	created, err := s.Grafeas.CreateNote(ctx, &gpb.CreateNoteRequest{
+		Parent: req.Parent,
+		NoteId: req.NoteId,
+		Note:   n,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return noteToV1Alpha1(created), nil
+}
+
+// UpdateNote updates the fields of the specified note in the update mask, or replaces it if there
+// is no mask.
+func (s *Server) UpdateNote(ctx context.Context, req *alphapb.UpdateNoteRequest) (*alphapb.Note, error) {
+	if req.Note == nil {
+		return nil, errors.Newf(codes.InvalidArgument, "a note is required")
+	}
+	n, mask := noteToV1Beta1(req.Note), (*fieldmaskpb.FieldMask)(nil)
+	if len(req.UpdateMask.GetPaths()) > 0 {
+		existing, err := s.Grafeas.GetNote(ctx, &gpb.GetNoteRequest{Name: req.Name})
+		if err != nil {
+			return nil, err
+		}
+		merged := noteToV1Alpha1(existing)
+		if err := fieldmask.Apply(merged, req.Note, req.UpdateMask); err != nil {
+			return nil, err
+		}
+		mask = v1Beta1Mask(req.UpdateMask, noteFieldsV1Beta1)
+		n = proto.Clone(existing).(*gpb.Note)
+		if err := fieldmask.Apply(n, noteToV1Beta1(merged), mask); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := s.Grafeas.UpdateNote(ctx, &gpb.UpdateNoteRequest{
+		Name:       req.Name,
+		Note:       n,
+		UpdateMask: mask,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return noteToV1Alpha1(updated), nil
+}
+
+// ListNoteOccurrences lists the occurrences of the specified note.
+func (s *Server) ListNoteOccurrences(ctx context.Context, req *alphapb.ListNoteOccurrencesRequest) (*alphapb.ListNoteOccurrencesResponse, error) {
+	resp, err := s.Grafeas.ListNoteOccurrences(ctx, &gpb.ListNoteOccurrencesRequest{
+		Name:      req.Name,
+		Filter:    req.Filter,
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := &alphapb.ListNoteOccurrencesResponse{NextPageToken: resp.NextPageToken}
+	for _, o := range resp.Occurrences {
+		out.Occurrences = append(out.Occurrences, occurrenceToV1Alpha1(o))
+	}
+	return out, nil
+}
+
+// CreateProject creates the specified project.
+func (s *Server) CreateProject(ctx context.Context, req *alphapb.CreateProjectRequest) (*empty.Empty, error) {
+	if req.Project == nil {
+		return nil, errors.Newf(codes.InvalidArgument, "a project is required")
+	}
+	if _, err := s.Projects.CreateProject(ctx, &prpb.CreateProjectRequest{Project: &prpb.Project{Name: req.Project.Name}}); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+// GetProject gets the specified project.
+func (s *Server) GetProject(ctx context.Context, req *alphapb.GetProjectRequest) (*alphapb.Project, error) {
+	p, err := s.Projects.GetProject(ctx, &prpb.GetProjectRequest{Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+	return &alphapb.Project{Name: p.Name}, nil
+}
+
+// ListProjects lists projects.
+func (s *Server) ListProjects(ctx context.Context, req *alphapb.ListProjectsRequest) (*alphapb.ListProjectsResponse, error) {
+	resp, err := s.Projects.ListProjects(ctx, &prpb.ListProjectsRequest{
+		Filter:    req.Filter,
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := &alphapb.ListProjectsResponse{NextPageToken: resp.NextPageToken}
+	for _, p := range resp.Projects {
+		out.Projects = append(out.Projects, &alphapb.Project{Name: p.Name})
+	}
+	return out, nil
+}
+
+// DeleteProject deletes the specified project.
+func (s *Server) DeleteProject(ctx context.Context, req *alphapb.DeleteProjectRequest) (*empty.Empty, error) {
+	return s.Projects.DeleteProject(ctx, &prpb.DeleteProjectRequest{Name: req.Name})
+}
+
+// v1Beta1Mask returns the v1beta1 update mask replacing the top level fields touched by the
+// specified v1alpha1 mask, renamed with fields.
+func v1Beta1Mask(mask *fieldmaskpb.FieldMask, fields map[string]string) *fieldmaskpb.FieldMask {
+	out := &fieldmaskpb.FieldMask{}
+	seen := map[string]bool{}
+	for _, p := range mask.GetPaths() {
+		f := strings.Split(p, ".")[0]
+		if betaF, ok := fields[f]; ok {
+			f = betaF
+		}
+		if f != "" && !seen[f] {
+			seen[f] = true
+			out.Paths = append(out.Paths, f)
+		}
+	}
+	return out
+}
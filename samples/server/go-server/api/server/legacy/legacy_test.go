@@ -0,0 +1,273 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package legacy
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	cpb "github.com/grafeas/grafeas/proto/v1beta1/common_go_proto"
+	gpb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/bridge"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/v1alpha1"
+	alphapb "github.com/grafeas/grafeas/v1alpha1/proto"
+	"golang.org/x/net/context"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	fieldmaskpb "google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewMemStore()
+	beta := &v1alpha1.Grafeas{S: st}
+	s := &Server{Grafeas: beta, Projects: beta, Operations: bridge.New(st)}
+
+	for _, p := range []string{"projects/goog-vulnz", "projects/consumer1"} {
+		if _, err := s.CreateProject(ctx, &alphapb.CreateProjectRequest{Project: &alphapb.Project{Name: p}}); err != nil {
+			t.Fatalf("CreateProject(%q) got err %v, want success", p, err)
+		}
+	}
+	ps, err := s.ListProjects(ctx, &alphapb.ListProjectsRequest{PageSize: 10})
+	if err != nil {
+		t.Fatalf("ListProjects got err %v, want success", err)
+	}
+	if len(ps.Projects) != 2 {
+		t.Errorf("ListProjects got %v, want 2 projects", ps.Projects)
+	}
+
+	n, err := s.CreateNote(ctx, &alphapb.CreateNoteRequest{
+		Parent: "projects/goog-vulnz",
+		NoteId: "CVE-2014-9911",
+		Note: &alphapb.Note{
+			ShortDescription: "CVE-2014-9911",
+			Kind:             alphapb.Note_PACKAGE_VULNERABILITY,
+			NoteType: &alphapb.Note_VulnerabilityType{VulnerabilityType: &alphapb.VulnerabilityType{
+				Severity: alphapb.VulnerabilityType_HIGH,
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateNote got err %v, want success", err)
+	}
+	if n.Name != "projects/goog-vulnz/notes/CVE-2014-9911" || n.Kind != alphapb.Note_PACKAGE_VULNERABILITY {
+		t.Errorf("CreateNote got %v, want a named vulnerability note", n)
+	}
+	// Notes created through v1alpha1 are stored as v1beta1 notes.
+	betaN, err := beta.GetNote(ctx, &gpb.GetNoteRequest{Name: n.Name})
+	if err != nil {
+		t.Fatalf("GetNote got err %v, want success", err)
+	}
+	if betaN.GetVulnerability() == nil {
+		t.Errorf("GetNote got %v, want a v1beta1 vulnerability note", betaN)
+	}
+
+	o, err := s.CreateOccurrence(ctx, &alphapb.CreateOccurrenceRequest{
+		Parent: "projects/consumer1",
+		Occurrence: &alphapb.Occurrence{
+			ResourceUrl: "debian",
+			NoteName:    n.Name,
+			Kind:        alphapb.Note_PACKAGE_VULNERABILITY,
+			Details: &alphapb.Occurrence_VulnerabilityDetails{VulnerabilityDetails: &alphapb.VulnerabilityType_VulnerabilityDetails{
+				Severity: alphapb.VulnerabilityType_HIGH,
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateOccurrence got err %v, want success", err)
+	}
+	if o.Name == "" || o.ResourceUrl != "debian" {
+		t.Errorf("CreateOccurrence got %v, want a named occurrence of debian", o)
+	}
+	os, err := s.ListNoteOccurrences(ctx, &alphapb.ListNoteOccurrencesRequest{Name: n.Name, PageSize: 10})
+	if err != nil {
+		t.Fatalf("ListNoteOccurrences got err %v, want success", err)
+	}
+	if len(os.Occurrences) != 1 || os.Occurrences[0].ResourceUrl != "debian" {
+		t.Errorf("ListNoteOccurrences got %v, want the debian occurrence", os.Occurrences)
+	}
+
+	// Masked updates keep the v1beta1 fields they don't touch, even those without a v1alpha1
+	// counterpart.
+	betaO, err := beta.GetOccurrence(ctx, &gpb.GetOccurrenceRequest{Name: o.Name})
+	if err != nil {
+		t.Fatalf("GetOccurrence got err %v, want success", err)
+	}
+	betaO.Resource.Name = "debian"
+	if _, err := beta.UpdateOccurrence(ctx, &gpb.UpdateOccurrenceRequest{Name: o.Name, Occurrence: betaO}); err != nil {
+		t.Fatalf("UpdateOccurrence got err %v, want success", err)
+	}
+	got, err := s.UpdateOccurrence(ctx, &alphapb.UpdateOccurrenceRequest{
+		Name:       o.Name,
+		Occurrence: &alphapb.Occurrence{ResourceUrl: "debian:9", Remediation: "upgrade", OperationName: "ignored"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"resource_url", "remediation", "operation_name"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateOccurrence got err %v, want success", err)
+	}
+	if got.ResourceUrl != "debian:9" || got.Remediation != "upgrade" || got.GetVulnerabilityDetails() == nil {
+		t.Errorf("UpdateOccurrence got %v, want the resource and remediation updated and details kept", got)
+	}
+	betaO, err = beta.GetOccurrence(ctx, &gpb.GetOccurrenceRequest{Name: o.Name})
+	if err != nil {
+		t.Fatalf("GetOccurrence got err %v, want success", err)
+	}
+	if betaO.Resource.Name != "debian" || betaO.Resource.Uri != "debian:9" {
+		t.Errorf("UpdateOccurrence stored resource %v, want the name kept and the URI updated", betaO.Resource)
+	}
+	if _, err := s.UpdateOccurrence(ctx, &alphapb.UpdateOccurrenceRequest{
+		Name:       o.Name,
+		Occurrence: &alphapb.Occurrence{},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"resource"}},
+	}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdateOccurrence with a v1beta1 path got err %v, want %v", err, codes.InvalidArgument)
+	}
+
+	// Renamed note types are mapped to their v1beta1 names.
+	n, err = s.UpdateNote(ctx, &alphapb.UpdateNoteRequest{
+		Name: n.Name,
+		Note: &alphapb.Note{NoteType: &alphapb.Note_VulnerabilityType{VulnerabilityType: &alphapb.VulnerabilityType{
+			Severity: alphapb.VulnerabilityType_LOW,
+		}}},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"vulnerability_type.severity"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateNote got err %v, want success", err)
+	}
+	if n.GetVulnerabilityType().GetSeverity() != alphapb.VulnerabilityType_LOW || n.ShortDescription != "CVE-2014-9911" {
+		t.Errorf("UpdateNote got %v, want the severity updated and the description kept", n)
+	}
+
+	op, err := s.CreateOperation(ctx, &alphapb.CreateOperationRequest{
+		Parent:      "projects/consumer1",
+		OperationId: "scan",
+		Operation:   &lrpb.Operation{},
+	})
+	if err != nil {
+		t.Fatalf("CreateOperation got err %v, want success", err)
+	}
+	if op.Name != "projects/consumer1/operations/scan" {
+		t.Errorf("CreateOperation got name %q, want projects/consumer1/operations/scan", op.Name)
+	}
+	if op, err = s.UpdateOperation(ctx, &alphapb.UpdateOperationRequest{Name: op.Name, Operation: &lrpb.Operation{Done: true}}); err != nil || !op.Done {
+		t.Errorf("UpdateOperation got %v, %v, want a done operation", op, err)
+	}
+	noOps := &Server{Grafeas: beta, Projects: beta}
+	if _, err := noOps.CreateOperation(ctx, &alphapb.CreateOperationRequest{Parent: "projects/consumer1", OperationId: "scan", Operation: &lrpb.Operation{}}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("CreateOperation without operations got err %v, want %v", err, codes.Unimplemented)
+	}
+
+	if _, err := s.DeleteOccurrence(ctx, &alphapb.DeleteOccurrenceRequest{Name: o.Name}); err != nil {
+		t.Fatalf("DeleteOccurrence got err %v, want success", err)
+	}
+	if _, err := s.GetOccurrence(ctx, &alphapb.GetOccurrenceRequest{Name: o.Name}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetOccurrence after delete got err %v, want %v", err, codes.NotFound)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	tests := []struct {
+		desc string
+		o    *alphapb.Occurrence
+	}{
+		{
+			desc: "build details",
+			o: &alphapb.Occurrence{
+				Name:        "projects/p/occurrences/o",
+				ResourceUrl: "debian",
+				Kind:        alphapb.Note_BUILD_DETAILS,
+				Details: &alphapb.Occurrence_BuildDetails{BuildDetails: &alphapb.BuildDetails{
+					Provenance: &alphapb.BuildProvenance{
+						Id:         "build",
+						LogsBucket: "gs://logs",
+						SourceProvenance: &alphapb.Source{
+							ArtifactStorageSource: &alphapb.StorageSource{Bucket: "b", Object: "o/src.tgz", Generation: 3},
+						},
+					},
+				}},
+			},
+		},
+		{
+			desc: "derived image",
+			o: &alphapb.Occurrence{
+				Kind: alphapb.Note_IMAGE_BASIS,
+				Details: &alphapb.Occurrence_DerivedImageDetails{DerivedImageDetails: &alphapb.DockerImage_DerivedDetails{
+					Distance:  2,
+					LayerInfo: []*alphapb.DockerImage_Layer{{Directive: alphapb.DockerImage_Layer_RUN, Arguments: "make"}},
+				}},
+			},
+		},
+		{
+			desc: "installation",
+			o: &alphapb.Occurrence{
+				Kind: alphapb.Note_PACKAGE_MANAGER,
+				Details: &alphapb.Occurrence_InstallationDetails{InstallationDetails: &alphapb.PackageManager_InstallationDetails{
+					Name: "openssl",
+					Location: []*alphapb.PackageManager_Location{{
+						CpeUri:  "cpe:/o:debian:debian_linux:9",
+						Version: &alphapb.VulnerabilityType_Version{Name: "1.1", Kind: alphapb.VulnerabilityType_Version_NORMAL},
+					}},
+				}},
+			},
+		},
+		{
+			desc: "failed discovery",
+			o: &alphapb.Occurrence{
+				Kind: alphapb.Note_DISCOVERY,
+				Details: &alphapb.Occurrence_DiscoveredDetails{DiscoveredDetails: &alphapb.Discovery_DiscoveredDetails{
+					Operation: &lrpb.Operation{Done: true, Result: &lrpb.Operation_Error{Error: &spb.Status{Message: "failed"}}},
+				}},
+			},
+		},
+		{
+			desc: "attestation",
+			o: &alphapb.Occurrence{
+				Kind: alphapb.Note_ATTESTATION_AUTHORITY,
+				Details: &alphapb.Occurrence_AttestationDetails{AttestationDetails: &alphapb.AttestationAuthority_AttestationDetails{
+					Signature: &alphapb.AttestationAuthority_AttestationDetails_PgpSignedAttestation{PgpSignedAttestation: &alphapb.PgpSignedAttestation{
+						Signature: "sig",
+						KeyId:     &alphapb.PgpSignedAttestation_PgpKeyId{PgpKeyId: "key"},
+					}},
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := occurrenceToV1Alpha1(occurrenceToV1Beta1(tt.o))
+		if diff := cmp.Diff(tt.o, got, cmp.Comparer(proto.Equal)); diff != "" {
+			t.Errorf("%s: round trip returned diff (-want +got):\n%s", tt.desc, diff)
+		}
+	}
+
+	n := &alphapb.Note{
+		Name: "projects/p/notes/n",
+		Kind: alphapb.Note_BUILD_DETAILS,
+		NoteType: &alphapb.Note_BuildType{BuildType: &alphapb.BuildType{
+			BuilderVersion: "1",
+			Signature:      &alphapb.BuildSignature{Signature: "c2ln", KeyType: alphapb.BuildSignature_PGP_ASCII_ARMORED},
+		}},
+	}
+	betaN := noteToV1Beta1(n)
+	if string(betaN.GetBuild().GetSignature().GetSignature()) != "sig" || betaN.Kind != cpb.NoteKind_BUILD {
+		t.Errorf("noteToV1Beta1(%v) got %v, want a decoded signature and the build kind", n, betaN)
+	}
+	if diff := cmp.Diff(n, noteToV1Alpha1(betaN), cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("note round trip returned diff (-want +got):\n%s", diff)
+	}
+}
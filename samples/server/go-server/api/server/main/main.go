@@ -19,9 +19,10 @@ import (
 	"log"
 
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/api"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/bridge"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/config"
-	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
-	server "github.com/grafeas/grafeas/server-go"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/retention"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/webhook"
 )
 
 var (
@@ -34,16 +35,35 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config file: %s", err)
 	}
-	var storager server.Storager
-	switch config.StorageType {
-	case "memstore":
-		storager = storage.NewMemStore()
-	case "postgres":
-		storager = storage.NewPgSQLStore(config.PgSQLConfig)
-	case "embedded":
-		storager = storage.NewEmbeddedStore(config.EmbeddedConfig)
-	default:
-		log.Fatalf("Storage type unsupported: %s", config.StorageType)
+	storager, err := config.Storager()
+	if err != nil {
+		log.Fatalf("Failed to configure storage: %s", err)
+	}
+	if config.Webhooks != nil && len(config.Webhooks.Endpoints) > 0 {
+		d, err := webhook.NewDispatcher(config.Webhooks)
+		if err != nil {
+			log.Fatalf("Failed to configure webhooks: %s", err)
+		}
+		defer d.Close()
+		storager = webhook.Wrap(storager, d)
+	}
+	a, err := api.NewAPI(config.API, bridge.New(storager))
+	if err != nil {
+		log.Fatalf("Failed to configure API: %s", err)
+	}
+	if config.Retention != nil && len(config.Retention.Rules) > 0 {
+		// Expired occurrences are deleted through the API so that they're audited and their
+		// policies purged.
+		w, err := retention.NewSweeper(storager, a, config.Retention)
+		if err != nil {
+			log.Fatalf("Failed to configure retention: %s", err)
+		}
+		defer w.Close()
+		w.Start()
+	}
+	if config.API.ValidatingAPI {
+		api.RunAPI(config.API, a)
+		return
 	}
-	api.Run(config.API, &storager)
+	api.Run(config.API, &storager, a)
 }
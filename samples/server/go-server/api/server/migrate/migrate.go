@@ -0,0 +1,167 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrate copies v1alpha1 projects, notes and occurrences into v1beta1 storage, converting
+// them with the legacy package.
+package migrate
+
+import (
+	"fmt"
+
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/legacy"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
+	server "github.com/grafeas/grafeas/server-go"
+	alphapb "github.com/grafeas/grafeas/v1alpha1/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Source reads v1alpha1 projects, notes and occurrences. Each method calls fn with every entity of
+// its kind and stops at the first error fn returns.
+type Source interface {
+	// Projects calls fn with the name of every project, e.g. "projects/myproject".
+	Projects(fn func(name string) error) error
+	Notes(fn func(*alphapb.Note) error) error
+	Occurrences(fn func(*alphapb.Occurrence) error) error
+}
+
+// Result is the outcome of migrating a project, note or occurrence.
+type Result struct {
+	// Name is the name of the entity.
+	Name string
+	// Dropped are the paths of the fields of the entity that couldn't be converted to v1beta1.
+	Dropped []string
+	// Err is the error writing the entity, if any.
+	Err error
+}
+
+// Stats counts the entities of a migration.
+type Stats struct {
+	// Projects, Notes and Occurrences count the entities written.
+	Projects, Notes, Occurrences int
+	// Existing counts the entities skipped because they already exist in the destination.
+	Existing int
+	// Lossy counts the entities written with dropped fields.
+	Lossy int
+	// Failed counts the entities that couldn't be written.
+	Failed int
+}
+
+// Run copies the projects, notes and occurrences of src to dst, creating the projects of notes and
+// occurrences that src doesn't list. Entities that already exist in dst are skipped, so an
+// interrupted migration can be run again. Run calls report with the result of every entity that
+// had fields dropped or couldn't be written, and only returns an error if src fails.
+func Run(src Source, dst server.Storager, report func(*Result)) (*Stats, error) {
+	m := &migration{dst: dst, report: report, stats: &Stats{}, projects: map[string]bool{}}
+	if err := src.Projects(func(n string) error {
+		pID, err := name.ParseProject(n)
+		if err != nil {
+			m.fail(&Result{Name: n, Err: err})
+			return nil
+		}
+		m.createProject(pID)
+		return nil
+	}); err != nil {
+		return m.stats, fmt.Errorf("failed to read projects: %v", err)
+	}
+	if err := src.Notes(m.note); err != nil {
+		return m.stats, fmt.Errorf("failed to read notes: %v", err)
+	}
+	if err := src.Occurrences(m.occurrence); err != nil {
+		return m.stats, fmt.Errorf("failed to read occurrences: %v", err)
+	}
+	return m.stats, nil
+}
+
+type migration struct {
+	dst    server.Storager
+	report func(*Result)
+	stats  *Stats
+	// projects are the IDs of the projects known to exist in dst.
+	projects map[string]bool
+}
+
+// createProject creates project pID in dst unless it exists, and returns whether it does now.
+func (m *migration) createProject(pID string) bool {
+	if m.projects[pID] {
+		return true
+	}
+	err := m.dst.CreateProject(pID)
+	switch status.Code(err) {
+	case codes.OK:
+		m.stats.Projects++
+	case codes.AlreadyExists:
+	default:
+		m.fail(&Result{Name: name.FormatProject(pID), Err: err})
+		return false
+	}
+	m.projects[pID] = true
+	return true
+}
+
+func (m *migration) note(n *alphapb.Note) error {
+	pID, _, err := name.ParseNote(n.Name)
+	if err != nil {
+		m.fail(&Result{Name: n.Name, Err: err})
+		return nil
+	}
+	if !m.createProject(pID) {
+		return nil
+	}
+	beta, dropped := legacy.NoteToV1Beta1(n)
+	if m.write(&Result{Name: n.Name, Dropped: dropped}, m.dst.CreateNote(beta)) {
+		m.stats.Notes++
+	}
+	return nil
+}
+
+func (m *migration) occurrence(o *alphapb.Occurrence) error {
+	pID, _, err := name.ParseOccurrence(o.Name)
+	if err != nil {
+		m.fail(&Result{Name: o.Name, Err: err})
+		return nil
+	}
+	if !m.createProject(pID) {
+		return nil
+	}
+	beta, dropped := legacy.OccurrenceToV1Beta1(o)
+	if m.write(&Result{Name: o.Name, Dropped: dropped}, m.dst.CreateOccurrence(beta)) {
+		m.stats.Occurrences++
+	}
+	return nil
+}
+
+// write records the result of writing an entity, and returns whether it was written.
+func (m *migration) write(r *Result, err error) bool {
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.AlreadyExists:
+		m.stats.Existing++
+		return false
+	default:
+		r.Err = err
+		m.fail(r)
+		return false
+	}
+	if len(r.Dropped) > 0 {
+		m.stats.Lossy++
+		m.report(r)
+	}
+	return true
+}
+
+func (m *migration) fail(r *Result) {
+	m.stats.Failed++
+	m.report(r)
+}
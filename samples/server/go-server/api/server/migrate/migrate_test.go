@@ -0,0 +1,164 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	discoverypb "github.com/grafeas/grafeas/proto/v1beta1/discovery_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
+	alphapb "github.com/grafeas/grafeas/v1alpha1/proto"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+)
+
+const export = `
+{"projects": [{"name": "projects/goog-vulnz"}], "nextPageToken": ""}
+{"notes": [
+  {"name": "projects/goog-vulnz/notes/CVE-2014-9911", "kind": "PACKAGE_VULNERABILITY",
+   "vulnerabilityType": {"severity": "HIGH"}},
+  {"name": "projects/goog-vulnz/notes/build", "kind": "BUILD_DETAILS", "operation_name": "projects/goog-vulnz/operations/o"}
+]}
+{"occurrences": [
+  {"name": "projects/consumer1/occurrences/debian", "resourceUrl": "debian",
+   "noteName": "projects/goog-vulnz/notes/CVE-2014-9911", "kind": "PACKAGE_VULNERABILITY",
+   "vulnerabilityDetails": {"severity": "HIGH"}},
+  {"name": "occurrences/unnamed", "noteName": "projects/goog-vulnz/notes/CVE-2014-9911"}
+]}
+`
+
+func TestRunJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "export.json")
+	if err := ioutil.WriteFile(path, []byte(export), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := storage.NewMemStore()
+	var results []*Result
+	stats, err := Run(&JSONSource{Paths: []string{path}}, dst, func(r *Result) { results = append(results, r) })
+	if err != nil {
+		t.Fatalf("Run got err %v, want success", err)
+	}
+	want := &Stats{Projects: 2, Notes: 2, Occurrences: 1, Lossy: 1, Failed: 1}
+	if diff := cmp.Diff(want, stats); diff != "" {
+		t.Errorf("Run returned diff (-want +got):\n%s", diff)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Run reported %v, want 2 results", results)
+	}
+	if r := results[0]; r.Name != "projects/goog-vulnz/notes/build" || !cmp.Equal(r.Dropped, []string{"operation_name"}) || r.Err != nil {
+		t.Errorf("Run reported %+v, want the operation name of the build note dropped", r)
+	}
+	if r := results[1]; r.Name != "occurrences/unnamed" || r.Err == nil {
+		t.Errorf("Run reported %+v, want the unnamed occurrence to fail", r)
+	}
+
+	o, err := dst.GetOccurrence("consumer1", "debian")
+	if err != nil {
+		t.Fatalf("GetOccurrence got err %v, want success", err)
+	}
+	if o.GetResource().GetUri() != "debian" || o.GetVulnerability() == nil {
+		t.Errorf("GetOccurrence got %v, want a v1beta1 vulnerability occurrence of debian", o)
+	}
+
+	// Migrating again skips the existing entities.
+	stats, err = Run(&JSONSource{Paths: []string{path}}, dst, func(*Result) {})
+	if err != nil {
+		t.Fatalf("Run got err %v, want success", err)
+	}
+	if want := (&Stats{Existing: 3, Failed: 1}); !cmp.Equal(want, stats) {
+		t.Errorf("Run again got %+v, want %+v", stats, want)
+	}
+}
+
+func TestRunBolt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := bolt.Open(filepath.Join(dir, "grafeas.db"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	entities := map[string]map[string]proto.Message{
+		"projects": {"consumer1": &alphapb.Project{Name: "projects/consumer1"}},
+		"notes": {"projects/consumer1/notes/discovery": &alphapb.Note{
+			Name:     "projects/consumer1/notes/discovery",
+			Kind:     alphapb.Note_DISCOVERY,
+			NoteType: &alphapb.Note_Discovery{Discovery: &alphapb.Discovery{AnalysisKind: alphapb.Note_PACKAGE_VULNERABILITY}},
+		}},
+		"occurrences": {"projects/consumer1/occurrences/scan": &alphapb.Occurrence{
+			Name:        "projects/consumer1/occurrences/scan",
+			ResourceUrl: "debian",
+			NoteName:    "projects/consumer1/notes/discovery",
+			Kind:        alphapb.Note_DISCOVERY,
+			Details: &alphapb.Occurrence_DiscoveredDetails{DiscoveredDetails: &alphapb.Discovery_DiscoveredDetails{
+				Operation: &lrpb.Operation{Name: "projects/consumer1/operations/scan", Done: true},
+			}},
+		}},
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for bucket, msgs := range entities {
+			b, err := tx.CreateBucket([]byte(bucket))
+			if err != nil {
+				return err
+			}
+			for k, m := range msgs {
+				v, err := proto.Marshal(m)
+				if err != nil {
+					return err
+				}
+				if err := b.Put([]byte(k), v); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := storage.NewMemStore()
+	var results []*Result
+	stats, err := Run(&BoltSource{DB: db}, dst, func(r *Result) { results = append(results, r) })
+	if err != nil {
+		t.Fatalf("Run got err %v, want success", err)
+	}
+	if want := (&Stats{Projects: 1, Notes: 1, Occurrences: 1, Lossy: 1}); !cmp.Equal(want, stats) {
+		t.Errorf("Run got %+v, want %+v", stats, want)
+	}
+	if len(results) != 1 || !cmp.Equal(results[0].Dropped, []string{"discovered_details.operation.name"}) {
+		t.Errorf("Run reported %v, want the name of the discovery operation dropped", results)
+	}
+	o, err := dst.GetOccurrence("consumer1", "scan")
+	if err != nil {
+		t.Fatalf("GetOccurrence got err %v, want success", err)
+	}
+	if got := o.GetDiscovered().GetDiscovered().GetAnalysisStatus(); got != discoverypb.Discovered_FINISHED_SUCCESS {
+		t.Errorf("GetOccurrence got analysis status %v, want %v", got, discoverypb.Discovered_FINISHED_SUCCESS)
+	}
+}
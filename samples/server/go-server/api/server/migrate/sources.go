@@ -0,0 +1,209 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	alphapb "github.com/grafeas/grafeas/v1alpha1/proto"
+)
+
+// JSONSource reads v1alpha1 JSON exports. Each file holds a sequence of JSON objects with
+// "projects", "notes" or "occurrences" lists of v1alpha1 entities, such as the responses of the
+// List methods of the v1alpha1 REST API.
+type JSONSource struct {
+	Paths []string
+}
+
+// Projects calls fn with the name of every project in the exports.
+func (s *JSONSource) Projects(fn func(string) error) error {
+	return s.each("projects", func() proto.Message { return &alphapb.Project{} }, func(m proto.Message) error {
+		return fn(m.(*alphapb.Project).Name)
+	})
+}
+
+// Notes calls fn with every note in the exports.
+func (s *JSONSource) Notes(fn func(*alphapb.Note) error) error {
+	return s.each("notes", func() proto.Message { return &alphapb.Note{} }, func(m proto.Message) error {
+		return fn(m.(*alphapb.Note))
+	})
+}
+
+// Occurrences calls fn with every occurrence in the exports.
+func (s *JSONSource) Occurrences(fn func(*alphapb.Occurrence) error) error {
+	return s.each("occurrences", func() proto.Message { return &alphapb.Occurrence{} }, func(m proto.Message) error {
+		return fn(m.(*alphapb.Occurrence))
+	})
+}
+
+// each calls fn with every entity in the lists under key, decoded into a new message.
+func (s *JSONSource) each(key string, newMsg func() proto.Message, fn func(proto.Message) error) error {
+	for _, p := range s.Paths {
+		if err := eachInFile(p, key, newMsg, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func eachInFile(path, key string, newMsg func() proto.Message, fn func(proto.Message) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	u := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	d := json.NewDecoder(f)
+	for {
+		var obj map[string]json.RawMessage
+		if err := d.Decode(&obj); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		raw, ok := obj[key]
+		if !ok {
+			continue
+		}
+		var list []json.RawMessage
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return fmt.Errorf("%s: %s: %v", path, key, err)
+		}
+		for _, e := range list {
+			m := newMsg()
+			if err := u.Unmarshal(bytes.NewReader(e), m); err != nil {
+				return fmt.Errorf("%s: %s: %v", path, key, err)
+			}
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// PgSQLSource reads a v1alpha1 Postgres database in the layout of the Postgres store, whose notes
+// and occurrences tables hold text format protos.
+type PgSQLSource struct {
+	DB *sql.DB
+}
+
+// Projects calls fn with the name of every project in the database.
+func (s *PgSQLSource) Projects(fn func(string) error) error {
+	return s.each(`SELECT id, name FROM projects ORDER BY id`, func(id int64, data string) error {
+		return fn(data)
+	})
+}
+
+// Notes calls fn with every note in the database.
+func (s *PgSQLSource) Notes(fn func(*alphapb.Note) error) error {
+	return s.each(`SELECT id, data FROM notes ORDER BY id`, func(id int64, data string) error {
+		var n alphapb.Note
+		if err := proto.UnmarshalText(data, &n); err != nil {
+			return fmt.Errorf("note %d: %v", id, err)
+		}
+		return fn(&n)
+	})
+}
+
+// Occurrences calls fn with every occurrence in the database.
+func (s *PgSQLSource) Occurrences(fn func(*alphapb.Occurrence) error) error {
+	return s.each(`SELECT id, data FROM occurrences ORDER BY id`, func(id int64, data string) error {
+		var o alphapb.Occurrence
+		if err := proto.UnmarshalText(data, &o); err != nil {
+			return fmt.Errorf("occurrence %d: %v", id, err)
+		}
+		return fn(&o)
+	})
+}
+
+// each calls fn with the ID and data of every row returned by query.
+func (s *PgSQLSource) each(query string, fn func(id int64, data string) error) error {
+	rows, err := s.DB.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id   int64
+			data string
+		)
+		if err := rows.Scan(&id, &data); err != nil {
+			return err
+		}
+		if err := fn(id, data); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// BoltSource reads a v1alpha1 bolt database in the layout of the embedded store, whose buckets hold
+// binary protos.
+type BoltSource struct {
+	DB *bolt.DB
+}
+
+// Projects calls fn with the name of every project in the database.
+func (s *BoltSource) Projects(fn func(string) error) error {
+	return s.each("projects", func(k []byte, v []byte) error {
+		var p alphapb.Project
+		if err := proto.Unmarshal(v, &p); err != nil {
+			return fmt.Errorf("project %q: %v", k, err)
+		}
+		return fn(p.Name)
+	})
+}
+
+// Notes calls fn with every note in the database.
+func (s *BoltSource) Notes(fn func(*alphapb.Note) error) error {
+	return s.each("notes", func(k []byte, v []byte) error {
+		var n alphapb.Note
+		if err := proto.Unmarshal(v, &n); err != nil {
+			return fmt.Errorf("note %q: %v", k, err)
+		}
+		return fn(&n)
+	})
+}
+
+// Occurrences calls fn with every occurrence in the database.
+func (s *BoltSource) Occurrences(fn func(*alphapb.Occurrence) error) error {
+	return s.each("occurrences", func(k []byte, v []byte) error {
+		var o alphapb.Occurrence
+		if err := proto.Unmarshal(v, &o); err != nil {
+			return fmt.Errorf("occurrence %q: %v", k, err)
+		}
+		return fn(&o)
+	})
+}
+
+// each calls fn with every key and value of bucket, if it exists.
+func (s *BoltSource) each(bucket string, fn func(k, v []byte) error) error {
+	return s.DB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(fn)
+	})
+}
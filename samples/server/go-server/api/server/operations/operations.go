@@ -0,0 +1,176 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package operations serves the google.longrunning.Operations service on top of the operations of
+// a server.Storager.
+package operations
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/iam"
+	grafeas "github.com/grafeas/grafeas/go/v1beta1/api"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
+	server "github.com/grafeas/grafeas/server-go"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// pollInterval is how often WaitOperation reads the operation it waits for.
+	pollInterval = time.Second
+	// maxWait is the longest WaitOperation waits, and how long it waits without a timeout.
+	maxWait = time.Minute
+)
+
+// Server implements the google.longrunning.Operations service. Operations are named
+// projects/{project_id}/operations/{operation_id}, and ListOperations lists the operations of the
+// project named by the request, e.g. projects/{project_id} or projects/{project_id}/operations.
+// Every call is authorized by Auth in the project of the operations it reads or changes.
+type Server struct {
+	S    server.Storager
+	Auth grafeas.Auth
+}
+
+var _ lrpb.OperationsServer = (*Server)(nil)
+
+// ListOperations lists the operations of a project. The filter is passed to the storage.
+func (s *Server) ListOperations(ctx context.Context, req *lrpb.ListOperationsRequest) (*lrpb.ListOperationsResponse, error) {
+	pID, err := name.ParseProject(strings.TrimSuffix(req.Name, "/operations"))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(ctx, pID, grafeas.OperationsList); err != nil {
+		return nil, err
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 100
+	}
+	ops, next, err := s.S.ListOperations(pID, req.Filter, int(req.PageSize), req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	return &lrpb.ListOperationsResponse{Operations: ops, NextPageToken: next}, nil
+}
+
+// GetOperation gets the latest state of an operation.
+func (s *Server) GetOperation(ctx context.Context, req *lrpb.GetOperationRequest) (*lrpb.Operation, error) {
+	pID, opID, err := name.ParseOperation(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(ctx, pID, grafeas.OperationsGet); err != nil {
+		return nil, err
+	}
+	return s.S.GetOperation(pID, opID)
+}
+
+// DeleteOperation deletes an operation. Deleting an operation doesn't cancel it.
+func (s *Server) DeleteOperation(ctx context.Context, req *lrpb.DeleteOperationRequest) (*empty.Empty, error) {
+	pID, opID, err := name.ParseOperation(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(ctx, pID, grafeas.OperationsDelete); err != nil {
+		return nil, err
+	}
+	if err := s.S.DeleteOperation(pID, opID); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+// CancelOperation marks an operation that isn't done yet as done with a CANCELLED error. Whatever
+// runs the operation notices this when it next updates the operation and stops; canceling an
+// operation that is already done has no effect.
+func (s *Server) CancelOperation(ctx context.Context, req *lrpb.CancelOperationRequest) (*empty.Empty, error) {
+	pID, opID, err := name.ParseOperation(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(ctx, pID, grafeas.OperationsCancel); err != nil {
+		return nil, err
+	}
+	op, err := s.S.GetOperation(pID, opID)
+	if err != nil {
+		return nil, err
+	}
+	if !op.Done {
+		op = proto.Clone(op).(*lrpb.Operation)
+		op.Done = true
+		op.Result = &lrpb.Operation_Error{Error: &spb.Status{Code: int32(codes.Canceled), Message: "operation cancelled"}}
+		// The operation may have finished since it was read.
+		if err := s.S.UpdateOperation(pID, opID, op); err != nil && status.Code(err) != codes.FailedPrecondition {
+			return nil, err
+		}
+	}
+	return &empty.Empty{}, nil
+}
+
+// WaitOperation waits until an operation is done or the timeout of the request expires, and
+// returns its latest state. The timeout is capped at a minute, which is also the timeout of
+// requests without one.
+func (s *Server) WaitOperation(ctx context.Context, req *lrpb.WaitOperationRequest) (*lrpb.Operation, error) {
+	pID, opID, err := name.ParseOperation(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(ctx, pID, grafeas.OperationsGet); err != nil {
+		return nil, err
+	}
+	timeout := maxWait
+	if req.Timeout != nil {
+		t, err := ptypes.Duration(req.Timeout)
+		if err != nil || t < 0 {
+			return nil, errors.Newf(codes.InvalidArgument, "invalid timeout %v", req.Timeout)
+		}
+		if t < timeout {
+			timeout = t
+		}
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	for {
+		op, err := s.S.GetOperation(pID, opID)
+		if err != nil || op.Done {
+			return op, err
+		}
+		select {
+		case <-ctx.Done():
+			code := codes.Canceled
+			if ctx.Err() == context.DeadlineExceeded {
+				code = codes.DeadlineExceeded
+			}
+			return nil, errors.Newf(code, "stopped waiting for operation %q: %v", req.Name, ctx.Err())
+		case <-deadline.C:
+			return op, nil
+		case <-poll.C:
+		}
+	}
+}
+
+// check checks that the caller has the specified permission in the specified project.
+func (s *Server) check(ctx context.Context, pID string, p iam.Permission) error {
+	return s.Auth.CheckAccessAndProject(ctx, pID, "", p)
+}
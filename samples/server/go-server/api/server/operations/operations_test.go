@@ -0,0 +1,156 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grafeas/grafeas/go/errors"
+	"github.com/grafeas/grafeas/go/iam"
+	grafeas "github.com/grafeas/grafeas/go/v1beta1/api"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
+	"golang.org/x/net/context"
+	lrpb "google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer(t *testing.T) {
+	pollInterval = time.Millisecond
+	ctx := context.Background()
+	st := storage.NewMemStore()
+	s := &Server{S: st, Auth: &fakeAuth{}}
+	for _, n := range []string{"projects/p/operations/a", "projects/p/operations/b", "projects/q/operations/c"} {
+		if err := st.CreateOperation(&lrpb.Operation{Name: n}); err != nil {
+			t.Fatalf("CreateOperation(%q) got err %v, want success", n, err)
+		}
+	}
+
+	for _, parent := range []string{"projects/p", "projects/p/operations"} {
+		resp, err := s.ListOperations(ctx, &lrpb.ListOperationsRequest{Name: parent})
+		if err != nil {
+			t.Fatalf("ListOperations(%q) got err %v, want success", parent, err)
+		}
+		if len(resp.Operations) != 2 {
+			t.Errorf("ListOperations(%q) got %v, want 2 operations", parent, resp.Operations)
+		}
+	}
+	if _, err := s.ListOperations(ctx, &lrpb.ListOperationsRequest{Name: "operations"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListOperations without a project got err %v, want %v", err, codes.InvalidArgument)
+	}
+
+	if _, err := s.CancelOperation(ctx, &lrpb.CancelOperationRequest{Name: "projects/p/operations/a"}); err != nil {
+		t.Fatalf("CancelOperation got err %v, want success", err)
+	}
+	op, err := s.GetOperation(ctx, &lrpb.GetOperationRequest{Name: "projects/p/operations/a"})
+	if err != nil {
+		t.Fatalf("GetOperation got err %v, want success", err)
+	}
+	if !op.Done || op.GetError().GetCode() != int32(codes.Canceled) {
+		t.Errorf("GetOperation got %v, want a cancelled operation", op)
+	}
+
+	// Waiting returns once the operation is done, or its latest state after the timeout.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		st.UpdateOperation("p", "b", &lrpb.Operation{Name: "projects/p/operations/b", Done: true})
+	}()
+	op, err = s.WaitOperation(ctx, &lrpb.WaitOperationRequest{Name: "projects/p/operations/b"})
+	if err != nil || !op.Done {
+		t.Errorf("WaitOperation got %v, %v, want a done operation", op, err)
+	}
+	op, err = s.WaitOperation(ctx, &lrpb.WaitOperationRequest{Name: "projects/q/operations/c", Timeout: ptypes.DurationProto(10 * time.Millisecond)})
+	if err != nil || op.Done {
+		t.Errorf("WaitOperation with a timeout got %v, %v, want the pending operation", op, err)
+	}
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := s.WaitOperation(cctx, &lrpb.WaitOperationRequest{Name: "projects/q/operations/c"}); status.Code(err) != codes.Canceled {
+		t.Errorf("WaitOperation with a cancelled context got err %v, want %v", err, codes.Canceled)
+	}
+
+	if _, err := s.DeleteOperation(ctx, &lrpb.DeleteOperationRequest{Name: "projects/q/operations/c"}); err != nil {
+		t.Fatalf("DeleteOperation got err %v, want success", err)
+	}
+	if _, err := s.GetOperation(ctx, &lrpb.GetOperationRequest{Name: "projects/q/operations/c"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetOperation after delete got err %v, want %v", err, codes.NotFound)
+	}
+}
+
+func TestServerPermissions(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewMemStore()
+	const opName = "projects/p/operations/a"
+	if err := st.CreateOperation(&lrpb.Operation{Name: opName}); err != nil {
+		t.Fatalf("CreateOperation got err %v, want success", err)
+	}
+	tests := []struct {
+		perm iam.Permission
+		call func(s *Server) error
+	}{
+		{grafeas.OperationsList, func(s *Server) error {
+			_, err := s.ListOperations(ctx, &lrpb.ListOperationsRequest{Name: "projects/p"})
+			return err
+		}},
+		{grafeas.OperationsGet, func(s *Server) error {
+			_, err := s.GetOperation(ctx, &lrpb.GetOperationRequest{Name: opName})
+			return err
+		}},
+		{grafeas.OperationsGet, func(s *Server) error {
+			_, err := s.WaitOperation(ctx, &lrpb.WaitOperationRequest{Name: opName})
+			return err
+		}},
+		{grafeas.OperationsCancel, func(s *Server) error {
+			_, err := s.CancelOperation(ctx, &lrpb.CancelOperationRequest{Name: opName})
+			return err
+		}},
+		{grafeas.OperationsDelete, func(s *Server) error {
+			_, err := s.DeleteOperation(ctx, &lrpb.DeleteOperationRequest{Name: opName})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		s := &Server{S: st, Auth: &fakeAuth{denied: tt.perm}}
+		if err := tt.call(s); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("call without %q got err %v, want %v", tt.perm, err, codes.PermissionDenied)
+		}
+	}
+	op, err := st.GetOperation("p", "a")
+	if err != nil || op.Done {
+		t.Errorf("GetOperation after denied calls got %v, %v, want the pending operation", op, err)
+	}
+}
+
+// fakeAuth allows every call except those that need the denied permission.
+type fakeAuth struct {
+	denied iam.Permission
+}
+
+func (a *fakeAuth) CheckAccessAndProject(ctx context.Context, projectID string, entityID string, p iam.Permission) error {
+	if p == a.denied {
+		return errors.Newf(codes.PermissionDenied, "permission %q denied in project %q", p, projectID)
+	}
+	return nil
+}
+
+func (a *fakeAuth) EndUserID(ctx context.Context) (string, error) {
+	return "somebody@example.com", nil
+}
+
+func (a *fakeAuth) PurgePolicy(ctx context.Context, projectID string, entityID string, r iam.Resource) error {
+	return nil
+}
@@ -0,0 +1,421 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retention deletes old occurrences according to per-project, per-kind retention rules.
+//
+// A rule expires the occurrences it applies to once they haven't been updated for max_age, or once
+// keep_last more recently updated ones exist for the same resource, whichever comes first. The
+// sweeper applies the rules periodically and reports every occurrence it deleted, or would have
+// deleted in a dry run.
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grafeas/grafeas/go/etag"
+	cpb "github.com/grafeas/grafeas/proto/v1beta1/common_go_proto"
+	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
+	server "github.com/grafeas/grafeas/server-go"
+)
+
+// Reasons an occurrence expired for.
+const (
+	MaxAge   = "max_age"
+	KeepLast = "keep_last"
+)
+
+// defaultInterval is the time between sweeps if Config leaves it unset.
+const defaultInterval = 24 * time.Hour
+
+// pageSize is the number of projects or occurrences listed at once.
+const pageSize = 1000
+
+// Config is the retention configuration.
+type Config struct {
+	Rules      []*Rule       `yaml:"rules"`
+	Interval   time.Duration `yaml:"interval"`    // Time between sweeps
+	DryRun     bool          `yaml:"dry_run"`     // Only report the occurrences the rules expire
+	ReportPath string        `yaml:"report_path"` // File the reports are appended to, the server log if empty
+}
+
+// Rule is a retention rule. At least one of MaxAge and KeepLast has to be set.
+type Rule struct {
+	Projects []string      `yaml:"projects"`  // Project IDs the rule applies to, all if empty
+	Kinds    []string      `yaml:"kinds"`     // Note kinds the rule applies to, e.g. VULNERABILITY, all if empty
+	MaxAge   time.Duration `yaml:"max_age"`   // Age of the last update after which occurrences expire
+	KeepLast int           `yaml:"keep_last"` // Number of most recently updated occurrences kept per resource
+}
+
+// Report is the outcome of a sweep, written to the report log as a JSON line.
+type Report struct {
+	Time    time.Time  `json:"time"`
+	DryRun  bool       `json:"dry_run"`
+	Expired []*Expired `json:"expired"`
+	Errors  []string   `json:"errors,omitempty"` // Projects that couldn't be swept
+}
+
+// Expired is an occurrence a rule expired.
+type Expired struct {
+	Occurrence string    `json:"occurrence"`
+	Resource   string    `json:"resource"`
+	Kind       string    `json:"kind"`
+	UpdateTime time.Time `json:"update_time"`
+	Rule       int       `json:"rule"`            // Index of the rule in Config.Rules
+	Reason     string    `json:"reason"`          // MaxAge or KeepLast
+	Error      string    `json:"error,omitempty"` // Why the occurrence couldn't be deleted
+}
+
+type rule struct {
+	index    int
+	projects map[string]bool
+	kinds    map[cpb.NoteKind]bool
+	maxAge   time.Duration
+	keepLast int
+}
+
+// occurrence is what a sweep keeps of each listed occurrence.
+type occurrence struct {
+	pID, oID   string
+	name       string
+	resource   string
+	kind       cpb.NoteKind
+	updateTime time.Time
+	etag       string
+}
+
+// Deleter deletes expired occurrences, provided they still have the specified etag. The API does,
+// auditing the deletions and purging the IAM policies of the occurrences.
+type Deleter interface {
+	ExpireOccurrence(ctx context.Context, pID, oID, etag string) error
+}
+
+// Sweeper applies the retention rules to the occurrences in a storage.
+type Sweeper struct {
+	s        server.Storager
+	d        Deleter
+	rules    []*rule
+	interval time.Duration
+	dryRun   bool
+
+	reportMu sync.Mutex
+	reports  io.Writer
+	closeLog func() error
+
+	// mu guards started and closing stop.
+	mu      sync.Mutex
+	started bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewSweeper validates config and returns a sweeper that lists occurrences in s and deletes the
+// expired ones with d.
+func NewSweeper(s server.Storager, d Deleter, config *Config) (*Sweeper, error) {
+	w := &Sweeper{
+		s:        s,
+		d:        d,
+		interval: config.Interval,
+		dryRun:   config.DryRun,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if w.interval <= 0 {
+		w.interval = defaultInterval
+	}
+	for i, c := range config.Rules {
+		r, err := newRule(i, c)
+		if err != nil {
+			return nil, fmt.Errorf("retention rule %d: %v", i, err)
+		}
+		w.rules = append(w.rules, r)
+	}
+	if config.ReportPath != "" {
+		f, err := os.OpenFile(config.ReportPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open retention report log: %v", err)
+		}
+		w.reports, w.closeLog = f, f.Close
+	}
+	return w, nil
+}
+
+func newRule(index int, c *Rule) (*rule, error) {
+	if c.MaxAge < 0 || c.KeepLast < 0 {
+		return nil, fmt.Errorf("max_age and keep_last can't be negative")
+	}
+	if c.MaxAge == 0 && c.KeepLast == 0 {
+		return nil, fmt.Errorf("max_age or keep_last is required")
+	}
+	r := &rule{index: index, maxAge: c.MaxAge, keepLast: c.KeepLast}
+	if len(c.Projects) > 0 {
+		r.projects = map[string]bool{}
+		for _, p := range c.Projects {
+			r.projects[p] = true
+		}
+	}
+	if len(c.Kinds) > 0 {
+		r.kinds = map[cpb.NoteKind]bool{}
+		for _, k := range c.Kinds {
+			v, ok := cpb.NoteKind_value[k]
+			if !ok {
+				return nil, fmt.Errorf("unknown note kind %q", k)
+			}
+			r.kinds[cpb.NoteKind(v)] = true
+		}
+	}
+	return r, nil
+}
+
+// Start sweeps every interval, starting now, until Close is called. Starting a sweeper again, or
+// after it was closed, has no effect.
+func (w *Sweeper) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.started || w.closed() {
+		return
+	}
+	w.started = true
+	go func() {
+		defer close(w.done)
+		for {
+			w.Sweep(time.Now())
+			select {
+			case <-w.stop:
+				return
+			case <-time.After(w.interval):
+			}
+		}
+	}()
+}
+
+// Close stops the sweeps started by Start, waiting for a running one to finish.
+func (w *Sweeper) Close() error {
+	w.mu.Lock()
+	if w.closed() {
+		w.mu.Unlock()
+		return nil
+	}
+	close(w.stop)
+	started := w.started
+	w.mu.Unlock()
+	if started {
+		<-w.done
+	}
+	if w.closeLog != nil {
+		return w.closeLog()
+	}
+	return nil
+}
+
+// closed reports whether Close was called. The caller holds mu.
+func (w *Sweeper) closed() bool {
+	select {
+	case <-w.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sweep applies the rules once, as of now, and writes its report to the report log. Unless the
+// sweeper is a dry run, the expired occurrences are deleted, provided they haven't changed since
+// they were listed.
+func (w *Sweeper) Sweep(now time.Time) *Report {
+	report := &Report{Time: now.UTC(), DryRun: w.dryRun, Expired: []*Expired{}}
+	var pIDs []string
+	err := server.ListAll(func(token string) (string, error) {
+		page, npt, err := w.s.ListProjects("", pageSize, token)
+		for _, p := range page {
+			pID, err := name.ParseProject(p.Name)
+			if err != nil {
+				continue
+			}
+			pIDs = append(pIDs, pID)
+		}
+		return npt, err
+	})
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to list projects: %v", err))
+	}
+	for _, pID := range pIDs {
+		if err := w.sweepProject(pID, now, report); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to sweep project %q: %v", pID, err))
+		}
+	}
+	w.writeReport(report)
+	return report
+}
+
+// sweepProject adds the occurrences of project pID the rules expire to report, deleting them unless
+// the sweeper is a dry run.
+func (w *Sweeper) sweepProject(pID string, now time.Time, report *Report) error {
+	var rules []*rule
+	for _, r := range w.rules {
+		if r.projects == nil || r.projects[pID] {
+			rules = append(rules, r)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	// The occurrences are all listed before any is deleted, so that deleting doesn't shift pages.
+	var occs []*occurrence
+	err := server.ListAll(func(token string) (string, error) {
+		page, npt, err := w.s.ListOccurrences(pID, "", pageSize, token)
+		for _, o := range page {
+			if oPID, oID, err := name.ParseOccurrence(o.Name); err == nil && oPID == pID {
+				occs = append(occs, newOccurrence(oPID, oID, o))
+			}
+		}
+		return npt, err
+	})
+	if err != nil {
+		return err
+	}
+
+	expired := map[string]bool{}
+	for _, r := range rules {
+		for _, e := range r.expire(occs, now) {
+			if expired[e.Occurrence] {
+				continue
+			}
+			expired[e.Occurrence] = true
+			report.Expired = append(report.Expired, e.Expired)
+			if w.dryRun {
+				continue
+			}
+			if err := w.d.ExpireOccurrence(context.Background(), e.pID, e.oID, e.etag); err != nil {
+				e.Error = err.Error()
+			}
+		}
+	}
+	return nil
+}
+
+type expiredOccurrence struct {
+	*Expired
+	*occurrence
+}
+
+// expire returns the occurrences among occs r expires as of now.
+func (r *rule) expire(occs []*occurrence, now time.Time) []*expiredOccurrence {
+	byResource := map[string][]*occurrence{}
+	var resources []string
+	for _, o := range occs {
+		if r.kinds != nil && !r.kinds[o.kind] {
+			continue
+		}
+		if _, ok := byResource[o.resource]; !ok {
+			resources = append(resources, o.resource)
+		}
+		byResource[o.resource] = append(byResource[o.resource], o)
+	}
+	sort.Strings(resources)
+
+	var expired []*expiredOccurrence
+	for _, res := range resources {
+		occs := byResource[res]
+		sort.SliceStable(occs, func(i, j int) bool { return occs[i].updateTime.After(occs[j].updateTime) })
+		for i, o := range occs {
+			reason := ""
+			switch {
+			case r.keepLast > 0 && i >= r.keepLast:
+				reason = KeepLast
+			case r.maxAge > 0 && !o.updateTime.IsZero() && now.Sub(o.updateTime) > r.maxAge:
+				reason = MaxAge
+			default:
+				continue
+			}
+			expired = append(expired, &expiredOccurrence{
+				Expired: &Expired{
+					Occurrence: o.name,
+					Resource:   o.resource,
+					Kind:       o.kind.String(),
+					UpdateTime: o.updateTime,
+					Rule:       r.index,
+					Reason:     reason,
+				},
+				occurrence: o,
+			})
+		}
+	}
+	return expired
+}
+
+// newOccurrence keeps what a sweep needs of o. Occurrences that were never updated were last
+// updated when they were created; those with neither time never expire by age.
+func newOccurrence(pID, oID string, o *pb.Occurrence) *occurrence {
+	occ := &occurrence{
+		pID:      pID,
+		oID:      oID,
+		name:     o.Name,
+		resource: o.GetResource().GetUri(),
+		kind:     o.Kind,
+	}
+	ts := o.UpdateTime
+	if ts == nil {
+		ts = o.CreateTime
+	}
+	if ts != nil {
+		if t, err := ptypes.Timestamp(ts); err == nil {
+			occ.updateTime = t
+		}
+	}
+	if tag, err := etag.Compute(o); err == nil {
+		occ.etag = tag
+	}
+	return occ
+}
+
+// writeReport appends report to the report log, or logs each expired occurrence if there is none.
+func (w *Sweeper) writeReport(report *Report) {
+	w.reportMu.Lock()
+	defer w.reportMu.Unlock()
+	if w.reports == nil {
+		verb := "deleted"
+		if report.DryRun {
+			verb = "would delete"
+		}
+		for _, e := range report.Expired {
+			if e.Error != "" {
+				log.Printf("retention: failed to delete %s (rule %d, %s): %s", e.Occurrence, e.Rule, e.Reason, e.Error)
+				continue
+			}
+			log.Printf("retention: %s %s (rule %d, %s)", verb, e.Occurrence, e.Rule, e.Reason)
+		}
+		for _, err := range report.Errors {
+			log.Printf("retention: %s", err)
+		}
+		return
+	}
+	b, err := json.Marshal(report)
+	if err != nil {
+		log.Printf("failed to marshal retention report: %v", err)
+		return
+	}
+	if _, err := w.reports.Write(append(b, '\n')); err != nil {
+		log.Printf("failed to write retention report: %v", err)
+	}
+}
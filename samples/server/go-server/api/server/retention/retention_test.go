@@ -0,0 +1,295 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	cpb "github.com/grafeas/grafeas/proto/v1beta1/common_go_proto"
+	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/testing"
+	server "github.com/grafeas/grafeas/server-go"
+)
+
+var now = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// newStorage returns a storage with occurrences in projects p1 and p2, each named after its
+// resource, kind and the days since it was last updated.
+func newStorage(t *testing.T) server.Storager {
+	t.Helper()
+	s := storage.NewMemStore()
+	for _, pID := range []string{"p1", "p2"} {
+		if err := s.CreateProject(pID); err != nil {
+			t.Fatalf("CreateProject got error %v, want success", err)
+		}
+	}
+	for _, o := range []struct {
+		pID, resource string
+		kind          cpb.NoteKind
+		age           int
+	}{
+		{"p1", "a", cpb.NoteKind_VULNERABILITY, 100},
+		{"p1", "a", cpb.NoteKind_VULNERABILITY, 10},
+		{"p1", "a", cpb.NoteKind_BUILD, 100},
+		{"p1", "a", cpb.NoteKind_BUILD, 50},
+		{"p1", "a", cpb.NoteKind_BUILD, 1},
+		{"p1", "b", cpb.NoteKind_BUILD, 100},
+		{"p2", "a", cpb.NoteKind_VULNERABILITY, 100},
+	} {
+		occ := testutil.Occurrence(o.pID, "projects/p1/notes/n")
+		occ.Name = fmt.Sprintf("projects/%s/occurrences/%s-%s-%d", o.pID, o.resource, o.kind, o.age)
+		occ.Resource = &pb.Resource{Uri: o.resource}
+		occ.Kind = o.kind
+		occ.UpdateTime, _ = ptypes.TimestampProto(now.Add(-time.Duration(o.age) * 24 * time.Hour))
+		if err := s.CreateOccurrence(occ); err != nil {
+			t.Fatalf("CreateOccurrence got error %v, want success", err)
+		}
+	}
+	return s
+}
+
+// occurrences returns the sorted names of the occurrences left in s.
+func occurrences(t *testing.T, s server.Storager) []string {
+	t.Helper()
+	var names []string
+	for _, pID := range []string{"p1", "p2"} {
+		occs, _, err := s.ListOccurrences(pID, "", 100, "")
+		if err != nil {
+			t.Fatalf("ListOccurrences got error %v, want success", err)
+		}
+		for _, o := range occs {
+			names = append(names, o.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expired returns the sorted names and reasons of the occurrences in r.
+func expired(r *Report) []string {
+	var names []string
+	for _, e := range r.Expired {
+		names = append(names, e.Occurrence+" "+e.Reason)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSweep(t *testing.T) {
+	s := newStorage(t)
+	d := &storageDeleter{s: s}
+	w, err := NewSweeper(s, d, &Config{Rules: []*Rule{
+		{Projects: []string{"p1"}, Kinds: []string{"VULNERABILITY"}, MaxAge: 90 * 24 * time.Hour},
+		{Kinds: []string{"BUILD"}, KeepLast: 2},
+		{Kinds: []string{"BUILD"}, MaxAge: 30 * 24 * time.Hour},
+	}})
+	if err != nil {
+		t.Fatalf("NewSweeper got error %v, want success", err)
+	}
+	defer w.Close()
+
+	r := w.Sweep(now)
+	want := []string{
+		"projects/p1/occurrences/a-BUILD-100 keep_last",
+		"projects/p1/occurrences/a-BUILD-50 max_age",
+		"projects/p1/occurrences/a-VULNERABILITY-100 max_age",
+		"projects/p1/occurrences/b-BUILD-100 max_age",
+	}
+	if got := expired(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sweep expired %v, want %v", got, want)
+	}
+	if r.DryRun || len(r.Errors) != 0 {
+		t.Errorf("Sweep got report %+v, want a sweep without errors", r)
+	}
+	for _, e := range r.Expired {
+		if e.Error != "" {
+			t.Errorf("Sweep failed to delete %s: %s", e.Occurrence, e.Error)
+		}
+	}
+	left := []string{
+		"projects/p1/occurrences/a-BUILD-1",
+		"projects/p1/occurrences/a-VULNERABILITY-10",
+		"projects/p2/occurrences/a-VULNERABILITY-100",
+	}
+	if got := occurrences(t, s); !reflect.DeepEqual(got, left) {
+		t.Errorf("Sweep left occurrences %v, want %v", got, left)
+	}
+	if len(d.expired) != len(want) {
+		t.Errorf("Sweep deleted %v through the deleter, want %d occurrences", d.expired, len(want))
+	}
+
+	// Nothing is left to expire.
+	if r := w.Sweep(now); len(r.Expired) != 0 {
+		t.Errorf("second Sweep expired %v, want none", expired(r))
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	s := newStorage(t)
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("retention-%d.log", time.Now().UnixNano()))
+	defer os.Remove(path)
+	d := &storageDeleter{s: s}
+	w, err := NewSweeper(s, d, &Config{
+		Rules:      []*Rule{{MaxAge: 90 * 24 * time.Hour}},
+		DryRun:     true,
+		ReportPath: path,
+	})
+	if err != nil {
+		t.Fatalf("NewSweeper got error %v, want success", err)
+	}
+	before := occurrences(t, s)
+	r := w.Sweep(now)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close got error %v, want success", err)
+	}
+
+	want := []string{
+		"projects/p1/occurrences/a-BUILD-100 max_age",
+		"projects/p1/occurrences/a-VULNERABILITY-100 max_age",
+		"projects/p1/occurrences/b-BUILD-100 max_age",
+		"projects/p2/occurrences/a-VULNERABILITY-100 max_age",
+	}
+	if got := expired(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sweep expired %v, want %v", got, want)
+	}
+	if got := occurrences(t, s); !reflect.DeepEqual(got, before) || len(d.expired) != 0 {
+		t.Errorf("dry run Sweep left occurrences %v and deleted %v, want all of %v left", got, d.expired, before)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the report log: %v", err)
+	}
+	var logged Report
+	if err := json.Unmarshal(b, &logged); err != nil {
+		t.Fatalf("failed to decode the report log %q: %v", b, err)
+	}
+	if !logged.DryRun || !reflect.DeepEqual(expired(&logged), want) {
+		t.Errorf("report log got %+v, want a dry run expiring %v", logged, want)
+	}
+}
+
+func TestChangedOccurrencesAreKept(t *testing.T) {
+	s := newStorage(t)
+	w, err := NewSweeper(&updatingStorager{Storager: s}, &storageDeleter{s: s}, &Config{
+		Rules: []*Rule{{Kinds: []string{"VULNERABILITY"}, Projects: []string{"p2"}, MaxAge: time.Hour}},
+	})
+	if err != nil {
+		t.Fatalf("NewSweeper got error %v, want success", err)
+	}
+	defer w.Close()
+
+	r := w.Sweep(now)
+	if len(r.Expired) != 1 || r.Expired[0].Error == "" {
+		t.Fatalf("Sweep got report %+v, want the occurrence updated since it was listed kept", r)
+	}
+	if got := occurrences(t, s); len(got) != 7 {
+		t.Errorf("Sweep left occurrences %v, want all of them", got)
+	}
+}
+
+// updatingStorager updates every occurrence right after it was listed.
+type updatingStorager struct {
+	server.Storager
+}
+
+func (s *updatingStorager) ListOccurrences(pID, filters string, pageSize int, pageToken string) ([]*pb.Occurrence, string, error) {
+	occs, npt, err := s.Storager.ListOccurrences(pID, filters, pageSize, pageToken)
+	var listed []*pb.Occurrence
+	for _, o := range occs {
+		o = proto.Clone(o).(*pb.Occurrence)
+		listed = append(listed, o)
+		update := proto.Clone(o).(*pb.Occurrence)
+		update.Remediation = "updated"
+		_, oID, _ := name.ParseOccurrence(o.Name)
+		if err := s.Storager.UpdateOccurrence(pID, oID, update, ""); err != nil {
+			return nil, "", err
+		}
+	}
+	return listed, npt, err
+}
+
+func TestInvalidRules(t *testing.T) {
+	for _, r := range []*Rule{
+		{},
+		{MaxAge: -time.Hour},
+		{KeepLast: -1},
+		{Kinds: []string{"NOT_A_KIND"}, KeepLast: 1},
+	} {
+		if _, err := NewSweeper(storage.NewMemStore(), nil, &Config{Rules: []*Rule{r}}); err == nil {
+			t.Errorf("NewSweeper(%+v) got success, want error", r)
+		}
+	}
+}
+
+func TestStartAndClose(t *testing.T) {
+	s := newStorage(t)
+	config := &Config{Rules: []*Rule{{MaxAge: time.Hour}}, DryRun: true}
+
+	// A sweeper that was never started closes right away.
+	w, err := NewSweeper(s, &storageDeleter{s: s}, config)
+	if err != nil {
+		t.Fatalf("NewSweeper got error %v, want success", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Close got error %v, want success", err)
+	}
+	// Starting it afterwards has no effect.
+	w.Start()
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close got error %v, want success", err)
+	}
+
+	// Starting and closing concurrently waits for the sweep that was started.
+	w, err = NewSweeper(s, &storageDeleter{s: s}, config)
+	if err != nil {
+		t.Fatalf("NewSweeper got error %v, want success", err)
+	}
+	go w.Start()
+	if err := w.Close(); err != nil {
+		t.Errorf("Close got error %v, want success", err)
+	}
+}
+
+// storageDeleter deletes expired occurrences from storage, recording their names.
+type storageDeleter struct {
+	s       server.Storager
+	mu      sync.Mutex
+	expired []string
+}
+
+func (d *storageDeleter) ExpireOccurrence(ctx context.Context, pID, oID, etag string) error {
+	if err := d.s.DeleteOccurrence(pID, oID, etag); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expired = append(d.expired, name.OccurrenceName(pID, oID))
+	return nil
+}
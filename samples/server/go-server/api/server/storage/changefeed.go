@@ -0,0 +1,114 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxChanges is the number of most recent occurrence changes each store retains, so that watches
+// can be resumed from a cursor.
+const maxChanges = 10000
+
+// changeNotifier wakes up the watchers of a store when occurrence changes are recorded.
+type changeNotifier struct {
+	mu       sync.Mutex
+	watchers map[chan struct{}]bool
+}
+
+// subscribe returns a channel that receives a value whenever notify is called, and a function to
+// stop receiving. Notifications are coalesced, so a watcher only knows that something changed.
+func (n *changeNotifier) subscribe() (<-chan struct{}, func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.watchers == nil {
+		n.watchers = map[chan struct{}]bool{}
+	}
+	c := make(chan struct{}, 1)
+	n.watchers[c] = true
+	return c, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.watchers, c)
+	}
+}
+
+// notify wakes up all watchers.
+func (n *changeNotifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for c := range n.watchers {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// watchChanges implements WatchOccurrences on top of a store's change log, where every change has
+// a sequence number. latest returns the sequence number of the newest change. changesAfter returns
+// the changes to the watched project with greater sequence numbers than seq in order, along with
+// the sequence number of the newest change in the same snapshot of the log. wake fires whenever
+// new changes may have been recorded. Since the cursor moves to the newest change, no change to the
+// watched project may become visible after a change with a greater sequence number.
+func watchChanges(ctx context.Context, cursor string, wake <-chan struct{}, latest func() (int64, error),
+	changesAfter func(seq int64) ([]*watchpb.OccurrenceEvent, int64, error), fn func(*watchpb.OccurrenceEvent) error) error {
+	var (
+		seq int64
+		err error
+	)
+	if cursor == "" {
+		if seq, err = latest(); err != nil {
+			return err
+		}
+	} else if seq, err = strconv.ParseInt(cursor, 10, 64); err != nil || seq < 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid cursor %q", cursor)
+	}
+	for {
+		events, newest, err := changesAfter(seq)
+		if err != nil {
+			return err
+		}
+		// Changes up to newest-maxChanges may already have been dropped, and cursors from the future
+		// come from a different change log, e.g. one that was reset.
+		if seq < newest-maxChanges || seq > newest {
+			return status.Errorf(codes.OutOfRange, "cursor %q has expired, list occurrences and watch again from now", cursor)
+		}
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		// Changes to other projects are skipped over too, so that a quiet project's cursor doesn't
+		// appear expired because of other projects' changes.
+		seq, cursor = newest, formatCursor(newest)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		}
+	}
+}
+
+// formatCursor returns the cursor of the change with sequence number seq.
+func formatCursor(seq int64) string {
+	return strconv.FormatInt(seq, 10)
+}
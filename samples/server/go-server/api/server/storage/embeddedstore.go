@@ -15,16 +15,25 @@
 package storage
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
 	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
 	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
 	"github.com/grafeas/grafeas/server-go"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
 	opspb "google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -38,11 +47,32 @@ const (
 	bucketProjects    = "projects"
 	bucketNotes       = "notes"
 	bucketOperations  = "operations"
+	bucketPolicies    = "policies"
+	// bucketOccurrencesByResourceNote indexes occurrences by resourceNoteKey. Its keys are the
+	// resourceNoteKey of an occurrence followed by a NUL byte and the occurrence name.
+	bucketOccurrencesByResourceNote = "occurrencesByResourceNote"
+	// bucketOccurrenceChanges is the change feed of occurrences. Its keys are big endian sequence
+	// numbers and its values encoded watchpb.OccurrenceEvents.
+	bucketOccurrenceChanges = "occurrenceChanges"
+	// bucketAuditEvents holds a bucket per project with its audit log. Their keys are big endian
+	// sequence numbers and their values encoded auditpb.AuditEvents.
+	bucketAuditEvents = "auditEvents"
+	// bucketNoteRevisions and bucketOccurrenceRevisions hold a bucket per note or occurrence name
+	// with its previous revisions. Their keys are big endian sequence numbers and their values
+	// encoded revpb.NoteRevisions or revpb.OccurrenceRevisions.
+	bucketNoteRevisions       = "noteRevisions"
+	bucketOccurrenceRevisions = "occurrenceRevisions"
+	// bucketDeletedProjects and bucketDeletedNotes hold the deleted projects and notes under the same
+	// keys as before they were deleted until they are undeleted or purged. Their values are the big
+	// endian Unix time in nanoseconds the entity was deleted at followed by the encoded entity.
+	bucketDeletedProjects = "deletedProjects"
+	bucketDeletedNotes    = "deletedNotes"
 )
 
 var (
 	errKeyExists = fmt.Errorf("key exists")
 	errNoKey     = fmt.Errorf("key missing")
+	errDeleted   = fmt.Errorf("key deleted")
 )
 
 type EmbeddedStoreConfig struct {
@@ -51,7 +81,8 @@ type EmbeddedStoreConfig struct {
 
 // embeddedStore is a storage solution for Grafeas based on boltdb
 type embeddedStore struct {
-	db *bolt.DB
+	db       *bolt.DB
+	notifier changeNotifier
 }
 
 // NewEmbeddedStore creates a embeddedS store with initialized filesystem
@@ -76,6 +107,40 @@ func NewEmbeddedStore(config *EmbeddedStoreConfig) server.Storager {
 		if _, err := tx.CreateBucketIfNotExists([]byte(bucketOperations)); err != nil {
 			return err
 		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(bucketOccurrenceChanges)); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(bucketPolicies)); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(bucketAuditEvents)); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(bucketNoteRevisions)); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(bucketOccurrenceRevisions)); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(bucketDeletedProjects)); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(bucketDeletedNotes)); err != nil {
+			return err
+		}
+		if tx.Bucket([]byte(bucketOccurrencesByResourceNote)) == nil {
+			// Databases created before the index existed need it built from their occurrences.
+			if _, err := tx.CreateBucket([]byte(bucketOccurrencesByResourceNote)); err != nil {
+				return err
+			}
+			return tx.Bucket([]byte(bucketOccurrences)).ForEach(func(k, v []byte) error {
+				var o pb.Occurrence
+				if err := proto.Unmarshal(v, &o); err != nil {
+					return err
+				}
+				return reindexOccurrence(tx, nil, &o)
+			})
+		}
 		return nil
 	}); err != nil {
 		log.Fatal(err)
@@ -85,22 +150,41 @@ func NewEmbeddedStore(config *EmbeddedStoreConfig) server.Storager {
 
 // CreateProject adds the specified project to the embedded store
 func (m *embeddedStore) CreateProject(pID string) error {
-	err := m.update(bucketProjects, pID, true, &prpb.Project{Name: name.FormatProject(pID)})
+	err := m.create(bucketProjects, bucketDeletedProjects, pID, &prpb.Project{Name: name.FormatProject(pID)})
 	if err == errKeyExists {
 		return status.Errorf(codes.AlreadyExists, "Project with name %q already exists", pID)
 	}
+	if err == errDeleted {
+		return status.Errorf(codes.AlreadyExists, "Project with name %q is deleted but not purged yet", pID)
+	}
 	return err
 }
 
 // DeleteProject deletes the project with the given pID from the embedded store
 func (m *embeddedStore) DeleteProject(pID string) error {
-	err := m.delete(bucketProjects, pID)
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		value, err := remove(tx, bucketProjects, pID, nil, "")
+		if err != nil {
+			return err
+		}
+		return tombstone(tx, bucketDeletedProjects, pID, value)
+	})
 	if err == errNoKey {
 		return status.Errorf(codes.NotFound, "Project with name %q does not Exist", pID)
 	}
 	return err
 }
 
+// UndeleteProject restores the project with the given pID if it was deleted at or after
+// deletedAfter
+func (m *embeddedStore) UndeleteProject(pID string, deletedAfter time.Time) error {
+	err := m.undelete(bucketProjects, bucketDeletedProjects, pID, deletedAfter)
+	if err == errNoKey {
+		return status.Errorf(codes.NotFound, "Deleted project with name %q does not Exist", pID)
+	}
+	return err
+}
+
 // GetProject returns the project with the given pID from the embedded store
 func (m *embeddedStore) GetProject(pID string) (*prpb.Project, error) {
 	var project prpb.Project
@@ -137,7 +221,7 @@ func (m *embeddedStore) ListProjects(filter string, pageSize int, pageToken stri
 
 // CreateOccurrence adds the specified occurrence to the embedded store
 func (m *embeddedStore) CreateOccurrence(o *pb.Occurrence) error {
-	err := m.update(bucketOccurrences, o.Name, true, o)
+	err := m.updateOccurrence(o.Name, true, o, "")
 	if err == errKeyExists {
 		return status.Errorf(codes.AlreadyExists, "Occurrence with name %q already exists", o.Name)
 	}
@@ -145,10 +229,70 @@ func (m *embeddedStore) CreateOccurrence(o *pb.Occurrence) error {
 
 }
 
+// UpsertOccurrence updates the occurrence with the same project, resource URI and note name as o in
+// place, or adds o to the embedded store if there is none
+func (m *embeddedStore) UpsertOccurrence(o *pb.Occurrence) (*pb.Occurrence, error) {
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		prefix := []byte(resourceNoteKey(o) + "\x00")
+		// Should there be duplicates from before upserting was used, always update the same one.
+		k, _ := tx.Bucket([]byte(bucketOccurrencesByResourceNote)).Cursor().Seek(prefix)
+		if k == nil || !bytes.HasPrefix(k, prefix) {
+			old, err := put(tx, bucketOccurrences, o.Name, true, o, "")
+			if err != nil {
+				return err
+			}
+			if err := reindexOccurrence(tx, old, o); err != nil {
+				return err
+			}
+			return recordChange(tx, watchpb.OccurrenceEvent_CREATED, o)
+		}
+		oName := string(k[len(prefix):])
+		var existing pb.Occurrence
+		if err := proto.Unmarshal(tx.Bucket([]byte(bucketOccurrences)).Get([]byte(oName)), &existing); err != nil {
+			return err
+		}
+		o.Name = existing.Name
+		o.CreateTime = existing.CreateTime
+		o.UpdateTime = ptypes.TimestampNow()
+		if _, err := put(tx, bucketOccurrences, oName, false, o, ""); err != nil {
+			return err
+		}
+		if err := addRevision(tx, bucketOccurrenceRevisions, oName, &revpb.OccurrenceRevision{Occurrence: &existing, ReplaceTime: o.UpdateTime}); err != nil {
+			return err
+		}
+		return recordChange(tx, watchpb.OccurrenceEvent_UPDATED, o)
+	})
+	m.notifier.notify()
+	if err == errKeyExists {
+		return nil, status.Errorf(codes.AlreadyExists, "Occurrence with name %q already exists", o.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
 // DeleteOccurrence deletes the occurrence with the given pID and oID from the embedded store
-func (m *embeddedStore) DeleteOccurrence(pID, oID string) error {
+func (m *embeddedStore) DeleteOccurrence(pID, oID, etag string) error {
 	oName := name.OccurrenceName(pID, oID)
-	err := m.delete(bucketOccurrences, oName)
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		old, err := remove(tx, bucketOccurrences, oName, &pb.Occurrence{}, etag)
+		if err != nil {
+			return err
+		}
+		if err := reindexOccurrence(tx, old, nil); err != nil {
+			return err
+		}
+		if err := deleteRevisions(tx, bucketOccurrenceRevisions, oName); err != nil {
+			return err
+		}
+		var existing pb.Occurrence
+		if err := proto.Unmarshal(old, &existing); err != nil {
+			return err
+		}
+		return recordChange(tx, watchpb.OccurrenceEvent_DELETED, &existing)
+	})
+	m.notifier.notify()
 	if err == errNoKey {
 		return status.Errorf(codes.NotFound, "Occurrence with oName %q does not Exist", oName)
 	}
@@ -156,9 +300,9 @@ func (m *embeddedStore) DeleteOccurrence(pID, oID string) error {
 }
 
 // UpdateOccurrence updates the existing occurrence with the given projectID and occurrenceID
-func (m *embeddedStore) UpdateOccurrence(pID, oID string, o *pb.Occurrence) error {
+func (m *embeddedStore) UpdateOccurrence(pID, oID string, o *pb.Occurrence, etag string) error {
 	oName := name.OccurrenceName(pID, oID)
-	err := m.update(bucketOccurrences, oName, false, o)
+	err := m.updateOccurrence(oName, false, o, etag)
 	if err == errNoKey {
 		return status.Errorf(codes.NotFound, "Occurrence with name %q does not Exist", oName)
 	}
@@ -204,27 +348,87 @@ func (m *embeddedStore) ListOccurrences(pID, filters string, pageSize int, pageT
 
 // CreateNote adds the specified note to the embedded store
 func (m *embeddedStore) CreateNote(n *pb.Note) error {
-	err := m.update(bucketNotes, n.Name, true, n)
+	err := m.create(bucketNotes, bucketDeletedNotes, n.Name, n)
 	if err == errKeyExists {
 		return status.Errorf(codes.AlreadyExists, "Note with name %q already exists", n.Name)
 	}
+	if err == errDeleted {
+		return status.Errorf(codes.AlreadyExists, "Note with name %q is deleted but not purged yet", n.Name)
+	}
 	return err
 }
 
 // DeleteNote deletes the note with the given pID and nID from the embedded store
-func (m *embeddedStore) DeleteNote(pID, nID string) error {
+func (m *embeddedStore) DeleteNote(pID, nID, etag string) error {
 	nName := name.NoteName(pID, nID)
-	err := m.delete(bucketNotes, nName)
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		value, err := remove(tx, bucketNotes, nName, &pb.Note{}, etag)
+		if err != nil {
+			return err
+		}
+		return tombstone(tx, bucketDeletedNotes, nName, value)
+	})
 	if err == errNoKey {
 		return status.Errorf(codes.NotFound, "Note with name %q does not Exist", nName)
 	}
 	return err
 }
 
+// UndeleteNote restores the note with the given pID and nID if it was deleted at or after
+// deletedAfter
+func (m *embeddedStore) UndeleteNote(pID, nID string, deletedAfter time.Time) error {
+	nName := name.NoteName(pID, nID)
+	err := m.undelete(bucketNotes, bucketDeletedNotes, nName, deletedAfter)
+	if err == errNoKey {
+		return status.Errorf(codes.NotFound, "Deleted note with name %q does not Exist", nName)
+	}
+	return err
+}
+
+// PurgeDeleted permanently removes the projects and notes deleted before the given time
+func (m *embeddedStore) PurgeDeleted(before time.Time) ([]string, error) {
+	var purged []string
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		pIDs, err := purge(tx, bucketDeletedProjects, before)
+		if err != nil {
+			return err
+		}
+		for _, pID := range pIDs {
+			purged = append(purged, name.FormatProject(pID))
+		}
+		nNames, err := purge(tx, bucketDeletedNotes, before)
+		if err != nil {
+			return err
+		}
+		for _, nName := range nNames {
+			if err := deleteRevisions(tx, bucketNoteRevisions, nName); err != nil {
+				return err
+			}
+			purged = append(purged, nName)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to purge deleted projects and notes")
+	}
+	sort.Strings(purged)
+	return purged, nil
+}
+
 // UpdateNote updates the existing note with the given pID and nID
-func (m *embeddedStore) UpdateNote(pID, nID string, n *pb.Note) error {
+func (m *embeddedStore) UpdateNote(pID, nID string, n *pb.Note, etag string) error {
 	nName := name.NoteName(pID, nID)
-	err := m.update(bucketNotes, nName, false, n)
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		old, err := put(tx, bucketNotes, nName, false, n, etag)
+		if err != nil {
+			return err
+		}
+		var prev pb.Note
+		if err := proto.Unmarshal(old, &prev); err != nil {
+			return err
+		}
+		return addRevision(tx, bucketNoteRevisions, nName, &revpb.NoteRevision{Note: &prev, ReplaceTime: ptypes.TimestampNow()})
+	})
 	if err == errNoKey {
 		return status.Errorf(codes.NotFound, "Note with name %q does not Exist", nName)
 	}
@@ -322,7 +526,7 @@ func (m *embeddedStore) GetOperation(pID, opID string) (*opspb.Operation, error)
 
 // CreateOperation adds the specified operation to the embedded store
 func (m *embeddedStore) CreateOperation(o *opspb.Operation) error {
-	err := m.update(bucketOperations, o.Name, true, o)
+	err := m.update(bucketOperations, o.Name, true, o, "")
 	if err == errKeyExists {
 		return status.Errorf(codes.AlreadyExists, "Operation with name %q already exists", o.Name)
 	}
@@ -332,17 +536,31 @@ func (m *embeddedStore) CreateOperation(o *opspb.Operation) error {
 // DeleteOperation deletes the operation with the given pID and oID from the embeddedStore
 func (m *embeddedStore) DeleteOperation(pID, opID string) error {
 	opName := name.OperationName(pID, opID)
-	err := m.delete(bucketOperations, opName)
+	err := m.delete(bucketOperations, opName, nil, "")
 	if err == errNoKey {
 		return status.Errorf(codes.NotFound, "Operation with name %q does not Exist", opName)
 	}
 	return err
 }
 
-// UpdateOperation updates the existing operation with the given pID and nID
+// UpdateOperation updates the existing operation with the given pID and nID, unless it is done
 func (m *embeddedStore) UpdateOperation(pID, opID string, op *opspb.Operation) error {
 	opName := name.OperationName(pID, opID)
-	err := m.update(bucketOperations, opName, false, op)
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		value := tx.Bucket([]byte(bucketOperations)).Get([]byte(opName))
+		if value == nil {
+			return errNoKey
+		}
+		var old opspb.Operation
+		if err := proto.Unmarshal(value, &old); err != nil {
+			return err
+		}
+		if old.Done {
+			return errOperationDone(opName)
+		}
+		_, err := put(tx, bucketOperations, opName, false, op, "")
+		return err
+	})
 	if err == errNoKey {
 		return status.Errorf(codes.NotFound, "Operation with name %q does not Exist", opName)
 	}
@@ -375,23 +593,283 @@ func (m *embeddedStore) ListOperations(pID, filters string, pageSize int, pageTo
 	return os[startPos:endPos], nextPageToken(endPos, len(os)), nil
 }
 
-func (m *embeddedStore) update(bucket string, key string, new bool, pb proto.Message) error {
+// GetIamPolicy returns the IAM policy of the project or note with the given resource name
+func (m *embeddedStore) GetIamPolicy(resource string) (*iampb.Policy, error) {
+	var p iampb.Policy
+	err := m.get(bucketPolicies, resource, &p)
+	if err == errNoKey {
+		return nil, status.Errorf(codes.NotFound, "Policy of %q does not Exist", resource)
+	}
+	return &p, err
+}
+
+// SetIamPolicy replaces the IAM policy of the project or note with the given resource name
+func (m *embeddedStore) SetIamPolicy(resource string, p *iampb.Policy, etag string) error {
 	return m.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(bucket))
-		value := b.Get([]byte(key))
-		if new && value != nil {
-			return errKeyExists
-		} else if !new && value == nil {
+		if tx.Bucket([]byte(bucketPolicies)).Get([]byte(resource)) == nil {
+			// Resources without a policy have an empty one.
+			if err := checkETag(&iampb.Policy{}, etag); err != nil {
+				return err
+			}
+			_, err := put(tx, bucketPolicies, resource, true, p, "")
+			return err
+		}
+		_, err := put(tx, bucketPolicies, resource, false, p, etag)
+		return err
+	})
+}
+
+// DeleteIamPolicy deletes the IAM policy of the project or note with the given resource name
+func (m *embeddedStore) DeleteIamPolicy(resource string) error {
+	err := m.delete(bucketPolicies, resource, nil, "")
+	if err == errNoKey {
+		return status.Errorf(codes.NotFound, "Policy of %q does not Exist", resource)
+	}
+	return err
+}
+
+// AppendAuditEvent appends the specified event to the audit log of project pID
+func (m *embeddedStore) AppendAuditEvent(pID string, e *auditpb.AuditEvent) error {
+	return m.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket([]byte(bucketAuditEvents)).CreateBucketIfNotExists([]byte(pID))
+		if err != nil {
+			return err
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		buf, err := proto.Marshal(e)
+		if err != nil {
+			return err
+		}
+		return b.Put(changeKey(seq), buf)
+	})
+}
+
+// ListAuditEvents returns up to pageSize number of the events of the audit log of project pID
+// matching resource, start and end beginning at pageToken
+func (m *embeddedStore) ListAuditEvents(pID, resource string, start, end time.Time, pageSize int, pageToken string) ([]*auditpb.AuditEvent, string, error) {
+	events := []*auditpb.AuditEvent{}
+	err := m.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucketAuditEvents)).Bucket([]byte(pID))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var e auditpb.AuditEvent
+			if err := proto.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if auditEventMatches(&e, resource, start, end) {
+				events = append(events, &e)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, "", status.Error(codes.Internal, "Failed to list audit events")
+	}
+	startPos := min(parsePageToken(pageToken, 0), len(events))
+	endPos := min(startPos+pageSize, len(events))
+	return events[startPos:endPos], nextPageToken(endPos, len(events)), nil
+}
+
+// ListNoteRevisions returns up to pageSize number of the previous revisions of the note with pID
+// and nID, newest first, beginning at pageToken
+func (m *embeddedStore) ListNoteRevisions(pID, nID string, pageSize int, pageToken string) ([]*revpb.NoteRevision, string, error) {
+	revs := []*revpb.NoteRevision{}
+	err := m.listRevisions(bucketNoteRevisions, name.NoteName(pID, nID), func(v []byte) error {
+		var r revpb.NoteRevision
+		if err := proto.Unmarshal(v, &r); err != nil {
+			return err
+		}
+		revs = append(revs, &r)
+		return nil
+	})
+	if err != nil {
+		return nil, "", status.Error(codes.Internal, "Failed to list Note revisions")
+	}
+	startPos := min(parsePageToken(pageToken, 0), len(revs))
+	endPos := min(startPos+pageSize, len(revs))
+	return revs[startPos:endPos], nextPageToken(endPos, len(revs)), nil
+}
+
+// ListOccurrenceRevisions returns up to pageSize number of the previous revisions of the occurrence
+// with pID and oID, newest first, beginning at pageToken
+func (m *embeddedStore) ListOccurrenceRevisions(pID, oID string, pageSize int, pageToken string) ([]*revpb.OccurrenceRevision, string, error) {
+	revs := []*revpb.OccurrenceRevision{}
+	err := m.listRevisions(bucketOccurrenceRevisions, name.OccurrenceName(pID, oID), func(v []byte) error {
+		var r revpb.OccurrenceRevision
+		if err := proto.Unmarshal(v, &r); err != nil {
+			return err
+		}
+		revs = append(revs, &r)
+		return nil
+	})
+	if err != nil {
+		return nil, "", status.Error(codes.Internal, "Failed to list Occurrence revisions")
+	}
+	startPos := min(parsePageToken(pageToken, 0), len(revs))
+	endPos := min(startPos+pageSize, len(revs))
+	return revs[startPos:endPos], nextPageToken(endPos, len(revs)), nil
+}
+
+// listRevisions calls fn with the encoded revisions of the entity named key in the revisions bucket,
+// newest first.
+func (m *embeddedStore) listRevisions(bucket, key string, fn func(v []byte) error) error {
+	return m.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket)).Bucket([]byte(key))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// addRevision stores rev as the newest revision of the entity named key in the revisions bucket
+// within tx.
+func addRevision(tx *bolt.Tx, bucket, key string, rev proto.Message) error {
+	b, err := tx.Bucket([]byte(bucket)).CreateBucketIfNotExists([]byte(key))
+	if err != nil {
+		return err
+	}
+	seq, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+	buf, err := proto.Marshal(rev)
+	if err != nil {
+		return err
+	}
+	return b.Put(changeKey(seq), buf)
+}
+
+// deleteRevisions deletes the revisions of the entity named key in the revisions bucket within tx.
+func deleteRevisions(tx *bolt.Tx, bucket, key string) error {
+	if err := tx.Bucket([]byte(bucket)).DeleteBucket([]byte(key)); err != bolt.ErrBucketNotFound {
+		return err
+	}
+	return nil
+}
+
+// tombstone stores value, removed from under key, in the deleted bucket within tx along with the
+// current time.
+func tombstone(tx *bolt.Tx, deletedBucket, key string, value []byte) error {
+	buf := changeKey(uint64(time.Now().UnixNano()))
+	return tx.Bucket([]byte(deletedBucket)).Put([]byte(key), append(buf, value...))
+}
+
+// undelete moves key back from the deleted bucket to bucket if it was deleted at or after
+// deletedAfter.
+func (m *embeddedStore) undelete(bucket, deletedBucket, key string, deletedAfter time.Time) error {
+	return m.db.Update(func(tx *bolt.Tx) error {
+		d := tx.Bucket([]byte(deletedBucket))
+		value := d.Get([]byte(key))
+		if value == nil || int64(binary.BigEndian.Uint64(value[:8])) < deletedAfter.UnixNano() {
 			return errNoKey
 		}
-		buf, err := proto.Marshal(pb)
+		if err := tx.Bucket([]byte(bucket)).Put([]byte(key), value[8:]); err != nil {
+			return err
+		}
+		return d.Delete([]byte(key))
+	})
+}
+
+// purge deletes the keys deleted before the given time from the deleted bucket within tx and
+// returns them.
+func purge(tx *bolt.Tx, deletedBucket string, before time.Time) ([]string, error) {
+	d := tx.Bucket([]byte(deletedBucket))
+	var keys []string
+	if err := d.ForEach(func(k, v []byte) error {
+		if int64(binary.BigEndian.Uint64(v[:8])) < before.UnixNano() {
+			keys = append(keys, string(k))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		if err := d.Delete([]byte(k)); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// create stores pb under the new key unless it is deleted but not purged yet.
+func (m *embeddedStore) create(bucket, deletedBucket, key string, pb proto.Message) error {
+	return m.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(deletedBucket)).Get([]byte(key)) != nil {
+			return errDeleted
+		}
+		_, err := put(tx, bucket, key, true, pb, "")
+		return err
+	})
+}
+
+// update stores pb under key. If etag is non-empty, the value currently stored under key has to
+// match it.
+func (m *embeddedStore) update(bucket string, key string, new bool, pb proto.Message, etag string) error {
+	return m.db.Update(func(tx *bolt.Tx) error {
+		_, err := put(tx, bucket, key, new, pb, etag)
+		return err
+	})
+}
+
+// updateOccurrence is update for occurrences, keeping the resource and note index in sync.
+// It also records the change in the change feed and the previous revision of updated occurrences.
+func (m *embeddedStore) updateOccurrence(key string, new bool, o *pb.Occurrence, etag string) error {
+	defer m.notifier.notify()
+	return m.db.Update(func(tx *bolt.Tx) error {
+		old, err := put(tx, bucketOccurrences, key, new, o, etag)
 		if err != nil {
 			return err
 		}
-		return b.Put([]byte(key), buf)
+		if err := reindexOccurrence(tx, old, o); err != nil {
+			return err
+		}
+		if new {
+			return recordChange(tx, watchpb.OccurrenceEvent_CREATED, o)
+		}
+		var prev pb.Occurrence
+		if err := proto.Unmarshal(old, &prev); err != nil {
+			return err
+		}
+		if err := addRevision(tx, bucketOccurrenceRevisions, key, &revpb.OccurrenceRevision{Occurrence: &prev, ReplaceTime: ptypes.TimestampNow()}); err != nil {
+			return err
+		}
+		return recordChange(tx, watchpb.OccurrenceEvent_UPDATED, o)
 	})
 }
 
+// put stores pb under key within tx and returns the value previously stored there, if any.
+func put(tx *bolt.Tx, bucket string, key string, new bool, pb proto.Message, etag string) ([]byte, error) {
+	b := tx.Bucket([]byte(bucket))
+	value := b.Get([]byte(key))
+	if new && value != nil {
+		return nil, errKeyExists
+	} else if !new && value == nil {
+		return nil, errNoKey
+	}
+	if value != nil {
+		if err := matchETag(value, pb, etag); err != nil {
+			return nil, err
+		}
+	}
+	buf, err := proto.Marshal(pb)
+	if err != nil {
+		return nil, err
+	}
+	return value, b.Put([]byte(key), buf)
+}
+
 func (m *embeddedStore) get(bucket string, key string, pb proto.Message) error {
 	return m.db.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(bucket))
@@ -403,13 +881,133 @@ func (m *embeddedStore) get(bucket string, key string, pb proto.Message) error {
 	})
 }
 
-func (m *embeddedStore) delete(bucket string, key string) error {
+// delete removes key. If etag is non-empty, the value currently stored under key has to match it
+// when decoded as a message of the same type as like.
+func (m *embeddedStore) delete(bucket string, key string, like proto.Message, etag string) error {
 	return m.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(bucket))
-		value := b.Get([]byte(key))
-		if value == nil {
-			return errNoKey
-		}
-		return b.Delete([]byte(key))
+		_, err := remove(tx, bucket, key, like, etag)
+		return err
 	})
 }
+
+// remove deletes key within tx and returns the value that was stored there.
+func remove(tx *bolt.Tx, bucket string, key string, like proto.Message, etag string) ([]byte, error) {
+	b := tx.Bucket([]byte(bucket))
+	value := b.Get([]byte(key))
+	if value == nil {
+		return nil, errNoKey
+	}
+	if err := matchETag(value, like, etag); err != nil {
+		return nil, err
+	}
+	return value, b.Delete([]byte(key))
+}
+
+// WatchOccurrences calls fn with every change to the occurrences of project pID after cursor
+func (m *embeddedStore) WatchOccurrences(ctx context.Context, pID, cursor string, fn func(*watchpb.OccurrenceEvent) error) error {
+	wake, stop := m.notifier.subscribe()
+	defer stop()
+	latest := func() (int64, error) {
+		var seq uint64
+		err := m.db.View(func(tx *bolt.Tx) error {
+			seq = tx.Bucket([]byte(bucketOccurrenceChanges)).Sequence()
+			return nil
+		})
+		return int64(seq), err
+	}
+	prefix := name.FormatProject(pID) + "/"
+	changesAfter := func(seq int64) ([]*watchpb.OccurrenceEvent, int64, error) {
+		var (
+			events []*watchpb.OccurrenceEvent
+			newest uint64
+		)
+		err := m.db.View(func(tx *bolt.Tx) error {
+			b := tx.Bucket([]byte(bucketOccurrenceChanges))
+			newest = b.Sequence()
+			c := b.Cursor()
+			for k, v := c.Seek(changeKey(uint64(seq) + 1)); k != nil; k, v = c.Next() {
+				var e watchpb.OccurrenceEvent
+				if err := proto.Unmarshal(v, &e); err != nil {
+					return err
+				}
+				if strings.HasPrefix(e.Occurrence.GetName(), prefix) {
+					events = append(events, &e)
+				}
+			}
+			return nil
+		})
+		return events, int64(newest), err
+	}
+	return watchChanges(ctx, cursor, wake, latest, changesAfter, fn)
+}
+
+// recordChange appends a change to o to the change feed within tx, dropping the oldest change if
+// more than maxChanges are retained.
+func recordChange(tx *bolt.Tx, t watchpb.OccurrenceEvent_Type, o *pb.Occurrence) error {
+	b := tx.Bucket([]byte(bucketOccurrenceChanges))
+	seq, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+	buf, err := proto.Marshal(&watchpb.OccurrenceEvent{
+		Type:       t,
+		Occurrence: o,
+		EventTime:  ptypes.TimestampNow(),
+		Cursor:     formatCursor(int64(seq)),
+	})
+	if err != nil {
+		return err
+	}
+	if err := b.Put(changeKey(seq), buf); err != nil {
+		return err
+	}
+	if seq > maxChanges {
+		return b.Delete(changeKey(seq - maxChanges))
+	}
+	return nil
+}
+
+// changeKey returns the key of the change, audit event or revision with sequence number seq, which
+// sort in order.
+func changeKey(seq uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, seq)
+	return k
+}
+
+// reindexOccurrence updates the resource and note index within tx for an occurrence changing from
+// the encoded occurrence old to o. Either may be nil for occurrences that are added or removed.
+func reindexOccurrence(tx *bolt.Tx, old []byte, o *pb.Occurrence) error {
+	b := tx.Bucket([]byte(bucketOccurrencesByResourceNote))
+	if old != nil {
+		var prev pb.Occurrence
+		if err := proto.Unmarshal(old, &prev); err != nil {
+			return err
+		}
+		if err := b.Delete(resourceNoteIndexKey(&prev)); err != nil {
+			return err
+		}
+	}
+	if o == nil {
+		return nil
+	}
+	return b.Put(resourceNoteIndexKey(o), []byte{})
+}
+
+// resourceNoteIndexKey returns the key o is stored under in the resource and note index.
+func resourceNoteIndexKey(o *pb.Occurrence) []byte {
+	return []byte(resourceNoteKey(o) + "\x00" + o.Name)
+}
+
+// matchETag decodes value into a message of the same type as like and checks it against etag.
+func matchETag(value []byte, like proto.Message, etag string) error {
+	if etag == "" {
+		return nil
+	}
+	stored := proto.Clone(like)
+	stored.Reset()
+	if err := proto.Unmarshal(value, stored); err != nil {
+		return err
+	}
+	return checkETag(stored, etag)
+}
@@ -15,16 +15,25 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grafeas/grafeas/go/etag"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
 	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
 	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
 	"github.com/grafeas/grafeas/server-go"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
 	opspb "google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -36,16 +45,54 @@ type memStore struct {
 	occurrencesByID map[string]*pb.Occurrence
 	notesByID       map[string]*pb.Note
 	opsByID         map[string]*opspb.Operation
+	policies        map[string]*iampb.Policy
 	projects        map[string]bool
+	// deletedProjects and deletedNotes hold the deleted projects and notes by their IDs and names
+	// until they are undeleted or purged.
+	deletedProjects map[string]time.Time
+	deletedNotes    map[string]*deletedNote
+	// auditEvents holds the audit log of every project in order.
+	auditEvents map[string][]*auditpb.AuditEvent
+	// noteRevisions and occurrenceRevisions hold the previous revisions of notes and occurrences by
+	// their names, oldest first.
+	noteRevisions       map[string][]*revpb.NoteRevision
+	occurrenceRevisions map[string][]*revpb.OccurrenceRevision
+	// occurrencesByResourceNote indexes the names of occurrences by their project, resource URI and
+	// note name, see resourceNoteKey.
+	occurrencesByResourceNote map[string]map[string]bool
+	// changes holds the most recent occurrence changes in order, the last one having sequence number
+	// changeSeq.
+	changes   []*memChange
+	changeSeq int64
+	notifier  changeNotifier
+}
+
+// memChange is a change to an occurrence recorded by the memStore.
+type memChange struct {
+	pID   string
+	event *watchpb.OccurrenceEvent
+}
+
+// deletedNote is a note deleted from the memStore and the time it was deleted.
+type deletedNote struct {
+	note       *pb.Note
+	deleteTime time.Time
 }
 
 // NewMemStore creates a memStore with all maps initialized.
 func NewMemStore() server.Storager {
 	return &memStore{
-		occurrencesByID: map[string]*pb.Occurrence{},
-		notesByID:       map[string]*pb.Note{},
-		opsByID:         map[string]*opspb.Operation{},
-		projects:        map[string]bool{},
+		occurrencesByID:           map[string]*pb.Occurrence{},
+		notesByID:                 map[string]*pb.Note{},
+		opsByID:                   map[string]*opspb.Operation{},
+		policies:                  map[string]*iampb.Policy{},
+		projects:                  map[string]bool{},
+		deletedProjects:           map[string]time.Time{},
+		deletedNotes:              map[string]*deletedNote{},
+		auditEvents:               map[string][]*auditpb.AuditEvent{},
+		noteRevisions:             map[string][]*revpb.NoteRevision{},
+		occurrenceRevisions:       map[string][]*revpb.OccurrenceRevision{},
+		occurrencesByResourceNote: map[string]map[string]bool{},
 	}
 }
 
@@ -56,6 +103,9 @@ func (m *memStore) CreateProject(pID string) error {
 	if _, ok := m.projects[pID]; ok {
 		return status.Errorf(codes.AlreadyExists, "Project with name %q already exists", pID)
 	}
+	if _, ok := m.deletedProjects[pID]; ok {
+		return status.Errorf(codes.AlreadyExists, "Project with name %q is deleted but not purged yet", pID)
+	}
 	m.projects[pID] = true
 	return nil
 }
@@ -68,6 +118,21 @@ func (m *memStore) DeleteProject(pID string) error {
 		return status.Errorf(codes.NotFound, "Project with name %q does not Exist", pID)
 	}
 	delete(m.projects, pID)
+	m.deletedProjects[pID] = time.Now()
+	return nil
+}
+
+// UndeleteProject restores the project with the given pID if it was deleted at or after
+// deletedAfter
+func (m *memStore) UndeleteProject(pID string, deletedAfter time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+	t, ok := m.deletedProjects[pID]
+	if !ok || t.Before(deletedAfter) {
+		return status.Errorf(codes.NotFound, "Deleted project with name %q does not Exist", pID)
+	}
+	delete(m.deletedProjects, pID)
+	m.projects[pID] = true
 	return nil
 }
 
@@ -108,30 +173,79 @@ func (m *memStore) CreateOccurrence(o *pb.Occurrence) error {
 		return status.Errorf(codes.AlreadyExists, "Occurrence with name %q already exists", o.Name)
 	}
 	m.occurrencesByID[o.Name] = o
+	m.indexOccurrence(o)
+	m.recordChange(watchpb.OccurrenceEvent_CREATED, o)
 	return nil
 }
 
+// UpsertOccurrence updates the occurrence with the same project, resource URI and note name as o in
+// place, or adds o if there is none
+func (m *memStore) UpsertOccurrence(o *pb.Occurrence) (*pb.Occurrence, error) {
+	m.Lock()
+	defer m.Unlock()
+	names := m.occurrencesByResourceNote[resourceNoteKey(o)]
+	if len(names) == 0 {
+		if _, ok := m.occurrencesByID[o.Name]; ok {
+			return nil, status.Errorf(codes.AlreadyExists, "Occurrence with name %q already exists", o.Name)
+		}
+		m.occurrencesByID[o.Name] = o
+		m.indexOccurrence(o)
+		m.recordChange(watchpb.OccurrenceEvent_CREATED, o)
+		return o, nil
+	}
+	// Should there be duplicates from before upserting was used, always update the same one.
+	var oName string
+	for n := range names {
+		if oName == "" || n < oName {
+			oName = n
+		}
+	}
+	existing := m.occurrencesByID[oName]
+	o.Name = existing.Name
+	o.CreateTime = existing.CreateTime
+	o.UpdateTime = ptypes.TimestampNow()
+	m.occurrencesByID[oName] = o
+	m.occurrenceRevisions[oName] = append(m.occurrenceRevisions[oName], &revpb.OccurrenceRevision{Occurrence: existing, ReplaceTime: o.UpdateTime})
+	m.recordChange(watchpb.OccurrenceEvent_UPDATED, o)
+	return o, nil
+}
+
 // DeleteOccurrence deletes the occurrence with the given pID and oID from the memStore
-func (m *memStore) DeleteOccurrence(pID, oID string) error {
+func (m *memStore) DeleteOccurrence(pID, oID, etag string) error {
 	oName := name.OccurrenceName(pID, oID)
 	m.Lock()
 	defer m.Unlock()
-	if _, ok := m.occurrencesByID[oName]; !ok {
+	existing, ok := m.occurrencesByID[oName]
+	if !ok {
 		return status.Errorf(codes.NotFound, "Occurrence with name %q does not Exist", oName)
 	}
+	if err := checkETag(existing, etag); err != nil {
+		return err
+	}
 	delete(m.occurrencesByID, oName)
+	delete(m.occurrenceRevisions, oName)
+	m.unindexOccurrence(existing)
+	m.recordChange(watchpb.OccurrenceEvent_DELETED, existing)
 	return nil
 }
 
 // UpdateOccurrence updates the existing occurrence with the given projectID and occurrenceID
-func (m *memStore) UpdateOccurrence(pID, oID string, o *pb.Occurrence) error {
+func (m *memStore) UpdateOccurrence(pID, oID string, o *pb.Occurrence, etag string) error {
 	oName := name.OccurrenceName(pID, oID)
 	m.Lock()
 	defer m.Unlock()
-	if _, ok := m.occurrencesByID[oName]; !ok {
+	existing, ok := m.occurrencesByID[oName]
+	if !ok {
 		return status.Errorf(codes.NotFound, "Occurrence with name %q does not Exist", oName)
 	}
+	if err := checkETag(existing, etag); err != nil {
+		return err
+	}
+	m.unindexOccurrence(existing)
 	m.occurrencesByID[oName] = o
+	m.occurrenceRevisions[oName] = append(m.occurrenceRevisions[oName], &revpb.OccurrenceRevision{Occurrence: existing, ReplaceTime: ptypes.TimestampNow()})
+	m.indexOccurrence(o)
+	m.recordChange(watchpb.OccurrenceEvent_UPDATED, o)
 	return nil
 }
 
@@ -173,31 +287,81 @@ func (m *memStore) CreateNote(n *pb.Note) error {
 	if _, ok := m.notesByID[n.Name]; ok {
 		return status.Errorf(codes.AlreadyExists, "Note with name %q already exists", n.Name)
 	}
+	if _, ok := m.deletedNotes[n.Name]; ok {
+		return status.Errorf(codes.AlreadyExists, "Note with name %q is deleted but not purged yet", n.Name)
+	}
 	m.notesByID[n.Name] = n
 	return nil
 }
 
 // DeleteNote deletes the note with the given pID and nID from the memStore
-func (m *memStore) DeleteNote(pID, nID string) error {
+func (m *memStore) DeleteNote(pID, nID, etag string) error {
 	nName := name.NoteName(pID, nID)
 	m.Lock()
 	defer m.Unlock()
-	if _, ok := m.notesByID[nName]; !ok {
+	existing, ok := m.notesByID[nName]
+	if !ok {
 		return status.Errorf(codes.NotFound, "Note with name %q does not Exist", nName)
 	}
+	if err := checkETag(existing, etag); err != nil {
+		return err
+	}
 	delete(m.notesByID, nName)
+	m.deletedNotes[nName] = &deletedNote{note: existing, deleteTime: time.Now()}
 	return nil
 }
 
+// UndeleteNote restores the note with the given pID and nID if it was deleted at or after
+// deletedAfter
+func (m *memStore) UndeleteNote(pID, nID string, deletedAfter time.Time) error {
+	nName := name.NoteName(pID, nID)
+	m.Lock()
+	defer m.Unlock()
+	d, ok := m.deletedNotes[nName]
+	if !ok || d.deleteTime.Before(deletedAfter) {
+		return status.Errorf(codes.NotFound, "Deleted note with name %q does not Exist", nName)
+	}
+	delete(m.deletedNotes, nName)
+	m.notesByID[nName] = d.note
+	return nil
+}
+
+// PurgeDeleted permanently removes the projects and notes deleted before the given time
+func (m *memStore) PurgeDeleted(before time.Time) ([]string, error) {
+	m.Lock()
+	defer m.Unlock()
+	var purged []string
+	for pID, t := range m.deletedProjects {
+		if t.Before(before) {
+			delete(m.deletedProjects, pID)
+			purged = append(purged, name.FormatProject(pID))
+		}
+	}
+	for nName, d := range m.deletedNotes {
+		if d.deleteTime.Before(before) {
+			delete(m.deletedNotes, nName)
+			delete(m.noteRevisions, nName)
+			purged = append(purged, nName)
+		}
+	}
+	sort.Strings(purged)
+	return purged, nil
+}
+
 // UpdateNote updates the existing note with the given pID and nID
-func (m *memStore) UpdateNote(pID, nID string, n *pb.Note) error {
+func (m *memStore) UpdateNote(pID, nID string, n *pb.Note, etag string) error {
 	nName := name.NoteName(pID, nID)
 	m.Lock()
 	defer m.Unlock()
-	if _, ok := m.notesByID[nName]; !ok {
+	existing, ok := m.notesByID[nName]
+	if !ok {
 		return status.Errorf(codes.NotFound, "Note with name %q does not Exist", nName)
 	}
+	if err := checkETag(existing, etag); err != nil {
+		return err
+	}
 	m.notesByID[nName] = n
+	m.noteRevisions[nName] = append(m.noteRevisions[nName], &revpb.NoteRevision{Note: existing, ReplaceTime: ptypes.TimestampNow()})
 	return nil
 }
 
@@ -292,7 +456,8 @@ func (m *memStore) CreateOperation(o *opspb.Operation) error {
 	if _, ok := m.opsByID[o.Name]; ok {
 		return status.Errorf(codes.AlreadyExists, "Operation with name %q already exists", o.Name)
 	}
-	m.opsByID[o.Name] = o
+	// Store a copy, so that whether the operation is done only changes through UpdateOperation.
+	m.opsByID[o.Name] = proto.Clone(o).(*opspb.Operation)
 	return nil
 }
 
@@ -308,15 +473,19 @@ func (m *memStore) DeleteOperation(pID, opID string) error {
 	return nil
 }
 
-// UpdateOperation updates the existing operation with the given pID and nID
+// UpdateOperation updates the existing operation with the given pID and nID, unless it is done
 func (m *memStore) UpdateOperation(pID, opID string, op *opspb.Operation) error {
 	opName := name.OperationName(pID, opID)
 	m.Lock()
 	defer m.Unlock()
-	if _, ok := m.opsByID[opName]; !ok {
+	old, ok := m.opsByID[opName]
+	if !ok {
 		return status.Errorf(codes.NotFound, "Operation with name %q does not Exist", opName)
 	}
-	m.opsByID[opName] = op
+	if old.Done {
+		return errOperationDone(opName)
+	}
+	m.opsByID[opName] = proto.Clone(op).(*opspb.Operation)
 	return nil
 }
 
@@ -339,6 +508,179 @@ func (m *memStore) ListOperations(pID, filters string, pageSize int, pageToken s
 	return ops[startPos:endPos], nextPageToken(endPos, len(ops)), nil
 }
 
+// GetIamPolicy returns the IAM policy of the project or note with the given resource name
+func (m *memStore) GetIamPolicy(resource string) (*iampb.Policy, error) {
+	m.RLock()
+	defer m.RUnlock()
+	p, ok := m.policies[resource]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "Policy of %q does not Exist", resource)
+	}
+	return p, nil
+}
+
+// SetIamPolicy replaces the IAM policy of the project or note with the given resource name
+func (m *memStore) SetIamPolicy(resource string, p *iampb.Policy, etag string) error {
+	m.Lock()
+	defer m.Unlock()
+	existing, ok := m.policies[resource]
+	if !ok {
+		existing = &iampb.Policy{}
+	}
+	if err := checkETag(existing, etag); err != nil {
+		return err
+	}
+	m.policies[resource] = p
+	return nil
+}
+
+// DeleteIamPolicy deletes the IAM policy of the project or note with the given resource name
+func (m *memStore) DeleteIamPolicy(resource string) error {
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.policies[resource]; !ok {
+		return status.Errorf(codes.NotFound, "Policy of %q does not Exist", resource)
+	}
+	delete(m.policies, resource)
+	return nil
+}
+
+// AppendAuditEvent appends the specified event to the audit log of project pID
+func (m *memStore) AppendAuditEvent(pID string, e *auditpb.AuditEvent) error {
+	m.Lock()
+	defer m.Unlock()
+	m.auditEvents[pID] = append(m.auditEvents[pID], e)
+	return nil
+}
+
+// ListAuditEvents returns up to pageSize number of the events of the audit log of project pID
+// matching resource, start and end beginning at pageToken
+func (m *memStore) ListAuditEvents(pID, resource string, start, end time.Time, pageSize int, pageToken string) ([]*auditpb.AuditEvent, string, error) {
+	events := []*auditpb.AuditEvent{}
+	m.RLock()
+	defer m.RUnlock()
+	for _, e := range m.auditEvents[pID] {
+		if auditEventMatches(e, resource, start, end) {
+			events = append(events, e)
+		}
+	}
+	startPos := min(parsePageToken(pageToken, 0), len(events))
+	endPos := min(startPos+pageSize, len(events))
+	return events[startPos:endPos], nextPageToken(endPos, len(events)), nil
+}
+
+// ListNoteRevisions returns up to pageSize number of the previous revisions of the note with pID
+// and nID, newest first, beginning at pageToken
+func (m *memStore) ListNoteRevisions(pID, nID string, pageSize int, pageToken string) ([]*revpb.NoteRevision, string, error) {
+	m.RLock()
+	defer m.RUnlock()
+	all := m.noteRevisions[name.NoteName(pID, nID)]
+	revs := make([]*revpb.NoteRevision, len(all))
+	for i, r := range all {
+		revs[len(all)-1-i] = r
+	}
+	startPos := min(parsePageToken(pageToken, 0), len(revs))
+	endPos := min(startPos+pageSize, len(revs))
+	return revs[startPos:endPos], nextPageToken(endPos, len(revs)), nil
+}
+
+// ListOccurrenceRevisions returns up to pageSize number of the previous revisions of the occurrence
+// with pID and oID, newest first, beginning at pageToken
+func (m *memStore) ListOccurrenceRevisions(pID, oID string, pageSize int, pageToken string) ([]*revpb.OccurrenceRevision, string, error) {
+	m.RLock()
+	defer m.RUnlock()
+	all := m.occurrenceRevisions[name.OccurrenceName(pID, oID)]
+	revs := make([]*revpb.OccurrenceRevision, len(all))
+	for i, r := range all {
+		revs[len(all)-1-i] = r
+	}
+	startPos := min(parsePageToken(pageToken, 0), len(revs))
+	endPos := min(startPos+pageSize, len(revs))
+	return revs[startPos:endPos], nextPageToken(endPos, len(revs)), nil
+}
+
+// WatchOccurrences calls fn with every change to the occurrences of project pID after cursor
+func (m *memStore) WatchOccurrences(ctx context.Context, pID, cursor string, fn func(*watchpb.OccurrenceEvent) error) error {
+	wake, stop := m.notifier.subscribe()
+	defer stop()
+	latest := func() (int64, error) {
+		m.RLock()
+		defer m.RUnlock()
+		return m.changeSeq, nil
+	}
+	changesAfter := func(seq int64) ([]*watchpb.OccurrenceEvent, int64, error) {
+		m.RLock()
+		defer m.RUnlock()
+		var events []*watchpb.OccurrenceEvent
+		// changes[i] has sequence number changeSeq-len(changes)+1+i.
+		start := int(seq-m.changeSeq) + len(m.changes)
+		if start < 0 {
+			start = 0
+		}
+		for i := start; i < len(m.changes); i++ {
+			if m.changes[i].pID == pID {
+				events = append(events, m.changes[i].event)
+			}
+		}
+		return events, m.changeSeq, nil
+	}
+	return watchChanges(ctx, cursor, wake, latest, changesAfter, fn)
+}
+
+// recordChange adds a change to o to the change feed and wakes up watchers. The lock must be held.
+func (m *memStore) recordChange(t watchpb.OccurrenceEvent_Type, o *pb.Occurrence) {
+	pID, _, _ := name.ParseOccurrence(o.Name)
+	m.changeSeq++
+	m.changes = append(m.changes, &memChange{
+		pID: pID,
+		event: &watchpb.OccurrenceEvent{
+			Type:       t,
+			Occurrence: o,
+			EventTime:  ptypes.TimestampNow(),
+			Cursor:     formatCursor(m.changeSeq),
+		},
+	})
+	if len(m.changes) > maxChanges {
+		m.changes = m.changes[len(m.changes)-maxChanges:]
+	}
+	m.notifier.notify()
+}
+
+// indexOccurrence adds o to the resource and note index. The lock must be held.
+func (m *memStore) indexOccurrence(o *pb.Occurrence) {
+	key := resourceNoteKey(o)
+	if m.occurrencesByResourceNote[key] == nil {
+		m.occurrencesByResourceNote[key] = map[string]bool{}
+	}
+	m.occurrencesByResourceNote[key][o.Name] = true
+}
+
+// unindexOccurrence removes o from the resource and note index. The lock must be held.
+func (m *memStore) unindexOccurrence(o *pb.Occurrence) {
+	key := resourceNoteKey(o)
+	delete(m.occurrencesByResourceNote[key], o.Name)
+	if len(m.occurrencesByResourceNote[key]) == 0 {
+		delete(m.occurrencesByResourceNote, key)
+	}
+}
+
+// resourceNoteKey returns the key occurrences are upserted by: two occurrences in the same project
+// for the same resource URI and note are considered the same finding.
+func resourceNoteKey(o *pb.Occurrence) string {
+	pID, _, _ := name.ParseOccurrence(o.Name)
+	return strings.Join([]string{pID, o.GetResource().GetUri(), o.NoteName}, "\x00")
+}
+
+// checkETag verifies that the stored entity matches the etag expected by the caller.
+func checkETag(stored proto.Message, want string) error {
+	return etag.Check(stored, want)
+}
+
+// errOperationDone is the error of updating the operation with the given name after it is done.
+func errOperationDone(opName string) error {
+	return status.Errorf(codes.FailedPrecondition, "Operation with name %q is done", opName)
+}
+
 // Parses the page token to an int. Returns defaultValue if parsing fails
 func parsePageToken(pageToken string, defaultValue int) int {
 	if pageToken == "" {
@@ -360,6 +702,19 @@ func min(a, b int) int {
 	}
 }
 
+// auditEventMatches returns whether e is about resource, if it is non-empty, and its event time is
+// in [start, end) for the bounds that are non-zero.
+func auditEventMatches(e *auditpb.AuditEvent, resource string, start, end time.Time) bool {
+	if resource != "" && e.Resource != resource {
+		return false
+	}
+	t, err := ptypes.Timestamp(e.EventTime)
+	if err != nil {
+		return start.IsZero() && end.IsZero()
+	}
+	return (start.IsZero() || !t.Before(start)) && (end.IsZero() || t.Before(end))
+}
+
 // nextPageToken returns the next page token (the next item index or empty if not more items are left)
 func nextPageToken(lastPage, total int) string {
 	if lastPage == total {
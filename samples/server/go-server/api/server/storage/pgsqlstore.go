@@ -15,18 +15,27 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/fernet/fernet-go"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	tpb "github.com/golang/protobuf/ptypes/timestamp"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
 	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
 	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
 	"github.com/lib/pq"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
 	opspb "google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -35,6 +44,28 @@ import (
 type pgSQLStore struct {
 	*sql.DB
 	paginationKey string
+	// source is the data source name of the database, used to listen for occurrence changes.
+	source string
+	// listener wakes up the watchers through notifier whenever an occurrence changes. It is started
+	// by the first watch.
+	listenerMu sync.Mutex
+	listener   *pq.Listener
+	notifier   changeNotifier
+	// closed stops trimming the occurrence change log. It is closed once, by the first Close.
+	closed    chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// trimChangesInterval is the time between trims of the occurrence change log to its most recent
+// maxChanges changes.
+const trimChangesInterval = time.Minute
+
+// changeTypes maps the trigger operations recorded in occurrence_changes to event types.
+var changeTypes = map[string]watchpb.OccurrenceEvent_Type{
+	"INSERT": watchpb.OccurrenceEvent_CREATED,
+	"UPDATE": watchpb.OccurrenceEvent_UPDATED,
+	"DELETE": watchpb.OccurrenceEvent_DELETED,
 }
 
 func NewPgSQLStore(config *PgSQLConfig) *pgSQLStore {
@@ -42,7 +73,8 @@ func NewPgSQLStore(config *PgSQLConfig) *pgSQLStore {
 	if err != nil {
 		log.Fatal(err.Error())
 	}
-	db, err := sql.Open("postgres", createSourceString(config.User, config.Password, config.Host, config.DbName, config.SSLMode))
+	source := createSourceString(config.User, config.Password, config.Host, config.DbName, config.SSLMode)
+	db, err := sql.Open("postgres", source)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -57,10 +89,47 @@ func NewPgSQLStore(config *PgSQLConfig) *pgSQLStore {
 	pg := pgSQLStore{
 		DB:            db,
 		paginationKey: config.PaginationKey,
+		source:        source,
+		closed:        make(chan struct{}),
 	}
+	go pg.trimChanges()
 	return &pg
 }
 
+// Close stops trimming the occurrence change log and listening for occurrence changes, and closes
+// the database. Closing the store again has no effect.
+func (pg *pgSQLStore) Close() error {
+	pg.closeOnce.Do(func() {
+		close(pg.closed)
+		pg.listenerMu.Lock()
+		if pg.listener != nil {
+			// This also ends the goroutine waking up the watchers.
+			pg.listener.Close()
+		}
+		pg.listenerMu.Unlock()
+		pg.closeErr = pg.DB.Close()
+	})
+	return pg.closeErr
+}
+
+// trimChanges deletes all but the most recent maxChanges occurrence changes every
+// trimChangesInterval until the store is closed. Trimming isn't done by the trigger recording the
+// changes, which would add a delete to every occurrence write.
+func (pg *pgSQLStore) trimChanges() {
+	t := time.NewTicker(trimChangesInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-pg.closed:
+			return
+		case <-t.C:
+		}
+		if _, err := pg.DB.Exec(trimOccurrenceChanges, maxChanges); err != nil {
+			log.Println("Failed to trim Occurrence changes", err)
+		}
+	}
+}
+
 func createDatabase(source, dbName string) error {
 	db, err := sql.Open("postgres", source)
 	if err != nil {
@@ -89,6 +158,14 @@ func createDatabase(source, dbName string) error {
 
 // CreateProject adds the specified project to the store
 func (pg *pgSQLStore) CreateProject(pID string) error {
+	var deleted bool
+	if err := pg.DB.QueryRow(projectDeleted, name.FormatProject(pID)).Scan(&deleted); err != nil {
+		log.Println("Failed to query deleted Project from database", err)
+		return status.Error(codes.Internal, "Failed to insert Project in database")
+	}
+	if deleted {
+		return status.Errorf(codes.AlreadyExists, "Project with name %q is deleted but not purged yet", pID)
+	}
 	_, err := pg.DB.Exec(insertProject, name.FormatProject(pID))
 	if err, ok := err.(*pq.Error); ok {
 		// Check for unique_violation
@@ -119,6 +196,25 @@ func (pg *pgSQLStore) DeleteProject(pID string) error {
 	return nil
 }
 
+// UndeleteProject restores the project with the given pID if it was deleted at or after
+// deletedAfter
+func (pg *pgSQLStore) UndeleteProject(pID string, deletedAfter time.Time) error {
+	pName := name.FormatProject(pID)
+	result, err := pg.DB.Exec(undeleteProject, pName, deletedAfter)
+	if err != nil {
+		log.Println("Failed to undelete Project in database", err)
+		return status.Error(codes.Internal, "Failed to undelete Project in database")
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return status.Error(codes.Internal, "Failed to undelete Project in database")
+	}
+	if count == 0 {
+		return status.Errorf(codes.NotFound, "Deleted project with name %q does not Exist", pName)
+	}
+	return nil
+}
+
 // GetProject returns the project with the given pID from the store
 func (pg *pgSQLStore) GetProject(pID string) (*prpb.Project, error) {
 	pName := name.FormatProject(pID)
@@ -136,34 +232,15 @@ func (pg *pgSQLStore) GetProject(pID string) (*prpb.Project, error) {
 // ListProjects returns up to pageSize number of projects beginning at pageToken (or from
 // start if pageToken is the empty string).
 func (pg *pgSQLStore) ListProjects(filter string, pageSize int, pageToken string) ([]*prpb.Project, string, error) {
-	var rows *sql.Rows
-	id := decryptInt64(pageToken, pg.paginationKey, 0)
-	rows, err := pg.DB.Query(listProjects, id, pageSize)
-	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to list Projects from database")
-	}
-	count, err := pg.count(projectCount)
-	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to count Projects from database")
-	}
 	var projects []*prpb.Project
-	var lastId int64
-	for rows.Next() {
-		var name string
-		err := rows.Scan(&lastId, &name)
-		if err != nil {
-			return nil, "", status.Error(codes.Internal, "Failed to scan Project row")
-		}
+	npt, err := pg.listPage(listProjects, pageSize, pageToken, func(name string) error {
 		projects = append(projects, &prpb.Project{Name: name})
-	}
-	if count == lastId {
-		return projects, "", nil
-	}
-	encryptedPage, err := encryptInt64(lastId, pg.paginationKey)
+		return nil
+	})
 	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to paginate projects")
+		return nil, "", status.Error(codes.Internal, "Failed to list Projects from database")
 	}
-	return projects, encryptedPage, nil
+	return projects, npt, nil
 }
 
 // CreateOccurrence adds the specified occurrence
@@ -178,7 +255,7 @@ func (pg *pgSQLStore) CreateOccurrence(o *pb.Occurrence) error {
 		log.Printf("Invalid note name: %v", o.NoteName)
 		return status.Error(codes.InvalidArgument, "Invalid note name")
 	}
-	_, err = pg.DB.Exec(insertOccurrence, oPID, oID, nPID, nID, proto.MarshalTextString(o))
+	_, err = pg.DB.Exec(insertOccurrence, oPID, oID, nPID, nID, proto.MarshalTextString(o), o.GetResource().GetUri())
 	if err, ok := err.(*pq.Error); ok {
 		// Check for unique_violation
 		if err.Code == "23505" {
@@ -191,9 +268,73 @@ func (pg *pgSQLStore) CreateOccurrence(o *pb.Occurrence) error {
 	return nil
 }
 
+// UpsertOccurrence updates the occurrence with the same project, resource URI and note name as o in
+// place, or adds o if there is none. Occurrences stored before the resource_uri column existed
+// are not matched.
+func (pg *pgSQLStore) UpsertOccurrence(o *pb.Occurrence) (*pb.Occurrence, error) {
+	oPID, oID, err := name.ParseOccurrence(o.Name)
+	if err != nil {
+		log.Printf("Invalid occurrence name: %v", o.Name)
+		return nil, status.Error(codes.InvalidArgument, "Invalid occurrence name")
+	}
+	nPID, nID, err := name.ParseNote(o.NoteName)
+	if err != nil {
+		log.Printf("Invalid note name: %v", o.NoteName)
+		return nil, status.Error(codes.InvalidArgument, "Invalid note name")
+	}
+	uri := o.GetResource().GetUri()
+	tx, err := pg.DB.Begin()
+	if err != nil {
+		log.Println("Failed to upsert Occurrence in database", err)
+		return nil, status.Error(codes.Internal, "Failed to upsert Occurrence in database")
+	}
+	defer tx.Rollback()
+	// Serialize upserts of the same resource and note, so concurrent ones can't both insert.
+	if _, err := tx.Exec(lockResourceNote, resourceNoteKey(o)); err != nil {
+		log.Println("Failed to upsert Occurrence in database", err)
+		return nil, status.Error(codes.Internal, "Failed to upsert Occurrence in database")
+	}
+	var existingID, data string
+	switch err := tx.QueryRow(searchResourceOccurrence, oPID, uri, nPID, nID).Scan(&existingID, &data); {
+	case err == sql.ErrNoRows:
+		_, err = tx.Exec(insertOccurrence, oPID, oID, nPID, nID, proto.MarshalTextString(o), uri)
+		if err, ok := err.(*pq.Error); ok {
+			// Check for unique_violation
+			if err.Code == "23505" {
+				return nil, status.Errorf(codes.AlreadyExists, "Occurrence with name %q already exists", o.Name)
+			}
+			log.Println("Failed to insert Occurrence in database", err)
+			return nil, status.Error(codes.Internal, "Failed to insert Occurrence in database")
+		}
+	case err != nil:
+		log.Println("Failed to query Occurrence from database", err)
+		return nil, status.Error(codes.Internal, "Failed to query Occurrence from database")
+	default:
+		var existing pb.Occurrence
+		if err := proto.UnmarshalText(data, &existing); err != nil {
+			return nil, status.Error(codes.Internal, "Failed to unmarshal Occurrence from database")
+		}
+		o.Name = existing.Name
+		o.CreateTime = existing.CreateTime
+		o.UpdateTime = ptypes.TimestampNow()
+		if _, err := tx.Exec(updateOccurrence, oPID, existingID, proto.MarshalTextString(o), uri); err != nil {
+			log.Println("Failed to update Occurrence in database", err)
+			return nil, status.Error(codes.Internal, "Failed to update Occurrence")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("Failed to upsert Occurrence in database", err)
+		return nil, status.Error(codes.Internal, "Failed to upsert Occurrence in database")
+	}
+	return o, nil
+}
+
 // DeleteOccurrence deletes the occurrence with the given pID and oID
-func (pg *pgSQLStore) DeleteOccurrence(pID, oID string) error {
-	result, err := pg.DB.Exec(deleteOccurrence, pID, oID)
+func (pg *pgSQLStore) DeleteOccurrence(pID, oID, etag string) error {
+	result, err := pg.execIfMatch(lockOccurrence, &pb.Occurrence{}, etag, deleteOccurrence, pID, oID)
+	if status.Code(err) == codes.Aborted {
+		return err
+	}
 	if err != nil {
 		return status.Error(codes.Internal, "Failed to delete Occurrence from database")
 	}
@@ -208,8 +349,11 @@ func (pg *pgSQLStore) DeleteOccurrence(pID, oID string) error {
 }
 
 // UpdateOccurrence updates the existing occurrence with the given projectID and occurrenceID
-func (pg *pgSQLStore) UpdateOccurrence(pID, oID string, o *pb.Occurrence) error {
-	result, err := pg.DB.Exec(updateOccurrence, pID, oID, proto.MarshalTextString(o))
+func (pg *pgSQLStore) UpdateOccurrence(pID, oID string, o *pb.Occurrence, etag string) error {
+	result, err := pg.execIfMatch(lockOccurrence, &pb.Occurrence{}, etag, updateOccurrence, pID, oID, proto.MarshalTextString(o), o.GetResource().GetUri())
+	if status.Code(err) == codes.Aborted {
+		return err
+	}
 	if err != nil {
 		return status.Error(codes.Internal, "Failed to update Occurrence")
 	}
@@ -244,39 +388,19 @@ func (pg *pgSQLStore) GetOccurrence(pID, oID string) (*pb.Occurrence, error) {
 // ListOccurrences returns up to pageSize number of occurrences for this project (pID) beginning
 // at pageToken (or from start if pageToken is the empty string).
 func (pg *pgSQLStore) ListOccurrences(pID, filters string, pageSize int, pageToken string) ([]*pb.Occurrence, string, error) {
-	var rows *sql.Rows
-	id := decryptInt64(pageToken, pg.paginationKey, 0)
-	rows, err := pg.DB.Query(listOccurrences, pID, id, pageSize)
-	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to list Occurrences from database")
-	}
-	count, err := pg.count(occurrenceCount, pID)
-	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to count Occurrences from database")
-	}
 	var os []*pb.Occurrence
-	var lastId int64
-	for rows.Next() {
-		var data string
-		err := rows.Scan(&lastId, &data)
-		if err != nil {
-			return nil, "", status.Error(codes.Internal, "Failed to scan Occurrences row")
-		}
+	npt, err := pg.listPage(listOccurrences, pageSize, pageToken, func(data string) error {
 		var o pb.Occurrence
-		proto.UnmarshalText(data, &o)
-		if err != nil {
-			return nil, "", status.Error(codes.Internal, "Failed to unmarshal Occurrence from database")
+		if err := proto.UnmarshalText(data, &o); err != nil {
+			return err
 		}
 		os = append(os, &o)
-	}
-	if count == lastId {
-		return os, "", nil
-	}
-	encryptedPage, err := encryptInt64(lastId, pg.paginationKey)
+		return nil
+	}, pID)
 	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to paginate projects")
+		return nil, "", status.Error(codes.Internal, "Failed to list Occurrences from database")
 	}
-	return os, encryptedPage, nil
+	return os, npt, nil
 }
 
 // CreateNote adds the specified note
@@ -286,6 +410,14 @@ func (pg *pgSQLStore) CreateNote(n *pb.Note) error {
 		log.Printf("Invalid note name: %v", n.Name)
 		return status.Error(codes.InvalidArgument, "Invalid note name")
 	}
+	var deleted bool
+	if err := pg.DB.QueryRow(noteDeleted, pID, nID).Scan(&deleted); err != nil {
+		log.Println("Failed to query deleted Note from database", err)
+		return status.Error(codes.Internal, "Failed to insert Note in database")
+	}
+	if deleted {
+		return status.Errorf(codes.AlreadyExists, "Note with name %q is deleted but not purged yet", n.Name)
+	}
 	_, err = pg.DB.Exec(insertNote, pID, nID, proto.MarshalTextString(n))
 	if err, ok := err.(*pq.Error); ok {
 		// Check for unique_violation
@@ -300,8 +432,11 @@ func (pg *pgSQLStore) CreateNote(n *pb.Note) error {
 }
 
 // DeleteNote deletes the note with the given pID and nID
-func (pg *pgSQLStore) DeleteNote(pID, nID string) error {
-	result, err := pg.DB.Exec(deleteNote, pID, nID)
+func (pg *pgSQLStore) DeleteNote(pID, nID, etag string) error {
+	result, err := pg.execIfMatch(lockNote, &pb.Note{}, etag, deleteNote, pID, nID)
+	if status.Code(err) == codes.Aborted {
+		return err
+	}
 	if err != nil {
 		return status.Error(codes.Internal, "Failed to delete Note from database")
 	}
@@ -315,9 +450,82 @@ func (pg *pgSQLStore) DeleteNote(pID, nID string) error {
 	return nil
 }
 
+// UndeleteNote restores the note with the given pID and nID if it was deleted at or after
+// deletedAfter
+func (pg *pgSQLStore) UndeleteNote(pID, nID string, deletedAfter time.Time) error {
+	result, err := pg.DB.Exec(undeleteNote, pID, nID, deletedAfter)
+	if err != nil {
+		log.Println("Failed to undelete Note in database", err)
+		return status.Error(codes.Internal, "Failed to undelete Note in database")
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return status.Error(codes.Internal, "Failed to undelete Note in database")
+	}
+	if count == 0 {
+		return status.Errorf(codes.NotFound, "Deleted note with name %q does not Exist", name.FormatNote(pID, nID))
+	}
+	return nil
+}
+
+// PurgeDeleted permanently removes the projects and notes deleted before the given time, and the
+// revisions of the notes, in a single transaction
+func (pg *pgSQLStore) PurgeDeleted(before time.Time) ([]string, error) {
+	purged, err := pg.purgeDeleted(before)
+	if err != nil {
+		log.Println("Failed to purge deleted Projects and Notes from database", err)
+		return nil, status.Error(codes.Internal, "Failed to purge deleted Projects and Notes from database")
+	}
+	sort.Strings(purged)
+	return purged, nil
+}
+
+func (pg *pgSQLStore) purgeDeleted(before time.Time) ([]string, error) {
+	tx, err := pg.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	var purged []string
+	rows, err := tx.Query(purgeProjects, before)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var pName string
+		if err := rows.Scan(&pName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		purged = append(purged, pName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows, err = tx.Query(purgeNotes, before)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var pID, nID string
+		if err := rows.Scan(&pID, &nID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		purged = append(purged, name.FormatNote(pID, nID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return purged, tx.Commit()
+}
+
 // UpdateNote updates the existing note with the given pID and nID
-func (pg *pgSQLStore) UpdateNote(pID, nID string, n *pb.Note) error {
-	result, err := pg.DB.Exec(updateNote, pID, nID, proto.MarshalTextString(n))
+func (pg *pgSQLStore) UpdateNote(pID, nID string, n *pb.Note, etag string) error {
+	result, err := pg.execIfMatch(lockNote, &pb.Note{}, etag, updateNote, pID, nID, proto.MarshalTextString(n))
+	if status.Code(err) == codes.Aborted {
+		return err
+	}
 	if err != nil {
 		return status.Error(codes.Internal, "Failed to update Note")
 	}
@@ -370,39 +578,19 @@ func (pg *pgSQLStore) GetNoteByOccurrence(pID, oID string) (*pb.Note, error) {
 // ListNotes returns up to pageSize number of notes for this project (pID) beginning
 // at pageToken (or from start if pageToken is the empty string).
 func (pg *pgSQLStore) ListNotes(pID, filters string, pageSize int, pageToken string) ([]*pb.Note, string, error) {
-	var rows *sql.Rows
-	id := decryptInt64(pageToken, pg.paginationKey, 0)
-	rows, err := pg.DB.Query(listNotes, pID, id, pageSize)
-	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to list Notes from database")
-	}
-	count, err := pg.count(noteCount, pID)
-	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to count Notes from database")
-	}
 	var ns []*pb.Note
-	var lastId int64
-	for rows.Next() {
-		var data string
-		err := rows.Scan(&lastId, &data)
-		if err != nil {
-			return nil, "", status.Error(codes.Internal, "Failed to scan Notes row")
-		}
+	npt, err := pg.listPage(listNotes, pageSize, pageToken, func(data string) error {
 		var n pb.Note
-		proto.UnmarshalText(data, &n)
-		if err != nil {
-			return nil, "", status.Error(codes.Internal, "Failed to unmarshal Note from database")
+		if err := proto.UnmarshalText(data, &n); err != nil {
+			return err
 		}
 		ns = append(ns, &n)
-	}
-	if count == lastId {
-		return ns, "", nil
-	}
-	encryptedPage, err := encryptInt64(lastId, pg.paginationKey)
+		return nil
+	}, pID)
 	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to paginate projects")
+		return nil, "", status.Error(codes.Internal, "Failed to list Notes from database")
 	}
-	return ns, encryptedPage, nil
+	return ns, npt, nil
 }
 
 // ListNoteOccurrences returns up to pageSize number of occcurrences on the particular note (nID)
@@ -412,39 +600,19 @@ func (pg *pgSQLStore) ListNoteOccurrences(pID, nID, filters string, pageSize int
 	if _, err := pg.GetNote(pID, nID); err != nil {
 		return nil, "", err
 	}
-	var rows *sql.Rows
-	id := decryptInt64(pageToken, pg.paginationKey, 0)
-	rows, err := pg.DB.Query(listNoteOccurrences, pID, nID, id, pageSize)
-	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to list Occurrences from database")
-	}
-	count, err := pg.count(noteOccurrencesCount, pID, nID)
-	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to count Occurrences from database")
-	}
 	var os []*pb.Occurrence
-	var lastId int64
-	for rows.Next() {
-		var data string
-		err := rows.Scan(&lastId, &data)
-		if err != nil {
-			return nil, "", status.Error(codes.Internal, "Failed to scan Occurrences row")
-		}
+	npt, err := pg.listPage(listNoteOccurrences, pageSize, pageToken, func(data string) error {
 		var o pb.Occurrence
-		proto.UnmarshalText(data, &o)
-		if err != nil {
-			return nil, "", status.Error(codes.Internal, "Failed to unmarshal Occurrence from database")
+		if err := proto.UnmarshalText(data, &o); err != nil {
+			return err
 		}
 		os = append(os, &o)
-	}
-	if count == lastId {
-		return os, "", nil
-	}
-	encryptedPage, err := encryptInt64(lastId, pg.paginationKey)
+		return nil
+	}, pID, nID)
 	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to paginate projects")
+		return nil, "", status.Error(codes.Internal, "Failed to list Occurrences from database")
 	}
-	return os, encryptedPage, nil
+	return os, npt, nil
 }
 
 // GetOperation returns the operation with pID and oID
@@ -501,18 +669,32 @@ func (pg *pgSQLStore) DeleteOperation(pID, opID string) error {
 	return nil
 }
 
-// UpdateOperation updates the existing operation with the given pID and nID
+// UpdateOperation updates the existing operation with the given pID and nID, unless it is done
 func (pg *pgSQLStore) UpdateOperation(pID, opID string, op *opspb.Operation) error {
-	result, err := pg.DB.Exec(updateOperation, pID, opID, proto.MarshalTextString(op))
+	tx, err := pg.DB.Begin()
 	if err != nil {
 		return status.Error(codes.Internal, "Failed to update Operation")
 	}
-	count, err := result.RowsAffected()
-	if err != nil {
+	defer tx.Rollback()
+	var data string
+	switch err := tx.QueryRow(lockOperation, pID, opID).Scan(&data); {
+	case err == sql.ErrNoRows:
+		return status.Errorf(codes.NotFound, "Operation with name %q/%q does not Exist", pID, opID)
+	case err != nil:
+		return status.Error(codes.Internal, "Failed to query Operation from database")
+	}
+	var old opspb.Operation
+	if err := proto.UnmarshalText(data, &old); err != nil {
+		return status.Error(codes.Internal, "Failed to unmarshal Operation from database")
+	}
+	if old.Done {
+		return errOperationDone(name.OperationName(pID, opID))
+	}
+	if _, err := tx.Exec(updateOperation, pID, opID, proto.MarshalTextString(op)); err != nil {
 		return status.Error(codes.Internal, "Failed to update Operation")
 	}
-	if count == 0 {
-		return status.Errorf(codes.NotFound, "Operation with name %q/%q does not Exist", pID, opID)
+	if err := tx.Commit(); err != nil {
+		return status.Error(codes.Internal, "Failed to update Operation")
 	}
 	return nil
 }
@@ -520,49 +702,378 @@ func (pg *pgSQLStore) UpdateOperation(pID, opID string, op *opspb.Operation) err
 // ListOperations returns up to pageSize number of operations for this project (pID) beginning
 // at pageToken (or from start if pageToken is the empty string).
 func (pg *pgSQLStore) ListOperations(pID, filters string, pageSize int, pageToken string) ([]*opspb.Operation, string, error) {
-	var rows *sql.Rows
-	id := decryptInt64(pageToken, pg.paginationKey, 0)
-	rows, err := pg.DB.Query(listOperations, pID, id, pageSize)
+	var ops []*opspb.Operation
+	npt, err := pg.listPage(listOperations, pageSize, pageToken, func(data string) error {
+		var op opspb.Operation
+		if err := proto.UnmarshalText(data, &op); err != nil {
+			return err
+		}
+		ops = append(ops, &op)
+		return nil
+	}, pID)
 	if err != nil {
 		return nil, "", status.Error(codes.Internal, "Failed to list Operations from database")
 	}
+	return ops, npt, nil
+}
+
+// GetIamPolicy returns the IAM policy of the project or note with the given resource name
+func (pg *pgSQLStore) GetIamPolicy(resource string) (*iampb.Policy, error) {
+	var data string
+	err := pg.DB.QueryRow(searchPolicy, resource).Scan(&data)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, status.Errorf(codes.NotFound, "Policy of %q does not Exist", resource)
+	case err != nil:
+		return nil, status.Error(codes.Internal, "Failed to query Policy from database")
+	}
+	var p iampb.Policy
+	if err := proto.UnmarshalText(data, &p); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to unmarshal Policy from database")
+	}
+	return &p, nil
+}
+
+// SetIamPolicy replaces the IAM policy of the project or note with the given resource name
+func (pg *pgSQLStore) SetIamPolicy(resource string, p *iampb.Policy, etag string) error {
+	tx, err := pg.DB.Begin()
+	if err != nil {
+		return status.Error(codes.Internal, "Failed to set Policy")
+	}
+	defer tx.Rollback()
+	// Lock the resource, which may not have a row to lock yet.
+	if _, err := tx.Exec(lockPolicy, resource); err != nil {
+		return status.Error(codes.Internal, "Failed to set Policy")
+	}
+	existing := &iampb.Policy{}
+	var data string
+	switch err := tx.QueryRow(searchPolicy, resource).Scan(&data); {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return status.Error(codes.Internal, "Failed to query Policy from database")
+	default:
+		if err := proto.UnmarshalText(data, existing); err != nil {
+			return status.Error(codes.Internal, "Failed to unmarshal Policy from database")
+		}
+	}
+	if err := checkETag(existing, etag); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(upsertPolicy, resource, proto.MarshalTextString(p)); err != nil {
+		log.Println("Failed to set Policy in database", err)
+		return status.Error(codes.Internal, "Failed to set Policy")
+	}
+	if err := tx.Commit(); err != nil {
+		return status.Error(codes.Internal, "Failed to set Policy")
+	}
+	return nil
+}
+
+// DeleteIamPolicy deletes the IAM policy of the project or note with the given resource name
+func (pg *pgSQLStore) DeleteIamPolicy(resource string) error {
+	result, err := pg.DB.Exec(deletePolicy, resource)
+	if err != nil {
+		return status.Error(codes.Internal, "Failed to delete Policy from database")
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return status.Error(codes.Internal, "Failed to delete Policy from database")
+	}
+	if count == 0 {
+		return status.Errorf(codes.NotFound, "Policy of %q does not Exist", resource)
+	}
+	return nil
+}
+
+// AppendAuditEvent appends the specified event to the audit log of project pID
+func (pg *pgSQLStore) AppendAuditEvent(pID string, e *auditpb.AuditEvent) error {
+	t, err := ptypes.Timestamp(e.EventTime)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid audit event time: %v", err)
+	}
+	if _, err := pg.DB.Exec(insertAuditEvent, pID, e.Resource, t, proto.MarshalTextString(e)); err != nil {
+		log.Println("Failed to insert audit event in database", err)
+		return status.Error(codes.Internal, "Failed to append audit event")
+	}
+	return nil
+}
+
+// ListAuditEvents returns up to pageSize number of the events of the audit log of project pID
+// matching resource, start and end beginning at pageToken
+func (pg *pgSQLStore) ListAuditEvents(pID, resource string, start, end time.Time, pageSize int, pageToken string) ([]*auditpb.AuditEvent, string, error) {
+	var events []*auditpb.AuditEvent
+	npt, err := pg.listPage(listAuditEvents, pageSize, pageToken, func(data string) error {
+		var e auditpb.AuditEvent
+		if err := proto.UnmarshalText(data, &e); err != nil {
+			return err
+		}
+		events = append(events, &e)
+		return nil
+	}, pID, resource, nullTime(start), nullTime(end))
+	if err != nil {
+		return nil, "", status.Error(codes.Internal, "Failed to list audit events from database")
+	}
+	return events, npt, nil
+}
+
+// ListNoteRevisions returns up to pageSize number of the previous revisions of the note with pID
+// and nID, newest first, beginning at pageToken. They are recorded by a trigger on the notes table.
+func (pg *pgSQLStore) ListNoteRevisions(pID, nID string, pageSize int, pageToken string) ([]*revpb.NoteRevision, string, error) {
+	var revs []*revpb.NoteRevision
+	npt, err := pg.listRevisions(listNoteRevisions, pID, nID, pageSize, pageToken, func(data string, replaceTime *tpb.Timestamp) error {
+		var n pb.Note
+		if err := proto.UnmarshalText(data, &n); err != nil {
+			return err
+		}
+		revs = append(revs, &revpb.NoteRevision{Note: &n, ReplaceTime: replaceTime})
+		return nil
+	})
+	if err != nil {
+		return nil, "", status.Error(codes.Internal, "Failed to list Note revisions from database")
+	}
+	return revs, npt, nil
+}
+
+// ListOccurrenceRevisions returns up to pageSize number of the previous revisions of the occurrence
+// with pID and oID, newest first, beginning at pageToken. They are recorded by a trigger on the
+// occurrences table.
+func (pg *pgSQLStore) ListOccurrenceRevisions(pID, oID string, pageSize int, pageToken string) ([]*revpb.OccurrenceRevision, string, error) {
+	var revs []*revpb.OccurrenceRevision
+	npt, err := pg.listRevisions(listOccurrenceRevisions, pID, oID, pageSize, pageToken, func(data string, replaceTime *tpb.Timestamp) error {
+		var o pb.Occurrence
+		if err := proto.UnmarshalText(data, &o); err != nil {
+			return err
+		}
+		revs = append(revs, &revpb.OccurrenceRevision{Occurrence: &o, ReplaceTime: replaceTime})
+		return nil
+	})
+	if err != nil {
+		return nil, "", status.Error(codes.Internal, "Failed to list Occurrence revisions from database")
+	}
+	return revs, npt, nil
+}
+
+// listPage runs query, which lists the id and data of rows in the order of their ids and takes the
+// id to list after and the number of rows to list as its last two arguments, following args. It
+// calls fn with the data of up to pageSize rows after the id in pageToken and returns the next page
+// token, which is empty after the last page.
+func (pg *pgSQLStore) listPage(query string, pageSize int, pageToken string, fn func(data string) error, args ...interface{}) (string, error) {
+	after := decryptInt64(pageToken, pg.paginationKey, 0)
+	// One more row than requested tells whether there is a next page.
+	rows, err := pg.DB.Query(query, append(args, after, pageSize+1)...)
+	if err != nil {
+		return "", err
+	}
 	defer rows.Close()
-	var ops []*opspb.Operation
-	var lastId int64
+	var (
+		count  int
+		lastId int64
+	)
 	for rows.Next() {
+		if count == pageSize {
+			return encryptInt64(lastId, pg.paginationKey)
+		}
 		var data string
-		err := rows.Scan(&lastId, &data)
-		if err != nil {
-			return nil, "", status.Error(codes.Internal, "Failed to scan Operations row")
+		if err := rows.Scan(&lastId, &data); err != nil {
+			return "", err
 		}
-		var op opspb.Operation
-		proto.UnmarshalText(data, &op)
+		if err := fn(data); err != nil {
+			return "", err
+		}
+		count++
+	}
+	return "", rows.Err()
+}
+
+// listRevisions runs query, listing the revisions of the entity with pID and ID id newest first,
+// and calls fn with up to pageSize number of them beginning at pageToken. It returns the next page
+// token.
+func (pg *pgSQLStore) listRevisions(query, pID, id string, pageSize int, pageToken string, fn func(data string, replaceTime *tpb.Timestamp) error) (string, error) {
+	before := decryptInt64(pageToken, pg.paginationKey, 0)
+	// One more revision than requested tells whether there is a next page.
+	rows, err := pg.DB.Query(query, pID, id, before, pageSize+1)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var (
+		count  int
+		lastId int64
+	)
+	for rows.Next() {
+		if count == pageSize {
+			return encryptInt64(lastId, pg.paginationKey)
+		}
+		var (
+			data        string
+			replaceTime time.Time
+		)
+		if err := rows.Scan(&lastId, &data, &replaceTime); err != nil {
+			return "", err
+		}
+		ts, err := ptypes.TimestampProto(replaceTime)
 		if err != nil {
-			return nil, "", status.Error(codes.Internal, "Failed to unmarshal Operation from database")
+			return "", err
 		}
-		ops = append(ops, &op)
+		if err := fn(data, ts); err != nil {
+			return "", err
+		}
+		count++
 	}
-	if count, err := pg.count(operationsCnt, pID); err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to count Operations from database")
-	} else if count == lastId {
-		return ops, "", nil
+	return "", rows.Err()
+}
+
+// nullTime returns t as a query argument, NULL if t is zero.
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
 	}
-	encryptedPage, err := encryptInt64(lastId, pg.paginationKey)
+	return t
+}
+
+// execIfMatch executes stmt, whose first two arguments identify a single row. If etag is non-empty,
+// the row is first locked with lock and its data, decoded into like, has to match etag. Rows that
+// don't exist are left for the caller to detect through the returned result.
+func (pg *pgSQLStore) execIfMatch(lock string, like proto.Message, etag, stmt string, args ...interface{}) (sql.Result, error) {
+	if etag == "" {
+		return pg.DB.Exec(stmt, args...)
+	}
+	tx, err := pg.DB.Begin()
 	if err != nil {
-		return nil, "", status.Error(codes.Internal, "Failed to paginate projects")
+		return nil, err
 	}
-	return ops, encryptedPage, nil
+	defer tx.Rollback()
+	var data string
+	switch err := tx.QueryRow(lock, args[0], args[1]).Scan(&data); {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return nil, err
+	default:
+		if err := proto.UnmarshalText(data, like); err != nil {
+			return nil, err
+		}
+		if err := checkETag(like, etag); err != nil {
+			return nil, err
+		}
+	}
+	result, err := tx.Exec(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+// WatchOccurrences calls fn with every change to the occurrences of project pID after cursor.
+// Changes are recorded by a trigger on the occurrences table and announced with NOTIFY, so changes
+// made by other Grafeas instances sharing the database are seen too.
+func (pg *pgSQLStore) WatchOccurrences(ctx context.Context, pID, cursor string, fn func(*watchpb.OccurrenceEvent) error) error {
+	if err := pg.listen(); err != nil {
+		log.Println("Failed to listen for Occurrence changes", err)
+		return status.Error(codes.Internal, "Failed to listen for Occurrence changes")
+	}
+	wake, stop := pg.notifier.subscribe()
+	defer stop()
+	latest := func() (int64, error) {
+		_, seq, err := pg.occurrenceChanges(ctx, pID, -1)
+		if err != nil {
+			log.Println("Failed to query Occurrence changes from database", err)
+			return 0, status.Error(codes.Internal, "Failed to query Occurrence changes from database")
+		}
+		return seq, nil
+	}
+	changesAfter := func(seq int64) ([]*watchpb.OccurrenceEvent, int64, error) {
+		events, newest, err := pg.occurrenceChanges(ctx, pID, seq)
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		if err != nil {
+			log.Println("Failed to query Occurrence changes from database", err)
+			return nil, 0, status.Error(codes.Internal, "Failed to query Occurrence changes from database")
+		}
+		return events, newest, nil
+	}
+	return watchChanges(ctx, cursor, wake, latest, changesAfter, fn)
 }
 
-// count returns the total number of entries for the specified query (assuming SELECT(*) is used)
-func (pg *pgSQLStore) count(query string, args ...interface{}) (int64, error) {
-	row := pg.DB.QueryRow(query, args...)
-	var count int64
-	err := row.Scan(&count)
+// occurrenceChanges returns the changes to the occurrences of project pID after seq, and the id of
+// the newest change, or only the latter if seq is negative. The changes of a project are only
+// committed while holding its lock, in the order of their ids, so while this holds the lock too,
+// every change of the project up to the newest id is committed and no other one can be.
+func (pg *pgSQLStore) occurrenceChanges(ctx context.Context, pID string, seq int64) ([]*watchpb.OccurrenceEvent, int64, error) {
+	tx, err := pg.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
-		return 0, err
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(lockOccurrenceChanges, pID); err != nil {
+		return nil, 0, err
 	}
-	return count, err
+	var newest int64
+	if err := tx.QueryRow(latestOccurrenceChange).Scan(&newest); err != nil {
+		return nil, 0, err
+	}
+	if seq < 0 {
+		return nil, newest, nil
+	}
+	rows, err := tx.Query(listOccurrenceChanges, pID, seq, newest)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var events []*watchpb.OccurrenceEvent
+	for rows.Next() {
+		var (
+			id               int64
+			changeType, data string
+			changeTime       time.Time
+		)
+		if err := rows.Scan(&id, &changeType, &data, &changeTime); err != nil {
+			return nil, 0, err
+		}
+		var o pb.Occurrence
+		if err := proto.UnmarshalText(data, &o); err != nil {
+			return nil, 0, err
+		}
+		eventTime, err := ptypes.TimestampProto(changeTime)
+		if err != nil {
+			return nil, 0, err
+		}
+		events = append(events, &watchpb.OccurrenceEvent{
+			Type:       changeTypes[changeType],
+			Occurrence: &o,
+			EventTime:  eventTime,
+			Cursor:     formatCursor(id),
+		})
+	}
+	return events, newest, rows.Err()
+}
+
+// listen starts listening for the notifications of the occurrence change trigger if it isn't
+// already.
+func (pg *pgSQLStore) listen() error {
+	pg.listenerMu.Lock()
+	defer pg.listenerMu.Unlock()
+	if pg.listener != nil {
+		return nil
+	}
+	select {
+	case <-pg.closed:
+		return fmt.Errorf("store is closed")
+	default:
+	}
+	l := pq.NewListener(pg.source, time.Second, time.Minute, nil)
+	if err := l.Listen("occurrence_changes"); err != nil {
+		l.Close()
+		return err
+	}
+	pg.listener = l
+	go func() {
+		// The listener also sends nil after reconnecting, when notifications may have been missed.
+		for range l.Notify {
+			pg.notifier.notify()
+		}
+	}()
+	return nil
 }
 
 // Encrypt int64 using provided key
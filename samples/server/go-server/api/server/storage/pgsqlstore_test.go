@@ -80,3 +80,21 @@ func TestPgSQLStoreWithUserAsEnv(t *testing.T) {
 
 	doTestStorager(t, createPgSQLStore)
 }
+
+func TestPgSQLStoreCloseTwice(t *testing.T) {
+	// Opening doesn't connect, so this needs no database.
+	db, err := sql.Open("postgres", "host=127.0.0.1 dbname=test_db sslmode=disable")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	pg := &pgSQLStore{DB: db, closed: make(chan struct{})}
+	if err := pg.Close(); err != nil {
+		t.Fatalf("Close got %v, want success", err)
+	}
+	if err := pg.Close(); err != nil {
+		t.Errorf("second Close got %v, want success", err)
+	}
+	if err := pg.listen(); err == nil {
+		t.Errorf("listen after Close got success, want error")
+	}
+}
@@ -33,52 +33,211 @@ const (
 			occurrence_name TEXT NOT NULL,
 			data TEXT,
 			note_id int REFERENCES notes NOT NULL,
+			resource_uri TEXT,
 			UNIQUE (project_name, occurrence_name)
 		);
+		ALTER TABLE occurrences ADD COLUMN IF NOT EXISTS resource_uri TEXT;
+		CREATE INDEX IF NOT EXISTS occurrences_resource_note_idx ON occurrences (project_name, resource_uri, note_id);
+		CREATE TABLE IF NOT EXISTS occurrence_changes (
+			id BIGSERIAL PRIMARY KEY,
+			project_name TEXT NOT NULL,
+			change_type TEXT NOT NULL,
+			data TEXT,
+			change_time TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		CREATE INDEX IF NOT EXISTS occurrence_changes_project_idx ON occurrence_changes (project_name, id);
+		CREATE OR REPLACE FUNCTION record_occurrence_change() RETURNS trigger AS $$
+		DECLARE
+			changed occurrences%ROWTYPE;
+		BEGIN
+			IF TG_OP = 'DELETE' THEN
+				changed := OLD;
+			ELSE
+				changed := NEW;
+			END IF;
+			-- Held until commit, so that the changes of a project are committed in the order of
+			-- their ids and its watchers moving their cursor to its newest id skip none.
+			PERFORM pg_advisory_xact_lock(hashtext('occurrence_changes'), hashtext(changed.project_name));
+			INSERT INTO occurrence_changes(project_name, change_type, data)
+				VALUES (changed.project_name, TG_OP, changed.data);
+			PERFORM pg_notify('occurrence_changes', changed.project_name);
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+		DROP TRIGGER IF EXISTS occurrence_changes_trigger ON occurrences;
+		CREATE TRIGGER occurrence_changes_trigger AFTER INSERT OR UPDATE OR DELETE ON occurrences
+			FOR EACH ROW EXECUTE PROCEDURE record_occurrence_change();
 		CREATE TABLE IF NOT EXISTS operations (
 			id SERIAL PRIMARY KEY,
 			project_name TEXT NOT NULL,
 			operation_name TEXT NOT NULL,
 			data TEXT,
 			UNIQUE (project_name, operation_name)
-		);`
+		);
+		CREATE TABLE IF NOT EXISTS policies (
+			resource TEXT PRIMARY KEY,
+			data TEXT
+		);
+		CREATE TABLE IF NOT EXISTS audit_events (
+			id BIGSERIAL PRIMARY KEY,
+			project_name TEXT NOT NULL,
+			resource TEXT NOT NULL,
+			event_time TIMESTAMPTZ NOT NULL,
+			data TEXT
+		);
+		CREATE INDEX IF NOT EXISTS audit_events_project_idx ON audit_events (project_name, id);
+		CREATE TABLE IF NOT EXISTS note_revisions (
+			id BIGSERIAL PRIMARY KEY,
+			project_name TEXT NOT NULL,
+			note_name TEXT NOT NULL,
+			data TEXT,
+			replace_time TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		CREATE INDEX IF NOT EXISTS note_revisions_note_idx ON note_revisions (project_name, note_name, id);
+		CREATE OR REPLACE FUNCTION record_note_revision() RETURNS trigger AS $$
+		BEGIN
+			INSERT INTO note_revisions(project_name, note_name, data)
+				VALUES (OLD.project_name, OLD.note_name, OLD.data);
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+		DROP TRIGGER IF EXISTS note_revisions_trigger ON notes;
+		CREATE TRIGGER note_revisions_trigger AFTER UPDATE ON notes
+			FOR EACH ROW EXECUTE PROCEDURE record_note_revision();
+		CREATE TABLE IF NOT EXISTS deleted_projects (
+			id INT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			delete_time TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		CREATE TABLE IF NOT EXISTS deleted_notes (
+			id INT PRIMARY KEY,
+			project_name TEXT NOT NULL,
+			note_name TEXT NOT NULL,
+			data TEXT,
+			delete_time TIMESTAMPTZ NOT NULL DEFAULT now(),
+			UNIQUE (project_name, note_name)
+		);
+		CREATE TABLE IF NOT EXISTS occurrence_revisions (
+			id BIGSERIAL PRIMARY KEY,
+			project_name TEXT NOT NULL,
+			occurrence_name TEXT NOT NULL,
+			data TEXT,
+			replace_time TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		CREATE INDEX IF NOT EXISTS occurrence_revisions_occurrence_idx ON occurrence_revisions (project_name, occurrence_name, id);
+		CREATE OR REPLACE FUNCTION record_occurrence_revision() RETURNS trigger AS $$
+		BEGIN
+			IF TG_OP = 'DELETE' THEN
+				DELETE FROM occurrence_revisions
+					WHERE project_name = OLD.project_name AND occurrence_name = OLD.occurrence_name;
+			ELSE
+				INSERT INTO occurrence_revisions(project_name, occurrence_name, data)
+					VALUES (OLD.project_name, OLD.occurrence_name, OLD.data);
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+		DROP TRIGGER IF EXISTS occurrence_revisions_trigger ON occurrences;
+		CREATE TRIGGER occurrence_revisions_trigger AFTER UPDATE OR DELETE ON occurrences
+			FOR EACH ROW EXECUTE PROCEDURE record_occurrence_revision();`
 
-	insertProject = `INSERT INTO projects(name) VALUES ($1)`
-	projectExists = `SELECT EXISTS (SELECT 1 FROM projects WHERE name = $1)`
-	deleteProject = `DELETE FROM projects WHERE name = $1`
-	listProjects  = `SELECT id, name FROM projects WHERE id > $1 LIMIT $2`
-	projectCount  = `SELECT COUNT(*) FROM projects`
+	insertProject  = `INSERT INTO projects(name) VALUES ($1)`
+	projectDeleted = `SELECT EXISTS (SELECT 1 FROM deleted_projects WHERE name = $1)`
+	projectExists  = `SELECT EXISTS (SELECT 1 FROM projects WHERE name = $1)`
+	deleteProject  = `WITH deleted AS (DELETE FROM projects WHERE name = $1 RETURNING id, name)
+	                  INSERT INTO deleted_projects(id, name) SELECT id, name FROM deleted`
+	undeleteProject = `WITH restored AS (DELETE FROM deleted_projects WHERE name = $1 AND delete_time >= $2
+	                                         RETURNING id, name)
+	                   INSERT INTO projects(id, name) SELECT id, name FROM restored`
+	purgeProjects = `DELETE FROM deleted_projects WHERE delete_time < $1 RETURNING name`
+	listProjects  = `SELECT id, name FROM projects WHERE id > $1 ORDER BY id LIMIT $2`
 
-	insertOccurrence = `INSERT INTO occurrences(project_name, occurrence_name, note_id, data)
-                      VALUES ($1, $2, (SELECT id FROM notes WHERE project_name = $3 AND note_name = $4), $5)`
+	insertOccurrence = `INSERT INTO occurrences(project_name, occurrence_name, note_id, data, resource_uri)
+                      VALUES ($1, $2, (SELECT id FROM notes WHERE project_name = $3 AND note_name = $4), $5, $6)`
 	searchOccurrence = `SELECT data FROM occurrences WHERE project_name = $1 AND occurrence_name = $2`
-	updateOccurrence = `UPDATE occurrences SET data = $3 WHERE project_name = $1 AND occurrence_name = $2`
+	lockOccurrence   = `SELECT data FROM occurrences WHERE project_name = $1 AND occurrence_name = $2 FOR UPDATE`
+	updateOccurrence = `UPDATE occurrences SET data = $3, resource_uri = $4 WHERE project_name = $1 AND occurrence_name = $2`
 	deleteOccurrence = `DELETE FROM occurrences WHERE project_name = $1 AND occurrence_name = $2`
-	listOccurrences  = `SELECT id, data FROM occurrences WHERE project_name = $1 AND id > $2 LIMIT $3`
-	occurrenceCount  = `SELECT COUNT(*) FROM occurrences WHERE project_name = $1`
+	listOccurrences  = `SELECT id, data FROM occurrences WHERE project_name = $1 AND id > $2 ORDER BY id LIMIT $3`
+
+	lockOccurrenceChanges  = `SELECT pg_advisory_xact_lock_shared(hashtext('occurrence_changes'), hashtext($1))`
+	latestOccurrenceChange = `SELECT COALESCE(MAX(id), 0) FROM occurrence_changes`
+	listOccurrenceChanges  = `SELECT id, change_type, data, change_time FROM occurrence_changes
+	                            WHERE project_name = $1 AND id > $2 AND id <= $3 ORDER BY id`
+	trimOccurrenceChanges = `DELETE FROM occurrence_changes
+	                            WHERE id <= (SELECT COALESCE(MAX(id), 0) FROM occurrence_changes) - $1`
 
-	insertNote          = `INSERT INTO notes(project_name, note_name, data) VALUES ($1, $2, $3)`
-	searchNote          = `SELECT data FROM notes WHERE project_name = $1 AND note_name = $2`
-	updateNote          = `UPDATE notes SET data = $3 WHERE project_name = $1 AND note_name = $2`
-	deleteNote          = `DELETE FROM notes WHERE project_name = $1 AND note_name = $2`
-	listNotes           = `SELECT id, data FROM notes WHERE project_name = $1 AND id > $2 LIMIT $3`
-	noteCount           = `SELECT COUNT(*) FROM notes WHERE project_name = $1`
+	lockResourceNote         = `SELECT pg_advisory_xact_lock(hashtext($1))`
+	searchResourceOccurrence = `SELECT o.occurrence_name, o.data FROM occurrences as o, notes as n
+	                             WHERE n.id = o.note_id
+	                               AND o.project_name = $1
+	                               AND o.resource_uri = $2
+	                               AND n.project_name = $3
+	                               AND n.note_name = $4
+	                             ORDER BY o.occurrence_name
+	                             LIMIT 1
+	                             FOR UPDATE OF o`
+
+	insertNote = `INSERT INTO notes(project_name, note_name, data) VALUES ($1, $2, $3)`
+	searchNote = `SELECT data FROM notes WHERE project_name = $1 AND note_name = $2`
+	lockNote   = `SELECT data FROM notes WHERE project_name = $1 AND note_name = $2 FOR UPDATE`
+	updateNote = `UPDATE notes SET data = $3 WHERE project_name = $1 AND note_name = $2`
+	deleteNote = `WITH deleted AS (DELETE FROM notes WHERE project_name = $1 AND note_name = $2
+	                                         RETURNING id, project_name, note_name, data)
+	                       INSERT INTO deleted_notes(id, project_name, note_name, data)
+	                         SELECT id, project_name, note_name, data FROM deleted`
+	noteDeleted  = `SELECT EXISTS (SELECT 1 FROM deleted_notes WHERE project_name = $1 AND note_name = $2)`
+	undeleteNote = `WITH restored AS (DELETE FROM deleted_notes
+	                                          WHERE project_name = $1 AND note_name = $2 AND delete_time >= $3
+	                                          RETURNING id, project_name, note_name, data)
+	                       INSERT INTO notes(id, project_name, note_name, data)
+	                         SELECT id, project_name, note_name, data FROM restored`
+	purgeNotes = `WITH purged AS (DELETE FROM deleted_notes WHERE delete_time < $1 RETURNING project_name, note_name),
+	                            revisions AS (DELETE FROM note_revisions AS r USING purged AS p
+	                                           WHERE r.project_name = p.project_name AND r.note_name = p.note_name)
+	                       SELECT project_name, note_name FROM purged`
+	listNotes           = `SELECT id, data FROM notes WHERE project_name = $1 AND id > $2 ORDER BY id LIMIT $3`
 	listNoteOccurrences = `SELECT o.id, o.data FROM occurrences as o, notes as n
 	                         WHERE n.id = o.note_id
 	                           AND n.project_name = $1
 	                           AND n.note_name = $2
 	                           AND o.id > $3
-	                           LIMIT $4`
-
-	noteOccurrencesCount = `SELECT COUNT(*) FROM occurrences as o, notes as n
-	                         WHERE n.id = o.note_id
-	                           AND n.project_name = $1
-	                           AND n.note_name = $2`
+	                         ORDER BY o.id
+	                         LIMIT $4`
 
 	insertOperation = `INSERT INTO operations(project_name, operation_name, data) VALUES ($1, $2, $3)`
 	searchOperation = `SELECT data FROM operations WHERE project_name = $1 AND operation_name = $2`
+	lockOperation   = `SELECT data FROM operations WHERE project_name = $1 AND operation_name = $2 FOR UPDATE`
 	deleteOperation = `DELETE FROM operations WHERE project_name = $1 AND operation_name = $2`
 	updateOperation = `UPDATE operations SET data = $3 WHERE project_name = $1 AND operation_name = $2`
-	listOperations  = `SELECT id, data FROM operations WHERE project_name = $1 AND id > $2 LIMIT $3`
-	operationsCnt   = `SELECT COUNT(*) FROM operations WHERE project_name = $1`
+	listOperations  = `SELECT id, data FROM operations WHERE project_name = $1 AND id > $2 ORDER BY id LIMIT $3`
+
+	lockPolicy   = `SELECT pg_advisory_xact_lock(hashtext('policies:' || $1))`
+	searchPolicy = `SELECT data FROM policies WHERE resource = $1`
+	upsertPolicy = `INSERT INTO policies(resource, data) VALUES ($1, $2)
+	                  ON CONFLICT (resource) DO UPDATE SET data = EXCLUDED.data`
+	deletePolicy = `DELETE FROM policies WHERE resource = $1`
+
+	insertAuditEvent = `INSERT INTO audit_events(project_name, resource, event_time, data) VALUES ($1, $2, $3, $4)`
+	listAuditEvents  = `SELECT id, data FROM audit_events
+	                      WHERE project_name = $1
+	                        AND ($2 = '' OR resource = $2)
+	                        AND ($3::timestamptz IS NULL OR event_time >= $3)
+	                        AND ($4::timestamptz IS NULL OR event_time < $4)
+	                        AND id > $5
+	                      ORDER BY id
+	                      LIMIT $6`
+
+	listNoteRevisions = `SELECT id, data, replace_time FROM note_revisions
+	                       WHERE project_name = $1
+	                         AND note_name = $2
+	                         AND ($3::bigint = 0 OR id < $3)
+	                       ORDER BY id DESC
+	                       LIMIT $4`
+	listOccurrenceRevisions = `SELECT id, data, replace_time FROM occurrence_revisions
+	                             WHERE project_name = $1
+	                               AND occurrence_name = $2
+	                               AND ($3::bigint = 0 OR id < $3)
+	                             ORDER BY id DESC
+	                             LIMIT $4`
 )
@@ -21,12 +21,18 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grafeas/grafeas/go/etag"
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
 	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/testing"
 	"github.com/grafeas/grafeas/server-go"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
 	opspb "google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -146,13 +152,13 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 		if err != nil {
 			t.Fatalf("Error parsing occurrence %v", err)
 		}
-		if err := s.DeleteOccurrence(pID, oID); err == nil {
+		if err := s.DeleteOccurrence(pID, oID, ""); err == nil {
 			t.Error("Deleting nonexistant occurrence got success, want error")
 		}
 		if err := s.CreateOccurrence(o); err != nil {
 			t.Fatalf("CreateOccurrence got %v want success", err)
 		}
-		if err := s.DeleteOccurrence(pID, oID); err != nil {
+		if err := s.DeleteOccurrence(pID, oID, ""); err != nil {
 			t.Errorf("DeleteOccurrence got %v, want success ", err)
 		}
 	})
@@ -171,7 +177,7 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 		if err != nil {
 			t.Fatalf("Error parsing projectID and occurrenceID %v", err)
 		}
-		if err := s.UpdateOccurrence(pID, oID, o); err == nil {
+		if err := s.UpdateOccurrence(pID, oID, o, ""); err == nil {
 			t.Fatal("UpdateOccurrence got success want error")
 		}
 		if err := s.CreateOccurrence(o); err != nil {
@@ -185,7 +191,7 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 
 		o2 := o
 		o2.GetVulnerability().CvssScore = 1.0
-		if err := s.UpdateOccurrence(pID, oID, o2); err != nil {
+		if err := s.UpdateOccurrence(pID, oID, o2, ""); err != nil {
 			t.Fatalf("UpdateOccurrence got %v want success", err)
 		}
 
@@ -206,18 +212,99 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 		if err != nil {
 			t.Fatalf("Error parsing note %v", err)
 		}
-		if err := s.DeleteNote(pID, oID); err == nil {
+		if err := s.DeleteNote(pID, oID, ""); err == nil {
 			t.Error("Deleting nonexistant note got success, want error")
 		}
 		if err := s.CreateNote(n); err != nil {
 			t.Fatalf("CreateNote got %v want success", err)
 		}
 
-		if err := s.DeleteNote(pID, oID); err != nil {
+		if err := s.DeleteNote(pID, oID, ""); err != nil {
 			t.Errorf("DeleteNote got %v, want success ", err)
 		}
 	})
 
+	t.Run("Undelete", func(t *testing.T) {
+		s, cleanUp := createStore(t)
+		defer cleanUp()
+		pID := "myproject"
+		n := testutil.Note(pID)
+		_, nID, err := name.ParseNote(n.Name)
+		if err != nil {
+			t.Fatalf("Error parsing note %v", err)
+		}
+		if err := s.CreateProject(pID); err != nil {
+			t.Fatalf("CreateProject got %v want success", err)
+		}
+		if err := s.CreateNote(n); err != nil {
+			t.Fatalf("CreateNote got %v want success", err)
+		}
+		if err := s.UndeleteNote(pID, nID, time.Time{}); status.Code(err) != codes.NotFound {
+			t.Errorf("UndeleteNote of a note that isn't deleted got %v, want %v", err, codes.NotFound)
+		}
+		if err := s.DeleteProject(pID); err != nil {
+			t.Fatalf("DeleteProject got %v want success", err)
+		}
+		if err := s.DeleteNote(pID, nID, ""); err != nil {
+			t.Fatalf("DeleteNote got %v want success", err)
+		}
+
+		// Deleted entities are hidden and can't be created again until they are purged.
+		if _, err := s.GetProject(pID); status.Code(err) != codes.NotFound {
+			t.Errorf("GetProject of a deleted project got %v, want %v", err, codes.NotFound)
+		}
+		if _, err := s.GetNote(pID, nID); status.Code(err) != codes.NotFound {
+			t.Errorf("GetNote of a deleted note got %v, want %v", err, codes.NotFound)
+		}
+		if ns, _, err := s.ListNotes(pID, "", 10, ""); err != nil || len(ns) != 0 {
+			t.Errorf("ListNotes got %v, %v, want no notes", ns, err)
+		}
+		if err := s.CreateProject(pID); status.Code(err) != codes.AlreadyExists {
+			t.Errorf("CreateProject of a deleted project got %v, want %v", err, codes.AlreadyExists)
+		}
+		if err := s.CreateNote(n); status.Code(err) != codes.AlreadyExists {
+			t.Errorf("CreateNote of a deleted note got %v, want %v", err, codes.AlreadyExists)
+		}
+
+		// Only entities deleted at or after deletedAfter are restored.
+		if err := s.UndeleteNote(pID, nID, time.Now().Add(time.Hour)); status.Code(err) != codes.NotFound {
+			t.Errorf("UndeleteNote of an expired note got %v, want %v", err, codes.NotFound)
+		}
+		if err := s.UndeleteNote(pID, nID, time.Now().Add(-time.Hour)); err != nil {
+			t.Fatalf("UndeleteNote got %v want success", err)
+		}
+		if got, err := s.GetNote(pID, nID); err != nil || !proto.Equal(got, n) {
+			t.Errorf("GetNote of an undeleted note got %v, %v, want %v", got, err, n)
+		}
+		if err := s.UndeleteProject(pID, time.Now().Add(-time.Hour)); err != nil {
+			t.Fatalf("UndeleteProject got %v want success", err)
+		}
+		if _, err := s.GetProject(pID); err != nil {
+			t.Errorf("GetProject of an undeleted project got %v, want success", err)
+		}
+
+		// Purging removes entities deleted before the given time for good.
+		if err := s.DeleteProject(pID); err != nil {
+			t.Fatalf("DeleteProject got %v want success", err)
+		}
+		if err := s.DeleteNote(pID, nID, ""); err != nil {
+			t.Fatalf("DeleteNote got %v want success", err)
+		}
+		if purged, err := s.PurgeDeleted(time.Now().Add(-time.Hour)); err != nil || len(purged) != 0 {
+			t.Errorf("PurgeDeleted got %v, %v, want nothing purged", purged, err)
+		}
+		purged, err := s.PurgeDeleted(time.Now().Add(time.Second))
+		if want := []string{name.FormatProject(pID), n.Name}; err != nil || !reflect.DeepEqual(purged, want) {
+			t.Errorf("PurgeDeleted got %v, %v, want %v", purged, err, want)
+		}
+		if err := s.UndeleteProject(pID, time.Time{}); status.Code(err) != codes.NotFound {
+			t.Errorf("UndeleteProject of a purged project got %v, want %v", err, codes.NotFound)
+		}
+		if err := s.CreateNote(n); err != nil {
+			t.Errorf("CreateNote of a purged note got %v, want success", err)
+		}
+	})
+
 	t.Run("UpdateNote", func(t *testing.T) {
 		s, cleanUp := createStore(t)
 		defer cleanUp()
@@ -228,7 +315,7 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 		if err != nil {
 			t.Fatalf("Error parsing projectID and noteID %v", err)
 		}
-		if err := s.UpdateNote(pID, nID, n); err == nil {
+		if err := s.UpdateNote(pID, nID, n, ""); err == nil {
 			t.Fatal("UpdateNote got success want error")
 		}
 		if err := s.CreateNote(n); err != nil {
@@ -242,7 +329,7 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 
 		n2 := n
 		n2.GetVulnerability().CvssScore = 1.0
-		if err := s.UpdateNote(pID, nID, n2); err != nil {
+		if err := s.UpdateNote(pID, nID, n2, ""); err != nil {
 			t.Fatalf("UpdateNote got %v want success", err)
 		}
 
@@ -253,6 +340,356 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 		}
 	})
 
+	t.Run("UpdateNoteETag", func(t *testing.T) {
+		s, cleanUp := createStore(t)
+		defer cleanUp()
+		n := testutil.Note("vulnerability-scanner-a")
+		pID, nID, err := name.ParseNote(n.Name)
+		if err != nil {
+			t.Fatalf("Error parsing projectID and noteID %v", err)
+		}
+		if err := s.CreateNote(n); err != nil {
+			t.Fatalf("CreateNote got %v want success", err)
+		}
+		stored, err := s.GetNote(pID, nID)
+		if err != nil {
+			t.Fatalf("GetNote got %v, want success", err)
+		}
+		tag, err := etag.Compute(stored)
+		if err != nil {
+			t.Fatalf("Compute etag got %v, want success", err)
+		}
+
+		// The first update holds the current etag, the second one is based on a stale read.
+		for _, want := range []codes.Code{codes.OK, codes.Aborted} {
+			update := testutil.Note("vulnerability-scanner-a")
+			update.LongDescription = fmt.Sprintf("updated, want %v", want)
+			if err := s.UpdateNote(pID, nID, update, tag); status.Code(err) != want {
+				t.Errorf("UpdateNote with etag got %v, want %v", err, want)
+			}
+		}
+		if err := s.DeleteNote(pID, nID, tag); status.Code(err) != codes.Aborted {
+			t.Errorf("DeleteNote with stale etag got %v, want %v", err, codes.Aborted)
+		}
+		if _, err := s.GetNote(pID, nID); err != nil {
+			t.Errorf("GetNote after failed delete got %v, want success", err)
+		}
+	})
+
+	t.Run("UpdateOccurrenceETag", func(t *testing.T) {
+		s, cleanUp := createStore(t)
+		defer cleanUp()
+		n := testutil.Note("vulnerability-scanner-a")
+		if err := s.CreateNote(n); err != nil {
+			t.Fatalf("CreateNote got %v want success", err)
+		}
+		o := testutil.Occurrence("occurrence-project", n.Name)
+		pID, oID, err := name.ParseOccurrence(o.Name)
+		if err != nil {
+			t.Fatalf("Error parsing projectID and occurrenceID %v", err)
+		}
+		if err := s.CreateOccurrence(o); err != nil {
+			t.Fatalf("CreateOccurrence got %v want success", err)
+		}
+		stored, err := s.GetOccurrence(pID, oID)
+		if err != nil {
+			t.Fatalf("GetOccurrence got %v, want success", err)
+		}
+		tag, err := etag.Compute(stored)
+		if err != nil {
+			t.Fatalf("Compute etag got %v, want success", err)
+		}
+
+		for _, want := range []codes.Code{codes.OK, codes.Aborted} {
+			update := testutil.Occurrence("occurrence-project", n.Name)
+			update.Remediation = fmt.Sprintf("updated, want %v", want)
+			if err := s.UpdateOccurrence(pID, oID, update, tag); status.Code(err) != want {
+				t.Errorf("UpdateOccurrence with etag got %v, want %v", err, want)
+			}
+		}
+		if err := s.DeleteOccurrence(pID, oID, tag); status.Code(err) != codes.Aborted {
+			t.Errorf("DeleteOccurrence with stale etag got %v, want %v", err, codes.Aborted)
+		}
+
+		stored, err = s.GetOccurrence(pID, oID)
+		if err != nil {
+			t.Fatalf("GetOccurrence got %v, want success", err)
+		}
+		if tag, err = etag.Compute(stored); err != nil {
+			t.Fatalf("Compute etag got %v, want success", err)
+		}
+		if err := s.DeleteOccurrence(pID, oID, tag); err != nil {
+			t.Errorf("DeleteOccurrence with current etag got %v, want success", err)
+		}
+	})
+
+	t.Run("IamPolicy", func(t *testing.T) {
+		s, cleanUp := createStore(t)
+		defer cleanUp()
+		resource := "projects/goog-vulnz/notes/CVE-1"
+		if _, err := s.GetIamPolicy(resource); status.Code(err) != codes.NotFound {
+			t.Errorf("GetIamPolicy got %v, want %v", err, codes.NotFound)
+		}
+		// Resources without a policy have the etag of an empty one.
+		empty, err := etag.Compute(&iampb.Policy{})
+		if err != nil {
+			t.Fatalf("Compute etag got %v, want success", err)
+		}
+		p := &iampb.Policy{Bindings: []*iampb.Binding{{Role: "roles/attestor", Members: []string{"ci"}}}}
+		if err := s.SetIamPolicy(resource, p, empty); err != nil {
+			t.Fatalf("SetIamPolicy got %v, want success", err)
+		}
+		got, err := s.GetIamPolicy(resource)
+		if err != nil {
+			t.Fatalf("GetIamPolicy got %v, want success", err)
+		}
+		if !proto.Equal(got, p) {
+			t.Errorf("GetIamPolicy got %v, want %v", got, p)
+		}
+
+		// The etag of the empty policy is stale now.
+		if err := s.SetIamPolicy(resource, &iampb.Policy{}, empty); status.Code(err) != codes.Aborted {
+			t.Errorf("SetIamPolicy with stale etag got %v, want %v", err, codes.Aborted)
+		}
+		if err := s.SetIamPolicy(resource, &iampb.Policy{}, ""); err != nil {
+			t.Errorf("SetIamPolicy without etag got %v, want success", err)
+		}
+
+		if err := s.DeleteIamPolicy(resource); err != nil {
+			t.Errorf("DeleteIamPolicy got %v, want success", err)
+		}
+		if err := s.DeleteIamPolicy(resource); status.Code(err) != codes.NotFound {
+			t.Errorf("DeleteIamPolicy of deleted policy got %v, want %v", err, codes.NotFound)
+		}
+	})
+
+	t.Run("AuditEvents", func(t *testing.T) {
+		s, cleanUp := createStore(t)
+		defer cleanUp()
+		pID := "goog-vulnz"
+		start := time.Now().Truncate(time.Second)
+		var want []*auditpb.AuditEvent
+		for i, r := range []string{"projects/goog-vulnz/notes/CVE-1", "projects/goog-vulnz/notes/CVE-2", "projects/goog-vulnz/notes/CVE-1"} {
+			ts, err := ptypes.TimestampProto(start.Add(time.Duration(i) * time.Minute))
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := &auditpb.AuditEvent{
+				Name:        fmt.Sprintf("projects/%s/auditEvents/%d", pID, i),
+				Actor:       "alice",
+				Method:      "UpdateNote",
+				Resource:    r,
+				AfterDigest: strconv.Itoa(i),
+				EventTime:   ts,
+			}
+			if err := s.AppendAuditEvent(pID, e); err != nil {
+				t.Fatalf("AppendAuditEvent got %v, want success", err)
+			}
+			want = append(want, e)
+		}
+		if err := s.AppendAuditEvent("other", want[0]); err != nil {
+			t.Fatalf("AppendAuditEvent got %v, want success", err)
+		}
+
+		// Events are listed in order, a page at a time.
+		got, pageToken, err := s.ListAuditEvents(pID, "", time.Time{}, time.Time{}, 2, "")
+		if err != nil {
+			t.Fatalf("ListAuditEvents got %v, want success", err)
+		}
+		if pageToken == "" {
+			t.Error("ListAuditEvents got an empty page token, want one")
+		}
+		rest, pageToken, err := s.ListAuditEvents(pID, "", time.Time{}, time.Time{}, 2, pageToken)
+		if err != nil {
+			t.Fatalf("ListAuditEvents got %v, want success", err)
+		}
+		if pageToken != "" {
+			t.Errorf("ListAuditEvents got page token %q, want none", pageToken)
+		}
+		if got := append(got, rest...); !eventsEqual(got, want) {
+			t.Errorf("ListAuditEvents got %v, want %v", got, want)
+		}
+
+		tests := []struct {
+			desc       string
+			resource   string
+			start, end time.Time
+			want       []*auditpb.AuditEvent
+		}{
+			{"by resource", "projects/goog-vulnz/notes/CVE-1", time.Time{}, time.Time{}, []*auditpb.AuditEvent{want[0], want[2]}},
+			{"by start", "", start.Add(time.Minute), time.Time{}, want[1:]},
+			{"by end", "", time.Time{}, start.Add(time.Minute), want[:1]},
+			{"by resource and time", "projects/goog-vulnz/notes/CVE-1", start.Add(time.Minute), start.Add(time.Hour), want[2:]},
+		}
+		for _, tt := range tests {
+			got, _, err := s.ListAuditEvents(pID, tt.resource, tt.start, tt.end, 100, "")
+			if err != nil {
+				t.Errorf("%q: ListAuditEvents got %v, want success", tt.desc, err)
+				continue
+			}
+			if !eventsEqual(got, tt.want) {
+				t.Errorf("%q: ListAuditEvents got %v, want %v", tt.desc, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("UpsertOccurrence", func(t *testing.T) {
+		s, cleanUp := createStore(t)
+		defer cleanUp()
+		n := testutil.Note("vulnerability-scanner-a")
+		if err := s.CreateNote(n); err != nil {
+			t.Fatalf("CreateNote got %v want success", err)
+		}
+		o := testutil.Occurrence("occurrence-project", n.Name)
+		if err := s.CreateOccurrence(o); err != nil {
+			t.Fatalf("CreateOccurrence got %v want success", err)
+		}
+
+		update := testutil.Occurrence("occurrence-project", n.Name)
+		update.Name = "projects/occurrence-project/occurrences/135"
+		update.Remediation = "upgrade"
+		got, err := s.UpsertOccurrence(update)
+		if err != nil {
+			t.Fatalf("UpsertOccurrence got %v want success", err)
+		}
+		if got.Name != o.Name {
+			t.Errorf("UpsertOccurrence updated %q, want %q", got.Name, o.Name)
+		}
+		if got.UpdateTime == nil {
+			t.Error("UpsertOccurrence got no update time, want it bumped")
+		}
+		pID, oID, err := name.ParseOccurrence(o.Name)
+		if err != nil {
+			t.Fatalf("Error parsing projectID and occurrenceID %v", err)
+		}
+		stored, err := s.GetOccurrence(pID, oID)
+		if err != nil {
+			t.Fatalf("GetOccurrence got %v, want success", err)
+		}
+		if stored.Remediation != "upgrade" {
+			t.Errorf("GetOccurrence got remediation %q, want %q", stored.Remediation, "upgrade")
+		}
+
+		other := testutil.Occurrence("occurrence-project", n.Name)
+		other.Name = "projects/occurrence-project/occurrences/136"
+		other.Resource = &pb.Resource{Uri: "gcr.io/foo/baz"}
+		if got, err := s.UpsertOccurrence(other); err != nil {
+			t.Fatalf("UpsertOccurrence got %v want success", err)
+		} else if got.Name != other.Name {
+			t.Errorf("UpsertOccurrence of another resource got %q, want %q", got.Name, other.Name)
+		}
+		occs, _, err := s.ListOccurrences("occurrence-project", "", 100, "")
+		if err != nil {
+			t.Fatalf("ListOccurrences got %v want success", err)
+		}
+		if len(occs) != 2 {
+			t.Errorf("ListOccurrences got %d occurrences, want 2", len(occs))
+		}
+
+		// Deleted occurrences are no longer upserted into.
+		if err := s.DeleteOccurrence(pID, oID, ""); err != nil {
+			t.Fatalf("DeleteOccurrence got %v want success", err)
+		}
+		if got, err := s.UpsertOccurrence(update); err != nil {
+			t.Fatalf("UpsertOccurrence got %v want success", err)
+		} else if got.Name != update.Name {
+			t.Errorf("UpsertOccurrence after delete got %q, want %q", got.Name, update.Name)
+		}
+	})
+
+	t.Run("Revisions", func(t *testing.T) {
+		s, cleanUp := createStore(t)
+		defer cleanUp()
+		n := testutil.Note("vulnerability-scanner-a")
+		if err := s.CreateNote(n); err != nil {
+			t.Fatalf("CreateNote got %v want success", err)
+		}
+		nPID, nID, err := name.ParseNote(n.Name)
+		if err != nil {
+			t.Fatalf("Error parsing projectID and noteID %v", err)
+		}
+		for _, desc := range []string{"1", "2"} {
+			update := proto.Clone(n).(*pb.Note)
+			update.ShortDescription = desc
+			if err := s.UpdateNote(nPID, nID, update, ""); err != nil {
+				t.Fatalf("UpdateNote got %v want success", err)
+			}
+		}
+
+		// Revisions are listed newest first, a page at a time.
+		var descs []string
+		pageToken := ""
+		for pages := 0; pages == 0 || pageToken != ""; pages++ {
+			if pages > 2 {
+				t.Fatal("ListNoteRevisions got more than 2 pages")
+			}
+			var revs []*revpb.NoteRevision
+			revs, pageToken, err = s.ListNoteRevisions(nPID, nID, 1, pageToken)
+			if err != nil {
+				t.Fatalf("ListNoteRevisions got %v want success", err)
+			}
+			for _, r := range revs {
+				if r.ReplaceTime == nil {
+					t.Errorf("ListNoteRevisions got %v, want a replace time", r)
+				}
+				descs = append(descs, r.Note.ShortDescription)
+			}
+		}
+		if want := []string{"1", n.ShortDescription}; !reflect.DeepEqual(descs, want) {
+			t.Errorf("ListNoteRevisions got short descriptions %v, want %v", descs, want)
+		}
+
+		// Updates and upserts of occurrences record their previous revision.
+		o := testutil.Occurrence("occurrence-project", n.Name)
+		if err := s.CreateOccurrence(o); err != nil {
+			t.Fatalf("CreateOccurrence got %v want success", err)
+		}
+		oPID, oID, err := name.ParseOccurrence(o.Name)
+		if err != nil {
+			t.Fatalf("Error parsing projectID and occurrenceID %v", err)
+		}
+		update := proto.Clone(o).(*pb.Occurrence)
+		update.Remediation = "1"
+		if err := s.UpdateOccurrence(oPID, oID, update, ""); err != nil {
+			t.Fatalf("UpdateOccurrence got %v want success", err)
+		}
+		upsert := proto.Clone(o).(*pb.Occurrence)
+		upsert.Remediation = "2"
+		if _, err := s.UpsertOccurrence(upsert); err != nil {
+			t.Fatalf("UpsertOccurrence got %v want success", err)
+		}
+		oRevs, pageToken, err := s.ListOccurrenceRevisions(oPID, oID, 10, "")
+		if err != nil || pageToken != "" {
+			t.Fatalf("ListOccurrenceRevisions got page token %q, err %v, want success", pageToken, err)
+		}
+		if len(oRevs) != 2 || oRevs[0].Occurrence.Remediation != "1" || oRevs[1].Occurrence.Remediation != o.Remediation {
+			t.Errorf("ListOccurrenceRevisions got %v, want the upserted and the created revisions", oRevs)
+		}
+
+		// Deleting occurrences and purging notes deletes their revisions.
+		if err := s.DeleteOccurrence(oPID, oID, ""); err != nil {
+			t.Fatalf("DeleteOccurrence got %v want success", err)
+		}
+		if err := s.DeleteNote(nPID, nID, ""); err != nil {
+			t.Fatalf("DeleteNote got %v want success", err)
+		}
+		if _, err := s.PurgeDeleted(time.Now().Add(time.Second)); err != nil {
+			t.Fatalf("PurgeDeleted got %v want success", err)
+		}
+		if err := s.CreateNote(n); err != nil {
+			t.Fatalf("CreateNote got %v want success", err)
+		}
+		if err := s.CreateOccurrence(o); err != nil {
+			t.Fatalf("CreateOccurrence got %v want success", err)
+		}
+		if revs, _, err := s.ListNoteRevisions(nPID, nID, 10, ""); err != nil || len(revs) != 0 {
+			t.Errorf("ListNoteRevisions of a recreated note got %v, %v, want no revisions", revs, err)
+		}
+		if revs, _, err := s.ListOccurrenceRevisions(oPID, oID, 10, ""); err != nil || len(revs) != 0 {
+			t.Errorf("ListOccurrenceRevisions of a recreated occurrence got %v, %v, want no revisions", revs, err)
+		}
+	})
+
 	t.Run("GetProject", func(t *testing.T) {
 		s, cleanUp := createStore(t)
 		defer cleanUp()
@@ -419,7 +856,7 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 			t.Errorf("GetOperation got %v, want %v", got, o)
 		}
 
-		o2 := o
+		o2 := proto.Clone(o).(*opspb.Operation)
 		o2.Done = true
 		if err := s.UpdateOperation(pID, oID, o2); err != nil {
 			t.Fatalf("UpdateOperation got %v want success", err)
@@ -430,6 +867,14 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 		} else if !proto.Equal(got, o2) {
 			t.Errorf("GetOperation got %v, want %v", got, o2)
 		}
+
+		// A done operation is final.
+		if err := s.UpdateOperation(pID, oID, &opspb.Operation{Name: o.Name}); status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("UpdateOperation of a done operation got %v, want %v", err, codes.FailedPrecondition)
+		}
+		if got, err := s.GetOperation(pID, oID); err != nil || !got.Done {
+			t.Errorf("GetOperation after updating a done operation got %v, %v, want the done operation", got, err)
+		}
 	})
 
 	t.Run("ListProjects", func(t *testing.T) {
@@ -727,6 +1172,10 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 		if err := s.CreateNote(n); err != nil {
 			t.Fatalf("CreateNote got %v want success", err)
 		}
+		// Occurrences of other projects take up ids in stores with ids shared by all projects.
+		if err := s.CreateOccurrence(testutil.Occurrence("otherproject", n.Name)); err != nil {
+			t.Errorf("CreateOccurrence got %v want success", err)
+		}
 		op1 := testutil.Occurrence(pID, n.Name)
 		op1.Name = name.FormatOccurrence(pID, oID1)
 		if err := s.CreateOccurrence(op1); err != nil {
@@ -773,6 +1222,12 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 		if p := gotOccurrences[0]; p.Name != name.FormatOccurrence(pID, oID3) {
 			t.Fatalf("Got %s want %s", p.Name, name.FormatOccurrence(pID, oID3))
 		}
+		// The last page has an empty page token even if it is full, and so does an empty one.
+		for _, p := range []string{pID, "emptyproject"} {
+			if _, pageToken, err := s.ListOccurrences(p, filter, 3, ""); err != nil || pageToken != "" {
+				t.Errorf("ListOccurrences(%q) got page token %q, err %v, want an empty page token", p, pageToken, err)
+			}
+		}
 	})
 
 	t.Run("NoteOccurrencePagination", func(t *testing.T) {
@@ -887,3 +1342,15 @@ func doTestStorager(t *testing.T, createStore func(t *testing.T) (server.Storage
 		}
 	})
 }
+
+func eventsEqual(a, b []*auditpb.AuditEvent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -21,8 +21,12 @@ import (
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
+	"github.com/grafeas/grafeas/go/etag"
+	"github.com/grafeas/grafeas/go/filtering/eval"
+	"github.com/grafeas/grafeas/go/v1beta1/summary"
 	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
 	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
 	"github.com/grafeas/grafeas/server-go"
 	"golang.org/x/net/context"
@@ -39,8 +43,35 @@ type Grafeas struct {
 // maxBatch is the maximum data size in the batch API according to the protocol specification
 const maxBatch = 1000
 
+// GetVulnerabilityOccurrencesSummary counts the vulnerability occurrences of a project that match the filter
+// by resource and severity.
 func (g *Grafeas) GetVulnerabilityOccurrencesSummary(ctx context.Context, req *pb.GetVulnerabilityOccurrencesSummaryRequest) (*pb.VulnerabilityOccurrencesSummary, error) {
-	return nil, status.Error(codes.Unimplemented, "GetVulnerabilityOccurrencesSummary is not yet implemented")
+	pID, err := name.ParseProject(req.Parent)
+	if err != nil {
+		log.Printf("Invalid project name: %v", req.Parent)
+		return nil, status.Error(codes.InvalidArgument, "Invalid project name")
+	}
+	f, err := eval.Compile(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return summary.FromPages(func(pageToken string) ([]*pb.Occurrence, string, error) {
+		os, nextToken, err := g.S.ListOccurrences(pID, req.Filter, maxBatch, pageToken)
+		if err != nil {
+			return nil, "", status.Error(codes.Unknown, "Failed to list occurrences")
+		}
+		var matching []*pb.Occurrence
+		for _, o := range os {
+			ok, err := f.Matches(o)
+			if err != nil {
+				return nil, "", err
+			}
+			if ok {
+				matching = append(matching, o)
+			}
+		}
+		return matching, nextToken, nil
+	})
 }
 
 // BatchCreateNotes validates that all notes are valid and then add them to the backing datastore.
@@ -123,7 +154,7 @@ func (g *Grafeas) DeleteOccurrence(ctx context.Context, req *pb.DeleteOccurrence
 		log.Printf("Error parsing name: %v", req.Name)
 		return nil, status.Error(codes.InvalidArgument, "Invalid occurrence name")
 	}
-	return &empty.Empty{}, g.S.DeleteOccurrence(pID, oID)
+	return &empty.Empty{}, g.S.DeleteOccurrence(pID, oID, etag.FromIncomingContext(ctx))
 }
 
 // DeleteNote deletes a note from the datastore.
@@ -134,7 +165,7 @@ func (g *Grafeas) DeleteNote(ctx context.Context, req *pb.DeleteNoteRequest) (*e
 		return nil, status.Error(codes.InvalidArgument, "Invalid note name")
 	}
 	// TODO: Check for occurrences tied to this note, and return an error if there are any before deletion.
-	return &empty.Empty{}, g.S.DeleteNote(pID, nID)
+	return &empty.Empty{}, g.S.DeleteNote(pID, nID, etag.FromIncomingContext(ctx))
 }
 
 // GetProject gets a project from the datastore.
@@ -154,7 +185,15 @@ func (g *Grafeas) GetNote(ctx context.Context, req *pb.GetNoteRequest) (*pb.Note
 		log.Printf("Error parsing name: %v", req.Name)
 		return nil, status.Error(codes.InvalidArgument, "Invalid Note name")
 	}
-	return g.S.GetNote(pID, nID)
+	if req.ReadTime != nil {
+		return nil, status.Error(codes.Unimplemented, "Reading Notes as of a past time is only supported by the validating API")
+	}
+	n, err := g.S.GetNote(pID, nID)
+	if err != nil {
+		return nil, err
+	}
+	etag.SetHeaderFor(ctx, n)
+	return n, nil
 }
 
 // GetOccurrence gets a occurrence from the datastore.
@@ -164,7 +203,12 @@ func (g *Grafeas) GetOccurrence(ctx context.Context, req *pb.GetOccurrenceReques
 		log.Printf("Could note parse name %v", req.Name)
 		return nil, status.Error(codes.InvalidArgument, "Could note parse name")
 	}
-	return g.S.GetOccurrence(pID, oID)
+	o, err := g.S.GetOccurrence(pID, oID)
+	if err != nil {
+		return nil, err
+	}
+	etag.SetHeaderFor(ctx, o)
+	return o, nil
 }
 
 // GetOccurrenceNote gets a the note for the provided occurrence from the datastore.
@@ -203,12 +247,12 @@ func (g *Grafeas) UpdateNote(ctx context.Context, req *pb.UpdateNoteRequest) (*p
 		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Cannot change note name: %v", req.Note.Name))
 	}
 
-	// update note
-	if gErr = g.S.UpdateNote(pID, nID, req.Note); err != nil {
+	// update note, failing if it was modified since the caller read it
+	if gErr = g.S.UpdateNote(pID, nID, req.Note, etag.FromIncomingContext(ctx)); gErr != nil {
 		log.Printf("Cannot update note : %v", gErr)
-		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Cannot change note name: %v", req.Note.Name))
+		return nil, gErr
 	}
-	return g.S.GetNote(pID, nID)
+	return g.GetNote(ctx, &pb.GetNoteRequest{Name: req.Name})
 }
 
 func (g *Grafeas) UpdateOccurrence(ctx context.Context, req *pb.UpdateOccurrenceRequest) (*pb.Occurrence, error) {
@@ -239,12 +283,15 @@ func (g *Grafeas) UpdateOccurrence(ctx context.Context, req *pb.UpdateOccurrence
 		}
 	}
 
-	// update Occurrence
-	if gErr = g.S.UpdateOccurrence(pID, oID, req.Occurrence); gErr != nil {
+	// update Occurrence, failing if it was modified since the caller read it
+	if gErr = g.S.UpdateOccurrence(pID, oID, req.Occurrence, etag.FromIncomingContext(ctx)); gErr != nil {
 		log.Printf("Cannot update occurrence : %v", req.Occurrence.Name)
+		if status.Code(gErr) == codes.Aborted {
+			return nil, gErr
+		}
 		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Cannot update Occurrences: %v", err))
 	}
-	return g.S.GetOccurrence(pID, oID)
+	return g.GetOccurrence(ctx, &pb.GetOccurrenceRequest{Name: req.Name})
 }
 
 // ListProjects returns the project id for all projects in the backing datastore.
@@ -303,6 +350,29 @@ func (g *Grafeas) ListOccurrences(ctx context.Context, req *pb.ListOccurrencesRe
 	}, nil
 }
 
+// WatchOccurrences streams changes to the occurrences of a project that match the filter.
+func (g *Grafeas) WatchOccurrences(req *watchpb.WatchOccurrencesRequest, stream watchpb.GrafeasWatchV1Beta1_WatchOccurrencesServer) error {
+	pID, err := name.ParseProject(req.Parent)
+	if err != nil {
+		log.Printf("Invalid project name: %v", req.Parent)
+		return status.Error(codes.InvalidArgument, "Invalid project name")
+	}
+	if _, err := g.S.GetProject(pID); err != nil {
+		log.Printf("Unable to get project %v, err: %v", pID, err)
+		return status.Errorf(codes.NotFound, "project %v not found", pID)
+	}
+	f, err := eval.Compile(req.Filter)
+	if err != nil {
+		return err
+	}
+	return g.S.WatchOccurrences(stream.Context(), pID, req.Cursor, func(e *watchpb.OccurrenceEvent) error {
+		if ok, err := f.Matches(e.Occurrence); err != nil || !ok {
+			return err
+		}
+		return stream.Send(e)
+	})
+}
+
 func (g *Grafeas) ListNoteOccurrences(ctx context.Context, req *pb.ListNoteOccurrencesRequest) (*pb.ListNoteOccurrencesResponse, error) {
 	pID, nID, err := name.ParseNote(req.Name)
 	if err != nil {
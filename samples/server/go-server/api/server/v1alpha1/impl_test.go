@@ -15,6 +15,7 @@
 package v1alpha1
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -24,9 +25,12 @@ import (
 
 	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
 	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	vpb "github.com/grafeas/grafeas/proto/v1beta1/vulnerability_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
 	"github.com/grafeas/grafeas/samples/server/go-server/api/server/testing"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -95,6 +99,65 @@ func TestCreateOccurrence(t *testing.T) {
 	}
 }
 
+// fakeWatchStream collects the events sent by WatchOccurrences, ending the watch after want events.
+type fakeWatchStream struct {
+	grpc.ServerStream
+	events []*watchpb.OccurrenceEvent
+	want   int
+}
+
+var errWatchDone = errors.New("done")
+
+func (s *fakeWatchStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeWatchStream) Send(e *watchpb.OccurrenceEvent) error {
+	s.events = append(s.events, e)
+	if len(s.events) == s.want {
+		return errWatchDone
+	}
+	return nil
+}
+
+func TestWatchOccurrences(t *testing.T) {
+	ctx := context.Background()
+	g := Grafeas{storage.NewMemStore()}
+	n := testutil.Note("vulnerability-scanner-a")
+	createProject(t, "vulnerability-scanner-a", ctx, g)
+	if _, err := g.CreateNote(ctx, &pb.CreateNoteRequest{Parent: "projects/vulnerability-scanner-a", Note: n}); err != nil {
+		t.Fatalf("CreateNote got %v, want success", err)
+	}
+	pID := "occurrence-project"
+	parent := name.FormatProject(pID)
+	createProject(t, pID, ctx, g)
+	other := testutil.Occurrence(pID, n.Name)
+	other.Resource.Uri = "gcr.io/foo/other"
+	for _, o := range []*pb.Occurrence{other, testutil.Occurrence(pID, n.Name)} {
+		if _, err := g.CreateOccurrence(ctx, &pb.CreateOccurrenceRequest{Parent: parent, Occurrence: o}); err != nil {
+			t.Fatalf("CreateOccurrence got %v, want success", err)
+		}
+	}
+
+	stream := &fakeWatchStream{want: 1}
+	req := &watchpb.WatchOccurrencesRequest{Parent: parent, Filter: `resource.uri = "gcr.io/foo/bar"`, Cursor: "0"}
+	if err := g.WatchOccurrences(req, stream); err != errWatchDone {
+		t.Fatalf("WatchOccurrences got %v, want %v", err, errWatchDone)
+	}
+	if got := stream.events[0].Occurrence.Resource.Uri; got != "gcr.io/foo/bar" {
+		t.Errorf("WatchOccurrences got event for %q, want only %q", got, "gcr.io/foo/bar")
+	}
+
+	req.Filter = "kind ="
+	if err := g.WatchOccurrences(req, &fakeWatchStream{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("WatchOccurrences with invalid filter got %v, want %v", err, codes.InvalidArgument)
+	}
+	req = &watchpb.WatchOccurrencesRequest{Parent: "projects/not-there"}
+	if err := g.WatchOccurrences(req, &fakeWatchStream{}); status.Code(err) != codes.NotFound {
+		t.Errorf("WatchOccurrences of missing project got %v, want %v", err, codes.NotFound)
+	}
+}
+
 func TestBatchCreateOccurrences(t *testing.T) {
 	ctx := context.Background()
 	g := Grafeas{storage.NewMemStore()}
@@ -540,6 +603,63 @@ func TestListOccurrences(t *testing.T) {
 	}
 }
 
+func TestGetVulnerabilityOccurrencesSummary(t *testing.T) {
+	ctx := context.Background()
+	g := Grafeas{storage.NewMemStore()}
+	pID := "vulnerability-scanner-a"
+	createProject(t, pID, ctx, g)
+	n := testutil.Note(pID)
+	if _, err := g.CreateNote(ctx, &pb.CreateNoteRequest{Parent: name.FormatProject(pID), Note: n}); err != nil {
+		t.Fatalf("CreateNote(%v) got %v, want success", n, err)
+	}
+	for _, uri := range []string{"gcr.io/foo/bar", "gcr.io/foo/bar", "gcr.io/foo/baz"} {
+		o := testutil.Occurrence(pID, n.Name)
+		o.Resource.Uri = uri
+		if _, err := g.CreateOccurrence(ctx, &pb.CreateOccurrenceRequest{Parent: name.FormatProject(pID), Occurrence: o}); err != nil {
+			t.Fatalf("CreateOccurrence got %v want success", err)
+		}
+	}
+
+	tests := []struct {
+		filter string
+		// Total counts by resource URI.
+		want map[string]int64
+	}{
+		{
+			filter: "",
+			want:   map[string]int64{"gcr.io/foo/bar": 2, "gcr.io/foo/baz": 1},
+		},
+		{
+			filter: `resource.uri = "gcr.io/foo/baz"`,
+			want:   map[string]int64{"gcr.io/foo/baz": 1},
+		},
+	}
+	for _, tt := range tests {
+		req := &pb.GetVulnerabilityOccurrencesSummaryRequest{Parent: name.FormatProject(pID), Filter: tt.filter}
+		s, err := g.GetVulnerabilityOccurrencesSummary(ctx, req)
+		if err != nil {
+			t.Fatalf("GetVulnerabilityOccurrencesSummary(%v) got %v, want success", req, err)
+		}
+		got := map[string]int64{}
+		for _, c := range s.Counts {
+			// Every occurrence has a fix, and is counted towards its severity and the total.
+			if c.FixableCount != c.TotalCount {
+				t.Errorf("GetVulnerabilityOccurrencesSummary(%v) got count %v, want all fixable", req, c)
+			}
+			if c.Severity == vpb.Severity_SEVERITY_UNSPECIFIED {
+				got[c.Resource.Uri] = c.TotalCount
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetVulnerabilityOccurrencesSummary(%v) got totals %v, want %v", req, got, tt.want)
+		}
+	}
+
+	if _, err := g.GetVulnerabilityOccurrencesSummary(ctx, &pb.GetVulnerabilityOccurrencesSummaryRequest{Parent: "projects"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetVulnerabilityOccurrencesSummary with an invalid parent got %v, want InvalidArgument", err)
+	}
+}
+
 func TestListProjects(t *testing.T) {
 	ctx := context.Background()
 	g := Grafeas{storage.NewMemStore()}
@@ -0,0 +1,129 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"time"
+
+	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/name"
+	server "github.com/grafeas/grafeas/server-go"
+)
+
+// storager notifies d of the changes made to notes and occurrences through the wrapped Storager.
+type storager struct {
+	server.Storager
+	d *Dispatcher
+}
+
+// Wrap returns a Storager that stores in s and notifies d of every note and occurrence that is
+// successfully created, updated or deleted through it.
+func Wrap(s server.Storager, d *Dispatcher) server.Storager {
+	return &storager{Storager: s, d: d}
+}
+
+// CreateNote adds the specified note
+func (s *storager) CreateNote(n *pb.Note) error {
+	if err := s.Storager.CreateNote(n); err != nil {
+		return err
+	}
+	if pID, _, err := name.ParseNote(n.Name); err == nil {
+		s.d.NotifyNote(NoteCreated, pID, n)
+	}
+	return nil
+}
+
+// CreateOccurrence adds the specified occurrence
+func (s *storager) CreateOccurrence(o *pb.Occurrence) error {
+	if err := s.Storager.CreateOccurrence(o); err != nil {
+		return err
+	}
+	if pID, _, err := name.ParseOccurrence(o.Name); err == nil {
+		s.d.NotifyOccurrence(OccurrenceCreated, pID, o)
+	}
+	return nil
+}
+
+// UpsertOccurrence updates or adds the specified occurrence
+func (s *storager) UpsertOccurrence(o *pb.Occurrence) (*pb.Occurrence, error) {
+	newName := o.Name
+	got, err := s.Storager.UpsertOccurrence(o)
+	if err != nil {
+		return nil, err
+	}
+	t := OccurrenceUpdated
+	if got.Name == newName {
+		t = OccurrenceCreated
+	}
+	if pID, _, err := name.ParseOccurrence(got.Name); err == nil {
+		s.d.NotifyOccurrence(t, pID, got)
+	}
+	return got, nil
+}
+
+// DeleteNote deletes the note with the given pID and nID if it matches etag
+func (s *storager) DeleteNote(pID, nID, etag string) error {
+	n, err := s.Storager.GetNote(pID, nID)
+	if err != nil {
+		return err
+	}
+	if err := s.Storager.DeleteNote(pID, nID, etag); err != nil {
+		return err
+	}
+	s.d.NotifyNote(NoteDeleted, pID, n)
+	return nil
+}
+
+// DeleteOccurrence deletes the occurrence with the given pID and oID if it matches etag
+func (s *storager) DeleteOccurrence(pID, oID, etag string) error {
+	o, err := s.Storager.GetOccurrence(pID, oID)
+	if err != nil {
+		return err
+	}
+	if err := s.Storager.DeleteOccurrence(pID, oID, etag); err != nil {
+		return err
+	}
+	s.d.NotifyOccurrence(OccurrenceDeleted, pID, o)
+	return nil
+}
+
+// UndeleteNote restores the note with the given pID and nID, which is then notified as created
+func (s *storager) UndeleteNote(pID, nID string, deletedAfter time.Time) error {
+	if err := s.Storager.UndeleteNote(pID, nID, deletedAfter); err != nil {
+		return err
+	}
+	if n, err := s.Storager.GetNote(pID, nID); err == nil {
+		s.d.NotifyNote(NoteCreated, pID, n)
+	}
+	return nil
+}
+
+// UpdateNote updates the existing note with the given pID and nID if it matches etag
+func (s *storager) UpdateNote(pID, nID string, n *pb.Note, etag string) error {
+	if err := s.Storager.UpdateNote(pID, nID, n, etag); err != nil {
+		return err
+	}
+	s.d.NotifyNote(NoteUpdated, pID, n)
+	return nil
+}
+
+// UpdateOccurrence updates the existing occurrence with the given pID and oID if it matches etag
+func (s *storager) UpdateOccurrence(pID, oID string, o *pb.Occurrence, etag string) error {
+	if err := s.Storager.UpdateOccurrence(pID, oID, o, etag); err != nil {
+		return err
+	}
+	s.d.NotifyOccurrence(OccurrenceUpdated, pID, o)
+	return nil
+}
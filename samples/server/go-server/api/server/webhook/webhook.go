@@ -0,0 +1,395 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook POSTs signed JSON notifications of note and occurrence changes to HTTP
+// endpoints.
+//
+// Every request carries the headers
+//
+//	X-Grafeas-Event:     the event type, e.g. "occurrence.created"
+//	X-Grafeas-Delivery:  the event ID, which is the same across retries
+//	X-Grafeas-Timestamp: the time the request was sent, in seconds since the Unix epoch
+//	X-Grafeas-Signature: "sha256=" followed by the hex encoded HMAC-SHA256 of the timestamp, a
+//	                     dot and the body, keyed with the endpoint's secret
+//
+// Deliveries that fail with a network error, a 429 or a 5xx response are retried with exponential
+// backoff. Deliveries that can't be made are written to the dead-letter log.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/grafeas/grafeas/go/filtering/eval"
+	cpb "github.com/grafeas/grafeas/proto/v1beta1/common_go_proto"
+	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
+)
+
+// Event types.
+const (
+	NoteCreated       = "note.created"
+	NoteUpdated       = "note.updated"
+	NoteDeleted       = "note.deleted"
+	OccurrenceCreated = "occurrence.created"
+	OccurrenceUpdated = "occurrence.updated"
+	OccurrenceDeleted = "occurrence.deleted"
+)
+
+// Default delivery settings, used for the settings left unset in Config.
+const (
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = time.Minute
+	defaultTimeout        = 10 * time.Second
+	defaultQueueSize      = 1000
+)
+
+// workers is the number of deliveries made concurrently.
+const workers = 4
+
+// Config is the webhooks configuration.
+type Config struct {
+	Endpoints      []*EndpointConfig `yaml:"endpoints"`
+	MaxAttempts    int               `yaml:"max_attempts"`     // Attempts per delivery, including the first one
+	InitialBackoff time.Duration     `yaml:"initial_backoff"`  // Delay before the first retry, doubled for every further retry
+	MaxBackoff     time.Duration     `yaml:"max_backoff"`      // Upper bound of the delay between retries
+	Timeout        time.Duration     `yaml:"timeout"`          // Timeout of each request
+	QueueSize      int               `yaml:"queue_size"`       // Deliveries waiting to be made before new ones are dead-lettered
+	DeadLetterPath string            `yaml:"dead_letter_path"` // File failed deliveries are appended to, the server log if empty
+}
+
+// EndpointConfig is the configuration of an endpoint notifications are sent to.
+type EndpointConfig struct {
+	URL      string   `yaml:"url"`
+	Secret   string   `yaml:"secret"`   // Key the payloads are signed with
+	Projects []string `yaml:"projects"` // Project IDs to send notifications for, all if empty
+	Kinds    []string `yaml:"kinds"`    // Note kinds to send notifications for, e.g. VULNERABILITY, all if empty
+	Filter   string   `yaml:"filter"`   // Filter the changed note or occurrence has to match
+}
+
+// Event is the JSON payload of a notification.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Project    string          `json:"project"`
+	Time       time.Time       `json:"time"`
+	Note       json.RawMessage `json:"note,omitempty"`
+	Occurrence json.RawMessage `json:"occurrence,omitempty"`
+}
+
+// deadLetter is an entry of the dead-letter log.
+type deadLetter struct {
+	Time     time.Time `json:"time"`
+	URL      string    `json:"url"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	Event    *Event    `json:"event"`
+}
+
+type endpoint struct {
+	url      string
+	secret   []byte
+	projects map[string]bool
+	kinds    map[cpb.NoteKind]bool
+	filter   *eval.Filter
+}
+
+type delivery struct {
+	endpoint *endpoint
+	event    *Event
+	body     []byte
+}
+
+// Dispatcher sends notifications to the configured endpoints in the background.
+type Dispatcher struct {
+	endpoints      []*endpoint
+	client         *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	deadLetterMu sync.Mutex
+	deadLetters  io.Writer
+	closeLog     func() error
+
+	// mu guards closed, so that nothing is queued once Close has started.
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *delivery
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher validates config and starts delivering the notifications passed to it.
+func NewDispatcher(config *Config) (*Dispatcher, error) {
+	d := &Dispatcher{
+		client:         &http.Client{Timeout: config.Timeout},
+		maxAttempts:    config.MaxAttempts,
+		initialBackoff: config.InitialBackoff,
+		maxBackoff:     config.MaxBackoff,
+	}
+	if d.client.Timeout <= 0 {
+		d.client.Timeout = defaultTimeout
+	}
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = defaultMaxAttempts
+	}
+	if d.initialBackoff <= 0 {
+		d.initialBackoff = defaultInitialBackoff
+	}
+	if d.maxBackoff <= 0 {
+		d.maxBackoff = defaultMaxBackoff
+	}
+	queueSize := config.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	for i, c := range config.Endpoints {
+		e, err := newEndpoint(c)
+		if err != nil {
+			return nil, fmt.Errorf("webhook endpoint %d: %v", i, err)
+		}
+		d.endpoints = append(d.endpoints, e)
+	}
+	if config.DeadLetterPath != "" {
+		f, err := os.OpenFile(config.DeadLetterPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open webhook dead-letter log: %v", err)
+		}
+		d.deadLetters, d.closeLog = f, f.Close
+	}
+
+	d.queue = make(chan *delivery, queueSize)
+	for i := 0; i < workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for dl := range d.queue {
+				d.deliver(dl)
+			}
+		}()
+	}
+	return d, nil
+}
+
+func newEndpoint(c *EndpointConfig) (*endpoint, error) {
+	if c.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	if c.Secret == "" {
+		return nil, fmt.Errorf("secret is required")
+	}
+	f, err := eval.Compile(c.Filter)
+	if err != nil {
+		return nil, err
+	}
+	e := &endpoint{url: c.URL, secret: []byte(c.Secret), filter: f}
+	if len(c.Projects) > 0 {
+		e.projects = map[string]bool{}
+		for _, p := range c.Projects {
+			e.projects[p] = true
+		}
+	}
+	if len(c.Kinds) > 0 {
+		e.kinds = map[cpb.NoteKind]bool{}
+		for _, k := range c.Kinds {
+			v, ok := cpb.NoteKind_value[k]
+			if !ok {
+				return nil, fmt.Errorf("unknown note kind %q", k)
+			}
+			e.kinds[cpb.NoteKind(v)] = true
+		}
+	}
+	return e, nil
+}
+
+// Close stops the dispatcher once the queued deliveries, including their retries, are done.
+// Notifications passed to it afterwards are dead-lettered.
+func (d *Dispatcher) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	close(d.queue)
+	d.mu.Unlock()
+
+	d.wg.Wait()
+	if d.closeLog != nil {
+		return d.closeLog()
+	}
+	return nil
+}
+
+// NotifyNote queues notifications of the change t to note n in project pID.
+func (d *Dispatcher) NotifyNote(t, pID string, n *pb.Note) {
+	d.notify(t, pID, n.GetKind(), n, func(e *Event, raw json.RawMessage) { e.Note = raw })
+}
+
+// NotifyOccurrence queues notifications of the change t to occurrence o in project pID.
+func (d *Dispatcher) NotifyOccurrence(t, pID string, o *pb.Occurrence) {
+	d.notify(t, pID, o.GetKind(), o, func(e *Event, raw json.RawMessage) { e.Occurrence = raw })
+}
+
+func (d *Dispatcher) notify(t, pID string, kind cpb.NoteKind, m proto.Message, set func(*Event, json.RawMessage)) {
+	var event *Event
+	for _, e := range d.endpoints {
+		if !e.matches(pID, kind, m) {
+			continue
+		}
+		if event == nil {
+			s, err := (&jsonpb.Marshaler{OrigName: true}).MarshalToString(m)
+			if err != nil {
+				log.Printf("failed to marshal webhook event %s: %v", t, err)
+				return
+			}
+			event = &Event{ID: uuid.New().String(), Type: t, Project: pID, Time: time.Now().UTC()}
+			set(event, json.RawMessage(s))
+		}
+		d.enqueue(e, event)
+	}
+}
+
+func (d *Dispatcher) enqueue(e *endpoint, event *Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("failed to marshal webhook event %s: %v", event.Type, err)
+		return
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		d.deadLetter(e, event, 0, fmt.Errorf("dispatcher is closed"))
+		return
+	}
+	select {
+	case d.queue <- &delivery{endpoint: e, event: event, body: body}:
+	default:
+		d.deadLetter(e, event, 0, fmt.Errorf("delivery queue is full"))
+	}
+}
+
+// matches returns whether notifications of changes to m, of the specified kind and in project
+// pID, are sent to e.
+func (e *endpoint) matches(pID string, kind cpb.NoteKind, m proto.Message) bool {
+	if e.projects != nil && !e.projects[pID] {
+		return false
+	}
+	if e.kinds != nil && !e.kinds[kind] {
+		return false
+	}
+	ok, err := e.filter.Matches(m)
+	if err != nil {
+		log.Printf("failed to evaluate webhook filter for %s: %v", e.url, err)
+		return false
+	}
+	return ok
+}
+
+// deliver makes up to maxAttempts attempts to send dl, backing off exponentially in between.
+func (d *Dispatcher) deliver(dl *delivery) {
+	backoff := d.initialBackoff
+	for attempt := 1; ; attempt++ {
+		retry, err := d.send(dl)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == d.maxAttempts {
+			d.deadLetter(dl.endpoint, dl.event, attempt, err)
+			return
+		}
+		// Waiting for between half and all of the backoff spreads out retries to the same endpoint.
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		time.Sleep(wait)
+		if backoff *= 2; backoff > d.maxBackoff {
+			backoff = d.maxBackoff
+		}
+	}
+}
+
+// send makes one attempt to send dl. It returns whether a failed attempt may be retried.
+func (d *Dispatcher) send(dl *delivery) (bool, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, dl.endpoint.url, bytes.NewReader(dl.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Grafeas-Event", dl.event.Type)
+	req.Header.Set("X-Grafeas-Delivery", dl.event.ID)
+	req.Header.Set("X-Grafeas-Timestamp", timestamp)
+	req.Header.Set("X-Grafeas-Signature", Sign(dl.endpoint.secret, timestamp, dl.body))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return true, fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return false, fmt.Errorf("endpoint responded %s", resp.Status)
+}
+
+func (d *Dispatcher) deadLetter(e *endpoint, event *Event, attempts int, err error) {
+	line, mErr := json.Marshal(&deadLetter{
+		Time:     time.Now().UTC(),
+		URL:      e.url,
+		Attempts: attempts,
+		Error:    err.Error(),
+		Event:    event,
+	})
+	if mErr != nil {
+		log.Printf("failed to deliver webhook event %s to %s: %v", event.ID, e.url, err)
+		return
+	}
+	d.deadLetterMu.Lock()
+	defer d.deadLetterMu.Unlock()
+	if d.deadLetters == nil {
+		log.Printf("failed to deliver webhook event: %s", line)
+		return
+	}
+	if _, wErr := d.deadLetters.Write(append(line, '\n')); wErr != nil {
+		log.Printf("failed to write webhook dead-letter log: %v, dropping: %s", wErr, line)
+	}
+}
+
+// Sign returns the X-Grafeas-Signature header value of a request with the specified timestamp
+// header and body, so that receivers can verify it with their copy of the secret.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,257 @@
+// Copyright 2019 The Grafeas Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/storage"
+	"github.com/grafeas/grafeas/samples/server/go-server/api/server/testing"
+)
+
+// receiver is a webhook endpoint that responds with the queued statuses, then 200.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	events   []*Event
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.statuses) > 0 {
+		status := r.statuses[0]
+		r.statuses = r.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+	var e Event
+	json.Unmarshal(body, &e)
+	r.events = append(r.events, &e)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+}
+
+func (r *receiver) eventTypes() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var types []string
+	for _, e := range r.events {
+		types = append(types, e.Type)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func newDispatcher(t *testing.T, c *Config) *Dispatcher {
+	t.Helper()
+	if c.InitialBackoff == 0 {
+		c.InitialBackoff = time.Millisecond
+	}
+	d, err := NewDispatcher(c)
+	if err != nil {
+		t.Fatalf("NewDispatcher(%+v) got error %v, want success", c, err)
+	}
+	return d
+}
+
+func TestDeliverySigned(t *testing.T) {
+	r := &receiver{}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	d := newDispatcher(t, &Config{Endpoints: []*EndpointConfig{{URL: srv.URL, Secret: "s3cret"}}})
+	o := testutil.Occurrence("p1", "projects/p1/notes/n1")
+	d.NotifyOccurrence(OccurrenceCreated, "p1", o)
+	d.Close()
+
+	if len(r.events) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(r.events))
+	}
+	req, e := r.requests[0], r.events[0]
+	if e.Type != OccurrenceCreated || e.Project != "p1" || e.ID == "" || e.Note != nil {
+		t.Errorf("got event %+v, want a created occurrence in p1", e)
+	}
+	if !strings.Contains(string(e.Occurrence), `"resource":{"uri":"gcr.io/foo/bar"}`) {
+		t.Errorf("got occurrence %s, want it to contain the resource", e.Occurrence)
+	}
+	if got := req.Header.Get("X-Grafeas-Event"); got != OccurrenceCreated {
+		t.Errorf("got X-Grafeas-Event %q, want %q", got, OccurrenceCreated)
+	}
+	if got := req.Header.Get("X-Grafeas-Delivery"); got != e.ID {
+		t.Errorf("got X-Grafeas-Delivery %q, want %q", got, e.ID)
+	}
+	want := Sign([]byte("s3cret"), req.Header.Get("X-Grafeas-Timestamp"), r.bodies[0])
+	if got := req.Header.Get("X-Grafeas-Signature"); got != want {
+		t.Errorf("got X-Grafeas-Signature %q, want %q", got, want)
+	}
+}
+
+func TestEndpointFilters(t *testing.T) {
+	tests := []struct {
+		desc     string
+		endpoint *EndpointConfig
+		want     int
+	}{
+		{"all", &EndpointConfig{}, 2},
+		{"project", &EndpointConfig{Projects: []string{"p2"}}, 1},
+		{"kind", &EndpointConfig{Kinds: []string{"BUILD"}}, 0},
+		{"filter", &EndpointConfig{Filter: `resource.uri:"gcr.io/foo"`}, 2},
+		{"filter mismatch", &EndpointConfig{Filter: `resource.uri = "other"`}, 0},
+		{"all restrictions", &EndpointConfig{Projects: []string{"p1"}, Kinds: []string{"VULNERABILITY"}, Filter: "kind = VULNERABILITY"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r := &receiver{}
+			srv := httptest.NewServer(r)
+			defer srv.Close()
+			tt.endpoint.URL, tt.endpoint.Secret = srv.URL, "s3cret"
+			d := newDispatcher(t, &Config{Endpoints: []*EndpointConfig{tt.endpoint}})
+			d.NotifyOccurrence(OccurrenceCreated, "p1", testutil.Occurrence("p1", "projects/p1/notes/n1"))
+			d.NotifyOccurrence(OccurrenceCreated, "p2", testutil.Occurrence("p2", "projects/p1/notes/n1"))
+			d.Close()
+			if got := len(r.events); got != tt.want {
+				t.Errorf("got %d deliveries, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidEndpoints(t *testing.T) {
+	for _, e := range []*EndpointConfig{
+		{Secret: "s3cret"},
+		{URL: "http://localhost"},
+		{URL: "http://localhost", Secret: "s3cret", Kinds: []string{"NOT_A_KIND"}},
+		{URL: "http://localhost", Secret: "s3cret", Filter: "resource.uri = ("},
+	} {
+		if _, err := NewDispatcher(&Config{Endpoints: []*EndpointConfig{e}}); err == nil {
+			t.Errorf("NewDispatcher with endpoint %+v got success, want error", e)
+		}
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		desc           string
+		statuses       []int
+		wantDelivered  bool
+		wantDeadLetter string
+	}{
+		{"retried until success", []int{500, 429, 503}, true, ""},
+		{"retries exhausted", []int{500, 500, 500, 500}, false, "500 Internal Server Error"},
+		{"not retried", []int{400}, false, "400 Bad Request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r := &receiver{statuses: tt.statuses}
+			srv := httptest.NewServer(r)
+			defer srv.Close()
+			dir, err := ioutil.TempDir("", "webhook")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "dead-letters.log")
+			d := newDispatcher(t, &Config{
+				Endpoints:      []*EndpointConfig{{URL: srv.URL, Secret: "s3cret"}},
+				MaxAttempts:    4,
+				DeadLetterPath: path,
+			})
+			d.NotifyNote(NoteCreated, "p1", testutil.Note("p1"))
+			if err := d.Close(); err != nil {
+				t.Fatalf("Close() got error %v, want success", err)
+			}
+
+			if got := len(r.events) == 1; got != tt.wantDelivered {
+				t.Errorf("got delivered %v, want %v", got, tt.wantDelivered)
+			}
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read dead-letter log: %v", err)
+			}
+			if tt.wantDeadLetter == "" {
+				if len(data) != 0 {
+					t.Errorf("got dead letters %s, want none", data)
+				}
+				return
+			}
+			var dl deadLetter
+			if err := json.Unmarshal(data, &dl); err != nil {
+				t.Fatalf("failed to parse dead letter %s: %v", data, err)
+			}
+			if dl.URL != srv.URL || !strings.Contains(dl.Error, tt.wantDeadLetter) || dl.Event.Type != NoteCreated {
+				t.Errorf("got dead letter %s, want a %s delivery to %s failing with %q", data, NoteCreated, srv.URL, tt.wantDeadLetter)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	r := &receiver{}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	d := newDispatcher(t, &Config{Endpoints: []*EndpointConfig{{URL: srv.URL, Secret: "s3cret"}}})
+	s := Wrap(storage.NewMemStore(), d)
+
+	n := testutil.Note("p1")
+	if err := s.CreateNote(n); err != nil {
+		t.Fatalf("CreateNote got error %v, want success", err)
+	}
+	o := testutil.Occurrence("p1", n.Name)
+	if err := s.CreateOccurrence(o); err != nil {
+		t.Fatalf("CreateOccurrence got error %v, want success", err)
+	}
+	// Upserting the same resource and note updates the occurrence.
+	upsert := testutil.Occurrence("p1", n.Name)
+	upsert.Name = "projects/p1/occurrences/new"
+	if got, err := s.UpsertOccurrence(upsert); err != nil || got.Name != o.Name {
+		t.Fatalf("UpsertOccurrence got %v, %v, want %q", got, err, o.Name)
+	}
+	if err := s.UpdateNote("p1", "CVE-1999-0710", n, ""); err != nil {
+		t.Fatalf("UpdateNote got error %v, want success", err)
+	}
+	if err := s.DeleteOccurrence("p1", "134", ""); err != nil {
+		t.Fatalf("DeleteOccurrence got error %v, want success", err)
+	}
+	if err := s.DeleteNote("p1", "CVE-1999-0710", "wrong"); err == nil {
+		t.Fatalf("DeleteNote with the wrong etag got success, want error")
+	}
+	if err := s.DeleteNote("p1", "CVE-1999-0710", ""); err != nil {
+		t.Fatalf("DeleteNote got error %v, want success", err)
+	}
+	// Undeleted notes are created again.
+	if err := s.UndeleteNote("p1", "CVE-1999-0710", time.Time{}); err != nil {
+		t.Fatalf("UndeleteNote got error %v, want success", err)
+	}
+	d.Close()
+
+	got := strings.Join(r.eventTypes(), ",")
+	want := strings.Join([]string{NoteCreated, NoteCreated, NoteDeleted, NoteUpdated, OccurrenceCreated, OccurrenceDeleted, OccurrenceUpdated}, ",")
+	if got != want {
+		t.Errorf("got events %s, want %s", got, want)
+	}
+}
@@ -15,12 +15,26 @@
 package server
 
 import (
+	"context"
+	"time"
+
+	auditpb "github.com/grafeas/grafeas/proto/v1beta1/audit_go_proto"
 	pb "github.com/grafeas/grafeas/proto/v1beta1/grafeas_go_proto"
 	prpb "github.com/grafeas/grafeas/proto/v1beta1/project_go_proto"
+	revpb "github.com/grafeas/grafeas/proto/v1beta1/revision_go_proto"
+	watchpb "github.com/grafeas/grafeas/proto/v1beta1/watch_go_proto"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
 	opspb "google.golang.org/genproto/googleapis/longrunning"
 )
 
 // Storager is the interface that a Grafeas storage implementation would provide
+//
+// Notes and occurrences are versioned by their etag as computed by the etag package. Updates and
+// deletes take the etag the caller expects the entity to have; if it is non-empty and doesn't
+// match the stored entity, they fail with codes.Aborted without modifying anything.
+//
+// List methods return an empty next page token with the last page, so callers can page through
+// them with ListAll.
 type Storager interface {
 	// CreateProject adds the specified project
 	CreateProject(pID string) error
@@ -31,17 +45,37 @@ type Storager interface {
 	// CreateOccurrence adds the specified occurrence
 	CreateOccurrence(o *pb.Occurrence) error
 
+	// UpsertOccurrence updates the occurrence with the same project, resource URI and note name as o
+	// in place, keeping its name and create time and bumping its update time, or adds o if there is
+	// none. It returns the stored occurrence.
+	UpsertOccurrence(o *pb.Occurrence) (*pb.Occurrence, error)
+
 	// CreateOperation adds the specified operation
 	CreateOperation(o *opspb.Operation) error
 
-	// DeleteNote deletes the project with the given pID
+	// DeleteProject deletes the project with the given pID. The project is kept, hidden from Get and
+	// List, until it is undeleted or purged, and no project with the same pID can be created until
+	// it is purged.
 	DeleteProject(pID string) error
 
-	// DeleteNote deletes the note with the given pID and nID
-	DeleteNote(pID, nID string) error
+	// DeleteNote deletes the note with the given pID and nID if it matches etag. Like projects, the
+	// note and its revisions are kept, hidden, until it is undeleted or purged.
+	DeleteNote(pID, nID, etag string) error
+
+	// UndeleteProject restores the project with the given pID if it was deleted at or after
+	// deletedAfter, and fails with codes.NotFound otherwise
+	UndeleteProject(pID string, deletedAfter time.Time) error
 
-	// DeleteOccurrence deletes the occurrence with the given pID and oID
-	DeleteOccurrence(pID, oID string) error
+	// UndeleteNote restores the note with the given pID and nID if it was deleted at or after
+	// deletedAfter, and fails with codes.NotFound otherwise
+	UndeleteNote(pID, nID string, deletedAfter time.Time) error
+
+	// PurgeDeleted permanently removes the projects and notes deleted before the given time, along
+	// with the revisions of the notes, and returns their resource names
+	PurgeDeleted(before time.Time) ([]string, error)
+
+	// DeleteOccurrence deletes the occurrence with the given pID and oID if it matches etag
+	DeleteOccurrence(pID, oID, etag string) error
 
 	// DeleteOperation deletes the operation with the given pID and oID
 	DeleteOperation(pID, opID string) error
@@ -81,12 +115,65 @@ type Storager interface {
 	// at pageToken (or from start if pageToken is the empty string).
 	ListOperations(pID, filters string, pageSize int, pageToken string) ([]*opspb.Operation, string, error)
 
-	// UpdateNote updates the existing note with the given pID and nID
-	UpdateNote(pID, nID string, n *pb.Note) error
+	// UpdateNote updates the existing note with the given pID and nID if it matches etag
+	UpdateNote(pID, nID string, n *pb.Note, etag string) error
 
-	// UpdateOccurrence updates the existing occurrence with the given projectID and occurrenceID
-	UpdateOccurrence(pID, oID string, o *pb.Occurrence) error
+	// UpdateOccurrence updates the existing occurrence with the given projectID and occurrenceID if
+	// it matches etag
+	UpdateOccurrence(pID, oID string, o *pb.Occurrence, etag string) error
 
-	// UpdateOperation updates the existing operation with the given pID and nID
+	// UpdateOperation updates the existing operation with the given pID and nID. An operation that
+	// is done is final: updating it fails with codes.FailedPrecondition without modifying it
 	UpdateOperation(pID, opID string, op *opspb.Operation) error
+
+	// GetIamPolicy returns the IAM policy of the project or note with the given resource name, or a
+	// codes.NotFound error if it has none
+	GetIamPolicy(resource string) (*iampb.Policy, error)
+
+	// SetIamPolicy replaces the IAM policy of the project or note with the given resource name if
+	// its current policy, or an empty policy if it has none, matches etag
+	SetIamPolicy(resource string, p *iampb.Policy, etag string) error
+
+	// DeleteIamPolicy deletes the IAM policy of the project or note with the given resource name
+	DeleteIamPolicy(resource string) error
+
+	// AppendAuditEvent appends the specified event to the audit log of project pID
+	AppendAuditEvent(pID string, e *auditpb.AuditEvent) error
+
+	// ListAuditEvents returns up to pageSize number of the events of the audit log of project pID, in
+	// the order they were appended, beginning at pageToken (or from start if pageToken is the empty
+	// string). Only events about resource are returned if it is non-empty, and only those whose
+	// event time is in [start, end) for the bounds that are non-zero.
+	ListAuditEvents(pID, resource string, start, end time.Time, pageSize int, pageToken string) ([]*auditpb.AuditEvent, string, error)
+
+	// ListNoteRevisions returns up to pageSize number of the revisions the note with pID and nID had
+	// before each of its updates, newest first, beginning at pageToken (or from start if pageToken
+	// is the empty string). Updating a note records its previous revision and purging it deletes
+	// its revisions.
+	ListNoteRevisions(pID, nID string, pageSize int, pageToken string) ([]*revpb.NoteRevision, string, error)
+
+	// ListOccurrenceRevisions returns up to pageSize number of the revisions the occurrence with pID
+	// and oID had before each of its updates, including those by UpsertOccurrence, newest first,
+	// beginning at pageToken (or from start if pageToken is the empty string). Deleting an
+	// occurrence deletes its revisions.
+	ListOccurrenceRevisions(pID, oID string, pageSize int, pageToken string) ([]*revpb.OccurrenceRevision, string, error)
+
+	// WatchOccurrences calls fn, in order, with every change to the occurrences of project pID made
+	// after the change identified by cursor, or after the call if cursor is empty. It blocks until
+	// ctx is done or fn returns an error, and fails with codes.OutOfRange if the changes after
+	// cursor are no longer retained.
+	WatchOccurrences(ctx context.Context, pID, cursor string, fn func(*watchpb.OccurrenceEvent) error) error
+}
+
+// ListAll calls list with the page token of every page of one of the list methods of a Storager in
+// turn, starting from the first page, until list returns an empty next page token or an error.
+func ListAll(list func(pageToken string) (string, error)) error {
+	pageToken := ""
+	for {
+		npt, err := list(pageToken)
+		if err != nil || npt == "" {
+			return err
+		}
+		pageToken = npt
+	}
 }